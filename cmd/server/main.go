@@ -13,22 +13,35 @@ import (
 	"github.com/fuomag9/uptime-kabomba/internal/api"
 	"github.com/fuomag9/uptime-kabomba/internal/config"
 	"github.com/fuomag9/uptime-kabomba/internal/database"
+	"github.com/fuomag9/uptime-kabomba/internal/demo"
 	"github.com/fuomag9/uptime-kabomba/internal/jobs"
 	"github.com/fuomag9/uptime-kabomba/internal/monitor"
 	"github.com/fuomag9/uptime-kabomba/internal/notification"
 	"github.com/fuomag9/uptime-kabomba/internal/oauth"
+	"github.com/fuomag9/uptime-kabomba/internal/version"
 	"github.com/fuomag9/uptime-kabomba/internal/websocket"
 )
 
 func main() {
 	// Load configuration
 	cfg := config.Load()
+	log.Printf("Uptime Kabomba %s", version.Version)
 
 	// Initialize monitor configuration
 	monitor.SetConfig(&monitor.MonitorConfig{
 		AllowPrivateIPs:        cfg.AllowPrivateIPs,
 		AllowMetadataEndpoints: cfg.AllowMetadataEndpoints,
+		SourceIP:               cfg.SourceIP,
+		AllowedCIDRs:           cfg.SSRFAllowedCIDRs,
+		AllowedHosts:           cfg.SSRFAllowedHosts,
+		MinInterval:            cfg.MinMonitorInterval,
 	})
+	if cfg.SourceIP != "" {
+		if _, err := monitor.ParseSourceIP(cfg.SourceIP); err != nil {
+			log.Fatalf("Invalid SOURCE_IP: %v", err)
+		}
+		log.Printf("Monitor checks egress from %s", cfg.SourceIP)
+	}
 
 	// Initialize database
 	db, err := database.Connect(cfg.Database)
@@ -48,12 +61,63 @@ func main() {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
 
+	// Partition heartbeats by month (opt-in) before checks write to them
+	if cfg.HeartbeatPartitioning {
+		converted, err := jobs.NewHeartbeatPartitionManager(db).PartitionTable()
+		if err != nil {
+			log.Fatalf("Failed to partition heartbeats: %v", err)
+		}
+		if converted {
+			log.Println("Converted the heartbeats table to monthly partitions")
+		}
+	}
+
+	// Seed or remove demo data before monitors start
+	switch cfg.DemoMode {
+	case "true":
+		password := cfg.DemoPassword
+		if password == "" {
+			password = demo.RandomPassword()
+		}
+		seeded, err := demo.NewSeeder(db).Seed(password)
+		if err != nil {
+			log.Fatalf("Failed to seed demo data: %v", err)
+		}
+		if seeded {
+			if cfg.DemoPassword == "" {
+				log.Printf("Demo data seeded: log in as %q with password %q", demo.Username, password)
+			} else {
+				log.Printf("Demo data seeded: log in as %q with DEMO_PASSWORD", demo.Username)
+			}
+		} else {
+			log.Println("DEMO_MODE is set but the database already has data; not seeding")
+		}
+	case "wipe":
+		wiped, err := demo.Wipe(db)
+		if err != nil {
+			log.Fatalf("Failed to remove demo data: %v", err)
+		}
+		if wiped {
+			log.Println("Demo data removed")
+		}
+	}
+
 	// Initialize WebSocket hub with allowed origins for security
-	hub := websocket.NewHub(cfg.JWTSecret, cfg.CORSOrigins, db)
+	hub := websocket.NewHub(cfg.JWTKeys, cfg.CORSOrigins, db, cfg.WSBroadcastBuffer)
 	go hub.Run()
 
 	// Initialize notification dispatcher
+	notification.SetSendTimeout(time.Duration(cfg.NotificationTimeout) * time.Second)
 	dispatcher := notification.NewDispatcher(db)
+	dispatcher.SetSendConcurrency(cfg.NotificationConcurrency)
+	if cfg.MassOutageThreshold > 0 {
+		dispatcher.EnableOutageCoalescing(cfg.MassOutageThreshold, time.Duration(cfg.MassOutageWindow)*time.Second)
+		log.Printf("Mass outage coalescing enabled: %d monitors within %ds", cfg.MassOutageThreshold, cfg.MassOutageWindow)
+	}
+	if cfg.NotificationSettleWindow > 0 {
+		dispatcher.EnableSettling(time.Duration(cfg.NotificationSettleWindow) * time.Second)
+		log.Printf("Notification settling enabled: %ds", cfg.NotificationSettleWindow)
+	}
 
 	// Register HTTP monitor with mTLS cert loader
 	monitor.RegisterMonitorType(monitor.NewHTTPMonitor(monitor.NewDBCertLoader(db)))
@@ -77,15 +141,21 @@ func main() {
 		log.Println("Chrome is disabled. page_change monitor type will be unavailable")
 	}
 
+	// Register script monitor (opt-in: it runs commands on the server)
+	if cfg.ScriptMonitorEnabled {
+		monitor.RegisterMonitorType(monitor.NewScriptMonitor())
+		log.Println("WARNING: script monitor type enabled; admins can run commands on this server")
+	}
+
 	// Initialize monitor executor
-	executor := monitor.NewExecutor(db, hub, dispatcher)
+	executor := monitor.NewExecutor(db, hub, dispatcher, cfg.MaxConcurrentChecks)
 	if err := executor.Start(); err != nil {
 		log.Fatalf("Failed to start monitor executor: %v", err)
 	}
 	defer executor.Stop()
 
 	// Initialize job scheduler
-	scheduler := jobs.NewScheduler(db, cfg.ScreenshotStoragePath)
+	scheduler := jobs.NewScheduler(db, cfg.ScreenshotStoragePath, dispatcher, cfg.StalledMonitorMultiplier, cfg.APIKeyExpiryWarningDays, cfg.DBSizeAlertMB, cfg.DBGrowthAlertMB, cfg.HeartbeatPartitioning)
 	scheduler.Start()
 
 	// Start OAuth cleanup job if OAuth is enabled
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+)
+
+// agentScope is the API key scope remote agents report check results with
+const agentScope = "agent"
+
+// maxAgentMessageLength caps the message an agent reports
+const maxAgentMessageLength = 1024
+
+// agentHeartbeatRequest is a check result reported by a remote agent
+type agentHeartbeatRequest struct {
+	MonitorID int    `json:"monitor_id"`
+	Status    int    `json:"status"` // 0=down, 1=up
+	Message   string `json:"message"`
+}
+
+// validate checks an agent heartbeat and truncates its message
+func (req *agentHeartbeatRequest) validate() error {
+	if req.MonitorID <= 0 {
+		return fmt.Errorf("monitor_id is required")
+	}
+	if req.Status != monitor.StatusDown && req.Status != monitor.StatusUp {
+		return fmt.Errorf("status must be 0 (down) or 1 (up)")
+	}
+	if runes := []rune(req.Message); len(runes) > maxAgentMessageLength {
+		req.Message = string(runes[:maxAgentMessageLength])
+	}
+	return nil
+}
+
+// agentSourceName names an agent among a monitor's check sources, after the
+// API key it reports with
+func agentSourceName(key *models.APIKey) string {
+	return "agent:" + key.Name
+}
+
+// HandleAgentHeartbeat records a remote agent's check result for one of the
+// key owner's monitors. It counts toward the monitor's quorum on the next
+// local check. Requires an API key with the agent scope.
+func HandleAgentHeartbeat(db *gorm.DB, executor *monitor.Executor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		key := r.Context().Value(apiKeyContextKey).(*models.APIKey)
+
+		if !key.HasScope(agentScope) {
+			http.Error(w, "API key lacks the agent scope", http.StatusForbidden)
+			return
+		}
+
+		var req agentHeartbeatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := req.validate(); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Verify ownership
+		var count int64
+		if err := db.Model(&models.Monitor{}).
+			Where("id = ? AND user_id = ?", req.MonitorID, user.ID).
+			Count(&count).Error; err != nil {
+			http.Error(w, "Failed to fetch monitor", http.StatusInternalServerError)
+			return
+		}
+		if count == 0 {
+			http.Error(w, "Monitor not found", http.StatusNotFound)
+			return
+		}
+
+		if !executor.RecordAgentResult(req.MonitorID, agentSourceName(key), req.Status, req.Message, time.Now()) {
+			http.Error(w, "Monitor is not active", http.StatusConflict)
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
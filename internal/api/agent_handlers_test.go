@@ -0,0 +1,26 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAgentHeartbeatRequestValidate(t *testing.T) {
+	req := agentHeartbeatRequest{MonitorID: 3, Status: 0, Message: strings.Repeat("é", maxAgentMessageLength+10)}
+	if err := req.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if n := len([]rune(req.Message)); n != maxAgentMessageLength {
+		t.Errorf("message has %d runes, want it cut to %d", n, maxAgentMessageLength)
+	}
+
+	for _, invalid := range []agentHeartbeatRequest{
+		{Status: 1},
+		{MonitorID: 3, Status: 2},
+		{MonitorID: 3, Status: 3},
+	} {
+		if err := invalid.validate(); err == nil {
+			t.Errorf("validate(%+v) succeeded, want error", invalid)
+		}
+	}
+}
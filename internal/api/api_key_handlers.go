@@ -32,6 +32,11 @@ func HandleGetAPIKeys(db *gorm.DB) http.HandlerFunc {
 
 		// AfterFind hook automatically unmarshals Scopes JSON
 
+		now := time.Now()
+		for i := range apiKeys {
+			apiKeys[i].DaysUntilExpiry = apiKeys[i].DaysLeft(now)
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(apiKeys)
 	}
@@ -64,7 +69,7 @@ func HandleCreateAPIKey(db *gorm.DB) http.HandlerFunc {
 		}
 
 		// Validate scopes
-		validScopes := map[string]bool{"read": true, "write": true, "admin": true}
+		validScopes := map[string]bool{"read": true, "write": true, "admin": true, agentScope: true, metricsScope: true}
 		for _, scope := range req.Scopes {
 			if !validScopes[scope] {
 				http.Error(w, "Invalid scope: "+scope, http.StatusBadRequest)
@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -14,6 +16,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/fuomag9/uptime-kabomba/internal/config"
+	"github.com/fuomag9/uptime-kabomba/internal/jwtkeys"
 	"github.com/fuomag9/uptime-kabomba/internal/models"
 )
 
@@ -36,6 +39,10 @@ type LoginResponse struct {
 
 // HandleLogin handles user login
 func HandleLogin(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
+	lockout := NewLoginLockout(db, cfg.LoginLockoutThreshold,
+		time.Duration(cfg.LoginLockoutWindow)*time.Second,
+		time.Duration(cfg.LoginLockoutDuration)*time.Second)
+
 	return func(w http.ResponseWriter, r *http.Request) {
 		var req LoginRequest
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -50,8 +57,23 @@ func HandleLogin(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
 		var user models.User
 		// Find user by username or email
 		err := db.Where("username = ? OR email = ?", req.Username, req.Username).First(&user).Error
+
+		// Unknown usernames are tracked and locked the same way as real accounts
+		var lockoutKey string
+		if err != nil {
+			lockoutKey = loginLockoutKey(nil, req.Username)
+		} else {
+			lockoutKey = loginLockoutKey(&user, req.Username)
+		}
+		if retryAfter, locked := lockout.Locked(lockoutKey); locked {
+			log.Println("Login: Rejected - account temporarily locked")
+			writeLoginLocked(w, retryAfter)
+			return
+		}
+
 		if err != nil {
 			log.Println("Login: Authentication failed - user not found")
+			recordLoginFailure(lockout, lockoutKey)
 			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
 			return
 		}
@@ -66,11 +88,15 @@ func HandleLogin(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
 		// Verify password
 		if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
 			log.Println("Login: Authentication failed - invalid password")
+			recordLoginFailure(lockout, lockoutKey)
 			http.Error(w, "Invalid credentials", http.StatusUnauthorized)
 			return
 		}
 
 		log.Println("Login: Successful authentication")
+		if err := lockout.Reset(lockoutKey); err != nil {
+			log.Printf("Login: Failed to reset lockout counter: %v", err)
+		}
 
 		// Check 2FA if enabled
 		if user.TotpSecret != nil && *user.TotpSecret != "" {
@@ -94,6 +120,25 @@ func HandleLogin(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
 	}
 }
 
+// recordLoginFailure counts a failed login towards the account lockout
+func recordLoginFailure(lockout *LoginLockout, key string) {
+	lockedFor, err := lockout.RecordFailure(key)
+	if err != nil {
+		log.Printf("Login: Failed to record failed attempt: %v", err)
+		return
+	}
+	if lockedFor > 0 {
+		log.Printf("Login: Too many failed attempts, account locked for %s", lockedFor)
+	}
+}
+
+// writeLoginLocked responds 429 with a Retry-After hint
+func writeLoginLocked(w http.ResponseWriter, retryAfter time.Duration) {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	http.Error(w, fmt.Sprintf("Too many failed login attempts. Try again in %d seconds.", seconds), http.StatusTooManyRequests)
+}
+
 // HandleLogout handles user logout
 func HandleLogout() http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -137,6 +182,7 @@ func HandleSetup(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
 			Password:  string(hashedPassword),
 			Provider:  new("local"),
 			Active:    true,
+			IsAdmin:   true,
 			CreatedAt: time.Now(),
 		}
 
@@ -196,7 +242,7 @@ func HandleGetSetupStatus(db *gorm.DB) http.HandlerFunc {
 }
 
 // AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtSecret string, db *gorm.DB) func(http.Handler) http.Handler {
+func AuthMiddleware(keys *jwtkeys.KeySet, db *gorm.DB) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			authHeader := r.Header.Get("Authorization")
@@ -212,17 +258,7 @@ func AuthMiddleware(jwtSecret string, db *gorm.DB) func(http.Handler) http.Handl
 			}
 
 			// Parse token with algorithm validation
-			token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-				// Validate the algorithm is HMAC
-				if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-					return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-				}
-				// Only accept HS256
-				if token.Method.Alg() != "HS256" {
-					return nil, fmt.Errorf("unexpected signing algorithm: %v", token.Method.Alg())
-				}
-				return []byte(jwtSecret), nil
-			})
+			token, err := jwt.Parse(tokenString, keys.Keyfunc)
 
 			if err != nil || !token.Valid {
 				http.Error(w, "Invalid token", http.StatusUnauthorized)
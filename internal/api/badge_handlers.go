@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"net/http"
 	"strconv"
+	"unicode/utf8"
 
 	"github.com/go-chi/chi/v5"
 	"gorm.io/gorm"
@@ -59,7 +60,7 @@ func HandleStatusBadge(db *gorm.DB) http.HandlerFunc {
 			}
 		}
 
-		svg := generateBadgeSVG("status", statusText, color)
+		svg := generateBadgeSVG(parseBadgeStyle(r.URL.Query(), "status"), statusText, color)
 
 		w.Header().Set("Content-Type", "image/svg+xml")
 		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
@@ -128,7 +129,7 @@ func HandleUptimeBadge(db *gorm.DB) http.HandlerFunc {
 			label = fmt.Sprintf("uptime (%s)", period)
 		}
 
-		svg := generateBadgeSVG(label, uptimeText, color)
+		svg := generateBadgeSVG(parseBadgeStyle(r.URL.Query(), label), uptimeText, color)
 
 		w.Header().Set("Content-Type", "image/svg+xml")
 		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
@@ -183,7 +184,7 @@ func HandlePingBadge(db *gorm.DB) http.HandlerFunc {
 			}
 		}
 
-		svg := generateBadgeSVG("response time", pingText, color)
+		svg := generateBadgeSVG(parseBadgeStyle(r.URL.Query(), "response time"), pingText, color)
 
 		w.Header().Set("Content-Type", "image/svg+xml")
 		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
@@ -191,30 +192,30 @@ func HandlePingBadge(db *gorm.DB) http.HandlerFunc {
 	}
 }
 
-// generateBadgeSVG generates a shields.io style badge
-func generateBadgeSVG(label, message, color string) string {
-	// Color mapping
-	colorMap := map[string]string{
-		"brightgreen": "#4c1",
-		"green":       "#97ca00",
-		"yellowgreen": "#a4a61d",
-		"yellow":      "#dfb317",
-		"orange":      "#fe7d37",
-		"red":         "#e05d44",
-		"blue":        "#007ec6",
-		"gray":        "#555",
-		"lightgray":   "#9f9f9f",
-	}
-
-	hexColor, ok := colorMap[color]
+// generateBadgeSVG generates a shields.io style badge. The label and message
+// are escaped, as custom labels come straight from the query string.
+func generateBadgeSVG(style badgeStyle, message, color string) string {
+	hexColor, ok := badgeColors[color]
 	if !ok {
-		hexColor = colorMap["gray"]
+		hexColor = badgeColors["gray"]
 	}
 
-	labelWidth := len(label) * 6 + 10
-	messageWidth := len(message) * 6 + 10
+	labelWidth := utf8.RuneCountInString(style.Label) * 6 + 10
+	messageWidth := utf8.RuneCountInString(message) * 6 + 10
+	labelOffset := 0
+	logo := ""
+	if style.Logo != "" {
+		labelOffset = badgeLogoWidth
+		labelWidth += labelOffset
+		logo = fmt.Sprintf(`
+  <image x="5" y="3" width="14" height="14" href="%s"/>`, escapeBadgeText(style.Logo))
+	}
 	totalWidth := labelWidth + messageWidth
 
+	label := escapeBadgeText(style.Label)
+	message = escapeBadgeText(message)
+	labelX := labelOffset + (labelWidth-labelOffset)/2
+
 	svg := fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="20">
   <linearGradient id="b" x2="0" y2="100%%">
     <stop offset="0" stop-color="#bbb" stop-opacity=".1"/>
@@ -224,10 +225,10 @@ func generateBadgeSVG(label, message, color string) string {
     <rect width="%d" height="20" rx="3" fill="#fff"/>
   </mask>
   <g mask="url(#a)">
-    <path fill="#555" d="M0 0h%dv20H0z"/>
+    <path fill="%s" d="M0 0h%dv20H0z"/>
     <path fill="%s" d="M%d 0h%dv20H%dz"/>
     <path fill="url(#b)" d="M0 0h%dv20H0z"/>
-  </g>
+  </g>%s
   <g fill="#fff" text-anchor="middle" font-family="DejaVu Sans,Verdana,Geneva,sans-serif" font-size="11">
     <text x="%d" y="15" fill="#010101" fill-opacity=".3">%s</text>
     <text x="%d" y="14">%s</text>
@@ -237,10 +238,11 @@ func generateBadgeSVG(label, message, color string) string {
 </svg>`,
 		totalWidth,
 		totalWidth,
-		labelWidth, hexColor, labelWidth, messageWidth, labelWidth,
+		style.LabelColor, labelWidth, hexColor, labelWidth, messageWidth, labelWidth,
 		totalWidth,
-		labelWidth/2, label,
-		labelWidth/2, label,
+		logo,
+		labelX, label,
+		labelX, label,
 		labelWidth+messageWidth/2, message,
 		labelWidth+messageWidth/2, message,
 	)
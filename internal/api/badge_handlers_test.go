@@ -0,0 +1,48 @@
+package api
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateBadgeSVGEscapesText(t *testing.T) {
+	tests := []struct {
+		label, message string
+		wantLabel      string
+		wantMessage    string
+	}{
+		{"status", "<script>alert(1)</script>", "status", "&lt;script&gt;alert(1)&lt;/script&gt;"},
+		{"R&D", "up & running", "R&amp;D", "up &amp; running"},
+		{`say "hi"`, `it's`, "say &#34;hi&#34;", "it&#39;s"},
+		{"tab\there", "bad\x00byte", "tab&#x9;here", "bad\uFFFDbyte"},
+	}
+	for _, tt := range tests {
+		svg := generateBadgeSVG(badgeStyle{Label: tt.label, LabelColor: "#555"}, tt.message, "brightgreen")
+
+		if err := wellFormed(svg); err != nil {
+			t.Errorf("badge for %q / %q is not well-formed: %v\n%s", tt.label, tt.message, err, svg)
+		}
+		if !strings.Contains(svg, ">"+tt.wantLabel+"</text>") {
+			t.Errorf("label %q not escaped to %q:\n%s", tt.label, tt.wantLabel, svg)
+		}
+		if !strings.Contains(svg, ">"+tt.wantMessage+"</text>") {
+			t.Errorf("message %q not escaped to %q:\n%s", tt.message, tt.wantMessage, svg)
+		}
+		if strings.Contains(svg, "<script") {
+			t.Errorf("badge contains a script element:\n%s", svg)
+		}
+	}
+}
+
+func TestGenerateBadgeSVGWidthFollowsDisplayedText(t *testing.T) {
+	// Entities render as one character, so they don't widen the badge
+	plain := generateBadgeSVG(badgeStyle{Label: "RxD", LabelColor: "#555"}, "a", "gray")
+	escaped := generateBadgeSVG(badgeStyle{Label: "R&D", LabelColor: "#555"}, "a", "gray")
+
+	width := func(svg string) string {
+		return svg[:strings.Index(svg, "\n")]
+	}
+	if width(plain) != width(escaped) {
+		t.Errorf("escaped label changed the width: %s vs %s", width(escaped), width(plain))
+	}
+}
@@ -0,0 +1,140 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+const (
+	// maxBadgeLabelLength caps custom labels, in characters
+	maxBadgeLabelLength = 64
+	// maxBadgeLogoLength caps data URI logos, in bytes
+	maxBadgeLogoLength = 8 * 1024
+	// badgeLogoWidth is the space a logo takes at the start of the label
+	badgeLogoWidth = 17
+)
+
+// badgeColors maps the named badge colors to their hex values
+var badgeColors = map[string]string{
+	"brightgreen": "#4c1",
+	"green":       "#97ca00",
+	"yellowgreen": "#a4a61d",
+	"yellow":      "#dfb317",
+	"orange":      "#fe7d37",
+	"red":         "#e05d44",
+	"blue":        "#007ec6",
+	"gray":        "#555",
+	"lightgray":   "#9f9f9f",
+}
+
+var (
+	hexColorPattern = regexp.MustCompile(`^#?([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+	dataURIPattern  = regexp.MustCompile(`^data:image/(png|gif|jpeg|webp|svg\+xml);base64,([A-Za-z0-9+/]+={0,2})$`)
+)
+
+// badgeIcons are the logos that can be picked by name
+var badgeIcons = map[string]string{
+	"check":  `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#fff" d="M9 16.2 4.8 12l-1.4 1.4L9 19 21 7l-1.4-1.4z"/></svg>`,
+	"heart":  `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#fff" d="M12 21.4 10.6 20C5.4 15.4 2 12.3 2 8.5 2 5.4 4.4 3 7.5 3c1.7 0 3.4.8 4.5 2.1C13.1 3.8 14.8 3 16.5 3 19.6 3 22 5.4 22 8.5c0 3.8-3.4 6.9-8.6 11.5z"/></svg>`,
+	"server": `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#fff" d="M3 3h18v8H3zm0 10h18v8H3zm3-7v2h2V6zm0 10v2h2v-2z"/></svg>`,
+	"globe":  `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 24 24"><path fill="#fff" d="M12 2a10 10 0 1 0 0 20 10 10 0 0 0 0-20zm6.9 6h-2.9a15.7 15.7 0 0 0-1.4-3.6A8 8 0 0 1 18.9 8zM12 4c.8 1.2 1.5 2.5 1.9 4h-3.8c.4-1.5 1.1-2.8 1.9-4zM4.3 14a8.2 8.2 0 0 1 0-4h3.4a16.5 16.5 0 0 0 0 4zm.8 2h2.9c.3 1.3.8 2.5 1.4 3.6A8 8 0 0 1 5.1 16zM8 8H5.1a8 8 0 0 1 4.3-3.6C8.8 5.5 8.3 6.7 8 8zm4 12c-.8-1.2-1.5-2.5-1.9-4h3.8c-.4 1.5-1.1 2.8-1.9 4zm2.3-6H9.7a14.7 14.7 0 0 1 0-4h4.6a14.7 14.7 0 0 1 0 4zm.3 5.6c.6-1.1 1.1-2.3 1.4-3.6h2.9a8 8 0 0 1-4.3 3.6zm1.7-5.6a16.5 16.5 0 0 0 0-4h3.4a8.2 8.2 0 0 1 0 4z"/></svg>`,
+}
+
+// badgeStyle holds the optional customizations of a badge
+type badgeStyle struct {
+	Label      string // plain text; escaped when rendered
+	LabelColor string // hex color of the left segment
+	Logo       string // data URI drawn before the label, or ""
+}
+
+// parseBadgeStyle reads the label, labelColor and logo query parameters.
+// Values that can't be used fall back to the defaults, so a badge always
+// renders.
+func parseBadgeStyle(query url.Values, defaultLabel string) badgeStyle {
+	style := badgeStyle{
+		Label:      defaultLabel,
+		LabelColor: badgeColors["gray"],
+	}
+
+	if label := sanitizeBadgeLabel(query.Get("label")); label != "" {
+		style.Label = label
+	}
+	if color, ok := parseBadgeColor(query.Get("labelColor")); ok {
+		style.LabelColor = color
+	}
+	style.Logo = parseBadgeLogo(query.Get("logo"))
+
+	return style
+}
+
+// sanitizeBadgeLabel trims a custom label, drops control characters and
+// caps its length. Markup is escaped later, when the SVG is written.
+func sanitizeBadgeLabel(label string) string {
+	if !utf8.ValidString(label) {
+		label = strings.ToValidUTF8(label, "")
+	}
+	label = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, label)
+	label = strings.TrimSpace(label)
+
+	if utf8.RuneCountInString(label) > maxBadgeLabelLength {
+		label = string([]rune(label)[:maxBadgeLabelLength])
+	}
+	return label
+}
+
+// parseBadgeColor accepts a named badge color or a 3 or 6 digit hex color,
+// with or without the leading #
+func parseBadgeColor(color string) (string, bool) {
+	color = strings.TrimSpace(color)
+	if hex, ok := badgeColors[strings.ToLower(color)]; ok {
+		return hex, true
+	}
+	if m := hexColorPattern.FindStringSubmatch(color); m != nil {
+		return "#" + strings.ToLower(m[1]), true
+	}
+	return "", false
+}
+
+// parseBadgeLogo returns the data URI for a known icon name or a base64
+// image data URI, or "" when logo is neither
+func parseBadgeLogo(logo string) string {
+	logo = strings.TrimSpace(logo)
+	if logo == "" {
+		return ""
+	}
+	if icon, ok := badgeIcons[strings.ToLower(logo)]; ok {
+		return "data:image/svg+xml;base64," + base64.StdEncoding.EncodeToString([]byte(icon))
+	}
+
+	// Query strings turn + into spaces
+	logo = strings.ReplaceAll(logo, " ", "+")
+	if len(logo) > maxBadgeLogoLength {
+		return ""
+	}
+	m := dataURIPattern.FindStringSubmatch(logo)
+	if m == nil {
+		return ""
+	}
+	if _, err := base64.StdEncoding.DecodeString(m[2]); err != nil {
+		return ""
+	}
+	return logo
+}
+
+// escapeBadgeText escapes text for use in SVG text and attributes. Quotes
+// are escaped too, and characters XML doesn't allow become U+FFFD.
+func escapeBadgeText(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
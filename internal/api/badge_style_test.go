@@ -0,0 +1,143 @@
+package api
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// wellFormed reports whether svg parses as XML
+func wellFormed(svg string) error {
+	decoder := xml.NewDecoder(strings.NewReader(svg))
+	for {
+		if _, err := decoder.Token(); err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+func TestBadgeCustomLabel(t *testing.T) {
+	style := parseBadgeStyle(url.Values{"label": {"api.example.com"}, "labelColor": {"blue"}}, "status")
+	if style.Label != "api.example.com" || style.LabelColor != "#007ec6" {
+		t.Fatalf("style = %+v, want the custom label and color", style)
+	}
+
+	svg := generateBadgeSVG(style, "up", "brightgreen")
+	if !strings.Contains(svg, ">api.example.com</text>") {
+		t.Errorf("badge has no custom label:\n%s", svg)
+	}
+	if !strings.Contains(svg, `<path fill="#007ec6" d="M0 0h`) {
+		t.Errorf("label segment doesn't use labelColor:\n%s", svg)
+	}
+
+	// Without parameters the default label and color are kept
+	style = parseBadgeStyle(url.Values{}, "uptime (7d)")
+	if style.Label != "uptime (7d)" || style.LabelColor != "#555" || style.Logo != "" {
+		t.Errorf("default style = %+v", style)
+	}
+}
+
+func TestBadgeLabelInjectionNeutralized(t *testing.T) {
+	payloads := []string{
+		`</text><script>alert(1)</script><text>`,
+		`"><foreignObject><iframe src="javascript:alert(1)"/></foreignObject>`,
+		`<a xlink:href="javascript:alert(1)">x</a>`,
+		"status\x00\x1b]<svg onload=alert(1)>",
+	}
+	for _, payload := range payloads {
+		style := parseBadgeStyle(url.Values{"label": {payload}}, "status")
+		svg := generateBadgeSVG(style, "up", "brightgreen")
+
+		if err := wellFormed(svg); err != nil {
+			t.Errorf("label %q breaks the SVG: %v\n%s", payload, err, svg)
+		}
+		for _, needle := range []string{"<script", "<foreignObject", "<iframe", "<a ", "<svg onload"} {
+			if strings.Contains(svg, needle) {
+				t.Errorf("label %q injected %q:\n%s", payload, needle, svg)
+			}
+		}
+	}
+}
+
+func TestBadgeLabelLength(t *testing.T) {
+	style := parseBadgeStyle(url.Values{"label": {strings.Repeat("é", 200)}}, "status")
+	if got := len([]rune(style.Label)); got != maxBadgeLabelLength {
+		t.Errorf("label has %d characters, want %d", got, maxBadgeLabelLength)
+	}
+	if style := parseBadgeStyle(url.Values{"label": {"   "}}, "status"); style.Label != "status" {
+		t.Errorf("blank label = %q, want the default", style.Label)
+	}
+}
+
+func TestParseBadgeColor(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"red", "#e05d44", true},
+		{"BrightGreen", "#4c1", true},
+		{"#ABC", "#abc", true},
+		{"00ff7f", "#00ff7f", true},
+		{"", "", false},
+		{"#12345", "", false},
+		{`red" onload="alert(1)`, "", false},
+		{"url(#x)", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := parseBadgeColor(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseBadgeColor(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+
+	// An invalid labelColor keeps the default instead of reaching the SVG
+	style := parseBadgeStyle(url.Values{"labelColor": {`#555"/><script>alert(1)</script>`}}, "status")
+	if style.LabelColor != "#555" {
+		t.Errorf("LabelColor = %q, want the default", style.LabelColor)
+	}
+}
+
+func TestBadgeLogo(t *testing.T) {
+	// Known icon names
+	style := parseBadgeStyle(url.Values{"logo": {"Heart"}}, "status")
+	if !strings.HasPrefix(style.Logo, "data:image/svg+xml;base64,") {
+		t.Fatalf("logo = %q, want an SVG data URI", style.Logo)
+	}
+	svg := generateBadgeSVG(style, "up", "brightgreen")
+	if !strings.Contains(svg, `<image x="5" y="3" width="14" height="14" href="data:image/svg+xml;base64,`) {
+		t.Errorf("badge has no logo:\n%s", svg)
+	}
+	if err := wellFormed(svg); err != nil {
+		t.Errorf("badge with logo is not well-formed: %v", err)
+	}
+
+	// Data URIs, including one whose + signs became spaces in the query
+	png := "data:image/png;base64," + base64.StdEncoding.EncodeToString([]byte{0x89, 'P', 'N', 'G', 0xfb, 0xff})
+	if got := parseBadgeLogo(png); got != png {
+		t.Errorf("parseBadgeLogo(png) = %q, want %q", got, png)
+	}
+	if got := parseBadgeLogo(strings.ReplaceAll(png, "+", " ")); got != png {
+		t.Errorf("parseBadgeLogo with spaces = %q, want %q", got, png)
+	}
+
+	invalid := []string{
+		"unknown-icon",
+		"https://example.com/logo.png",
+		"javascript:alert(1)",
+		"data:text/html;base64,PHNjcmlwdD5hbGVydCgxKTwvc2NyaXB0Pg==",
+		`data:image/png;base64,AAAA"/><script>alert(1)</script>`,
+		"data:image/png;base64,A",
+		"data:image/png;base64," + strings.Repeat("A", maxBadgeLogoLength),
+	}
+	for _, logo := range invalid {
+		if got := parseBadgeLogo(logo); got != "" {
+			t.Errorf("parseBadgeLogo(%q) = %q, want it rejected", logo, got)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// maxEscalationSteps caps the steps of one escalation policy
+const maxEscalationSteps = 10
+
+// escalationPolicyRequest is the body of the create and update endpoints
+type escalationPolicyRequest struct {
+	Name  string                  `json:"name"`
+	Steps []models.EscalationStep `json:"steps"`
+}
+
+// validate checks the name and steps of a policy. Steps run in order, so
+// their delays must increase, and each step notifies at least one channel.
+func (req *escalationPolicyRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if len(req.Steps) == 0 {
+		return fmt.Errorf("at least one step is required")
+	}
+	if len(req.Steps) > maxEscalationSteps {
+		return fmt.Errorf("at most %d steps are allowed", maxEscalationSteps)
+	}
+	for i, step := range req.Steps {
+		if step.DelayMinutes < 0 {
+			return fmt.Errorf("step %d: delay_minutes must not be negative", i+1)
+		}
+		if i > 0 && step.DelayMinutes <= req.Steps[i-1].DelayMinutes {
+			return fmt.Errorf("step %d: delay_minutes must be greater than the previous step's", i+1)
+		}
+		if len(step.NotificationIDs) == 0 {
+			return fmt.Errorf("step %d: notification_ids must not be empty", i+1)
+		}
+	}
+	return nil
+}
+
+// notificationIDs returns the distinct notifications the steps notify
+func (req *escalationPolicyRequest) notificationIDs() []int {
+	seen := make(map[int]bool)
+	var ids []int
+	for _, step := range req.Steps {
+		for _, id := range step.NotificationIDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+// decodeEscalationPolicy reads and validates a policy request, checking
+// that the user owns every notification it uses. It writes the error
+// response and returns false when the request is rejected.
+func decodeEscalationPolicy(db *gorm.DB, w http.ResponseWriter, r *http.Request, user *models.User) (*escalationPolicyRequest, bool) {
+	var req escalationPolicyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := req.validate(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+
+	ids := req.notificationIDs()
+	var count int64
+	if err := db.Model(&models.Notification{}).
+		Where("id IN ? AND user_id = ?", ids, user.ID).
+		Count(&count).Error; err != nil {
+		http.Error(w, "Failed to verify notifications", http.StatusInternalServerError)
+		return nil, false
+	}
+	if int(count) != len(ids) {
+		http.Error(w, "Notification not found", http.StatusBadRequest)
+		return nil, false
+	}
+	return &req, true
+}
+
+// ownsEscalationPolicy reports whether the policy exists and belongs to the user
+func ownsEscalationPolicy(db *gorm.DB, userID, policyID int) (bool, error) {
+	var count int64
+	err := db.Model(&models.EscalationPolicy{}).
+		Where("id = ? AND user_id = ?", policyID, userID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// HandleGetEscalationPolicies lists the user's escalation policies
+func HandleGetEscalationPolicies(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+
+		policies := []models.EscalationPolicy{}
+		if err := db.Where("user_id = ?", user.ID).Order("name").Find(&policies).Error; err != nil {
+			http.Error(w, "Failed to fetch escalation policies", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(policies)
+	}
+}
+
+// HandleGetEscalationPolicy returns one of the user's escalation policies
+func HandleGetEscalationPolicy(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+
+		var policy models.EscalationPolicy
+		if err := db.Where("id = ? AND user_id = ?", id, user.ID).First(&policy).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Escalation policy not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch escalation policy", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(policy)
+	}
+}
+
+// HandleCreateEscalationPolicy creates an escalation policy
+func HandleCreateEscalationPolicy(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+
+		req, ok := decodeEscalationPolicy(db, w, r, user)
+		if !ok {
+			return
+		}
+
+		policy := models.EscalationPolicy{
+			UserID:    user.ID,
+			Name:      req.Name,
+			Steps:     req.Steps,
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+		if err := db.Create(&policy).Error; err != nil {
+			http.Error(w, "Failed to create escalation policy", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(policy)
+	}
+}
+
+// HandleUpdateEscalationPolicy replaces the name and steps of an escalation
+// policy. Outages already escalating keep the steps they started with.
+func HandleUpdateEscalationPolicy(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+
+		req, ok := decodeEscalationPolicy(db, w, r, user)
+		if !ok {
+			return
+		}
+
+		var policy models.EscalationPolicy
+		if err := db.Where("id = ? AND user_id = ?", id, user.ID).First(&policy).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Escalation policy not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch escalation policy", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		policy.Name = req.Name
+		policy.Steps = req.Steps
+		policy.UpdatedAt = time.Now()
+		if err := db.Save(&policy).Error; err != nil {
+			http.Error(w, "Failed to update escalation policy", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(policy)
+	}
+}
+
+// HandleDeleteEscalationPolicy deletes an escalation policy no monitor uses
+func HandleDeleteEscalationPolicy(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+
+		var count int64
+		if err := db.Model(&models.Monitor{}).
+			Where("escalation_policy_id = ? AND user_id = ?", id, user.ID).
+			Count(&count).Error; err != nil {
+			http.Error(w, "Failed to check escalation policy usage", http.StatusInternalServerError)
+			return
+		}
+		if count > 0 {
+			http.Error(w, "Escalation policy is in use by one or more monitors", http.StatusConflict)
+			return
+		}
+
+		result := db.Where("id = ? AND user_id = ?", id, user.ID).Delete(&models.EscalationPolicy{})
+		if result.Error != nil {
+			http.Error(w, "Failed to delete escalation policy", http.StatusInternalServerError)
+			return
+		}
+		if result.RowsAffected == 0 {
+			http.Error(w, "Escalation policy not found", http.StatusNotFound)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,50 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestEscalationPolicyRequestValidate(t *testing.T) {
+	req := escalationPolicyRequest{Name: "  Production  ", Steps: []models.EscalationStep{
+		{DelayMinutes: 0, NotificationIDs: []int{1}},
+		{DelayMinutes: 10, NotificationIDs: []int{2, 3}},
+		{DelayMinutes: 30, NotificationIDs: []int{3, 4}},
+	}}
+	if err := req.validate(); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if req.Name != "Production" {
+		t.Errorf("name = %q, want it trimmed", req.Name)
+	}
+	if ids := req.notificationIDs(); !reflect.DeepEqual(ids, []int{1, 2, 3, 4}) {
+		t.Errorf("notificationIDs() = %v, want [1 2 3 4]", ids)
+	}
+
+	tooMany := make([]models.EscalationStep, maxEscalationSteps+1)
+	for i := range tooMany {
+		tooMany[i] = models.EscalationStep{DelayMinutes: i, NotificationIDs: []int{1}}
+	}
+	invalid := []escalationPolicyRequest{
+		{Name: "", Steps: []models.EscalationStep{{NotificationIDs: []int{1}}}},
+		{Name: "no steps"},
+		{Name: "too many", Steps: tooMany},
+		{Name: "negative", Steps: []models.EscalationStep{{DelayMinutes: -5, NotificationIDs: []int{1}}}},
+		{Name: "unordered", Steps: []models.EscalationStep{
+			{DelayMinutes: 30, NotificationIDs: []int{1}},
+			{DelayMinutes: 10, NotificationIDs: []int{2}},
+		}},
+		{Name: "same delay", Steps: []models.EscalationStep{
+			{DelayMinutes: 10, NotificationIDs: []int{1}},
+			{DelayMinutes: 10, NotificationIDs: []int{2}},
+		}},
+		{Name: "empty step", Steps: []models.EscalationStep{{DelayMinutes: 0}}},
+	}
+	for _, req := range invalid {
+		if err := req.validate(); err == nil {
+			t.Errorf("validate(%q) succeeded, want error", req.Name)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+)
+
+// executorStateResponse is the executor's running jobs, and where they have
+// drifted from the monitors marked active in the database
+type executorStateResponse struct {
+	Jobs             []monitor.JobState `json:"jobs"`
+	ActiveNotRunning []int              `json:"active_not_running"` // active monitors without a job
+	RunningNotActive []int              `json:"running_not_active"` // jobs of monitors paused or deleted since
+	Stale            []int              `json:"stale"`              // running jobs without a recent check
+}
+
+// executorDrift compares the running jobs' monitor IDs to the active ones
+func executorDrift(jobs []monitor.JobState, active []int) (activeNotRunning, runningNotActive []int) {
+	running := make(map[int]bool, len(jobs))
+	for _, job := range jobs {
+		running[job.MonitorID] = true
+	}
+	isActive := make(map[int]bool, len(active))
+	activeNotRunning = []int{}
+	for _, id := range active {
+		isActive[id] = true
+		if !running[id] {
+			activeNotRunning = append(activeNotRunning, id)
+		}
+	}
+	runningNotActive = []int{}
+	for _, job := range jobs {
+		if !isActive[job.MonitorID] {
+			runningNotActive = append(runningNotActive, job.MonitorID)
+		}
+	}
+	sort.Ints(activeNotRunning)
+	return activeNotRunning, runningNotActive
+}
+
+// staleJobs returns the running jobs whose last check is older than
+// staleIntervalFactor intervals, e.g. stuck on a check that never returns.
+// Jobs yet to finish their first check aren't judged.
+func staleJobs(jobs []monitor.JobState, now time.Time) []int {
+	stale := []int{}
+	for _, job := range jobs {
+		if job.LastCheck == nil {
+			continue
+		}
+		if now.Sub(*job.LastCheck) > staleIntervalFactor*time.Duration(job.Interval)*time.Second {
+			stale = append(stale, job.MonitorID)
+		}
+	}
+	return stale
+}
+
+// jobController is the part of the executor reconciling starts and stops
+type jobController interface {
+	Jobs() []monitor.JobState
+	StartMonitor(m *monitor.Monitor)
+	StopMonitor(monitorID int)
+}
+
+// reconcileResult is what a reconcile changed, by monitor ID
+type reconcileResult struct {
+	Started   []int `json:"started"`   // active monitors that had no job
+	Stopped   []int `json:"stopped"`   // jobs of monitors no longer active
+	Restarted []int `json:"restarted"` // stale jobs
+}
+
+// reconcileJobs brings the executor in line with the active monitors: it
+// stops jobs of inactive monitors, and starts or restarts active monitors
+// loaded by load. Monitors of unregistered types are left alone, as starting
+// them only records another unsupported type heartbeat.
+func reconcileJobs(executor jobController, active []int, load func(ids []int) ([]*monitor.Monitor, error), now time.Time) (*reconcileResult, error) {
+	jobs := executor.Jobs()
+	activeNotRunning, runningNotActive := executorDrift(jobs, active)
+	stale := staleJobs(jobs, now)
+
+	result := &reconcileResult{Started: []int{}, Stopped: runningNotActive, Restarted: []int{}}
+	for _, id := range runningNotActive {
+		executor.StopMonitor(id)
+	}
+
+	monitors, err := load(append(activeNotRunning, stale...))
+	if err != nil {
+		return nil, err
+	}
+	isStale := make(map[int]bool, len(stale))
+	for _, id := range stale {
+		isStale[id] = true
+	}
+	for _, m := range monitors {
+		if isUnsupportedMonitorType(m.Type) {
+			continue
+		}
+		executor.StartMonitor(m)
+		if isStale[m.ID] {
+			result.Restarted = append(result.Restarted, m.ID)
+		} else {
+			result.Started = append(result.Started, m.ID)
+		}
+	}
+	sort.Ints(result.Started)
+	sort.Ints(result.Restarted)
+	return result, nil
+}
+
+// HandleGetExecutorState returns the executor's in-memory monitor jobs for
+// debugging. Admin only, as it lists every user's monitors.
+func HandleGetExecutorState(db *gorm.DB, executor *monitor.Executor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		if !user.IsAdmin {
+			http.Error(w, "Admin access required", http.StatusForbidden)
+			return
+		}
+		if executor == nil {
+			http.Error(w, "Executor is not running", http.StatusServiceUnavailable)
+			return
+		}
+
+		jobs := executor.Jobs()
+		var active []int
+		if err := db.Model(&models.Monitor{}).Where("active = ?", true).Pluck("id", &active).Error; err != nil {
+			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
+			return
+		}
+
+		response := executorStateResponse{Jobs: jobs, Stale: staleJobs(jobs, time.Now())}
+		response.ActiveNotRunning, response.RunningNotActive = executorDrift(jobs, active)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(response)
+	}
+}
+
+// HandleReconcileExecutor fixes the drift GET /admin/executor reports:
+// jobs of inactive monitors are stopped, active monitors without a job are
+// started and stale jobs are restarted. Admin only.
+func HandleReconcileExecutor(db *gorm.DB, executor *monitor.Executor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		if !user.IsAdmin {
+			http.Error(w, "Admin access required", http.StatusForbidden)
+			return
+		}
+		if executor == nil {
+			http.Error(w, "Executor is not running", http.StatusServiceUnavailable)
+			return
+		}
+
+		var active []int
+		if err := db.Model(&models.Monitor{}).Where("active = ?", true).Pluck("id", &active).Error; err != nil {
+			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
+			return
+		}
+
+		load := func(ids []int) ([]*monitor.Monitor, error) {
+			var monitors []*monitor.Monitor
+			if len(ids) == 0 {
+				return monitors, nil
+			}
+			// Config is parsed by the AfterFind hook
+			err := db.Where("id IN ? AND active = ?", ids, true).Find(&monitors).Error
+			return monitors, err
+		}
+		result, err := reconcileJobs(executor, active, load, time.Now())
+		if err != nil {
+			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(result)
+	}
+}
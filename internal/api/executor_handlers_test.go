@@ -0,0 +1,122 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+)
+
+func TestExecutorDrift(t *testing.T) {
+	jobs := []monitor.JobState{{MonitorID: 1}, {MonitorID: 2}, {MonitorID: 4}}
+	activeNotRunning, runningNotActive := executorDrift(jobs, []int{5, 1, 2, 3})
+
+	if !reflect.DeepEqual(activeNotRunning, []int{3, 5}) {
+		t.Errorf("active not running = %v, want [3 5]", activeNotRunning)
+	}
+	if !reflect.DeepEqual(runningNotActive, []int{4}) {
+		t.Errorf("running not active = %v, want [4]", runningNotActive)
+	}
+
+	none, _ := executorDrift(nil, nil)
+	if none == nil {
+		t.Error("no drift should encode as [], not null")
+	}
+}
+
+func TestStaleJobs(t *testing.T) {
+	now := time.Date(2026, 5, 2, 12, 0, 0, 0, time.UTC)
+	at := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+	jobs := []monitor.JobState{
+		{MonitorID: 1, Interval: 60, LastCheck: at(time.Minute)},
+		{MonitorID: 2, Interval: 60, LastCheck: at(3 * time.Minute)},
+		{MonitorID: 3, Interval: 60},
+		{MonitorID: 4, Interval: 600, LastCheck: at(15 * time.Minute)},
+	}
+
+	if stale := staleJobs(jobs, now); !reflect.DeepEqual(stale, []int{2}) {
+		t.Errorf("stale = %v, want [2]", stale)
+	}
+}
+
+// fakeJobs is a jobController recording starts and stops
+type fakeJobs struct {
+	jobs    map[int]monitor.JobState
+	started []int
+	stopped []int
+}
+
+func (f *fakeJobs) Jobs() []monitor.JobState {
+	jobs := []monitor.JobState{}
+	for _, job := range f.jobs {
+		jobs = append(jobs, job)
+	}
+	return jobs
+}
+
+func (f *fakeJobs) StartMonitor(m *monitor.Monitor) {
+	f.started = append(f.started, m.ID)
+	f.jobs[m.ID] = monitor.JobState{MonitorID: m.ID, Interval: m.Interval}
+}
+
+func (f *fakeJobs) StopMonitor(monitorID int) {
+	f.stopped = append(f.stopped, monitorID)
+	delete(f.jobs, monitorID)
+}
+
+func TestReconcileJobs(t *testing.T) {
+	now := time.Date(2026, 5, 2, 12, 0, 0, 0, time.UTC)
+	recent := now.Add(-30 * time.Second)
+	stuck := now.Add(-time.Hour)
+
+	// Monitor 2 was paused behind the executor's back, 3 and 4 were
+	// activated, and 5's job is stuck
+	executor := &fakeJobs{jobs: map[int]monitor.JobState{
+		1: {MonitorID: 1, Interval: 60, LastCheck: &recent},
+		2: {MonitorID: 2, Interval: 60, LastCheck: &recent},
+		5: {MonitorID: 5, Interval: 60, LastCheck: &stuck},
+	}}
+	monitors := map[int]*monitor.Monitor{
+		1: {ID: 1, Type: "tcp", Interval: 60},
+		3: {ID: 3, Type: "dns", Interval: 60},
+		4: {ID: 4, Type: "no-such-type", Interval: 60},
+		5: {ID: 5, Type: "tcp", Interval: 60},
+	}
+	var loaded []int
+	load := func(ids []int) ([]*monitor.Monitor, error) {
+		loaded = append(loaded, ids...)
+		var found []*monitor.Monitor
+		for _, id := range ids {
+			found = append(found, monitors[id])
+		}
+		return found, nil
+	}
+
+	result, err := reconcileJobs(executor, []int{1, 3, 4, 5}, load, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(result.Stopped, []int{2}) || !reflect.DeepEqual(executor.stopped, []int{2}) {
+		t.Errorf("stopped %v (reported %v), want [2]", executor.stopped, result.Stopped)
+	}
+	if !reflect.DeepEqual(result.Started, []int{3}) {
+		t.Errorf("started = %v, want [3]: 4 has an unsupported type", result.Started)
+	}
+	if !reflect.DeepEqual(result.Restarted, []int{5}) {
+		t.Errorf("restarted = %v, want [5]", result.Restarted)
+	}
+	if !reflect.DeepEqual(loaded, []int{3, 4, 5}) {
+		t.Errorf("loaded %v, want only the monitors to start [3 4 5]", loaded)
+	}
+
+	// Nothing is left to fix
+	activeNotRunning, runningNotActive := executorDrift(executor.Jobs(), []int{1, 3, 5})
+	if len(activeNotRunning) != 0 || len(runningNotActive) != 0 {
+		t.Errorf("drift after reconcile: %v active not running, %v running not active", activeNotRunning, runningNotActive)
+	}
+}
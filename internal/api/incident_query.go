@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// defaultIncidentLimit is the page size of an incident list without limit
+	defaultIncidentLimit = 50
+
+	// maxIncidentLimit caps the page size of an incident list
+	maxIncidentLimit = 200
+)
+
+// incidentQuery is a validated page and filter of a status page's incidents
+type incidentQuery struct {
+	limit  int
+	offset int
+	status string     // "open", "resolved" or "" for both
+	from   *time.Time // created at or after, if set
+	to     *time.Time // created before, if set
+}
+
+// parseIncidentQuery validates limit (1-200, default 50), offset, status
+// (open or resolved) and the from/to creation range (RFC 3339)
+func parseIncidentQuery(query url.Values) (*incidentQuery, error) {
+	q := &incidentQuery{limit: defaultIncidentLimit}
+
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit < 1 || limit > maxIncidentLimit {
+			return nil, fmt.Errorf("limit must be between 1 and %d", maxIncidentLimit)
+		}
+		q.limit = limit
+	}
+
+	if v := query.Get("offset"); v != "" {
+		offset, err := strconv.Atoi(v)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("offset must be a non-negative number")
+		}
+		q.offset = offset
+	}
+
+	switch status := query.Get("status"); status {
+	case "", "open", "resolved":
+		q.status = status
+	default:
+		return nil, fmt.Errorf("status must be open or resolved")
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  **time.Time
+	}{{"from", &q.from}, {"to", &q.to}} {
+		if v := query.Get(bound.name); v != "" {
+			t, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: must be RFC 3339", bound.name)
+			}
+			*bound.dst = &t
+		}
+	}
+	if q.from != nil && q.to != nil && !q.from.Before(*q.to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+
+	return q, nil
+}
+
+// filter restricts tx to the incidents matching the status and date range
+func (q *incidentQuery) filter(tx *gorm.DB) *gorm.DB {
+	switch q.status {
+	case "open":
+		tx = tx.Where("resolved_at IS NULL")
+	case "resolved":
+		tx = tx.Where("resolved_at IS NOT NULL")
+	}
+	if q.from != nil {
+		tx = tx.Where("created_at >= ?", *q.from)
+	}
+	if q.to != nil {
+		tx = tx.Where("created_at < ?", *q.to)
+	}
+	return tx
+}
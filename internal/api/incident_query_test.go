@@ -0,0 +1,119 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestParseIncidentQueryDefaults(t *testing.T) {
+	q, err := parseIncidentQuery(url.Values{})
+	if err != nil {
+		t.Fatalf("parseIncidentQuery: %v", err)
+	}
+	if q.limit != defaultIncidentLimit || q.offset != 0 || q.status != "" || q.from != nil || q.to != nil {
+		t.Errorf("got %+v, want the first %d incidents unfiltered", q, defaultIncidentLimit)
+	}
+}
+
+func TestParseIncidentQueryPagination(t *testing.T) {
+	tests := []struct {
+		limit, offset string
+		wantLimit     int
+		wantOffset    int
+		wantErr       bool
+	}{
+		{limit: "1", wantLimit: 1},
+		{limit: "200", offset: "400", wantLimit: 200, wantOffset: 400},
+		{offset: "0", wantLimit: defaultIncidentLimit},
+		{limit: "0", wantErr: true},
+		{limit: "201", wantErr: true},
+		{limit: "ten", wantErr: true},
+		{offset: "-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		values := url.Values{}
+		if tt.limit != "" {
+			values.Set("limit", tt.limit)
+		}
+		if tt.offset != "" {
+			values.Set("offset", tt.offset)
+		}
+		q, err := parseIncidentQuery(values)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("limit=%q offset=%q succeeded, want error", tt.limit, tt.offset)
+			}
+			continue
+		}
+		if err != nil || q.limit != tt.wantLimit || q.offset != tt.wantOffset {
+			t.Errorf("limit=%q offset=%q = %+v, %v; want limit %d offset %d",
+				tt.limit, tt.offset, q, err, tt.wantLimit, tt.wantOffset)
+		}
+	}
+}
+
+func TestParseIncidentQueryFilters(t *testing.T) {
+	for _, status := range []string{"open", "resolved"} {
+		q, err := parseIncidentQuery(url.Values{"status": {status}})
+		if err != nil || q.status != status {
+			t.Errorf("status=%s = %+v, %v", status, q, err)
+		}
+	}
+
+	q, err := parseIncidentQuery(url.Values{"from": {"2026-01-01T00:00:00Z"}, "to": {"2026-02-01T00:00:00Z"}})
+	if err != nil {
+		t.Fatalf("parseIncidentQuery: %v", err)
+	}
+	if !q.from.Equal(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)) || !q.to.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("range = %v - %v", q.from, q.to)
+	}
+
+	invalid := []url.Values{
+		{"status": {"closed"}},
+		{"status": {"OPEN"}},
+		{"from": {"2026-01-01"}},
+		{"to": {"yesterday"}},
+		{"from": {"2026-02-01T00:00:00Z"}, "to": {"2026-01-01T00:00:00Z"}},
+		{"from": {"2026-01-01T00:00:00Z"}, "to": {"2026-01-01T00:00:00Z"}},
+	}
+	for _, values := range invalid {
+		if _, err := parseIncidentQuery(values); err == nil {
+			t.Errorf("parseIncidentQuery(%v) succeeded, want error", values)
+		}
+	}
+}
+
+func TestIncidentQueryFilterSQL(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	tests := []struct {
+		values url.Values
+		want   string
+	}{
+		{url.Values{}, `SELECT * FROM "incidents" WHERE status_page_id = $1`},
+		{url.Values{"status": {"open"}}, `SELECT * FROM "incidents" WHERE status_page_id = $1 AND resolved_at IS NULL`},
+		{url.Values{"status": {"resolved"}, "from": {"2026-01-01T00:00:00Z"}},
+			`SELECT * FROM "incidents" WHERE status_page_id = $1 AND resolved_at IS NOT NULL AND created_at >= $2`},
+	}
+	for _, tt := range tests {
+		q, err := parseIncidentQuery(tt.values)
+		if err != nil {
+			t.Fatalf("parseIncidentQuery(%v): %v", tt.values, err)
+		}
+		var incidents []models.Incident
+		stmt := q.filter(db.Where("status_page_id = ?", 1)).Find(&incidents).Statement
+		if got := stmt.SQL.String(); got != tt.want {
+			t.Errorf("%v: SQL = %s, want %s", tt.values, got, tt.want)
+		}
+	}
+}
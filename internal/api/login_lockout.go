@@ -0,0 +1,152 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// LoginLockout temporarily locks an account after repeated failed logins.
+// Unlike the per-IP auth rate limiter it also slows attacks spread over
+// many addresses.
+type LoginLockout struct {
+	db        *gorm.DB
+	threshold int // 0 disables the lockout
+	window    time.Duration
+	duration  time.Duration
+}
+
+// NewLoginLockout locks an account for duration once threshold failures
+// happen within window
+func NewLoginLockout(db *gorm.DB, threshold int, window, duration time.Duration) *LoginLockout {
+	return &LoginLockout{db: db, threshold: threshold, window: window, duration: duration}
+}
+
+// loginLockoutKey identifies the account a login targets. Unknown identifiers
+// get their own key so they lock out exactly like real accounts and the
+// response never reveals whether a username exists.
+func loginLockoutKey(user *models.User, identifier string) string {
+	if user != nil {
+		return fmt.Sprintf("user:%d", user.ID)
+	}
+	return "login:" + strings.ToLower(strings.TrimSpace(identifier))
+}
+
+// Locked reports how long the account behind key stays locked, if it is
+func (l *LoginLockout) Locked(key string) (time.Duration, bool) {
+	if l.threshold <= 0 {
+		return 0, false
+	}
+
+	var attempt models.LoginAttempt
+	if err := l.db.Where("key = ?", key).First(&attempt).Error; err != nil {
+		return 0, false
+	}
+
+	remaining := lockoutRemaining(&attempt, time.Now())
+	return remaining, remaining > 0
+}
+
+// RecordFailure counts a failed login, locking the account once the
+// threshold is reached. It returns the lockout duration when this failure
+// triggered one. The count is kept by a single upsert, so concurrent
+// failures for the same account each count, including the first ones.
+func (l *LoginLockout) RecordFailure(key string) (time.Duration, error) {
+	if l.threshold <= 0 {
+		return 0, nil
+	}
+
+	// A new row holds this failure alone, as applyFailure would set it
+	now := time.Now()
+	first := models.LoginAttempt{Key: key}
+	l.applyFailure(&first, now)
+
+	var result struct {
+		Locked bool `gorm:"column:locked"`
+	}
+	err := l.db.Raw(recordFailureQuery, map[string]interface{}{
+		"key":          key,
+		"failed_count": first.FailedCount,
+		"window_start": first.WindowStart,
+		"locked_until": first.LockedUntil,
+		"now":          now,
+		"since":        now.Add(-l.window),
+		"threshold":    l.threshold,
+		"lock_until":   now.Add(l.duration),
+	}).Scan(&result).Error
+	if err != nil || !result.Locked {
+		return 0, err
+	}
+	return l.duration, nil
+}
+
+// recordFailureQuery applies applyFailure's rules to a stored attempt in
+// place: a window that has passed starts over, and reaching the threshold
+// locks the account and starts counting afresh once the lock expires.
+// locked reports whether this failure set the lock.
+const recordFailureQuery = `
+	INSERT INTO login_attempts (key, failed_count, window_start, locked_until, updated_at)
+	VALUES (@key, @failed_count, @window_start, @locked_until, @now)
+	ON CONFLICT (key) DO UPDATE SET
+		failed_count = CASE
+			WHEN ` + loginAttemptCount + ` >= @threshold THEN 0
+			ELSE ` + loginAttemptCount + `
+		END,
+		window_start = CASE
+			WHEN ` + loginAttemptCount + ` >= @threshold THEN @lock_until
+			WHEN login_attempts.window_start < @since THEN @now
+			ELSE login_attempts.window_start
+		END,
+		locked_until = CASE
+			WHEN ` + loginAttemptCount + ` >= @threshold THEN @lock_until
+			WHEN login_attempts.window_start < @since THEN NULL
+			ELSE login_attempts.locked_until
+		END,
+		updated_at = @now
+	RETURNING locked_until IS NOT NULL AND locked_until = @lock_until AS locked
+`
+
+// loginAttemptCount is a stored attempt's failure count with this failure
+const loginAttemptCount = `(CASE WHEN login_attempts.window_start < @since THEN 1 ELSE login_attempts.failed_count + 1 END)`
+
+// Reset clears the failure count after a successful login
+func (l *LoginLockout) Reset(key string) error {
+	if l.threshold <= 0 {
+		return nil
+	}
+	return l.db.Where("key = ?", key).Delete(&models.LoginAttempt{}).Error
+}
+
+// applyFailure adds a failure to attempt, starting a new window when the
+// previous one has passed, and locks it when the threshold is reached
+func (l *LoginLockout) applyFailure(attempt *models.LoginAttempt, now time.Time) time.Duration {
+	if attempt.WindowStart.IsZero() || now.Sub(attempt.WindowStart) > l.window {
+		attempt.FailedCount = 0
+		attempt.WindowStart = now
+		attempt.LockedUntil = nil
+	}
+
+	attempt.FailedCount++
+	if attempt.FailedCount < l.threshold {
+		return 0
+	}
+
+	// Lock and start counting afresh once the lock expires
+	lockedUntil := now.Add(l.duration)
+	attempt.LockedUntil = &lockedUntil
+	attempt.FailedCount = 0
+	attempt.WindowStart = lockedUntil
+	return l.duration
+}
+
+// lockoutRemaining returns how long attempt stays locked at now
+func lockoutRemaining(attempt *models.LoginAttempt, now time.Time) time.Duration {
+	if attempt.LockedUntil == nil || !now.Before(*attempt.LockedUntil) {
+		return 0
+	}
+	return attempt.LockedUntil.Sub(now)
+}
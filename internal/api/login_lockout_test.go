@@ -0,0 +1,172 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestLoginLockoutLocksAfterThreshold(t *testing.T) {
+	l := NewLoginLockout(nil, 3, 15*time.Minute, 10*time.Minute)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	attempt := &models.LoginAttempt{Key: "user:1"}
+
+	for i := 0; i < 2; i++ {
+		if lockedFor := l.applyFailure(attempt, now.Add(time.Duration(i)*time.Minute)); lockedFor != 0 {
+			t.Fatalf("failure %d locked the account", i+1)
+		}
+		if remaining := lockoutRemaining(attempt, now.Add(time.Duration(i)*time.Minute)); remaining != 0 {
+			t.Fatalf("failure %d: locked for %s, want unlocked", i+1, remaining)
+		}
+	}
+
+	third := now.Add(2 * time.Minute)
+	if lockedFor := l.applyFailure(attempt, third); lockedFor != 10*time.Minute {
+		t.Fatalf("third failure lockedFor = %s, want 10m", lockedFor)
+	}
+	if remaining := lockoutRemaining(attempt, third.Add(time.Minute)); remaining != 9*time.Minute {
+		t.Errorf("remaining = %s, want 9m", remaining)
+	}
+}
+
+func TestLoginLockoutAutoUnlocks(t *testing.T) {
+	l := NewLoginLockout(nil, 2, 15*time.Minute, 10*time.Minute)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	attempt := &models.LoginAttempt{Key: "user:1"}
+
+	l.applyFailure(attempt, now)
+	l.applyFailure(attempt, now)
+
+	unlocked := now.Add(10 * time.Minute)
+	if remaining := lockoutRemaining(attempt, unlocked); remaining != 0 {
+		t.Fatalf("still locked for %s after the lockout duration", remaining)
+	}
+
+	// A single failure after unlocking starts a fresh count instead of relocking
+	if lockedFor := l.applyFailure(attempt, unlocked.Add(time.Second)); lockedFor != 0 {
+		t.Errorf("first failure after unlock relocked the account")
+	}
+}
+
+func TestLoginLockoutWindowExpires(t *testing.T) {
+	l := NewLoginLockout(nil, 2, 15*time.Minute, 10*time.Minute)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	attempt := &models.LoginAttempt{Key: "user:1"}
+
+	l.applyFailure(attempt, now)
+	// The second failure falls outside the window, so it starts over
+	if lockedFor := l.applyFailure(attempt, now.Add(16*time.Minute)); lockedFor != 0 {
+		t.Errorf("failures in separate windows locked the account")
+	}
+	if attempt.FailedCount != 1 {
+		t.Errorf("FailedCount = %d, want 1", attempt.FailedCount)
+	}
+}
+
+func TestRecordFailureIsOneUpsert(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	var statements []string
+	err = db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		statements = append(statements, tx.Statement.SQL.String())
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	NewLoginLockout(db, 3, 15*time.Minute, 10*time.Minute).RecordFailure("user:1")
+
+	// A locking read finds no row to lock for an account's first failures,
+	// so they must be counted by the insert itself
+	if len(statements) != 1 {
+		t.Fatalf("ran %d statements, want one upsert: %q", len(statements), statements)
+	}
+	sql := statements[0]
+	if !strings.Contains(sql, "INSERT INTO login_attempts") || !strings.Contains(sql, "ON CONFLICT (key) DO UPDATE") || strings.Contains(sql, "FOR UPDATE") {
+		t.Errorf("statement = %q, want an INSERT ... ON CONFLICT (key) DO UPDATE", sql)
+	}
+}
+
+// TestRecordFailureCountsConcurrentFailures needs a Postgres database in
+// TEST_DATABASE_URL, where it creates the login_attempts table if missing
+func TestRecordFailureCountsConcurrentFailures(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	if err := db.AutoMigrate(&models.LoginAttempt{}); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+
+	const failures = 20
+	l := NewLoginLockout(db, failures, 15*time.Minute, 10*time.Minute)
+	key := fmt.Sprintf("test:concurrent:%d", time.Now().UnixNano())
+	defer l.Reset(key)
+
+	var wg sync.WaitGroup
+	locks := make(chan time.Duration, failures)
+	for i := 0; i < failures; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lockedFor, err := l.RecordFailure(key)
+			if err != nil {
+				t.Errorf("RecordFailure: %v", err)
+			}
+			locks <- lockedFor
+		}()
+	}
+	wg.Wait()
+	close(locks)
+
+	// Every failure counts, so exactly the last one reaches the threshold
+	locked := 0
+	for lockedFor := range locks {
+		if lockedFor > 0 {
+			locked++
+		}
+	}
+	if locked != 1 {
+		t.Errorf("%d failures locked the account, want exactly one", locked)
+	}
+	if _, ok := l.Locked(key); !ok {
+		t.Error("account not locked after reaching the threshold")
+	}
+}
+
+func TestLoginLockoutKeyHidesUnknownUsers(t *testing.T) {
+	if got := loginLockoutKey(&models.User{ID: 7}, "alice@example.com"); got != "user:7" {
+		t.Errorf("key for existing user = %q, want user:7", got)
+	}
+	if a, b := loginLockoutKey(nil, " Ghost "), loginLockoutKey(nil, "ghost"); a != b {
+		t.Errorf("unknown identifiers should normalise to one key, got %q and %q", a, b)
+	}
+}
+
+func TestWriteLoginLocked(t *testing.T) {
+	rec := httptest.NewRecorder()
+	writeLoginLocked(rec, 90*time.Second+time.Millisecond)
+
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", rec.Code)
+	}
+	if got := rec.Header().Get("Retry-After"); got != "91" {
+		t.Errorf("Retry-After = %q, want 91", got)
+	}
+}
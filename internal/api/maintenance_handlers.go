@@ -0,0 +1,273 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+)
+
+// maxMaintenanceTitleLength caps the length of a maintenance window title
+const maxMaintenanceTitleLength = 100
+
+// maintenanceWindowRequest is the body of the create and update endpoints
+type maintenanceWindowRequest struct {
+	Title    string    `json:"title"`
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	Weekly   bool      `json:"weekly"`
+	Timezone string    `json:"timezone"`
+}
+
+// validate checks a window's title and times. A one-off window must not be
+// over already, and a weekly one must be shorter than a week so occurrences
+// don't overlap.
+func (req *maintenanceWindowRequest) validate(now time.Time) error {
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" {
+		return fmt.Errorf("title is required")
+	}
+	if len(req.Title) > maxMaintenanceTitleLength {
+		return fmt.Errorf("title must be at most %d characters", maxMaintenanceTitleLength)
+	}
+	if req.StartsAt.IsZero() || req.EndsAt.IsZero() {
+		return fmt.Errorf("starts_at and ends_at are required")
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		return fmt.Errorf("ends_at must be after starts_at")
+	}
+	if req.Weekly && req.EndsAt.Sub(req.StartsAt) >= 7*24*time.Hour {
+		return fmt.Errorf("a weekly window must be shorter than a week")
+	}
+	if !req.Weekly && !req.EndsAt.After(now) {
+		return fmt.Errorf("ends_at must be in the future")
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "Local" {
+			return fmt.Errorf("timezone must be an IANA time zone, e.g. Europe/Rome")
+		}
+	}
+	return nil
+}
+
+// decodeMaintenanceWindow reads and validates a window request, defaulting
+// its time zone to the user's. It writes the error response and returns
+// false when the request is rejected.
+func decodeMaintenanceWindow(db *gorm.DB, w http.ResponseWriter, r *http.Request, user *models.User) (*maintenanceWindowRequest, bool) {
+	var req maintenanceWindowRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return nil, false
+	}
+	if err := req.validate(time.Now()); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return nil, false
+	}
+	if req.Timezone == "" {
+		req.Timezone = userTimezone(db, user.ID).String()
+	}
+	return &req, true
+}
+
+// ownedMonitorID reads the monitor ID from the URL and checks the user owns
+// the monitor. It writes the error response and returns false otherwise.
+func ownedMonitorID(db *gorm.DB, w http.ResponseWriter, r *http.Request, user *models.User) (int, bool) {
+	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	if err != nil {
+		http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+		return 0, false
+	}
+
+	var count int64
+	if err := db.Model(&models.Monitor{}).
+		Where("id = ? AND user_id = ?", id, user.ID).
+		Count(&count).Error; err != nil {
+		http.Error(w, "Failed to fetch monitor", http.StatusInternalServerError)
+		return 0, false
+	}
+	if count == 0 {
+		http.Error(w, "Monitor not found", http.StatusNotFound)
+		return 0, false
+	}
+	return id, true
+}
+
+// refreshMaintenance hands the executor a monitor's current windows
+func refreshMaintenance(db *gorm.DB, executor *monitor.Executor, monitorID int) error {
+	if executor == nil {
+		return nil
+	}
+	var windows []models.MaintenanceWindow
+	if err := db.Where("monitor_id = ?", monitorID).Find(&windows).Error; err != nil {
+		return err
+	}
+	executor.SetMaintenanceWindows(monitorID, windows)
+	return nil
+}
+
+// HandleGetMaintenanceWindows lists a monitor's maintenance windows by start
+func HandleGetMaintenanceWindows(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		monitorID, ok := ownedMonitorID(db, w, r, user)
+		if !ok {
+			return
+		}
+
+		windows := []models.MaintenanceWindow{}
+		if err := db.Where("monitor_id = ?", monitorID).Order("starts_at").Find(&windows).Error; err != nil {
+			http.Error(w, "Failed to fetch maintenance windows", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(windows)
+	}
+}
+
+// HandleCreateMaintenanceWindow schedules maintenance of a monitor
+func HandleCreateMaintenanceWindow(db *gorm.DB, executor *monitor.Executor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		monitorID, ok := ownedMonitorID(db, w, r, user)
+		if !ok {
+			return
+		}
+		req, ok := decodeMaintenanceWindow(db, w, r, user)
+		if !ok {
+			return
+		}
+
+		window := models.MaintenanceWindow{
+			MonitorID: monitorID,
+			Title:     req.Title,
+			StartsAt:  req.StartsAt,
+			EndsAt:    req.EndsAt,
+			Weekly:    req.Weekly,
+			Timezone:  req.Timezone,
+			CreatedAt: time.Now(),
+		}
+		if err := db.Create(&window).Error; err != nil {
+			http.Error(w, "Failed to create maintenance window", http.StatusInternalServerError)
+			return
+		}
+		if err := refreshMaintenance(db, executor, monitorID); err != nil {
+			http.Error(w, "Failed to apply maintenance window", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(window)
+	}
+}
+
+// HandleUpdateMaintenanceWindow replaces a maintenance window's title and times
+func HandleUpdateMaintenanceWindow(db *gorm.DB, executor *monitor.Executor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		monitorID, ok := ownedMonitorID(db, w, r, user)
+		if !ok {
+			return
+		}
+		windowID, err := strconv.Atoi(chi.URLParam(r, "windowId"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+		req, ok := decodeMaintenanceWindow(db, w, r, user)
+		if !ok {
+			return
+		}
+
+		var window models.MaintenanceWindow
+		if err := db.Where("id = ? AND monitor_id = ?", windowID, monitorID).First(&window).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Maintenance window not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch maintenance window", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		window.Title = req.Title
+		window.StartsAt = req.StartsAt
+		window.EndsAt = req.EndsAt
+		window.Weekly = req.Weekly
+		window.Timezone = req.Timezone
+		if err := db.Save(&window).Error; err != nil {
+			http.Error(w, "Failed to update maintenance window", http.StatusInternalServerError)
+			return
+		}
+		if err := refreshMaintenance(db, executor, monitorID); err != nil {
+			http.Error(w, "Failed to apply maintenance window", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(window)
+	}
+}
+
+// HandleDeleteMaintenanceWindow deletes a maintenance window, ending it
+// right away when it is under way
+func HandleDeleteMaintenanceWindow(db *gorm.DB, executor *monitor.Executor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		monitorID, ok := ownedMonitorID(db, w, r, user)
+		if !ok {
+			return
+		}
+		windowID, err := strconv.Atoi(chi.URLParam(r, "windowId"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+
+		result := db.Where("id = ? AND monitor_id = ?", windowID, monitorID).Delete(&models.MaintenanceWindow{})
+		if result.Error != nil {
+			http.Error(w, "Failed to delete maintenance window", http.StatusInternalServerError)
+			return
+		}
+		if result.RowsAffected == 0 {
+			http.Error(w, "Maintenance window not found", http.StatusNotFound)
+			return
+		}
+		if err := refreshMaintenance(db, executor, monitorID); err != nil {
+			http.Error(w, "Failed to apply maintenance window", http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// publicMaintenance is a monitor's maintenance under way, on status pages
+type publicMaintenance struct {
+	Title  string    `json:"title"`
+	EndsAt time.Time `json:"ends_at"`
+}
+
+// activeMaintenanceByMonitor returns the maintenance under way at now for
+// each monitor with some. When windows overlap, the one ending last is shown.
+func activeMaintenanceByMonitor(windows []models.MaintenanceWindow, now time.Time) map[int]publicMaintenance {
+	active := make(map[int]publicMaintenance)
+	for _, window := range windows {
+		_, end, ok := window.Occurrence(now)
+		if !ok {
+			continue
+		}
+		if current, exists := active[window.MonitorID]; !exists || end.After(current.EndsAt) {
+			active[window.MonitorID] = publicMaintenance{Title: window.Title, EndsAt: end}
+		}
+	}
+	return active
+}
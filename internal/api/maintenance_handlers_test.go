@@ -0,0 +1,65 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestMaintenanceWindowRequestValidate(t *testing.T) {
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+	hour := time.Hour
+
+	req := maintenanceWindowRequest{Title: "  Kernel upgrade ", StartsAt: now.Add(hour), EndsAt: now.Add(3 * hour)}
+	if err := req.validate(now); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if req.Title != "Kernel upgrade" {
+		t.Errorf("title = %q, want it trimmed", req.Title)
+	}
+
+	valid := []maintenanceWindowRequest{
+		{Title: "under way", StartsAt: now.Add(-hour), EndsAt: now.Add(hour)},
+		{Title: "weekly from the past", StartsAt: now.Add(-30 * 24 * hour), EndsAt: now.Add(-30*24*hour + 2*hour), Weekly: true},
+		{Title: "in a zone", StartsAt: now, EndsAt: now.Add(hour), Weekly: true, Timezone: "UTC"},
+	}
+	for _, req := range valid {
+		if err := req.validate(now); err != nil {
+			t.Errorf("validate(%q): %v", req.Title, err)
+		}
+	}
+
+	invalid := []maintenanceWindowRequest{
+		{Title: "", StartsAt: now, EndsAt: now.Add(hour)},
+		{Title: string(make([]byte, maxMaintenanceTitleLength+1)), StartsAt: now, EndsAt: now.Add(hour)},
+		{Title: "no times"},
+		{Title: "backwards", StartsAt: now.Add(2 * hour), EndsAt: now.Add(hour)},
+		{Title: "over", StartsAt: now.Add(-2 * hour), EndsAt: now.Add(-hour)},
+		{Title: "a week long", StartsAt: now, EndsAt: now.Add(7 * 24 * hour), Weekly: true},
+		{Title: "unknown zone", StartsAt: now, EndsAt: now.Add(hour), Timezone: "Mars/Olympus"},
+	}
+	for _, req := range invalid {
+		if err := req.validate(now); err == nil {
+			t.Errorf("validate(%q) succeeded, want error", req.Title)
+		}
+	}
+}
+
+func TestActiveMaintenanceByMonitor(t *testing.T) {
+	now := time.Date(2026, 5, 4, 12, 0, 0, 0, time.UTC)
+	windows := []models.MaintenanceWindow{
+		{MonitorID: 1, Title: "short", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+		{MonitorID: 1, Title: "long", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(4 * time.Hour)},
+		{MonitorID: 2, Title: "later", StartsAt: now.Add(time.Hour), EndsAt: now.Add(2 * time.Hour)},
+		{MonitorID: 3, Title: "weekly", StartsAt: now.Add(-7*24*time.Hour - time.Hour), EndsAt: now.Add(-7 * 24 * time.Hour), Weekly: true},
+	}
+
+	active := activeMaintenanceByMonitor(windows, now)
+	if len(active) != 1 {
+		t.Fatalf("active = %v, want only monitor 1", active)
+	}
+	if m := active[1]; m.Title != "long" || !m.EndsAt.Equal(now.Add(4*time.Hour)) {
+		t.Errorf("monitor 1 = %+v, want the window ending last", m)
+	}
+}
@@ -1,8 +1,12 @@
 package api
 
 import (
+	"crypto/subtle"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -10,7 +14,9 @@ import (
 
 	"github.com/fuomag9/uptime-kabomba/internal/config"
 	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
 	"github.com/fuomag9/uptime-kabomba/internal/uptime"
+	"github.com/fuomag9/uptime-kabomba/internal/websocket"
 )
 
 // escapePrometheusLabel escapes special characters in Prometheus label values
@@ -22,109 +28,191 @@ func escapePrometheusLabel(s string) string {
 	return s
 }
 
-// HandlePrometheusMetrics exports metrics in Prometheus format
-func HandlePrometheusMetrics(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
+// metricsAuthorized reports whether the request carries the metrics token,
+// either as X-Metrics-Token or as a bearer token, the way Prometheus sends
+// its authorization credentials. An empty token authorizes nothing.
+func metricsAuthorized(r *http.Request, token string) bool {
+	if token == "" {
+		return false
+	}
+	given := r.Header.Get("X-Metrics-Token")
+	if bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && given == "" {
+		given = strings.TrimSpace(bearer)
+	}
+	return subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1
+}
+
+// metricsFilter scopes the per-monitor metrics to one user or monitor type
+type metricsFilter struct {
+	userID      int // 0: every user
+	monitorType string
+}
+
+// parseMetricsFilter reads the user_id and monitor_type query parameters
+func parseMetricsFilter(query url.Values) (metricsFilter, error) {
+	var filter metricsFilter
+	if raw := query.Get("user_id"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id < 1 {
+			return filter, fmt.Errorf("user_id must be a positive integer")
+		}
+		filter.userID = id
+	}
+	filter.monitorType = strings.TrimSpace(query.Get("monitor_type"))
+	return filter, nil
+}
+
+// apply restricts a monitor query to the filter
+func (f metricsFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.userID != 0 {
+		query = query.Where("user_id = ?", f.userID)
+	}
+	if f.monitorType != "" {
+		query = query.Where("type = ?", f.monitorType)
+	}
+	return query
+}
+
+// metricsScope is the API key scope that scrapes the key owner's metrics
+const metricsScope = "metrics"
+
+// metricsMonitor is a monitor as the metrics endpoints export it
+type metricsMonitor struct {
+	ID     int    `gorm:"column:id"`
+	Name   string `gorm:"column:name"`
+	Type   string `gorm:"column:type"`
+	URL    string `gorm:"column:url"`
+	Active bool   `gorm:"column:active"`
+	UserID int    `gorm:"column:user_id"`
+}
+
+// loadMetricsMonitors returns the monitors matching filter
+func loadMetricsMonitors(db *gorm.DB, filter metricsFilter) ([]metricsMonitor, error) {
+	var monitors []metricsMonitor
+	err := filter.apply(db.Model(&models.Monitor{})).
+		Select("id, name, type, url, active, user_id").
+		Find(&monitors).Error
+	return monitors, err
+}
+
+// writeMonitorMetrics writes the status, ping, uptime and active metrics of
+// each monitor
+func writeMonitorMetrics(w io.Writer, db *gorm.DB, monitors []metricsMonitor) {
+	calculator := uptime.NewCalculator(db)
+
+	// Write metrics header
+	fmt.Fprintln(w, "# HELP uptime_monitor_up Monitor status (1 = up, 0 = down)")
+	fmt.Fprintln(w, "# TYPE uptime_monitor_up gauge")
+
+	fmt.Fprintln(w, "# HELP uptime_monitor_ping_ms Monitor response time in milliseconds")
+	fmt.Fprintln(w, "# TYPE uptime_monitor_ping_ms gauge")
+
+	fmt.Fprintln(w, "# HELP uptime_monitor_uptime_percentage Monitor uptime percentage (24h)")
+	fmt.Fprintln(w, "# TYPE uptime_monitor_uptime_percentage gauge")
+
+	fmt.Fprintln(w, "# HELP uptime_monitor_total_checks Total number of checks (24h)")
+	fmt.Fprintln(w, "# TYPE uptime_monitor_total_checks counter")
+
+	fmt.Fprintln(w, "# HELP uptime_monitor_active Monitor active status")
+	fmt.Fprintln(w, "# TYPE uptime_monitor_active gauge")
+
+	// Write metrics for each monitor
+	for _, monitor := range monitors {
+		labels := fmt.Sprintf(`monitor_id="%d",monitor_name="%s",monitor_type="%s",user_id="%d"`,
+			monitor.ID, escapePrometheusLabel(monitor.Name), escapePrometheusLabel(monitor.Type), monitor.UserID)
+
+		// Get latest heartbeat
+		var heartbeat models.Heartbeat
+		err := db.Where("monitor_id = ?", monitor.ID).
+			Order("time DESC").
+			Limit(1).
+			First(&heartbeat).Error
+		if err == nil {
+			// Monitor status
+			status := 0
+			if heartbeat.Status == 1 {
+				status = 1
+			}
+			fmt.Fprintf(w, "uptime_monitor_up{%s} %d\n", labels, status)
+
+			// Monitor ping
+			fmt.Fprintf(w, "uptime_monitor_ping_ms{%s} %d\n", labels, heartbeat.Ping)
+		} else {
+			// No heartbeat data
+			fmt.Fprintf(w, "uptime_monitor_up{%s} 0\n", labels)
+			fmt.Fprintf(w, "uptime_monitor_ping_ms{%s} 0\n", labels)
+		}
+
+		// Get 24h uptime stats
+		stats, err := calculator.Calculate24HourUptime(monitor.ID)
+		if err == nil {
+			fmt.Fprintf(w, "uptime_monitor_uptime_percentage{%s} %.2f\n", labels, stats.UptimePercentage)
+			fmt.Fprintf(w, "uptime_monitor_total_checks{%s} %d\n", labels, stats.TotalChecks)
+		}
+
+		// Monitor active status
+		activeValue := 0
+		if monitor.Active {
+			activeValue = 1
+		}
+		fmt.Fprintf(w, "uptime_monitor_active{%s} %d\n", labels, activeValue)
+	}
+}
+
+// writeMonitorCounts writes how many monitors there are and how many are active
+func writeMonitorCounts(w io.Writer, monitors []metricsMonitor) {
+	fmt.Fprintln(w, "# HELP uptime_system_total_monitors Total number of monitors")
+	fmt.Fprintln(w, "# TYPE uptime_system_total_monitors gauge")
+	fmt.Fprintf(w, "uptime_system_total_monitors %d\n", len(monitors))
+
+	// Count active monitors
+	activeCount := 0
+	for _, m := range monitors {
+		if m.Active {
+			activeCount++
+		}
+	}
+	fmt.Fprintln(w, "# HELP uptime_system_active_monitors Number of active monitors")
+	fmt.Fprintln(w, "# TYPE uptime_system_active_monitors gauge")
+	fmt.Fprintf(w, "uptime_system_active_monitors %d\n", activeCount)
+}
+
+// writeScrapeTimestamp writes when the scrape happened
+func writeScrapeTimestamp(w io.Writer) {
+	fmt.Fprintln(w, "# HELP uptime_system_scrape_timestamp_seconds Unix timestamp of this scrape")
+	fmt.Fprintln(w, "# TYPE uptime_system_scrape_timestamp_seconds gauge")
+	fmt.Fprintf(w, "uptime_system_scrape_timestamp_seconds %d\n", time.Now().Unix())
+}
+
+// HandlePrometheusMetrics exports metrics in Prometheus format. The
+// user_id and monitor_type query parameters limit the per-monitor metrics
+// and monitor counts; the other system metrics stay server-wide.
+func HandlePrometheusMetrics(db *gorm.DB, cfg *config.Config, hub *websocket.Hub) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.Header.Get("X-Metrics-Token") != cfg.MetricsToken {
+		if !metricsAuthorized(r, cfg.MetricsToken) {
 			http.Error(w, "Unauthorized", http.StatusUnauthorized)
 			return
 		}
 
-		// Set content type for Prometheus
-		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
-
-		// Get all monitors
-		var monitors []struct {
-			ID       int    `gorm:"column:id"`
-			Name     string `gorm:"column:name"`
-			Type     string `gorm:"column:type"`
-			URL      string `gorm:"column:url"`
-			Active   bool   `gorm:"column:active"`
-			UserID   int    `gorm:"column:user_id"`
+		filter, err := parseMetricsFilter(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
 		}
 
-		if err := db.Model(&models.Monitor{}).
-			Select("id, name, type, url, active, user_id").
-			Find(&monitors).Error; err != nil {
+		monitors, err := loadMetricsMonitors(db, filter)
+		if err != nil {
 			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
 			return
 		}
 
-		calculator := uptime.NewCalculator(db)
-
-		// Write metrics header
-		fmt.Fprintln(w, "# HELP uptime_monitor_up Monitor status (1 = up, 0 = down)")
-		fmt.Fprintln(w, "# TYPE uptime_monitor_up gauge")
-
-		fmt.Fprintln(w, "# HELP uptime_monitor_ping_ms Monitor response time in milliseconds")
-		fmt.Fprintln(w, "# TYPE uptime_monitor_ping_ms gauge")
-
-		fmt.Fprintln(w, "# HELP uptime_monitor_uptime_percentage Monitor uptime percentage (24h)")
-		fmt.Fprintln(w, "# TYPE uptime_monitor_uptime_percentage gauge")
-
-		fmt.Fprintln(w, "# HELP uptime_monitor_total_checks Total number of checks (24h)")
-		fmt.Fprintln(w, "# TYPE uptime_monitor_total_checks counter")
-
-		fmt.Fprintln(w, "# HELP uptime_monitor_active Monitor active status")
-		fmt.Fprintln(w, "# TYPE uptime_monitor_active gauge")
-
-		// Write metrics for each monitor
-		for _, monitor := range monitors {
-			labels := fmt.Sprintf(`monitor_id="%d",monitor_name="%s",monitor_type="%s",user_id="%d"`,
-				monitor.ID, escapePrometheusLabel(monitor.Name), escapePrometheusLabel(monitor.Type), monitor.UserID)
-
-			// Get latest heartbeat
-			var heartbeat models.Heartbeat
-			err := db.Where("monitor_id = ?", monitor.ID).
-				Order("time DESC").
-				Limit(1).
-				First(&heartbeat).Error
-			if err == nil {
-				// Monitor status
-				status := 0
-				if heartbeat.Status == 1 {
-					status = 1
-				}
-				fmt.Fprintf(w, "uptime_monitor_up{%s} %d\n", labels, status)
-
-				// Monitor ping
-				fmt.Fprintf(w, "uptime_monitor_ping_ms{%s} %d\n", labels, heartbeat.Ping)
-			} else {
-				// No heartbeat data
-				fmt.Fprintf(w, "uptime_monitor_up{%s} 0\n", labels)
-				fmt.Fprintf(w, "uptime_monitor_ping_ms{%s} 0\n", labels)
-			}
+		// Set content type for Prometheus
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
 
-			// Get 24h uptime stats
-			stats, err := calculator.Calculate24HourUptime(monitor.ID)
-			if err == nil {
-				fmt.Fprintf(w, "uptime_monitor_uptime_percentage{%s} %.2f\n", labels, stats.UptimePercentage)
-				fmt.Fprintf(w, "uptime_monitor_total_checks{%s} %d\n", labels, stats.TotalChecks)
-			}
-
-			// Monitor active status
-			activeValue := 0
-			if monitor.Active {
-				activeValue = 1
-			}
-			fmt.Fprintf(w, "uptime_monitor_active{%s} %d\n", labels, activeValue)
-		}
+		writeMonitorMetrics(w, db, monitors)
 
 		// System metrics
-		fmt.Fprintln(w, "# HELP uptime_system_total_monitors Total number of monitors")
-		fmt.Fprintln(w, "# TYPE uptime_system_total_monitors gauge")
-		fmt.Fprintf(w, "uptime_system_total_monitors %d\n", len(monitors))
-
-		// Count active monitors
-		activeCount := 0
-		for _, m := range monitors {
-			if m.Active {
-				activeCount++
-			}
-		}
-		fmt.Fprintln(w, "# HELP uptime_system_active_monitors Number of active monitors")
-		fmt.Fprintln(w, "# TYPE uptime_system_active_monitors gauge")
-		fmt.Fprintf(w, "uptime_system_active_monitors %d\n", activeCount)
+		writeMonitorCounts(w, monitors)
 
 		// Heartbeat count (total in database)
 		var totalHeartbeats int64
@@ -140,9 +228,84 @@ func HandlePrometheusMetrics(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
 		fmt.Fprintln(w, "# TYPE uptime_system_database_size_bytes gauge")
 		fmt.Fprintf(w, "uptime_system_database_size_bytes %d\n", dbSize)
 
-		// Timestamp
-		fmt.Fprintln(w, "# HELP uptime_system_scrape_timestamp_seconds Unix timestamp of this scrape")
-		fmt.Fprintln(w, "# TYPE uptime_system_scrape_timestamp_seconds gauge")
-		fmt.Fprintf(w, "uptime_system_scrape_timestamp_seconds %d\n", time.Now().Unix())
+		// WebSocket broadcasts dropped because the queue was full
+		fmt.Fprintln(w, "# HELP uptime_system_websocket_dropped_broadcasts_total WebSocket broadcasts dropped because the broadcast queue was full")
+		fmt.Fprintln(w, "# TYPE uptime_system_websocket_dropped_broadcasts_total counter")
+		fmt.Fprintf(w, "uptime_system_websocket_dropped_broadcasts_total %d\n", hub.DroppedBroadcasts())
+
+		// Notification sends per provider type
+		writeNotificationMetrics(w, notification.SendMetrics())
+
+		writeScrapeTimestamp(w)
+	}
+}
+
+// userMetricsFilter scopes a per-user scrape to userID. A user_id parameter
+// can't widen it: one naming another user is an error.
+func userMetricsFilter(userID int, query url.Values) (metricsFilter, error) {
+	filter, err := parseMetricsFilter(query)
+	if err != nil {
+		return filter, err
+	}
+	if filter.userID != 0 && filter.userID != userID {
+		return filter, fmt.Errorf("user_id must be the API key owner's")
+	}
+	filter.userID = userID
+	return filter, nil
+}
+
+// HandleUserPrometheusMetrics exports the metrics of the API key owner's
+// monitors in Prometheus format, so each tenant can scrape only their own.
+// Requires an API key with the metrics scope; monitor_type filters as on
+// /metrics. Server-wide metrics are left out.
+func HandleUserPrometheusMetrics(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		key := r.Context().Value(apiKeyContextKey).(*models.APIKey)
+
+		if !key.HasScope(metricsScope) {
+			http.Error(w, "API key lacks the metrics scope", http.StatusForbidden)
+			return
+		}
+
+		filter, err := userMetricsFilter(user.ID, r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		monitors, err := loadMetricsMonitors(db, filter)
+		if err != nil {
+			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		writeMonitorMetrics(w, db, monitors)
+		writeMonitorCounts(w, monitors)
+		writeScrapeTimestamp(w)
+	}
+}
+
+// writeNotificationMetrics writes the send duration histogram and failure
+// count of each notification provider type
+func writeNotificationMetrics(w io.Writer, metrics []notification.ProviderSendMetrics) {
+	fmt.Fprintln(w, "# HELP uptime_notification_send_duration_seconds Time taken to send notifications, by provider type")
+	fmt.Fprintln(w, "# TYPE uptime_notification_send_duration_seconds histogram")
+	for _, m := range metrics {
+		provider := escapePrometheusLabel(m.Provider)
+		for i, bound := range notification.SendDurationBuckets {
+			fmt.Fprintf(w, "uptime_notification_send_duration_seconds_bucket{provider=\"%s\",le=\"%s\"} %d\n",
+				provider, strconv.FormatFloat(bound, 'f', -1, 64), m.Buckets[i])
+		}
+		fmt.Fprintf(w, "uptime_notification_send_duration_seconds_bucket{provider=\"%s\",le=\"+Inf\"} %d\n", provider, m.Count)
+		fmt.Fprintf(w, "uptime_notification_send_duration_seconds_sum{provider=\"%s\"} %g\n", provider, m.Sum)
+		fmt.Fprintf(w, "uptime_notification_send_duration_seconds_count{provider=\"%s\"} %d\n", provider, m.Count)
+	}
+
+	fmt.Fprintln(w, "# HELP uptime_notification_send_failures_total Notification sends that failed, by provider type")
+	fmt.Fprintln(w, "# TYPE uptime_notification_send_failures_total counter")
+	for _, m := range metrics {
+		fmt.Fprintf(w, "uptime_notification_send_failures_total{provider=\"%s\"} %d\n", escapePrometheusLabel(m.Provider), m.Failures)
 	}
 }
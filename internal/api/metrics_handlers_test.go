@@ -0,0 +1,183 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/config"
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
+)
+
+func TestPrometheusMetricsDeniesUnauthenticated(t *testing.T) {
+	cfg := &config.Config{MetricsToken: "scrape-secret"}
+	handler := HandlePrometheusMetrics(nil, cfg, nil)
+
+	tests := []struct {
+		name   string
+		header string
+		value  string
+	}{
+		{name: "no token"},
+		{name: "wrong token", header: "X-Metrics-Token", value: "guess"},
+		{name: "wrong bearer", header: "Authorization", value: "Bearer guess"},
+		{name: "token without bearer scheme", header: "Authorization", value: "scrape-secret"},
+		{name: "filter without token", header: "X-Other", value: "scrape-secret"},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/metrics?user_id=1", nil)
+		if tt.header != "" {
+			req.Header.Set(tt.header, tt.value)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		if rec.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status %d, want 401", tt.name, rec.Code)
+		}
+		if strings.Contains(rec.Body.String(), "uptime_monitor") {
+			t.Errorf("%s: metrics leaked to an unauthenticated request", tt.name)
+		}
+	}
+}
+
+func TestMetricsAuthorized(t *testing.T) {
+	for _, tt := range []struct {
+		header, value string
+		want          bool
+	}{
+		{header: "X-Metrics-Token", value: "scrape-secret", want: true},
+		{header: "Authorization", value: "Bearer scrape-secret", want: true},
+		{header: "Authorization", value: "Bearer scrape", want: false},
+		{header: "Authorization", value: "Basic scrape-secret", want: false},
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.Header.Set(tt.header, tt.value)
+		if got := metricsAuthorized(req, "scrape-secret"); got != tt.want {
+			t.Errorf("%s: %q authorized = %v, want %v", tt.header, tt.value, got, tt.want)
+		}
+	}
+
+	// An unset token must not match an empty header
+	if metricsAuthorized(httptest.NewRequest(http.MethodGet, "/metrics", nil), "") {
+		t.Error("empty token authorized a request without credentials")
+	}
+}
+
+func TestParseMetricsFilter(t *testing.T) {
+	filter, err := parseMetricsFilter(url.Values{"user_id": {"7"}, "monitor_type": {"http"}})
+	if err != nil || filter.userID != 7 || filter.monitorType != "http" {
+		t.Errorf("got %+v, %v; want user 7 and type http", filter, err)
+	}
+
+	filter, err = parseMetricsFilter(url.Values{})
+	if err != nil || filter != (metricsFilter{}) {
+		t.Errorf("got %+v, %v; want no filter", filter, err)
+	}
+
+	for _, bad := range []string{"0", "-3", "abc"} {
+		if _, err := parseMetricsFilter(url.Values{"user_id": {bad}}); err == nil {
+			t.Errorf("user_id=%q accepted", bad)
+		}
+	}
+}
+
+func TestWriteNotificationMetrics(t *testing.T) {
+	buckets := make([]uint64, len(notification.SendDurationBuckets))
+	for i := range buckets {
+		buckets[i] = 3
+	}
+	buckets[0] = 1
+
+	var out strings.Builder
+	writeNotificationMetrics(&out, []notification.ProviderSendMetrics{
+		{Provider: "slack", Buckets: buckets, Count: 4, Sum: 2.5, Failures: 1},
+	})
+	text := out.String()
+
+	for _, line := range []string{
+		"# TYPE uptime_notification_send_duration_seconds histogram",
+		`uptime_notification_send_duration_seconds_bucket{provider="slack",le="0.1"} 1`,
+		`uptime_notification_send_duration_seconds_bucket{provider="slack",le="2.5"} 3`,
+		`uptime_notification_send_duration_seconds_bucket{provider="slack",le="+Inf"} 4`,
+		`uptime_notification_send_duration_seconds_sum{provider="slack"} 2.5`,
+		`uptime_notification_send_duration_seconds_count{provider="slack"} 4`,
+		"# TYPE uptime_notification_send_failures_total counter",
+		`uptime_notification_send_failures_total{provider="slack"} 1`,
+	} {
+		if !strings.Contains(text, line+"\n") {
+			t.Errorf("metrics missing %q:\n%s", line, text)
+		}
+	}
+}
+
+func TestUserMetricsFilter(t *testing.T) {
+	filter, err := userMetricsFilter(7, url.Values{"monitor_type": {"http"}})
+	if err != nil || filter.userID != 7 || filter.monitorType != "http" {
+		t.Errorf("got %+v, %v; want user 7 and type http", filter, err)
+	}
+
+	// Naming yourself is allowed, anyone else is not
+	if filter, err := userMetricsFilter(7, url.Values{"user_id": {"7"}}); err != nil || filter.userID != 7 {
+		t.Errorf("own user_id: got %+v, %v", filter, err)
+	}
+	if _, err := userMetricsFilter(7, url.Values{"user_id": {"8"}}); err == nil {
+		t.Error("another user's user_id accepted")
+	}
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	var monitors []metricsMonitor
+	stmt := filter.apply(db.Model(&models.Monitor{})).Find(&monitors).Statement
+	if got := stmt.SQL.String(); !strings.Contains(got, "WHERE user_id = $1") || stmt.Vars[0] != 7 {
+		t.Errorf("SQL = %s %v, want it scoped to user 7", got, stmt.Vars)
+	}
+}
+
+func TestUserPrometheusMetrics(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+	handler := HandleUserPrometheusMetrics(db)
+
+	scrape := func(scopes []string, target string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		ctx := setUserContext(req.Context(), &models.User{ID: 7})
+		ctx = context.WithValue(ctx, apiKeyContextKey, &models.APIKey{UserID: 7, Scopes: scopes})
+		rec := httptest.NewRecorder()
+		handler(rec, req.WithContext(ctx))
+		return rec
+	}
+
+	if rec := scrape([]string{"read"}, "/users/metrics"); rec.Code != http.StatusForbidden {
+		t.Errorf("key without metrics scope: status %d, want 403", rec.Code)
+	}
+	if rec := scrape([]string{metricsScope}, "/users/metrics?user_id=8"); rec.Code != http.StatusBadRequest {
+		t.Errorf("another user's user_id: status %d, want 400", rec.Code)
+	}
+
+	rec := scrape([]string{metricsScope}, "/users/metrics")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body.String())
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "uptime_system_total_monitors 0\n") {
+		t.Errorf("monitor count missing:\n%s", body)
+	}
+	// Server-wide metrics would tell tenants about each other
+	for _, metric := range []string{"uptime_system_total_heartbeats", "uptime_system_database_size_bytes", "uptime_notification_send_failures_total"} {
+		if strings.Contains(body, metric) {
+			t.Errorf("per-user scrape exports server-wide %s", metric)
+		}
+	}
+}
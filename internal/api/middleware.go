@@ -1,7 +1,10 @@
 package api
 
 import (
+	"context"
+	"net"
 	"net/http"
+	"net/netip"
 	"sync"
 	"time"
 
@@ -41,12 +44,43 @@ func SecurityHeadersMiddleware(cfg *config.Config) func(http.Handler) http.Handl
 	}
 }
 
+const connectingIPContextKey contextKey = "connecting_ip"
+
+// ConnectingIPMiddleware records the TCP peer address before RealIP rewrites
+// RemoteAddr from client-controlled headers. It must run before RealIP.
+func ConnectingIPMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := context.WithValue(r.Context(), connectingIPContextKey, r.RemoteAddr)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// connectingIP returns the real peer IP of the request, ignoring forwarding headers
+func connectingIP(r *http.Request) (netip.Addr, bool) {
+	addr, ok := r.Context().Value(connectingIPContextKey).(string)
+	if !ok {
+		addr = r.RemoteAddr
+	}
+
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+
+	ip, err := netip.ParseAddr(host)
+	if err != nil {
+		return netip.Addr{}, false
+	}
+	return ip.Unmap(), true
+}
+
 // RateLimiter stores rate limiters per identifier (IP or user)
 type RateLimiter struct {
 	limiters map[string]*rate.Limiter
 	mu       sync.RWMutex
 	rate     rate.Limit
 	burst    int
+	exempt   []netip.Prefix
 }
 
 // NewRateLimiter creates a new rate limiter
@@ -58,6 +92,36 @@ func NewRateLimiter(r rate.Limit, b int) *RateLimiter {
 	}
 }
 
+// SetExemptions sets the trusted networks that bypass this limiter
+func (rl *RateLimiter) SetExemptions(prefixes []netip.Prefix) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.exempt = prefixes
+}
+
+// IsExempt reports whether the request comes from a trusted network.
+// Only the connecting IP is considered so forwarding headers can't be spoofed
+// to skip rate limiting.
+func (rl *RateLimiter) IsExempt(r *http.Request) bool {
+	rl.mu.RLock()
+	defer rl.mu.RUnlock()
+
+	if len(rl.exempt) == 0 {
+		return false
+	}
+
+	ip, ok := connectingIP(r)
+	if !ok {
+		return false
+	}
+	for _, prefix := range rl.exempt {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetLimiter returns a rate limiter for the given identifier
 func (rl *RateLimiter) GetLimiter(identifier string) *rate.Limiter {
 	rl.mu.Lock()
@@ -91,6 +155,12 @@ func (rl *RateLimiter) CleanupOldLimiters() {
 func RateLimitMiddleware(limiter *RateLimiter) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Trusted networks skip the limiter entirely
+			if limiter.IsExempt(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
 			// Use IP address as identifier
 			identifier := r.RemoteAddr
 
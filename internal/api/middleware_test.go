@@ -0,0 +1,55 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestRateLimitExemptions(t *testing.T) {
+	limiter := NewRateLimiter(0, 1) // one request, never refilled
+	limiter.SetExemptions([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")})
+
+	handler := ConnectingIPMiddleware(middleware.RealIP(RateLimitMiddleware(limiter)(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}),
+	)))
+
+	send := func(remoteAddr, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = remoteAddr
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// Allowlisted peer is never limited
+	for i := 0; i < 5; i++ {
+		if code := send("10.1.2.3:4000", ""); code != http.StatusOK {
+			t.Fatalf("allowlisted request %d: status %d, want 200", i, code)
+		}
+	}
+
+	// Other peers are limited after their burst
+	if code := send("203.0.113.7:4000", ""); code != http.StatusOK {
+		t.Fatalf("first request: status %d, want 200", code)
+	}
+	if code := send("203.0.113.7:4000", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("second request: status %d, want 429", code)
+	}
+
+	// A spoofed forwarding header doesn't grant the exemption
+	if code := send("198.51.100.9:4000", "10.1.2.3"); code != http.StatusOK {
+		t.Fatalf("spoofed first request: status %d, want 200", code)
+	}
+	if code := send("198.51.100.9:4000", "10.1.2.3"); code != http.StatusTooManyRequests {
+		t.Fatalf("spoofed second request: status %d, want 429", code)
+	}
+}
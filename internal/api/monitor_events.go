@@ -0,0 +1,146 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// maxMonitorEvents bounds the number of transitions returned for one period
+const maxMonitorEvents = 5000
+
+// monitorEventPeriods maps the accepted ?period= values to their length
+var monitorEventPeriods = map[string]time.Duration{
+	"1h":  time.Hour,
+	"3h":  3 * time.Hour,
+	"6h":  6 * time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// monitorEventsQuery returns the heartbeats in [from, to] whose status differs
+// from the heartbeat before them. The last heartbeat before the period is
+// included as a seed so the first heartbeat of the period is compared against
+// it rather than counted as a change.
+const monitorEventsQuery = `
+WITH seed AS (
+	SELECT id, status, ping, message, time
+	FROM heartbeats
+	WHERE monitor_id = ? AND time < ?
+	ORDER BY time DESC
+	LIMIT 1
+), windowed AS (
+	SELECT id, status, ping, message, time FROM seed
+	UNION ALL
+	SELECT id, status, ping, message, time
+	FROM heartbeats
+	WHERE monitor_id = ? AND time >= ? AND time <= ?
+), marked AS (
+	SELECT id, status, ping, message, time,
+		LAG(status) OVER (ORDER BY time, id) AS previous_status
+	FROM windowed
+)
+SELECT id, status, previous_status, ping, message, time
+FROM marked
+WHERE time >= ? AND (previous_status IS NULL OR status <> previous_status)
+ORDER BY time, id
+LIMIT ?`
+
+// monitorEventRow is a status transition as returned by monitorEventsQuery
+type monitorEventRow struct {
+	ID             int
+	Status         int
+	PreviousStatus *int
+	Ping           int
+	Message        string
+	Time           time.Time
+}
+
+// MonitorEvent is a heartbeat where the monitor's status changed
+type MonitorEvent struct {
+	HeartbeatID    int       `json:"heartbeat_id"`
+	Time           time.Time `json:"time"`
+	Status         int       `json:"status"`
+	PreviousStatus *int      `json:"previous_status"` // nil for the monitor's first heartbeat
+	Ping           int       `json:"ping"`
+	Message        string    `json:"message"`
+	// PreviousDuration is how long the previous status lasted, in seconds.
+	// It is nil when that status began before the requested period.
+	PreviousDuration *int64 `json:"previous_duration"`
+}
+
+// buildMonitorEvents turns transitions in time order into events, timing each
+// previous state from the transition that started it
+func buildMonitorEvents(rows []monitorEventRow) []MonitorEvent {
+	events := make([]MonitorEvent, 0, len(rows))
+	for i, row := range rows {
+		event := MonitorEvent{
+			HeartbeatID:    row.ID,
+			Time:           row.Time,
+			Status:         row.Status,
+			PreviousStatus: row.PreviousStatus,
+			Ping:           row.Ping,
+			Message:        row.Message,
+		}
+		if i > 0 {
+			seconds := int64(row.Time.Sub(rows[i-1].Time).Seconds())
+			event.PreviousDuration = &seconds
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// HandleGetMonitorEvents returns only the heartbeats where a monitor's status
+// changed within the period (default 24h)
+func HandleGetMonitorEvents(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		monitorID := chi.URLParam(r, "id")
+
+		// Verify ownership
+		var count int64
+		db.Model(&models.Monitor{}).
+			Where("id = ? AND user_id = ?", monitorID, user.ID).
+			Count(&count)
+		if count == 0 {
+			http.Error(w, "Monitor not found", http.StatusNotFound)
+			return
+		}
+
+		period := r.URL.Query().Get("period")
+		if period == "" {
+			period = "24h"
+		}
+		length, ok := monitorEventPeriods[period]
+		if !ok {
+			http.Error(w, fmt.Sprintf("Invalid period %q (use 1h, 3h, 6h, 24h, 7d, 30d or 90d)", period), http.StatusBadRequest)
+			return
+		}
+
+		to := time.Now()
+		from := to.Add(-length)
+
+		var rows []monitorEventRow
+		err := db.Raw(monitorEventsQuery,
+			monitorID, from,
+			monitorID, from, to,
+			from, maxMonitorEvents,
+		).Scan(&rows).Error
+		if err != nil {
+			http.Error(w, "Failed to fetch monitor events", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(buildMonitorEvents(rows))
+	}
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestMonitorEventsQuery(t *testing.T) {
+	db := statusPageDB(t, models.StatusPage{}, nil)
+	var sql string
+	var vars []interface{}
+	err := db.Callback().Row().After("gorm:row").Register("test:capture", func(tx *gorm.DB) {
+		sql, vars = tx.Statement.SQL.String(), tx.Statement.Vars
+	})
+	if err != nil {
+		t.Fatalf("register callback: %v", err)
+	}
+
+	routeCtx := chi.NewRouteContext()
+	routeCtx.URLParams.Add("id", "5")
+	req := httptest.NewRequest(http.MethodGet, "/monitors/5/events?period=1h", nil)
+	ctx := context.WithValue(setUserContext(req.Context(), &models.User{ID: 7}), chi.RouteCtxKey, routeCtx)
+	HandleGetMonitorEvents(db)(httptest.NewRecorder(), req.WithContext(ctx))
+
+	// The last heartbeat before the period seeds LAG, so a period starting
+	// mid-outage doesn't report its first heartbeat as a change
+	for _, want := range []string{
+		"WHERE monitor_id = $1 AND time < $2\n\tORDER BY time DESC\n\tLIMIT 1",
+		"WHERE monitor_id = $3 AND time >= $4 AND time <= $5",
+		"LAG(status) OVER (ORDER BY time, id) AS previous_status",
+		"WHERE time >= $6 AND (previous_status IS NULL OR status <> previous_status)",
+		"ORDER BY time, id\nLIMIT $7",
+	} {
+		if !strings.Contains(sql, want) {
+			t.Errorf("query lacks %q:\n%s", want, sql)
+		}
+	}
+
+	if len(vars) != 7 {
+		t.Fatalf("vars = %v, want 7", vars)
+	}
+	from, _ := vars[1].(time.Time)
+	to, _ := vars[4].(time.Time)
+	if vars[0] != "5" || vars[2] != "5" || vars[3] != from || vars[5] != from || vars[6] != maxMonitorEvents {
+		t.Errorf("vars = %v, want monitor 5, the period start for the seed, window and filter, and the limit", vars)
+	}
+	if to.Sub(from) != time.Hour {
+		t.Errorf("period = %s to %s, want one hour", from, to)
+	}
+}
+
+func TestBuildMonitorEvents(t *testing.T) {
+	start := time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC)
+	up, down, pending := 1, 0, 2
+
+	// Transitions of up up down down down up pending up up, one check a
+	// minute, seeded with an up heartbeat before the period
+	rows := []monitorEventRow{
+		{ID: 3, Status: down, PreviousStatus: &up, Time: start.Add(2 * time.Minute)},
+		{ID: 6, Status: up, PreviousStatus: &down, Time: start.Add(5 * time.Minute)},
+		{ID: 7, Status: pending, PreviousStatus: &up, Time: start.Add(6 * time.Minute)},
+		{ID: 8, Status: up, PreviousStatus: &pending, Time: start.Add(7 * time.Minute)},
+	}
+	events := buildMonitorEvents(rows)
+
+	want := []struct {
+		heartbeatID int
+		status      int
+		previous    int
+		duration    int64 // -1 when unknown
+	}{
+		{3, 0, 1, -1},  // the up state started before the period
+		{6, 1, 0, 180}, // down for three checks
+		{7, 2, 1, 60},
+		{8, 1, 2, 60},
+	}
+
+	if len(events) != len(want) {
+		t.Fatalf("got %d events, want %d: %+v", len(events), len(want), events)
+	}
+	for i, w := range want {
+		got := events[i]
+		if got.HeartbeatID != w.heartbeatID || got.Status != w.status ||
+			got.PreviousStatus == nil || *got.PreviousStatus != w.previous {
+			t.Errorf("event %d = heartbeat %d %v->%d, want heartbeat %d %d->%d",
+				i, got.HeartbeatID, got.PreviousStatus, got.Status, w.heartbeatID, w.previous, w.status)
+		}
+		switch {
+		case w.duration < 0 && got.PreviousDuration != nil:
+			t.Errorf("event %d duration = %d, want unknown", i, *got.PreviousDuration)
+		case w.duration >= 0 && (got.PreviousDuration == nil || *got.PreviousDuration != w.duration):
+			t.Errorf("event %d duration = %v, want %d", i, got.PreviousDuration, w.duration)
+		}
+	}
+}
+
+func TestBuildMonitorEventsFirstHeartbeat(t *testing.T) {
+	start := time.Date(2026, 7, 1, 10, 0, 0, 0, time.UTC)
+	pending := 2
+
+	// No heartbeat before the period: the first check opens the timeline
+	events := buildMonitorEvents([]monitorEventRow{
+		{ID: 1, Status: pending, Time: start},
+		{ID: 2, Status: 1, PreviousStatus: &pending, Time: start.Add(30 * time.Second)},
+	})
+
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2: %+v", len(events), events)
+	}
+	if events[0].PreviousStatus != nil || events[0].PreviousDuration != nil {
+		t.Errorf("first event = %+v, want no previous status or duration", events[0])
+	}
+	if events[1].Status != 1 || events[1].PreviousDuration == nil || *events[1].PreviousDuration != 30 {
+		t.Errorf("second event = %+v, want up after 30s pending", events[1])
+	}
+}
+
+func TestBuildMonitorEventsSteadyState(t *testing.T) {
+	// An unchanged status leaves the query with no transitions
+	if events := buildMonitorEvents(nil); events == nil || len(events) != 0 {
+		t.Errorf("got %v for no transitions, want an empty list", events)
+	}
+}
@@ -12,22 +12,74 @@ import (
 
 	"github.com/fuomag9/uptime-kabomba/internal/models"
 	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+	"github.com/fuomag9/uptime-kabomba/internal/uptime"
 )
 
 // MonitorExecutor interface for monitor execution
 type MonitorExecutor interface {
 	StartMonitor(m *monitor.Monitor)
 	StopMonitor(monitorID int)
+	Flapping(monitorID int) bool
 }
 
 // MonitorWithStatus includes monitor data with its last heartbeat
 type MonitorWithStatus struct {
 	models.Monitor
 	LastHeartbeat *models.Heartbeat `json:"last_heartbeat,omitempty"`
+	// UnsupportedType is set when the monitor's type isn't registered in this
+	// server (e.g. page_change with Chrome disabled), so it is never checked
+	UnsupportedType bool `json:"unsupported_type,omitempty"`
+	// IsStale is set when the last heartbeat is older than
+	// staleIntervalFactor check intervals, e.g. the monitor is paused or the
+	// executor stopped checking it
+	IsStale bool `json:"is_stale"`
+	// SecondsSinceLastCheck is the age of the last heartbeat (nil without one)
+	SecondsSinceLastCheck *int `json:"seconds_since_last_check"`
+	// Flapping is set while the monitor's flap detection sees it changing
+	// between up and down more than flap_threshold allows
+	Flapping bool `json:"flapping"`
+	// PushToken is a push monitor's URL token, only sent with the single
+	// monitor its owner opens
+	PushToken string `json:"push_token,omitempty"`
+}
+
+// staleIntervalFactor is how many check intervals a monitor's last heartbeat
+// may age before the monitor list reports it stale
+const staleIntervalFactor = 2
+
+// setStaleness fills in the staleness fields from the last heartbeat as of now
+func (m *MonitorWithStatus) setStaleness(now time.Time) {
+	if m.LastHeartbeat == nil {
+		return
+	}
+	age := now.Sub(m.LastHeartbeat.Time)
+	if age < 0 {
+		age = 0
+	}
+	seconds := int(age / time.Second)
+	m.SecondsSinceLastCheck = &seconds
+	m.IsStale = age > staleIntervalFactor*time.Duration(m.Interval)*time.Second
+}
+
+// isUnsupportedMonitorType reports whether no monitor type named t is registered
+func isUnsupportedMonitorType(t string) bool {
+	_, ok := monitor.GetMonitorType(t)
+	return !ok
+}
+
+// adminMonitorTypes are the monitor types that run code on the server, so
+// only admins may create them
+var adminMonitorTypes = map[string]bool{
+	"script": true,
+}
+
+// canUseMonitorType reports whether user may create or edit monitors of type t
+func canUseMonitorType(user *models.User, t string) bool {
+	return !adminMonitorTypes[t] || user.IsAdmin
 }
 
 // HandleGetMonitors returns all monitors for the current user with their last heartbeat
-func HandleGetMonitors(db *gorm.DB) http.HandlerFunc {
+func HandleGetMonitors(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user := r.Context().Value(userContextKey).(*models.User)
 
@@ -46,8 +98,11 @@ func HandleGetMonitors(db *gorm.DB) http.HandlerFunc {
 		monitorsWithStatus := make([]MonitorWithStatus, len(monitors))
 		monitorIDs := make([]int, 0, len(monitors))
 		for i, mon := range monitors {
+			mon.Config = redactMonitorConfig(mon.Config)
 			monitorsWithStatus[i] = MonitorWithStatus{
-				Monitor: mon,
+				Monitor:         mon,
+				UnsupportedType: isUnsupportedMonitorType(mon.Type),
+				Flapping:        executor != nil && executor.Flapping(mon.ID),
 			}
 			monitorIDs = append(monitorIDs, mon.ID)
 		}
@@ -67,9 +122,11 @@ func HandleGetMonitors(db *gorm.DB) http.HandlerFunc {
 				}
 			}
 
+			now := time.Now()
 			for i, mon := range monitors {
 				if hb, ok := latestByMonitor[mon.ID]; ok {
 					monitorsWithStatus[i].LastHeartbeat = &hb
+					monitorsWithStatus[i].setStaleness(now)
 				}
 			}
 		}
@@ -80,7 +137,7 @@ func HandleGetMonitors(db *gorm.DB) http.HandlerFunc {
 }
 
 // HandleGetMonitor returns a single monitor by ID
-func HandleGetMonitor(db *gorm.DB) http.HandlerFunc {
+func HandleGetMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user := r.Context().Value(userContextKey).(*models.User)
 		monitorID := chi.URLParam(r, "id")
@@ -99,9 +156,16 @@ func HandleGetMonitor(db *gorm.DB) http.HandlerFunc {
 		}
 
 		// AfterFind hook automatically unmarshals Config JSON
+		pushToken, _ := mon.Config["push_token"].(string)
+		mon.Config = redactMonitorConfig(mon.Config)
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(mon)
+		json.NewEncoder(w).Encode(MonitorWithStatus{
+			Monitor:         mon,
+			UnsupportedType: isUnsupportedMonitorType(mon.Type),
+			Flapping:        executor != nil && executor.Flapping(mon.ID),
+			PushToken:       pushToken,
+		})
 	}
 }
 
@@ -130,6 +194,23 @@ func HandleCreateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 		mon.CreatedAt = time.Now()
 		mon.UpdatedAt = time.Now()
 
+		config, err := assignPushToken(mon.Type, mon.Config, nil)
+		if err != nil {
+			http.Error(w, "Failed to generate push token", http.StatusInternalServerError)
+			return
+		}
+		mon.Config = config
+
+		if err := mon.ValidateRetentionDays(); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !canUseMonitorType(user, mon.Type) {
+			http.Error(w, "Only admins can use the "+mon.Type+" monitor type", http.StatusForbidden)
+			return
+		}
+
 		// Validate monitor type
 		monitorType, ok := monitor.GetMonitorType(mon.Type)
 		if !ok {
@@ -144,7 +225,13 @@ func HandleCreateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 			URL:      mon.URL,
 			Interval: mon.Interval,
 			Timeout:  mon.Timeout,
+			Priority: mon.Priority,
 			Config:   mon.Config,
+
+			Retries:       mon.Retries,
+			RetryInterval: mon.RetryInterval,
+
+			EscalationPolicyID: mon.EscalationPolicyID,
 		}
 
 		// Validate configuration
@@ -152,11 +239,63 @@ func HandleCreateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
 			return
 		}
+		if err := monitor.ValidateInterval(internalMon, user.IsAdmin, 0); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateResultWebhook(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateQuorum(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateAutoPause(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateFlapDetection(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateCoalesceInterval(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateResendInterval(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateRetries(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := uptime.ValidateBusinessHours(internalMon.Config); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if mon.EscalationPolicyID != nil {
+			owned, err := ownsEscalationPolicy(db, user.ID, *mon.EscalationPolicyID)
+			if err != nil {
+				http.Error(w, "Failed to verify escalation policy", http.StatusInternalServerError)
+				return
+			}
+			if !owned {
+				http.Error(w, "Escalation policy not found", http.StatusBadRequest)
+				return
+			}
+		}
 
 		// BeforeSave hook will automatically marshal Config to ConfigRaw
 
-		// Insert into database
-		err := db.Create(&mon).Error
+		// Insert into database together with the default notification links
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Create(&mon).Error; err != nil {
+				return err
+			}
+			return attachDefaultNotifications(tx, &mon)
+		})
 		if err != nil {
 			http.Error(w, "Failed to create monitor", http.StatusInternalServerError)
 			return
@@ -172,12 +311,62 @@ func HandleCreateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 			executor.StartMonitor(internalMon)
 		}
 
+		mon.Config = redactMonitorConfig(mon.Config)
+
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
 		json.NewEncoder(w).Encode(mon)
 	}
 }
 
+// newMonitorNotifications decides how a new monitor is wired to
+// notifications. With auto-attach on, the user's default notifications are
+// linked explicitly; if there are none yet the monitor is left unconfigured so
+// defaults added later still apply. With auto-attach off the monitor is marked
+// configured with nothing linked, so it stays silent until notifications are
+// chosen.
+func newMonitorNotifications(autoAttach bool, defaultIDs []int) (link []int, configured bool) {
+	if !autoAttach {
+		return nil, true
+	}
+	if len(defaultIDs) == 0 {
+		return nil, false
+	}
+	return defaultIDs, true
+}
+
+// attachDefaultNotifications applies the owner's auto-attach setting to a
+// newly created monitor
+func attachDefaultNotifications(tx *gorm.DB, mon *models.Monitor) error {
+	settings := models.DefaultUserSettings(mon.UserID)
+	if err := tx.Where("user_id = ?", mon.UserID).First(&settings).Error; err != nil && err != gorm.ErrRecordNotFound {
+		return err
+	}
+
+	var defaultIDs []int
+	if err := tx.Model(&models.Notification{}).
+		Where("user_id = ? AND is_default = ?", mon.UserID, true).
+		Order("id ASC").
+		Pluck("id", &defaultIDs).Error; err != nil {
+		return err
+	}
+
+	link, configured := newMonitorNotifications(settings.AutoAttachDefaultNotifications, defaultIDs)
+	for _, notificationID := range link {
+		if err := tx.Exec("INSERT INTO monitor_notifications (monitor_id, notification_id) VALUES (?, ?)", mon.ID, notificationID).Error; err != nil {
+			return err
+		}
+	}
+	if configured {
+		if err := tx.Exec("UPDATE monitors SET notifications_configured = true WHERE id = ?", mon.ID).Error; err != nil {
+			return err
+		}
+		mon.NotificationsConfigured = true
+	}
+
+	return nil
+}
+
 // HandleUpdateMonitor updates an existing monitor
 func HandleUpdateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -208,6 +397,28 @@ func HandleUpdateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 			return
 		}
 
+		// Keep stored credentials if the client echoed back the redacted values
+		var existing models.Monitor
+		if err := db.Select("config", "interval").Where("id = ?", mon.ID).First(&existing).Error; err == nil {
+			restoreRedactedCredentials(mon.Config, existing.Config)
+		}
+		config, err := assignPushToken(mon.Type, mon.Config, existing.Config)
+		if err != nil {
+			http.Error(w, "Failed to generate push token", http.StatusInternalServerError)
+			return
+		}
+		mon.Config = config
+
+		if err := mon.ValidateRetentionDays(); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if !canUseMonitorType(user, mon.Type) {
+			http.Error(w, "Only admins can use the "+mon.Type+" monitor type", http.StatusForbidden)
+			return
+		}
+
 		// Validate monitor type
 		monitorType, ok := monitor.GetMonitorType(mon.Type)
 		if !ok {
@@ -224,8 +435,14 @@ func HandleUpdateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 			URL:      mon.URL,
 			Interval: mon.Interval,
 			Timeout:  mon.Timeout,
+			Priority: mon.Priority,
 			Active:   mon.Active,
 			Config:   mon.Config,
+
+			Retries:       mon.Retries,
+			RetryInterval: mon.RetryInterval,
+
+			EscalationPolicyID: mon.EscalationPolicyID,
 		}
 
 		// Validate configuration
@@ -233,6 +450,53 @@ func HandleUpdateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
 			return
 		}
+		if err := monitor.ValidateInterval(internalMon, user.IsAdmin, existing.Interval); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateResultWebhook(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateQuorum(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateAutoPause(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateFlapDetection(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateCoalesceInterval(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateResendInterval(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := monitor.ValidateRetries(internalMon); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := uptime.ValidateBusinessHours(internalMon.Config); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if mon.EscalationPolicyID != nil {
+			owned, err := ownsEscalationPolicy(db, user.ID, *mon.EscalationPolicyID)
+			if err != nil {
+				http.Error(w, "Failed to verify escalation policy", http.StatusInternalServerError)
+				return
+			}
+			if !owned {
+				http.Error(w, "Escalation policy not found", http.StatusBadRequest)
+				return
+			}
+		}
 
 		// Manually trigger BeforeSave to marshal Config to ConfigRaw
 		// (Updates() with map doesn't call BeforeSave hook)
@@ -246,16 +510,22 @@ func HandleUpdateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 		err = db.Model(&models.Monitor{}).
 			Where("id = ? AND user_id = ?", mon.ID, user.ID).
 			Updates(map[string]interface{}{
-				"name":            mon.Name,
-				"type":            mon.Type,
-				"url":             mon.URL,
-				"interval":        mon.Interval,
-				"timeout":         mon.Timeout,
-				"resend_interval": mon.ResendInterval,
-				"ip_version":      mon.IPVersion,
-				"active":          mon.Active,
-				"config":          mon.ConfigRaw,
-				"updated_at":      mon.UpdatedAt,
+				"name":                 mon.Name,
+				"type":                 mon.Type,
+				"url":                  mon.URL,
+				"interval":             mon.Interval,
+				"timeout":              mon.Timeout,
+				"resend_interval":      mon.ResendInterval,
+				"retries":              mon.Retries,
+				"retry_interval":       mon.RetryInterval,
+				"ip_version":           mon.IPVersion,
+				"priority":             mon.Priority,
+				"retention_days":       mon.RetentionDays,
+				"active":               mon.Active,
+				"public":               mon.Public,
+				"config":               mon.ConfigRaw,
+				"escalation_policy_id": mon.EscalationPolicyID,
+				"updated_at":           mon.UpdatedAt,
 			}).Error
 
 		if err != nil {
@@ -271,11 +541,129 @@ func HandleUpdateMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc
 			}
 		}
 
+		mon.Config = redactMonitorConfig(mon.Config)
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(mon)
 	}
 }
 
+// redactedPassword replaces a monitor's auth_password, and the bodies and
+// header values of its flow steps, in API responses
+const redactedPassword = "********"
+
+// redactMonitorConfig returns a copy of config with proxy credentials, the
+// auth password and flow step bodies and headers hidden and the push token
+// left out. Saving never changes a push token, so it needs no placeholder to
+// restore.
+func redactMonitorConfig(config map[string]interface{}) map[string]interface{} {
+	proxyURL, _ := config["proxy_url"].(string)
+	password, _ := config["auth_password"].(string)
+	_, hasPushToken := config["push_token"]
+	steps, _ := config["steps"].([]interface{})
+	if proxyURL == "" && password == "" && !hasPushToken && len(steps) == 0 {
+		return config
+	}
+
+	redacted := make(map[string]interface{}, len(config))
+	for k, v := range config {
+		redacted[k] = v
+	}
+	if proxyURL != "" {
+		redacted["proxy_url"] = monitor.RedactProxyURL(proxyURL)
+	}
+	if password != "" {
+		redacted["auth_password"] = redactedPassword
+	}
+	delete(redacted, "push_token")
+	if len(steps) > 0 {
+		redacted["steps"] = redactFlowSteps(steps)
+	}
+	return redacted
+}
+
+// redactFlowSteps returns a copy of a flow's steps with their bodies and
+// header values hidden; logins usually carry credentials in one or the other
+func redactFlowSteps(steps []interface{}) []interface{} {
+	redacted := make([]interface{}, len(steps))
+	for i, raw := range steps {
+		step, ok := raw.(map[string]interface{})
+		if !ok {
+			redacted[i] = raw
+			continue
+		}
+		copied := make(map[string]interface{}, len(step))
+		for k, v := range step {
+			copied[k] = v
+		}
+		if body, _ := step["body"].(string); body != "" {
+			copied["body"] = redactedPassword
+		}
+		if headers, ok := step["headers"].(map[string]interface{}); ok && len(headers) > 0 {
+			hidden := make(map[string]interface{}, len(headers))
+			for name := range headers {
+				hidden[name] = redactedPassword
+			}
+			copied["headers"] = hidden
+		}
+		redacted[i] = copied
+	}
+	return redacted
+}
+
+// restoreRedactedCredentials swaps a redacted proxy_url or auth_password for
+// the stored one so round-tripping a monitor through the API doesn't wipe its
+// credentials
+func restoreRedactedCredentials(config, stored map[string]interface{}) {
+	if password, _ := config["auth_password"].(string); password == redactedPassword {
+		if storedPassword, _ := stored["auth_password"].(string); storedPassword != "" {
+			config["auth_password"] = storedPassword
+		}
+	}
+	restoreRedactedFlowSteps(config, stored)
+
+	proxyURL, _ := config["proxy_url"].(string)
+	storedURL, _ := stored["proxy_url"].(string)
+	if proxyURL == "" || storedURL == "" || proxyURL == storedURL {
+		return
+	}
+	if proxyURL == monitor.RedactProxyURL(storedURL) {
+		config["proxy_url"] = storedURL
+	}
+}
+
+// restoreRedactedFlowSteps swaps the redacted bodies and header values of
+// flow steps for the stored ones of the step at the same position
+func restoreRedactedFlowSteps(config, stored map[string]interface{}) {
+	steps, _ := config["steps"].([]interface{})
+	storedSteps, _ := stored["steps"].([]interface{})
+	for i, raw := range steps {
+		if i >= len(storedSteps) {
+			return
+		}
+		step, ok := raw.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		storedStep, _ := storedSteps[i].(map[string]interface{})
+		if body, _ := step["body"].(string); body == redactedPassword {
+			if storedBody, ok := storedStep["body"].(string); ok {
+				step["body"] = storedBody
+			}
+		}
+		headers, _ := step["headers"].(map[string]interface{})
+		storedHeaders, _ := storedStep["headers"].(map[string]interface{})
+		for name, value := range headers {
+			if value != redactedPassword {
+				continue
+			}
+			if storedValue, ok := storedHeaders[name].(string); ok {
+				headers[name] = storedValue
+			}
+		}
+	}
+}
+
 // HandleDeleteMonitor deletes a monitor
 func HandleDeleteMonitor(db *gorm.DB, executor MonitorExecutor) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,172 @@
+package api
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestNewMonitorNotifications(t *testing.T) {
+	tests := []struct {
+		name           string
+		autoAttach     bool
+		defaultIDs     []int
+		wantLink       []int
+		wantConfigured bool
+	}{
+		{
+			name:           "auto-attach links the defaults",
+			autoAttach:     true,
+			defaultIDs:     []int{2, 5},
+			wantLink:       []int{2, 5},
+			wantConfigured: true,
+		},
+		{
+			name:           "auto-attach without defaults keeps falling back to defaults",
+			autoAttach:     true,
+			wantConfigured: false,
+		},
+		{
+			name:           "disabled starts without notifications",
+			autoAttach:     false,
+			defaultIDs:     []int{2, 5},
+			wantConfigured: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			link, configured := newMonitorNotifications(tt.autoAttach, tt.defaultIDs)
+			if !reflect.DeepEqual(link, tt.wantLink) || configured != tt.wantConfigured {
+				t.Errorf("newMonitorNotifications() = %v, %v, want %v, %v", link, configured, tt.wantLink, tt.wantConfigured)
+			}
+		})
+	}
+}
+
+func TestCanUseMonitorType(t *testing.T) {
+	admin := &models.User{ID: 1, IsAdmin: true}
+	user := &models.User{ID: 2}
+
+	if !canUseMonitorType(admin, "script") {
+		t.Error("admin can't use the script monitor type")
+	}
+	if canUseMonitorType(user, "script") {
+		t.Error("non-admin can use the script monitor type")
+	}
+	for _, monitorType := range []string{"http", "tcp", "docker"} {
+		if !canUseMonitorType(user, monitorType) {
+			t.Errorf("non-admin can't use the %s monitor type", monitorType)
+		}
+	}
+}
+
+func TestRedactMonitorConfigAuthPassword(t *testing.T) {
+	stored := map[string]interface{}{"auth_type": "ntlm", "auth_username": "monitor", "auth_password": "s3cret"}
+
+	redacted := redactMonitorConfig(stored)
+	if redacted["auth_password"] != redactedPassword || stored["auth_password"] != "s3cret" {
+		t.Fatalf("redactMonitorConfig() = %v, stored = %v", redacted, stored)
+	}
+
+	restoreRedactedCredentials(redacted, stored)
+	if redacted["auth_password"] != "s3cret" {
+		t.Errorf("echoed redacted password restored to %v", redacted["auth_password"])
+	}
+
+	changed := map[string]interface{}{"auth_password": "n3w"}
+	restoreRedactedCredentials(changed, stored)
+	if changed["auth_password"] != "n3w" {
+		t.Errorf("new password replaced by %v", changed["auth_password"])
+	}
+}
+
+func TestRedactMonitorConfigPushToken(t *testing.T) {
+	stored := map[string]interface{}{"push_token": strings.Repeat("ab", 16), "grace_period": float64(60)}
+
+	redacted := redactMonitorConfig(stored)
+	if _, ok := redacted["push_token"]; ok || redacted["grace_period"] != float64(60) {
+		t.Errorf("redactMonitorConfig() = %v, want the push token left out", redacted)
+	}
+	if stored["push_token"] == nil {
+		t.Error("redactMonitorConfig() modified the stored config")
+	}
+}
+
+func TestRedactMonitorConfigFlowSteps(t *testing.T) {
+	stored := map[string]interface{}{"steps": []interface{}{
+		map[string]interface{}{"url": "https://example.com/login", "method": "POST", "body": "user=admin&password=s3cret",
+			"headers": map[string]interface{}{"Authorization": "Bearer t0ken"}},
+		map[string]interface{}{"url": "https://example.com/dashboard"},
+	}}
+
+	redacted := redactMonitorConfig(stored)
+	login := redacted["steps"].([]interface{})[0].(map[string]interface{})
+	if login["body"] != redactedPassword || login["headers"].(map[string]interface{})["Authorization"] != redactedPassword {
+		t.Fatalf("login step = %v, want body and headers redacted", login)
+	}
+	if login["url"] != "https://example.com/login" {
+		t.Errorf("login url = %v, want it kept", login["url"])
+	}
+	if body := stored["steps"].([]interface{})[0].(map[string]interface{})["body"]; body != "user=admin&password=s3cret" {
+		t.Errorf("redactMonitorConfig() modified the stored body to %v", body)
+	}
+
+	// Saving echoes the redacted steps back; the second step's new header
+	// is kept as sent
+	echoed := redactMonitorConfig(stored)
+	dashboard := echoed["steps"].([]interface{})[1].(map[string]interface{})
+	dashboard["headers"] = map[string]interface{}{"Accept": "text/html"}
+	restoreRedactedCredentials(echoed, stored)
+	login = echoed["steps"].([]interface{})[0].(map[string]interface{})
+	if login["body"] != "user=admin&password=s3cret" || login["headers"].(map[string]interface{})["Authorization"] != "Bearer t0ken" {
+		t.Errorf("restored login step = %v", login)
+	}
+	if dashboard["headers"].(map[string]interface{})["Accept"] != "text/html" {
+		t.Errorf("new header replaced: %v", dashboard["headers"])
+	}
+}
+
+func TestMonitorWithStatusStaleness(t *testing.T) {
+	now := time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		age         time.Duration
+		wantStale   bool
+		wantSeconds int
+	}{
+		{name: "just checked", age: 5 * time.Second, wantStale: false, wantSeconds: 5},
+		{name: "one interval late", age: 90 * time.Second, wantStale: false, wantSeconds: 90},
+		{name: "exactly two intervals", age: 2 * time.Minute, wantStale: false, wantSeconds: 120},
+		{name: "past two intervals", age: 2*time.Minute + time.Second, wantStale: true, wantSeconds: 121},
+		{name: "paused for a day", age: 24 * time.Hour, wantStale: true, wantSeconds: 86400},
+		{name: "clock skew", age: -3 * time.Second, wantStale: false, wantSeconds: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := MonitorWithStatus{
+				Monitor:       models.Monitor{Interval: 60},
+				LastHeartbeat: &models.Heartbeat{Time: now.Add(-tt.age)},
+			}
+			m.setStaleness(now)
+			if m.IsStale != tt.wantStale {
+				t.Errorf("IsStale = %v, want %v", m.IsStale, tt.wantStale)
+			}
+			if m.SecondsSinceLastCheck == nil || *m.SecondsSinceLastCheck != tt.wantSeconds {
+				t.Errorf("SecondsSinceLastCheck = %v, want %d", m.SecondsSinceLastCheck, tt.wantSeconds)
+			}
+		})
+	}
+}
+
+func TestMonitorWithStatusStalenessWithoutHeartbeat(t *testing.T) {
+	m := MonitorWithStatus{Monitor: models.Monitor{Interval: 60}}
+	m.setStaleness(time.Now())
+	if m.IsStale || m.SecondsSinceLastCheck != nil {
+		t.Errorf("got stale %v, seconds %v; want neither without a heartbeat", m.IsStale, m.SecondsSinceLastCheck)
+	}
+}
@@ -0,0 +1,147 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
+)
+
+const (
+	// testAllConcurrency bounds how many test notifications are sent at once
+	testAllConcurrency = 5
+
+	// testAllTimeout bounds each test send, so one hanging provider can't
+	// hold up the whole report
+	testAllTimeout = 15 * time.Second
+)
+
+// notificationTestResult is the outcome of one test send
+type notificationTestResult struct {
+	ID      int    `json:"id"`
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Success bool   `json:"success"`
+	Error   string `json:"error,omitempty"`
+}
+
+// notificationTestReport is the response of POST /notifications/test-all
+type notificationTestReport struct {
+	Results   []notificationTestResult `json:"results"`
+	Succeeded int                      `json:"succeeded"`
+	Failed    int                      `json:"failed"`
+}
+
+// dispatcherNotification converts a stored notification for the dispatcher
+func dispatcherNotification(m *models.Notification) (*notification.Notification, error) {
+	var config map[string]interface{}
+	if m.Config != "" {
+		if err := json.Unmarshal([]byte(m.Config), &config); err != nil {
+			return nil, err
+		}
+	}
+	return &notification.Notification{
+		ID:               m.ID,
+		UserID:           m.UserID,
+		Name:             m.Name,
+		Type:             m.Type,
+		Config:           config,
+		IsDefault:        m.IsDefault,
+		ReceiveAllEvents: m.ReceiveAllEvents,
+		Active:           m.Active,
+	}, nil
+}
+
+// testNotifications sends a test through every notification, at most
+// concurrency at a time and each bounded by timeout. Results keep the order
+// of notifs. A send that outlives its timeout is reported as failed and left
+// to finish in the background.
+func testNotifications(ctx context.Context, notifs []*notification.Notification, send func(context.Context, *notification.Notification) error, concurrency int, timeout time.Duration) notificationTestReport {
+	report := notificationTestReport{Results: make([]notificationTestResult, len(notifs))}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, n := range notifs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, n *notification.Notification) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			sendCtx, cancel := context.WithTimeout(ctx, timeout)
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() { done <- send(sendCtx, n) }()
+
+			var err error
+			select {
+			case err = <-done:
+			case <-sendCtx.Done():
+				err = fmt.Errorf("timed out after %s", timeout)
+			}
+
+			result := notificationTestResult{ID: n.ID, Name: n.Name, Type: n.Type, Success: err == nil}
+			if err != nil {
+				result.Error = err.Error()
+			}
+			report.Results[i] = result
+		}(i, n)
+	}
+	wg.Wait()
+
+	for _, result := range report.Results {
+		if result.Success {
+			report.Succeeded++
+		} else {
+			report.Failed++
+		}
+	}
+	return report
+}
+
+// HandleTestAllNotifications sends a test notification through each of the
+// user's active notifications and reports the outcome of every send
+func HandleTestAllNotifications(db *gorm.DB, dispatcher *notification.Dispatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+
+		var stored []models.Notification
+		if err := db.Where("user_id = ? AND active = ?", user.ID, true).
+			Order("id").
+			Find(&stored).Error; err != nil {
+			http.Error(w, "Failed to fetch notifications", http.StatusInternalServerError)
+			return
+		}
+
+		notifs := make([]*notification.Notification, 0, len(stored))
+		var invalid []notificationTestResult
+		for i := range stored {
+			n, err := dispatcherNotification(&stored[i])
+			if err != nil {
+				invalid = append(invalid, notificationTestResult{
+					ID:    stored[i].ID,
+					Name:  stored[i].Name,
+					Type:  stored[i].Type,
+					Error: "Invalid notification configuration",
+				})
+				continue
+			}
+			notifs = append(notifs, n)
+		}
+
+		report := testNotifications(r.Context(), notifs, dispatcher.TestNotification, testAllConcurrency, testAllTimeout)
+		report.Results = append(report.Results, invalid...)
+		report.Failed += len(invalid)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
+}
@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
+)
+
+// bulkTestProvider is a notification provider with a scripted Send
+type bulkTestProvider struct {
+	name string
+	send func() error
+}
+
+func (p *bulkTestProvider) Name() string { return p.name }
+
+func (p *bulkTestProvider) Send(ctx context.Context, n *notification.Notification, msg *notification.Message) error {
+	return p.send()
+}
+
+func (p *bulkTestProvider) Validate(config map[string]interface{}) error { return nil }
+
+func TestTestNotificationsMixedResults(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	notification.RegisterProvider(&bulkTestProvider{name: "bulk-ok", send: func() error { return nil }})
+	notification.RegisterProvider(&bulkTestProvider{name: "bulk-fail", send: func() error { return errors.New("webhook returned 500") }})
+	// Ignores its context, like a provider stuck on a dead connection
+	notification.RegisterProvider(&bulkTestProvider{name: "bulk-hang", send: func() error { <-release; return nil }})
+
+	notifs := []*notification.Notification{
+		{ID: 1, Name: "slack", Type: "bulk-ok", Active: true},
+		{ID: 2, Name: "webhook", Type: "bulk-fail", Active: true},
+		{ID: 3, Name: "smtp", Type: "bulk-hang", Active: true},
+		{ID: 4, Name: "discord", Type: "bulk-ok", Active: true},
+		{ID: 5, Name: "legacy", Type: "bulk-missing", Active: true},
+	}
+
+	dispatcher := notification.NewDispatcher(nil)
+	start := time.Now()
+	report := testNotifications(context.Background(), notifs, dispatcher.TestNotification, 2, 100*time.Millisecond)
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Fatalf("test-all took %s, a hanging provider blocked it", elapsed)
+	}
+
+	want := []struct {
+		success bool
+		error   string
+	}{
+		{true, ""},
+		{false, "webhook returned 500"},
+		{false, "timed out after 100ms"},
+		{true, ""},
+		{false, "unknown notification provider"},
+	}
+	if len(report.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(report.Results), len(want))
+	}
+	for i, w := range want {
+		got := report.Results[i]
+		if got.ID != notifs[i].ID || got.Success != w.success || !strings.Contains(got.Error, w.error) {
+			t.Errorf("result %d = %+v, want success=%v error containing %q", i, got, w.success, w.error)
+		}
+	}
+	if report.Succeeded != 2 || report.Failed != 3 {
+		t.Errorf("succeeded = %d, failed = %d, want 2 and 3", report.Succeeded, report.Failed)
+	}
+}
+
+func TestTestNotificationsBoundsConcurrency(t *testing.T) {
+	var running, peak int32
+	send := func(ctx context.Context, n *notification.Notification) error {
+		now := atomic.AddInt32(&running, 1)
+		for {
+			old := atomic.LoadInt32(&peak)
+			if now <= old || atomic.CompareAndSwapInt32(&peak, old, now) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return nil
+	}
+
+	notifs := make([]*notification.Notification, 10)
+	for i := range notifs {
+		notifs[i] = &notification.Notification{ID: i + 1}
+	}
+
+	report := testNotifications(context.Background(), notifs, send, 3, time.Second)
+	if report.Succeeded != 10 {
+		t.Errorf("succeeded = %d, want 10", report.Succeeded)
+	}
+	if peak > 3 {
+		t.Errorf("%d sends ran at once, want at most 3", peak)
+	}
+}
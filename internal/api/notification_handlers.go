@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
@@ -13,6 +14,43 @@ import (
 	"github.com/fuomag9/uptime-kabomba/internal/notification"
 )
 
+// notificationVerification reports the outcome of a save-time reachability probe
+type notificationVerification struct {
+	Supported bool   `json:"supported"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+// notificationResponse is a saved notification with its optional verification result
+type notificationResponse struct {
+	models.Notification
+	Verification *notificationVerification `json:"verification,omitempty"`
+	// Warnings flag problems that don't prevent saving, such as a referenced
+	// environment variable that isn't set yet
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// envWarnings describes the unset environment variables a config references
+func envWarnings(unset []string) []string {
+	var warnings []string
+	for _, name := range unset {
+		warnings = append(warnings, "environment variable "+name+" is not set")
+	}
+	return warnings
+}
+
+// verifyNotification probes the endpoint of a provider configuration without sending an alert
+func verifyNotification(ctx context.Context, provider notification.Provider, config map[string]interface{}) *notificationVerification {
+	supported, err := notification.Verify(ctx, provider, config)
+	if !supported {
+		return &notificationVerification{Supported: false}
+	}
+	if err != nil {
+		return &notificationVerification{Supported: true, Error: err.Error()}
+	}
+	return &notificationVerification{Supported: true, Reachable: true}
+}
+
 // HandleGetNotifications returns all notifications for the current user
 func HandleGetNotificationsV2(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -63,11 +101,13 @@ func HandleCreateNotification(db *gorm.DB) http.HandlerFunc {
 		user := r.Context().Value(userContextKey).(*models.User)
 
 		var req struct {
-			Name      string                 `json:"name"`
-			Type      string                 `json:"type"`
-			Config    map[string]interface{} `json:"config"`
-			IsDefault bool                   `json:"is_default"`
-			Active    bool                   `json:"active"`
+			Name             string                 `json:"name"`
+			Type             string                 `json:"type"`
+			Config           map[string]interface{} `json:"config"`
+			IsDefault        bool                   `json:"is_default"`
+			ReceiveAllEvents bool                   `json:"receive_all_events"`
+			Active           bool                   `json:"active"`
+			Verify           bool                   `json:"verify"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -82,12 +122,35 @@ func HandleCreateNotification(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		// ${NOTIFY_...} references are stored as written and validated as
+		// they will be sent
+		unsetEnv, err := notification.CheckEnvReferences(req.Config)
+		if err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		expandedConfig := notification.ExpandEnv(req.Config)
+
 		// Validate configuration
-		if err := provider.Validate(req.Config); err != nil {
+		if err := provider.Validate(expandedConfig); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := notification.ValidateQuietHours(req.Config); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := notification.ValidateLocale(req.Config); err != nil {
 			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
 			return
 		}
 
+		// Optional reachability probe; the result is reported, never fatal
+		var verification *notificationVerification
+		if req.Verify {
+			verification = verifyNotification(r.Context(), provider, expandedConfig)
+		}
+
 		// Marshal config to JSON
 		configJSON, err := json.Marshal(req.Config)
 		if err != nil {
@@ -97,14 +160,15 @@ func HandleCreateNotification(db *gorm.DB) http.HandlerFunc {
 
 		// Create notification
 		notif := models.Notification{
-			UserID:    user.ID,
-			Name:      req.Name,
-			Type:      req.Type,
-			Config:    string(configJSON),
-			IsDefault: req.IsDefault,
-			Active:    true,
-			CreatedAt: time.Now(),
-			UpdatedAt: time.Now(),
+			UserID:           user.ID,
+			Name:             req.Name,
+			Type:             req.Type,
+			Config:           string(configJSON),
+			IsDefault:        req.IsDefault,
+			ReceiveAllEvents: req.ReceiveAllEvents,
+			Active:           true,
+			CreatedAt:        time.Now(),
+			UpdatedAt:        time.Now(),
 		}
 
 		err = db.Create(&notif).Error
@@ -115,7 +179,11 @@ func HandleCreateNotification(db *gorm.DB) http.HandlerFunc {
 
 		w.Header().Set("Content-Type", "application/json")
 		w.WriteHeader(http.StatusCreated)
-		json.NewEncoder(w).Encode(notif)
+		json.NewEncoder(w).Encode(notificationResponse{
+			Notification: notif,
+			Verification: verification,
+			Warnings:     envWarnings(unsetEnv),
+		})
 	}
 }
 
@@ -126,11 +194,13 @@ func HandleUpdateNotification(db *gorm.DB) http.HandlerFunc {
 		notificationID := chi.URLParam(r, "id")
 
 		var req struct {
-			Name      string                 `json:"name"`
-			Type      string                 `json:"type"`
-			Config    map[string]interface{} `json:"config"`
-			IsDefault bool                   `json:"is_default"`
-			Active    bool                   `json:"active"`
+			Name             string                 `json:"name"`
+			Type             string                 `json:"type"`
+			Config           map[string]interface{} `json:"config"`
+			IsDefault        bool                   `json:"is_default"`
+			ReceiveAllEvents bool                   `json:"receive_all_events"`
+			Active           bool                   `json:"active"`
+			Verify           bool                   `json:"verify"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -162,12 +232,35 @@ func HandleUpdateNotification(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		// ${NOTIFY_...} references are stored as written and validated as
+		// they will be sent
+		unsetEnv, err := notification.CheckEnvReferences(req.Config)
+		if err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		expandedConfig := notification.ExpandEnv(req.Config)
+
 		// Validate configuration
-		if err := provider.Validate(req.Config); err != nil {
+		if err := provider.Validate(expandedConfig); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := notification.ValidateQuietHours(req.Config); err != nil {
+			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := notification.ValidateLocale(req.Config); err != nil {
 			http.Error(w, "Validation failed: "+err.Error(), http.StatusBadRequest)
 			return
 		}
 
+		// Optional reachability probe; the result is reported, never fatal
+		var verification *notificationVerification
+		if req.Verify {
+			verification = verifyNotification(r.Context(), provider, expandedConfig)
+		}
+
 		// Marshal config to JSON
 		configJSON, err := json.Marshal(req.Config)
 		if err != nil {
@@ -179,12 +272,13 @@ func HandleUpdateNotification(db *gorm.DB) http.HandlerFunc {
 		err = db.Model(&models.Notification{}).
 			Where("id = ? AND user_id = ?", id, user.ID).
 			Updates(map[string]interface{}{
-				"name":       req.Name,
-				"type":       req.Type,
-				"config":     string(configJSON),
-				"is_default": req.IsDefault,
-				"active":     req.Active,
-				"updated_at": time.Now(),
+				"name":               req.Name,
+				"type":               req.Type,
+				"config":             string(configJSON),
+				"is_default":         req.IsDefault,
+				"receive_all_events": req.ReceiveAllEvents,
+				"active":             req.Active,
+				"updated_at":         time.Now(),
 			}).Error
 
 		if err != nil {
@@ -197,7 +291,11 @@ func HandleUpdateNotification(db *gorm.DB) http.HandlerFunc {
 		db.Where("id = ?", id).First(&notif)
 
 		w.Header().Set("Content-Type", "application/json")
-		json.NewEncoder(w).Encode(notif)
+		json.NewEncoder(w).Encode(notificationResponse{
+			Notification: notif,
+			Verification: verification,
+			Warnings:     envWarnings(unsetEnv),
+		})
 	}
 }
 
@@ -252,24 +350,10 @@ func HandleTestNotification(db *gorm.DB, dispatcher *notification.Dispatcher) ht
 			return
 		}
 
-		// Parse config JSON
-		var config map[string]interface{}
-		if modelNotif.Config != "" {
-			if err := json.Unmarshal([]byte(modelNotif.Config), &config); err != nil {
-				http.Error(w, "Invalid notification configuration", http.StatusInternalServerError)
-				return
-			}
-		}
-
-		// Create notification.Notification for dispatcher
-		notif := &notification.Notification{
-			ID:        modelNotif.ID,
-			UserID:    modelNotif.UserID,
-			Name:      modelNotif.Name,
-			Type:      modelNotif.Type,
-			Config:    config,
-			IsDefault: modelNotif.IsDefault,
-			Active:    modelNotif.Active,
+		notif, err := dispatcherNotification(&modelNotif)
+		if err != nil {
+			http.Error(w, "Invalid notification configuration", http.StatusInternalServerError)
+			return
 		}
 
 		// Send test notification
@@ -315,6 +399,8 @@ func getProviderLabel(name string) string {
 		"pushover":   "Pushover",
 		"gotify":     "Gotify",
 		"ntfy":       "Ntfy",
+		"matrix":     "Matrix",
+		"opsgenie":   "Opsgenie",
 	}
 
 	if label, ok := labels[name]; ok {
@@ -41,10 +41,11 @@ func HandleOAuthAuthorize(db *gorm.DB, cfg *config.Config, oauthClient *oauth.Cl
 			return
 		}
 
-		// Determine redirect URL (use configured or construct from request)
-		redirectURL := cfg.OAuth.RedirectURL
-		if redirectURL == "" {
-			redirectURL = getRedirectURL(r)
+		redirectURL, err := oauthRedirectURL(r, cfg.OAuth)
+		if err != nil {
+			log.Println("OAuth:", err)
+			http.Error(w, "Redirect URL is not allowed", http.StatusBadRequest)
+			return
 		}
 
 		// Store session in database (10 minute expiry)
@@ -382,6 +383,33 @@ func generateUsername(email string, db *gorm.DB) string {
 	return username
 }
 
+// oauthRedirectURL picks the URL the provider sends the user back to: the
+// redirect_uri query parameter when it is the configured RedirectURL or in
+// AllowedRedirectURLs, else RedirectURL, else the first allowlisted URL. Only
+// with neither configured is it guessed from the request. Requested URLs are
+// compared exactly, so a login can't be sent anywhere else.
+func oauthRedirectURL(r *http.Request, cfg *config.OAuthConfig) (string, error) {
+	if requested := r.URL.Query().Get("redirect_uri"); requested != "" {
+		if requested == cfg.RedirectURL {
+			return requested, nil
+		}
+		for _, allowed := range cfg.AllowedRedirectURLs {
+			if requested == allowed {
+				return requested, nil
+			}
+		}
+		return "", fmt.Errorf("redirect_uri %q is not allowlisted", requested)
+	}
+
+	if cfg.RedirectURL != "" {
+		return cfg.RedirectURL, nil
+	}
+	if len(cfg.AllowedRedirectURLs) > 0 {
+		return cfg.AllowedRedirectURLs[0], nil
+	}
+	return getRedirectURL(r), nil
+}
+
 // getRedirectURL constructs the OAuth redirect URL from the request
 // Returns the FRONTEND URL, not the backend URL, since OIDC provider
 // should redirect users to the frontend, not directly to backend
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/fuomag9/uptime-kabomba/internal/config"
+)
+
+func authorizeRequest(redirectURI string) *http.Request {
+	target := "/api/auth/oauth/authorize"
+	if redirectURI != "" {
+		target += "?redirect_uri=" + url.QueryEscape(redirectURI)
+	}
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.Header.Set("Origin", "https://guessed.example.com")
+	return r
+}
+
+func TestOAuthRedirectURLAllowed(t *testing.T) {
+	cfg := &config.OAuthConfig{
+		RedirectURL:         "https://uptime.example.com/oauth/callback",
+		AllowedRedirectURLs: []string{"https://example.com/monitoring/oauth/callback", "myapp://oauth/callback"},
+	}
+
+	tests := []struct {
+		requested string
+		want      string
+	}{
+		{"", cfg.RedirectURL},
+		{cfg.RedirectURL, cfg.RedirectURL},
+		{"https://example.com/monitoring/oauth/callback", "https://example.com/monitoring/oauth/callback"},
+		{"myapp://oauth/callback", "myapp://oauth/callback"},
+	}
+	for _, tt := range tests {
+		got, err := oauthRedirectURL(authorizeRequest(tt.requested), cfg)
+		if err != nil || got != tt.want {
+			t.Errorf("redirect_uri %q: got %q, %v; want %q", tt.requested, got, err, tt.want)
+		}
+	}
+}
+
+func TestOAuthRedirectURLDisallowed(t *testing.T) {
+	cfg := &config.OAuthConfig{
+		RedirectURL:         "https://uptime.example.com/oauth/callback",
+		AllowedRedirectURLs: []string{"https://example.com/monitoring/oauth/callback"},
+	}
+
+	for _, requested := range []string{
+		"https://evil.example.net/oauth/callback",
+		"https://uptime.example.com/oauth/callback/../../steal",
+		"https://uptime.example.com/oauth/callback?next=https://evil.example.net",
+		"https://uptime.example.com.evil.example.net/oauth/callback",
+		"https://example.com/monitoring/oauth/callback#",
+		"//evil.example.net/oauth/callback",
+	} {
+		if got, err := oauthRedirectURL(authorizeRequest(requested), cfg); err == nil {
+			t.Errorf("redirect_uri %q was allowed as %q", requested, got)
+		}
+	}
+}
+
+func TestOAuthRedirectURLFallbacks(t *testing.T) {
+	allowlistOnly := &config.OAuthConfig{AllowedRedirectURLs: []string{"https://example.com/monitoring/oauth/callback"}}
+	if got, _ := oauthRedirectURL(authorizeRequest(""), allowlistOnly); got != "https://example.com/monitoring/oauth/callback" {
+		t.Errorf("without RedirectURL got %q, want the first allowlisted URL", got)
+	}
+
+	// Nothing configured keeps guessing the frontend from the request
+	if got, _ := oauthRedirectURL(authorizeRequest(""), &config.OAuthConfig{}); got != "https://guessed.example.com/oauth/callback" {
+		t.Errorf("unconfigured got %q, want the guessed URL", got)
+	}
+	if _, err := oauthRedirectURL(authorizeRequest("https://guessed.example.com/oauth/callback"), &config.OAuthConfig{}); err == nil {
+		t.Error("a requested redirect_uri was allowed with no allowlist")
+	}
+}
+
+func TestOAuthAuthorizeRejectsDisallowedRedirect(t *testing.T) {
+	cfg := &config.Config{OAuth: &config.OAuthConfig{
+		Enabled:     true,
+		RedirectURL: "https://uptime.example.com/oauth/callback",
+	}}
+	w := httptest.NewRecorder()
+	HandleOAuthAuthorize(nil, cfg, nil)(w, authorizeRequest("https://evil.example.net/oauth/callback"))
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want 400", w.Code)
+	}
+}
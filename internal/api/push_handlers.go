@@ -0,0 +1,100 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+)
+
+// maxPushPing caps the ping a push may report, an hour in milliseconds
+const maxPushPing = 60 * 60 * 1000
+
+// pushRequest is a heartbeat pushed to a push monitor's URL
+type pushRequest struct {
+	status  int
+	ping    int
+	message string
+}
+
+// parsePushRequest reads a push's optional query parameters: status ("up",
+// the default, or "down"), ping in milliseconds and msg
+func parsePushRequest(query url.Values) (*pushRequest, error) {
+	req := &pushRequest{status: monitor.StatusUp}
+
+	switch query.Get("status") {
+	case "", "up":
+	case "down":
+		req.status = monitor.StatusDown
+	default:
+		return nil, fmt.Errorf("status must be up or down")
+	}
+
+	if raw := query.Get("ping"); raw != "" {
+		ping, err := strconv.ParseFloat(raw, 64)
+		if err != nil || ping < 0 || ping > maxPushPing {
+			return nil, fmt.Errorf("ping must be a number of milliseconds between 0 and %d", maxPushPing)
+		}
+		req.ping = int(ping)
+	}
+
+	req.message = query.Get("msg")
+	if runes := []rune(req.message); len(runes) > maxAgentMessageLength {
+		req.message = string(runes[:maxAgentMessageLength])
+	}
+	return req, nil
+}
+
+// assignPushToken gives a push monitor's config its push URL token: the
+// stored one when it has one, otherwise a new one. Clients can't choose
+// tokens, so they stay unguessable and unique.
+func assignPushToken(monitorType string, config, stored map[string]interface{}) (map[string]interface{}, error) {
+	if monitorType != "push" {
+		if config != nil {
+			delete(config, "push_token")
+		}
+		return config, nil
+	}
+
+	if config == nil {
+		config = make(map[string]interface{})
+	}
+	if token, _ := stored["push_token"].(string); token != "" {
+		config["push_token"] = token
+		return config, nil
+	}
+	token, err := monitor.GeneratePushToken()
+	if err != nil {
+		return nil, err
+	}
+	config["push_token"] = token
+	return config, nil
+}
+
+// HandlePush records a heartbeat for the active push monitor with the token
+// in the URL. It needs no other authentication: the token is the secret.
+// GET is accepted as well as POST so plain curl and wget calls work.
+func HandlePush(executor *monitor.Executor) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req, err := parsePushRequest(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		token := chi.URLParam(r, "token")
+		if !executor.RecordPush(token, req.status, req.ping, req.message, time.Now()) {
+			http.Error(w, "Push monitor not found or not active", http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]bool{"ok": true})
+	}
+}
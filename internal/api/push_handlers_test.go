@@ -0,0 +1,78 @@
+package api
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+)
+
+func TestParsePushRequest(t *testing.T) {
+	tests := []struct {
+		query      string
+		wantStatus int
+		wantPing   int
+		wantMsg    string
+		wantErr    bool
+	}{
+		{query: "", wantStatus: monitor.StatusUp},
+		{query: "ping=250", wantStatus: monitor.StatusUp, wantPing: 250},
+		{query: "ping=12.7&msg=OK", wantStatus: monitor.StatusUp, wantPing: 12, wantMsg: "OK"},
+		{query: "status=down&msg=disk+full", wantStatus: monitor.StatusDown, wantMsg: "disk full"},
+		{query: "ping=abc", wantErr: true},
+		{query: "ping=-5", wantErr: true},
+		{query: "status=maybe", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		values, _ := url.ParseQuery(tt.query)
+		req, err := parsePushRequest(values)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parsePushRequest(%q) error = %v, want error %v", tt.query, err, tt.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		if req.status != tt.wantStatus || req.ping != tt.wantPing || req.message != tt.wantMsg {
+			t.Errorf("parsePushRequest(%q) = %+v, want status %d ping %d msg %q", tt.query, req, tt.wantStatus, tt.wantPing, tt.wantMsg)
+		}
+	}
+}
+
+func TestParsePushRequestTruncatesMessage(t *testing.T) {
+	req, err := parsePushRequest(url.Values{"msg": {strings.Repeat("é", maxAgentMessageLength+10)}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len([]rune(req.message)); n != maxAgentMessageLength {
+		t.Errorf("message has %d characters, want %d", n, maxAgentMessageLength)
+	}
+}
+
+func TestAssignPushToken(t *testing.T) {
+	stored := map[string]interface{}{"push_token": strings.Repeat("ab", 16)}
+
+	// A new push monitor gets a token, even one the client tried to pick
+	config, err := assignPushToken("push", map[string]interface{}{"push_token": "chosen"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, _ := config["push_token"].(string)
+	if token == "chosen" || len(token) != 32 {
+		t.Errorf("new token = %q, want a generated one", token)
+	}
+
+	// An update keeps the stored token
+	config, _ = assignPushToken("push", map[string]interface{}{}, stored)
+	if config["push_token"] != stored["push_token"] {
+		t.Errorf("updated token = %v, want the stored %v", config["push_token"], stored["push_token"])
+	}
+
+	// Other types carry none
+	config, _ = assignPushToken("http", map[string]interface{}{"push_token": "x"}, stored)
+	if _, ok := config["push_token"]; ok {
+		t.Error("http monitor kept a push_token")
+	}
+}
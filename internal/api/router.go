@@ -22,6 +22,7 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 
 	// Middleware
 	r.Use(middleware.RequestID)
+	r.Use(ConnectingIPMiddleware)
 	r.Use(middleware.RealIP)
 	r.Use(middleware.Logger)
 	r.Use(middleware.Recoverer)
@@ -31,8 +32,8 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   cfg.CORSOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "X-Status-Page-Password", "X-Status-Page-Token"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count"},
 		AllowCredentials: true,
 		MaxAge:           300,
 	}))
@@ -49,7 +50,9 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 	})
 
 	// Global rate limiter - 100 requests per minute per IP
+	// Trusted networks (RATE_LIMIT_EXEMPT_CIDRS) bypass it; auth endpoints stay limited
 	globalLimiter := NewRateLimiter(100.0/60.0, 20)
+	globalLimiter.SetExemptions(cfg.RateLimitExemptCIDRs)
 	globalLimiter.CleanupOldLimiters()
 	r.Use(RateLimitMiddleware(globalLimiter))
 
@@ -57,6 +60,11 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 	authLimiter := NewRateLimiter(5.0/900.0, 2)
 	authLimiter.CleanupOldLimiters()
 
+	// Status page password checks - 10 requests per minute, burst of 5, kept
+	// apart from the auth limiter so visitors can't lock out logins
+	statusAuthLimiter := NewRateLimiter(10.0/60.0, 5)
+	statusAuthLimiter.CleanupOldLimiters()
+
 	// Initialize OAuth client if enabled
 	// Discovery is now lazy, so initialization won't fail even if OIDC provider is unreachable
 	var oauthClient *oauth.Client
@@ -82,6 +90,17 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 
 		// Public status page endpoint (no auth required)
 		r.Get("/status/{slug}", HandleGetPublicStatusPage(db))
+		r.With(StrictRateLimitMiddleware(statusAuthLimiter)).Post("/status/{slug}/auth", HandleStatusPageAuth(db))
+		r.Get("/status/{slug}/incidents.rss", HandleStatusPageIncidentFeed(db))
+		r.Get("/status/{slug}/incidents", HandleGetPublicStatusPageIncidents(db))
+		r.Get("/status-domain", HandleGetPublicStatusPageByDomain(db))
+
+		// Push monitors receive heartbeats at their token's URL
+		r.Get("/push/{token}", HandlePush(executor))
+		r.Post("/push/{token}", HandlePush(executor))
+
+		// Remote agents report check results with an agent scoped API key
+		r.With(APIKeyAuthMiddleware(db)).Post("/agent/heartbeat", HandleAgentHeartbeat(db, executor))
 
 		// OAuth routes (if enabled)
 		if oauthClient != nil {
@@ -93,7 +112,7 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 
 		// Protected routes
 		r.Group(func(r chi.Router) {
-			r.Use(AuthMiddleware(cfg.JWTSecret, db))
+			r.Use(AuthMiddleware(cfg.JWTKeys, db))
 
 			// User routes
 			r.Get("/user/me", HandleGetCurrentUser(db))
@@ -104,18 +123,33 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 			r.Post("/user/change-password", HandleChangePassword(db))
 
 			// Monitor routes
-			r.Get("/monitors", HandleGetMonitors(db))
+			r.Get("/monitors", HandleGetMonitors(db, executor))
 			r.Post("/monitors", HandleCreateMonitor(db, executor))
-			r.Get("/monitors/{id}", HandleGetMonitor(db))
+			r.Get("/monitors/{id}", HandleGetMonitor(db, executor))
 			r.Put("/monitors/{id}", HandleUpdateMonitor(db, executor))
 			r.Delete("/monitors/{id}", HandleDeleteMonitor(db, executor))
 			r.Get("/monitors/{id}/heartbeats", HandleGetHeartbeats(db))
+			r.Get("/monitors/{id}/events", HandleGetMonitorEvents(db))
 			r.Get("/monitors/{id}/notifications", HandleGetMonitorNotifications(db))
 			r.Put("/monitors/{id}/notifications", HandleUpdateMonitorNotifications(db))
 			r.Get("/monitors/{id}/uptime", HandleGetMonitorUptime(db))
 			r.Get("/monitors/{id}/uptime/history", HandleGetMonitorUptimeHistory(db))
 			r.Get("/monitors/{id}/uptime/hourly", HandleGetMonitorHourlyUptime(db))
+			r.Get("/monitors/{id}/uptime/weekly", HandleGetMonitorWeeklyUptime(db))
+			r.Get("/monitors/{id}/stats", HandleGetMonitorStats(db))
+			r.Get("/monitors/{id}/maintenance", HandleGetMaintenanceWindows(db))
+			r.Post("/monitors/{id}/maintenance", HandleCreateMaintenanceWindow(db, executor))
+			r.Put("/monitors/{id}/maintenance/{windowId}", HandleUpdateMaintenanceWindow(db, executor))
+			r.Delete("/monitors/{id}/maintenance/{windowId}", HandleDeleteMaintenanceWindow(db, executor))
 			r.Get("/monitors/uptime/all", HandleGetAllMonitorsUptime(db))
+			r.Post("/monitors/uptime/compare", HandleCompareMonitorsUptime(db))
+
+			// Tag routes
+			r.Get("/tags", HandleGetTags(db))
+			r.Post("/tags", HandleCreateTag(db))
+			r.Post("/tags/{id}/assign", HandleAssignTag(db))
+			r.Get("/tags/{id}/status", HandleGetTagStatus(db))
+			r.Get("/monitor-groups/{id}/status", HandleGetTagStatus(db)) // tags group monitors
 
 			// Page change snapshot routes
 			r.Get("/monitors/{id}/snapshots", HandleGetSnapshots(db))
@@ -127,6 +161,7 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 			r.Get("/notifications", HandleGetNotificationsV2(db))
 			r.Post("/notifications", HandleCreateNotification(db))
 			r.Get("/notifications/providers", HandleGetAvailableProviders())
+			r.Post("/notifications/test-all", HandleTestAllNotifications(db, dispatcher))
 			r.Get("/notifications/{id}", HandleGetNotification(db))
 			r.Put("/notifications/{id}", HandleUpdateNotification(db))
 			r.Delete("/notifications/{id}", HandleDeleteNotification(db))
@@ -138,6 +173,9 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 			r.Get("/status-pages/{id}", HandleGetStatusPage(db))
 			r.Put("/status-pages/{id}", HandleUpdateStatusPage(db))
 			r.Delete("/status-pages/{id}", HandleDeleteStatusPage(db))
+			r.Put("/status-pages/{id}/order", HandleUpdateStatusPageOrder(db))
+			r.Get("/status-pages/{id}/export", HandleExportStatusPage(db))
+			r.Post("/status-pages/import", HandleImportStatusPage(db))
 			r.Get("/status-pages/{id}/incidents", HandleGetIncidents(db))
 			r.Post("/status-pages/{id}/incidents", HandleCreateIncident(db))
 			r.Delete("/status-pages/{id}/incidents/{incidentId}", HandleDeleteIncident(db))
@@ -153,6 +191,17 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 			r.Get("/certificates/{id}", HandleGetCertificate(db))
 			r.Put("/certificates/{id}", HandleUpdateCertificate(db))
 			r.Delete("/certificates/{id}", HandleDeleteCertificate(db))
+
+			// Escalation policy routes
+			r.Get("/escalation-policies", HandleGetEscalationPolicies(db))
+			r.Post("/escalation-policies", HandleCreateEscalationPolicy(db))
+			r.Get("/escalation-policies/{id}", HandleGetEscalationPolicy(db))
+			r.Put("/escalation-policies/{id}", HandleUpdateEscalationPolicy(db))
+			r.Delete("/escalation-policies/{id}", HandleDeleteEscalationPolicy(db))
+
+			// Admin debugging routes
+			r.Get("/admin/executor", HandleGetExecutorState(db, executor))
+			r.Post("/admin/executor/reconcile", HandleReconcileExecutor(db, executor))
 		})
 	})
 
@@ -161,7 +210,9 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 	r.Get("/api/status/{slug}/monitors/{id}/heartbeats", HandleGetPublicStatusPageHeartbeats(db))
 
 	// Prometheus metrics endpoint (token required)
-	r.Get("/metrics", HandlePrometheusMetrics(db, cfg))
+	r.Get("/metrics", HandlePrometheusMetrics(db, cfg, hub))
+	// Per-user metrics, scraped with a metrics scoped API key
+	r.With(APIKeyAuthMiddleware(db)).Get("/users/metrics", HandleUserPrometheusMetrics(db))
 
 	// Badge endpoints (no auth required)
 	r.Get("/api/badge/{id}/status", HandleStatusBadge(db))
@@ -181,5 +232,8 @@ func NewRouter(cfg *config.Config, db *gorm.DB, hub *websocket.Hub, executor *mo
 		w.Write([]byte("OK"))
 	})
 
+	// Version and schema migration state (token required)
+	r.Get("/version", HandleGetVersion(db, cfg))
+
 	return r
 }
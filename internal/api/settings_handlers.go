@@ -101,6 +101,10 @@ type UpdateUserSettingsRequest struct {
 	HeartbeatRetentionDays  int `json:"heartbeat_retention_days"`
 	HourlyStatRetentionDays int `json:"hourly_stat_retention_days"`
 	DailyStatRetentionDays  int `json:"daily_stat_retention_days"`
+
+	// Optional so clients that only send retention values keep the current setting
+	AutoAttachDefaultNotifications *bool   `json:"auto_attach_default_notifications,omitempty"`
+	Timezone                       *string `json:"timezone,omitempty"`
 }
 
 // HandleUpdateUserSettings updates the user's settings
@@ -120,8 +124,14 @@ func HandleUpdateUserSettings(db *gorm.DB) http.HandlerFunc {
 			HourlyStatRetentionDays: req.HourlyStatRetentionDays,
 			DailyStatRetentionDays:  req.DailyStatRetentionDays,
 		}
+		if req.Timezone != nil {
+			newSettings.Timezone = *req.Timezone
+			if newSettings.Timezone == "" {
+				newSettings.Timezone = "UTC"
+			}
+		}
 
-		// Validate retention values
+		// Validate retention values and time zone
 		if err := newSettings.Validate(); err != nil {
 			http.Error(w, err.Error(), http.StatusBadRequest)
 			return
@@ -133,14 +143,18 @@ func HandleUpdateUserSettings(db *gorm.DB) http.HandlerFunc {
 
 		if result.Error == gorm.ErrRecordNotFound {
 			// Create new settings
-			settings = models.UserSettings{
-				UserID:                  user.ID,
-				HeartbeatRetentionDays:  req.HeartbeatRetentionDays,
-				HourlyStatRetentionDays: req.HourlyStatRetentionDays,
-				DailyStatRetentionDays:  req.DailyStatRetentionDays,
-				CreatedAt:               time.Now(),
-				UpdatedAt:               time.Now(),
+			settings = models.DefaultUserSettings(user.ID)
+			settings.HeartbeatRetentionDays = req.HeartbeatRetentionDays
+			settings.HourlyStatRetentionDays = req.HourlyStatRetentionDays
+			settings.DailyStatRetentionDays = req.DailyStatRetentionDays
+			if req.AutoAttachDefaultNotifications != nil {
+				settings.AutoAttachDefaultNotifications = *req.AutoAttachDefaultNotifications
+			}
+			if req.Timezone != nil {
+				settings.Timezone = newSettings.Timezone
 			}
+			settings.CreatedAt = time.Now()
+			settings.UpdatedAt = time.Now()
 			if err := db.Create(&settings).Error; err != nil {
 				http.Error(w, "Failed to create settings", http.StatusInternalServerError)
 				return
@@ -153,6 +167,12 @@ func HandleUpdateUserSettings(db *gorm.DB) http.HandlerFunc {
 			settings.HeartbeatRetentionDays = req.HeartbeatRetentionDays
 			settings.HourlyStatRetentionDays = req.HourlyStatRetentionDays
 			settings.DailyStatRetentionDays = req.DailyStatRetentionDays
+			if req.AutoAttachDefaultNotifications != nil {
+				settings.AutoAttachDefaultNotifications = *req.AutoAttachDefaultNotifications
+			}
+			if req.Timezone != nil {
+				settings.Timezone = newSettings.Timezone
+			}
 			settings.UpdatedAt = time.Now()
 
 			if err := db.Save(&settings).Error; err != nil {
@@ -0,0 +1,144 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// statsGranularity describes one pre-aggregated stats table
+type statsGranularity struct {
+	table        string
+	defaultRange time.Duration
+	maxRange     time.Duration
+}
+
+var statsGranularities = map[string]statsGranularity{
+	"hourly": {table: "stat_hourly", defaultRange: 24 * time.Hour, maxRange: 90 * 24 * time.Hour},
+	"daily":  {table: "stat_daily", defaultRange: 30 * 24 * time.Hour, maxRange: 730 * 24 * time.Hour},
+}
+
+// StatRow is a single pre-aggregated stats bucket
+type StatRow struct {
+	Timestamp        time.Time `json:"timestamp" gorm:"column:timestamp"`
+	PingMin          int       `json:"ping_min" gorm:"column:ping_min"`
+	PingMax          int       `json:"ping_max" gorm:"column:ping_max"`
+	PingAvg          float64   `json:"ping_avg" gorm:"column:ping_avg"`
+	UpCount          int       `json:"up_count" gorm:"column:up_count"`
+	DownCount        int       `json:"down_count" gorm:"column:down_count"`
+	UptimePercentage float64   `json:"uptime_percentage" gorm:"column:uptime_percentage"`
+}
+
+// statsQuery is a validated stats request
+type statsQuery struct {
+	granularity string
+	table       string
+	from        time.Time
+	to          time.Time
+}
+
+// parseStatsQuery validates granularity and the from/to range (RFC 3339).
+// Missing bounds default to the granularity's default range ending now.
+func parseStatsQuery(query url.Values, now time.Time) (*statsQuery, error) {
+	granularity := query.Get("granularity")
+	if granularity == "" {
+		granularity = "hourly"
+	}
+	g, ok := statsGranularities[granularity]
+	if !ok {
+		return nil, fmt.Errorf("granularity must be hourly or daily")
+	}
+
+	to := now
+	if v := query.Get("to"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to: must be RFC 3339")
+		}
+		to = t
+	}
+
+	from := to.Add(-g.defaultRange)
+	if v := query.Get("from"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from: must be RFC 3339")
+		}
+		from = t
+	}
+
+	if !from.Before(to) {
+		return nil, fmt.Errorf("from must be before to")
+	}
+	if to.Sub(from) > g.maxRange {
+		return nil, fmt.Errorf("range too large for %s stats (max %d days)", granularity, int(g.maxRange.Hours()/24))
+	}
+
+	return &statsQuery{
+		granularity: granularity,
+		table:       g.table,
+		from:        from,
+		to:          to,
+	}, nil
+}
+
+// HandleGetMonitorStats returns pre-aggregated hourly or daily stats for a monitor
+func HandleGetMonitorStats(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		monitorID := chi.URLParam(r, "id")
+
+		id, err := strconv.Atoi(monitorID)
+		if err != nil {
+			http.Error(w, "Invalid monitor ID", http.StatusBadRequest)
+			return
+		}
+
+		// Verify ownership
+		var count int64
+		db.Model(&models.Monitor{}).
+			Where("id = ? AND user_id = ?", id, user.ID).
+			Count(&count)
+		if count == 0 {
+			http.Error(w, "Monitor not found", http.StatusNotFound)
+			return
+		}
+
+		q, err := parseStatsQuery(r.URL.Query(), time.Now())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Table name comes from statsGranularities, never from the request
+		query := `
+			SELECT timestamp, ping_min, ping_max, ping_avg, up_count, down_count, uptime_percentage
+			FROM ` + q.table + `
+			WHERE monitor_id = ? AND timestamp >= ? AND timestamp < ?
+			ORDER BY timestamp ASC
+		`
+
+		stats := []StatRow{}
+		if err := db.Raw(query, id, q.from, q.to).Scan(&stats).Error; err != nil {
+			http.Error(w, "Failed to get stats", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"monitor_id":  id,
+			"granularity": q.granularity,
+			"from":        q.from,
+			"to":          q.to,
+			"stats":       stats,
+		})
+	}
+}
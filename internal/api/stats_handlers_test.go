@@ -0,0 +1,107 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestParseStatsQuery(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		query     string
+		wantTable string
+		wantFrom  time.Time
+		wantTo    time.Time
+		wantErr   bool
+	}{
+		{
+			name:      "hourly defaults to last 24h",
+			query:     "granularity=hourly",
+			wantTable: "stat_hourly",
+			wantFrom:  now.Add(-24 * time.Hour),
+			wantTo:    now,
+		},
+		{
+			name:      "daily defaults to last 30 days",
+			query:     "granularity=daily",
+			wantTable: "stat_daily",
+			wantFrom:  now.Add(-30 * 24 * time.Hour),
+			wantTo:    now,
+		},
+		{
+			name:      "missing granularity is hourly",
+			query:     "",
+			wantTable: "stat_hourly",
+			wantFrom:  now.Add(-24 * time.Hour),
+			wantTo:    now,
+		},
+		{
+			name:      "hourly explicit range",
+			query:     "granularity=hourly&from=2026-02-20T00:00:00Z&to=2026-02-21T00:00:00Z",
+			wantTable: "stat_hourly",
+			wantFrom:  time.Date(2026, 2, 20, 0, 0, 0, 0, time.UTC),
+			wantTo:    time.Date(2026, 2, 21, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "daily explicit range",
+			query:     "granularity=daily&from=2025-03-01T00:00:00Z&to=2026-03-01T00:00:00Z",
+			wantTable: "stat_daily",
+			wantFrom:  time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC),
+			wantTo:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "hourly range too large",
+			query:   "granularity=hourly&from=2025-01-01T00:00:00Z&to=2026-01-01T00:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "daily range too large",
+			query:   "granularity=daily&from=2020-01-01T00:00:00Z&to=2026-01-01T00:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "unknown granularity",
+			query:   "granularity=minutely",
+			wantErr: true,
+		},
+		{
+			name:    "from after to",
+			query:   "granularity=daily&from=2026-02-02T00:00:00Z&to=2026-02-01T00:00:00Z",
+			wantErr: true,
+		},
+		{
+			name:    "invalid from",
+			query:   "granularity=daily&from=yesterday",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			values, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQuery: %v", err)
+			}
+
+			got, err := parseStatsQuery(values, now)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error, got %+v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.table != tt.wantTable {
+				t.Errorf("table = %q, want %q", got.table, tt.wantTable)
+			}
+			if !got.from.Equal(tt.wantFrom) || !got.to.Equal(tt.wantTo) {
+				t.Errorf("range = %v..%v, want %v..%v", got.from, got.to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
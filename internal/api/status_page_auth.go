@@ -0,0 +1,133 @@
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// statusPageAccessTTL is how long a status page access token stays valid
+const statusPageAccessTTL = time.Hour
+
+// statusPageAuthResponse is returned by a successful status page login
+type statusPageAuthResponse struct {
+	// Token is sent back in the X-Status-Page-Token header; empty when the
+	// page has no password
+	Token     string     `json:"token,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// hasValidStatusPagePassword reports whether the request may view the page:
+// it has no password, or the request carries the password in
+// X-Status-Page-Password or an access token in X-Status-Page-Token
+func hasValidStatusPagePassword(r *http.Request, page *models.StatusPage) bool {
+	if page.Password == "" {
+		return true
+	}
+	if token := r.Header.Get("X-Status-Page-Token"); token != "" {
+		return validStatusPageAccessToken(page, token, time.Now())
+	}
+	provided := r.Header.Get("X-Status-Page-Password")
+	if provided == "" {
+		return false
+	}
+	return statusPagePasswordMatches(page, provided)
+}
+
+// statusPagePasswordMatches compares a password with the page's bcrypt hash,
+// or with the plain text stored by older versions
+func statusPagePasswordMatches(page *models.StatusPage, provided string) bool {
+	if strings.HasPrefix(page.Password, "$2") {
+		return bcrypt.CompareHashAndPassword([]byte(page.Password), []byte(provided)) == nil
+	}
+	return subtle.ConstantTimeCompare([]byte(page.Password), []byte(provided)) == 1
+}
+
+// statusPageAccessToken returns a token granting access to the page until
+// expires, formatted "<expiry unix seconds>.<signature>". It is signed with
+// the page's stored password, so changing the password revokes it.
+func statusPageAccessToken(page *models.StatusPage, expires time.Time) string {
+	exp := expires.Unix()
+	return strconv.FormatInt(exp, 10) + "." + statusPageAccessSignature(page, exp)
+}
+
+func statusPageAccessSignature(page *models.StatusPage, exp int64) string {
+	mac := hmac.New(sha256.New, []byte(page.Password))
+	fmt.Fprintf(mac, "status-page-access:%d:%d", page.ID, exp)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// validStatusPageAccessToken reports whether token grants access to the page at now
+func validStatusPageAccessToken(page *models.StatusPage, token string, now time.Time) bool {
+	expPart, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return false
+	}
+	exp, err := strconv.ParseInt(expPart, 10, 64)
+	if err != nil || now.Unix() >= exp {
+		return false
+	}
+	return hmac.Equal([]byte(signature), []byte(statusPageAccessSignature(page, exp)))
+}
+
+// HandleStatusPageAuth checks a password for a published status page. It
+// answers 200 with an access token when it matches, so clients can validate
+// the password before rendering the page, and 401 otherwise.
+func HandleStatusPageAuth(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slug := chi.URLParam(r, "slug")
+
+		var page models.StatusPage
+		err := db.Where("slug = ? AND published = ?", slug, true).
+			First(&page).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Status page not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch status page", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		authenticateStatusPage(w, r, &page, time.Now())
+	}
+}
+
+// authenticateStatusPage answers a status page login for a loaded page
+func authenticateStatusPage(w http.ResponseWriter, r *http.Request, page *models.StatusPage, now time.Time) {
+	var req struct {
+		Password string `json:"password"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Invalid request body", http.StatusBadRequest)
+		return
+	}
+
+	var resp statusPageAuthResponse
+	if page.Password != "" {
+		if req.Password == "" || !statusPagePasswordMatches(page, req.Password) {
+			http.Error(w, "Invalid status page password", http.StatusUnauthorized)
+			return
+		}
+		expires := now.Add(statusPageAccessTTL).UTC().Truncate(time.Second)
+		resp.Token = statusPageAccessToken(page, expires)
+		resp.ExpiresAt = &expires
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	json.NewEncoder(w).Encode(resp)
+}
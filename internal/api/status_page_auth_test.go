@@ -0,0 +1,122 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func protectedStatusPage(t *testing.T, password string) *models.StatusPage {
+	t.Helper()
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("GenerateFromPassword: %v", err)
+	}
+	return &models.StatusPage{ID: 3, Slug: "internal", Password: string(hash)}
+}
+
+func postStatusPageAuth(page *models.StatusPage, body string, now time.Time) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/api/status/internal/auth", strings.NewReader(body))
+	w := httptest.NewRecorder()
+	authenticateStatusPage(w, r, page, now)
+	return w
+}
+
+func TestStatusPageAuthCorrectPassword(t *testing.T) {
+	page := protectedStatusPage(t, "letmein")
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	w := postStatusPageAuth(page, `{"password": "letmein"}`, now)
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	var resp statusPageAuthResponse
+	if err := json.NewDecoder(w.Body).Decode(&resp); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if resp.Token == "" || resp.ExpiresAt == nil || !resp.ExpiresAt.Equal(now.Add(statusPageAccessTTL)) {
+		t.Fatalf("response = %+v, want a token expiring in %s", resp, statusPageAccessTTL)
+	}
+
+	// The token opens the page in place of the password until it expires
+	if !validStatusPageAccessToken(page, resp.Token, now.Add(59*time.Minute)) {
+		t.Error("token rejected before it expired")
+	}
+	if validStatusPageAccessToken(page, resp.Token, now.Add(statusPageAccessTTL)) {
+		t.Error("token accepted once expired")
+	}
+
+	r := httptest.NewRequest(http.MethodGet, "/api/status/internal", nil)
+	r.Header.Set("X-Status-Page-Token", resp.Token)
+	if !hasValidStatusPagePassword(r, page) {
+		t.Error("page rejected the fresh token")
+	}
+}
+
+func TestStatusPageAuthIncorrectPassword(t *testing.T) {
+	page := protectedStatusPage(t, "letmein")
+
+	for _, body := range []string{`{"password": "guess"}`, `{"password": ""}`, `{}`} {
+		w := postStatusPageAuth(page, body, time.Now())
+		if w.Code != http.StatusUnauthorized {
+			t.Errorf("%s: status = %d, want 401", body, w.Code)
+		}
+		if strings.Contains(w.Body.String(), "token") {
+			t.Errorf("%s: a rejected login returned %q", body, w.Body.String())
+		}
+	}
+
+	if w := postStatusPageAuth(page, `not json`, time.Now()); w.Code != http.StatusBadRequest {
+		t.Errorf("invalid body: status = %d, want 400", w.Code)
+	}
+}
+
+func TestStatusPageAuthWithoutPassword(t *testing.T) {
+	w := postStatusPageAuth(&models.StatusPage{ID: 4}, `{}`, time.Now())
+	if w.Code != http.StatusOK || strings.TrimSpace(w.Body.String()) != "{}" {
+		t.Errorf("got %d %q, want 200 without a token", w.Code, w.Body.String())
+	}
+}
+
+func TestStatusPageAccessTokenRevokedByPasswordChange(t *testing.T) {
+	page := protectedStatusPage(t, "letmein")
+	expires := time.Now().Add(time.Hour)
+	token := statusPageAccessToken(page, expires)
+
+	tampered := strings.Replace(token, token[:10], "9999999999", 1)
+	if validStatusPageAccessToken(page, tampered, time.Now()) {
+		t.Error("token with an extended expiry was accepted")
+	}
+
+	other := *page
+	other.ID = 5
+	if validStatusPageAccessToken(&other, token, time.Now()) {
+		t.Error("token accepted by another page")
+	}
+
+	changed := protectedStatusPage(t, "new-password")
+	changed.ID = page.ID
+	if validStatusPageAccessToken(changed, token, time.Now()) {
+		t.Error("token survived a password change")
+	}
+}
+
+func TestHasValidStatusPagePasswordPlainText(t *testing.T) {
+	page := &models.StatusPage{Password: "legacy"}
+	r := httptest.NewRequest(http.MethodGet, "/api/status/old", nil)
+	r.Header.Set("X-Status-Page-Password", "legacy")
+	if !hasValidStatusPagePassword(r, page) {
+		t.Error("plain text password rejected")
+	}
+	r.Header.Set("X-Status-Page-Password", "legac")
+	if hasValidStatusPagePassword(r, page) {
+		t.Error("wrong plain text password accepted")
+	}
+}
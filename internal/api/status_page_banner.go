@@ -0,0 +1,49 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// maxBannerLength caps a status page banner, in characters
+const maxBannerLength = 500
+
+// bannerStyles are the banner colors, the same as incident styles
+var bannerStyles = map[string]bool{
+	"info":    true,
+	"warning": true,
+	"danger":  true,
+	"success": true,
+}
+
+// normalizeBanner sanitizes a status page banner and checks its style. The
+// text drops control characters other than line breaks and is trimmed; it is
+// shown as plain text, so markup needs no stripping. The style defaults to
+// info.
+func normalizeBanner(text, style string) (string, string, error) {
+	if !utf8.ValidString(text) {
+		text = strings.ToValidUTF8(text, "")
+	}
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.Map(func(r rune) rune {
+		if r != '\n' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, text)
+	text = strings.TrimSpace(text)
+	if utf8.RuneCountInString(text) > maxBannerLength {
+		return "", "", fmt.Errorf("banner must be at most %d characters", maxBannerLength)
+	}
+
+	style = strings.ToLower(strings.TrimSpace(style))
+	if style == "" {
+		style = "info"
+	}
+	if !bannerStyles[style] {
+		return "", "", fmt.Errorf("banner_style must be info, warning, danger or success")
+	}
+	return text, style, nil
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestStatusPageBannerRoundTrip(t *testing.T) {
+	banner, style, err := normalizeBanner("  Migration in progress this week\r\nExpect short outages\x00  ", "Warning")
+	if err != nil {
+		t.Fatalf("normalizeBanner: %v", err)
+	}
+	if banner != "Migration in progress this week\nExpect short outages" || style != "warning" {
+		t.Fatalf("normalizeBanner = %q, %q", banner, style)
+	}
+	page := models.StatusPage{ID: 4, Slug: "acme", Title: "Acme Status", Banner: banner, BannerStyle: style}
+
+	// The public endpoint returns the page as is
+	data, err := json.Marshal(map[string]interface{}{"page": page})
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var public struct {
+		Page struct {
+			Banner      string `json:"banner"`
+			BannerStyle string `json:"banner_style"`
+		} `json:"page"`
+	}
+	if err := json.Unmarshal(data, &public); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if public.Page.Banner != banner || public.Page.BannerStyle != "warning" {
+		t.Errorf("public page banner = %q (%s), want %q (warning)", public.Page.Banner, public.Page.BannerStyle, banner)
+	}
+
+	// And exports carry it to the imported page
+	data, err = json.Marshal(buildStatusPageExport(page, nil, nil, time.Now()))
+	if err != nil {
+		t.Fatalf("marshal export: %v", err)
+	}
+	var imported StatusPageExport
+	if err := json.Unmarshal(data, &imported); err != nil {
+		t.Fatalf("unmarshal export: %v", err)
+	}
+	text, style, err := normalizeBanner(imported.Page.Banner, imported.Page.BannerStyle)
+	if err != nil || text != banner || style != "warning" {
+		t.Errorf("imported banner = %q, %q, %v", text, style, err)
+	}
+}
+
+func TestNormalizeBanner(t *testing.T) {
+	text, style, err := normalizeBanner("", "")
+	if text != "" || style != "info" || err != nil {
+		t.Errorf("no banner = %q, %q, %v; want empty with info style", text, style, err)
+	}
+
+	if _, _, err := normalizeBanner("Upgrade tonight", "blink"); err == nil {
+		t.Error("unknown style accepted")
+	}
+	if _, _, err := normalizeBanner(strings.Repeat("é", maxBannerLength+1), "info"); err == nil {
+		t.Error("overlong banner accepted")
+	}
+	if _, _, err := normalizeBanner(strings.Repeat("é", maxBannerLength), "info"); err != nil {
+		t.Errorf("banner at the limit: %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// normalizeCustomDomain validates a status page custom domain and returns it
+// lowercased without a trailing dot. An empty domain returns nil, which
+// removes the custom domain.
+func normalizeCustomDomain(raw string) (*string, error) {
+	domain := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(raw)), ".")
+	if domain == "" {
+		return nil, nil
+	}
+
+	if len(domain) > 253 {
+		return nil, fmt.Errorf("custom_domain must be at most 253 characters")
+	}
+	if net.ParseIP(domain) != nil {
+		return nil, fmt.Errorf("custom_domain must be a host name, not an IP address")
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return nil, fmt.Errorf("custom_domain must be a fully qualified host name such as status.example.com")
+	}
+	for _, label := range labels {
+		if !isValidDomainLabel(label) {
+			return nil, fmt.Errorf("custom_domain %q is not a valid host name", raw)
+		}
+	}
+
+	return &domain, nil
+}
+
+// isValidDomainLabel reports whether label is a valid LDH host name label
+func isValidDomainLabel(label string) bool {
+	if len(label) == 0 || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+		return false
+	}
+	for _, c := range label {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// statusPageHost returns the host a public request was made to, normalized
+// like a stored custom domain. The web frontend proxies API calls, so the
+// original host arrives in X-Forwarded-Host. Trusting it is safe here: it only
+// selects which published page to show, and every published page is already
+// reachable by slug.
+func statusPageHost(r *http.Request) string {
+	host := r.Header.Get("X-Forwarded-Host")
+	if host == "" {
+		host = r.Host
+	}
+	// A proxy chain may list several hosts; the first is the client's
+	if i := strings.Index(host, ","); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.TrimSpace(host)
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	return strings.TrimSuffix(strings.ToLower(host), ".")
+}
+
+// customDomainTaken reports whether another status page already uses domain
+func customDomainTaken(db *gorm.DB, domain string, excludePageID interface{}) (bool, error) {
+	query := db.Model(&models.StatusPage{}).Where("custom_domain = ?", domain)
+	if excludePageID != nil {
+		query = query.Where("id != ?", excludePageID)
+	}
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+// HandleGetPublicStatusPageByDomain serves the public status page whose
+// custom domain matches the request host. The slug is returned in the
+// X-Status-Page-Slug header, including for password protected pages, so the
+// frontend can load the page's other public endpoints.
+func HandleGetPublicStatusPageByDomain(db *gorm.DB) http.HandlerFunc {
+	servePage := HandleGetPublicStatusPage(db)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		host := statusPageHost(r)
+		if host == "" {
+			http.Error(w, "Status page not found", http.StatusNotFound)
+			return
+		}
+
+		var page models.StatusPage
+		err := db.Select("slug").
+			Where("custom_domain = ? AND published = ?", host, true).
+			First(&page).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Status page not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch status page", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.Header().Set("X-Status-Page-Slug", page.Slug)
+
+		rctx := chi.RouteContext(r.Context())
+		if rctx == nil {
+			rctx = chi.NewRouteContext()
+			r = r.WithContext(context.WithValue(r.Context(), chi.RouteCtxKey, rctx))
+		}
+		rctx.URLParams.Add("slug", page.Slug)
+		servePage(w, r)
+	}
+}
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNormalizeCustomDomain(t *testing.T) {
+	valid := map[string]string{
+		"status.mycompany.com":   "status.mycompany.com",
+		" Status.MyCompany.COM ": "status.mycompany.com",
+		"status.mycompany.com.":  "status.mycompany.com",
+		"xn--bcher-kva.example":  "xn--bcher-kva.example",
+		"a-b.c1.example.org":     "a-b.c1.example.org",
+	}
+	for raw, want := range valid {
+		got, err := normalizeCustomDomain(raw)
+		if err != nil || got == nil || *got != want {
+			t.Errorf("normalizeCustomDomain(%q) = %v, %v, want %q", raw, got, err, want)
+		}
+	}
+
+	if got, err := normalizeCustomDomain("  "); got != nil || err != nil {
+		t.Errorf("empty domain = %v, %v, want nil to clear it", got, err)
+	}
+
+	invalid := []string{
+		"localhost",
+		"status.mycompany.com:8443",
+		"https://status.mycompany.com",
+		"status..mycompany.com",
+		"-status.mycompany.com",
+		"status_page.mycompany.com",
+		"192.168.1.10",
+		"::1",
+	}
+	for _, raw := range invalid {
+		if got, err := normalizeCustomDomain(raw); err == nil {
+			t.Errorf("normalizeCustomDomain(%q) = %q, want error", raw, *got)
+		}
+	}
+}
+
+func TestStatusPageHostMatchesStoredDomain(t *testing.T) {
+	stored, err := normalizeCustomDomain("Status.MyCompany.com")
+	if err != nil {
+		t.Fatalf("normalizeCustomDomain: %v", err)
+	}
+
+	tests := []struct {
+		name          string
+		host          string
+		forwardedHost string
+		want          string
+	}{
+		{name: "direct request", host: "status.mycompany.com", want: *stored},
+		{name: "host with port and mixed case", host: "STATUS.mycompany.com:443", want: *stored},
+		{name: "proxied through the frontend", host: "backend:8080", forwardedHost: "status.mycompany.com", want: *stored},
+		{name: "proxy chain uses the first host", host: "backend:8080", forwardedHost: "status.mycompany.com, edge.internal", want: *stored},
+		{name: "other host does not match", host: "uptime.mycompany.com", want: "uptime.mycompany.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/api/status-domain", nil)
+			r.Host = tt.host
+			if tt.forwardedHost != "" {
+				r.Header.Set("X-Forwarded-Host", tt.forwardedHost)
+			}
+			if got := statusPageHost(r); got != tt.want {
+				t.Errorf("statusPageHost() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
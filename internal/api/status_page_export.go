@@ -0,0 +1,358 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// statusPageExportVersion is bumped when the export document changes shape
+const statusPageExportVersion = 1
+
+// maxStatusPageImportSize bounds the import request body
+const maxStatusPageImportSize = 5 << 20
+
+// StatusPageExport is a self-contained backup of a status page. The password
+// hash is never exported; supply a new password on import instead.
+type StatusPageExport struct {
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+
+	Page      StatusPageExportPage       `json:"page"`
+	Monitors  []StatusPageExportMonitor  `json:"monitors"`
+	Incidents []StatusPageExportIncident `json:"incidents"`
+}
+
+// StatusPageExportPage holds the page settings and theme
+type StatusPageExportPage struct {
+	Slug                 string `json:"slug"`
+	Title                string `json:"title"`
+	Description          string `json:"description"`
+	Published            bool   `json:"published"`
+	ShowPoweredBy        bool   `json:"show_powered_by"`
+	Theme                string `json:"theme"`
+	CustomCSS            string `json:"custom_css"`
+	ConfirmationChecks   int    `json:"confirmation_checks"`
+	PasswordProtected    bool   `json:"password_protected"`
+	ShowUptimePercentage bool   `json:"show_uptime_percentage"` // true when missing from older exports
+	Banner               string `json:"banner,omitempty"`
+	BannerStyle          string `json:"banner_style,omitempty"`
+}
+
+// StatusPageExportMonitor is a monitor shown on the page. On import it is
+// matched by ID first, then by name, among the importing user's monitors.
+type StatusPageExportMonitor struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	DisplayOrder int    `json:"display_order"`
+}
+
+// StatusPageExportIncident is an incident posted on the page
+type StatusPageExportIncident struct {
+	Title            string     `json:"title"`
+	Content          string     `json:"content"`
+	Style            string     `json:"style"`
+	Pin              bool       `json:"pin"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+	AutoResolveAfter *int       `json:"auto_resolve_after,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+}
+
+// buildStatusPageExport assembles the export document. monitors must be in
+// display order.
+func buildStatusPageExport(page models.StatusPage, monitors []models.Monitor, incidents []models.Incident, now time.Time) StatusPageExport {
+	export := StatusPageExport{
+		Version:    statusPageExportVersion,
+		ExportedAt: now,
+		Page: StatusPageExportPage{
+			Slug:                 page.Slug,
+			Title:                page.Title,
+			Description:          page.Description,
+			Published:            page.Published,
+			ShowPoweredBy:        page.ShowPoweredBy,
+			Theme:                page.Theme,
+			CustomCSS:            page.CustomCSS,
+			ConfirmationChecks:   page.ConfirmationChecks,
+			PasswordProtected:    page.Password != "",
+			ShowUptimePercentage: page.ShowUptimePercentage,
+			Banner:               page.Banner,
+			BannerStyle:          page.BannerStyle,
+		},
+		Monitors:  make([]StatusPageExportMonitor, 0, len(monitors)),
+		Incidents: make([]StatusPageExportIncident, 0, len(incidents)),
+	}
+
+	for i, m := range monitors {
+		export.Monitors = append(export.Monitors, StatusPageExportMonitor{
+			ID:           m.ID,
+			Name:         m.Name,
+			DisplayOrder: i,
+		})
+	}
+
+	for _, incident := range incidents {
+		export.Incidents = append(export.Incidents, StatusPageExportIncident{
+			Title:            incident.Title,
+			Content:          incident.Content,
+			Style:            incident.Style,
+			Pin:              incident.Pin,
+			CreatedAt:        incident.CreatedAt,
+			UpdatedAt:        incident.UpdatedAt,
+			AutoResolveAfter: incident.AutoResolveAfter,
+			ResolvedAt:       incident.ResolvedAt,
+		})
+	}
+
+	return export
+}
+
+// resolveImportMonitors maps exported monitors onto the importing user's
+// monitors, by ID and then by name. It returns the matched monitor IDs in
+// display order and the names of monitors that could not be matched.
+func resolveImportMonitors(exported []StatusPageExportMonitor, owned []models.Monitor) (ids []int, missing []string) {
+	byID := make(map[int]bool, len(owned))
+	byName := make(map[string]int, len(owned))
+	for _, m := range owned {
+		byID[m.ID] = true
+		if _, ok := byName[m.Name]; !ok {
+			byName[m.Name] = m.ID
+		}
+	}
+
+	used := make(map[int]bool, len(exported))
+	for _, m := range sortedByDisplayOrder(exported) {
+		id := 0
+		if byID[m.ID] {
+			id = m.ID
+		} else if named, ok := byName[m.Name]; ok {
+			id = named
+		}
+		if id == 0 || used[id] {
+			missing = append(missing, m.Name)
+			continue
+		}
+		used[id] = true
+		ids = append(ids, id)
+	}
+
+	return ids, missing
+}
+
+// sortedByDisplayOrder returns a copy of monitors ordered by DisplayOrder
+func sortedByDisplayOrder(monitors []StatusPageExportMonitor) []StatusPageExportMonitor {
+	sorted := make([]StatusPageExportMonitor, len(monitors))
+	copy(sorted, monitors)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].DisplayOrder < sorted[j].DisplayOrder
+	})
+	return sorted
+}
+
+// availableSlug returns slug if it is free, otherwise the first free
+// slug-2, slug-3, ...
+func availableSlug(slug string, taken func(string) bool) string {
+	if !taken(slug) {
+		return slug
+	}
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", slug, i)
+		if !taken(candidate) {
+			return candidate
+		}
+	}
+}
+
+// HandleExportStatusPage returns a status page with its monitors and incidents as JSON
+func HandleExportStatusPage(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		pageID := chi.URLParam(r, "id")
+
+		var page models.StatusPage
+		err := db.Where("id = ? AND user_id = ?", pageID, user.ID).
+			First(&page).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Status page not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch status page", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		var monitors []models.Monitor
+		if err := db.Joins("INNER JOIN status_page_monitors spm ON monitors.id = spm.monitor_id").
+			Where("spm.status_page_id = ?", page.ID).
+			Order("spm.display_order ASC, monitors.name ASC").
+			Find(&monitors).Error; err != nil {
+			http.Error(w, "Failed to fetch status page monitors", http.StatusInternalServerError)
+			return
+		}
+
+		var incidents []models.Incident
+		if err := db.Where("status_page_id = ?", page.ID).
+			Order("created_at ASC").
+			Find(&incidents).Error; err != nil {
+			http.Error(w, "Failed to fetch incidents", http.StatusInternalServerError)
+			return
+		}
+
+		export := buildStatusPageExport(page, monitors, incidents, time.Now())
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="status-page-%s.json"`, page.Slug))
+		json.NewEncoder(w).Encode(export)
+	}
+}
+
+// HandleImportStatusPage recreates a status page from an export document.
+// A taken slug gets a numeric suffix and monitors the user doesn't own are skipped.
+func HandleImportStatusPage(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+
+		var req struct {
+			StatusPageExport
+			Password string `json:"password"` // optional new password; hashes are never exported
+		}
+
+		// Exports from before show_uptime_percentage keep showing uptime
+		req.Page.ShowUptimePercentage = true
+		r.Body = http.MaxBytesReader(w, r.Body, maxStatusPageImportSize)
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		if req.Version != statusPageExportVersion {
+			http.Error(w, fmt.Sprintf("Unsupported export version %d", req.Version), http.StatusBadRequest)
+			return
+		}
+		if req.Page.Slug == "" || req.Page.Title == "" {
+			http.Error(w, "Export is missing the page slug or title", http.StatusBadRequest)
+			return
+		}
+
+		if !isAdminUser(user.ID) && req.Page.CustomCSS != "" {
+			http.Error(w, "Custom CSS requires admin access", http.StatusForbidden)
+			return
+		}
+
+		confirmationChecks, err := normalizeConfirmationChecks(req.Page.ConfirmationChecks)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		banner, bannerStyle, err := normalizeBanner(req.Page.Banner, req.Page.BannerStyle)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var owned []models.Monitor
+		if err := db.Where("user_id = ?", user.ID).Find(&owned).Error; err != nil {
+			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
+			return
+		}
+		monitorIDs, missing := resolveImportMonitors(req.Monitors, owned)
+
+		now := time.Now()
+		page := models.StatusPage{
+			UserID:        user.ID,
+			Title:         req.Page.Title,
+			Description:   req.Page.Description,
+			Published:     req.Page.Published,
+			ShowPoweredBy: req.Page.ShowPoweredBy,
+			Theme:         req.Page.Theme,
+			CustomCSS:     sanitizeCustomCSS(req.Page.CustomCSS),
+			CreatedAt:     now,
+			UpdatedAt:     now,
+
+			ConfirmationChecks:   confirmationChecks,
+			ShowUptimePercentage: req.Page.ShowUptimePercentage,
+			Banner:               banner,
+			BannerStyle:          bannerStyle,
+		}
+
+		if req.Password != "" {
+			hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+			if err != nil {
+				http.Error(w, "Failed to set password", http.StatusInternalServerError)
+				return
+			}
+			page.Password = string(hashed)
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			page.Slug = availableSlug(req.Page.Slug, func(slug string) bool {
+				var count int64
+				tx.Model(&models.StatusPage{}).Where("slug = ?", slug).Count(&count)
+				return count > 0
+			})
+
+			if err := tx.Create(&page).Error; err != nil {
+				return err
+			}
+
+			for i, monitorID := range monitorIDs {
+				spm := models.StatusPageMonitor{
+					StatusPageID: page.ID,
+					MonitorID:    monitorID,
+					DisplayOrder: i,
+				}
+				if err := tx.Create(&spm).Error; err != nil {
+					return err
+				}
+			}
+
+			for _, exported := range req.Incidents {
+				if exported.CreatedAt.IsZero() {
+					exported.CreatedAt = now
+				}
+				if exported.UpdatedAt.IsZero() {
+					exported.UpdatedAt = exported.CreatedAt
+				}
+				incident := models.Incident{
+					StatusPageID: page.ID,
+					Title:        exported.Title,
+					Content:      exported.Content,
+					Style:        exported.Style,
+					Pin:          exported.Pin,
+					CreatedAt:    exported.CreatedAt,
+					UpdatedAt:    exported.UpdatedAt,
+
+					AutoResolveAfter: exported.AutoResolveAfter,
+					ResolvedAt:       exported.ResolvedAt,
+				}
+				if err := tx.Create(&incident).Error; err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+		if err != nil {
+			http.Error(w, "Failed to import status page", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"page":             page,
+			"skipped_monitors": missing,
+			// The original page was protected but no new password was given
+			"password_missing": req.Page.PasswordProtected && req.Password == "",
+		})
+	}
+}
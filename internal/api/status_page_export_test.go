@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestStatusPageExportRoundTrip(t *testing.T) {
+	now := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	hour := 3600
+	page := models.StatusPage{
+		ID:                 4,
+		Slug:               "acme",
+		Title:              "Acme Status",
+		Description:        "Production services",
+		Published:          true,
+		ShowPoweredBy:      false,
+		Theme:              "dark",
+		CustomCSS:          "body { color: red; }",
+		Password:           "$2a$10$secret-hash",
+		ConfirmationChecks: 3,
+	}
+	monitors := []models.Monitor{{ID: 12, Name: "API"}, {ID: 7, Name: "Website"}}
+	incidents := []models.Incident{{
+		Title:            "Degraded API",
+		Content:          "Investigating",
+		Style:            "warning",
+		Pin:              true,
+		CreatedAt:        now.Add(-time.Hour),
+		UpdatedAt:        now,
+		AutoResolveAfter: &hour,
+	}}
+
+	data, err := json.Marshal(buildStatusPageExport(page, monitors, incidents, now))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	if strings.Contains(string(data), "secret-hash") {
+		t.Fatal("export contains the password hash")
+	}
+
+	var imported StatusPageExport
+	if err := json.Unmarshal(data, &imported); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+
+	want := StatusPageExportPage{
+		Slug:               "acme",
+		Title:              "Acme Status",
+		Description:        "Production services",
+		Published:          true,
+		Theme:              "dark",
+		CustomCSS:          "body { color: red; }",
+		ConfirmationChecks: 3,
+		PasswordProtected:  true,
+	}
+	if imported.Page != want {
+		t.Errorf("page = %+v, want %+v", imported.Page, want)
+	}
+
+	ids, missing := resolveImportMonitors(imported.Monitors, monitors)
+	if !reflect.DeepEqual(ids, []int{12, 7}) || len(missing) != 0 {
+		t.Errorf("monitors = %v (missing %v), want [12 7] in display order", ids, missing)
+	}
+
+	if len(imported.Incidents) != 1 {
+		t.Fatalf("incidents = %d, want 1", len(imported.Incidents))
+	}
+	got := imported.Incidents[0]
+	if got.Title != "Degraded API" || got.Style != "warning" || !got.Pin ||
+		!got.CreatedAt.Equal(now.Add(-time.Hour)) || got.AutoResolveAfter == nil || *got.AutoResolveAfter != hour {
+		t.Errorf("incident = %+v", got)
+	}
+}
+
+func TestResolveImportMonitorsOnAnotherInstance(t *testing.T) {
+	exported := []StatusPageExportMonitor{
+		{ID: 12, Name: "API", DisplayOrder: 1},
+		{ID: 7, Name: "Website", DisplayOrder: 0},
+		{ID: 99, Name: "Legacy", DisplayOrder: 2},
+	}
+	// Same names, different IDs; monitor 99 belongs to nobody here
+	owned := []models.Monitor{{ID: 3, Name: "API"}, {ID: 4, Name: "Website"}}
+
+	ids, missing := resolveImportMonitors(exported, owned)
+	if !reflect.DeepEqual(ids, []int{4, 3}) {
+		t.Errorf("ids = %v, want [4 3]", ids)
+	}
+	if !reflect.DeepEqual(missing, []string{"Legacy"}) {
+		t.Errorf("missing = %v, want [Legacy]", missing)
+	}
+}
+
+func TestAvailableSlug(t *testing.T) {
+	taken := map[string]bool{"acme": true, "acme-2": true}
+	isTaken := func(slug string) bool { return taken[slug] }
+
+	if got := availableSlug("status", isTaken); got != "status" {
+		t.Errorf("availableSlug(status) = %q, want status", got)
+	}
+	if got := availableSlug("acme", isTaken); got != "acme-3" {
+		t.Errorf("availableSlug(acme) = %q, want acme-3", got)
+	}
+}
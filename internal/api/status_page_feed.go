@@ -0,0 +1,217 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+const (
+	// incidentFeedTTL is how long a rendered incident feed is served from cache
+	incidentFeedTTL = time.Minute
+	// incidentFeedLimit is how many of the newest incidents a feed lists
+	incidentFeedLimit = 50
+	// maxCachedIncidentFeeds bounds the cache, whose keys include the
+	// request's host
+	maxCachedIncidentFeeds = 1000
+)
+
+// rssFeed is an RSS 2.0 document
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	TTL           int       `xml:"ttl"` // minutes
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link"`
+	Description string  `xml:"description"`
+	Category    string  `xml:"category,omitempty"`
+	PubDate     string  `xml:"pubDate"`
+	GUID        rssGUID `xml:"guid"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// feedCacheEntry is a rendered feed and when it stops being served
+type feedCacheEntry struct {
+	body    []byte
+	expires time.Time
+}
+
+// incidentFeedCache holds rendered feeds by page and link, so feed readers
+// polling a page don't query the database each time
+type incidentFeedCache struct {
+	mu      sync.Mutex
+	entries map[string]feedCacheEntry
+}
+
+func newIncidentFeedCache() *incidentFeedCache {
+	return &incidentFeedCache{entries: make(map[string]feedCacheEntry)}
+}
+
+// get returns the cached feed for key unless it has expired
+func (c *incidentFeedCache) get(key string, now time.Time) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[key]
+	if !ok || !now.Before(entry.expires) {
+		return nil, false
+	}
+	return entry.body, true
+}
+
+// put caches a feed for incidentFeedTTL, dropping expired entries. Once the
+// cache is full, feeds are rendered per request until entries expire.
+func (c *incidentFeedCache) put(key string, body []byte, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, k)
+		}
+	}
+	if len(c.entries) >= maxCachedIncidentFeeds {
+		return
+	}
+	c.entries[key] = feedCacheEntry{body: body, expires: now.Add(incidentFeedTTL)}
+}
+
+// statusPageURL returns the public address of a status page: its custom
+// domain, or its slug on the host the request was made to
+func statusPageURL(r *http.Request, page *models.StatusPage) string {
+	if page.CustomDomain != nil && *page.CustomDomain != "" {
+		return "https://" + *page.CustomDomain + "/"
+	}
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "https" || proto == "http" {
+		scheme = proto
+	}
+	host := r.Header.Get("X-Forwarded-Host")
+	if host == "" {
+		host = r.Host
+	}
+	if i := strings.Index(host, ","); i >= 0 {
+		host = host[:i]
+	}
+	return fmt.Sprintf("%s://%s/status/%s", scheme, strings.TrimSpace(host), page.Slug)
+}
+
+// buildIncidentFeed renders incidents, newest first, as an RSS feed for a page
+func buildIncidentFeed(page *models.StatusPage, incidents []models.Incident, link string, now time.Time) ([]byte, error) {
+	description := page.Description
+	if description == "" {
+		description = "Incidents on the " + page.Title + " status page"
+	}
+	feed := rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         page.Title + " incidents",
+			Link:          link,
+			Description:   description,
+			LastBuildDate: now.UTC().Format(time.RFC1123Z),
+			TTL:           int(incidentFeedTTL / time.Minute),
+		},
+	}
+
+	for _, incident := range incidents {
+		title := incident.Title
+		if incident.ResolvedAt != nil {
+			title = "[Resolved] " + title
+		}
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{
+			Title:       title,
+			Link:        fmt.Sprintf("%s#incident-%d", link, incident.ID),
+			Description: incident.Content,
+			Category:    incident.Style,
+			PubDate:     incident.CreatedAt.UTC().Format(time.RFC1123Z),
+			GUID:        rssGUID{Value: fmt.Sprintf("status-page-%d-incident-%d", page.ID, incident.ID)},
+		})
+	}
+
+	body, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// HandleStatusPageIncidentFeed serves a published status page's incidents as
+// an RSS feed. Password protected pages need the same X-Status-Page-Password
+// or X-Status-Page-Token header as the page itself.
+func HandleStatusPageIncidentFeed(db *gorm.DB) http.HandlerFunc {
+	cache := newIncidentFeedCache()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		slug := chi.URLParam(r, "slug")
+
+		var page models.StatusPage
+		err := db.Where("slug = ? AND published = ?", slug, true).
+			First(&page).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Status page not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch status page", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if !hasValidStatusPagePassword(r, &page) {
+			http.Error(w, "Status page password required", http.StatusUnauthorized)
+			return
+		}
+
+		now := time.Now()
+		link := statusPageURL(r, &page)
+		key := fmt.Sprintf("%d|%s", page.ID, link)
+		body, ok := cache.get(key, now)
+		if !ok {
+			var incidents []models.Incident
+			if err := db.Where("status_page_id = ?", page.ID).
+				Order("created_at DESC").
+				Limit(incidentFeedLimit).
+				Find(&incidents).Error; err != nil {
+				http.Error(w, "Failed to fetch incidents", http.StatusInternalServerError)
+				return
+			}
+			if body, err = buildIncidentFeed(&page, incidents, link, now); err != nil {
+				http.Error(w, "Failed to render feed", http.StatusInternalServerError)
+				return
+			}
+			cache.put(key, body, now)
+		}
+
+		cacheControl := fmt.Sprintf("public, max-age=%d", int(incidentFeedTTL/time.Second))
+		if page.Password != "" {
+			cacheControl = fmt.Sprintf("private, max-age=%d", int(incidentFeedTTL/time.Second))
+		}
+		w.Header().Set("Content-Type", "application/rss+xml; charset=utf-8")
+		w.Header().Set("Cache-Control", cacheControl)
+		w.Write(body)
+	}
+}
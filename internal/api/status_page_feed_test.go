@@ -0,0 +1,92 @@
+package api
+
+import (
+	"encoding/xml"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestBuildIncidentFeed(t *testing.T) {
+	page := &models.StatusPage{ID: 2, Slug: "acme", Title: "Acme & Co"}
+	created := time.Date(2026, 10, 15, 14, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+	resolved := created.Add(time.Hour)
+	incidents := []models.Incident{
+		{ID: 9, Title: "API <errors> & timeouts", Content: "Requests to /v1 fail with 502 <b>\"bad gateway\"</b>", Style: "danger", CreatedAt: created},
+		{ID: 4, Title: "Scheduled maintenance", Content: "Done", Style: "info", CreatedAt: created.AddDate(0, 0, -7), ResolvedAt: &resolved},
+	}
+	now := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	body, err := buildIncidentFeed(page, incidents, "https://status.acme.test/status/acme", now)
+	if err != nil {
+		t.Fatalf("buildIncidentFeed: %v", err)
+	}
+	if !strings.HasPrefix(string(body), "<?xml") {
+		t.Errorf("feed doesn't start with an XML declaration: %.40s", body)
+	}
+	if strings.Contains(string(body), "<b>") || strings.Contains(string(body), "<errors>") {
+		t.Error("incident text was not escaped")
+	}
+
+	var feed rssFeed
+	if err := xml.Unmarshal(body, &feed); err != nil {
+		t.Fatalf("feed does not parse: %v", err)
+	}
+	if feed.Version != "2.0" || feed.Channel.Title != "Acme & Co incidents" || feed.Channel.Link != "https://status.acme.test/status/acme" {
+		t.Errorf("channel = %+v", feed.Channel)
+	}
+	if len(feed.Channel.Items) != 2 {
+		t.Fatalf("got %d items, want 2", len(feed.Channel.Items))
+	}
+
+	latest := feed.Channel.Items[0]
+	if latest.Title != incidents[0].Title || latest.Description != incidents[0].Content || latest.Category != "danger" {
+		t.Errorf("latest item = %+v", latest)
+	}
+	if latest.PubDate != "Thu, 15 Oct 2026 12:30:00 +0000" {
+		t.Errorf("pubDate = %q, want RFC 1123 in UTC", latest.PubDate)
+	}
+	if _, err := time.Parse(time.RFC1123Z, feed.Channel.LastBuildDate); err != nil {
+		t.Errorf("lastBuildDate %q: %v", feed.Channel.LastBuildDate, err)
+	}
+	if latest.GUID.Value != "status-page-2-incident-9" || latest.GUID.IsPermaLink || latest.Link != "https://status.acme.test/status/acme#incident-9" {
+		t.Errorf("latest item identity = %+v, %q", latest.GUID, latest.Link)
+	}
+	if feed.Channel.Items[1].Title != "[Resolved] Scheduled maintenance" {
+		t.Errorf("resolved item title = %q", feed.Channel.Items[1].Title)
+	}
+}
+
+func TestIncidentFeedCache(t *testing.T) {
+	cache := newIncidentFeedCache()
+	now := time.Now()
+
+	cache.put("2|a", []byte("feed"), now)
+	if body, ok := cache.get("2|a", now.Add(incidentFeedTTL-time.Second)); !ok || string(body) != "feed" {
+		t.Errorf("fresh entry = %q, %v", body, ok)
+	}
+	if _, ok := cache.get("2|a", now.Add(incidentFeedTTL)); ok {
+		t.Error("expired entry was served")
+	}
+	if _, ok := cache.get("3|a", now); ok {
+		t.Error("another page's key was served")
+	}
+}
+
+func TestStatusPageURL(t *testing.T) {
+	r := httptest.NewRequest("GET", "/api/status/acme/incidents.rss", nil)
+	r.Host = "backend:8080"
+	r.Header.Set("X-Forwarded-Host", "status.acme.test")
+	r.Header.Set("X-Forwarded-Proto", "https")
+	if got := statusPageURL(r, &models.StatusPage{Slug: "acme"}); got != "https://status.acme.test/status/acme" {
+		t.Errorf("statusPageURL = %q", got)
+	}
+
+	domain := "status.acme.com"
+	if got := statusPageURL(r, &models.StatusPage{Slug: "acme", CustomDomain: &domain}); got != "https://status.acme.com/" {
+		t.Errorf("statusPageURL with custom domain = %q", got)
+	}
+}
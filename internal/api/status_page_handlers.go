@@ -2,7 +2,9 @@ package api
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +14,7 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/uptime"
 )
 
 func isAdminUser(userID int) bool {
@@ -27,20 +30,6 @@ func sanitizeCustomCSS(css string) string {
 	return css
 }
 
-func hasValidStatusPagePassword(r *http.Request, page *models.StatusPage) bool {
-	if page.Password == "" {
-		return true
-	}
-	provided := r.Header.Get("X-Status-Page-Password")
-	if provided == "" {
-		return false
-	}
-	if strings.HasPrefix(page.Password, "$2") {
-		return bcrypt.CompareHashAndPassword([]byte(page.Password), []byte(provided)) == nil
-	}
-	return page.Password == provided
-}
-
 // HandleGetStatusPages returns all status pages for the current user
 func HandleGetStatusPages(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -90,8 +79,11 @@ func HandleGetStatusPage(db *gorm.DB) http.HandlerFunc {
 		var monitors []models.Monitor
 		db.Joins("INNER JOIN status_page_monitors spm ON monitors.id = spm.monitor_id").
 			Where("spm.status_page_id = ?", pageID).
-			Order("monitors.name ASC").
+			Order("spm.display_order ASC, monitors.name ASC").
 			Find(&monitors)
+		for i := range monitors {
+			monitors[i].Config = redactMonitorConfig(monitors[i].Config)
+		}
 
 		result := models.StatusPageWithMonitors{
 			StatusPage: page,
@@ -121,6 +113,12 @@ func HandleCreateStatusPage(db *gorm.DB) http.HandlerFunc {
 			CustomCSS     string `json:"custom_css"`
 			Password      string `json:"password"`
 			MonitorIDs    []int  `json:"monitor_ids"`
+
+			ConfirmationChecks   int    `json:"confirmation_checks"`
+			CustomDomain         string `json:"custom_domain"`
+			ShowUptimePercentage *bool  `json:"show_uptime_percentage"` // default true
+			Banner               string `json:"banner"`
+			BannerStyle          string `json:"banner_style"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -133,6 +131,24 @@ func HandleCreateStatusPage(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		confirmationChecks, err := normalizeConfirmationChecks(req.ConfirmationChecks)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		banner, bannerStyle, err := normalizeBanner(req.Banner, req.BannerStyle)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		customDomain, err := normalizeCustomDomain(req.CustomDomain)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Validate slug is unique
 		var count int64
 		db.Model(&models.StatusPage{}).
@@ -143,6 +159,18 @@ func HandleCreateStatusPage(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		if customDomain != nil {
+			taken, err := customDomainTaken(db, *customDomain, nil)
+			if err != nil {
+				http.Error(w, "Failed to check custom domain", http.StatusInternalServerError)
+				return
+			}
+			if taken {
+				http.Error(w, "Custom domain already in use", http.StatusConflict)
+				return
+			}
+		}
+
 		// Create status page
 		now := time.Now()
 		page := models.StatusPage{
@@ -156,6 +184,12 @@ func HandleCreateStatusPage(db *gorm.DB) http.HandlerFunc {
 			CustomCSS:     sanitizeCustomCSS(req.CustomCSS),
 			CreatedAt:     now,
 			UpdatedAt:     now,
+
+			ConfirmationChecks:   confirmationChecks,
+			CustomDomain:         customDomain,
+			ShowUptimePercentage: req.ShowUptimePercentage == nil || *req.ShowUptimePercentage,
+			Banner:               banner,
+			BannerStyle:          bannerStyle,
 		}
 
 		if req.Password != "" {
@@ -167,7 +201,7 @@ func HandleCreateStatusPage(db *gorm.DB) http.HandlerFunc {
 			page.Password = string(hashed)
 		}
 
-		err := db.Transaction(func(tx *gorm.DB) error {
+		err = db.Transaction(func(tx *gorm.DB) error {
 			// Create status page
 			if err := tx.Create(&page).Error; err != nil {
 				return err
@@ -175,10 +209,11 @@ func HandleCreateStatusPage(db *gorm.DB) http.HandlerFunc {
 
 			// Add monitors to status page
 			if len(req.MonitorIDs) > 0 {
-				for _, monitorID := range req.MonitorIDs {
+				for i, monitorID := range req.MonitorIDs {
 					spm := models.StatusPageMonitor{
 						StatusPageID: page.ID,
 						MonitorID:    monitorID,
+						DisplayOrder: i,
 					}
 					if err := tx.Create(&spm).Error; err != nil {
 						// Log error but continue
@@ -217,6 +252,12 @@ func HandleUpdateStatusPage(db *gorm.DB) http.HandlerFunc {
 			CustomCSS     string `json:"custom_css"`
 			Password      string `json:"password"`
 			MonitorIDs    []int  `json:"monitor_ids"`
+
+			ConfirmationChecks   *int    `json:"confirmation_checks"`    // unchanged when left out
+			CustomDomain         *string `json:"custom_domain"`          // unchanged when left out
+			ShowUptimePercentage *bool   `json:"show_uptime_percentage"` // default true
+			Banner               string  `json:"banner"`
+			BannerStyle          string  `json:"banner_style"`
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -229,6 +270,22 @@ func HandleUpdateStatusPage(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		var confirmationChecks int
+		if req.ConfirmationChecks != nil {
+			n, err := normalizeConfirmationChecks(*req.ConfirmationChecks)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			confirmationChecks = n
+		}
+
+		banner, bannerStyle, err := normalizeBanner(req.Banner, req.BannerStyle)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
 		// Verify ownership
 		var count int64
 		db.Model(&models.StatusPage{}).
@@ -248,8 +305,28 @@ func HandleUpdateStatusPage(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		var customDomain *string
+		if req.CustomDomain != nil {
+			customDomain, err = normalizeCustomDomain(*req.CustomDomain)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if customDomain != nil {
+			taken, err := customDomainTaken(db, *customDomain, pageID)
+			if err != nil {
+				http.Error(w, "Failed to check custom domain", http.StatusInternalServerError)
+				return
+			}
+			if taken {
+				http.Error(w, "Custom domain already in use", http.StatusConflict)
+				return
+			}
+		}
+
 		// Update status page using transaction
-		err := db.Transaction(func(tx *gorm.DB) error {
+		err = db.Transaction(func(tx *gorm.DB) error {
 			updates := map[string]interface{}{
 				"slug":            req.Slug,
 				"title":           req.Title,
@@ -258,12 +335,28 @@ func HandleUpdateStatusPage(db *gorm.DB) http.HandlerFunc {
 				"show_powered_by": req.ShowPoweredBy,
 				"theme":           req.Theme,
 				"updated_at":      time.Now(),
+
+				"banner":       banner,
+				"banner_style": bannerStyle,
+			}
+
+			if req.ConfirmationChecks != nil {
+				updates["confirmation_checks"] = confirmationChecks
+			}
+
+			// An empty custom_domain removes the domain
+			if req.CustomDomain != nil {
+				updates["custom_domain"] = customDomain
 			}
 
 			if isAdminUser(user.ID) {
 				updates["custom_css"] = sanitizeCustomCSS(req.CustomCSS)
 			}
 
+			if req.ShowUptimePercentage != nil {
+				updates["show_uptime_percentage"] = *req.ShowUptimePercentage
+			}
+
 			if req.Password != "" {
 				hashed, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 				if err != nil {
@@ -288,10 +381,11 @@ func HandleUpdateStatusPage(db *gorm.DB) http.HandlerFunc {
 			// Add new monitors
 			if len(req.MonitorIDs) > 0 {
 				pageIDInt, _ := strconv.Atoi(pageID)
-				for _, monitorID := range req.MonitorIDs {
+				for i, monitorID := range req.MonitorIDs {
 					spm := models.StatusPageMonitor{
 						StatusPageID: pageIDInt,
 						MonitorID:    monitorID,
+						DisplayOrder: i,
 					}
 					if err := tx.Create(&spm).Error; err != nil {
 						return err
@@ -316,6 +410,196 @@ func HandleUpdateStatusPage(db *gorm.DB) http.HandlerFunc {
 	}
 }
 
+// HandleUpdateStatusPageOrder sets the display order of monitors on a status page
+func HandleUpdateStatusPageOrder(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		pageID := chi.URLParam(r, "id")
+
+		var req struct {
+			MonitorIDs []int `json:"monitor_ids"`
+		}
+
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		// Verify ownership
+		var count int64
+		db.Model(&models.StatusPage{}).
+			Where("id = ? AND user_id = ?", pageID, user.ID).
+			Count(&count)
+		if count == 0 {
+			http.Error(w, "Status page not found", http.StatusNotFound)
+			return
+		}
+
+		// Current monitors in their current order
+		var current []int
+		if err := db.Model(&models.StatusPageMonitor{}).
+			Where("status_page_id = ?", pageID).
+			Order("display_order ASC, monitor_id ASC").
+			Pluck("monitor_id", &current).Error; err != nil {
+			http.Error(w, "Failed to fetch status page monitors", http.StatusInternalServerError)
+			return
+		}
+
+		order, err := buildDisplayOrder(current, req.MonitorIDs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			for monitorID, displayOrder := range order {
+				if err := tx.Model(&models.StatusPageMonitor{}).
+					Where("status_page_id = ? AND monitor_id = ?", pageID, monitorID).
+					Update("display_order", displayOrder).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+
+		if err != nil {
+			http.Error(w, "Failed to update monitor order", http.StatusInternalServerError)
+			return
+		}
+
+		var monitors []models.StatusPageMonitor
+		db.Where("status_page_id = ?", pageID).
+			Order("display_order ASC").
+			Find(&monitors)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(monitors)
+	}
+}
+
+// buildDisplayOrder maps each monitor on a page to its new position.
+// requested must only contain monitors already on the page, without
+// duplicates; monitors it leaves out follow in their current order.
+func buildDisplayOrder(current, requested []int) (map[int]int, error) {
+	onPage := make(map[int]bool, len(current))
+	for _, id := range current {
+		onPage[id] = true
+	}
+
+	order := make(map[int]int, len(current))
+	for _, id := range requested {
+		if !onPage[id] {
+			return nil, fmt.Errorf("monitor %d is not on this status page", id)
+		}
+		if _, dup := order[id]; dup {
+			return nil, fmt.Errorf("monitor %d is listed more than once", id)
+		}
+		order[id] = len(order)
+	}
+
+	for _, id := range current {
+		if _, ok := order[id]; !ok {
+			order[id] = len(order)
+		}
+	}
+
+	return order, nil
+}
+
+// maxConfirmationChecks bounds how many heartbeats the public page loads per monitor
+const maxConfirmationChecks = 20
+
+// normalizeConfirmationChecks defaults an unset confirmation_checks to 1 and
+// checks it is in range
+func normalizeConfirmationChecks(n int) (int, error) {
+	if n == 0 {
+		return 1, nil
+	}
+	if n < 1 || n > maxConfirmationChecks {
+		return 0, fmt.Errorf("confirmation_checks must be between 1 and %d", maxConfirmationChecks)
+	}
+	return n, nil
+}
+
+// confirmedStatus returns the status a public page shows given the most recent
+// heartbeats, newest first. A down status only shows once the last
+// confirmations heartbeats are all down; until then the page keeps the status
+// from before the failures, or pending if there is none.
+func confirmedStatus(recent []models.Heartbeat, confirmations int) int {
+	const statusDown, statusPending = 0, 2
+
+	for i, hb := range recent {
+		if hb.Status == statusDown {
+			if i+1 >= confirmations {
+				return statusDown
+			}
+			continue
+		}
+		return hb.Status
+	}
+	return statusPending
+}
+
+// confirmedStatuses returns the status a public page showed after each of
+// heartbeats, oldest first. As with confirmedStatus, a down heartbeat only
+// shows as down from the confirmations-th down in a row.
+func confirmedStatuses(heartbeats []models.Heartbeat, confirmations int) []int {
+	const statusDown, statusPending = 0, 2
+
+	statuses := make([]int, len(heartbeats))
+	before, downs := statusPending, 0
+	for i, hb := range heartbeats {
+		if hb.Status != statusDown {
+			before, downs = hb.Status, 0
+			statuses[i] = hb.Status
+			continue
+		}
+		downs++
+		if downs >= confirmations {
+			statuses[i] = statusDown
+		} else {
+			statuses[i] = before
+		}
+	}
+	return statuses
+}
+
+// fillHistory sets the status of each bucket, starting at start and
+// interval long, from heartbeats, oldest first: the worst confirmed status
+// in the bucket, or the one carried over from the buckets before it.
+// Buckets before any heartbeat take fallback, if there is one.
+func fillHistory(buckets []StatusHistoryBucket, heartbeats []models.Heartbeat, start time.Time, interval time.Duration, confirmations int, fallback int, hasFallback bool) {
+	intervalSeconds := int64(interval.Seconds())
+	if intervalSeconds <= 0 {
+		return
+	}
+
+	statuses := confirmedStatuses(heartbeats, confirmations)
+	seen := make([]bool, len(buckets))
+	for i, hb := range heartbeats {
+		idx := int(hb.Time.Unix()/intervalSeconds - start.Unix()/intervalSeconds)
+		if idx < 0 {
+			fallback, hasFallback = statuses[i], true
+			continue
+		}
+		if idx >= len(buckets) {
+			continue
+		}
+		if !seen[idx] || models.StatusSeverity(statuses[i]) > models.StatusSeverity(buckets[idx].Status) {
+			buckets[idx].Status = statuses[i]
+		}
+		seen[idx] = true
+	}
+
+	for i := range buckets {
+		if seen[i] {
+			fallback, hasFallback = buckets[i].Status, true
+		} else if hasFallback {
+			buckets[i].Status = fallback
+		}
+	}
+}
+
 // HandleDeleteStatusPage deletes a status page
 func HandleDeleteStatusPage(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -340,6 +624,87 @@ func HandleDeleteStatusPage(db *gorm.DB) http.HandlerFunc {
 	}
 }
 
+// StatusHistoryBucket is one slot of a monitor's recent history on a public page
+type StatusHistoryBucket struct {
+	Start  time.Time `json:"start"`
+	Status int       `json:"status"`
+}
+
+// PublicMonitorStatus is a monitor as shown on a public status page. Only
+// what the page displays is copied from the monitor; its config holds
+// targets and credentials and never leaves the owner's API.
+type PublicMonitorStatus struct {
+	ID            int                   `json:"id"`
+	Name          string                `json:"name"`
+	Type          string                `json:"type"`
+	Interval      int                   `json:"interval"`
+	LastHeartbeat *models.Heartbeat     `json:"last_heartbeat"`
+	History       []StatusHistoryBucket `json:"history"`
+	// UptimePercentage is the last 24 hours' uptime, left out when the
+	// page doesn't show uptime numbers
+	UptimePercentage *float64 `json:"uptime_percentage,omitempty"`
+	// Maintenance is the planned maintenance under way, if any
+	Maintenance *publicMaintenance `json:"maintenance,omitempty"`
+}
+
+// pageStatus returns the overall status of a public page from its monitors'
+// confirmed statuses: down when any is down, otherwise maintenance when any
+// is under maintenance, otherwise up
+func pageStatus(monitors []PublicMonitorStatus) int {
+	const statusDown, statusUp, statusMaintenance = 0, 1, 3
+
+	status := statusUp
+	for _, monitor := range monitors {
+		if monitor.LastHeartbeat == nil {
+			continue
+		}
+		switch monitor.LastHeartbeat.Status {
+		case statusDown:
+			return statusDown
+		case statusMaintenance:
+			status = statusMaintenance
+		}
+	}
+	return status
+}
+
+// addPublicUptime sets each monitor's 24 hour uptime percentage when the
+// page shows uptime numbers. Monitors whose uptime can't be calculated are
+// shown without one.
+func addPublicUptime(page *models.StatusPage, monitors []PublicMonitorStatus, uptime24h func(monitorID int) (float64, error)) {
+	if !page.ShowUptimePercentage {
+		return
+	}
+	for i := range monitors {
+		if percentage, err := uptime24h(monitors[i].ID); err == nil {
+			monitors[i].UptimePercentage = &percentage
+		}
+	}
+}
+
+// newPublicMonitorStatus copies the fields a public page shows from monitor
+func newPublicMonitorStatus(monitor models.Monitor) PublicMonitorStatus {
+	return PublicMonitorStatus{
+		ID:       monitor.ID,
+		Name:     monitor.Name,
+		Type:     monitor.Type,
+		Interval: monitor.Interval,
+	}
+}
+
+// publicMonitors drops the monitors that aren't marked public, keeping the
+// order of the rest. Owners may add any monitor to a page; only public ones
+// are ever shown on it.
+func publicMonitors(monitors []models.Monitor) []models.Monitor {
+	public := make([]models.Monitor, 0, len(monitors))
+	for _, monitor := range monitors {
+		if monitor.Public {
+			public = append(public, monitor)
+		}
+	}
+	return public
+}
+
 // HandleGetPublicStatusPage returns a public status page by slug (no auth required)
 func HandleGetPublicStatusPage(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -370,41 +735,40 @@ func HandleGetPublicStatusPage(db *gorm.DB) http.HandlerFunc {
 		}
 
 		// Get monitors with their latest heartbeat
-		type StatusHistoryBucket struct {
-			Start  time.Time `json:"start"`
-			Status int       `json:"status"`
-		}
-		type MonitorWithStatus struct {
-			models.Monitor
-			LastHeartbeat *models.Heartbeat     `json:"last_heartbeat"`
-			History       []StatusHistoryBucket `json:"history"`
-		}
-
 		var monitors []models.Monitor
 		db.Joins("INNER JOIN status_page_monitors spm ON monitors.id = spm.monitor_id").
 			Where("spm.status_page_id = ?", page.ID).
-			Order("monitors.name ASC").
+			Order("spm.display_order ASC, monitors.name ASC").
 			Find(&monitors)
+		monitors = publicMonitors(monitors)
 
-		monitorsWithStatus := make([]MonitorWithStatus, len(monitors))
+		monitorsWithStatus := make([]PublicMonitorStatus, len(monitors))
 		monitorIDs := make([]int, 0, len(monitors))
 		for i, monitor := range monitors {
-			monitorsWithStatus[i].Monitor = monitor
+			monitorsWithStatus[i] = newPublicMonitorStatus(monitor)
 			monitorIDs = append(monitorIDs, monitor.ID)
 		}
 
-		// Get latest heartbeat per monitor using individual indexed lookups
+		// Get latest heartbeats per monitor using individual indexed lookups.
+		// Enough are fetched to confirm a down status before showing it.
+		confirmations := max(page.ConfirmationChecks, 1)
 		lastHeartbeatStatusByMonitor := make(map[int]int, len(monitorIDs))
 		if len(monitorIDs) > 0 {
 			latestByMonitor := make(map[int]models.Heartbeat, len(monitorIDs))
 			for _, mid := range monitorIDs {
-				var hb models.Heartbeat
+				var recent []models.Heartbeat
 				if err := db.Where("monitor_id = ?", mid).
 					Order("time DESC").
-					Limit(1).
-					First(&hb).Error; err == nil {
-					latestByMonitor[mid] = hb
+					Limit(confirmations).
+					Find(&recent).Error; err == nil && len(recent) > 0 {
+					hb := recent[0]
+					hb.RemoteAddr = nil // target addresses stay private
+					if status := confirmedStatus(recent, confirmations); status != hb.Status {
+						hb.Status = status
+						hb.Message = ""
+					}
 					lastHeartbeatStatusByMonitor[mid] = hb.Status
+					latestByMonitor[mid] = hb
 				}
 			}
 
@@ -427,14 +791,7 @@ func HandleGetPublicStatusPage(db *gorm.DB) http.HandlerFunc {
 		}
 
 		for _, monitorID := range monitorIDs {
-			intervalSeconds := 60
-			if interval, ok := monitorIntervalByID[monitorID]; ok && interval > 0 {
-				if interval > intervalSeconds {
-					intervalSeconds = interval
-				}
-			}
-
-			interval := time.Duration(intervalSeconds) * time.Second
+			interval := time.Duration(models.StatusBucketSeconds(monitorIntervalByID[monitorID])) * time.Second
 			intervalByMonitor[monitorID] = interval
 
 			bucketCount := int((1*time.Hour + interval - 1) / interval)
@@ -448,118 +805,63 @@ func HandleGetPublicStatusPage(db *gorm.DB) http.HandlerFunc {
 			historyByMonitor[monitorID] = buckets
 		}
 
-		if len(monitorIDs) > 0 {
-			type bucketRow struct {
-				MonitorID int   `gorm:"column:monitor_id"`
-				Bucket    int64 `gorm:"column:bucket"`
-				MaxWeight int   `gorm:"column:max_weight"`
-			}
-
-			var rows []bucketRow
-			db.Raw(`
-				SELECT
-					h.monitor_id,
-					FLOOR(EXTRACT(EPOCH FROM h.time) / GREATEST(60, m.interval)) AS bucket,
-					MAX(
-						CASE h.status
-							WHEN 0 THEN 4
-							WHEN 3 THEN 3
-							WHEN 2 THEN 2
-							WHEN 1 THEN 1
-							ELSE 0
-						END
-					) AS max_weight
-				FROM heartbeats h
-				JOIN monitors m ON m.id = h.monitor_id
-				WHERE h.monitor_id IN ? AND h.time >= ?
-				GROUP BY h.monitor_id, bucket, GREATEST(60, m.interval)
-				ORDER BY h.monitor_id, bucket ASC
-			`, monitorIDs, start).Scan(&rows)
-
-			type lastStatusRow struct {
-				MonitorID int `gorm:"column:monitor_id"`
-				Status    int `gorm:"column:status"`
-			}
-			lastStatusByMonitor := make(map[int]int, len(monitorIDs))
-			for _, mid := range monitorIDs {
-				var row lastStatusRow
-				if err := db.Raw(`
-					SELECT monitor_id, status
-					FROM heartbeats
-					WHERE monitor_id = ? AND time < ?
-					ORDER BY time DESC
-					LIMIT 1
-				`, mid, start).Scan(&row).Error; err == nil && row.MonitorID != 0 {
-					lastStatusByMonitor[mid] = row.Status
-				}
+		// History comes from the status cache the executor updates with each
+		// heartbeat. Heartbeats are only read for monitors without a cache
+		// row for their current interval, and on pages confirming down
+		// statuses: the cache keeps each bucket's worst status, not the runs
+		// of down checks confirmation counts.
+		liveMonitorIDs := make([]int, 0, len(monitorIDs))
+		if len(monitorIDs) > 0 && confirmations == 1 {
+			var caches []models.MonitorStatusCache
+			db.Where("monitor_id IN ?", monitorIDs).Find(&caches)
+			cacheByMonitor := make(map[int]*models.MonitorStatusCache, len(caches))
+			for i := range caches {
+				cacheByMonitor[caches[i].MonitorID] = &caches[i]
 			}
 
-			bucketStatusByMonitor := make(map[int]map[int]int, len(monitorIDs))
-			for _, row := range rows {
-				buckets, ok := historyByMonitor[row.MonitorID]
-				if !ok || len(buckets) == 0 {
-					continue
-				}
-
-				interval := intervalByMonitor[row.MonitorID]
-				if interval <= 0 {
-					interval = time.Minute
-				}
-				intervalSeconds := int64(interval.Seconds())
-				if intervalSeconds <= 0 {
-					continue
-				}
-
-				bucketStartIndex := start.Unix() / intervalSeconds
-				idx := int(row.Bucket - bucketStartIndex)
-				if idx < 0 || idx >= len(buckets) {
+			for _, monitorID := range monitorIDs {
+				cache, ok := cacheByMonitor[monitorID]
+				if !ok || time.Duration(cache.BucketSeconds)*time.Second != intervalByMonitor[monitorID] {
+					liveMonitorIDs = append(liveMonitorIDs, monitorID)
 					continue
 				}
-
-				status := -1
-				switch row.MaxWeight {
-				case 4:
-					status = 0
-				case 3:
-					status = 3
-				case 2:
-					status = 2
-				case 1:
-					status = 1
-				}
-				if _, ok := bucketStatusByMonitor[row.MonitorID]; !ok {
-					bucketStatusByMonitor[row.MonitorID] = make(map[int]int)
+				buckets := historyByMonitor[monitorID]
+				for i, status := range cache.History(start, len(buckets)) {
+					buckets[i].Status = status
 				}
-				bucketStatusByMonitor[row.MonitorID][idx] = status
 			}
+		} else {
+			liveMonitorIDs = monitorIDs
+		}
 
-			for _, monitorID := range monitorIDs {
-				buckets, ok := historyByMonitor[monitorID]
-				if !ok || len(buckets) == 0 {
-					continue
-				}
+		if len(liveMonitorIDs) > 0 {
+			var window []models.Heartbeat
+			db.Select("monitor_id, status, time").
+				Where("monitor_id IN ? AND time >= ?", liveMonitorIDs, start).
+				Order("monitor_id, time ASC").
+				Find(&window)
+			windowByMonitor := make(map[int][]models.Heartbeat, len(liveMonitorIDs))
+			for _, hb := range window {
+				windowByMonitor[hb.MonitorID] = append(windowByMonitor[hb.MonitorID], hb)
+			}
 
-				lastStatus, hasLast := lastStatusByMonitor[monitorID]
-				if !hasLast {
-					if hbStatus, ok := lastHeartbeatStatusByMonitor[monitorID]; ok {
-						lastStatus = hbStatus
-						hasLast = true
-					}
-				}
-				for i := range buckets {
-					if statusMap, ok := bucketStatusByMonitor[monitorID]; ok {
-						if status, ok := statusMap[i]; ok {
-							buckets[i].Status = status
-							lastStatus = status
-							hasLast = true
-							continue
-						}
-					}
+			for _, monitorID := range liveMonitorIDs {
+				// The heartbeats just before the hour give its first buckets
+				// a status and count towards down runs that started earlier
+				var before []models.Heartbeat
+				db.Select("monitor_id, status, time").
+					Where("monitor_id = ? AND time < ?", monitorID, start).
+					Order("time DESC").
+					Limit(confirmations).
+					Find(&before)
+				slices.Reverse(before)
 
-					if hasLast {
-						buckets[i].Status = lastStatus
-					}
+				fallback, hasFallback := lastHeartbeatStatusByMonitor[monitorID]
+				if len(before) > 0 {
+					hasFallback = false
 				}
+				fillHistory(historyByMonitor[monitorID], append(before, windowByMonitor[monitorID]...),
+					start, intervalByMonitor[monitorID], confirmations, fallback, hasFallback)
 			}
 		}
 
@@ -569,6 +871,27 @@ func HandleGetPublicStatusPage(db *gorm.DB) http.HandlerFunc {
 			}
 		}
 
+		// Flag monitors under planned maintenance for the page's banner
+		if len(monitorIDs) > 0 {
+			var windows []models.MaintenanceWindow
+			db.Where("monitor_id IN ?", monitorIDs).Find(&windows)
+			active := activeMaintenanceByMonitor(windows, now)
+			for i, monitor := range monitors {
+				if maintenance, ok := active[monitor.ID]; ok {
+					monitorsWithStatus[i].Maintenance = &maintenance
+				}
+			}
+		}
+
+		calculator := uptime.NewCalculator(db)
+		addPublicUptime(&page, monitorsWithStatus, func(monitorID int) (float64, error) {
+			stats, err := calculator.CalculateUptimeForPeriod(monitorID, 24*time.Hour)
+			if err != nil {
+				return 0, err
+			}
+			return stats.UptimePercentage, nil
+		})
+
 		// Get recent incidents
 		var incidents []models.Incident
 		db.Where("status_page_id = ?", page.ID).
@@ -578,6 +901,7 @@ func HandleGetPublicStatusPage(db *gorm.DB) http.HandlerFunc {
 
 		result := map[string]interface{}{
 			"page":      page,
+			"status":    pageStatus(monitorsWithStatus),
 			"monitors":  monitorsWithStatus,
 			"incidents": incidents,
 		}
@@ -610,10 +934,11 @@ func HandleGetPublicStatusPageHeartbeats(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
-		// Ensure monitor belongs to page
+		// Ensure monitor belongs to page and is public
 		var count int64
 		db.Table("status_page_monitors").
-			Where("status_page_id = ? AND monitor_id = ?", page.ID, monitorID).
+			Joins("INNER JOIN monitors ON monitors.id = status_page_monitors.monitor_id").
+			Where("status_page_monitors.status_page_id = ? AND status_page_monitors.monitor_id = ? AND monitors.public = ?", page.ID, monitorID, true).
 			Count(&count)
 		if count == 0 {
 			http.Error(w, "Monitor not found", http.StatusNotFound)
@@ -652,12 +977,19 @@ func HandleGetPublicStatusPageHeartbeats(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		// Target addresses are only shown to the monitor owner
+		for i := range heartbeats {
+			heartbeats[i].RemoteAddr = nil
+		}
+
 		w.Header().Set("Content-Type", "application/json")
 		json.NewEncoder(w).Encode(heartbeats)
 	}
 }
 
-// HandleGetIncidents returns all incidents for a status page
+// HandleGetIncidents returns a page of a status page's incidents, pinned first,
+// optionally filtered by status and creation date. X-Total-Count holds the
+// number of matching incidents.
 func HandleGetIncidents(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		user := r.Context().Value(userContextKey).(*models.User)
@@ -673,9 +1005,24 @@ func HandleGetIncidents(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
-		var incidents []models.Incident
-		err := db.Where("status_page_id = ?", pageID).
-			Order("pin DESC, created_at DESC").
+		query, err := parseIncidentQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var total int64
+		if err := query.filter(db.Model(&models.Incident{}).Where("status_page_id = ?", pageID)).
+			Count(&total).Error; err != nil {
+			http.Error(w, "Failed to fetch incidents", http.StatusInternalServerError)
+			return
+		}
+
+		incidents := []models.Incident{}
+		err = query.filter(db.Where("status_page_id = ?", pageID)).
+			Order("pin DESC, created_at DESC, id DESC").
+			Limit(query.limit).
+			Offset(query.offset).
 			Find(&incidents).Error
 
 		if err != nil {
@@ -684,6 +1031,7 @@ func HandleGetIncidents(db *gorm.DB) http.HandlerFunc {
 		}
 
 		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
 		json.NewEncoder(w).Encode(incidents)
 	}
 }
@@ -705,10 +1053,11 @@ func HandleCreateIncident(db *gorm.DB) http.HandlerFunc {
 		}
 
 		var req struct {
-			Title   string `json:"title"`
-			Content string `json:"content"`
-			Style   string `json:"style"`
-			Pin     bool   `json:"pin"`
+			Title            string `json:"title"`
+			Content          string `json:"content"`
+			Style            string `json:"style"`
+			Pin              bool   `json:"pin"`
+			AutoResolveAfter string `json:"auto_resolve_after"` // Go duration, e.g. "30m" or "4h"
 		}
 
 		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -716,6 +1065,17 @@ func HandleCreateIncident(db *gorm.DB) http.HandlerFunc {
 			return
 		}
 
+		var autoResolveAfter *int
+		if req.AutoResolveAfter != "" {
+			d, err := time.ParseDuration(req.AutoResolveAfter)
+			if err != nil || d < time.Minute {
+				http.Error(w, "auto_resolve_after must be a duration of at least 1m (e.g. \"30m\", \"4h\")", http.StatusBadRequest)
+				return
+			}
+			seconds := int(d.Seconds())
+			autoResolveAfter = &seconds
+		}
+
 		now := time.Now()
 		pageIDInt, _ := strconv.Atoi(pageID)
 		incident := models.Incident{
@@ -726,6 +1086,8 @@ func HandleCreateIncident(db *gorm.DB) http.HandlerFunc {
 			Pin:          req.Pin,
 			CreatedAt:    now,
 			UpdatedAt:    now,
+
+			AutoResolveAfter: autoResolveAfter,
 		}
 
 		err := db.Create(&incident).Error
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// publicIncident is an incident as a status page's visitors see it in the
+// incident history
+type publicIncident struct {
+	ID         int        `json:"id"`
+	Title      string     `json:"title"`
+	Content    string     `json:"content"`
+	Style      string     `json:"style"`
+	CreatedAt  time.Time  `json:"created_at"`
+	ResolvedAt *time.Time `json:"resolved_at"`
+	// ResolutionSeconds is how long the incident was open, nil while it is
+	ResolutionSeconds *int64 `json:"resolution_seconds"`
+}
+
+// toPublicIncidents converts incidents for the history, with how long each
+// resolved one took
+func toPublicIncidents(incidents []models.Incident) []publicIncident {
+	history := make([]publicIncident, len(incidents))
+	for i, incident := range incidents {
+		history[i] = publicIncident{
+			ID:         incident.ID,
+			Title:      incident.Title,
+			Content:    incident.Content,
+			Style:      incident.Style,
+			CreatedAt:  incident.CreatedAt,
+			ResolvedAt: incident.ResolvedAt,
+		}
+		if incident.ResolvedAt != nil {
+			seconds := int64(max(incident.ResolvedAt.Sub(incident.CreatedAt), 0) / time.Second)
+			history[i].ResolutionSeconds = &seconds
+		}
+	}
+	return history
+}
+
+// incidentHistoryPage restricts tx to one page of a status page's incident
+// history, newest first. Pinned incidents are not lifted to the top, so
+// pages stay in date order.
+func incidentHistoryPage(tx *gorm.DB, pageID int, q *incidentQuery) *gorm.DB {
+	return q.filter(tx.Where("status_page_id = ?", pageID)).
+		Order("created_at DESC, id DESC").
+		Limit(q.limit).
+		Offset(q.offset)
+}
+
+// HandleGetPublicStatusPageIncidents returns a published status page's full
+// incident history, paginated like the owner's incident list. Password
+// protected pages need the same password or token as the page itself.
+func HandleGetPublicStatusPageIncidents(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		slug := chi.URLParam(r, "slug")
+
+		var page models.StatusPage
+		err := db.Where("slug = ? AND published = ?", slug, true).
+			First(&page).Error
+		if err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Status page not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch status page", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		if !hasValidStatusPagePassword(r, &page) {
+			http.Error(w, "Status page password required", http.StatusUnauthorized)
+			return
+		}
+
+		query, err := parseIncidentQuery(r.URL.Query())
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var total int64
+		if err := query.filter(db.Model(&models.Incident{}).Where("status_page_id = ?", page.ID)).
+			Count(&total).Error; err != nil {
+			http.Error(w, "Failed to fetch incidents", http.StatusInternalServerError)
+			return
+		}
+
+		var incidents []models.Incident
+		if err := incidentHistoryPage(db, page.ID, query).Find(&incidents).Error; err != nil {
+			http.Error(w, "Failed to fetch incidents", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Total-Count", strconv.FormatInt(total, 10))
+		json.NewEncoder(w).Encode(toPublicIncidents(incidents))
+	}
+}
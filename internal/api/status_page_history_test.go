@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestIncidentHistoryPageSQL(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatalf("gorm.Open: %v", err)
+	}
+
+	tests := []struct {
+		values   url.Values
+		want     string
+		wantVars []interface{}
+	}{
+		{
+			values:   url.Values{},
+			want:     `SELECT * FROM "incidents" WHERE status_page_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2`,
+			wantVars: []interface{}{7, defaultIncidentLimit},
+		},
+		{
+			values:   url.Values{"limit": {"20"}, "offset": {"40"}},
+			want:     `SELECT * FROM "incidents" WHERE status_page_id = $1 ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`,
+			wantVars: []interface{}{7, 20, 40},
+		},
+		{
+			values:   url.Values{"status": {"resolved"}, "limit": {"5"}, "offset": {"5"}},
+			want:     `SELECT * FROM "incidents" WHERE status_page_id = $1 AND resolved_at IS NOT NULL ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3`,
+			wantVars: []interface{}{7, 5, 5},
+		},
+	}
+
+	for _, tt := range tests {
+		q, err := parseIncidentQuery(tt.values)
+		if err != nil {
+			t.Fatalf("parseIncidentQuery(%v): %v", tt.values, err)
+		}
+		var incidents []models.Incident
+		stmt := incidentHistoryPage(db, 7, q).Find(&incidents).Statement
+		if got := stmt.SQL.String(); got != tt.want {
+			t.Errorf("%v: SQL = %s, want %s", tt.values, got, tt.want)
+		}
+		if len(stmt.Vars) != len(tt.wantVars) {
+			t.Errorf("%v: vars = %v, want %v", tt.values, stmt.Vars, tt.wantVars)
+			continue
+		}
+		for i, v := range stmt.Vars {
+			if v != tt.wantVars[i] {
+				t.Errorf("%v: var %d = %v, want %v", tt.values, i, v, tt.wantVars[i])
+			}
+		}
+	}
+}
+
+func TestToPublicIncidentsResolutionTime(t *testing.T) {
+	created := time.Date(2026, 5, 4, 10, 0, 0, 0, time.UTC)
+	resolved := created.Add(90 * time.Minute)
+
+	history := toPublicIncidents([]models.Incident{
+		{ID: 2, Title: "Database failover", CreatedAt: created, ResolvedAt: &resolved},
+		{ID: 1, Title: "Degraded API", CreatedAt: created},
+	})
+
+	if len(history) != 2 {
+		t.Fatalf("got %d incidents, want 2", len(history))
+	}
+	if s := history[0].ResolutionSeconds; s == nil || *s != 5400 {
+		t.Errorf("resolution_seconds = %v, want 5400", s)
+	}
+	if history[1].ResolutionSeconds != nil || history[1].ResolvedAt != nil {
+		t.Errorf("open incident has resolution %v at %v, want none", history[1].ResolutionSeconds, history[1].ResolvedAt)
+	}
+}
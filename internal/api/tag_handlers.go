@@ -0,0 +1,238 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+const (
+	// maxTagNameLength caps the length of a tag name
+	maxTagNameLength = 50
+
+	// tagAssignMaxMonitors caps the monitors tagged in one request
+	tagAssignMaxMonitors = 500
+)
+
+// tagSummary is a tag with the number of monitors carrying it
+type tagSummary struct {
+	models.Tag
+	MonitorCount int `json:"monitor_count"`
+}
+
+// summarizeTags pairs each tag with how many monitors carry it. Tags no
+// monitor carries count zero; links to other tags are ignored.
+func summarizeTags(tags []models.Tag, links []models.MonitorTag) []tagSummary {
+	counts := make(map[int]int, len(tags))
+	for _, link := range links {
+		counts[link.TagID]++
+	}
+
+	summaries := make([]tagSummary, len(tags))
+	for i, tag := range tags {
+		summaries[i] = tagSummary{Tag: tag, MonitorCount: counts[tag.ID]}
+	}
+	return summaries
+}
+
+// parseTagAssignment validates the monitor IDs of a bulk assignment.
+// Duplicate IDs are dropped, keeping the first.
+func parseTagAssignment(monitorIDs []int) ([]int, error) {
+	seen := make(map[int]bool, len(monitorIDs))
+	ids := make([]int, 0, len(monitorIDs))
+	for _, id := range monitorIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("monitor_ids must not be empty")
+	}
+	if len(ids) > tagAssignMaxMonitors {
+		return nil, fmt.Errorf("at most %d monitors can be tagged at once", tagAssignMaxMonitors)
+	}
+	return ids, nil
+}
+
+// untaggedMonitors returns the ids not already tagged, so assigning a tag
+// twice leaves the existing links alone
+func untaggedMonitors(ids, tagged []int) []int {
+	has := make(map[int]bool, len(tagged))
+	for _, id := range tagged {
+		has[id] = true
+	}
+
+	var missing []int
+	for _, id := range ids {
+		if !has[id] {
+			missing = append(missing, id)
+		}
+	}
+	return missing
+}
+
+// HandleGetTags lists the user's tags with how many monitors carry each
+func HandleGetTags(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+
+		tags := []models.Tag{}
+		if err := db.Where("user_id = ?", user.ID).Order("name").Find(&tags).Error; err != nil {
+			http.Error(w, "Failed to fetch tags", http.StatusInternalServerError)
+			return
+		}
+
+		var links []models.MonitorTag
+		if len(tags) > 0 {
+			ids := make([]int, len(tags))
+			for i, tag := range tags {
+				ids[i] = tag.ID
+			}
+			if err := db.Where("tag_id IN ?", ids).Find(&links).Error; err != nil {
+				http.Error(w, "Failed to count tagged monitors", http.StatusInternalServerError)
+				return
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(summarizeTags(tags, links))
+	}
+}
+
+// HandleCreateTag creates a tag
+func HandleCreateTag(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+
+		var req struct {
+			Name string `json:"name"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			http.Error(w, "Name is required", http.StatusBadRequest)
+			return
+		}
+		if len(name) > maxTagNameLength {
+			http.Error(w, fmt.Sprintf("Name must be at most %d characters", maxTagNameLength), http.StatusBadRequest)
+			return
+		}
+
+		var count int64
+		if err := db.Model(&models.Tag{}).
+			Where("user_id = ? AND name = ?", user.ID, name).
+			Count(&count).Error; err != nil {
+			http.Error(w, "Failed to check tag name", http.StatusInternalServerError)
+			return
+		}
+		if count > 0 {
+			http.Error(w, "Tag already exists", http.StatusConflict)
+			return
+		}
+
+		tag := models.Tag{UserID: user.ID, Name: name, CreatedAt: time.Now()}
+		if err := db.Create(&tag).Error; err != nil {
+			http.Error(w, "Failed to create tag", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(tagSummary{Tag: tag})
+	}
+}
+
+// HandleAssignTag tags several of the user's monitors in one transaction.
+// Monitors that already carry the tag are left as they are.
+func HandleAssignTag(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+
+		var req struct {
+			MonitorIDs []int `json:"monitor_ids"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		ids, err := parseTagAssignment(req.MonitorIDs)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		var tag models.Tag
+		if err := db.Where("id = ? AND user_id = ?", id, user.ID).First(&tag).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Tag not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch tag", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		// Verify ownership of every monitor
+		var count int64
+		if err := db.Model(&models.Monitor{}).
+			Where("id IN ? AND user_id = ?", ids, user.ID).
+			Count(&count).Error; err != nil {
+			http.Error(w, "Failed to verify monitors", http.StatusInternalServerError)
+			return
+		}
+		if int(count) != len(ids) {
+			http.Error(w, "Monitor not found", http.StatusNotFound)
+			return
+		}
+
+		var added int
+		var links []models.MonitorTag
+		err = db.Transaction(func(tx *gorm.DB) error {
+			var tagged []int
+			if err := tx.Model(&models.MonitorTag{}).
+				Where("tag_id = ? AND monitor_id IN ?", tag.ID, ids).
+				Pluck("monitor_id", &tagged).Error; err != nil {
+				return err
+			}
+
+			missing := untaggedMonitors(ids, tagged)
+			for _, monitorID := range missing {
+				if err := tx.Exec("INSERT INTO monitor_tags (monitor_id, tag_id) VALUES (?, ?)", monitorID, tag.ID).Error; err != nil {
+					return err
+				}
+			}
+			added = len(missing)
+
+			return tx.Where("tag_id = ?", tag.ID).Find(&links).Error
+		})
+		if err != nil {
+			http.Error(w, "Failed to assign tag", http.StatusInternalServerError)
+			return
+		}
+
+		summaries := summarizeTags([]models.Tag{tag}, links)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"tag":   summaries[0],
+			"added": added,
+		})
+	}
+}
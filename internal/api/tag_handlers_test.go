@@ -0,0 +1,71 @@
+package api
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestSummarizeTagsCounts(t *testing.T) {
+	tags := []models.Tag{{ID: 1, Name: "db"}, {ID: 2, Name: "prod"}, {ID: 3, Name: "unused"}}
+	links := []models.MonitorTag{
+		{MonitorID: 10, TagID: 2},
+		{MonitorID: 11, TagID: 2},
+		{MonitorID: 10, TagID: 1},
+		{MonitorID: 12, TagID: 2},
+		{MonitorID: 13, TagID: 99}, // another user's tag
+	}
+
+	got := summarizeTags(tags, links)
+	want := map[string]int{"db": 1, "prod": 3, "unused": 0}
+	if len(got) != len(tags) {
+		t.Fatalf("got %d summaries, want %d", len(got), len(tags))
+	}
+	for i, s := range got {
+		if s.Name != tags[i].Name {
+			t.Errorf("summary %d is %q, want tag order kept (%q)", i, s.Name, tags[i].Name)
+		}
+		if s.MonitorCount != want[s.Name] {
+			t.Errorf("%s: monitor_count = %d, want %d", s.Name, s.MonitorCount, want[s.Name])
+		}
+	}
+}
+
+func TestParseTagAssignment(t *testing.T) {
+	ids, err := parseTagAssignment([]int{3, 1, 3, 2, 1})
+	if err != nil {
+		t.Fatalf("parseTagAssignment: %v", err)
+	}
+	if want := []int{3, 1, 2}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ids = %v, want %v", ids, want)
+	}
+
+	if _, err := parseTagAssignment(nil); err == nil {
+		t.Error("empty monitor_ids accepted")
+	}
+
+	tooMany := make([]int, tagAssignMaxMonitors+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	if _, err := parseTagAssignment(tooMany); err == nil {
+		t.Errorf("%d monitors accepted, want at most %d", len(tooMany), tagAssignMaxMonitors)
+	}
+}
+
+func TestUntaggedMonitorsSkipsExistingLinks(t *testing.T) {
+	tests := []struct {
+		ids, tagged, want []int
+	}{
+		{ids: []int{1, 2, 3}, tagged: nil, want: []int{1, 2, 3}},
+		{ids: []int{1, 2, 3}, tagged: []int{2}, want: []int{1, 3}},
+		{ids: []int{1, 2}, tagged: []int{1, 2}, want: nil},
+	}
+
+	for _, tt := range tests {
+		if got := untaggedMonitors(tt.ids, tt.tagged); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("untaggedMonitors(%v, %v) = %v, want %v", tt.ids, tt.tagged, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+	"github.com/fuomag9/uptime-kabomba/internal/uptime"
+)
+
+// Rolled-up statuses of a tag's monitors
+const (
+	tagStatusUp          = "up"          // every monitor is up
+	tagStatusDegraded    = "degraded"    // some monitors are down or pending
+	tagStatusDown        = "down"        // every monitor is down
+	tagStatusMaintenance = "maintenance" // every monitor is in maintenance
+	tagStatusUnknown     = "unknown"     // no active monitor has a heartbeat yet
+)
+
+// tagMonitorStatus is one monitor in a tag's status rollup. Status is the
+// latest heartbeat's, or nil when it has none.
+type tagMonitorStatus struct {
+	MonitorID int    `json:"monitor_id"`
+	Name      string `json:"name"`
+	Active    bool   `json:"active"`
+	Status    *int   `json:"status"`
+}
+
+// tagStatusReport is the response of GET /tags/{id}/status
+type tagStatusReport struct {
+	TagID            int                `json:"tag_id"`
+	Name             string             `json:"name"`
+	Status           string             `json:"status"`
+	Period           string             `json:"period"`
+	UptimePercentage float64            `json:"uptime_percentage"`
+	TotalChecks      int                `json:"total_checks"`
+	UpChecks         int                `json:"up_checks"`
+	Monitors         []tagMonitorStatus `json:"monitors"`
+}
+
+// rollupTagStatus reduces the latest statuses of a tag's active monitors to
+// the worst case: down when all are down, degraded when any is down or
+// pending, and maintenance only when all are. Monitors without a heartbeat
+// yet are left out.
+func rollupTagStatus(monitors []tagMonitorStatus) string {
+	var reporting, down, degraded, maintenance int
+	for _, m := range monitors {
+		if !m.Active || m.Status == nil {
+			continue
+		}
+		reporting++
+		switch *m.Status {
+		case monitor.StatusDown:
+			down++
+		case monitor.StatusPending:
+			degraded++
+		case monitor.StatusMaintenance:
+			maintenance++
+		}
+	}
+
+	switch {
+	case reporting == 0:
+		return tagStatusUnknown
+	case down == reporting:
+		return tagStatusDown
+	case down > 0 || degraded > 0:
+		return tagStatusDegraded
+	case maintenance == reporting:
+		return tagStatusMaintenance
+	}
+	return tagStatusUp
+}
+
+// combineUptime adds up the checks of several monitors, so each monitor
+// weighs by how often it was checked
+func combineUptime(stats []*uptime.UptimeStats) (percentage float64, total, up int) {
+	for _, s := range stats {
+		total += s.TotalChecks
+		up += s.UpChecks
+	}
+	if total > 0 {
+		percentage = float64(up) / float64(total) * 100
+	}
+	return percentage, total, up
+}
+
+// HandleGetTagStatus rolls up the status of a tag's monitors and their
+// combined uptime over ?period= (24h, the default, 7d, 30d or 90d), so a
+// summary dashboard needn't fetch every monitor. Tags are how monitors are
+// grouped, so it also serves /monitor-groups/{id}/status.
+func HandleGetTagStatus(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		id, err := strconv.Atoi(chi.URLParam(r, "id"))
+		if err != nil {
+			http.Error(w, "Invalid ID", http.StatusBadRequest)
+			return
+		}
+
+		period := r.URL.Query().Get("period")
+		if period == "" {
+			period = "24h"
+		}
+		duration, ok := uptimePeriods[period]
+		if !ok {
+			http.Error(w, "period must be 24h, 7d, 30d or 90d", http.StatusBadRequest)
+			return
+		}
+
+		var tag models.Tag
+		if err := db.Where("id = ? AND user_id = ?", id, user.ID).First(&tag).Error; err != nil {
+			if err == gorm.ErrRecordNotFound {
+				http.Error(w, "Tag not found", http.StatusNotFound)
+			} else {
+				http.Error(w, "Failed to fetch tag", http.StatusInternalServerError)
+			}
+			return
+		}
+
+		var monitors []models.Monitor
+		if err := db.Select("monitors.id, monitors.name, monitors.active").
+			Joins("INNER JOIN monitor_tags mt ON mt.monitor_id = monitors.id").
+			Where("mt.tag_id = ? AND monitors.user_id = ?", tag.ID, user.ID).
+			Order("monitors.name").
+			Find(&monitors).Error; err != nil {
+			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
+			return
+		}
+
+		report := tagStatusReport{TagID: tag.ID, Name: tag.Name, Period: period, Monitors: []tagMonitorStatus{}}
+		ids := make([]int, len(monitors))
+		for i, m := range monitors {
+			ids[i] = m.ID
+			report.Monitors = append(report.Monitors, tagMonitorStatus{MonitorID: m.ID, Name: m.Name, Active: m.Active})
+		}
+
+		if len(ids) > 0 {
+			var latest []struct {
+				MonitorID int `gorm:"column:monitor_id"`
+				Status    int `gorm:"column:status"`
+			}
+			if err := db.Raw(`
+				SELECT DISTINCT ON (monitor_id) monitor_id, status
+				FROM heartbeats
+				WHERE monitor_id IN ?
+				ORDER BY monitor_id, time DESC
+			`, ids).Scan(&latest).Error; err != nil {
+				http.Error(w, "Failed to fetch heartbeats", http.StatusInternalServerError)
+				return
+			}
+			statuses := make(map[int]int, len(latest))
+			for _, l := range latest {
+				statuses[l.MonitorID] = l.Status
+			}
+			for i := range report.Monitors {
+				if status, ok := statuses[report.Monitors[i].MonitorID]; ok {
+					report.Monitors[i].Status = &status
+				}
+			}
+
+			calculator := uptime.NewCalculator(db)
+			stats, err := compareUptime(ids, func(monitorID int) (*uptime.UptimeStats, error) {
+				return calculator.CalculateUptimeForPeriod(monitorID, duration)
+			}, compareConcurrency)
+			if err != nil {
+				http.Error(w, "Failed to calculate uptime", http.StatusInternalServerError)
+				return
+			}
+			report.UptimePercentage, report.TotalChecks, report.UpChecks = combineUptime(stats)
+		}
+		report.Status = rollupTagStatus(report.Monitors)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
+}
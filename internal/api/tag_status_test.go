@@ -0,0 +1,58 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/fuomag9/uptime-kabomba/internal/monitor"
+	"github.com/fuomag9/uptime-kabomba/internal/uptime"
+)
+
+// statusOf returns a pointer to status, for tagMonitorStatus
+func statusOf(status int) *int {
+	return &status
+}
+
+func TestRollupTagStatus(t *testing.T) {
+	up, down := statusOf(monitor.StatusUp), statusOf(monitor.StatusDown)
+	maintenance := statusOf(monitor.StatusMaintenance)
+
+	tests := []struct {
+		name     string
+		monitors []tagMonitorStatus
+		want     string
+	}{
+		{"all up", []tagMonitorStatus{{Active: true, Status: up}, {Active: true, Status: up}}, tagStatusUp},
+		{"one down", []tagMonitorStatus{{Active: true, Status: up}, {Active: true, Status: down}, {Active: true, Status: up}}, tagStatusDegraded},
+		{"pending", []tagMonitorStatus{{Active: true, Status: up}, {Active: true, Status: statusOf(monitor.StatusPending)}}, tagStatusDegraded},
+		{"all down", []tagMonitorStatus{{Active: true, Status: down}, {Active: true, Status: down}}, tagStatusDown},
+		{"paused monitor ignored", []tagMonitorStatus{{Active: true, Status: up}, {Active: false, Status: down}}, tagStatusUp},
+		{"maintenance with up", []tagMonitorStatus{{Active: true, Status: up}, {Active: true, Status: maintenance}}, tagStatusUp},
+		{"all maintenance", []tagMonitorStatus{{Active: true, Status: maintenance}}, tagStatusMaintenance},
+		{"no heartbeats", []tagMonitorStatus{{Active: true}}, tagStatusUnknown},
+		{"no monitors", nil, tagStatusUnknown},
+	}
+
+	for _, tt := range tests {
+		if got := rollupTagStatus(tt.monitors); got != tt.want {
+			t.Errorf("%s: rollupTagStatus = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestCombineUptime(t *testing.T) {
+	// A monitor checked ten times as often weighs ten times as much
+	percentage, total, up := combineUptime([]*uptime.UptimeStats{
+		{TotalChecks: 1000, UpChecks: 1000},
+		{TotalChecks: 100, UpChecks: 0},
+	})
+	if total != 1100 || up != 1000 {
+		t.Errorf("total %d up %d, want 1100 and 1000", total, up)
+	}
+	if percentage < 90.9 || percentage > 90.91 {
+		t.Errorf("uptime = %f, want 90.9", percentage)
+	}
+
+	if percentage, _, _ := combineUptime(nil); percentage != 0 {
+		t.Errorf("uptime without checks = %f, want 0", percentage)
+	}
+}
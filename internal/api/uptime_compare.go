@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/uptime"
+)
+
+const (
+	// compareConcurrency bounds how many monitors' uptime is calculated at once
+	compareConcurrency = 4
+
+	// compareMaxMonitors caps the monitors in one comparison
+	compareMaxMonitors = 50
+)
+
+// uptimePeriods are the periods uptime can be compared over
+var uptimePeriods = map[string]time.Duration{
+	"24h": 24 * time.Hour,
+	"7d":  7 * 24 * time.Hour,
+	"30d": 30 * 24 * time.Hour,
+	"90d": 90 * 24 * time.Hour,
+}
+
+// uptimeComparison is one monitor's entry in an uptime comparison
+type uptimeComparison struct {
+	MonitorID int                 `json:"monitor_id"`
+	Name      string              `json:"name"`
+	Stats     *uptime.UptimeStats `json:"stats"`
+}
+
+// uptimeComparisonReport is the response of POST /monitors/uptime/compare
+type uptimeComparisonReport struct {
+	Period   string             `json:"period"`
+	Monitors []uptimeComparison `json:"monitors"`
+}
+
+// parseCompareRequest validates the monitor IDs and period of a comparison.
+// Duplicate IDs are dropped, keeping the first; the period defaults to 24h.
+func parseCompareRequest(monitorIDs []int, period string) ([]int, string, error) {
+	if period == "" {
+		period = "24h"
+	}
+	if _, ok := uptimePeriods[period]; !ok {
+		return nil, "", fmt.Errorf("period must be 24h, 7d, 30d or 90d")
+	}
+
+	seen := make(map[int]bool, len(monitorIDs))
+	ids := make([]int, 0, len(monitorIDs))
+	for _, id := range monitorIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, "", fmt.Errorf("monitor_ids must not be empty")
+	}
+	if len(ids) > compareMaxMonitors {
+		return nil, "", fmt.Errorf("at most %d monitors can be compared", compareMaxMonitors)
+	}
+	return ids, period, nil
+}
+
+// compareUptime calculates the uptime of every monitor, at most concurrency
+// at a time. Stats keep the order of ids; the first error is returned.
+func compareUptime(ids []int, calculate func(monitorID int) (*uptime.UptimeStats, error), concurrency int) ([]*uptime.UptimeStats, error) {
+	stats := make([]*uptime.UptimeStats, len(ids))
+	errs := make([]error, len(ids))
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i, id int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			stats[i], errs[i] = calculate(id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("monitor %d: %w", ids[i], err)
+		}
+	}
+	return stats, nil
+}
+
+// HandleCompareMonitorsUptime returns the uptime of several of the user's
+// monitors over one period, in the order they were requested
+func HandleCompareMonitorsUptime(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+
+		var req struct {
+			MonitorIDs []int  `json:"monitor_ids"`
+			Period     string `json:"period"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "Invalid request body", http.StatusBadRequest)
+			return
+		}
+
+		ids, period, err := parseCompareRequest(req.MonitorIDs, req.Period)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Verify ownership of every monitor
+		var monitors []models.Monitor
+		if err := db.Select("id, name").
+			Where("id IN ? AND user_id = ?", ids, user.ID).
+			Find(&monitors).Error; err != nil {
+			http.Error(w, "Failed to fetch monitors", http.StatusInternalServerError)
+			return
+		}
+		if len(monitors) != len(ids) {
+			http.Error(w, "Monitor not found", http.StatusNotFound)
+			return
+		}
+		names := make(map[int]string, len(monitors))
+		for _, m := range monitors {
+			names[m.ID] = m.Name
+		}
+
+		calculator := uptime.NewCalculator(db)
+		duration := uptimePeriods[period]
+		stats, err := compareUptime(ids, func(monitorID int) (*uptime.UptimeStats, error) {
+			return calculator.CalculateUptimeForPeriod(monitorID, duration)
+		}, compareConcurrency)
+		if err != nil {
+			http.Error(w, "Failed to calculate uptime", http.StatusInternalServerError)
+			return
+		}
+
+		report := uptimeComparisonReport{Period: period, Monitors: make([]uptimeComparison, len(ids))}
+		for i, id := range ids {
+			report.Monitors[i] = uptimeComparison{MonitorID: id, Name: names[id], Stats: stats[i]}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(report)
+	}
+}
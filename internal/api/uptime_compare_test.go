@@ -0,0 +1,87 @@
+package api
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/uptime"
+)
+
+func TestCompareUptimeThreeMonitors(t *testing.T) {
+	stored := map[int]*uptime.UptimeStats{
+		4: {MonitorID: 4, UptimePercentage: 99.95, TotalChecks: 2000, UpChecks: 1999, DownChecks: 1, AveragePing: 42},
+		7: {MonitorID: 7, UptimePercentage: 97.5, TotalChecks: 400, UpChecks: 390, DownChecks: 10, AveragePing: 180},
+		2: {MonitorID: 2, UptimePercentage: 100, TotalChecks: 1440, UpChecks: 1440, AveragePing: 12},
+	}
+
+	var running, peak atomic.Int32
+	stats, err := compareUptime([]int{4, 7, 2}, func(monitorID int) (*uptime.UptimeStats, error) {
+		n := running.Add(1)
+		defer running.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return stored[monitorID], nil
+	}, 2)
+	if err != nil {
+		t.Fatalf("compareUptime: %v", err)
+	}
+
+	if len(stats) != 3 {
+		t.Fatalf("got %d stats, want 3", len(stats))
+	}
+	for i, id := range []int{4, 7, 2} {
+		if stats[i] != stored[id] {
+			t.Errorf("stats[%d] = %+v, want monitor %d's stats", i, stats[i], id)
+		}
+	}
+	if p := peak.Load(); p > 2 {
+		t.Errorf("%d calculations ran at once, want at most 2", p)
+	}
+}
+
+func TestCompareUptimeError(t *testing.T) {
+	_, err := compareUptime([]int{1, 2, 3}, func(monitorID int) (*uptime.UptimeStats, error) {
+		if monitorID == 2 {
+			return nil, errors.New("connection reset")
+		}
+		return &uptime.UptimeStats{MonitorID: monitorID}, nil
+	}, compareConcurrency)
+	if err == nil || err.Error() != "monitor 2: connection reset" {
+		t.Errorf("err = %v, want monitor 2's error", err)
+	}
+}
+
+func TestParseCompareRequest(t *testing.T) {
+	ids, period, err := parseCompareRequest([]int{3, 1, 3, 2}, "")
+	if err != nil {
+		t.Fatalf("parseCompareRequest: %v", err)
+	}
+	if period != "24h" || len(ids) != 3 || ids[0] != 3 || ids[1] != 1 || ids[2] != 2 {
+		t.Errorf("got %v, %q; want [3 1 2], 24h", ids, period)
+	}
+
+	tooMany := make([]int, compareMaxMonitors+1)
+	for i := range tooMany {
+		tooMany[i] = i + 1
+	}
+	invalid := []struct {
+		ids    []int
+		period string
+	}{
+		{nil, "7d"},
+		{[]int{1}, "1y"},
+		{tooMany, "30d"},
+	}
+	for _, tt := range invalid {
+		if _, _, err := parseCompareRequest(tt.ids, tt.period); err == nil {
+			t.Errorf("parseCompareRequest(%d ids, %q) succeeded, want error", len(tt.ids), tt.period)
+		}
+	}
+}
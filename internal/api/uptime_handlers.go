@@ -35,6 +35,11 @@ func HandleGetMonitorUptime(db *gorm.DB) http.HandlerFunc {
 		// Get period from query param (default to 24h)
 		period := r.URL.Query().Get("period")
 
+		if r.URL.Query().Get("business_hours") == "true" {
+			handleBusinessHoursUptime(w, db, calculator, id, period)
+			return
+		}
+
 		var stats *uptime.UptimeStats
 		var err error
 
@@ -59,6 +64,54 @@ func HandleGetMonitorUptime(db *gorm.DB) http.HandlerFunc {
 	}
 }
 
+// handleBusinessHoursUptime answers an uptime request counting only the
+// checks within the monitor's business_hours config
+func handleBusinessHoursUptime(w http.ResponseWriter, db *gorm.DB, calculator *uptime.Calculator, monitorID int, period string) {
+	var mon models.Monitor
+	if err := db.Select("id", "config").First(&mon, monitorID).Error; err != nil {
+		http.Error(w, "Failed to fetch monitor", http.StatusInternalServerError)
+		return
+	}
+	hours, err := uptime.ParseBusinessHours(mon.Config)
+	if err != nil {
+		http.Error(w, "Invalid business_hours: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if hours == nil {
+		http.Error(w, "Monitor has no business_hours configured", http.StatusBadRequest)
+		return
+	}
+
+	stats, err := calculator.CalculateBusinessHoursUptime(monitorID, uptimePeriodDuration(period), hours)
+	if err != nil {
+		http.Error(w, "Failed to calculate uptime", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(stats)
+}
+
+// uptimePeriodDuration maps a period query param, one of uptimePeriods, to
+// its duration, defaulting to 24 hours
+func uptimePeriodDuration(period string) time.Duration {
+	if duration, ok := uptimePeriods[period]; ok {
+		return duration
+	}
+	return uptimePeriods["24h"]
+}
+
+// userTimezone returns the time zone from the user's settings, UTC when
+// they have none
+func userTimezone(db *gorm.DB, userID int) *time.Location {
+	var tz string
+	db.Model(&models.UserSettings{}).
+		Where("user_id = ?", userID).
+		Select("timezone").
+		Scan(&tz)
+	return models.LoadTimezone(tz)
+}
+
 // HandleGetMonitorUptimeHistory returns daily uptime history for a monitor
 func HandleGetMonitorUptimeHistory(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -87,7 +140,7 @@ func HandleGetMonitorUptimeHistory(db *gorm.DB) http.HandlerFunc {
 			}
 		}
 
-		history, err := calculator.GetDailyUptimeHistory(id, days)
+		history, err := calculator.GetDailyUptimeHistory(id, days, userTimezone(db, user.ID))
 		if err != nil {
 			http.Error(w, "Failed to get uptime history", http.StatusInternalServerError)
 			return
@@ -98,6 +151,44 @@ func HandleGetMonitorUptimeHistory(db *gorm.DB) http.HandlerFunc {
 	}
 }
 
+// HandleGetMonitorWeeklyUptime returns weekly uptime history for a monitor
+func HandleGetMonitorWeeklyUptime(db *gorm.DB) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := r.Context().Value(userContextKey).(*models.User)
+		monitorID := chi.URLParam(r, "id")
+
+		// Verify ownership
+		var count int64
+		db.Model(&models.Monitor{}).
+			Where("id = ? AND user_id = ?", monitorID, user.ID).
+			Count(&count)
+		if count == 0 {
+			http.Error(w, "Monitor not found", http.StatusNotFound)
+			return
+		}
+
+		id, _ := strconv.Atoi(monitorID)
+		calculator := uptime.NewCalculator(db)
+
+		// Get weeks from query param (default to 12)
+		weeks := 12
+		if weeksStr := r.URL.Query().Get("weeks"); weeksStr != "" {
+			if n, err := strconv.Atoi(weeksStr); err == nil && n > 0 && n <= 52 {
+				weeks = n
+			}
+		}
+
+		history, err := calculator.GetWeeklyUptimeHistory(id, weeks, userTimezone(db, user.ID))
+		if err != nil {
+			http.Error(w, "Failed to get weekly uptime", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(history)
+	}
+}
+
 // HandleGetMonitorHourlyUptime returns hourly uptime for the last 24 hours
 func HandleGetMonitorHourlyUptime(db *gorm.DB) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -117,7 +208,7 @@ func HandleGetMonitorHourlyUptime(db *gorm.DB) http.HandlerFunc {
 		id, _ := strconv.Atoi(monitorID)
 		calculator := uptime.NewCalculator(db)
 
-		history, err := calculator.GetHourlyUptimeHistory(id)
+		history, err := calculator.GetHourlyUptimeHistory(id, userTimezone(db, user.ID))
 		if err != nil {
 			http.Error(w, "Failed to get hourly uptime", http.StatusInternalServerError)
 			return
@@ -136,21 +227,7 @@ func HandleGetAllMonitorsUptime(db *gorm.DB) http.HandlerFunc {
 		calculator := uptime.NewCalculator(db)
 
 		// Get period from query param (default to 24h)
-		period := r.URL.Query().Get("period")
-		var duration time.Duration
-
-		switch period {
-		case "7d":
-			duration = 7 * 24 * time.Hour
-		case "30d":
-			duration = 30 * 24 * time.Hour
-		case "90d":
-			duration = 90 * 24 * time.Hour
-		default:
-			duration = 24 * time.Hour
-		}
-
-		allStats, err := calculator.GetUptimeForAllMonitors(duration)
+		allStats, err := calculator.GetUptimeForAllMonitors(uptimePeriodDuration(r.URL.Query().Get("period")))
 		if err != nil {
 			http.Error(w, "Failed to calculate uptime", http.StatusInternalServerError)
 			return
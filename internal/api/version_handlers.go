@@ -0,0 +1,50 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/config"
+	"github.com/fuomag9/uptime-kabomba/internal/database"
+	"github.com/fuomag9/uptime-kabomba/internal/version"
+)
+
+// VersionResponse reports the running application and database schema versions
+type VersionResponse struct {
+	Version         string `json:"version"`
+	SchemaVersion   uint   `json:"schema_version"`
+	SchemaDirty     bool   `json:"schema_dirty"`
+	LatestMigration uint   `json:"latest_migration"`
+}
+
+// HandleGetVersion returns the app version and the applied migration version (token required)
+func HandleGetVersion(db *gorm.DB, cfg *config.Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Health-Token") != cfg.HealthToken {
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		schema, err := database.GetSchemaVersion(db)
+		if err != nil {
+			http.Error(w, "Failed to read schema version", http.StatusInternalServerError)
+			return
+		}
+
+		latest, err := database.LatestMigrationVersion(database.MigrationsDir)
+		if err != nil {
+			http.Error(w, "Failed to read migrations", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(VersionResponse{
+			Version:         version.Version,
+			SchemaVersion:   schema.Version,
+			SchemaDirty:     schema.Dirty,
+			LatestMigration: latest,
+		})
+	}
+}
@@ -5,27 +5,57 @@ import (
 	"encoding/base64"
 	"fmt"
 	"log"
+	"net/netip"
 	"net/url"
 	"os"
 	"strconv"
 	"strings"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/jwtkeys"
 )
 
 // Config holds application configuration
 type Config struct {
-	Port                   int
-	Database               DatabaseConfig
-	JWTSecret              string
-	Environment            string
-	CORSOrigins            []string
-	OAuth                  *OAuthConfig
-	AllowPrivateIPs        bool
-	AllowMetadataEndpoints bool
-	MetricsToken           string
-	HealthToken            string
-	ScreenshotStoragePath  string
-	ChromePath             string
-	ChromeEnabled          bool
+	Port                     int
+	Database                 DatabaseConfig
+	JWTSecret                string
+	JWTPreviousSecrets       []string // still accepted during a rotation
+	JWTRotationWindow        int      // seconds previous secrets are accepted after startup
+	JWTKeys                  *jwtkeys.KeySet
+	Environment              string
+	CORSOrigins              []string
+	OAuth                    *OAuthConfig
+	AllowPrivateIPs          bool
+	AllowMetadataEndpoints   bool
+	SSRFAllowedCIDRs         []netip.Prefix // private targets allowed when private IPs are blocked
+	SSRFAllowedHosts         []string
+	MetricsToken             string
+	HealthToken              string
+	ScreenshotStoragePath    string
+	ChromePath               string
+	ChromeEnabled            bool
+	ScriptMonitorEnabled     bool // registers the script monitor type, which runs commands on the server
+	MaxConcurrentChecks      int
+	StalledMonitorMultiplier int
+	APIKeyExpiryWarningDays  int // days before expiry API key owners are warned
+	DBSizeAlertMB            int // database size admins are warned at, 0 disables
+	DBGrowthAlertMB          int // database growth per day admins are warned at, 0 disables
+	MinMonitorInterval       int // seconds; admins may check more often
+	RateLimitExemptCIDRs     []netip.Prefix
+	MassOutageThreshold      int
+	MassOutageWindow         int // seconds
+	NotificationSettleWindow int // seconds a status change must hold before it is notified
+	HeartbeatPartitioning    bool
+	SourceIP                 string
+	LoginLockoutThreshold    int
+	LoginLockoutWindow       int // seconds
+	LoginLockoutDuration     int // seconds
+	WSBroadcastBuffer        int
+	NotificationTimeout      int    // seconds each notification provider request may take
+	NotificationConcurrency  int    // notifications of one event sent at once
+	DemoMode                 string // "true" seeds demo data into an empty database, "wipe" removes it
+	DemoPassword             string
 }
 
 // DatabaseConfig holds database configuration
@@ -43,7 +73,10 @@ type OAuthConfig struct {
 	ClientID     string
 	ClientSecret string
 	RedirectURL  string
-	Scopes       []string
+	// AllowedRedirectURLs are the other callback URLs a login may ask the
+	// provider to return to with redirect_uri, matched exactly
+	AllowedRedirectURLs []string
+	Scopes              []string
 }
 
 // Load loads configuration from environment variables
@@ -52,6 +85,16 @@ func Load() *Config {
 	jwtSecret := loadJWTSecret(env)
 	oauthConfig := loadOAuthConfig()
 
+	exemptCIDRs, err := ParseCIDRList(getEnv("RATE_LIMIT_EXEMPT_CIDRS", ""))
+	if err != nil {
+		log.Fatalf("Invalid RATE_LIMIT_EXEMPT_CIDRS: %v", err)
+	}
+
+	ssrfCIDRs, ssrfHosts, err := ParseSSRFAllowlist(getEnv("SSRF_ALLOWLIST", ""))
+	if err != nil {
+		log.Fatalf("Invalid SSRF_ALLOWLIST: %v", err)
+	}
+
 	cfg := &Config{
 		Port: getEnvInt("PORT", 8080),
 		Database: DatabaseConfig{
@@ -60,17 +103,42 @@ func Load() *Config {
 			MaxOpenConns: getEnvInt("DB_MAX_OPEN_CONNS", 25),
 			MaxIdleConns: getEnvInt("DB_MAX_IDLE_CONNS", 5),
 		},
-		JWTSecret:              jwtSecret,
-		Environment:            env,
-		CORSOrigins:            loadCORSOrigins(env),
-		OAuth:                  oauthConfig,
-		AllowPrivateIPs:        getEnvBool("ALLOW_PRIVATE_IPS", false),
-		AllowMetadataEndpoints: getEnvBool("ALLOW_METADATA_ENDPOINTS", false),
-		MetricsToken:           getEnv("METRICS_TOKEN", ""),
-		HealthToken:            getEnv("HEALTH_TOKEN", ""),
-		ScreenshotStoragePath:  getEnv("SCREENSHOT_STORAGE_PATH", "./data/screenshots"),
-		ChromePath:             getEnv("CHROME_PATH", ""),
-		ChromeEnabled:          getEnvBool("CHROME_ENABLED", true),
+		JWTSecret:                jwtSecret,
+		JWTPreviousSecrets:       splitAndTrim(getEnv("JWT_PREVIOUS_SECRETS", ""), ","),
+		JWTRotationWindow:        getEnvInt("JWT_ROTATION_WINDOW", 7200),
+		Environment:              env,
+		CORSOrigins:              loadCORSOrigins(env),
+		OAuth:                    oauthConfig,
+		AllowPrivateIPs:          getEnvBool("ALLOW_PRIVATE_IPS", false),
+		AllowMetadataEndpoints:   getEnvBool("ALLOW_METADATA_ENDPOINTS", false),
+		SSRFAllowedCIDRs:         ssrfCIDRs,
+		SSRFAllowedHosts:         ssrfHosts,
+		MetricsToken:             getEnv("METRICS_TOKEN", ""),
+		HealthToken:              getEnv("HEALTH_TOKEN", ""),
+		ScreenshotStoragePath:    getEnv("SCREENSHOT_STORAGE_PATH", "./data/screenshots"),
+		ChromePath:               getEnv("CHROME_PATH", ""),
+		ChromeEnabled:            getEnvBool("CHROME_ENABLED", true),
+		ScriptMonitorEnabled:     getEnvBool("SCRIPT_MONITOR_ENABLED", false),
+		MaxConcurrentChecks:      getEnvInt("MAX_CONCURRENT_CHECKS", 0),
+		StalledMonitorMultiplier: getEnvInt("STALLED_MONITOR_MULTIPLIER", 3),
+		APIKeyExpiryWarningDays:  getEnvInt("API_KEY_EXPIRY_WARNING_DAYS", 7),
+		DBSizeAlertMB:            getEnvInt("DB_SIZE_ALERT_MB", 0),
+		DBGrowthAlertMB:          getEnvInt("DB_GROWTH_ALERT_MB", 0),
+		MinMonitorInterval:       getEnvInt("MIN_MONITOR_INTERVAL", 20),
+		RateLimitExemptCIDRs:     exemptCIDRs,
+		MassOutageThreshold:      getEnvInt("MASS_OUTAGE_THRESHOLD", 0),
+		MassOutageWindow:         getEnvInt("MASS_OUTAGE_WINDOW", 30),
+		NotificationSettleWindow: getEnvInt("NOTIFICATION_SETTLE_WINDOW", 0),
+		HeartbeatPartitioning:    getEnvBool("HEARTBEAT_PARTITIONING", false),
+		SourceIP:                 getEnv("SOURCE_IP", ""),
+		LoginLockoutThreshold:    getEnvInt("LOGIN_LOCKOUT_THRESHOLD", 5),
+		LoginLockoutWindow:       getEnvInt("LOGIN_LOCKOUT_WINDOW", 900),
+		LoginLockoutDuration:     getEnvInt("LOGIN_LOCKOUT_DURATION", 900),
+		WSBroadcastBuffer:        getEnvInt("WS_BROADCAST_BUFFER", 256),
+		NotificationTimeout:      getEnvInt("NOTIFICATION_TIMEOUT", 10),
+		NotificationConcurrency:  getEnvInt("NOTIFICATION_CONCURRENCY", 8),
+		DemoMode:                 strings.ToLower(getEnv("DEMO_MODE", "false")),
+		DemoPassword:             getEnv("DEMO_PASSWORD", ""),
 	}
 
 	// Validate configuration
@@ -78,6 +146,11 @@ func Load() *Config {
 		log.Fatalf("Configuration validation failed: %v", err)
 	}
 
+	cfg.JWTKeys = jwtkeys.New(cfg.JWTSecret, cfg.JWTPreviousSecrets, time.Duration(cfg.JWTRotationWindow)*time.Second)
+	if len(cfg.JWTPreviousSecrets) > 0 {
+		log.Printf("JWT secret rotation: accepting %d previous secret(s) for %ds", len(cfg.JWTPreviousSecrets), cfg.JWTRotationWindow)
+	}
+
 	return cfg
 }
 
@@ -125,6 +198,19 @@ func (c *Config) Validate() error {
 		}
 	}
 
+	for _, previous := range c.JWTPreviousSecrets {
+		if len(previous) < 16 {
+			return fmt.Errorf("JWT_PREVIOUS_SECRETS entries must be at least 16 characters long")
+		}
+		if previous == c.JWTSecret {
+			return fmt.Errorf("JWT_PREVIOUS_SECRETS must not contain the current JWT_SECRET")
+		}
+	}
+
+	if len(c.JWTPreviousSecrets) > 0 && c.JWTRotationWindow <= 0 {
+		return fmt.Errorf("JWT_ROTATION_WINDOW must be positive when JWT_PREVIOUS_SECRETS is set")
+	}
+
 	if len(c.CORSOrigins) == 0 {
 		return fmt.Errorf("at least one CORS origin must be configured")
 	}
@@ -141,6 +227,68 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("HEALTH_TOKEN must be set")
 	}
 
+	if c.MaxConcurrentChecks < 0 {
+		return fmt.Errorf("MAX_CONCURRENT_CHECKS must not be negative")
+	}
+
+	if c.StalledMonitorMultiplier < 0 {
+		return fmt.Errorf("STALLED_MONITOR_MULTIPLIER must not be negative")
+	}
+
+	if c.APIKeyExpiryWarningDays < 0 {
+		return fmt.Errorf("API_KEY_EXPIRY_WARNING_DAYS must not be negative")
+	}
+
+	if c.DBSizeAlertMB < 0 {
+		return fmt.Errorf("DB_SIZE_ALERT_MB must not be negative")
+	}
+
+	if c.DBGrowthAlertMB < 0 {
+		return fmt.Errorf("DB_GROWTH_ALERT_MB must not be negative")
+	}
+
+	if c.MinMonitorInterval < 0 {
+		return fmt.Errorf("MIN_MONITOR_INTERVAL must not be negative")
+	}
+
+	if c.MassOutageThreshold < 0 {
+		return fmt.Errorf("MASS_OUTAGE_THRESHOLD must not be negative")
+	}
+
+	if c.MassOutageThreshold > 0 && c.MassOutageWindow <= 0 {
+		return fmt.Errorf("MASS_OUTAGE_WINDOW must be positive when MASS_OUTAGE_THRESHOLD is set")
+	}
+
+	if c.NotificationSettleWindow < 0 {
+		return fmt.Errorf("NOTIFICATION_SETTLE_WINDOW must not be negative")
+	}
+
+	if c.LoginLockoutThreshold < 0 {
+		return fmt.Errorf("LOGIN_LOCKOUT_THRESHOLD must not be negative")
+	}
+
+	if c.LoginLockoutThreshold > 0 && (c.LoginLockoutWindow <= 0 || c.LoginLockoutDuration <= 0) {
+		return fmt.Errorf("LOGIN_LOCKOUT_WINDOW and LOGIN_LOCKOUT_DURATION must be positive when LOGIN_LOCKOUT_THRESHOLD is set")
+	}
+
+	if c.WSBroadcastBuffer < 1 {
+		return fmt.Errorf("WS_BROADCAST_BUFFER must be at least 1")
+	}
+
+	if c.NotificationTimeout < 1 {
+		return fmt.Errorf("NOTIFICATION_TIMEOUT must be at least 1 second")
+	}
+
+	if c.NotificationConcurrency < 1 {
+		return fmt.Errorf("NOTIFICATION_CONCURRENCY must be at least 1")
+	}
+
+	switch c.DemoMode {
+	case "true", "false", "wipe":
+	default:
+		return fmt.Errorf("DEMO_MODE must be true, false or wipe")
+	}
+
 	// Validate OAuth config if enabled
 	if c.OAuth != nil && c.OAuth.Enabled {
 		if c.OAuth.Issuer == "" {
@@ -150,6 +298,11 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("OAUTH_CLIENT_ID and OAUTH_CLIENT_SECRET are required when OAuth is enabled")
 		}
 		// OAuth redirect URL is derived from APP_URL
+		for _, u := range c.OAuth.AllowedRedirectURLs {
+			if err := ValidateRedirectURL(u); err != nil {
+				return fmt.Errorf("OAUTH_ALLOWED_REDIRECT_URLS: %w", err)
+			}
+		}
 	}
 
 	return nil
@@ -194,6 +347,74 @@ func loadCORSOrigins(env string) []string {
 	return []string{"http://localhost:3000", "http://localhost:8080"}
 }
 
+// ParseCIDRList parses a comma separated list of CIDRs. Bare IP addresses are
+// accepted as single-host prefixes.
+func ParseCIDRList(value string) ([]netip.Prefix, error) {
+	var prefixes []netip.Prefix
+	for _, part := range splitAndTrim(value, ",") {
+		if !strings.Contains(part, "/") {
+			addr, err := netip.ParseAddr(part)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP or CIDR %q", part)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+
+		prefix, err := netip.ParsePrefix(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", part, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// ParseSSRFAllowlist parses a comma separated list of IPs, CIDRs and host
+// names. Host names are lowercased without a trailing dot.
+func ParseSSRFAllowlist(value string) ([]netip.Prefix, []string, error) {
+	var cidrs []string
+	var hosts []string
+	for _, part := range splitAndTrim(value, ",") {
+		if _, err := netip.ParseAddr(part); err == nil || strings.Contains(part, "/") {
+			cidrs = append(cidrs, part)
+			continue
+		}
+
+		host := strings.TrimSuffix(strings.ToLower(part), ".")
+		if !isValidHostname(host) {
+			return nil, nil, fmt.Errorf("invalid host name %q", part)
+		}
+		hosts = append(hosts, host)
+	}
+
+	prefixes, err := ParseCIDRList(strings.Join(cidrs, ","))
+	if err != nil {
+		return nil, nil, err
+	}
+	return prefixes, hosts, nil
+}
+
+// isValidHostname checks that a lowercase host name only has letters, digits,
+// hyphens and underscores in non-empty, dot separated labels
+func isValidHostname(host string) bool {
+	if host == "" || len(host) > 253 {
+		return false
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 {
+			return false
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return false
+			}
+		}
+	}
+	return true
+}
+
 func splitAndTrim(s, sep string) []string {
 	parts := []string{}
 	for i := 0; i < len(s); {
@@ -284,11 +505,36 @@ func loadOAuthConfig() *OAuthConfig {
 	}
 
 	return &OAuthConfig{
-		Enabled:      true,
-		Issuer:       issuer,
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		RedirectURL:  redirectURL,
-		Scopes:       scopes,
+		Enabled:             true,
+		Issuer:              issuer,
+		ClientID:            clientID,
+		ClientSecret:        clientSecret,
+		RedirectURL:         redirectURL,
+		AllowedRedirectURLs: splitAndTrim(os.Getenv("OAUTH_ALLOWED_REDIRECT_URLS"), ","),
+		Scopes:              scopes,
+	}
+}
+
+// ValidateRedirectURL checks that an OAuth redirect URL is absolute and has
+// no fragment, as OAuth 2.0 requires. Besides http and https URLs, app deep
+// links with their own scheme (e.g. "myapp://oauth/callback") are accepted.
+func ValidateRedirectURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil {
+		return fmt.Errorf("invalid redirect URL %q: %w", value, err)
 	}
+	switch u.Scheme {
+	case "":
+		return fmt.Errorf("redirect URL %q must be absolute", value)
+	case "http", "https":
+		if u.Host == "" {
+			return fmt.Errorf("redirect URL %q has no host", value)
+		}
+	case "javascript", "data", "vbscript", "file":
+		return fmt.Errorf("redirect URL %q has a disallowed scheme", value)
+	}
+	if u.Fragment != "" || strings.HasSuffix(value, "#") {
+		return fmt.Errorf("redirect URL %q must not have a fragment", value)
+	}
+	return nil
 }
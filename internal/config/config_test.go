@@ -0,0 +1,62 @@
+package config
+
+import "testing"
+
+func TestParseCIDRList(t *testing.T) {
+	prefixes, err := ParseCIDRList("10.0.0.0/8, 192.168.1.7 ,2001:db8::/32, 172.16.5.9/12")
+	if err != nil {
+		t.Fatalf("ParseCIDRList: %v", err)
+	}
+
+	want := []string{"10.0.0.0/8", "192.168.1.7/32", "2001:db8::/32", "172.16.0.0/12"}
+	if len(prefixes) != len(want) {
+		t.Fatalf("got %v, want %v", prefixes, want)
+	}
+	for i, p := range prefixes {
+		if p.String() != want[i] {
+			t.Errorf("prefix %d = %s, want %s", i, p, want[i])
+		}
+	}
+
+	if prefixes, err := ParseCIDRList(""); err != nil || len(prefixes) != 0 {
+		t.Errorf("empty list = %v, %v; want none", prefixes, err)
+	}
+
+	for _, invalid := range []string{"10.0.0.0/33", "not-an-ip", "10.0.0.1, bogus/8"} {
+		if _, err := ParseCIDRList(invalid); err == nil {
+			t.Errorf("ParseCIDRList(%q) succeeded, want error", invalid)
+		}
+	}
+}
+
+func TestParseSSRFAllowlist(t *testing.T) {
+	cidrs, hosts, err := ParseSSRFAllowlist("10.0.5.20, internal.db ,172.16.0.0/16, Build-01.Example.com.")
+	if err != nil {
+		t.Fatalf("ParseSSRFAllowlist: %v", err)
+	}
+	if len(cidrs) != 2 || cidrs[0].String() != "10.0.5.20/32" || cidrs[1].String() != "172.16.0.0/16" {
+		t.Errorf("cidrs = %v, want [10.0.5.20/32 172.16.0.0/16]", cidrs)
+	}
+	if len(hosts) != 2 || hosts[0] != "internal.db" || hosts[1] != "build-01.example.com" {
+		t.Errorf("hosts = %v, want [internal.db build-01.example.com]", hosts)
+	}
+
+	for _, invalid := range []string{"10.0.0.0/33", "http://internal.db", "bad..host", "db:5432"} {
+		if _, _, err := ParseSSRFAllowlist(invalid); err == nil {
+			t.Errorf("ParseSSRFAllowlist(%q) succeeded, want error", invalid)
+		}
+	}
+}
+
+func TestValidateRedirectURL(t *testing.T) {
+	for _, valid := range []string{"https://example.com/monitoring/oauth/callback", "http://localhost:3000/oauth/callback", "myapp://oauth/callback"} {
+		if err := ValidateRedirectURL(valid); err != nil {
+			t.Errorf("ValidateRedirectURL(%q) = %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"/oauth/callback", "example.com/oauth/callback", "javascript:alert(1)", "https:///cb", "https://example.com/cb#frag", "https://example.com/cb#"} {
+		if err := ValidateRedirectURL(invalid); err == nil {
+			t.Errorf("ValidateRedirectURL(%q) succeeded, want error", invalid)
+		}
+	}
+}
@@ -2,13 +2,29 @@ package database
 
 import (
 	"fmt"
+	"os"
+	"regexp"
+	"strconv"
 
 	"github.com/golang-migrate/migrate/v4"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"gorm.io/gorm"
+
 	"github.com/fuomag9/uptime-kabomba/internal/config"
 )
 
+// MigrationsDir is the directory holding the postgres migration files
+const MigrationsDir = "./migrations/postgres"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_.+\.up\.sql$`)
+
+// SchemaVersion describes the migration state recorded in the database
+type SchemaVersion struct {
+	Version uint `json:"version"`
+	Dirty   bool `json:"dirty"`
+}
+
 // RunMigrations runs database migrations
 func RunMigrations(cfg config.DatabaseConfig) error {
 	// Connect to database for migrations
@@ -29,7 +45,7 @@ func RunMigrations(cfg config.DatabaseConfig) error {
 	}
 
 	driver, err := postgres.WithInstance(sqlDB, &postgres.Config{})
-	migrationsPath := "file://./migrations/postgres"
+	migrationsPath := "file://" + MigrationsDir
 
 	if err != nil {
 		return fmt.Errorf("failed to create migration driver: %w", err)
@@ -44,6 +60,21 @@ func RunMigrations(cfg config.DatabaseConfig) error {
 		return fmt.Errorf("failed to create migration instance: %w", err)
 	}
 
+	// Refuse to run against a schema written by a newer binary
+	latest, err := LatestMigrationVersion(MigrationsDir)
+	if err != nil {
+		return err
+	}
+	current, _, err := m.Version()
+	if err != nil && err != migrate.ErrNilVersion {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+	if err == nil {
+		if err := checkSchemaVersion(current, latest); err != nil {
+			return err
+		}
+	}
+
 	// Run migrations
 	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
 		return fmt.Errorf("failed to run migrations: %w", err)
@@ -51,3 +82,46 @@ func RunMigrations(cfg config.DatabaseConfig) error {
 
 	return nil
 }
+
+// GetSchemaVersion reads the applied migration version from the schema_migrations table
+func GetSchemaVersion(db *gorm.DB) (*SchemaVersion, error) {
+	var version SchemaVersion
+	err := db.Raw("SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read schema version: %w", err)
+	}
+	return &version, nil
+}
+
+// LatestMigrationVersion returns the highest migration version shipped in dir
+func LatestMigrationVersion(dir string) (uint, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read migrations directory: %w", err)
+	}
+
+	var latest uint
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+
+	return latest, nil
+}
+
+// checkSchemaVersion fails if the database has migrations this binary doesn't know about
+func checkSchemaVersion(current, latest uint) error {
+	if current > latest {
+		return fmt.Errorf("database schema version %d is ahead of this binary (latest known migration %d); upgrade the application before starting it", current, latest)
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package database
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLatestMigrationVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{
+		"000001_initial_schema.up.sql",
+		"000001_initial_schema.down.sql",
+		"000018_add_index.up.sql",
+		"000018_add_index.down.sql",
+		"000020_future.down.sql",
+		"README.md",
+	} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	got, err := LatestMigrationVersion(dir)
+	if err != nil {
+		t.Fatalf("LatestMigrationVersion() error = %v", err)
+	}
+	if got != 18 {
+		t.Fatalf("LatestMigrationVersion() = %d, want 18", got)
+	}
+}
+
+func TestLatestMigrationVersionShippedMigrations(t *testing.T) {
+	got, err := LatestMigrationVersion(filepath.Join("..", "..", MigrationsDir))
+	if err != nil {
+		t.Fatalf("LatestMigrationVersion() error = %v", err)
+	}
+	if got == 0 {
+		t.Fatal("expected shipped migrations to have a non-zero version")
+	}
+}
+
+func TestCheckSchemaVersion(t *testing.T) {
+	if err := checkSchemaVersion(18, 18); err != nil {
+		t.Fatalf("same version should be accepted: %v", err)
+	}
+	if err := checkSchemaVersion(10, 18); err != nil {
+		t.Fatalf("older schema should be accepted: %v", err)
+	}
+	if err := checkSchemaVersion(19, 18); err == nil {
+		t.Fatal("schema ahead of binary should be rejected")
+	}
+}
@@ -0,0 +1,330 @@
+// Package demo seeds sample monitors, heartbeat history and a status page so
+// a new installation shows a working product right away (DEMO_MODE)
+package demo
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"log"
+	"math"
+	mathrand "math/rand"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+const (
+	// Username is the user owning all demo data; deleting it wipes the demo
+	Username = "demo"
+	// Email marks the demo user, so a real user named "demo" is never wiped
+	Email = "demo@uptime-kabomba.invalid"
+	// NamePrefix marks seeded monitors as demo data
+	NamePrefix = "[Demo] "
+	// StatusPageSlug is the slug of the seeded status page
+	StatusPageSlug = "demo"
+
+	// historyLength is how much heartbeat history is generated
+	historyLength = 24 * time.Hour
+)
+
+// outage is a period in which a sample monitor is down, relative to now
+type outage struct {
+	ago      time.Duration
+	duration time.Duration
+	message  string
+}
+
+// sampleMonitor describes a seeded monitor and the shape of its history
+type sampleMonitor struct {
+	name     string
+	kind     string
+	url      string
+	interval int // seconds
+	config   map[string]interface{}
+
+	basePing  int     // typical response time in milliseconds
+	jitter    float64 // spread of response times, as a log-normal sigma
+	flakiness float64 // chance that a single check fails on its own
+	outages   []outage
+}
+
+var samples = []sampleMonitor{
+	{
+		name: "Website", kind: "http", url: "https://example.com", interval: 60,
+		config:   map[string]interface{}{"method": "GET"},
+		basePing: 120, jitter: 0.25, flakiness: 0.002,
+	},
+	{
+		name: "Public API", kind: "http", url: "https://example.org/health", interval: 60,
+		config:   map[string]interface{}{"method": "GET"},
+		basePing: 240, jitter: 0.35, flakiness: 0.004,
+		outages: []outage{{ago: 5 * time.Hour, duration: 18 * time.Minute, message: "HTTP 503 - Service Unavailable"}},
+	},
+	{
+		name: "TLS Port", kind: "tcp", url: "example.net", interval: 120,
+		config:   map[string]interface{}{"port": 443.0},
+		basePing: 45, jitter: 0.2, flakiness: 0.01,
+	},
+	{
+		name: "DNS", kind: "dns", url: "example.com", interval: 300,
+		config:   map[string]interface{}{"dns_server": "1.1.1.1", "query_type": "A"},
+		basePing: 18, jitter: 0.4,
+	},
+}
+
+// Dataset is the demo data seeded into an empty database
+type Dataset struct {
+	User       models.User
+	Monitors   []models.Monitor
+	Heartbeats [][]models.Heartbeat // per monitor, in Monitors order
+	StatusPage models.StatusPage
+	Incidents  []models.Incident
+}
+
+// Build generates the demo data, with heartbeat history up to now. The user
+// gets passwordHash; IDs are assigned when the data is saved.
+func Build(now time.Time, rng *mathrand.Rand, passwordHash string) *Dataset {
+	email := Email
+	data := &Dataset{
+		User: models.User{
+			Username:  Username,
+			Email:     &email,
+			Password:  passwordHash,
+			Provider:  new("local"),
+			Active:    true,
+			CreatedAt: now,
+		},
+		StatusPage: models.StatusPage{
+			Slug:                 StatusPageSlug,
+			Title:                "Demo Status Page",
+			Description:          "Sample data seeded by DEMO_MODE. Start the server with DEMO_MODE=wipe to remove it.",
+			Published:            true,
+			ShowPoweredBy:        true,
+			Theme:                "light",
+			ConfirmationChecks:   1,
+			ShowUptimePercentage: true,
+		},
+	}
+
+	for _, sample := range samples {
+		data.Monitors = append(data.Monitors, models.Monitor{
+			Name:                    NamePrefix + sample.name,
+			Type:                    sample.kind,
+			URL:                     sample.url,
+			Interval:                sample.interval,
+			Timeout:                 30,
+			IPVersion:               "auto",
+			Active:                  true,
+			Public:                  true,
+			NotificationsConfigured: true,
+			Config:                  sample.config,
+			CreatedAt:               now.Add(-historyLength),
+			UpdatedAt:               now,
+		})
+		data.Heartbeats = append(data.Heartbeats, generateHeartbeats(sample, now, rng))
+
+		for _, o := range sample.outages {
+			start := now.Add(-o.ago)
+			resolved := start.Add(o.duration)
+			data.Incidents = append(data.Incidents, models.Incident{
+				Title:      fmt.Sprintf("%s outage", sample.name),
+				Content:    fmt.Sprintf("%s was unavailable for %d minutes (demo incident).", sample.name, int(o.duration.Minutes())),
+				Style:      "danger",
+				CreatedAt:  start,
+				UpdatedAt:  resolved,
+				ResolvedAt: &resolved,
+			})
+		}
+	}
+	return data
+}
+
+// generateHeartbeats simulates the checks of a sample monitor over the last
+// historyLength. Response times are log-normal around the monitor's base with
+// occasional slow spikes, and checks fail on their own now and then besides
+// the scripted outages.
+func generateHeartbeats(sample sampleMonitor, now time.Time, rng *mathrand.Rand) []models.Heartbeat {
+	interval := time.Duration(sample.interval) * time.Second
+	count := int(historyLength / interval)
+	heartbeats := make([]models.Heartbeat, 0, count)
+
+	lastStatus := -1
+	for i := count; i > 0; i-- {
+		at := now.Add(-time.Duration(i) * interval)
+		hb := models.Heartbeat{Status: 1, Time: at}
+
+		if o, down := outageAt(sample.outages, now, at); down {
+			hb.Status = 0
+			hb.Message = o.message
+		} else if rng.Float64() < sample.flakiness {
+			hb.Status = 0
+			hb.Message = "Request failed: context deadline exceeded"
+		} else {
+			ping := float64(sample.basePing) * math.Exp(rng.NormFloat64()*sample.jitter)
+			if rng.Float64() < 0.01 {
+				ping *= 3 + rng.Float64()*5
+			}
+			hb.Ping = max(1, int(ping))
+			hb.Message = upMessage(sample, hb.Ping)
+		}
+
+		hb.Important = hb.Status != lastStatus
+		lastStatus = hb.Status
+		heartbeats = append(heartbeats, hb)
+	}
+	return heartbeats
+}
+
+// outageAt returns the scripted outage covering at, if any
+func outageAt(outages []outage, now, at time.Time) (outage, bool) {
+	for _, o := range outages {
+		start := now.Add(-o.ago)
+		if !at.Before(start) && at.Before(start.Add(o.duration)) {
+			return o, true
+		}
+	}
+	return outage{}, false
+}
+
+// upMessage mirrors the message of a successful check of the monitor's type
+func upMessage(sample sampleMonitor, ping int) string {
+	switch sample.kind {
+	case "tcp":
+		return fmt.Sprintf("Port %d is open - %dms", int(sample.config["port"].(float64)), ping)
+	case "dns":
+		return fmt.Sprintf("%s query OK - 93.184.215.14 - %dms", sample.config["query_type"], ping)
+	}
+	return fmt.Sprintf("HTTP 200 - %dms", ping)
+}
+
+// Seeder seeds the demo data into an empty database
+type Seeder struct {
+	db  *gorm.DB
+	now func() time.Time
+	rng *mathrand.Rand
+
+	// isEmpty reports whether the database holds no users, monitors or
+	// status pages
+	isEmpty func() (bool, error)
+	// save stores a dataset
+	save func(data *Dataset) error
+}
+
+// NewSeeder creates a seeder for db
+func NewSeeder(db *gorm.DB) *Seeder {
+	s := &Seeder{
+		db:  db,
+		now: time.Now,
+		rng: mathrand.New(mathrand.NewSource(time.Now().UnixNano())),
+	}
+	s.isEmpty = s.databaseIsEmpty
+	s.save = s.saveDataset
+	return s
+}
+
+// Seed seeds the demo data, with password for the demo user, when the
+// database is empty. It returns false without changes when any user, monitor
+// or status page exists, including data seeded before.
+func (s *Seeder) Seed(password string) (bool, error) {
+	empty, err := s.isEmpty()
+	if err != nil {
+		return false, fmt.Errorf("failed to check for existing data: %w", err)
+	}
+	if !empty {
+		return false, nil
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return false, fmt.Errorf("failed to hash demo password: %w", err)
+	}
+
+	if err := s.save(Build(s.now(), s.rng, string(hash))); err != nil {
+		return false, fmt.Errorf("failed to save demo data: %w", err)
+	}
+	return true, nil
+}
+
+// databaseIsEmpty reports whether there are no users, monitors or status pages
+func (s *Seeder) databaseIsEmpty() (bool, error) {
+	for _, model := range []interface{}{&models.User{}, &models.Monitor{}, &models.StatusPage{}} {
+		var count int64
+		if err := s.db.Model(model).Count(&count).Error; err != nil {
+			return false, err
+		}
+		if count > 0 {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// saveDataset stores a dataset in one transaction
+func (s *Seeder) saveDataset(data *Dataset) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&data.User).Error; err != nil {
+			return err
+		}
+
+		data.StatusPage.UserID = data.User.ID
+		if err := tx.Omit("Monitors", "Incidents").Create(&data.StatusPage).Error; err != nil {
+			return err
+		}
+
+		for i := range data.Monitors {
+			monitor := &data.Monitors[i]
+			monitor.UserID = data.User.ID
+			if err := tx.Create(monitor).Error; err != nil {
+				return err
+			}
+
+			heartbeats := data.Heartbeats[i]
+			for j := range heartbeats {
+				heartbeats[j].MonitorID = monitor.ID
+			}
+			if err := tx.Omit("Monitor").CreateInBatches(heartbeats, 500).Error; err != nil {
+				return err
+			}
+
+			link := models.StatusPageMonitor{StatusPageID: data.StatusPage.ID, MonitorID: monitor.ID, DisplayOrder: i}
+			if err := tx.Create(&link).Error; err != nil {
+				return err
+			}
+		}
+
+		for i := range data.Incidents {
+			data.Incidents[i].StatusPageID = data.StatusPage.ID
+		}
+		if len(data.Incidents) > 0 {
+			if err := tx.Create(&data.Incidents).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Wipe removes the demo user and, through cascading deletes, every monitor,
+// heartbeat and status page seeded with it. It returns false when there is
+// no demo data.
+func Wipe(db *gorm.DB) (bool, error) {
+	result := db.Where("username = ? AND email = ?", Username, Email).Delete(&models.User{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// RandomPassword returns a password for the demo user when DEMO_PASSWORD is
+// not set
+func RandomPassword() string {
+	bytes := make([]byte, 12)
+	if _, err := rand.Read(bytes); err != nil {
+		log.Fatal("Failed to generate demo password:", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(bytes)
+}
@@ -0,0 +1,127 @@
+package demo
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newMemorySeeder returns a seeder storing datasets in memory, with existing
+// counting as data already in the database
+func newMemorySeeder(existing int) (*Seeder, *[]*Dataset) {
+	var saved []*Dataset
+	s := &Seeder{
+		now: func() time.Time { return time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) },
+		rng: rand.New(rand.NewSource(1)),
+	}
+	s.isEmpty = func() (bool, error) { return existing+len(saved) == 0, nil }
+	s.save = func(data *Dataset) error {
+		saved = append(saved, data)
+		return nil
+	}
+	return s, &saved
+}
+
+func TestSeedIsIdempotent(t *testing.T) {
+	s, saved := newMemorySeeder(0)
+
+	seeded, err := s.Seed("demo-password")
+	if err != nil || !seeded {
+		t.Fatalf("first Seed = %v, %v; want seeded", seeded, err)
+	}
+	seeded, err = s.Seed("demo-password")
+	if err != nil || seeded {
+		t.Fatalf("second Seed = %v, %v; want skipped", seeded, err)
+	}
+	if len(*saved) != 1 {
+		t.Fatalf("saved %d datasets, want 1", len(*saved))
+	}
+}
+
+func TestSeedSkippedWithExistingData(t *testing.T) {
+	s, saved := newMemorySeeder(1)
+
+	seeded, err := s.Seed("demo-password")
+	if err != nil || seeded {
+		t.Fatalf("Seed = %v, %v; want skipped", seeded, err)
+	}
+	if len(*saved) != 0 {
+		t.Fatalf("saved %d datasets into a database with data", len(*saved))
+	}
+}
+
+func TestBuildMarksDemoData(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	data := Build(now, rand.New(rand.NewSource(1)), "hash")
+
+	if data.User.Username != Username || data.User.Email == nil || *data.User.Email != Email {
+		t.Errorf("user = %q %v, want the demo user", data.User.Username, data.User.Email)
+	}
+	if data.StatusPage.Slug != StatusPageSlug || !data.StatusPage.Published {
+		t.Errorf("status page = %+v, want a published demo page", data.StatusPage)
+	}
+	if len(data.Monitors) != len(samples) || len(data.Heartbeats) != len(samples) {
+		t.Fatalf("got %d monitors and %d histories, want %d", len(data.Monitors), len(data.Heartbeats), len(samples))
+	}
+	for _, m := range data.Monitors {
+		if !strings.HasPrefix(m.Name, NamePrefix) {
+			t.Errorf("monitor %q is not marked as demo data", m.Name)
+		}
+	}
+	if len(data.Incidents) == 0 || data.Incidents[0].ResolvedAt == nil {
+		t.Errorf("incidents = %+v, want the resolved sample outage", data.Incidents)
+	}
+}
+
+func TestGenerateHeartbeatsDistribution(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	rng := rand.New(rand.NewSource(7))
+
+	for _, sample := range samples {
+		heartbeats := generateHeartbeats(sample, now, rng)
+		if want := int(historyLength / (time.Duration(sample.interval) * time.Second)); len(heartbeats) != want {
+			t.Fatalf("%s: %d heartbeats, want %d", sample.name, len(heartbeats), want)
+		}
+
+		var up, pingSum int
+		for i, hb := range heartbeats {
+			if i > 0 && !hb.Time.After(heartbeats[i-1].Time) {
+				t.Fatalf("%s: heartbeats are not in time order", sample.name)
+			}
+			if hb.Important != (i == 0 || hb.Status != heartbeats[i-1].Status) {
+				t.Fatalf("%s: heartbeat %d important = %v, want it on status changes only", sample.name, i, hb.Important)
+			}
+			if hb.Status == 1 {
+				up++
+				pingSum += hb.Ping
+			}
+		}
+		if !heartbeats[len(heartbeats)-1].Time.Before(now) {
+			t.Errorf("%s: history reaches into the future", sample.name)
+		}
+
+		uptime := float64(up) / float64(len(heartbeats))
+		if uptime < 0.95 || uptime == 1 && len(sample.outages) > 0 {
+			t.Errorf("%s: uptime %.4f is not realistic", sample.name, uptime)
+		}
+		if avg := pingSum / up; avg < sample.basePing/2 || avg > sample.basePing*2 {
+			t.Errorf("%s: average ping %dms, want near %dms", sample.name, avg, sample.basePing)
+		}
+	}
+
+	// The scripted outage shows as a run of consecutive down checks
+	heartbeats := generateHeartbeats(samples[1], now, rng)
+	longest, run := 0, 0
+	for _, hb := range heartbeats {
+		if hb.Status == 0 {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	if want := int(samples[1].outages[0].duration / time.Minute); longest < want {
+		t.Errorf("longest outage is %d checks, want at least %d", longest, want)
+	}
+}
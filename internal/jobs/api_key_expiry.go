@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
+)
+
+// apiKeyExpiry is an API key with an expiry date
+type apiKeyExpiry struct {
+	ID               int        `gorm:"column:id"`
+	UserID           int        `gorm:"column:user_id"`
+	Name             string     `gorm:"column:name"`
+	ExpiresAt        time.Time  `gorm:"column:expires_at"`
+	ExpiryNotifiedAt *time.Time `gorm:"column:expiry_notified_at"`
+}
+
+// APIKeyExpiryNotifier warns users before their API keys expire, so
+// integrations using them can be moved to a new key in time
+type APIKeyExpiryNotifier struct {
+	db         *gorm.DB
+	dispatcher *notification.Dispatcher
+	window     time.Duration
+}
+
+// NewAPIKeyExpiryNotifier creates a notifier that warns once a key expires
+// within the given number of days
+func NewAPIKeyExpiryNotifier(db *gorm.DB, dispatcher *notification.Dispatcher, days int) *APIKeyExpiryNotifier {
+	return &APIKeyExpiryNotifier{
+		db:         db,
+		dispatcher: dispatcher,
+		window:     time.Duration(days) * 24 * time.Hour,
+	}
+}
+
+// Check warns the owners of keys entering the window, once per key
+func (n *APIKeyExpiryNotifier) Check() error {
+	var keys []apiKeyExpiry
+	err := n.db.Table("api_keys").
+		Select("id, user_id, name, expires_at, expiry_notified_at").
+		Where("expires_at IS NOT NULL AND expiry_notified_at IS NULL").
+		Scan(&keys).Error
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, key := range findExpiringAPIKeys(keys, n.window, now) {
+		log.Printf("API key %s (ID: %d) of user %d expires at %s",
+			key.Name, key.ID, key.UserID, key.ExpiresAt.Format(time.RFC3339))
+
+		if n.dispatcher != nil {
+			days := int(key.ExpiresAt.Sub(now) / (24 * time.Hour))
+			message := fmt.Sprintf("The API key %q expires in %d day(s), at %s. Create a new key for the integrations using it.",
+				key.Name, days, key.ExpiresAt.Format(time.RFC3339))
+			if err := n.dispatcher.NotifyAPIKeyExpiring(context.Background(), key.UserID, key.Name, message); err != nil {
+				log.Printf("Failed to send expiry notification for API key %d: %v", key.ID, err)
+				continue
+			}
+		}
+
+		if err := n.db.Table("api_keys").Where("id = ?", key.ID).Update("expiry_notified_at", now).Error; err != nil {
+			log.Printf("Failed to mark API key %d as notified: %v", key.ID, err)
+		}
+	}
+
+	return nil
+}
+
+// findExpiringAPIKeys returns the keys not yet warned about that expire
+// within window. Keys that already expired are skipped: warning about them
+// is too late to help.
+func findExpiringAPIKeys(keys []apiKeyExpiry, window time.Duration, now time.Time) []apiKeyExpiry {
+	var expiring []apiKeyExpiry
+	for _, key := range keys {
+		if key.ExpiryNotifiedAt != nil || !key.ExpiresAt.After(now) {
+			continue
+		}
+		if key.ExpiresAt.Sub(now) <= window {
+			expiring = append(expiring, key)
+		}
+	}
+	return expiring
+}
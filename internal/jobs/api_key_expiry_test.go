@@ -0,0 +1,29 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindExpiringAPIKeys(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	warned := now.Add(-24 * time.Hour)
+
+	keys := []apiKeyExpiry{
+		{ID: 1, Name: "soon", ExpiresAt: now.Add(3 * 24 * time.Hour)},
+		{ID: 2, Name: "later", ExpiresAt: now.Add(30 * 24 * time.Hour)},
+		{ID: 3, Name: "expired", ExpiresAt: now.Add(-time.Hour)},
+		{ID: 4, Name: "already warned", ExpiresAt: now.Add(2 * 24 * time.Hour), ExpiryNotifiedAt: &warned},
+	}
+
+	expiring := findExpiringAPIKeys(keys, 7*24*time.Hour, now)
+	if len(expiring) != 1 || expiring[0].ID != 1 {
+		t.Fatalf("expiring = %+v, want only key 1", expiring)
+	}
+
+	// A wider window reaches the later key, but never the expired one
+	expiring = findExpiringAPIKeys(keys, 60*24*time.Hour, now)
+	if len(expiring) != 2 || expiring[0].ID != 1 || expiring[1].ID != 2 {
+		t.Fatalf("expiring with a 60 day window = %+v, want keys 1 and 2", expiring)
+	}
+}
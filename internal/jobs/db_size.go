@@ -0,0 +1,123 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
+)
+
+const (
+	// growthWindow is how far back growth is measured
+	growthWindow = 24 * time.Hour
+
+	// minGrowthSpan is the shortest sample span growth is extrapolated from,
+	// so a burst right after startup doesn't read as a daily rate
+	minGrowthSpan = 6 * time.Hour
+
+	bytesPerMB = 1024 * 1024
+)
+
+// sizeSample is the database size at a point in time
+type sizeSample struct {
+	at   time.Time
+	size int64
+}
+
+// DatabaseSizeWatch warns admins before the database fills the disk: when it
+// is larger than a size or grows faster than a rate per day
+type DatabaseSizeWatch struct {
+	db          *gorm.DB
+	dispatcher  *notification.Dispatcher
+	maxSize     int64 // bytes, 0 disables
+	maxGrowth   int64 // bytes per day, 0 disables
+	mu          sync.Mutex
+	samples     []sizeSample // within growthWindow, oldest first
+	sizeAlerted bool
+	growthAlert bool
+}
+
+// NewDatabaseSizeWatch creates a watch alerting at sizeMB MiB and at growthMB
+// MiB of growth per day; 0 disables either
+func NewDatabaseSizeWatch(db *gorm.DB, dispatcher *notification.Dispatcher, sizeMB, growthMB int) *DatabaseSizeWatch {
+	return &DatabaseSizeWatch{
+		db:         db,
+		dispatcher: dispatcher,
+		maxSize:    int64(sizeMB) * bytesPerMB,
+		maxGrowth:  int64(growthMB) * bytesPerMB,
+	}
+}
+
+// Check samples the database size and notifies admins of thresholds it
+// crossed since the last check
+func (w *DatabaseSizeWatch) Check() error {
+	var size int64
+	if err := w.db.Raw("SELECT pg_database_size(current_database())").Scan(&size).Error; err != nil {
+		return err
+	}
+
+	for _, message := range w.evaluate(size, time.Now()) {
+		log.Printf("Database size alert: %s", message)
+		if w.dispatcher == nil {
+			continue
+		}
+		if err := w.dispatcher.NotifyDatabaseSize(context.Background(), message); err != nil {
+			log.Printf("Failed to send database size notification: %v", err)
+		}
+	}
+	return nil
+}
+
+// evaluate records a size sample and returns a message for each threshold
+// newly crossed. Each alerts once, and again only after the database fell
+// back below it.
+func (w *DatabaseSizeWatch) evaluate(size int64, now time.Time) []string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	var messages []string
+
+	if w.maxSize > 0 {
+		over := size > w.maxSize
+		if over && !w.sizeAlerted {
+			messages = append(messages, fmt.Sprintf("The database is %s, over the %s limit.",
+				formatMB(size), formatMB(w.maxSize)))
+		}
+		w.sizeAlerted = over
+	}
+
+	// Keep the samples of the window, plus this one
+	kept := w.samples[:0]
+	for _, sample := range w.samples {
+		if now.Sub(sample.at) <= growthWindow {
+			kept = append(kept, sample)
+		}
+	}
+	w.samples = append(kept, sizeSample{at: now, size: size})
+
+	if w.maxGrowth > 0 {
+		oldest := w.samples[0]
+		span := now.Sub(oldest.at)
+		if span >= minGrowthSpan {
+			perDay := int64(float64(size-oldest.size) * float64(24*time.Hour) / float64(span))
+			over := perDay > w.maxGrowth
+			if over && !w.growthAlert {
+				messages = append(messages, fmt.Sprintf("The database grows by %s a day, over the %s limit. It is %s now.",
+					formatMB(perDay), formatMB(w.maxGrowth), formatMB(size)))
+			}
+			w.growthAlert = over
+		}
+	}
+
+	return messages
+}
+
+// formatMB formats a byte count in MiB for messages
+func formatMB(bytes int64) string {
+	return fmt.Sprintf("%.1f MiB", float64(bytes)/bytesPerMB)
+}
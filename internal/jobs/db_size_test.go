@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDatabaseSizeWatchSizeThreshold(t *testing.T) {
+	w := NewDatabaseSizeWatch(nil, nil, 100, 0)
+	now := time.Date(2026, 6, 1, 0, 25, 0, 0, time.UTC)
+
+	steps := []struct {
+		sizeMB int64
+		alerts int
+	}{
+		{90, 0},
+		{101, 1}, // crossed
+		{120, 0}, // still over: alerted already
+		{95, 0},  // back below
+		{110, 1}, // crossed again
+	}
+	for i, step := range steps {
+		messages := w.evaluate(step.sizeMB*bytesPerMB, now.Add(time.Duration(i)*time.Hour))
+		if len(messages) != step.alerts {
+			t.Errorf("step %d at %d MiB: alerts %q, want %d", i, step.sizeMB, messages, step.alerts)
+		}
+	}
+}
+
+func TestDatabaseSizeWatchGrowthThreshold(t *testing.T) {
+	w := NewDatabaseSizeWatch(nil, nil, 0, 240)
+	start := time.Date(2026, 6, 1, 0, 25, 0, 0, time.UTC)
+
+	// 20 MiB an hour is 480 MiB a day, but it isn't judged until six hours
+	// of samples are in
+	var alerted []int
+	for hour := 0; hour <= 8; hour++ {
+		messages := w.evaluate(int64(1000+20*hour)*bytesPerMB, start.Add(time.Duration(hour)*time.Hour))
+		if len(messages) > 0 {
+			alerted = append(alerted, hour)
+			if !strings.Contains(messages[0], "grows by 480.0 MiB a day") {
+				t.Errorf("message = %q, want the daily growth", messages[0])
+			}
+		}
+	}
+	if len(alerted) != 1 || alerted[0] != 6 {
+		t.Errorf("alerted at hours %v, want only at hour 6", alerted)
+	}
+}
+
+func TestDatabaseSizeWatchGrowthForgetsOldSamples(t *testing.T) {
+	w := NewDatabaseSizeWatch(nil, nil, 0, 100)
+	start := time.Date(2026, 6, 1, 0, 25, 0, 0, time.UTC)
+
+	// A large cleanup long ago and steady size since: no growth
+	w.evaluate(5000*bytesPerMB, start)
+	for hour := 30; hour <= 40; hour++ {
+		if messages := w.evaluate(200*bytesPerMB, start.Add(time.Duration(hour)*time.Hour)); len(messages) > 0 {
+			t.Fatalf("hour %d: alerted %q for a flat size", hour, messages)
+		}
+	}
+}
@@ -0,0 +1,274 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// heartbeatPartitionPrefix names monthly partitions heartbeats_pYYYY_MM
+	heartbeatPartitionPrefix = "heartbeats_p"
+
+	// heartbeatPartitionsAhead is how many future months always have a partition
+	heartbeatPartitionsAhead = 2
+
+	// defaultHeartbeatRetentionDays matches the row cleanup default for users without settings
+	defaultHeartbeatRetentionDays = 90
+)
+
+// heartbeatPartition is one monthly range of the heartbeats table
+type heartbeatPartition struct {
+	Name string
+	From time.Time // inclusive
+	To   time.Time // exclusive
+}
+
+// monthlyPartition returns the partition covering the month containing t
+func monthlyPartition(t time.Time) heartbeatPartition {
+	from := time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	return heartbeatPartition{
+		Name: heartbeatPartitionPrefix + from.Format("2006_01"),
+		From: from,
+		To:   from.AddDate(0, 1, 0),
+	}
+}
+
+// parsePartitionName returns the partition a heartbeats_pYYYY_MM name refers to
+func parsePartitionName(name string) (heartbeatPartition, bool) {
+	suffix, ok := strings.CutPrefix(name, heartbeatPartitionPrefix)
+	if !ok {
+		return heartbeatPartition{}, false
+	}
+	month, err := time.Parse("2006_01", suffix)
+	if err != nil {
+		return heartbeatPartition{}, false
+	}
+	return monthlyPartition(month), true
+}
+
+// planHeartbeatPartitions decides which monthly partitions to create (the
+// current month and heartbeatPartitionsAhead following ones) and which to drop
+// (every partition that ended more than retentionDays ago)
+func planHeartbeatPartitions(existing []string, now time.Time, retentionDays int) (create []heartbeatPartition, drop []heartbeatPartition) {
+	have := make(map[string]bool, len(existing))
+	for _, name := range existing {
+		have[name] = true
+	}
+
+	current := monthlyPartition(now.UTC())
+	for i := 0; i <= heartbeatPartitionsAhead; i++ {
+		p := monthlyPartition(current.From.AddDate(0, i, 0))
+		if !have[p.Name] {
+			create = append(create, p)
+		}
+	}
+
+	cutoff := now.UTC().AddDate(0, 0, -retentionDays)
+	for _, name := range existing {
+		p, ok := parsePartitionName(name)
+		if ok && !p.To.After(cutoff) {
+			drop = append(drop, p)
+		}
+	}
+
+	return create, drop
+}
+
+// initialHeartbeatPartitions returns the monthly partitions a freshly
+// converted table needs: every month from the oldest heartbeat through
+// heartbeatPartitionsAhead months after now
+func initialHeartbeatPartitions(oldest, now time.Time) []heartbeatPartition {
+	if oldest.IsZero() || oldest.After(now) {
+		oldest = now
+	}
+	last := monthlyPartition(now.UTC().AddDate(0, heartbeatPartitionsAhead, 0))
+
+	var partitions []heartbeatPartition
+	for p := monthlyPartition(oldest.UTC()); !p.From.After(last.From); p = monthlyPartition(p.To) {
+		partitions = append(partitions, p)
+	}
+	return partitions
+}
+
+// HeartbeatPartitionManager keeps the monthly partitions of the heartbeats
+// table in step with time: future months are created ahead of inserts and
+// months past every user's and monitor's retention are dropped
+type HeartbeatPartitionManager struct {
+	db *gorm.DB
+}
+
+// NewHeartbeatPartitionManager creates a new heartbeat partition manager
+func NewHeartbeatPartitionManager(db *gorm.DB) *HeartbeatPartitionManager {
+	return &HeartbeatPartitionManager{db: db}
+}
+
+// PartitionTable converts heartbeats to a table partitioned by month on time,
+// copying every row. It reports false and changes nothing when the table is
+// already partitioned. Run it before checks start writing heartbeats.
+//
+// The partition key must be part of every unique constraint, so the primary
+// key becomes (id, time) and page_change_snapshots.heartbeat_id can no longer
+// reference heartbeats(id). Its foreign key is dropped: the column only links
+// a snapshot to the check that took it and nothing joins on it, so a snapshot
+// outliving its heartbeat is harmless. Migrating down restores the key.
+func (m *HeartbeatPartitionManager) PartitionTable() (bool, error) {
+	var partitioned bool
+	err := m.db.Raw(`
+		SELECT EXISTS (
+			SELECT 1 FROM pg_partitioned_table pt
+			JOIN pg_class c ON c.oid = pt.partrelid
+			WHERE c.relname = 'heartbeats'
+		)
+	`).Scan(&partitioned).Error
+	if err != nil {
+		return false, fmt.Errorf("failed to inspect heartbeats table: %w", err)
+	}
+	if partitioned {
+		return false, nil
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		var oldest *time.Time
+		if err := tx.Raw(`SELECT MIN(time) FROM heartbeats`).Scan(&oldest).Error; err != nil {
+			return err
+		}
+		var from time.Time
+		if oldest != nil {
+			from = *oldest
+		}
+
+		// LIKE copies the columns later migrations added, in the same order
+		statements := []string{
+			`ALTER TABLE page_change_snapshots DROP CONSTRAINT IF EXISTS page_change_snapshots_heartbeat_id_fkey`,
+			`ALTER TABLE heartbeats RENAME TO heartbeats_legacy`,
+			`ALTER SEQUENCE heartbeats_id_seq OWNED BY NONE`,
+			`CREATE TABLE heartbeats (
+				LIKE heartbeats_legacy INCLUDING DEFAULTS,
+				PRIMARY KEY (id, time),
+				FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+			) PARTITION BY RANGE (time)`,
+			`ALTER SEQUENCE heartbeats_id_seq OWNED BY heartbeats.id`,
+			`CREATE TABLE heartbeats_default PARTITION OF heartbeats DEFAULT`,
+		}
+		for _, p := range initialHeartbeatPartitions(from, time.Now()) {
+			statements = append(statements, fmt.Sprintf(`CREATE TABLE %s PARTITION OF heartbeats FOR VALUES FROM ('%s') TO ('%s')`,
+				p.Name, p.From.Format("2006-01-02"), p.To.Format("2006-01-02")))
+		}
+		statements = append(statements,
+			`UPDATE heartbeats_legacy SET time = CURRENT_TIMESTAMP WHERE time IS NULL`,
+			`INSERT INTO heartbeats SELECT * FROM heartbeats_legacy`,
+			`DROP TABLE heartbeats_legacy`,
+			`CREATE INDEX IF NOT EXISTS idx_heartbeats_monitor_time ON heartbeats(monitor_id, time DESC)`,
+			`CREATE INDEX IF NOT EXISTS idx_heartbeats_time ON heartbeats(time)`,
+			`CREATE INDEX IF NOT EXISTS idx_heartbeats_monitor_time_id_desc ON heartbeats(monitor_id, time DESC, id DESC)`,
+		)
+
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to partition heartbeats: %w", err)
+	}
+
+	return true, nil
+}
+
+// Maintain creates missing upcoming partitions and drops expired ones
+func (m *HeartbeatPartitionManager) Maintain() error {
+	var existing []string
+	err := m.db.Raw(`
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		JOIN pg_class p ON p.oid = i.inhparent
+		WHERE p.relname = 'heartbeats'
+	`).Scan(&existing).Error
+	if err != nil {
+		return fmt.Errorf("failed to list heartbeat partitions: %w", err)
+	}
+
+	// Partitions are shared by every user, so only drop what the longest
+	// retention, of a user or a single monitor, no longer needs
+	var retentionDays int
+	err = m.db.Raw(`
+		SELECT GREATEST(
+			(SELECT COALESCE(MAX(heartbeat_retention_days), 0) FROM user_settings),
+			(SELECT COALESCE(MAX(retention_days), 0) FROM monitors)
+		)
+	`).Scan(&retentionDays).Error
+	if err != nil {
+		return fmt.Errorf("failed to read heartbeat retention: %w", err)
+	}
+	if retentionDays < defaultHeartbeatRetentionDays {
+		retentionDays = defaultHeartbeatRetentionDays
+	}
+
+	create, drop := planHeartbeatPartitions(existing, time.Now(), retentionDays)
+
+	for _, p := range create {
+		if err := m.createPartition(p); err != nil {
+			return fmt.Errorf("failed to create partition %s: %w", p.Name, err)
+		}
+		log.Printf("Created heartbeat partition %s", p.Name)
+	}
+
+	for _, p := range drop {
+		if err := m.dropPartition(p); err != nil {
+			return fmt.Errorf("failed to drop partition %s: %w", p.Name, err)
+		}
+		log.Printf("Dropped expired heartbeat partition %s", p.Name)
+	}
+
+	return nil
+}
+
+// createPartition attaches a new monthly partition. Rows for that month that
+// already landed in the default partition are moved into it first, otherwise
+// the attach would fail.
+func (m *HeartbeatPartitionManager) createPartition(p heartbeatPartition) error {
+	from := p.From.Format("2006-01-02")
+	to := p.To.Format("2006-01-02")
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			fmt.Sprintf(`CREATE TABLE %s (LIKE heartbeats INCLUDING DEFAULTS)`, p.Name),
+			fmt.Sprintf(`WITH moved AS (
+				DELETE FROM heartbeats_default WHERE time >= '%s' AND time < '%s' RETURNING *
+			) INSERT INTO %s SELECT * FROM moved`, from, to, p.Name),
+			fmt.Sprintf(`ALTER TABLE heartbeats ATTACH PARTITION %s FOR VALUES FROM ('%s') TO ('%s')`, p.Name, from, to),
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// dropPartition removes an expired monthly partition. Important heartbeats
+// are never removed by the row cleanup either, so they are kept by moving
+// them to the default partition.
+func (m *HeartbeatPartitionManager) dropPartition(p heartbeatPartition) error {
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			fmt.Sprintf(`ALTER TABLE heartbeats DETACH PARTITION %s`, p.Name),
+			fmt.Sprintf(`INSERT INTO heartbeats SELECT * FROM %s WHERE important = true`, p.Name),
+			fmt.Sprintf(`DROP TABLE %s`, p.Name),
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
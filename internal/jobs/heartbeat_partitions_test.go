@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func partitionNames(partitions []heartbeatPartition) []string {
+	names := make([]string, 0, len(partitions))
+	for _, p := range partitions {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+func TestPlanHeartbeatPartitionsCreatesNextMonth(t *testing.T) {
+	now := time.Date(2026, 11, 20, 8, 0, 0, 0, time.UTC)
+	existing := []string{"heartbeats_default", "heartbeats_p2026_10", "heartbeats_p2026_11", "heartbeats_p2026_12"}
+
+	create, drop := planHeartbeatPartitions(existing, now, 90)
+
+	if len(create) != 1 {
+		t.Fatalf("create = %v, want only the next missing month", partitionNames(create))
+	}
+	p := create[0]
+	if p.Name != "heartbeats_p2027_01" {
+		t.Errorf("name = %s, want heartbeats_p2027_01", p.Name)
+	}
+	if !p.From.Equal(time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC)) || !p.To.Equal(time.Date(2027, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("range = [%s, %s), want January 2027", p.From, p.To)
+	}
+	if len(drop) != 0 {
+		t.Errorf("drop = %v, want none", partitionNames(drop))
+	}
+}
+
+func TestPlanHeartbeatPartitionsFreshTable(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	create, _ := planHeartbeatPartitions([]string{"heartbeats_default"}, now, 90)
+
+	want := []string{"heartbeats_p2026_03", "heartbeats_p2026_04", "heartbeats_p2026_05"}
+	got := partitionNames(create)
+	if len(got) != len(want) {
+		t.Fatalf("create = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("create[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+}
+
+func TestPlanHeartbeatPartitionsDropsExpired(t *testing.T) {
+	now := time.Date(2026, 6, 15, 0, 0, 0, 0, time.UTC)
+	existing := []string{
+		"heartbeats_default",
+		"heartbeats_p2026_02", // ended Mar 1, more than 90 days ago
+		"heartbeats_p2026_03", // ended Apr 1, within 90 days
+		"heartbeats_p2026_06",
+		"heartbeats_p2026_07",
+		"heartbeats_p2026_08",
+	}
+
+	create, drop := planHeartbeatPartitions(existing, now, 90)
+
+	if len(create) != 0 {
+		t.Errorf("create = %v, want none", partitionNames(create))
+	}
+	if got := partitionNames(drop); len(got) != 1 || got[0] != "heartbeats_p2026_02" {
+		t.Errorf("drop = %v, want [heartbeats_p2026_02]", got)
+	}
+}
+
+func TestParsePartitionName(t *testing.T) {
+	if _, ok := parsePartitionName("heartbeats_default"); ok {
+		t.Error("default partition should not parse as a monthly partition")
+	}
+	if _, ok := parsePartitionName("heartbeats_p2026_13"); ok {
+		t.Error("invalid month should not parse")
+	}
+	p, ok := parsePartitionName("heartbeats_p2026_02")
+	if !ok || !p.From.Equal(time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("parsePartitionName = %+v, %v", p, ok)
+	}
+}
+
+func TestInitialHeartbeatPartitions(t *testing.T) {
+	now := time.Date(2026, 11, 20, 8, 0, 0, 0, time.UTC)
+
+	got := partitionNames(initialHeartbeatPartitions(time.Date(2026, 9, 30, 23, 0, 0, 0, time.UTC), now))
+	want := []string{"heartbeats_p2026_09", "heartbeats_p2026_10", "heartbeats_p2026_11", "heartbeats_p2026_12", "heartbeats_p2027_01"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("partitions = %v, want %v", got, want)
+	}
+
+	// An empty table only needs the current and upcoming months
+	got = partitionNames(initialHeartbeatPartitions(time.Time{}, now))
+	want = []string{"heartbeats_p2026_11", "heartbeats_p2026_12", "heartbeats_p2027_01"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("empty table partitions = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// IncidentAutoResolver resolves incidents whose auto_resolve_after window has passed
+type IncidentAutoResolver struct {
+	db *gorm.DB
+}
+
+// NewIncidentAutoResolver creates a new incident auto-resolver
+func NewIncidentAutoResolver(db *gorm.DB) *IncidentAutoResolver {
+	return &IncidentAutoResolver{db: db}
+}
+
+// ResolveExpired resolves every opted-in incident whose window has elapsed
+func (r *IncidentAutoResolver) ResolveExpired() error {
+	var incidents []models.Incident
+	err := r.db.Where("auto_resolve_after IS NOT NULL AND resolved_at IS NULL").
+		Find(&incidents).Error
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, incident := range incidents {
+		if !autoResolveDue(incident, now) {
+			continue
+		}
+
+		// resolved_at IS NULL guards against racing a manual resolution
+		result := r.db.Model(&models.Incident{}).
+			Where("id = ? AND resolved_at IS NULL", incident.ID).
+			Updates(map[string]interface{}{
+				"content":     autoResolvedContent(incident),
+				"resolved_at": now,
+				"updated_at":  now,
+			})
+		if result.Error != nil {
+			log.Printf("Failed to auto-resolve incident %d: %v", incident.ID, result.Error)
+			continue
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("Auto-resolved incident %d (%s)", incident.ID, incident.Title)
+		}
+	}
+
+	return nil
+}
+
+// autoResolveDue reports whether an incident opted into auto-resolution and
+// its window has elapsed
+func autoResolveDue(incident models.Incident, now time.Time) bool {
+	if incident.AutoResolveAfter == nil || incident.ResolvedAt != nil {
+		return false
+	}
+	window := time.Duration(*incident.AutoResolveAfter) * time.Second
+	return !now.Before(incident.CreatedAt.Add(window))
+}
+
+// autoResolvedContent appends an update noting the auto-resolution
+func autoResolvedContent(incident models.Incident) string {
+	window := time.Duration(*incident.AutoResolveAfter) * time.Second
+	note := fmt.Sprintf("**Update:** This incident was automatically resolved after %s.", window)
+	if incident.Content == "" {
+		return note
+	}
+	return incident.Content + "\n\n" + note
+}
@@ -0,0 +1,63 @@
+package jobs
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestAutoResolveDue(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hour := 3600
+	resolvedAt := now.Add(-time.Minute)
+
+	tests := []struct {
+		name     string
+		incident models.Incident
+		want     bool
+	}{
+		{
+			name:     "expired auto-resolve incident",
+			incident: models.Incident{CreatedAt: now.Add(-2 * time.Hour), AutoResolveAfter: &hour},
+			want:     true,
+		},
+		{
+			name:     "window not elapsed",
+			incident: models.Incident{CreatedAt: now.Add(-30 * time.Minute), AutoResolveAfter: &hour},
+			want:     false,
+		},
+		{
+			name:     "not opted in",
+			incident: models.Incident{CreatedAt: now.Add(-48 * time.Hour)},
+			want:     false,
+		},
+		{
+			name:     "already resolved",
+			incident: models.Incident{CreatedAt: now.Add(-2 * time.Hour), AutoResolveAfter: &hour, ResolvedAt: &resolvedAt},
+			want:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := autoResolveDue(tt.incident, now); got != tt.want {
+				t.Fatalf("autoResolveDue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAutoResolvedContent(t *testing.T) {
+	window := 1800
+	incident := models.Incident{Content: "Investigating elevated errors.", AutoResolveAfter: &window}
+
+	got := autoResolvedContent(incident)
+	if !strings.HasPrefix(got, "Investigating elevated errors.\n\n") {
+		t.Errorf("original content not preserved: %q", got)
+	}
+	if !strings.Contains(got, "automatically resolved after 30m0s") {
+		t.Errorf("auto-resolution note missing: %q", got)
+	}
+}
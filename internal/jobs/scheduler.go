@@ -5,11 +5,13 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
 
 	"gorm.io/gorm"
 	"github.com/robfig/cron/v3"
 
 	"github.com/fuomag9/uptime-kabomba/internal/models"
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
 )
 
 // Scheduler manages background jobs
@@ -17,14 +19,26 @@ type Scheduler struct {
 	cron                  *cron.Cron
 	db                    *gorm.DB
 	screenshotStoragePath string
+	dispatcher            *notification.Dispatcher
+	stalledMultiplier     int // 0 disables the stalled monitor watchdog
+	apiKeyExpiryDays      int // 0 disables API key expiry warnings
+	dbSizeAlertMB         int // 0 disables the database size alert
+	dbGrowthAlertMB       int // 0 disables the database growth alert
+	heartbeatPartitioning bool
 }
 
 // NewScheduler creates a new job scheduler
-func NewScheduler(db *gorm.DB, screenshotStoragePath string) *Scheduler {
+func NewScheduler(db *gorm.DB, screenshotStoragePath string, dispatcher *notification.Dispatcher, stalledMultiplier int, apiKeyExpiryDays int, dbSizeAlertMB, dbGrowthAlertMB int, heartbeatPartitioning bool) *Scheduler {
 	return &Scheduler{
 		cron:                  cron.New(),
 		db:                    db,
 		screenshotStoragePath: screenshotStoragePath,
+		dispatcher:            dispatcher,
+		stalledMultiplier:     stalledMultiplier,
+		apiKeyExpiryDays:      apiKeyExpiryDays,
+		dbSizeAlertMB:         dbSizeAlertMB,
+		dbGrowthAlertMB:       dbGrowthAlertMB,
+		heartbeatPartitioning: heartbeatPartitioning,
 	}
 }
 
@@ -61,6 +75,61 @@ func (s *Scheduler) Start() {
 		s.cleanupOldSnapshots()
 	})
 
+	// Cleanup stale login lockout counters daily at 3:50 AM
+	s.cron.AddFunc("50 3 * * *", func() {
+		s.cleanupStaleLoginAttempts()
+	})
+
+	// Resolve incidents whose auto_resolve_after window has passed every minute
+	incidentResolver := NewIncidentAutoResolver(s.db)
+	s.cron.AddFunc("* * * * *", func() {
+		if err := incidentResolver.ResolveExpired(); err != nil {
+			log.Printf("Incident auto-resolve failed: %v", err)
+		}
+	})
+
+	// Alert on monitors that stopped writing heartbeats every minute
+	if s.stalledMultiplier > 0 {
+		watchdog := NewStalledMonitorWatchdog(s.db, s.dispatcher, s.stalledMultiplier)
+		s.cron.AddFunc("* * * * *", func() {
+			if err := watchdog.Check(); err != nil {
+				log.Printf("Stalled monitor watchdog failed: %v", err)
+			}
+		})
+	}
+
+	// Warn owners of API keys about to expire every hour at minute 20
+	if s.apiKeyExpiryDays > 0 {
+		expiryNotifier := NewAPIKeyExpiryNotifier(s.db, s.dispatcher, s.apiKeyExpiryDays)
+		s.cron.AddFunc("20 * * * *", func() {
+			if err := expiryNotifier.Check(); err != nil {
+				log.Printf("API key expiry check failed: %v", err)
+			}
+		})
+	}
+
+	// Warn admins about the database size and growth every hour at minute 25
+	if s.dbSizeAlertMB > 0 || s.dbGrowthAlertMB > 0 {
+		sizeWatch := NewDatabaseSizeWatch(s.db, s.dispatcher, s.dbSizeAlertMB, s.dbGrowthAlertMB)
+		s.cron.AddFunc("25 * * * *", func() {
+			if err := sizeWatch.Check(); err != nil {
+				log.Printf("Database size check failed: %v", err)
+			}
+		})
+	}
+
+	// Keep heartbeat partitions ahead of inserts: on startup and daily at 0:10 AM
+	if s.heartbeatPartitioning {
+		partitions := NewHeartbeatPartitionManager(s.db)
+		maintain := func() {
+			if err := partitions.Maintain(); err != nil {
+				log.Printf("Heartbeat partition maintenance failed: %v", err)
+			}
+		}
+		maintain()
+		s.cron.AddFunc("10 0 * * *", maintain)
+	}
+
 	s.cron.Start()
 	log.Println("Job scheduler started")
 }
@@ -71,7 +140,33 @@ func (s *Scheduler) Stop() {
 	log.Println("Job scheduler stopped")
 }
 
+// monitorRetention is a monitor's own heartbeat retention override
+type monitorRetention struct {
+	ID            int
+	UserID        int
+	RetentionDays int
+}
+
+// groupByRetention groups monitor IDs by how many days of heartbeats they
+// keep: the monitor's retention_days when set, else its owner's setting,
+// else defaultHeartbeatRetentionDays
+func groupByRetention(monitors []monitorRetention, userRetention map[int]int) map[int][]int {
+	groups := make(map[int][]int)
+	for _, m := range monitors {
+		days := m.RetentionDays
+		if days <= 0 {
+			days = defaultHeartbeatRetentionDays
+			if userDays, ok := userRetention[m.UserID]; ok {
+				days = userDays
+			}
+		}
+		groups[days] = append(groups[days], m.ID)
+	}
+	return groups
+}
+
 // cleanupOldHeartbeats removes old heartbeat data based on user settings
+// and per-monitor retention overrides
 func (s *Scheduler) cleanupOldHeartbeats() {
 	// Get all user settings
 	var settings []models.UserSettings
@@ -83,30 +178,25 @@ func (s *Scheduler) cleanupOldHeartbeats() {
 		userRetention[setting.UserID] = setting.HeartbeatRetentionDays
 	}
 
-	// Get all users with monitors
-	var users []struct {
-		UserID int
+	var monitors []monitorRetention
+	if err := s.db.Model(&models.Monitor{}).
+		Select("id, user_id, retention_days").
+		Scan(&monitors).Error; err != nil {
+		log.Printf("Failed to load monitors for heartbeat cleanup: %v", err)
+		return
 	}
-	s.db.Model(&models.Monitor{}).Select("DISTINCT user_id").Scan(&users)
-
-	totalCleaned := int64(0)
 
-	for _, user := range users {
-		// Get retention days for this user (default 90 if not configured)
-		retentionDays := 90
-		if days, ok := userRetention[user.UserID]; ok {
-			retentionDays = days
-		}
+	groups := groupByRetention(monitors, userRetention)
+	retentions := make([]int, 0, len(groups))
+	for days := range groups {
+		retentions = append(retentions, days)
+	}
+	sort.Ints(retentions)
 
-		// Get monitor IDs for this user
-		var monitorIDs []int
-		s.db.Model(&models.Monitor{}).
-			Where("user_id = ?", user.UserID).
-			Pluck("id", &monitorIDs)
+	totalCleaned := int64(0)
 
-		if len(monitorIDs) == 0 {
-			continue
-		}
+	for _, retentionDays := range retentions {
+		monitorIDs := groups[retentionDays]
 
 		// Delete old heartbeats for these monitors
 		query := fmt.Sprintf(`
@@ -118,12 +208,12 @@ func (s *Scheduler) cleanupOldHeartbeats() {
 
 		result := s.db.Exec(query, monitorIDs)
 		if result.Error != nil {
-			log.Printf("Failed to cleanup heartbeats for user %d: %v", user.UserID, result.Error)
+			log.Printf("Failed to cleanup heartbeats with %d day retention: %v", retentionDays, result.Error)
 			continue
 		}
 
 		if result.RowsAffected > 0 {
-			log.Printf("User %d: Cleaned up %d heartbeats (retention: %d days)", user.UserID, result.RowsAffected, retentionDays)
+			log.Printf("Cleaned up %d heartbeats of %d monitors (retention: %d days)", result.RowsAffected, len(monitorIDs), retentionDays)
 			totalCleaned += result.RowsAffected
 		}
 	}
@@ -209,6 +299,20 @@ func (s *Scheduler) cleanupOldStats() {
 	log.Printf("Total stats cleaned up: %d hourly, %d daily", totalHourlyCleaned, totalDailyCleaned)
 }
 
+// cleanupStaleLoginAttempts removes login lockout counters that have not
+// changed in a day and are not currently locked
+func (s *Scheduler) cleanupStaleLoginAttempts() {
+	result := s.db.Where("updated_at < NOW() - INTERVAL '1 day' AND (locked_until IS NULL OR locked_until < NOW())").
+		Delete(&models.LoginAttempt{})
+	if result.Error != nil {
+		log.Printf("Failed to cleanup login attempts: %v", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		log.Printf("Cleaned up %d stale login attempt counters", result.RowsAffected)
+	}
+}
+
 // cleanupOldSnapshots removes old page change snapshots and their screenshot files.
 // Keeps the latest baseline per monitor and snapshots from the last 30 days.
 func (s *Scheduler) cleanupOldSnapshots() {
@@ -0,0 +1,35 @@
+package jobs
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGroupByRetentionMixesOverridesAndDefaults(t *testing.T) {
+	monitors := []monitorRetention{
+		{ID: 1, UserID: 10},                     // user setting: 30
+		{ID: 2, UserID: 10, RetentionDays: 365}, // critical monitor kept longer
+		{ID: 3, UserID: 10},                     // user setting: 30
+		{ID: 4, UserID: 20},                     // no settings: default 90
+		{ID: 5, UserID: 20, RetentionDays: 30},  // shares the 30 day group
+		{ID: 6, UserID: 30, RetentionDays: 0},   // 0 means the user's 14
+	}
+	userRetention := map[int]int{10: 30, 30: 14}
+
+	got := groupByRetention(monitors, userRetention)
+	want := map[int][]int{
+		14:  {6},
+		30:  {1, 3, 5},
+		90:  {4},
+		365: {2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("groupByRetention = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByRetentionEmpty(t *testing.T) {
+	if got := groupByRetention(nil, map[int]int{1: 30}); len(got) != 0 {
+		t.Errorf("groupByRetention(nil) = %v, want no groups", got)
+	}
+}
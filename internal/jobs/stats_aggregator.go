@@ -52,9 +52,9 @@ func (a *StatsAggregator) aggregateMonitorHourly(monitorID int, hourStart, hourE
 			MIN(ping) as ping_min,
 			MAX(ping) as ping_max,
 			AVG(ping) as ping_avg,
-			SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END) as up_count,
-			SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END) as down_count,
-			COUNT(*) as total_count
+			SUM(CASE WHEN status = 1 THEN checks ELSE 0 END) as up_count,
+			SUM(CASE WHEN status = 0 THEN checks ELSE 0 END) as down_count,
+			COALESCE(SUM(checks), 0) as total_count
 		FROM heartbeats
 		WHERE monitor_id = ? AND time >= ? AND time < ?
 	`
@@ -153,9 +153,9 @@ func (a *StatsAggregator) aggregateMonitorDaily(monitorID int, dayStart, dayEnd
 			MIN(ping) as ping_min,
 			MAX(ping) as ping_max,
 			AVG(ping) as ping_avg,
-			SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END) as up_count,
-			SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END) as down_count,
-			COUNT(*) as total_count
+			SUM(CASE WHEN status = 1 THEN checks ELSE 0 END) as up_count,
+			SUM(CASE WHEN status = 0 THEN checks ELSE 0 END) as down_count,
+			COALESCE(SUM(checks), 0) as total_count
 		FROM heartbeats
 		WHERE monitor_id = ? AND time >= ? AND time < ?
 	`
@@ -0,0 +1,116 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
+)
+
+// monitorActivity is an active monitor and the time it last reported
+type monitorActivity struct {
+	ID            int       `gorm:"column:id"`
+	Name          string    `gorm:"column:name"`
+	Interval      int       `gorm:"column:interval"`
+	LastHeartbeat time.Time `gorm:"column:last_heartbeat"`
+}
+
+// StalledMonitorWatchdog alerts when an active monitor stops writing
+// heartbeats, which means its job died or the executor is stuck
+type StalledMonitorWatchdog struct {
+	db         *gorm.DB
+	dispatcher *notification.Dispatcher
+	multiplier int
+
+	mu       sync.Mutex
+	notified map[int]time.Time // monitor ID -> last heartbeat we already alerted on
+}
+
+// NewStalledMonitorWatchdog creates a watchdog that flags monitors whose
+// latest heartbeat is older than multiplier × their interval
+func NewStalledMonitorWatchdog(db *gorm.DB, dispatcher *notification.Dispatcher, multiplier int) *StalledMonitorWatchdog {
+	return &StalledMonitorWatchdog{
+		db:         db,
+		dispatcher: dispatcher,
+		multiplier: multiplier,
+		notified:   make(map[int]time.Time),
+	}
+}
+
+// Check looks for stalled monitors and sends one notification per stall
+func (w *StalledMonitorWatchdog) Check() error {
+	// Monitors that never reported are measured from their last update,
+	// which is also when the executor (re)started them
+	query := `
+		SELECT m.id, m.name, m.interval,
+			COALESCE(
+				(SELECT h.time FROM heartbeats h WHERE h.monitor_id = m.id ORDER BY h.time DESC LIMIT 1),
+				m.updated_at
+			) AS last_heartbeat
+		FROM monitors m
+		WHERE m.active = true
+	`
+
+	var monitors []monitorActivity
+	if err := w.db.Raw(query).Scan(&monitors).Error; err != nil {
+		return err
+	}
+
+	stalled := findStalledMonitors(monitors, w.multiplier, time.Now())
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stalledIDs := make(map[int]bool, len(stalled))
+	for _, m := range stalled {
+		stalledIDs[m.ID] = true
+
+		// Already alerted for this stall
+		if last, ok := w.notified[m.ID]; ok && last.Equal(m.LastHeartbeat) {
+			continue
+		}
+
+		log.Printf("Monitor %s (ID: %d) has stalled: no heartbeat since %s",
+			m.Name, m.ID, m.LastHeartbeat.Format(time.RFC3339))
+
+		if w.dispatcher != nil {
+			message := fmt.Sprintf("No heartbeat since %s (interval %ds). The monitor job may have stopped.",
+				m.LastHeartbeat.Format(time.RFC3339), m.Interval)
+			if err := w.dispatcher.NotifyMonitorStalled(context.Background(), m.ID, m.Name, "", message); err != nil {
+				log.Printf("Failed to send stalled notification for monitor %d: %v", m.ID, err)
+				continue
+			}
+		}
+		w.notified[m.ID] = m.LastHeartbeat
+	}
+
+	// Forget monitors that recovered so a later stall alerts again
+	for id := range w.notified {
+		if !stalledIDs[id] {
+			delete(w.notified, id)
+		}
+	}
+
+	return nil
+}
+
+// findStalledMonitors returns monitors whose last heartbeat is older than
+// multiplier × interval
+func findStalledMonitors(monitors []monitorActivity, multiplier int, now time.Time) []monitorActivity {
+	var stalled []monitorActivity
+	for _, m := range monitors {
+		if m.Interval <= 0 {
+			continue
+		}
+		threshold := time.Duration(m.Interval*multiplier) * time.Second
+		if now.Sub(m.LastHeartbeat) > threshold {
+			stalled = append(stalled, m)
+		}
+	}
+	return stalled
+}
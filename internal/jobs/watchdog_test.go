@@ -0,0 +1,28 @@
+package jobs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFindStalledMonitors(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	monitors := []monitorActivity{
+		{ID: 1, Name: "fresh", Interval: 60, LastHeartbeat: now.Add(-30 * time.Second)},
+		{ID: 2, Name: "stale", Interval: 60, LastHeartbeat: now.Add(-10 * time.Minute)},
+		{ID: 3, Name: "at threshold", Interval: 60, LastHeartbeat: now.Add(-3 * time.Minute)},
+		{ID: 4, Name: "slow but fine", Interval: 3600, LastHeartbeat: now.Add(-2 * time.Hour)},
+		{ID: 5, Name: "no interval", Interval: 0, LastHeartbeat: now.Add(-24 * time.Hour)},
+	}
+
+	stalled := findStalledMonitors(monitors, 3, now)
+	if len(stalled) != 1 || stalled[0].ID != 2 {
+		t.Fatalf("stalled = %+v, want only monitor 2", stalled)
+	}
+
+	// A larger multiplier tolerates the same gap
+	if stalled := findStalledMonitors(monitors, 20, now); len(stalled) != 0 {
+		t.Fatalf("stalled with multiplier 20 = %+v, want none", stalled)
+	}
+}
@@ -0,0 +1,58 @@
+// Package jwtkeys holds the secrets session tokens are signed and verified
+// with, so JWT_SECRET can be rotated without logging everyone out
+package jwtkeys
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// KeySet signs new tokens with the current secret. During a rotation it also
+// verifies tokens signed with previous secrets, until the rotation window has
+// passed.
+type KeySet struct {
+	current       string
+	previous      []string
+	previousUntil time.Time
+	now           func() time.Time
+}
+
+// New creates a key set. previous secrets are accepted for window from now;
+// a window as long as the token lifetime lets every existing session run out.
+func New(current string, previous []string, window time.Duration) *KeySet {
+	return &KeySet{
+		current:       current,
+		previous:      previous,
+		previousUntil: time.Now().Add(window),
+		now:           time.Now,
+	}
+}
+
+// Current returns the secret new tokens are signed with
+func (k *KeySet) Current() string {
+	return k.current
+}
+
+// Keyfunc is a jwt.Keyfunc that accepts only HS256 tokens, signed with the
+// current secret or, within the rotation window, a previous one
+func (k *KeySet) Keyfunc(token *jwt.Token) (interface{}, error) {
+	// Validate the algorithm is HMAC
+	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+	}
+	// Only accept HS256
+	if token.Method.Alg() != "HS256" {
+		return nil, fmt.Errorf("unexpected signing algorithm: %v", token.Method.Alg())
+	}
+
+	if len(k.previous) == 0 || !k.now().Before(k.previousUntil) {
+		return []byte(k.current), nil
+	}
+	keys := jwt.VerificationKeySet{Keys: []jwt.VerificationKey{[]byte(k.current)}}
+	for _, secret := range k.previous {
+		keys.Keys = append(keys.Keys, []byte(secret))
+	}
+	return keys, nil
+}
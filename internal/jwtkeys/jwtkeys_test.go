@@ -0,0 +1,70 @@
+package jwtkeys
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	oldSecret = "old-secret-0123456789abcdef0123456789"
+	newSecret = "new-secret-0123456789abcdef0123456789"
+)
+
+func signToken(t *testing.T, method jwt.SigningMethod, secret string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(method, jwt.MapClaims{
+		"user_id": 1,
+		"exp":     time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+	if err != nil {
+		t.Fatalf("sign: %v", err)
+	}
+	return token
+}
+
+func valid(keys *KeySet, token string) bool {
+	parsed, err := jwt.Parse(token, keys.Keyfunc)
+	return err == nil && parsed.Valid
+}
+
+func TestPreviousSecretValidDuringRotationWindow(t *testing.T) {
+	keys := New(newSecret, []string{oldSecret}, 2*time.Hour)
+
+	if !valid(keys, signToken(t, jwt.SigningMethodHS256, oldSecret)) {
+		t.Error("token signed with the previous secret rejected during the rotation window")
+	}
+	if !valid(keys, signToken(t, jwt.SigningMethodHS256, newSecret)) {
+		t.Error("token signed with the current secret rejected")
+	}
+	if valid(keys, signToken(t, jwt.SigningMethodHS256, "unrelated-secret-0123456789abcdef")) {
+		t.Error("token signed with an unknown secret accepted")
+	}
+	if keys.Current() != newSecret {
+		t.Errorf("new tokens would be signed with %q", keys.Current())
+	}
+}
+
+func TestPreviousSecretRejectedAfterRotationWindow(t *testing.T) {
+	keys := New(newSecret, []string{oldSecret}, 2*time.Hour)
+	keys.now = func() time.Time { return time.Now().Add(2*time.Hour + time.Second) }
+
+	if valid(keys, signToken(t, jwt.SigningMethodHS256, oldSecret)) {
+		t.Error("token signed with the previous secret accepted after the rotation window")
+	}
+	if !valid(keys, signToken(t, jwt.SigningMethodHS256, newSecret)) {
+		t.Error("token signed with the current secret rejected after the rotation window")
+	}
+}
+
+func TestKeyfuncRejectsOtherAlgorithms(t *testing.T) {
+	keys := New(newSecret, []string{oldSecret}, time.Hour)
+
+	if valid(keys, signToken(t, jwt.SigningMethodHS512, oldSecret)) {
+		t.Error("HS512 token accepted")
+	}
+	if valid(keys, signToken(t, jwt.SigningMethodHS384, newSecret)) {
+		t.Error("HS384 token accepted")
+	}
+}
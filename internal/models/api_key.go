@@ -20,6 +20,11 @@ type APIKey struct {
 	LastUsedAt *time.Time `json:"last_used_at,omitempty"`
 	CreatedAt  time.Time  `json:"created_at"`
 
+	// ExpiryNotifiedAt is when the owner was warned the key is about to expire
+	ExpiryNotifiedAt *time.Time `json:"-"`
+	// DaysUntilExpiry is filled in by the list endpoint for keys that expire
+	DaysUntilExpiry *int `json:"days_until_expiry,omitempty" gorm:"-"`
+
 	// Relationship (optional, for eager loading)
 	User User `json:"-" gorm:"foreignKey:UserID"`
 }
@@ -66,3 +71,16 @@ func (k *APIKey) IsExpired() bool {
 	}
 	return k.ExpiresAt.Before(time.Now())
 }
+
+// DaysLeft returns the whole days until the key expires, 0 once it has
+// expired, or nil when it never expires
+func (k *APIKey) DaysLeft(now time.Time) *int {
+	if k.ExpiresAt == nil {
+		return nil
+	}
+	days := 0
+	if left := k.ExpiresAt.Sub(now); left > 0 {
+		days = int(left / (24 * time.Hour))
+	}
+	return &days
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EscalationPolicy is an ordered list of steps notifying further channels
+// the longer a monitor's outage lasts
+type EscalationPolicy struct {
+	ID        int              `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int              `json:"user_id" gorm:"not null;index"`
+	Name      string           `json:"name" gorm:"not null"`
+	Steps     []EscalationStep `json:"steps" gorm:"-"`
+	StepsRaw  string           `json:"-" gorm:"column:steps;type:text"` // JSON storage
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// EscalationStep notifies its channels once an outage has lasted DelayMinutes
+type EscalationStep struct {
+	DelayMinutes    int   `json:"delay_minutes"`
+	NotificationIDs []int `json:"notification_ids"`
+}
+
+// Delay is how long an outage lasts before the step notifies
+func (s EscalationStep) Delay() time.Duration {
+	return time.Duration(s.DelayMinutes) * time.Minute
+}
+
+// TableName specifies the table name for EscalationPolicy
+func (EscalationPolicy) TableName() string {
+	return "escalation_policies"
+}
+
+// BeforeSave marshals Steps to JSON before saving (GORM hook)
+func (p *EscalationPolicy) BeforeSave(tx *gorm.DB) error {
+	if p.Steps == nil {
+		p.Steps = []EscalationStep{}
+	}
+	stepsJSON, err := json.Marshal(p.Steps)
+	if err != nil {
+		return err
+	}
+	p.StepsRaw = string(stepsJSON)
+	return nil
+}
+
+// AfterFind unmarshals the Steps JSON after loading (GORM hook)
+func (p *EscalationPolicy) AfterFind(tx *gorm.DB) error {
+	if p.StepsRaw != "" {
+		return json.Unmarshal([]byte(p.StepsRaw), &p.Steps)
+	}
+	return nil
+}
@@ -4,13 +4,15 @@ import "time"
 
 // Heartbeat represents a monitor check result
 type Heartbeat struct {
-	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
-	MonitorID int       `json:"monitor_id" gorm:"not null;index:idx_monitor_time"`
-	Status    int       `json:"status" gorm:"not null"` // 0=down, 1=up, 2=pending, 3=maintenance
-	Ping      int       `json:"ping"`                   // milliseconds
-	Important bool      `json:"important" gorm:"default:false"`
-	Message   string    `json:"message"`
-	Time      time.Time `json:"time" gorm:"not null;index:idx_monitor_time,sort:desc;index:idx_time"`
+	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	MonitorID  int       `json:"monitor_id" gorm:"not null;index:idx_monitor_time"`
+	Status     int       `json:"status" gorm:"not null"` // 0=down, 1=up, 2=pending, 3=maintenance
+	Ping       int       `json:"ping"`                   // milliseconds
+	Important  bool      `json:"important" gorm:"default:false"`
+	Message    string    `json:"message"`
+	Time       time.Time `json:"time" gorm:"not null;index:idx_monitor_time,sort:desc;index:idx_time"`
+	RemoteAddr *string   `json:"remote_addr"`             // IP that served the check, if known
+	Checks     int       `json:"checks" gorm:"default:1"` // checks the heartbeat stands for, more than 1 when coalesced
 
 	// Relationship (optional, for eager loading)
 	Monitor Monitor `json:"-" gorm:"foreignKey:MonitorID"`
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// LoginAttempt tracks failed logins for one account within the lockout window
+type LoginAttempt struct {
+	Key         string     `json:"key" gorm:"primaryKey"`
+	FailedCount int        `json:"failed_count" gorm:"not null;default:0"`
+	WindowStart time.Time  `json:"window_start" gorm:"not null"`
+	LockedUntil *time.Time `json:"locked_until"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// TableName specifies the table name for LoginAttempt
+func (LoginAttempt) TableName() string {
+	return "login_attempts"
+}
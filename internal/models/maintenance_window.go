@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// maintenanceWeek is how often a weekly maintenance window repeats
+const maintenanceWeek = 7 * 24 * time.Hour
+
+// MaintenanceWindow is planned maintenance of a monitor. Its checks still
+// run, but within the window they are recorded as maintenance and send no
+// alerts. A weekly window repeats every seven days from StartsAt, at the
+// same wall clock time in its Timezone.
+type MaintenanceWindow struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	MonitorID int       `json:"monitor_id" gorm:"not null;index"`
+	Title     string    `json:"title" gorm:"not null"`
+	StartsAt  time.Time `json:"starts_at" gorm:"not null"`
+	EndsAt    time.Time `json:"ends_at" gorm:"not null"`
+	Weekly    bool      `json:"weekly" gorm:"default:false"`
+	Timezone  string    `json:"timezone" gorm:"not null;default:UTC"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for MaintenanceWindow
+func (MaintenanceWindow) TableName() string {
+	return "maintenance_windows"
+}
+
+// Occurrence returns the start and end of the occurrence of the window that
+// t falls in, and false when t is outside the window
+func (m MaintenanceWindow) Occurrence(t time.Time) (time.Time, time.Time, bool) {
+	if t.Before(m.StartsAt) {
+		return time.Time{}, time.Time{}, false
+	}
+	start := m.StartsAt
+	if m.Weekly {
+		// Step whole days in the window's zone, so it keeps its wall clock
+		// time when daylight saving time starts or ends. Counting weeks by
+		// duration can be off by one across those changes, so correct it.
+		first := m.StartsAt.In(LoadTimezone(m.Timezone))
+		weeks := int(t.Sub(first) / maintenanceWeek)
+		if first.AddDate(0, 0, 7*weeks).After(t) {
+			weeks--
+		} else if !first.AddDate(0, 0, 7*(weeks+1)).After(t) {
+			weeks++
+		}
+		start = first.AddDate(0, 0, 7*weeks)
+	}
+	end := start.Add(m.EndsAt.Sub(m.StartsAt))
+	if !t.Before(end) {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// Ended reports whether the window is over for good at t: a one-off window
+// past its end. Weekly windows never end.
+func (m MaintenanceWindow) Ended(t time.Time) bool {
+	return !m.Weekly && !t.Before(m.EndsAt)
+}
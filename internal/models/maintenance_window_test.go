@@ -0,0 +1,74 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestMaintenanceWindowOccurrence(t *testing.T) {
+	start := time.Date(2026, 3, 1, 22, 0, 0, 0, time.UTC) // a Sunday
+	once := MaintenanceWindow{StartsAt: start, EndsAt: start.Add(2 * time.Hour)}
+	weekly := once
+	weekly.Weekly = true
+
+	tests := []struct {
+		name   string
+		window MaintenanceWindow
+		at     time.Time
+		want   bool
+	}{
+		{"before", once, start.Add(-time.Minute), false},
+		{"start is inclusive", once, start, true},
+		{"within", once, start.Add(time.Hour), true},
+		{"end is exclusive", once, start.Add(2 * time.Hour), false},
+		{"one-off a week later", once, start.Add(7*24*time.Hour + time.Hour), false},
+		{"weekly a week later", weekly, start.Add(7*24*time.Hour + time.Hour), true},
+		{"weekly between occurrences", weekly, start.Add(3 * 24 * time.Hour), false},
+		{"weekly before the first", weekly, start.Add(-7 * 24 * time.Hour), false},
+	}
+
+	for _, tt := range tests {
+		if _, _, got := tt.window.Occurrence(tt.at); got != tt.want {
+			t.Errorf("%s: active = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	occurrenceStart, end, _ := weekly.Occurrence(start.Add(14*24*time.Hour + 30*time.Minute))
+	if !occurrenceStart.Equal(start.Add(14*24*time.Hour)) || !end.Equal(start.Add(14*24*time.Hour+2*time.Hour)) {
+		t.Errorf("third occurrence = %s to %s, want two weeks after the first", occurrenceStart, end)
+	}
+
+	if once.Ended(start.Add(time.Hour)) || !once.Ended(start.Add(2*time.Hour)) || weekly.Ended(start.Add(30*24*time.Hour)) {
+		t.Error("Ended: a one-off window ends at ends_at, a weekly one never")
+	}
+}
+
+func TestMaintenanceWindowOccurrenceAcrossDST(t *testing.T) {
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+	// Sundays at 02:30 in Rome; clocks go forward on March 29, 2026 at 02:00
+	start := time.Date(2026, 3, 22, 1, 30, 0, 0, time.UTC) // 02:30 CET
+	weekly := MaintenanceWindow{StartsAt: start, EndsAt: start.Add(time.Hour), Weekly: true, Timezone: "Europe/Rome"}
+
+	// Two weeks on it still starts at 02:30, now CEST, not at 03:30
+	at := time.Date(2026, 4, 5, 2, 45, 0, 0, rome)
+	occurrenceStart, end, ok := weekly.Occurrence(at)
+	want := time.Date(2026, 4, 5, 2, 30, 0, 0, rome)
+	if !ok || !occurrenceStart.Equal(want) || !end.Equal(want.Add(time.Hour)) {
+		t.Errorf("occurrence = %s to %s (%v), want %s for an hour", occurrenceStart, end, ok, want)
+	}
+	if _, _, ok := weekly.Occurrence(want.Add(-time.Minute)); ok {
+		t.Error("active a minute before the wall clock start")
+	}
+
+	// Back to standard time on October 25, 2026
+	autumn := time.Date(2026, 11, 1, 2, 30, 0, 0, rome)
+	if occurrenceStart, _, ok := weekly.Occurrence(autumn.Add(59 * time.Minute)); !ok || !occurrenceStart.Equal(autumn) {
+		t.Errorf("occurrence after DST ends = %s (%v), want %s", occurrenceStart, ok, autumn)
+	}
+	if _, _, ok := weekly.Occurrence(autumn.Add(time.Hour)); ok {
+		t.Error("active past the end after DST ends")
+	}
+}
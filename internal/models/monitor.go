@@ -2,6 +2,7 @@ package models
 
 import (
 	"encoding/json"
+	"fmt"
 	"time"
 
 	"gorm.io/gorm"
@@ -17,9 +18,15 @@ type Monitor struct {
 	Interval       int                    `json:"interval" gorm:"default:60"`        // seconds
 	Timeout        int                    `json:"timeout" gorm:"default:30"`         // seconds
 	ResendInterval         int                    `json:"resend_interval" gorm:"default:0"`     // 0=once per downtime period, N=resend every N failures
+	Retries                int                    `json:"retries" gorm:"default:0"`             // re-checks of a failed check before it counts as down
+	RetryInterval          int                    `json:"retry_interval" gorm:"default:0"`      // seconds between retries, 0=5
 	IPVersion              string                 `json:"ip_version" gorm:"default:'auto'"`     // auto, ipv4, ipv6
+	Priority               int                    `json:"priority" gorm:"default:0"`            // higher runs first when checks are queued
+	RetentionDays          int                    `json:"retention_days" gorm:"default:0"`      // 0=owner's heartbeat_retention_days
 	Active                 bool                   `json:"active" gorm:"default:true;index"`
+	Public                 bool                   `json:"public" gorm:"default:false"` // shown on public status pages
 	NotificationsConfigured bool                   `json:"notifications_configured" gorm:"default:false"` // true if notifications have been explicitly set
+	EscalationPolicyID     *int                   `json:"escalation_policy_id"` // nil: no escalation
 	Config                 map[string]interface{} `json:"config" gorm:"-"`
 	ConfigRaw              string                 `json:"-" gorm:"column:config;type:text"`
 	CreatedAt              time.Time              `json:"created_at"`
@@ -55,3 +62,12 @@ func (m *Monitor) AfterFind(tx *gorm.DB) error {
 	}
 	return nil
 }
+
+// ValidateRetentionDays checks the heartbeat retention override: 0 to use
+// the owner's setting, or the same 7 to 365 days user settings allow
+func (m *Monitor) ValidateRetentionDays() error {
+	if m.RetentionDays != 0 && (m.RetentionDays < 7 || m.RetentionDays > 365) {
+		return fmt.Errorf("retention_days must be 0 or between 7 and 365")
+	}
+	return nil
+}
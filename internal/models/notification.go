@@ -4,15 +4,16 @@ import "time"
 
 // Notification represents a notification configuration
 type Notification struct {
-	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
-	UserID    int       `json:"user_id" gorm:"not null;index"`
-	Name      string    `json:"name" gorm:"not null"`
-	Type      string    `json:"type" gorm:"not null"`
-	Config    string    `json:"-" gorm:"type:text"` // JSON storage
-	IsDefault bool      `json:"is_default" gorm:"default:false"`
-	Active    bool      `json:"active" gorm:"default:true"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID               int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID           int       `json:"user_id" gorm:"not null;index"`
+	Name             string    `json:"name" gorm:"not null"`
+	Type             string    `json:"type" gorm:"not null"`
+	Config           string    `json:"-" gorm:"type:text"` // JSON storage
+	IsDefault        bool      `json:"is_default" gorm:"default:false"`
+	ReceiveAllEvents bool      `json:"receive_all_events" gorm:"default:false"`
+	Active           bool      `json:"active" gorm:"default:true"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 
 	// Relationships (optional, for eager loading)
 	User     User      `json:"-" gorm:"foreignKey:UserID"`
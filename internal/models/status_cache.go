@@ -0,0 +1,159 @@
+package models
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StatusCacheWindow is how much history MonitorStatusCache keeps: the hour
+// shown on public status pages
+const StatusCacheWindow = time.Hour
+
+// MonitorStatusCache holds the recent status history of a monitor, so public
+// status pages don't aggregate heartbeats on every request. It is updated
+// with each saved heartbeat.
+type MonitorStatusCache struct {
+	MonitorID     int            `json:"monitor_id" gorm:"primaryKey"`
+	BucketSeconds int            `json:"bucket_seconds" gorm:"not null"`
+	LastStatus    int            `json:"last_status" gorm:"not null"` // status of the latest heartbeat
+	LastTime      time.Time      `json:"last_time" gorm:"not null"`
+	CarryStatus   *int           `json:"carry_status"` // status of the latest heartbeat older than Buckets
+	Buckets       []StatusBucket `json:"buckets" gorm:"-"`
+	BucketsRaw    string         `json:"-" gorm:"column:buckets;type:text"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// StatusBucket summarizes the heartbeats of one bucket
+type StatusBucket struct {
+	Index int64 `json:"i"` // bucket start in Unix seconds divided by the bucket size
+	Worst int   `json:"w"` // most severe status
+	Last  int   `json:"l"` // status of the latest heartbeat
+}
+
+// TableName specifies the table name for MonitorStatusCache
+func (MonitorStatusCache) TableName() string {
+	return "monitor_status_cache"
+}
+
+// BeforeSave marshals Buckets to JSON before saving (GORM hook)
+func (c *MonitorStatusCache) BeforeSave(tx *gorm.DB) error {
+	bucketsJSON, err := json.Marshal(c.Buckets)
+	if err != nil {
+		return err
+	}
+	c.BucketsRaw = string(bucketsJSON)
+	return nil
+}
+
+// AfterFind unmarshals the Buckets JSON after loading (GORM hook)
+func (c *MonitorStatusCache) AfterFind(tx *gorm.DB) error {
+	if c.BucketsRaw != "" {
+		return json.Unmarshal([]byte(c.BucketsRaw), &c.Buckets)
+	}
+	return nil
+}
+
+// StatusBucketSeconds returns the status history bucket size for a monitor
+// interval: the interval, but at least a minute
+func StatusBucketSeconds(interval int) int {
+	return max(interval, 60)
+}
+
+// StatusSeverity orders statuses for a bucket: down, then maintenance,
+// pending and up
+func StatusSeverity(status int) int {
+	switch status {
+	case 0:
+		return 4
+	case 3:
+		return 3
+	case 2:
+		return 2
+	case 1:
+		return 1
+	}
+	return 0
+}
+
+// Record adds a heartbeat to the cache. A changed bucket size discards the
+// buckets, and buckets older than StatusCacheWindow are dropped.
+func (c *MonitorStatusCache) Record(status int, at time.Time, bucketSeconds int) {
+	hasData := !c.LastTime.IsZero()
+	if c.BucketSeconds != bucketSeconds {
+		if hasData {
+			last := c.LastStatus
+			c.CarryStatus = &last
+		}
+		c.Buckets = nil
+		c.BucketSeconds = bucketSeconds
+	}
+	if !hasData || !at.Before(c.LastTime) {
+		c.LastStatus = status
+		c.LastTime = at
+	}
+
+	index := at.Unix() / int64(bucketSeconds)
+	i := sort.Search(len(c.Buckets), func(i int) bool { return c.Buckets[i].Index >= index })
+	if i < len(c.Buckets) && c.Buckets[i].Index == index {
+		bucket := &c.Buckets[i]
+		if StatusSeverity(status) > StatusSeverity(bucket.Worst) {
+			bucket.Worst = status
+		}
+		if !at.Before(c.LastTime) {
+			bucket.Last = status
+		}
+	} else {
+		c.Buckets = append(c.Buckets, StatusBucket{})
+		copy(c.Buckets[i+1:], c.Buckets[i:])
+		c.Buckets[i] = StatusBucket{Index: index, Worst: status, Last: status}
+	}
+
+	// Keep one bucket more than the window, as pages don't align the
+	// window to bucket boundaries
+	oldest := c.LastTime.Add(-StatusCacheWindow).Unix()/int64(bucketSeconds) - 1
+	drop := sort.Search(len(c.Buckets), func(i int) bool { return c.Buckets[i].Index >= oldest })
+	if drop > 0 {
+		last := c.Buckets[drop-1].Last
+		c.CarryStatus = &last
+		c.Buckets = append(c.Buckets[:0], c.Buckets[drop:]...)
+	}
+}
+
+// History returns the status of count buckets from start, -1 where nothing
+// is known. A bucket without heartbeats repeats the status before it. Buckets
+// are whole, so the first also counts heartbeats just before start.
+func (c *MonitorStatusCache) History(start time.Time, count int) []int {
+	statuses := make([]int, count)
+	last := -1
+	if c.BucketSeconds <= 0 {
+		for i := range statuses {
+			statuses[i] = last
+		}
+		return statuses
+	}
+
+	startIndex := start.Unix() / int64(c.BucketSeconds)
+	if c.CarryStatus != nil {
+		last = *c.CarryStatus
+	}
+	j := 0
+	for ; j < len(c.Buckets) && c.Buckets[j].Index < startIndex; j++ {
+		last = c.Buckets[j].Last
+	}
+	// Without anything older, fall back to the latest status
+	if last == -1 && !c.LastTime.IsZero() {
+		last = c.LastStatus
+	}
+
+	for i := range statuses {
+		if j < len(c.Buckets) && c.Buckets[j].Index == startIndex+int64(i) {
+			last = c.Buckets[j].Worst
+			j++
+		}
+		statuses[i] = last
+	}
+	return statuses
+}
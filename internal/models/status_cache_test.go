@@ -0,0 +1,115 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// liveHistory aggregates heartbeats the way the public status page query
+// does without the cache
+func liveHistory(heartbeats []Heartbeat, start time.Time, bucketSeconds, count int) []int {
+	startIndex := start.Unix() / int64(bucketSeconds)
+	worst := make(map[int]int)
+	last := -1
+	for _, hb := range heartbeats {
+		if hb.Time.Before(start) {
+			last = hb.Status
+			continue
+		}
+		idx := int(hb.Time.Unix()/int64(bucketSeconds) - startIndex)
+		if current, ok := worst[idx]; !ok || StatusSeverity(hb.Status) > StatusSeverity(current) {
+			worst[idx] = hb.Status
+		}
+	}
+	if last == -1 && len(heartbeats) > 0 {
+		last = heartbeats[len(heartbeats)-1].Status
+	}
+
+	statuses := make([]int, count)
+	for i := range statuses {
+		if status, ok := worst[i]; ok {
+			last = status
+		}
+		statuses[i] = last
+	}
+	return statuses
+}
+
+// reload round-trips a cache through its database hooks
+func reload(t *testing.T, cache *MonitorStatusCache) *MonitorStatusCache {
+	t.Helper()
+	if err := cache.BeforeSave(nil); err != nil {
+		t.Fatalf("BeforeSave: %v", err)
+	}
+	loaded := *cache
+	loaded.Buckets = nil
+	if err := loaded.AfterFind(nil); err != nil {
+		t.Fatalf("AfterFind: %v", err)
+	}
+	return &loaded
+}
+
+func TestMonitorStatusCacheMatchesHeartbeats(t *testing.T) {
+	const bucketSeconds = 120
+	base := time.Unix(1_700_000_000/bucketSeconds*bucketSeconds, 0).UTC()
+	count := int(StatusCacheWindow / (bucketSeconds * time.Second))
+	// Down, maintenance and pending spells in otherwise up checks
+	statusAt := func(i int) int {
+		switch {
+		case i%37 < 3:
+			return 0
+		case i%53 == 10:
+			return 3
+		case i%41 == 20:
+			return 2
+		}
+		return 1
+	}
+
+	var heartbeats []Heartbeat
+	cache := &MonitorStatusCache{MonitorID: 1}
+	// Three hours of checks, some buckets holding several and some none
+	for i := 0; i < 200; i++ {
+		at := base.Add(time.Duration(i*i%7+i*55) * time.Second)
+		hb := Heartbeat{MonitorID: 1, Status: statusAt(i), Time: at}
+		heartbeats = append(heartbeats, hb)
+
+		cache.Record(hb.Status, hb.Time, bucketSeconds)
+		cache = reload(t, cache)
+
+		start := time.Unix(at.Add(-StatusCacheWindow).Unix()/bucketSeconds*bucketSeconds, 0)
+		got := cache.History(start, count)
+		want := liveHistory(heartbeats, start, bucketSeconds, count)
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("after heartbeat %d: cache history %v, want %v", i, got, want)
+		}
+	}
+
+	if limit := int(StatusCacheWindow/(bucketSeconds*time.Second)) + 2; len(cache.Buckets) > limit {
+		t.Errorf("cache holds %d buckets, want at most %d", len(cache.Buckets), limit)
+	}
+}
+
+func TestMonitorStatusCacheIntervalChange(t *testing.T) {
+	base := time.Unix(1_700_000_000/300*300, 0).UTC()
+	cache := &MonitorStatusCache{MonitorID: 1}
+	cache.Record(0, base, 60)
+	cache.Record(1, base.Add(time.Minute), 60)
+
+	// A new bucket size starts over, keeping the latest status
+	cache.Record(1, base.Add(5*time.Minute), 300)
+	if cache.BucketSeconds != 300 || len(cache.Buckets) != 1 {
+		t.Fatalf("cache = %+v, want one 300s bucket", cache)
+	}
+	if got := cache.History(base, 3); !reflect.DeepEqual(got, []int{1, 1, 1}) {
+		t.Errorf("history = %v, want the carried up status", got)
+	}
+}
+
+func TestMonitorStatusCacheEmpty(t *testing.T) {
+	var cache MonitorStatusCache
+	if got := cache.History(time.Now(), 2); !reflect.DeepEqual(got, []int{-1, -1}) {
+		t.Errorf("history = %v, want unknown buckets", got)
+	}
+}
@@ -14,6 +14,21 @@ type StatusPage struct {
 	Theme         string    `json:"theme" gorm:"default:light"`
 	CustomCSS     string    `json:"custom_css" gorm:"type:text"`
 	Password      string    `json:"-"` // Never send to client
+	// ConfirmationChecks is how many consecutive down checks it takes before
+	// the public page shows a monitor as down
+	ConfirmationChecks int `json:"confirmation_checks" gorm:"default:1"`
+	// CustomDomain is a host name that serves this page at its root; nil
+	// when the page is only reachable by slug
+	CustomDomain *string `json:"custom_domain" gorm:"uniqueIndex"`
+	// ShowUptimePercentage publishes each monitor's uptime percentage on
+	// the public page; the status bars show either way
+	ShowUptimePercentage bool `json:"show_uptime_percentage" gorm:"not null"`
+	// Banner is an announcement shown above the monitors until it is
+	// cleared, e.g. "Migration in progress this week"; empty for none
+	Banner string `json:"banner" gorm:"type:text;not null"`
+	// BannerStyle colors the banner like an incident: info, warning,
+	// danger or success
+	BannerStyle string `json:"banner_style" gorm:"not null"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 
@@ -55,6 +70,11 @@ type Incident struct {
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 
+	// AutoResolveAfter is the number of seconds after creation when the
+	// incident is resolved automatically; nil means never
+	AutoResolveAfter *int       `json:"auto_resolve_after,omitempty"`
+	ResolvedAt       *time.Time `json:"resolved_at"`
+
 	// Relationship (optional, for eager loading)
 	StatusPage StatusPage `json:"-" gorm:"foreignKey:StatusPageID"`
 }
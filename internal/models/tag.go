@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Tag is a user-defined label for grouping monitors
+type Tag struct {
+	ID        int       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    int       `json:"user_id" gorm:"not null;index"`
+	Name      string    `json:"name" gorm:"not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for Tag
+func (Tag) TableName() string {
+	return "tags"
+}
+
+// MonitorTag links monitors to tags
+type MonitorTag struct {
+	MonitorID int `gorm:"primaryKey"`
+	TagID     int `gorm:"primaryKey"`
+}
+
+// TableName specifies the table name for MonitorTag
+func (MonitorTag) TableName() string {
+	return "monitor_tags"
+}
@@ -7,12 +7,13 @@ type User struct {
 	ID         int       `json:"id" gorm:"primaryKey;autoIncrement"`
 	Username   string    `json:"username" gorm:"uniqueIndex;not null"`
 	Email      *string   `json:"email,omitempty" gorm:"uniqueIndex"` // Email (nullable, unique)
-	Password   string    `json:"-" gorm:"not null"`                   // Never expose password in JSON
-	Provider   *string   `json:"provider,omitempty"`                  // Auth provider: 'local' or 'oidc'
-	Subject    *string   `json:"subject,omitempty"`                   // OAuth subject (sub claim)
-	OAuthData  *string   `json:"-" gorm:"column:oauth_data"`          // JSON blob for OAuth data
+	Password   string    `json:"-" gorm:"not null"`                  // Never expose password in JSON
+	Provider   *string   `json:"provider,omitempty"`                 // Auth provider: 'local' or 'oidc'
+	Subject    *string   `json:"subject,omitempty"`                  // OAuth subject (sub claim)
+	OAuthData  *string   `json:"-" gorm:"column:oauth_data"`         // JSON blob for OAuth data
 	Active     bool      `json:"active" gorm:"default:true"`
-	TotpSecret *string   `json:"-" gorm:"column:totp_secret"` // 2FA secret (nullable)
+	IsAdmin    bool      `json:"is_admin" gorm:"default:false"` // may create script monitors
+	TotpSecret *string   `json:"-" gorm:"column:totp_secret"`   // 2FA secret (nullable)
 	CreatedAt  time.Time `json:"created_at"`
 
 	// Relationships (optional, for eager loading)
@@ -15,6 +15,14 @@ type UserSettings struct {
 	CreatedAt               time.Time `json:"created_at"`
 	UpdatedAt               time.Time `json:"updated_at"`
 
+	// AutoAttachDefaultNotifications links the user's default notifications
+	// to each new monitor; when false new monitors start without notifications
+	AutoAttachDefaultNotifications bool `json:"auto_attach_default_notifications" gorm:"not null"`
+
+	// Timezone is the IANA time zone uptime history is grouped in and
+	// notification times are shown in
+	Timezone string `json:"timezone" gorm:"default:UTC;not null"`
+
 	// Relationships
 	User User `json:"-" gorm:"foreignKey:UserID"`
 }
@@ -35,9 +43,34 @@ func (s *UserSettings) Validate() error {
 	if s.DailyStatRetentionDays < 90 || s.DailyStatRetentionDays > 1825 {
 		return fmt.Errorf("daily stat retention must be between 90 and 1825 days")
 	}
+	if err := ValidateTimezone(s.Timezone); err != nil {
+		return err
+	}
+	return nil
+}
+
+// ValidateTimezone checks that tz names a zone in the IANA database. Empty
+// means UTC.
+func ValidateTimezone(tz string) error {
+	if tz == "" {
+		return nil
+	}
+	// LoadLocation also accepts "Local", the server's own zone
+	if _, err := time.LoadLocation(tz); err != nil || tz == "Local" {
+		return fmt.Errorf("unknown time zone %q", tz)
+	}
 	return nil
 }
 
+// LoadTimezone returns the named time zone, UTC when empty or unknown
+func LoadTimezone(tz string) *time.Location {
+	loc, err := time.LoadLocation(tz)
+	if err != nil || tz == "Local" {
+		return time.UTC
+	}
+	return loc
+}
+
 // DefaultUserSettings returns settings with default values
 func DefaultUserSettings(userID int) UserSettings {
 	return UserSettings{
@@ -45,5 +78,8 @@ func DefaultUserSettings(userID int) UserSettings {
 		HeartbeatRetentionDays:  90,
 		HourlyStatRetentionDays: 365,
 		DailyStatRetentionDays:  730,
+
+		AutoAttachDefaultNotifications: true,
+		Timezone:                       "UTC",
 	}
 }
@@ -0,0 +1,120 @@
+package monitor
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"time"
+)
+
+// isHardError reports whether a check failed in a way retrying won't fix:
+// the host name doesn't exist, or its certificate is untrusted, for another
+// host or expired. Timeouts, refused connections and server errors are
+// transient.
+func isHardError(err error) bool {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		return dnsErr.IsNotFound && !dnsErr.IsTemporary
+	}
+	var hostnameErr x509.HostnameError
+	var authorityErr x509.UnknownAuthorityError
+	var invalidErr x509.CertificateInvalidError
+	return errors.As(err, &hostnameErr) || errors.As(err, &authorityErr) || errors.As(err, &invalidErr)
+}
+
+// autoPauseAfter returns after how many consecutive hard errors a monitor
+// pauses itself. The "auto_pause_after" config defaults to 0, never.
+func autoPauseAfter(monitor *Monitor) int {
+	switch v := monitor.Config["auto_pause_after"].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return 0
+}
+
+// ValidateAutoPause checks the optional auto_pause_after config, a whole
+// number of at least 1
+func ValidateAutoPause(monitor *Monitor) error {
+	var after float64
+	switch v := monitor.Config["auto_pause_after"].(type) {
+	case nil:
+		return nil
+	case float64:
+		after = v
+	case int:
+		after = float64(v)
+	default:
+		return fmt.Errorf("auto_pause_after must be a number")
+	}
+	if after < 1 || after != float64(int(after)) {
+		return fmt.Errorf("auto_pause_after must be a whole number of at least 1")
+	}
+	return nil
+}
+
+// countHardError tracks the monitor's run of hard errors and reports whether
+// it has reached auto_pause_after. Any other result ends the run.
+func (job *monitorJob) countHardError(heartbeat *Heartbeat) bool {
+	threshold := autoPauseAfter(job.monitor)
+	if threshold < 1 {
+		return false
+	}
+	if heartbeat.Status == StatusDown && heartbeat.HardError {
+		job.hardErrors++
+	} else {
+		job.hardErrors = 0
+	}
+	return job.hardErrors >= threshold
+}
+
+// autoPause deactivates a monitor that keeps failing with hard errors and
+// stops its job, so it no longer checks or resends alerts. One notification
+// says so; the monitor stays paused until it is resumed by hand.
+func (e *Executor) autoPause(job *monitorJob, heartbeat *Heartbeat) {
+	monitor := job.monitor
+	// A check queued before the job stopped may still finish; only the
+	// first one to reach the threshold pauses and notifies
+	if !e.stopJob(job) {
+		return
+	}
+	if err := e.pauseMonitor(monitor.ID); err != nil {
+		log.Printf("Failed to auto-pause monitor %d, stopped until restart: %v", monitor.ID, err)
+		return
+	}
+	log.Printf("Auto-paused monitor %s (ID: %d) after %d consecutive hard errors: %s",
+		monitor.Name, monitor.ID, job.hardErrors, heartbeat.Message)
+
+	if e.dispatcher != nil {
+		message := fmt.Sprintf("Paused after %d consecutive checks failed with: %s\n\nResume the monitor once the target is fixed.",
+			job.hardErrors, heartbeat.Message)
+		if err := e.dispatcher.NotifyMonitorAutoPaused(context.Background(), monitor.ID, monitor.Name, "", message); err != nil {
+			log.Printf("Failed to send auto-pause notification for monitor %d: %v", monitor.ID, err)
+		}
+	}
+}
+
+// deactivateMonitor marks a monitor inactive, as pausing it by hand does
+func (e *Executor) deactivateMonitor(monitorID int) error {
+	return e.db.Exec(`UPDATE monitors SET active = ?, updated_at = ? WHERE id = ?`, false, time.Now(), monitorID).Error
+}
+
+// stopJob stops a job unless it was already replaced or stopped, e.g. by
+// the monitor being edited while its check ran. It reports whether it
+// stopped the job.
+func (e *Executor) stopJob(job *monitorJob) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if current, exists := e.monitors[job.monitor.ID]; !exists || current != job {
+		return false
+	}
+	job.stop <- true
+	delete(e.monitors, job.monitor.ID)
+	e.unindexPushJob(job)
+	return true
+}
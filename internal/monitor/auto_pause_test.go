@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestAutoPauseAfterConsecutiveHardErrors(t *testing.T) {
+	var paused []int
+	e := NewExecutor(nil, nil, nil, 0)
+	e.pauseMonitor = func(monitorID int) error {
+		paused = append(paused, monitorID)
+		return nil
+	}
+
+	job := &monitorJob{
+		monitor:  &Monitor{ID: 9, Name: "deleted-site", Config: map[string]interface{}{"auto_pause_after": 3.0}},
+		stop:     make(chan bool, 1),
+		executor: e,
+	}
+	e.monitors[9] = job
+
+	hard := &Heartbeat{MonitorID: 9, Status: StatusDown, Message: "Request failed: no such host", HardError: true}
+	transient := &Heartbeat{MonitorID: 9, Status: StatusDown, Message: "Request failed: timeout"}
+
+	// A transient failure restarts the count
+	for i, hb := range []*Heartbeat{hard, hard, transient, hard, hard} {
+		if job.countHardError(hb) {
+			t.Fatalf("check %d reached the threshold early (%d hard errors)", i+1, job.hardErrors)
+		}
+	}
+	if !job.countHardError(hard) {
+		t.Fatalf("third consecutive hard error did not reach the threshold")
+	}
+
+	e.autoPause(job, hard)
+	if len(paused) != 1 || paused[0] != 9 {
+		t.Errorf("paused = %v, want monitor 9 once", paused)
+	}
+	if _, running := e.monitors[9]; running {
+		t.Error("job still running after auto-pause")
+	}
+	select {
+	case <-job.stop:
+	default:
+		t.Error("job was not told to stop")
+	}
+
+	// A check that was already queued doesn't pause or notify again
+	e.autoPause(job, hard)
+	if len(paused) != 1 {
+		t.Errorf("paused %d times, want once", len(paused))
+	}
+}
+
+func TestAutoPauseDisabledByDefault(t *testing.T) {
+	job := &monitorJob{monitor: &Monitor{ID: 1, Config: map[string]interface{}{}}}
+	for i := 0; i < 100; i++ {
+		if job.countHardError(&Heartbeat{Status: StatusDown, HardError: true}) {
+			t.Fatal("monitor without auto_pause_after paused")
+		}
+	}
+}
+
+func TestIsHardError(t *testing.T) {
+	nxdomain := &net.DNSError{Err: "no such host", Name: "gone.example.com", IsNotFound: true}
+	wrapped := &url.Error{Op: "Get", URL: "https://gone.example.com", Err: &net.OpError{Op: "dial", Net: "tcp", Err: nxdomain}}
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nxdomain", wrapped, true},
+		{"dns server failure", &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true}, false},
+		{"wrong certificate host", &url.Error{Op: "Get", URL: "https://example.com", Err: x509.HostnameError{Certificate: &x509.Certificate{}, Host: "example.com"}}, true},
+		{"untrusted certificate", fmt.Errorf("tls: %w", x509.UnknownAuthorityError{}), true},
+		{"expired certificate", x509.CertificateInvalidError{Reason: x509.Expired}, true},
+		{"connection refused", &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}, false},
+	}
+	for _, tt := range tests {
+		if got := isHardError(tt.err); got != tt.want {
+			t.Errorf("%s: isHardError = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateAutoPause(t *testing.T) {
+	for _, v := range []interface{}{nil, 1.0, 5} {
+		if err := ValidateAutoPause(&Monitor{Config: map[string]interface{}{"auto_pause_after": v}}); err != nil {
+			t.Errorf("ValidateAutoPause(%v) = %v", v, err)
+		}
+	}
+	for _, v := range []interface{}{0.0, -2.0, 2.5, "3"} {
+		if err := ValidateAutoPause(&Monitor{Config: map[string]interface{}{"auto_pause_after": v}}); err == nil {
+			t.Errorf("ValidateAutoPause(%v) succeeded, want error", v)
+		}
+	}
+}
@@ -0,0 +1,234 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultCertDaysWarning is how many days before expiry a certificate
+	// turns the check to a warning unless days_warning is set
+	defaultCertDaysWarning = 14
+
+	// maxCertDaysWarning caps days_warning at a year
+	maxCertDaysWarning = 365
+)
+
+// CertMonitor checks how long a server's TLS certificate has left. An
+// expired certificate is down; one expiring within days_warning is pending
+// and raises a warning.
+type CertMonitor struct{}
+
+func init() {
+	RegisterMonitorType(&CertMonitor{})
+}
+
+func (c *CertMonitor) Name() string {
+	return "cert"
+}
+
+func (c *CertMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, error) {
+	heartbeat := &Heartbeat{
+		MonitorID: monitor.ID,
+		Status:    StatusDown,
+		Time:      time.Now(),
+	}
+
+	host, port, err := certTarget(monitor.URL)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	minTLSVersion, err := parseTLSVersion(getConfigString(monitor, "min_tls_version", ""))
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	// tls_server_name is sent as SNI and verified instead of the host
+	serverName := tlsServerNameConfig(monitor)
+	if serverName == "" {
+		serverName = host
+	}
+
+	dialer, network, err := newCheckDialer(monitor, "tcp")
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	// The chain is verified below, after the expiry check, so an expired
+	// certificate is reported as such rather than as a failed handshake
+	start := time.Now()
+	conn, err := (&tls.Dialer{
+		NetDialer: dialer,
+		Config: &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+			MinVersion:         minTLSVersion,
+		},
+	}).DialContext(ctx, network, net.JoinHostPort(host, port))
+	heartbeat.Ping = int(time.Since(start).Milliseconds())
+	if err != nil {
+		if minTLSVersion != 0 && isTLSVersionError(err) {
+			heartbeat.Message = fmt.Sprintf("TLS handshake failed: server does not support %s or higher", tls.VersionName(minTLSVersion))
+			return heartbeat, nil
+		}
+		heartbeat.Message = fmt.Sprintf("TLS connection failed: %v", err)
+		heartbeat.HardError = isHardError(err)
+		return heartbeat, nil
+	}
+	defer conn.Close()
+	heartbeat.RemoteAddr = remoteIP(conn.RemoteAddr())
+
+	certs := conn.(*tls.Conn).ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		heartbeat.Message = "Server sent no certificate"
+		return heartbeat, nil
+	}
+
+	c.evaluate(heartbeat, monitor, serverName, certs)
+	return heartbeat, nil
+}
+
+// evaluate decides the heartbeat from the certificates the server sent,
+// leaf first, for the host name they should be valid for
+func (c *CertMonitor) evaluate(heartbeat *Heartbeat, monitor *Monitor, host string, certs []*x509.Certificate) {
+	leaf := certs[0]
+	if heartbeat.Time.After(leaf.NotAfter) {
+		heartbeat.Message = fmt.Sprintf("Certificate for %s expired %s", host, leaf.NotAfter.UTC().Format("2006-01-02"))
+		return
+	}
+
+	if verify, ok := monitor.Config["verify_chain"].(bool); verify || !ok {
+		roots, err := withCACertConfig(monitor, nil)
+		if err != nil {
+			heartbeat.Message = err.Error()
+			return
+		}
+		intermediates := x509.NewCertPool()
+		for _, cert := range certs[1:] {
+			intermediates.AddCert(cert)
+		}
+		_, err = leaf.Verify(x509.VerifyOptions{
+			DNSName:       host,
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   heartbeat.Time,
+		})
+		if err != nil {
+			heartbeat.Message = fmt.Sprintf("Certificate verification failed: %v", err)
+			heartbeat.HardError = isHardError(err)
+			return
+		}
+	}
+
+	// "expires in N days"
+	expiry := strings.TrimPrefix(certificateExpiry(leaf, heartbeat.Time), "certificate ")
+	heartbeat.Message = fmt.Sprintf("Certificate for %s %s (%s)", host, expiry, leaf.NotAfter.UTC().Format("2006-01-02"))
+
+	daysWarning := certDaysWarning(monitor)
+	if leaf.NotAfter.Sub(heartbeat.Time) < time.Duration(daysWarning)*24*time.Hour {
+		heartbeat.Status = StatusPending
+		heartbeat.Warning = true
+		heartbeat.Message += fmt.Sprintf(", within the %d day warning", daysWarning)
+		return
+	}
+	heartbeat.Status = StatusUp
+}
+
+func (c *CertMonitor) Validate(monitor *Monitor) error {
+	host, _, err := certTarget(monitor.URL)
+	if err != nil {
+		return err
+	}
+
+	// Resolving the host is part of the SSRF check
+	ssrfProtection := GetConfig().SSRFProtection()
+	if err := ssrfProtection.ValidateHost(host); err != nil {
+		return fmt.Errorf("host validation failed: %w", err)
+	}
+
+	if err := validateSourceIPConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateMinTLSVersionConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateCACertConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateTLSServerNameConfig(monitor); err != nil {
+		return err
+	}
+
+	days, set, err := wholeConfigNumber(monitor.Config, "days_warning")
+	if err != nil {
+		return err
+	}
+	if set && (days < 0 || days > maxCertDaysWarning) {
+		return fmt.Errorf("days_warning must be between 0 and %d", maxCertDaysWarning)
+	}
+
+	if raw, ok := monitor.Config["verify_chain"]; ok && raw != nil {
+		if _, ok := raw.(bool); !ok {
+			return fmt.Errorf("verify_chain must be a boolean")
+		}
+	}
+	return nil
+}
+
+// certTarget splits a cert monitor's URL, "host", "host:port" or an
+// https:// URL, into the host and port to connect to. The port defaults to
+// 443.
+func certTarget(raw string) (host, port string, err error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return "", "", fmt.Errorf("host is required")
+	}
+
+	address := raw
+	if strings.Contains(raw, "://") {
+		u, err := url.Parse(raw)
+		if err != nil || u.Scheme != "https" {
+			return "", "", fmt.Errorf("URL must be host, host:port or an https:// URL")
+		}
+		address = u.Host
+	}
+
+	host, port, err = net.SplitHostPort(address)
+	if err != nil {
+		// No port: a bare host, or an IPv6 address in brackets
+		host, port = strings.TrimSuffix(strings.TrimPrefix(address, "["), "]"), "443"
+	}
+	if host == "" || strings.ContainsAny(host, "/[] ") {
+		return "", "", fmt.Errorf("invalid host %q", raw)
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return "", "", fmt.Errorf("port must be between 1 and 65535")
+	}
+	return host, port, nil
+}
+
+// certDaysWarning returns the monitor's "days_warning", how many days before
+// expiry the check warns. 0 turns warnings off.
+func certDaysWarning(monitor *Monitor) int {
+	switch v := monitor.Config["days_warning"].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	}
+	return defaultCertDaysWarning
+}
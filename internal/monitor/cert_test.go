@@ -0,0 +1,240 @@
+package monitor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// selfSignedCert makes a certificate for example.com that expires at notAfter
+func selfSignedCert(t *testing.T, notAfter time.Time) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "example.com"},
+		DNSNames:     []string{"example.com"},
+		NotBefore:    notAfter.Add(-90 * 24 * time.Hour),
+		NotAfter:     notAfter,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestCertMonitorEvaluate(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name        string
+		notAfter    time.Time
+		config      map[string]interface{}
+		wantStatus  int
+		wantWarning bool
+		wantMessage string
+	}{
+		{name: "valid", notAfter: now.Add(60 * 24 * time.Hour), wantStatus: StatusUp, wantMessage: "expires in 60 days (2026-07-31)"},
+		{name: "within warning", notAfter: now.Add(9*24*time.Hour + time.Hour), wantStatus: StatusPending, wantWarning: true, wantMessage: "expires in 9 days (2026-06-10), within the 14 day warning"},
+		{name: "custom warning", notAfter: now.Add(20 * 24 * time.Hour), config: map[string]interface{}{"days_warning": float64(30)}, wantStatus: StatusPending, wantWarning: true, wantMessage: "within the 30 day warning"},
+		{name: "warning off", notAfter: now.Add(24 * time.Hour), config: map[string]interface{}{"days_warning": float64(0)}, wantStatus: StatusUp, wantMessage: "expires in 1 day"},
+		{name: "expired", notAfter: now.Add(-48 * time.Hour), wantStatus: StatusDown, wantMessage: "Certificate for example.com expired 2026-05-30"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"verify_chain": false}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+			hb := &Heartbeat{Status: StatusDown, Time: now}
+			(&CertMonitor{}).evaluate(hb, &Monitor{Config: config}, "example.com", []*x509.Certificate{selfSignedCert(t, tt.notAfter)})
+
+			if hb.Status != tt.wantStatus || hb.Warning != tt.wantWarning || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d warning %v %q, want %d warning %v containing %q",
+					hb.Status, hb.Warning, hb.Message, tt.wantStatus, tt.wantWarning, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestCertMonitorVerifiesChain(t *testing.T) {
+	now := time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+	certs := []*x509.Certificate{selfSignedCert(t, now.Add(60*24*time.Hour))}
+
+	// verify_chain defaults to true, and nothing trusts a self-signed cert
+	hb := &Heartbeat{Status: StatusDown, Time: now}
+	(&CertMonitor{}).evaluate(hb, &Monitor{}, "example.com", certs)
+	if hb.Status != StatusDown || !strings.HasPrefix(hb.Message, "Certificate verification failed") || !hb.HardError {
+		t.Errorf("got status %d %q (hard error %v), want a hard verification failure", hb.Status, hb.Message, hb.HardError)
+	}
+}
+
+func TestCertMonitorCheck(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	m := &Monitor{URL: server.URL, Timeout: 5, Config: map[string]interface{}{"verify_chain": false}}
+	hb, err := (&CertMonitor{}).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusUp || !strings.Contains(hb.Message, "Certificate for 127.0.0.1 expires in") {
+		t.Errorf("got status %d %q, want up with the days left", hb.Status, hb.Message)
+	}
+	if hb.RemoteAddr == nil || *hb.RemoteAddr != "127.0.0.1" {
+		t.Errorf("remote addr = %v, want 127.0.0.1", hb.RemoteAddr)
+	}
+}
+
+func TestCertMonitorServerNameAndCA(t *testing.T) {
+	var sni string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+		sni = hello.ServerName
+		return nil, nil
+	}}
+	server.StartTLS()
+	defer server.Close()
+
+	// httptest's certificate is for example.com, signed by itself
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	m := &Monitor{URL: server.URL, Timeout: 5, Config: map[string]interface{}{
+		"tls_server_name": "Example.com.",
+		"ca_cert":         caCert,
+	}}
+	if err := (&CertMonitor{}).Validate(&Monitor{URL: "93.184.215.14", Config: m.Config}); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+	hb, err := (&CertMonitor{}).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if sni != "example.com" {
+		t.Errorf("SNI = %q, want example.com", sni)
+	}
+	if hb.Status != StatusUp || !strings.Contains(hb.Message, "Certificate for example.com expires in") {
+		t.Errorf("got status %d %q, want up, verified for example.com against ca_cert", hb.Status, hb.Message)
+	}
+
+	// Without ca_cert nothing trusts it
+	delete(m.Config, "ca_cert")
+	hb, _ = (&CertMonitor{}).Check(context.Background(), m)
+	if hb.Status != StatusDown || !strings.HasPrefix(hb.Message, "Certificate verification failed") {
+		t.Errorf("got status %d %q, want a verification failure", hb.Status, hb.Message)
+	}
+}
+
+func TestCertMonitorMinTLSVersion(t *testing.T) {
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{MaxVersion: tls.VersionTLS12}
+	server.StartTLS()
+	defer server.Close()
+
+	for _, tt := range []struct {
+		version    string
+		wantStatus int
+		wantMsg    string
+	}{
+		{"1.2", StatusUp, "expires in"},
+		{"1.3", StatusDown, "server does not support TLS 1.3 or higher"},
+	} {
+		m := &Monitor{URL: server.URL, Timeout: 5, Config: map[string]interface{}{"verify_chain": false, "min_tls_version": tt.version}}
+		hb, err := (&CertMonitor{}).Check(context.Background(), m)
+		if err != nil {
+			t.Fatalf("Check: %v", err)
+		}
+		if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMsg) {
+			t.Errorf("min_tls_version %s: got status %d %q, want %d containing %q", tt.version, hb.Status, hb.Message, tt.wantStatus, tt.wantMsg)
+		}
+	}
+}
+
+func TestCertMonitorValidateTLSConfig(t *testing.T) {
+	for _, config := range []map[string]interface{}{
+		{"min_tls_version": "1.4"},
+		{"tls_server_name": "10.0.0.1"},
+		{"ca_cert": "not a certificate"},
+	} {
+		if err := (&CertMonitor{}).Validate(&Monitor{URL: "93.184.215.14", Config: config}); err == nil {
+			t.Errorf("Validate accepted %v", config)
+		}
+	}
+}
+
+func TestCertTarget(t *testing.T) {
+	tests := []struct {
+		raw      string
+		wantHost string
+		wantPort string
+		wantErr  bool
+	}{
+		{raw: "example.com", wantHost: "example.com", wantPort: "443"},
+		{raw: "example.com:8443", wantHost: "example.com", wantPort: "8443"},
+		{raw: "https://example.com/path", wantHost: "example.com", wantPort: "443"},
+		{raw: "https://example.com:993", wantHost: "example.com", wantPort: "993"},
+		{raw: "[2001:db8::1]:443", wantHost: "2001:db8::1", wantPort: "443"},
+		{raw: "[2001:db8::1]", wantHost: "2001:db8::1", wantPort: "443"},
+		{raw: "", wantErr: true},
+		{raw: "http://example.com", wantErr: true},
+		{raw: "example.com:0", wantErr: true},
+		{raw: "example.com:https", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		host, port, err := certTarget(tt.raw)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("certTarget(%q) error = %v, want error %v", tt.raw, err, tt.wantErr)
+			continue
+		}
+		if host != tt.wantHost || port != tt.wantPort {
+			t.Errorf("certTarget(%q) = %q, %q, want %q, %q", tt.raw, host, port, tt.wantHost, tt.wantPort)
+		}
+	}
+}
+
+func TestTrackWarningAlertsOncePerRun(t *testing.T) {
+	job := &monitorJob{}
+	warning := &Heartbeat{Status: StatusPending, Warning: true}
+
+	var alerts, clears []bool
+	for _, hb := range []*Heartbeat{
+		warning,
+		warning,
+		{Status: StatusDown}, // a failed check doesn't end the run
+		warning,
+		{Status: StatusUp}, // renewed
+		{Status: StatusUp},
+		warning,
+	} {
+		started, cleared := job.trackWarning(hb)
+		alerts = append(alerts, started)
+		clears = append(clears, cleared)
+	}
+
+	wantAlerts := []bool{true, false, false, false, false, false, true}
+	wantClears := []bool{false, false, false, false, true, false, false}
+	for i := range wantAlerts {
+		if alerts[i] != wantAlerts[i] || clears[i] != wantClears[i] {
+			t.Errorf("check %d: alert = %v, cleared = %v, want %v, %v", i, alerts[i], clears[i], wantAlerts[i], wantClears[i])
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package monitor
+
+import (
+	"container/heap"
+	"sync"
+)
+
+// queuedCheck is a pending monitor check waiting for a worker
+type queuedCheck struct {
+	monitorID int
+	priority  int
+	seq       uint64 // FIFO tie-breaker for equal priorities
+	run       func()
+}
+
+// checkHeap orders queued checks by priority (highest first), then by arrival
+type checkHeap []*queuedCheck
+
+func (h checkHeap) Len() int { return len(h) }
+
+func (h checkHeap) Less(i, j int) bool {
+	if h[i].priority != h[j].priority {
+		return h[i].priority > h[j].priority
+	}
+	return h[i].seq < h[j].seq
+}
+
+func (h checkHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *checkHeap) Push(x any) { *h = append(*h, x.(*queuedCheck)) }
+
+func (h *checkHeap) Pop() any {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	old[n-1] = nil
+	*h = old[:n-1]
+	return item
+}
+
+// checkQueue is a bounded worker pool for monitor checks. When every worker
+// is busy, queued checks are handed out highest priority first.
+type checkQueue struct {
+	mu     sync.Mutex
+	cond   *sync.Cond
+	items  checkHeap
+	queued map[int]bool // monitor IDs with a check already waiting
+	seq    uint64
+	closed bool
+	wg     sync.WaitGroup
+}
+
+// newCheckQueue creates an empty check queue
+func newCheckQueue() *checkQueue {
+	q := &checkQueue{queued: make(map[int]bool)}
+	q.cond = sync.NewCond(&q.mu)
+	return q
+}
+
+// start launches the given number of workers
+func (q *checkQueue) start(workers int) {
+	for i := 0; i < workers; i++ {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for {
+				check, ok := q.pop()
+				if !ok {
+					return
+				}
+				check.run()
+			}
+		}()
+	}
+}
+
+// push queues a check. A monitor that already has a check waiting is not
+// queued twice, so a saturated pool doesn't pile up stale checks.
+func (q *checkQueue) push(monitorID, priority int, run func()) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if q.closed || q.queued[monitorID] {
+		return false
+	}
+
+	q.seq++
+	heap.Push(&q.items, &queuedCheck{
+		monitorID: monitorID,
+		priority:  priority,
+		seq:       q.seq,
+		run:       run,
+	})
+	q.queued[monitorID] = true
+	q.cond.Signal()
+	return true
+}
+
+// pop blocks until a check is available or the queue is closed
+func (q *checkQueue) pop() (*queuedCheck, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for len(q.items) == 0 && !q.closed {
+		q.cond.Wait()
+	}
+	if q.closed {
+		return nil, false
+	}
+
+	check := heap.Pop(&q.items).(*queuedCheck)
+	delete(q.queued, check.monitorID)
+	return check, true
+}
+
+// close drops any waiting checks and stops the workers once their current
+// check finishes
+func (q *checkQueue) close() {
+	q.mu.Lock()
+	q.closed = true
+	q.items = nil
+	q.queued = make(map[int]bool)
+	q.cond.Broadcast()
+	q.mu.Unlock()
+
+	q.wg.Wait()
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import "testing"
+
+func TestCheckQueueDequeuesHighestPriorityFirst(t *testing.T) {
+	q := newCheckQueue()
+
+	checks := []struct {
+		monitorID int
+		priority  int
+	}{
+		{1, 0},
+		{2, 10},
+		{3, 5},
+		{4, 10},
+		{5, -1},
+	}
+	for _, c := range checks {
+		if !q.push(c.monitorID, c.priority, func() {}) {
+			t.Fatalf("push(%d) rejected", c.monitorID)
+		}
+	}
+
+	// Equal priorities keep arrival order
+	want := []int{2, 4, 3, 1, 5}
+	for i, id := range want {
+		check, ok := q.pop()
+		if !ok {
+			t.Fatalf("pop %d: queue closed", i)
+		}
+		if check.monitorID != id {
+			t.Errorf("pop %d = monitor %d, want %d", i, check.monitorID, id)
+		}
+	}
+}
+
+func TestCheckQueueSkipsAlreadyQueuedMonitor(t *testing.T) {
+	q := newCheckQueue()
+
+	if !q.push(1, 0, func() {}) {
+		t.Fatal("first push rejected")
+	}
+	if q.push(1, 0, func() {}) {
+		t.Error("second push for a queued monitor should be rejected")
+	}
+
+	if _, ok := q.pop(); !ok {
+		t.Fatal("pop: queue closed")
+	}
+	if !q.push(1, 0, func() {}) {
+		t.Error("push after dequeue should be accepted")
+	}
+}
+
+func TestCheckQueueClose(t *testing.T) {
+	q := newCheckQueue()
+	q.start(2)
+	q.push(1, 0, func() {})
+	q.close()
+
+	if q.push(2, 0, func() {}) {
+		t.Error("push after close should be rejected")
+	}
+	if _, ok := q.pop(); ok {
+		t.Error("pop after close should report closed")
+	}
+}
@@ -0,0 +1,123 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxCoalesceInterval caps coalesce_interval so a heartbeat never stands for
+// more than a day of checks
+const maxCoalesceInterval = 24 * 60 * 60
+
+// coalescedRun is the stored state of a monitor's current run of checks with
+// the same status while heartbeat coalescing is on
+type coalescedRun struct {
+	status int
+	// marker is the heartbeat row the run's latest checks are folded into;
+	// 0 until the run's first check after the one that started it
+	marker      int
+	markerStart time.Time // first check folded into the marker
+	checks      int       // checks the marker stands for
+}
+
+// coalesceInterval returns the monitor's "coalesce_interval" config: how long
+// a run of identical statuses may be folded into one heartbeat, 0 when every
+// check is stored
+func coalesceInterval(monitor *Monitor) time.Duration {
+	var seconds float64
+	switch v := monitor.Config["coalesce_interval"].(type) {
+	case float64:
+		seconds = v
+	case int:
+		seconds = float64(v)
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// ValidateCoalesceInterval checks the optional coalesce_interval config,
+// which every monitor type accepts: whole seconds, at least the check
+// interval and at most a day
+func ValidateCoalesceInterval(monitor *Monitor) error {
+	var seconds float64
+	switch v := monitor.Config["coalesce_interval"].(type) {
+	case nil:
+		return nil
+	case float64:
+		seconds = v
+	case int:
+		seconds = float64(v)
+	default:
+		return fmt.Errorf("coalesce_interval must be a number")
+	}
+	if seconds == 0 {
+		return nil
+	}
+	if seconds < 0 {
+		return fmt.Errorf("coalesce_interval must not be negative")
+	}
+	if seconds != float64(int(seconds)) {
+		return fmt.Errorf("coalesce_interval must be a whole number of seconds")
+	}
+	if seconds < float64(monitor.Interval) {
+		return fmt.Errorf("coalesce_interval must be at least the check interval (%ds)", monitor.Interval)
+	}
+	if seconds > maxCoalesceInterval {
+		return fmt.Errorf("coalesce_interval must be at most %d seconds", maxCoalesceInterval)
+	}
+	return nil
+}
+
+// persist stores a checked heartbeat. With coalesce_interval set, a check
+// that repeats the previous status is folded into a marker heartbeat: its
+// time, ping and message follow the latest check and "checks" counts the
+// checks it stands for. A new marker starts once the current one spans the
+// interval, and at each UTC hour: the hourly stats count a marker's checks
+// in the hour of its time, so its checks must all fall in that hour.
+// Transitions and other important heartbeats always get their own
+// row, so the timeline keeps every status change.
+func (job *monitorJob) persist(heartbeat *Heartbeat) error {
+	executor := job.executor
+	heartbeat.Checks = 1
+
+	interval := coalesceInterval(job.monitor)
+	run := job.coalesced
+	switch {
+	case interval <= 0:
+		job.coalesced = nil
+		return executor.saveHeartbeat(heartbeat)
+
+	case run == nil || heartbeat.Important || heartbeat.Status != run.status:
+		// The run's first heartbeat is never folded into
+		job.coalesced = nil
+		if err := executor.saveHeartbeat(heartbeat); err != nil {
+			return err
+		}
+		job.coalesced = &coalescedRun{status: heartbeat.Status}
+		return nil
+
+	case run.marker == 0 || heartbeat.Time.Sub(run.markerStart) >= interval ||
+		!heartbeat.Time.Truncate(time.Hour).Equal(run.markerStart.Truncate(time.Hour)):
+		if err := executor.saveHeartbeat(heartbeat); err != nil {
+			run.marker = 0
+			return err
+		}
+		run.marker = heartbeat.ID
+		run.markerStart = heartbeat.Time
+		run.checks = 1
+		return nil
+
+	default:
+		heartbeat.ID = run.marker
+		heartbeat.Checks = run.checks + 1
+		if err := executor.extendHeartbeat(heartbeat); err != nil {
+			// Start a fresh marker rather than guess what was stored
+			run.marker = 0
+			return err
+		}
+		run.checks++
+		return nil
+	}
+}
@@ -0,0 +1,195 @@
+package monitor
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// heartbeatTable stands in for the heartbeats table
+type heartbeatTable struct {
+	rows   []*Heartbeat
+	nextID int
+}
+
+func (h *heartbeatTable) insert(heartbeat *Heartbeat) error {
+	h.nextID++
+	heartbeat.ID = h.nextID
+	row := *heartbeat
+	h.rows = append(h.rows, &row)
+	return nil
+}
+
+func (h *heartbeatTable) update(heartbeat *Heartbeat) error {
+	for _, row := range h.rows {
+		if row.ID == heartbeat.ID {
+			row.Time, row.Ping, row.Message, row.Checks = heartbeat.Time, heartbeat.Ping, heartbeat.Message, heartbeat.Checks
+			return nil
+		}
+	}
+	return errors.New("no such heartbeat")
+}
+
+// newCoalesceTestJob returns a job checked every 20s that stores into table
+func newCoalesceTestJob(config map[string]interface{}) (*monitorJob, *heartbeatTable) {
+	table := &heartbeatTable{}
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = table.insert
+	e.extendHeartbeat = table.update
+	job := &monitorJob{
+		monitor:    &Monitor{ID: 1, Interval: 20, Config: config},
+		executor:   e,
+		lastStatus: StatusPending,
+	}
+	return job, table
+}
+
+// runChecks stores one check per status, 20s apart from start
+func runChecks(t *testing.T, job *monitorJob, start time.Time, statuses []int) {
+	t.Helper()
+	for i, status := range statuses {
+		at := start.Add(time.Duration(i) * 20 * time.Second)
+		hb := &Heartbeat{MonitorID: 1, Status: status, Time: at}
+		hb.Important = job.evaluate(status, at).important
+		if err := job.persist(hb); err != nil {
+			t.Fatalf("check %d: persist: %v", i, err)
+		}
+	}
+}
+
+func repeatStatus(status, n int) []int {
+	statuses := make([]int, n)
+	for i := range statuses {
+		statuses[i] = status
+	}
+	return statuses
+}
+
+func TestCoalescingKeepsTransitionsAndThinsSteadyState(t *testing.T) {
+	job, table := newCoalesceTestJob(map[string]interface{}{"coalesce_interval": 300.0})
+	start := time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC)
+
+	// 2 hours up, a 1 minute outage, then 20 more minutes up
+	var statuses []int
+	statuses = append(statuses, repeatStatus(StatusUp, 360)...)
+	statuses = append(statuses, repeatStatus(StatusDown, 3)...)
+	statuses = append(statuses, repeatStatus(StatusUp, 60)...)
+	runChecks(t, job, start, statuses)
+
+	if len(table.rows) >= len(statuses)/10 {
+		t.Errorf("stored %d heartbeats for %d checks, want far fewer", len(table.rows), len(statuses))
+	}
+
+	// Every status change is its own important row at the time it happened
+	var transitions []time.Time
+	for _, row := range table.rows {
+		if row.Important {
+			transitions = append(transitions, row.Time)
+		}
+	}
+	want := []time.Time{start, start.Add(360 * 20 * time.Second), start.Add(363 * 20 * time.Second)}
+	if len(transitions) != len(want) {
+		t.Fatalf("got %d important heartbeats at %v, want %v", len(transitions), transitions, want)
+	}
+	for i := range want {
+		if !transitions[i].Equal(want[i]) {
+			t.Errorf("transition %d at %s, want %s", i, transitions[i], want[i])
+		}
+	}
+
+	// Summed checks give the same uptime as storing every check
+	checks := map[int]int{}
+	for _, row := range table.rows {
+		checks[row.Status] += row.Checks
+	}
+	if checks[StatusUp] != 420 || checks[StatusDown] != 3 {
+		t.Errorf("checks by status = %v, want 420 up and 3 down", checks)
+	}
+
+	// No marker spans more than the interval, and the last row is the
+	// latest check, so staleness and the watchdog still see fresh data
+	for i := 1; i < len(table.rows); i++ {
+		if gap := table.rows[i].Time.Sub(table.rows[i-1].Time); gap > 300*time.Second+20*time.Second {
+			t.Errorf("rows %d and %d are %s apart", i-1, i, gap)
+		}
+	}
+	last := table.rows[len(table.rows)-1]
+	if wantLast := start.Add(time.Duration(len(statuses)-1) * 20 * time.Second); !last.Time.Equal(wantLast) {
+		t.Errorf("last heartbeat at %s, want the latest check at %s", last.Time, wantLast)
+	}
+}
+
+func TestCoalescingKeepsMarkersWithinTheHour(t *testing.T) {
+	job, table := newCoalesceTestJob(map[string]interface{}{"coalesce_interval": 3600})
+	start := time.Date(2026, 10, 16, 8, 40, 10, 0, time.UTC)
+	runChecks(t, job, start, repeatStatus(StatusUp, 120)) // until 9:19:50
+
+	// Hourly stats sum the checks of the rows stored in each hour
+	stored := map[time.Time]int{}
+	for _, row := range table.rows {
+		stored[row.Time.Truncate(time.Hour)] += row.Checks
+	}
+	want := map[time.Time]int{
+		time.Date(2026, 10, 16, 8, 0, 0, 0, time.UTC): 60,
+		time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC): 60,
+	}
+	for hour, checks := range want {
+		if stored[hour] != checks {
+			t.Errorf("hour %s stands for %d checks, want %d", hour.Format("15:04"), stored[hour], checks)
+		}
+	}
+}
+
+func TestCoalescingOffStoresEveryCheck(t *testing.T) {
+	job, table := newCoalesceTestJob(map[string]interface{}{})
+	runChecks(t, job, time.Now(), repeatStatus(StatusUp, 30))
+
+	if len(table.rows) != 30 {
+		t.Fatalf("stored %d heartbeats, want all 30", len(table.rows))
+	}
+	for _, row := range table.rows {
+		if row.Checks != 1 {
+			t.Errorf("heartbeat %d stands for %d checks, want 1", row.ID, row.Checks)
+		}
+	}
+}
+
+func TestCoalescingRecoversFromFailedUpdate(t *testing.T) {
+	job, table := newCoalesceTestJob(map[string]interface{}{"coalesce_interval": 300})
+	start := time.Now()
+	runChecks(t, job, start, repeatStatus(StatusUp, 3))
+
+	job.executor.extendHeartbeat = func(*Heartbeat) error { return errors.New("connection reset") }
+	hb := &Heartbeat{MonitorID: 1, Status: StatusUp, Time: start.Add(time.Minute)}
+	if err := job.persist(hb); err == nil {
+		t.Fatal("persist succeeded despite the failed update")
+	}
+
+	job.executor.extendHeartbeat = table.update
+	before := len(table.rows)
+	hb = &Heartbeat{MonitorID: 1, Status: StatusUp, Time: start.Add(80 * time.Second)}
+	if err := job.persist(hb); err != nil {
+		t.Fatalf("persist: %v", err)
+	}
+	if len(table.rows) != before+1 || table.rows[len(table.rows)-1].Checks != 1 {
+		t.Errorf("after a failed update the next check should start a new marker")
+	}
+}
+
+func TestValidateCoalesceInterval(t *testing.T) {
+	valid := []interface{}{nil, 0, 60.0, 300, 86400}
+	for _, v := range valid {
+		m := &Monitor{Interval: 60, Config: map[string]interface{}{"coalesce_interval": v}}
+		if err := ValidateCoalesceInterval(m); err != nil {
+			t.Errorf("ValidateCoalesceInterval(%v) = %v", v, err)
+		}
+	}
+
+	invalid := []interface{}{"300", 30, 90.5, 86401, -60}
+	for _, v := range invalid {
+		m := &Monitor{Interval: 60, Config: map[string]interface{}{"coalesce_interval": v}}
+		if err := ValidateCoalesceInterval(m); err == nil {
+			t.Errorf("ValidateCoalesceInterval(%v) succeeded, want error", v)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"fmt"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+)
+
+const (
+	// maxConditionLength bounds user supplied condition expressions
+	maxConditionLength = 1024
+
+	// maxConditionBodySize caps how much of a response body a condition sees
+	maxConditionBodySize = 1 << 20
+)
+
+// httpConditionEnv is the variables available to HTTP monitor conditions
+type httpConditionEnv struct {
+	Status   int    `expr:"status"`    // response status code
+	Ping     int    `expr:"ping"`      // response time in milliseconds
+	Body     string `expr:"body"`      // response body
+	BodySize int    `expr:"body_size"` // response body length in bytes
+}
+
+// newHTTPConditionEnv builds the variables for an HTTP condition
+func newHTTPConditionEnv(status, ping int, body string) httpConditionEnv {
+	return httpConditionEnv{Status: status, Ping: ping, Body: body, BodySize: len(body)}
+}
+
+// Condition is a compiled success condition such as
+// `status == 200 && ping < 500 && body contains "ok"`, in the expr language
+// (https://expr-lang.org): comparisons, && / and, || / or, ! / not, and the
+// string operators contains, startsWith, endsWith and matches.
+type Condition struct {
+	source  string
+	program *vm.Program
+}
+
+// CompileCondition parses and type-checks an expression against the
+// variables of env, a struct such as httpConditionEnv. The expression must
+// evaluate to a bool.
+func CompileCondition(source string, env interface{}) (*Condition, error) {
+	if len(source) > maxConditionLength {
+		return nil, fmt.Errorf("condition is too long (max %d characters)", maxConditionLength)
+	}
+
+	program, err := expr.Compile(source, expr.Env(env), expr.AsBool())
+	if err != nil {
+		return nil, err
+	}
+	return &Condition{source: source, program: program}, nil
+}
+
+// Eval evaluates the condition against env, of the type it was compiled with
+func (c *Condition) Eval(env interface{}) (bool, error) {
+	v, err := expr.Run(c.program, env)
+	if err != nil {
+		return false, err
+	}
+	return v.(bool), nil
+}
+
+// String returns the original expression
+func (c *Condition) String() string {
+	return c.source
+}
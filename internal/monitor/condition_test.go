@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConditionEval(t *testing.T) {
+	env := newHTTPConditionEnv(200, 120, `{"status":"ok","version":"1.4.2"}`)
+
+	tests := []struct {
+		condition string
+		want      bool
+	}{
+		{`status == 200`, true},
+		{`status == 200 && ping < 500 && body contains "ok"`, true},
+		{`status == 200 && ping < 100`, false},
+		{`status != 200 || ping > 1000`, false},
+		{`status >= 200 and status < 300`, true},
+		{`body contains "error"`, false},
+		{`!(body contains "error")`, true},
+		{`not (body contains "ok")`, false},
+		{`body startsWith "{" && body endsWith "}"`, true},
+		{"body matches `\"version\":\"1\\.\\d+\\.\\d+\"`", true}, // a raw string keeps the regex escapes
+		{`body matches "^error"`, false},
+		{`body_size > 10`, true},
+		{`(status == 301 || status == 200) && true`, true},
+		{`status == 500 or false`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.condition, func(t *testing.T) {
+			c, err := CompileCondition(tt.condition, httpConditionEnv{})
+			if err != nil {
+				t.Fatalf("CompileCondition(%q): %v", tt.condition, err)
+			}
+			got, err := c.Eval(env)
+			if err != nil {
+				t.Fatalf("Eval(%q): %v", tt.condition, err)
+			}
+			if got != tt.want {
+				t.Errorf("Eval(%q) = %v, want %v", tt.condition, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileConditionRejectsInvalid(t *testing.T) {
+	tests := []string{
+		``,
+		`status`,
+		`status == "200"`,
+		`body < 5`,
+		`unknown == 1`,
+		`status == 200 &&`,
+		`(status == 200`,
+		`status == 200)`,
+		`body contains 5`,
+		`body matches "("`,
+		`body contains "unterminated`,
+		`status = 200`,
+		`ping < 1.2.3`,
+	}
+
+	for _, condition := range tests {
+		if _, err := CompileCondition(condition, httpConditionEnv{}); err == nil {
+			t.Errorf("CompileCondition(%q) succeeded, want error", condition)
+		}
+	}
+}
+
+func TestHTTPValidateRejectsConditionWithKeywords(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{"condition alone", map[string]interface{}{"condition": `body contains "ok"`}, false},
+		{"keyword alone", map[string]interface{}{"keyword": "ok"}, false},
+		{"condition and keyword", map[string]interface{}{"condition": `status == 200`, "keyword": "ok"}, true},
+		{"condition and keywords", map[string]interface{}{"condition": `status == 200`, "keywords": []interface{}{"ok", "ready"}}, true},
+	}
+
+	for _, tt := range tests {
+		err := NewHTTPMonitor(nil).Validate(&Monitor{URL: "http://93.184.216.34/health", Config: tt.config})
+		if tt.wantErr && (err == nil || !strings.Contains(err.Error(), "cannot be combined with condition")) {
+			t.Errorf("%s: error = %v, want keywords rejected alongside a condition", tt.name, err)
+		}
+		if !tt.wantErr && err != nil {
+			t.Errorf("%s: unexpected error %v", tt.name, err)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package monitor
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"strings"
@@ -50,15 +51,22 @@ func (d *DNSMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, e
 		expectedResult = expected
 	}
 
+	// With expect_nxdomain the check passes while the name doesn't resolve,
+	// e.g. to confirm a deprovisioned record stays gone
+	expectNXDomain, _ := monitor.Config["expect_nxdomain"].(bool)
+
 	// Create resolver with IP version support
 	resolver := &net.Resolver{
 		PreferGo: true,
 		Dial: func(ctx context.Context, network, address string) (net.Conn, error) {
-			// Determine network based on IP version preference
-			network = GetNetworkForIPVersion(network, monitor.IPVersion)
-			d := net.Dialer{
-				Timeout: time.Duration(monitor.Timeout) * time.Second,
+			// Network follows the IP version preference and source IP
+			d, network, err := newCheckDialer(monitor, network)
+			if err != nil {
+				return nil, err
 			}
+			// Connecting to the resolver gets the whole monitor timeout;
+			// query_timeout only bounds the lookup below
+			d.Timeout = time.Duration(monitor.Timeout) * time.Second
 			// Use custom DNS server if specified, otherwise use default
 			if dnsServer != "" {
 				targetServer := dnsServer
@@ -72,7 +80,7 @@ func (d *DNSMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, e
 	}
 
 	// Create context with timeout
-	queryCtx, cancel := context.WithTimeout(ctx, time.Duration(monitor.Timeout)*time.Second)
+	queryCtx, cancel := context.WithTimeout(ctx, dnsQueryTimeout(monitor))
 	defer cancel()
 
 	// Measure query time
@@ -123,6 +131,10 @@ func (d *DNSMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, e
 
 	ping := time.Since(start).Milliseconds()
 
+	if expectNXDomain {
+		return expectAbsent(heartbeat, queryType, hostname, results, err, ping), nil
+	}
+
 	if err != nil {
 		heartbeat.Status = StatusDown
 		heartbeat.Ping = int(ping)
@@ -161,11 +173,64 @@ func (d *DNSMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, e
 	return heartbeat, nil
 }
 
+// expectAbsent maps a lookup to a heartbeat for a monitor expecting the name
+// not to resolve. NXDOMAIN, and an answer without records of the queried
+// type, mean up; records mean down. Other failures, such as a timeout or
+// SERVFAIL, prove nothing about the record and stay down.
+func expectAbsent(heartbeat *Heartbeat, queryType, hostname string, results []string, err error, ping int64) *Heartbeat {
+	heartbeat.Ping = int(ping)
+
+	var dnsErr *net.DNSError
+	switch {
+	case errors.As(err, &dnsErr) && dnsErr.IsNotFound, err == nil && len(results) == 0:
+		heartbeat.Status = StatusUp
+		heartbeat.Message = fmt.Sprintf("%s query OK - %s not found as expected - %dms", queryType, hostname, ping)
+	case err != nil:
+		heartbeat.Status = StatusDown
+		heartbeat.Message = fmt.Sprintf("DNS query failed: %v", err)
+	default:
+		heartbeat.Status = StatusDown
+		heartbeat.Message = fmt.Sprintf("Expected NXDOMAIN, found %s records: %s", queryType, strings.Join(results, ", "))
+	}
+	return heartbeat
+}
+
 func (d *DNSMonitor) Validate(monitor *Monitor) error {
 	if monitor.URL == "" {
 		return fmt.Errorf("hostname is required")
 	}
 
+	if err := validateSourceIPConfig(monitor); err != nil {
+		return err
+	}
+
+	var queryTimeout float64
+	switch v := monitor.Config["query_timeout"].(type) {
+	case nil:
+	case float64:
+		queryTimeout = v
+	case int:
+		queryTimeout = float64(v)
+	default:
+		return fmt.Errorf("query_timeout must be a number")
+	}
+	if monitor.Config["query_timeout"] != nil && queryTimeout <= 0 {
+		return fmt.Errorf("query_timeout must be positive")
+	}
+	if monitor.Timeout > 0 && queryTimeout > float64(monitor.Timeout) {
+		return fmt.Errorf("query_timeout must not exceed the monitor timeout (%ds)", monitor.Timeout)
+	}
+
+	if v, ok := monitor.Config["expect_nxdomain"]; ok && v != nil {
+		expectNXDomain, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("expect_nxdomain must be a boolean")
+		}
+		if expected, _ := monitor.Config["expected_result"].(string); expectNXDomain && expected != "" {
+			return fmt.Errorf("expect_nxdomain cannot be combined with expected_result")
+		}
+	}
+
 	// Validate query type
 	if qt, ok := monitor.Config["query_type"]; ok {
 		if queryType, ok := qt.(string); ok {
@@ -188,3 +253,19 @@ func (d *DNSMonitor) Validate(monitor *Monitor) error {
 
 	return nil
 }
+
+// dnsQueryTimeout is how long a lookup may take: the "query_timeout" config
+// in seconds, fractions allowed, or the monitor timeout
+func dnsQueryTimeout(monitor *Monitor) time.Duration {
+	var seconds float64
+	switch v := monitor.Config["query_timeout"].(type) {
+	case float64:
+		seconds = v
+	case int:
+		seconds = float64(v)
+	}
+	if seconds <= 0 || (monitor.Timeout > 0 && seconds > float64(monitor.Timeout)) {
+		return time.Duration(monitor.Timeout) * time.Second
+	}
+	return time.Duration(seconds * float64(time.Second))
+}
@@ -0,0 +1,222 @@
+package monitor
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newSlowResolver starts a UDP DNS server that answers every A query with
+// 192.0.2.1 after delay, and returns its address
+func newSlowResolver(t *testing.T, delay time.Duration) string {
+	t.Helper()
+	return newTestResolver(t, delay, func(query []byte) []byte {
+		return dnsAnswerA(query, net.IPv4(192, 0, 2, 1))
+	})
+}
+
+// newTestResolver starts a UDP DNS server that replies to every query with
+// respond's answer after delay, and returns its address
+func newTestResolver(t *testing.T, delay time.Duration, respond func(query []byte) []byte) string {
+	t.Helper()
+	conn, err := net.ListenPacket("udp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("ListenPacket: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 512)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			query := append([]byte(nil), buf[:n]...)
+			go func() {
+				time.Sleep(delay)
+				conn.WriteTo(respond(query), addr)
+			}()
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+// dnsQuestion returns the header and question of a single-question query
+func dnsQuestion(query []byte) []byte {
+	// The question ends after its name's terminating zero, type and class
+	end := 12
+	for end < len(query) && query[end] != 0 {
+		end += int(query[end]) + 1
+	}
+	end += 5
+	return append([]byte(nil), query[:end]...)
+}
+
+// dnsNXDomain builds an NXDOMAIN response to a single-question query
+func dnsNXDomain(query []byte) []byte {
+	resp := dnsQuestion(query)
+	binary.BigEndian.PutUint16(resp[2:], 0x8183) // response, recursion desired and available, NXDOMAIN
+	binary.BigEndian.PutUint16(resp[6:], 0)
+	binary.BigEndian.PutUint16(resp[8:], 0)
+	binary.BigEndian.PutUint16(resp[10:], 0)
+	return resp
+}
+
+// dnsServerFailure builds a SERVFAIL response to a single-question query
+func dnsServerFailure(query []byte) []byte {
+	resp := dnsNXDomain(query)
+	binary.BigEndian.PutUint16(resp[2:], 0x8182)
+	return resp
+}
+
+// dnsAnswerA builds the response to a single-question query with one A
+// record
+func dnsAnswerA(query []byte, ip net.IP) []byte {
+	resp := dnsQuestion(query)
+	binary.BigEndian.PutUint16(resp[2:], 0x8180) // response, recursion desired and available
+	binary.BigEndian.PutUint16(resp[6:], 1)      // one answer
+	binary.BigEndian.PutUint16(resp[8:], 0)
+	binary.BigEndian.PutUint16(resp[10:], 0)
+	resp = append(resp, 0xc0, 12) // name: pointer to the question's
+	resp = append(resp, 0, 1, 0, 1, 0, 0, 0, 60, 0, 4)
+	return append(resp, ip.To4()...)
+}
+
+func TestDNSMonitorQueryTimeout(t *testing.T) {
+	server := newSlowResolver(t, 300*time.Millisecond)
+
+	tests := []struct {
+		name         string
+		queryTimeout interface{}
+		wantStatus   int
+		wantMessage  string
+	}{
+		{name: "monitor timeout", queryTimeout: nil, wantStatus: StatusUp, wantMessage: "192.0.2.1"},
+		{name: "query timeout long enough", queryTimeout: 2, wantStatus: StatusUp, wantMessage: "192.0.2.1"},
+		{name: "query timeout too short", queryTimeout: 0.1, wantStatus: StatusDown, wantMessage: "DNS query failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"dns_server": server}
+			if tt.queryTimeout != nil {
+				config["query_timeout"] = tt.queryTimeout
+			}
+			m := &Monitor{Type: "dns", URL: "slow.example.com.", Timeout: 5, Config: config}
+
+			start := time.Now()
+			hb, err := (&DNSMonitor{}).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+			if elapsed := time.Since(start); elapsed > 2*time.Second {
+				t.Errorf("check took %s", elapsed)
+			}
+		})
+	}
+}
+
+func TestDNSMonitorValidateQueryTimeout(t *testing.T) {
+	valid := []interface{}{nil, 1, 2.5, 10}
+	for _, queryTimeout := range valid {
+		m := &Monitor{URL: "example.com", Timeout: 10, Config: map[string]interface{}{"query_timeout": queryTimeout}}
+		if err := (&DNSMonitor{}).Validate(m); err != nil {
+			t.Errorf("Validate(query_timeout=%v) = %v", queryTimeout, err)
+		}
+	}
+
+	invalid := []interface{}{0, -1, 10.5, 30, "5"}
+	for _, queryTimeout := range invalid {
+		m := &Monitor{URL: "example.com", Timeout: 10, Config: map[string]interface{}{"query_timeout": queryTimeout}}
+		if err := (&DNSMonitor{}).Validate(m); err == nil {
+			t.Errorf("Validate(query_timeout=%v) succeeded, want error", queryTimeout)
+		}
+	}
+}
+
+func TestDNSQueryTimeoutDefault(t *testing.T) {
+	m := &Monitor{Timeout: 8, Config: map[string]interface{}{}}
+	if got := dnsQueryTimeout(m); got != 8*time.Second {
+		t.Errorf("dnsQueryTimeout() = %s, want the 8s monitor timeout", got)
+	}
+	m.Config["query_timeout"] = 1.5
+	if got := dnsQueryTimeout(m); got != 1500*time.Millisecond {
+		t.Errorf("dnsQueryTimeout() = %s, want 1.5s", got)
+	}
+}
+
+func TestDNSMonitorExpectNXDomain(t *testing.T) {
+	tests := []struct {
+		name        string
+		respond     func(query []byte) []byte
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "nxdomain", respond: dnsNXDomain, wantStatus: StatusUp, wantMessage: "not found as expected"},
+		{name: "record found", respond: func(query []byte) []byte {
+			return dnsAnswerA(query, net.IPv4(192, 0, 2, 7))
+		}, wantStatus: StatusDown, wantMessage: "Expected NXDOMAIN, found A records: 192.0.2.7"},
+		{name: "server failure", respond: dnsServerFailure, wantStatus: StatusDown, wantMessage: "DNS query failed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTestResolver(t, 0, tt.respond)
+			m := &Monitor{Type: "dns", URL: "gone.example.com.", Timeout: 5, Config: map[string]interface{}{
+				"dns_server":      server,
+				"expect_nxdomain": true,
+			}}
+			hb, err := (&DNSMonitor{}).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestDNSMonitorNXDomainWithoutExpectation(t *testing.T) {
+	server := newTestResolver(t, 0, dnsNXDomain)
+	m := &Monitor{Type: "dns", URL: "gone.example.com.", Timeout: 5, Config: map[string]interface{}{"dns_server": server}}
+	hb, err := (&DNSMonitor{}).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusDown {
+		t.Errorf("got status %d %q, want down", hb.Status, hb.Message)
+	}
+}
+
+func TestDNSMonitorValidateExpectNXDomain(t *testing.T) {
+	valid := []map[string]interface{}{
+		{"expect_nxdomain": true},
+		{"expect_nxdomain": false, "expected_result": "192.0.2.1"},
+		{"expect_nxdomain": nil, "expected_result": "192.0.2.1"},
+		{"expect_nxdomain": true, "expected_result": ""},
+	}
+	for _, config := range valid {
+		if err := (&DNSMonitor{}).Validate(&Monitor{URL: "example.com", Timeout: 10, Config: config}); err != nil {
+			t.Errorf("Validate(%v) = %v", config, err)
+		}
+	}
+
+	invalid := []map[string]interface{}{
+		{"expect_nxdomain": "true"},
+		{"expect_nxdomain": 1},
+		{"expect_nxdomain": true, "expected_result": "192.0.2.1"},
+	}
+	for _, config := range invalid {
+		if err := (&DNSMonitor{}).Validate(&Monitor{URL: "example.com", Timeout: 10, Config: config}); err == nil {
+			t.Errorf("Validate(%v) succeeded, want error", config)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"log"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// escalationState walks one outage through its monitor's escalation policy
+type escalationState struct {
+	steps    []models.EscalationStep
+	next     int   // index of the next step to notify
+	notified []int // channels escalated to so far, without duplicates
+}
+
+// escalation is an escalation step due for notification
+type escalation struct {
+	step            int // position in the policy, counting from 1
+	notificationIDs []int
+}
+
+// due returns the steps whose delay an outage of the given length has
+// reached and that haven't notified yet, and marks them notified
+func (s *escalationState) due(outage time.Duration) []escalation {
+	var due []escalation
+	for s.next < len(s.steps) && outage >= s.steps[s.next].Delay() {
+		step := s.steps[s.next]
+		s.next++
+		due = append(due, escalation{step: s.next, notificationIDs: step.NotificationIDs})
+		for _, id := range step.NotificationIDs {
+			if !containsInt(s.notified, id) {
+				s.notified = append(s.notified, id)
+			}
+		}
+	}
+	return due
+}
+
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// escalate advances the monitor's escalation policy for a new status. An
+// outage starts escalating with its first down alert; from then on each down
+// check returns the steps whose delay the outage has reached since its first
+// failure, every step once. A recovery ends the escalation and, when it is
+// notified, returns the channels escalated to so they hear about it too.
+// Pending and maintenance checks leave the escalation where it was.
+func (job *monitorJob) escalate(status int, at time.Time, decision checkDecision) (due []escalation, recovered []int) {
+	switch status {
+	case StatusDown:
+		if job.escalation == nil && decision.notifyDown && job.monitor.EscalationPolicyID != nil {
+			steps, err := job.executor.escalationSteps(*job.monitor.EscalationPolicyID)
+			if err != nil {
+				log.Printf("Failed to load escalation policy %d for monitor %d: %v",
+					*job.monitor.EscalationPolicyID, job.monitor.ID, err)
+				return nil, nil
+			}
+			job.escalation = &escalationState{steps: steps}
+		}
+		if job.escalation != nil {
+			due = job.escalation.due(at.Sub(job.downSince))
+		}
+	case StatusUp:
+		if job.escalation != nil && decision.notifyUp {
+			recovered = job.escalation.notified
+		}
+		job.escalation = nil
+	}
+	return due, recovered
+}
+
+// loadEscalationSteps reads the steps of an escalation policy
+func (e *Executor) loadEscalationSteps(policyID int) ([]models.EscalationStep, error) {
+	var policy models.EscalationPolicy
+	if err := e.db.First(&policy, policyID).Error; err != nil {
+		return nil, err
+	}
+	return policy.Steps, nil
+}
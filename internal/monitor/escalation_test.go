@@ -0,0 +1,119 @@
+package monitor
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// newEscalatingJob returns a job whose monitor escalates along steps
+func newEscalatingJob(resendInterval int, steps []models.EscalationStep) *monitorJob {
+	policyID := 7
+	job := newTestJob(StatusUp, resendInterval)
+	job.monitor.EscalationPolicyID = &policyID
+	job.executor = &Executor{
+		escalationSteps: func(id int) ([]models.EscalationStep, error) {
+			if id != policyID {
+				return nil, fmt.Errorf("escalation policy %d not found", id)
+			}
+			return steps, nil
+		},
+	}
+	return job
+}
+
+// slackOncallManager notifies Slack at once, on-call after 10 minutes and a
+// manager after 30
+var slackOncallManager = []models.EscalationStep{
+	{DelayMinutes: 0, NotificationIDs: []int{1}},
+	{DelayMinutes: 10, NotificationIDs: []int{2, 3}},
+	{DelayMinutes: 30, NotificationIDs: []int{4, 1}},
+}
+
+func TestEscalationWalksOutage(t *testing.T) {
+	job := newEscalatingJob(0, slackOncallManager)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	checks := []struct {
+		status        int
+		after         time.Duration
+		wantSteps     []int
+		wantRecovered []int
+	}{
+		{StatusDown, 0, []int{1}, nil},
+		{StatusDown, 5 * time.Minute, nil, nil},
+		{StatusDown, 10 * time.Minute, []int{2}, nil},
+		{StatusDown, 20 * time.Minute, nil, nil},
+		{StatusMaintenance, 29 * time.Minute, nil, nil}, // paused, outage continues
+		{StatusPending, 30 * time.Minute, nil, nil},
+		{StatusDown, 31 * time.Minute, []int{3}, nil},
+		{StatusDown, 60 * time.Minute, nil, nil}, // every step notified once
+		{StatusUp, 65 * time.Minute, nil, []int{1, 2, 3, 4}},
+		{StatusUp, 66 * time.Minute, nil, nil},
+		{StatusDown, 70 * time.Minute, []int{1}, nil}, // a new outage starts over
+	}
+
+	for i, check := range checks {
+		at := start.Add(check.after)
+		decision := job.evaluate(check.status, at)
+		due, recovered := job.escalate(check.status, at, decision)
+
+		var steps []int
+		for _, e := range due {
+			steps = append(steps, e.step)
+		}
+		if !reflect.DeepEqual(steps, check.wantSteps) || !reflect.DeepEqual(recovered, check.wantRecovered) {
+			t.Errorf("check %d (+%s): escalated to steps %v, recovered %v; want %v, %v",
+				i, check.after, steps, recovered, check.wantSteps, check.wantRecovered)
+		}
+	}
+}
+
+func TestEscalationStartsWithFirstAlert(t *testing.T) {
+	// resend_interval 3 alerts on the third failure, 12 minutes into the
+	// outage: the immediate and 10 minute steps both go out then
+	job := newEscalatingJob(3, slackOncallManager)
+	start := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	var got [][]int
+	for i, after := range []time.Duration{0, 6 * time.Minute, 12 * time.Minute} {
+		at := start.Add(after)
+		due, _ := job.escalate(StatusDown, at, job.evaluate(StatusDown, at))
+		for _, e := range due {
+			got = append(got, e.notificationIDs)
+		}
+		if i < 2 && len(got) > 0 {
+			t.Fatalf("escalated before the first alert, at +%s", after)
+		}
+	}
+	if want := [][]int{{1}, {2, 3}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("escalated to %v, want %v", got, want)
+	}
+}
+
+func TestEscalationRecoveryNotificationDisabled(t *testing.T) {
+	job := newEscalatingJob(0, slackOncallManager)
+	job.monitor.Config = map[string]interface{}{"notify_recovery": false}
+	at := time.Now()
+
+	job.escalate(StatusDown, at, job.evaluate(StatusDown, at))
+	_, recovered := job.escalate(StatusUp, at.Add(time.Minute), job.evaluate(StatusUp, at.Add(time.Minute)))
+	if recovered != nil {
+		t.Errorf("recovered = %v, want none with recovery notifications off", recovered)
+	}
+	if job.escalation != nil {
+		t.Error("escalation still running after recovery")
+	}
+}
+
+func TestEscalationWithoutPolicy(t *testing.T) {
+	job := newTestJob(StatusUp, 0)
+	at := time.Now()
+	due, _ := job.escalate(StatusDown, at, job.evaluate(StatusDown, at))
+	if due != nil || job.escalation != nil {
+		t.Errorf("monitor without a policy escalated: %v", due)
+	}
+}
@@ -2,11 +2,18 @@ package monitor
 
 import (
 	"context"
+	"crypto/sha256"
+	"fmt"
 	"log"
+	"net/http"
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"github.com/fuomag9/uptime-kabomba/internal/models"
 	"github.com/fuomag9/uptime-kabomba/internal/websocket"
 	"github.com/fuomag9/uptime-kabomba/internal/notification"
 )
@@ -18,6 +25,36 @@ type Executor struct {
 	dispatcher *notification.Dispatcher
 	monitors   map[int]*monitorJob
 	mu         sync.RWMutex
+	queue      *checkQueue // nil when checks are not bounded
+	maxChecks  int
+	// maintenance holds the maintenance windows of monitors, guarded by mu
+	maintenance map[int][]models.MaintenanceWindow
+	// pushJobs holds the running push monitors by pushTokenKey, guarded by mu
+	pushJobs map[[sha256.Size]byte]*monitorJob
+
+	// saveHeartbeat persists a heartbeat, setting its ID
+	saveHeartbeat func(heartbeat *Heartbeat) error
+	// extendHeartbeat folds a check into the stored heartbeat with its ID
+	extendHeartbeat func(heartbeat *Heartbeat) error
+	// resultClient posts check results to result webhooks
+	resultClient *http.Client
+	// escalationSteps loads the steps of an escalation policy
+	escalationSteps func(policyID int) ([]models.EscalationStep, error)
+	// pauseMonitor deactivates a monitor that auto_pause_after stopped
+	pauseMonitor func(monitorID int) error
+	// jobHistory loads what a starting job picks up from the monitor's
+	// stored heartbeats
+	jobHistory func(monitorID int) jobStart
+	// retryWait waits between the retries of a failed check
+	retryWait func(d time.Duration)
+}
+
+// jobStart is a starting job's state from the monitor's stored heartbeats
+type jobStart struct {
+	lastStatus int       // last settled status
+	warmingUp  bool      // the monitor has no heartbeats yet
+	failures   int       // down checks since the last up one
+	downSince  time.Time // the first of those down checks
 }
 
 // monitorJob represents a running monitor job
@@ -28,16 +65,52 @@ type monitorJob struct {
 	executor           *Executor
 	lastStatus         int // Track last status for change detection
 	consecutiveFailures int // Track consecutive down statuses
+	downSince          time.Time // first down check of the current outage
+	agents             agentResults // latest results reported by remote agents
+	recentFailures     []string // messages of the current outage's latest failures
+	warmingUp          bool // no heartbeat yet: the first up/down result sends no notifications
+	deferredDown       bool // the first check's down alert, held for a confirming check
+	escalation         *escalationState // the current outage's escalation, once alerted
+	coalesced          *coalescedRun // the stored run of identical statuses, with coalesce_interval set
+	hardErrors         int // consecutive checks failed with a hard error, with auto_pause_after set
+	flap               flapState // recent status changes, with flap_threshold set
+	warned             bool // a warning was sent and no up check has followed
+	resentPeriods      int // downtime periods alerted for, with resend_interval_seconds set
+
+	recordMu  sync.Mutex // serializes record, as pushes arrive alongside the scheduled checks
+	stateMu   sync.Mutex // guards the state Executor.Jobs reads while checks run
+	nextCheck time.Time  // when the next scheduled check is due
+	lastCheck time.Time  // when the latest check was made; zero before the first
+	lastPush  time.Time  // latest push to a push monitor, or when the job started
 }
 
-// NewExecutor creates a new monitor executor
-func NewExecutor(db *gorm.DB, hub *websocket.Hub, dispatcher *notification.Dispatcher) *Executor {
-	return &Executor{
+const (
+	// recentFailureWindow is how many of an outage's latest failure
+	// messages are kept for down notifications
+	recentFailureWindow = 20
+
+	// maxFailureSamples caps the distinct messages a down notification lists
+	maxFailureSamples = 3
+)
+
+// NewExecutor creates a new monitor executor.
+// maxChecks bounds how many checks run at once; 0 means unlimited.
+func NewExecutor(db *gorm.DB, hub *websocket.Hub, dispatcher *notification.Dispatcher, maxChecks int) *Executor {
+	e := &Executor{
 		db:         db,
 		hub:        hub,
 		dispatcher: dispatcher,
 		monitors:   make(map[int]*monitorJob),
+		maxChecks:  maxChecks,
 	}
+	e.saveHeartbeat = e.insertHeartbeat
+	e.extendHeartbeat = e.updateHeartbeat
+	e.resultClient = newResultWebhookClient()
+	e.escalationSteps = e.loadEscalationSteps
+	e.pauseMonitor = e.deactivateMonitor
+	e.jobHistory = e.loadJobHistory
+	e.retryWait = time.Sleep
+	return e
 }
 
 // Start loads all active monitors and starts monitoring
@@ -51,6 +124,16 @@ func (e *Executor) Start() error {
 
 	log.Printf("Starting %d active monitors", len(monitors))
 
+	if err := e.loadMaintenanceWindows(); err != nil {
+		log.Printf("Failed to load maintenance windows: %v", err)
+	}
+
+	if e.maxChecks > 0 {
+		e.queue = newCheckQueue()
+		e.queue.start(e.maxChecks)
+		log.Printf("Monitor checks limited to %d concurrent workers", e.maxChecks)
+	}
+
 	for _, monitor := range monitors {
 		// Config is already parsed by AfterFind hook
 		e.StartMonitor(monitor)
@@ -68,38 +151,40 @@ func (e *Executor) StartMonitor(monitor *Monitor) {
 	if job, exists := e.monitors[monitor.ID]; exists {
 		job.stop <- true
 		delete(e.monitors, monitor.ID)
+		e.unindexPushJob(job)
 	}
 
-	// Get last heartbeat status from database
-	lastStatus := StatusPending
-	var lastHeartbeat struct {
-		Status int `gorm:"column:status"`
-	}
-	query := `SELECT status FROM heartbeats WHERE monitor_id = ? ORDER BY time DESC LIMIT 1`
-	if err := e.db.Raw(query, monitor.ID).Scan(&lastHeartbeat).Error; err == nil {
-		lastStatus = lastHeartbeat.Status
+	// A type that is no longer registered (e.g. page_change with Chrome
+	// disabled) can't be checked; report it instead of leaving it idle
+	if _, ok := GetMonitorType(monitor.Type); !ok {
+		e.recordUnsupportedType(monitor)
+		return
 	}
 
+	history := e.jobHistory(monitor.ID)
+
 	// Create new job
 	job := &monitorJob{
-		monitor:    monitor,
-		ticker:     time.NewTicker(time.Duration(monitor.Interval) * time.Second),
-		stop:       make(chan bool),
-		executor:   e,
-		lastStatus: lastStatus,
+		monitor:  monitor,
+		ticker:   time.NewTicker(time.Duration(monitor.Interval) * time.Second),
+		stop:     make(chan bool),
+		executor: e,
+		lastPush: time.Now(),
 	}
+	job.resume(history, time.Now())
 
 	e.monitors[monitor.ID] = job
+	e.indexPushJob(job)
 
 	// Run first check immediately
-	go job.runCheck()
+	job.schedule()
 
 	// Start ticker
 	go func() {
 		for {
 			select {
 			case <-job.ticker.C:
-				go job.runCheck()
+				job.schedule()
 			case <-job.stop:
 				job.ticker.Stop()
 				return
@@ -110,6 +195,58 @@ func (e *Executor) StartMonitor(monitor *Monitor) {
 	log.Printf("Started monitor: %s (ID: %d, Interval: %ds)", monitor.Name, monitor.ID, monitor.Interval)
 }
 
+// loadJobHistory gets the last heartbeat status from the database. Pending
+// heartbeats are skipped so the first settled status after a restart is
+// compared against the last real one. An ongoing outage's failures and start
+// are counted from the down heartbeats since the last up one, so resends and
+// the recovery's downtime carry on across a restart.
+func (e *Executor) loadJobHistory(monitorID int) jobStart {
+	lastStatus := StatusPending
+	var lastHeartbeat struct {
+		Status int `gorm:"column:status"`
+	}
+	query := `SELECT status FROM heartbeats WHERE monitor_id = ? AND status <> ? ORDER BY time DESC LIMIT 1`
+	result := e.db.Raw(query, monitorID, StatusPending).Scan(&lastHeartbeat)
+	if result.Error == nil && result.RowsAffected > 0 {
+		lastStatus = lastHeartbeat.Status
+	}
+
+	// A brand-new monitor warms up: its first result is stored as is, but
+	// alerts wait for a second check so setup mistakes don't page anyone
+	warmingUp := false
+	if result.Error == nil && result.RowsAffected == 0 {
+		var exists bool
+		if err := e.db.Raw(`SELECT EXISTS (SELECT 1 FROM heartbeats WHERE monitor_id = ?)`, monitorID).
+			Scan(&exists).Error; err == nil && !exists {
+			warmingUp = true
+		}
+	}
+
+	history := jobStart{lastStatus: lastStatus, warmingUp: warmingUp}
+	if lastStatus == StatusPending {
+		return history
+	}
+	var outage struct {
+		Failures  int        `gorm:"column:failures"`
+		DownSince *time.Time `gorm:"column:down_since"`
+	}
+	err := e.db.Raw(outageQuery, monitorID, StatusDown, monitorID, StatusUp).Scan(&outage).Error
+	if err == nil && outage.Failures > 0 && outage.DownSince != nil {
+		history.failures = outage.Failures
+		history.downSince = *outage.DownSince
+	}
+	return history
+}
+
+// outageQuery sums the down checks since a monitor's last up heartbeat, and
+// finds the first of them. A coalesced row stands for several checks.
+const outageQuery = `
+	SELECT COALESCE(SUM(checks), 0) AS failures, MIN(time) AS down_since
+	FROM heartbeats
+	WHERE monitor_id = ? AND status = ?
+	  AND time > COALESCE((SELECT MAX(time) FROM heartbeats WHERE monitor_id = ? AND status = ?), '-infinity')
+`
+
 // StopMonitor stops monitoring for a specific monitor
 func (e *Executor) StopMonitor(monitorID int) {
 	e.mu.Lock()
@@ -118,6 +255,7 @@ func (e *Executor) StopMonitor(monitorID int) {
 	if job, exists := e.monitors[monitorID]; exists {
 		job.stop <- true
 		delete(e.monitors, monitorID)
+		e.unindexPushJob(job)
 		log.Printf("Stopped monitor ID: %d", monitorID)
 	}
 }
@@ -125,16 +263,61 @@ func (e *Executor) StopMonitor(monitorID int) {
 // Stop stops all monitors
 func (e *Executor) Stop() {
 	e.mu.Lock()
-	defer e.mu.Unlock()
-
 	for id, job := range e.monitors {
 		job.stop <- true
 		delete(e.monitors, id)
 	}
+	e.pushJobs = nil
+	queue := e.queue
+	e.mu.Unlock()
+
+	if queue != nil {
+		queue.close()
+	}
 
 	log.Println("All monitors stopped")
 }
 
+// recordUnsupportedType stores a DOWN heartbeat for a monitor whose type is
+// not registered, so it shows as failing rather than healthy but never checked
+func (e *Executor) recordUnsupportedType(monitor *Monitor) {
+	log.Printf("Not starting monitor %s (ID: %d): unsupported monitor type %q", monitor.Name, monitor.ID, monitor.Type)
+
+	heartbeat := &Heartbeat{
+		MonitorID: monitor.ID,
+		Status:    StatusDown,
+		Message:   fmt.Sprintf("Unsupported monitor type %q", monitor.Type),
+		Time:      time.Now(),
+	}
+	if err := e.saveHeartbeat(heartbeat); err != nil {
+		log.Printf("Failed to save heartbeat for monitor %d: %v", monitor.ID, err)
+		return
+	}
+
+	if e.hub != nil {
+		e.hub.Broadcast("heartbeat", heartbeat)
+	}
+}
+
+// schedule runs a check right away, or queues it by priority when the
+// executor has a bounded worker pool
+func (job *monitorJob) schedule() {
+	job.stateMu.Lock()
+	job.nextCheck = time.Now().Add(time.Duration(job.monitor.Interval) * time.Second)
+	job.stateMu.Unlock()
+
+	queue := job.executor.queue
+	if queue == nil {
+		go job.runCheck()
+		return
+	}
+
+	if !queue.push(job.monitor.ID, job.monitor.Priority, job.runCheck) {
+		log.Printf("Skipping check for monitor %s (ID: %d): previous check still queued",
+			job.monitor.Name, job.monitor.ID)
+	}
+}
+
 // runCheck performs a single monitor check
 func (job *monitorJob) runCheck() {
 	monitor := job.monitor
@@ -146,19 +329,54 @@ func (job *monitorJob) runCheck() {
 		return
 	}
 
-	// Create context with timeout
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(monitor.Timeout+5)*time.Second)
-	defer cancel()
+	// Push monitors aren't checked; their checks only look for a missed push
+	if monitor.Type == pushMonitorType {
+		if heartbeat := job.checkPush(time.Now()); heartbeat != nil {
+			job.record(heartbeat)
+		}
+		return
+	}
 
-	// Perform check
-	heartbeat, err := monitorType.Check(ctx, monitor)
+	// Perform check, retrying a failure when the monitor has retries
+	heartbeat, err := job.checkWithRetries(monitorType)
 	if err != nil {
 		log.Printf("Monitor check failed for %s (ID: %d): %v", monitor.Name, monitor.ID, err)
 		return
 	}
 
+	// Combine with remote agents' results when the monitor has any
+	job.applyQuorum(heartbeat)
+
+	job.record(heartbeat)
+}
+
+// record stores a monitor's new heartbeat, broadcasts it and sends the
+// notifications its status calls for. Heartbeats of a job are recorded one
+// at a time, so the outage state they update stays consistent.
+func (job *monitorJob) record(heartbeat *Heartbeat) {
+	job.recordMu.Lock()
+	defer job.recordMu.Unlock()
+
+	monitor := job.monitor
+
+	// Planned maintenance overrides what the check found
+	job.applyMaintenance(heartbeat)
+
+	// Decide importance and notifications before persisting so the
+	// heartbeat is stored with the right flag
+	job.stateMu.Lock()
+	decision := job.evaluate(heartbeat.Status, heartbeat.Time)
+	job.lastCheck = heartbeat.Time
+	startedFlapping := job.trackFlapping(heartbeat.Status, heartbeat.Time)
+	flapChanges := len(job.flap.changes)
+	sendWarning, clearedWarning := job.trackWarning(heartbeat)
+	job.stateMu.Unlock()
+	heartbeat.Important = decision.important
+	job.recordFailure(heartbeat.Status, heartbeat.Message)
+	escalations, escalatedTo := job.escalate(heartbeat.Status, heartbeat.Time, decision)
+
 	// Save heartbeat to database
-	if err := job.saveHeartbeat(heartbeat); err != nil {
+	if err := job.persist(heartbeat); err != nil {
 		log.Printf("Failed to save heartbeat for monitor %d: %v", monitor.ID, err)
 		return
 	}
@@ -168,96 +386,341 @@ func (job *monitorJob) runCheck() {
 		job.executor.hub.Broadcast("heartbeat", heartbeat)
 	}
 
-	// Detect status changes and send notifications
+	// Feed the monitor's result webhook, if any
+	go job.executor.postResult(monitor, heartbeat)
+
+	// Send notifications for status changes
 	if job.executor.dispatcher != nil {
 		ctx := context.Background()
 		monitorURL := "" // TODO: Generate monitor URL when status pages are implemented
 
-		// Track consecutive failures for resend logic
-		if heartbeat.Status == StatusDown {
-			job.consecutiveFailures++
-
-			// Determine if we should send notification based on resend_interval
-			resendInterval := monitor.ResendInterval
+		if decision.notifyDown {
+			err := job.executor.dispatcher.NotifyMonitorDown(ctx, monitor.ID, monitor.Name, monitorURL, heartbeat.Ping, job.downMessage(heartbeat.Message))
+			if err != nil {
+				log.Printf("Failed to send down notification for monitor %d: %v", monitor.ID, err)
+			} else {
+				log.Printf("Sent DOWN notification for monitor %s (ID: %d) after %d consecutive failures",
+					monitor.Name, monitor.ID, job.consecutiveFailures)
+			}
+		} else if job.deferredDown {
+			log.Printf("Monitor %s (ID: %d) failed its first check, holding the alert for a confirming check",
+				monitor.Name, monitor.ID)
+		} else if heartbeat.Status == StatusDown && resendPeriod(monitor) > 0 {
+			log.Printf("Monitor %s (ID: %d) is down (%d consecutive failures), resending every %s of downtime",
+				monitor.Name, monitor.ID, job.consecutiveFailures, resendPeriod(monitor))
+		} else if heartbeat.Status == StatusDown {
+			log.Printf("Monitor %s (ID: %d) is down (%d consecutive failures), waiting for threshold %d",
+				monitor.Name, monitor.ID, job.consecutiveFailures, monitor.ResendInterval)
+		}
 
-			shouldNotify := false
-			if resendInterval == 0 {
-				// resend_interval=0 means notify only once per downtime period (never resend)
-				if job.consecutiveFailures == 1 {
-					shouldNotify = true
-				}
+		// Page the escalation steps the outage has now lasted long enough for
+		for _, step := range escalations {
+			outage := heartbeat.Time.Sub(job.downSince)
+			err := job.executor.dispatcher.NotifyMonitorEscalated(ctx, step.notificationIDs, step.step, monitor.ID, monitor.Name, monitorURL, heartbeat.Ping, job.downMessage(heartbeat.Message), outage)
+			if err != nil {
+				log.Printf("Failed to send escalation step %d for monitor %d: %v", step.step, monitor.ID, err)
 			} else {
-				// Ensure minimum of 1 for non-zero values
-				if resendInterval < 1 {
-					resendInterval = 1
-				}
-
-				if job.consecutiveFailures == resendInterval {
-					// First notification after reaching threshold
-					shouldNotify = true
-				} else if job.consecutiveFailures > resendInterval {
-					// Resend notification: check if it's time to resend
-					// After threshold is reached, resend every resend_interval failures
-					if (job.consecutiveFailures-resendInterval)%resendInterval == 0 {
-						shouldNotify = true
-					}
-				}
+				log.Printf("Escalated monitor %s (ID: %d) to step %d after %s down",
+					monitor.Name, monitor.ID, step.step, outage.Round(time.Second))
 			}
+		}
 
-			if shouldNotify {
-				err := job.executor.dispatcher.NotifyMonitorDown(ctx, monitor.ID, monitor.Name, monitorURL, heartbeat.Ping, heartbeat.Message)
-				if err != nil {
-					log.Printf("Failed to send down notification for monitor %d: %v", monitor.ID, err)
-				} else {
-					log.Printf("Sent DOWN notification for monitor %s (ID: %d) after %d consecutive failures",
-						monitor.Name, monitor.ID, job.consecutiveFailures)
-				}
+		if decision.notifyUp {
+			err := job.executor.dispatcher.NotifyMonitorUp(ctx, monitor.ID, monitor.Name, monitorURL, heartbeat.Ping, heartbeat.Message, decision.downtime)
+			if err != nil {
+				log.Printf("Failed to send up notification for monitor %d: %v", monitor.ID, err)
 			} else {
-				log.Printf("Monitor %s (ID: %d) is down (%d consecutive failures), waiting for threshold %d",
-					monitor.Name, monitor.ID, job.consecutiveFailures, resendInterval)
+				log.Printf("Sent UP notification for monitor %s (ID: %d) after %d failures (down for %s)",
+					monitor.Name, monitor.ID, decision.failures, decision.downtime.Round(time.Second))
 			}
-		} else if heartbeat.Status == StatusUp {
-			// Monitor came back up - reset consecutive failures and send notification if was down
-			if job.consecutiveFailures > 0 {
-				err := job.executor.dispatcher.NotifyMonitorUp(ctx, monitor.ID, monitor.Name, monitorURL, heartbeat.Ping, heartbeat.Message)
-				if err != nil {
-					log.Printf("Failed to send up notification for monitor %d: %v", monitor.ID, err)
-				} else {
-					log.Printf("Sent UP notification for monitor %s (ID: %d) after %d failures",
-						monitor.Name, monitor.ID, job.consecutiveFailures)
-				}
-				job.consecutiveFailures = 0 // Reset counter
+		}
+		if len(escalatedTo) > 0 {
+			err := job.executor.dispatcher.NotifyEscalationRecovered(ctx, escalatedTo, monitor.ID, monitor.Name, monitorURL, heartbeat.Ping, heartbeat.Message, decision.downtime)
+			if err != nil {
+				log.Printf("Failed to send escalation recovery for monitor %d: %v", monitor.ID, err)
 			}
 		}
 	}
 
-	// Update last status
-	job.lastStatus = heartbeat.Status
-
 	// Log status
 	statusText := "DOWN"
-	if heartbeat.Status == StatusUp {
+	switch heartbeat.Status {
+	case StatusUp:
 		statusText = "UP"
+	case StatusMaintenance:
+		statusText = "MAINTENANCE"
 	}
 	log.Printf("Monitor %s (ID: %d): %s - %dms - %s",
 		monitor.Name, monitor.ID, statusText, heartbeat.Ping, heartbeat.Message)
+
+	if startedFlapping {
+		_, window := flapDetection(monitor)
+		job.executor.notifyFlapping(job, flapChanges, window)
+	}
+
+	if sendWarning {
+		job.executor.notifyWarning(job, heartbeat.Message)
+	}
+	if clearedWarning {
+		job.executor.notifyWarningCleared(job, heartbeat.Message)
+	}
+
+	// Stop a monitor whose target is gone rather than alerting forever
+	if job.countHardError(heartbeat) {
+		job.executor.autoPause(job, heartbeat)
+	}
+}
+
+// resume sets a starting job's transition state from the monitor's stored
+// heartbeats, so an outage that was going on before a restart keeps its
+// failure count and start
+func (job *monitorJob) resume(history jobStart, now time.Time) {
+	job.lastStatus = history.lastStatus
+	job.warmingUp = history.warmingUp
+	if history.failures == 0 {
+		return
+	}
+	job.consecutiveFailures = history.failures
+	job.downSince = history.downSince
+	// The outage has had its alerts for the periods already passed
+	if period := resendPeriod(job.monitor); period > 0 {
+		job.resentPeriods = int(now.Sub(history.downSince) / period)
+	}
+}
+
+// checkDecision is what runCheck should do with a new heartbeat
+type checkDecision struct {
+	important  bool
+	notifyDown bool
+	notifyUp   bool
+	failures   int           // consecutive failures before this check
+	downtime   time.Duration // outage length, for a recovery
+}
+
+// evaluate updates the job's transition state for a new status checked at
+// the given time and decides whether the heartbeat is important and which
+// notifications to send
+func (job *monitorJob) evaluate(status int, at time.Time) checkDecision {
+	decision := checkDecision{failures: job.consecutiveFailures}
+
+	// A down alert held back during warm-up goes out with the next down
+	// check, or is dropped by an up check
+	deferred := job.deferredDown
+	if status == StatusUp || status == StatusDown {
+		job.deferredDown = false
+	}
+
+	switch status {
+	case StatusDown:
+		if job.consecutiveFailures == 0 {
+			job.downSince = at
+		}
+		job.consecutiveFailures++
+		if period := resendPeriod(job.monitor); period > 0 {
+			decision.notifyDown = shouldResendByTime(job.consecutiveFailures, at.Sub(job.downSince), period, &job.resentPeriods) || deferred
+		} else {
+			decision.notifyDown = shouldNotifyDown(job.consecutiveFailures, job.monitor.ResendInterval) || deferred
+		}
+		if job.warmingUp {
+			job.deferredDown = decision.notifyDown
+			decision.notifyDown = false
+		}
+	case StatusUp:
+		// Only a recovery from notified failures is "back up"; the first up
+		// after pending is not, nor one after a held back warm-up failure
+		if job.consecutiveFailures > 0 && !deferred {
+			decision.notifyUp = notifyRecovery(job.monitor)
+			decision.downtime = at.Sub(job.downSince)
+		}
+		job.consecutiveFailures = 0
+	case StatusMaintenance:
+		// Maintenance is neither a failure nor a recovery: it sends no alerts
+		// and leaves an outage's failure count and start as they were, so
+		// resends and the recovery pick up where they left off afterward
+	}
+
+	if status == StatusUp || status == StatusDown {
+		job.warmingUp = false
+	}
+
+	// Pending isn't a settled state, so lastStatus only tracks up/down/maintenance.
+	// That makes the first real status after pending a transition. Without
+	// maintenance_important, maintenance is skipped the same way.
+	if status != StatusPending && (status != StatusMaintenance || maintenanceImportant(job.monitor)) {
+		decision.important = status != job.lastStatus
+		job.lastStatus = status
+	}
+
+	return decision
+}
+
+// recordFailure keeps the latest failure messages of the current outage.
+// A recovery clears them; pending and maintenance leave them as they are.
+func (job *monitorJob) recordFailure(status int, message string) {
+	switch status {
+	case StatusDown:
+		job.recentFailures = append(job.recentFailures, message)
+		if len(job.recentFailures) > recentFailureWindow {
+			job.recentFailures = job.recentFailures[len(job.recentFailures)-recentFailureWindow:]
+		}
+	case StatusUp:
+		job.recentFailures = nil
+	}
+}
+
+// downMessage is the body of a down notification: the latest message, and
+// when the outage has failed in other ways too, a summary of its recent errors
+func (job *monitorJob) downMessage(message string) string {
+	summary, distinct := summarizeFailures(job.recentFailures, maxFailureSamples)
+	if distinct < 2 {
+		return message
+	}
+	return message + "\n\nRecent errors: " + summary
 }
 
-// saveHeartbeat saves a heartbeat to the database
-func (job *monitorJob) saveHeartbeat(heartbeat *Heartbeat) error {
+// summarizeFailures counts each distinct message, e.g. "connection refused
+// (x3), timeout (x1)", most frequent first and ties in order of first
+// appearance. At most maxSamples are listed; it also returns how many
+// distinct messages there were.
+func summarizeFailures(messages []string, maxSamples int) (string, int) {
+	counts := make(map[string]int)
+	var distinct []string
+	for _, message := range messages {
+		if counts[message] == 0 {
+			distinct = append(distinct, message)
+		}
+		counts[message]++
+	}
+	sort.SliceStable(distinct, func(i, j int) bool {
+		return counts[distinct[i]] > counts[distinct[j]]
+	})
+
+	samples := distinct
+	if len(samples) > maxSamples {
+		samples = samples[:maxSamples]
+	}
+	parts := make([]string, len(samples))
+	for i, message := range samples {
+		parts[i] = fmt.Sprintf("%s (x%d)", message, counts[message])
+	}
+	summary := strings.Join(parts, ", ")
+	if more := len(distinct) - len(samples); more > 0 {
+		summary += fmt.Sprintf(" and %d more", more)
+	}
+	return summary, len(distinct)
+}
+
+// maintenanceImportant reports whether entering and leaving maintenance are
+// important heartbeats. The "maintenance_important" config turns this off;
+// it is on by default.
+func maintenanceImportant(monitor *Monitor) bool {
+	enabled, ok := monitor.Config["maintenance_important"].(bool)
+	return !ok || enabled
+}
+
+// notifyRecovery reports whether a monitor sends notifications when it comes
+// back up. The "notify_recovery" config turns them off; they are on by default.
+func notifyRecovery(monitor *Monitor) bool {
+	enabled, ok := monitor.Config["notify_recovery"].(bool)
+	return !ok || enabled
+}
+
+// shouldNotifyDown applies resend_interval to the consecutive failure count
+func shouldNotifyDown(consecutiveFailures, resendInterval int) bool {
+	if resendInterval == 0 {
+		// resend_interval=0 means notify only once per downtime period (never resend)
+		return consecutiveFailures == 1
+	}
+
+	// Ensure minimum of 1 for non-zero values
+	if resendInterval < 1 {
+		resendInterval = 1
+	}
+
+	if consecutiveFailures == resendInterval {
+		// First notification after reaching threshold
+		return true
+	}
+
+	// After threshold is reached, resend every resend_interval failures
+	return consecutiveFailures > resendInterval && (consecutiveFailures-resendInterval)%resendInterval == 0
+}
+
+// insertHeartbeat saves a heartbeat to the database, and records it in the
+// monitor's status page cache in the same transaction
+func (e *Executor) insertHeartbeat(heartbeat *Heartbeat) error {
 	query := `
-		INSERT INTO heartbeats (monitor_id, status, ping, important, message, time)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO heartbeats (monitor_id, status, ping, important, message, time, remote_addr, checks)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
 	`
 
-	err := job.executor.db.Exec(query,
-		heartbeat.MonitorID,
-		heartbeat.Status,
-		heartbeat.Ping,
-		heartbeat.Important,
-		heartbeat.Message,
-		heartbeat.Time,
-	).Error
+	checks := heartbeat.Checks
+	if checks < 1 {
+		checks = 1
+	}
+
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Raw(query,
+			heartbeat.MonitorID,
+			heartbeat.Status,
+			heartbeat.Ping,
+			heartbeat.Important,
+			heartbeat.Message,
+			heartbeat.Time,
+			heartbeat.RemoteAddr,
+			checks,
+		).Scan(&heartbeat.ID).Error
+		if err != nil {
+			return err
+		}
+		return updateStatusCache(tx, heartbeat)
+	})
+}
+
+// updateHeartbeat folds a check into the stored heartbeat with the same ID:
+// the row takes the check's time, ping and message and its checks count. The
+// check is recorded in the status page cache like an inserted one.
+func (e *Executor) updateHeartbeat(heartbeat *Heartbeat) error {
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`
+			UPDATE heartbeats
+			SET ping = ?, message = ?, time = ?, remote_addr = ?, checks = ?
+			WHERE id = ? AND monitor_id = ?
+		`,
+			heartbeat.Ping,
+			heartbeat.Message,
+			heartbeat.Time,
+			heartbeat.RemoteAddr,
+			heartbeat.Checks,
+			heartbeat.ID,
+			heartbeat.MonitorID,
+		).Error
+		if err != nil {
+			return err
+		}
+		return updateStatusCache(tx, heartbeat)
+	})
+}
+
+// updateStatusCache adds a heartbeat to its monitor's status page cache. The
+// row is locked so concurrent checks of a monitor don't lose updates.
+func updateStatusCache(tx *gorm.DB, heartbeat *Heartbeat) error {
+	var interval int
+	if err := tx.Table("monitors").Select("interval").
+		Where("id = ?", heartbeat.MonitorID).Scan(&interval).Error; err != nil {
+		return err
+	}
+
+	var cache models.MonitorStatusCache
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("monitor_id = ?", heartbeat.MonitorID).
+		Limit(1).Find(&cache).Error
+	if err != nil {
+		return err
+	}
 
-	return err
+	cache.MonitorID = heartbeat.MonitorID
+	cache.Record(heartbeat.Status, heartbeat.Time, models.StatusBucketSeconds(interval))
+	return tx.Clauses(clause.OnConflict{UpdateAll: true}).Create(&cache).Error
 }
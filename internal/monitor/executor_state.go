@@ -0,0 +1,56 @@
+package monitor
+
+import (
+	"sort"
+	"time"
+)
+
+// JobState is a running monitor job as the executor sees it, for comparing
+// against the monitors marked active in the database
+type JobState struct {
+	MonitorID           int        `json:"monitor_id"`
+	Name                string     `json:"name"`
+	Type                string     `json:"type"`
+	Interval            int        `json:"interval"`
+	NextCheck           time.Time  `json:"next_check"` // estimate: the last scheduled check plus the interval
+	LastCheck           *time.Time `json:"last_check"` // nil before the first check finishes
+	LastStatus          int        `json:"last_status"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Flapping            bool       `json:"flapping"`
+}
+
+// Jobs returns the state of every running monitor job, by monitor ID
+func (e *Executor) Jobs() []JobState {
+	e.mu.RLock()
+	jobs := make([]*monitorJob, 0, len(e.monitors))
+	for _, job := range e.monitors {
+		jobs = append(jobs, job)
+	}
+	e.mu.RUnlock()
+
+	states := make([]JobState, 0, len(jobs))
+	for _, job := range jobs {
+		job.stateMu.Lock()
+		state := JobState{
+			MonitorID:           job.monitor.ID,
+			Name:                job.monitor.Name,
+			Type:                job.monitor.Type,
+			Interval:            job.monitor.Interval,
+			NextCheck:           job.nextCheck,
+			LastStatus:          job.lastStatus,
+			ConsecutiveFailures: job.consecutiveFailures,
+			Flapping:            job.flap.flapping,
+		}
+		if !job.lastCheck.IsZero() {
+			lastCheck := job.lastCheck
+			state.LastCheck = &lastCheck
+		}
+		job.stateMu.Unlock()
+		states = append(states, state)
+	}
+
+	sort.Slice(states, func(i, j int) bool {
+		return states[i].MonitorID < states[j].MonitorID
+	})
+	return states
+}
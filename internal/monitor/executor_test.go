@@ -0,0 +1,386 @@
+package monitor
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestJob(lastStatus, resendInterval int) *monitorJob {
+	return &monitorJob{
+		monitor:    &Monitor{ID: 1, Name: "test", ResendInterval: resendInterval},
+		lastStatus: lastStatus,
+	}
+}
+
+func TestEvaluateFirstTransitionFromPending(t *testing.T) {
+	tests := []struct {
+		name           string
+		status         int
+		wantImportant  bool
+		wantNotifyDown bool
+		wantNotifyUp   bool
+	}{
+		{"pending to up", StatusUp, true, false, false},
+		{"pending to down", StatusDown, true, true, false},
+		{"pending to pending", StatusPending, false, false, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := newTestJob(StatusPending, 0)
+			d := job.evaluate(tt.status, time.Now())
+			if d.important != tt.wantImportant {
+				t.Errorf("important = %v, want %v", d.important, tt.wantImportant)
+			}
+			if d.notifyDown != tt.wantNotifyDown {
+				t.Errorf("notifyDown = %v, want %v", d.notifyDown, tt.wantNotifyDown)
+			}
+			if d.notifyUp != tt.wantNotifyUp {
+				t.Errorf("notifyUp = %v, want %v", d.notifyUp, tt.wantNotifyUp)
+			}
+		})
+	}
+}
+
+func TestEvaluateSequence(t *testing.T) {
+	job := newTestJob(StatusPending, 0)
+
+	steps := []struct {
+		status         int
+		wantImportant  bool
+		wantNotifyDown bool
+		wantNotifyUp   bool
+	}{
+		{StatusUp, true, false, false},  // first real status
+		{StatusUp, false, false, false}, // steady
+		{StatusPending, false, false, false},
+		{StatusUp, false, false, false},   // pending blip doesn't count as a change
+		{StatusDown, true, true, false},   // real transition
+		{StatusDown, false, false, false}, // resend_interval=0 notifies once
+		{StatusUp, true, false, true},     // recovery
+	}
+
+	for i, step := range steps {
+		d := job.evaluate(step.status, time.Now())
+		if d.important != step.wantImportant || d.notifyDown != step.wantNotifyDown || d.notifyUp != step.wantNotifyUp {
+			t.Errorf("step %d: got important=%v down=%v up=%v, want %v %v %v",
+				i, d.important, d.notifyDown, d.notifyUp,
+				step.wantImportant, step.wantNotifyDown, step.wantNotifyUp)
+		}
+	}
+}
+
+func TestEvaluateRecoveryDowntime(t *testing.T) {
+	job := newTestJob(StatusUp, 0)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	steps := []int{StatusUp, StatusDown, StatusPending, StatusDown, StatusDown}
+	for i, status := range steps {
+		job.evaluate(status, start.Add(time.Duration(i)*time.Minute))
+	}
+
+	// The outage counts from its first down check, one minute in
+	d := job.evaluate(StatusUp, start.Add(13*time.Minute))
+	if !d.notifyUp || d.downtime != 12*time.Minute {
+		t.Fatalf("recovery: notifyUp=%v downtime=%s, want a notification after 12m", d.notifyUp, d.downtime)
+	}
+
+	// A new outage starts counting again
+	job.evaluate(StatusDown, start.Add(20*time.Minute))
+	if d := job.evaluate(StatusUp, start.Add(23*time.Minute)); d.downtime != 3*time.Minute {
+		t.Errorf("second outage downtime = %s, want 3m", d.downtime)
+	}
+}
+
+func TestResumedOutageKeepsFailuresAndStart(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	restart := start.Add(25 * time.Minute)
+
+	// Three down checks before the restart; resend_interval=3 alerted on the first and third
+	job := newTestJob(StatusDown, 3)
+	job.resume(jobStart{lastStatus: StatusDown, failures: 3, downSince: start}, restart)
+
+	for i, want := range []bool{false, false, true} {
+		d := job.evaluate(StatusDown, restart.Add(time.Duration(i)*time.Minute))
+		if d.important || d.notifyDown != want {
+			t.Errorf("down check %d after restart: important=%v notifyDown=%v, want notifyDown=%v", i+1, d.important, d.notifyDown, want)
+		}
+	}
+	d := job.evaluate(StatusUp, restart.Add(5*time.Minute))
+	if !d.notifyUp || d.downtime != 30*time.Minute {
+		t.Errorf("recovery: notifyUp=%v downtime=%s, want a notification after 30m", d.notifyUp, d.downtime)
+	}
+}
+
+func TestResumedOutageKeepsResendPeriods(t *testing.T) {
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	job := newTestJob(StatusDown, 0)
+	job.monitor.Config = map[string]interface{}{"resend_interval_seconds": 600}
+	job.resume(jobStart{lastStatus: StatusDown, failures: 25, downSince: start}, start.Add(25*time.Minute))
+
+	// 20m was alerted for before the restart; the next resend is at 30m
+	if d := job.evaluate(StatusDown, start.Add(26*time.Minute)); d.notifyDown {
+		t.Error("resent an alert for a period already alerted before the restart")
+	}
+	if d := job.evaluate(StatusDown, start.Add(30*time.Minute)); !d.notifyDown {
+		t.Error("no resend once the outage passed 30m")
+	}
+}
+
+func TestEvaluateRecoveryNotificationDisabled(t *testing.T) {
+	job := newTestJob(StatusUp, 0)
+	job.monitor.Config = map[string]interface{}{"notify_recovery": false}
+
+	job.evaluate(StatusDown, time.Now())
+	d := job.evaluate(StatusUp, time.Now())
+	if d.notifyUp || !d.important {
+		t.Errorf("got notifyUp=%v important=%v, want an important heartbeat without notification", d.notifyUp, d.important)
+	}
+}
+
+func TestShouldNotifyDown(t *testing.T) {
+	tests := []struct {
+		failures int
+		resend   int
+		want     bool
+	}{
+		{1, 0, true},
+		{2, 0, false},
+		{1, 1, true},
+		{2, 1, true},
+		{1, 3, false},
+		{3, 3, true},
+		{4, 3, false},
+		{6, 3, true},
+	}
+
+	for _, tt := range tests {
+		if got := shouldNotifyDown(tt.failures, tt.resend); got != tt.want {
+			t.Errorf("shouldNotifyDown(%d, %d) = %v, want %v", tt.failures, tt.resend, got, tt.want)
+		}
+	}
+}
+
+func TestStartMonitorUnsupportedType(t *testing.T) {
+	var saved []*Heartbeat
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error {
+		saved = append(saved, heartbeat)
+		return nil
+	}
+
+	e.StartMonitor(&Monitor{ID: 7, Name: "legacy", Type: "no-such-type", Interval: 60})
+
+	if len(e.monitors) != 0 {
+		t.Errorf("a job was started for an unsupported monitor type")
+	}
+	if len(saved) != 1 {
+		t.Fatalf("saved %d heartbeats, want 1", len(saved))
+	}
+	hb := saved[0]
+	if hb.MonitorID != 7 || hb.Status != StatusDown || hb.Message != `Unsupported monitor type "no-such-type"` {
+		t.Errorf("heartbeat = %+v, want a DOWN unsupported type heartbeat for monitor 7", hb)
+	}
+}
+
+// evaluateStep is one check of a sequence and the counters expected after it
+type evaluateStep struct {
+	status        int
+	wantImportant bool
+	wantDown      bool
+	wantUp        bool
+	wantFailures  int
+}
+
+func runEvaluateSteps(t *testing.T, job *monitorJob, steps []evaluateStep) {
+	t.Helper()
+	for i, step := range steps {
+		d := job.evaluate(step.status, time.Now())
+		if d.important != step.wantImportant || d.notifyDown != step.wantDown || d.notifyUp != step.wantUp {
+			t.Errorf("step %d (status %d): got important=%v down=%v up=%v, want %v %v %v",
+				i, step.status, d.important, d.notifyDown, d.notifyUp,
+				step.wantImportant, step.wantDown, step.wantUp)
+		}
+		if job.consecutiveFailures != step.wantFailures {
+			t.Errorf("step %d (status %d): consecutiveFailures = %d, want %d",
+				i, step.status, job.consecutiveFailures, step.wantFailures)
+		}
+	}
+}
+
+func TestEvaluateUpMaintenanceUp(t *testing.T) {
+	runEvaluateSteps(t, newTestJob(StatusUp, 0), []evaluateStep{
+		{StatusMaintenance, true, false, false, 0},  // entering maintenance
+		{StatusMaintenance, false, false, false, 0}, // steady
+		{StatusUp, true, false, false, 0},           // leaving it is no recovery
+		{StatusUp, false, false, false, 0},
+	})
+}
+
+func TestEvaluateUpMaintenanceDown(t *testing.T) {
+	runEvaluateSteps(t, newTestJob(StatusUp, 2), []evaluateStep{
+		{StatusMaintenance, true, false, false, 0},
+		{StatusMaintenance, false, false, false, 0},
+		{StatusDown, true, false, false, 1}, // a fresh outage, counted from 1
+		{StatusDown, false, true, false, 2}, // resend_interval=2 reached
+		{StatusUp, true, false, true, 0},
+	})
+}
+
+func TestEvaluateMaintenanceDuringOutage(t *testing.T) {
+	job := newTestJob(StatusUp, 2)
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// Maintenance checks are left out of the resend counter: the down
+	// checks around them count 1, 2, 3, 4 and resend on 2 and 4
+	steps := []struct {
+		status    int
+		wantDown  bool
+		wantCount int
+	}{
+		{StatusDown, false, 1},
+		{StatusDown, true, 2},
+		{StatusMaintenance, false, 2},
+		{StatusMaintenance, false, 2},
+		{StatusDown, false, 3},
+		{StatusDown, true, 4},
+	}
+	for i, step := range steps {
+		d := job.evaluate(step.status, start.Add(time.Duration(i)*time.Minute))
+		if d.notifyDown != step.wantDown || d.notifyUp || job.consecutiveFailures != step.wantCount {
+			t.Errorf("step %d: notifyDown=%v notifyUp=%v failures=%d, want %v false %d",
+				i, d.notifyDown, d.notifyUp, job.consecutiveFailures, step.wantDown, step.wantCount)
+		}
+	}
+
+	// The recovery reports the whole outage, maintenance included
+	d := job.evaluate(StatusUp, start.Add(10*time.Minute))
+	if !d.notifyUp || d.downtime != 10*time.Minute {
+		t.Errorf("recovery: notifyUp=%v downtime=%s, want a notification after 10m", d.notifyUp, d.downtime)
+	}
+}
+
+func TestEvaluateMaintenanceImportanceDisabled(t *testing.T) {
+	job := newTestJob(StatusUp, 0)
+	job.monitor.Config = map[string]interface{}{"maintenance_important": false}
+
+	runEvaluateSteps(t, job, []evaluateStep{
+		{StatusMaintenance, false, false, false, 0},
+		{StatusUp, false, false, false, 0}, // compared with the status before maintenance
+		{StatusMaintenance, false, false, false, 0},
+		{StatusDown, true, true, false, 1}, // still a real transition
+	})
+}
+
+func TestDownMessageSummarizesRecentErrors(t *testing.T) {
+	job := newTestJob(StatusUp, 1)
+	for _, message := range []string{"connection refused", "timeout", "connection refused", "connection refused"} {
+		job.recordFailure(StatusDown, message)
+	}
+	job.recordFailure(StatusMaintenance, "maintenance")
+
+	want := "connection refused\n\nRecent errors: connection refused (x3), timeout (x1)"
+	if got := job.downMessage("connection refused"); got != want {
+		t.Errorf("downMessage = %q, want %q", got, want)
+	}
+
+	// Recovery starts the next outage afresh
+	job.recordFailure(StatusUp, "HTTP 200")
+	job.recordFailure(StatusDown, "timeout")
+	if got := job.downMessage("timeout"); got != "timeout" {
+		t.Errorf("downMessage after recovery = %q, want only the latest message", got)
+	}
+}
+
+func TestSummarizeFailuresBounded(t *testing.T) {
+	job := newTestJob(StatusUp, 1)
+	for i := 0; i < recentFailureWindow+5; i++ {
+		job.recordFailure(StatusDown, []string{"a", "b", "b", "c", "d", "d", "d"}[i%7])
+	}
+	if len(job.recentFailures) != recentFailureWindow {
+		t.Errorf("kept %d failures, want %d", len(job.recentFailures), recentFailureWindow)
+	}
+
+	summary, distinct := summarizeFailures([]string{"a", "b", "b", "c", "d", "d", "d"}, 3)
+	if want := "d (x3), b (x2), a (x1) and 1 more"; summary != want || distinct != 4 {
+		t.Errorf("summarizeFailures = %q, %d; want %q, 4", summary, distinct, want)
+	}
+}
+
+func TestEvaluateWarmUpDefersFirstFailure(t *testing.T) {
+	job := newTestJob(StatusPending, 1)
+	job.warmingUp = true
+	runEvaluateSteps(t, job, []evaluateStep{
+		{StatusDown, true, false, false, 1}, // stored as down, alert held back
+		{StatusDown, false, true, false, 2}, // confirmed
+		{StatusDown, false, true, false, 3},
+	})
+
+	// resend_interval 0 alerts once per outage: the held alert still goes out
+	job = newTestJob(StatusPending, 0)
+	job.warmingUp = true
+	runEvaluateSteps(t, job, []evaluateStep{
+		{StatusDown, true, false, false, 1},
+		{StatusDown, false, true, false, 2},
+		{StatusDown, false, false, false, 3},
+	})
+}
+
+func TestEvaluateWarmUpFailureThenUp(t *testing.T) {
+	job := newTestJob(StatusPending, 1)
+	job.warmingUp = true
+	runEvaluateSteps(t, job, []evaluateStep{
+		{StatusDown, true, false, false, 1},
+		{StatusUp, true, false, false, 0},  // no recovery for an alert never sent
+		{StatusDown, true, true, false, 1}, // warmed up: alerts as usual
+	})
+}
+
+func TestEvaluateWarmUpEndsWithFirstResult(t *testing.T) {
+	job := newTestJob(StatusPending, 1)
+	job.warmingUp = true
+	runEvaluateSteps(t, job, []evaluateStep{
+		{StatusPending, false, false, false, 0}, // not a result yet
+		{StatusUp, true, false, false, 0},
+		{StatusDown, true, true, false, 1},
+	})
+}
+
+// upMonitorType reports every check up
+type upMonitorType struct{}
+
+func (upMonitorType) Name() string { return "executor-state-test" }
+
+func (upMonitorType) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, error) {
+	return &Heartbeat{MonitorID: monitor.ID, Status: StatusUp, Message: "ok", Time: time.Now()}, nil
+}
+
+func (upMonitorType) Validate(monitor *Monitor) error { return nil }
+
+func TestJobsListsRunningMonitors(t *testing.T) {
+	RegisterMonitorType(upMonitorType{})
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error { return nil }
+	e.jobHistory = func(monitorID int) jobStart { return jobStart{lastStatus: StatusDown} }
+
+	before := time.Now()
+	e.StartMonitor(&Monitor{ID: 3, Name: "api", Type: "executor-state-test", Interval: 60, Timeout: 5})
+	e.StartMonitor(&Monitor{ID: 5, Name: "web", Type: "executor-state-test", Interval: 30, Timeout: 5})
+	defer e.Stop()
+
+	jobs := e.Jobs()
+	if len(jobs) != 2 || jobs[0].MonitorID != 3 || jobs[1].MonitorID != 5 {
+		t.Fatalf("jobs = %+v, want monitors 3 and 5", jobs)
+	}
+	api := jobs[0]
+	if api.Name != "api" || api.Interval != 60 || api.NextCheck.Before(before.Add(60*time.Second)) {
+		t.Errorf("job = %+v, want api's next check an interval from now", api)
+	}
+
+	e.StopMonitor(3)
+	jobs = e.Jobs()
+	if len(jobs) != 1 || jobs[0].MonitorID != 5 {
+		t.Errorf("after stopping monitor 3, jobs = %+v, want only monitor 5", jobs)
+	}
+}
@@ -0,0 +1,169 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/notification"
+)
+
+const (
+	// defaultFlapWindow is how far back status changes count toward
+	// flap_threshold unless flap_window is set
+	defaultFlapWindow = 10 * time.Minute
+
+	// maxFlapWindow caps flap_window at a day
+	maxFlapWindow = 24 * 60 * 60
+)
+
+// flapState tracks a monitor's recent changes between up and down
+type flapState struct {
+	changes  []time.Time // when the status changed, oldest first, within the window
+	last     int         // the latest up or down status
+	seen     bool        // whether last holds a status yet
+	flapping bool
+}
+
+// observe records a checked status and reports whether the monitor just
+// started or stopped flapping. It starts once threshold changes fall within
+// window and stops once a whole window passes without one. Maintenance and
+// pending checks neither count as changes nor end a run.
+func (f *flapState) observe(status int, at time.Time, threshold int, window time.Duration) (started, stopped bool) {
+	if status != StatusUp && status != StatusDown {
+		return false, false
+	}
+	if f.seen && status != f.last {
+		f.changes = append(f.changes, at)
+	}
+	f.last, f.seen = status, true
+
+	cutoff := at.Add(-window)
+	kept := 0
+	for kept < len(f.changes) && !f.changes[kept].After(cutoff) {
+		kept++
+	}
+	f.changes = f.changes[kept:]
+
+	switch {
+	case !f.flapping && len(f.changes) >= threshold:
+		f.flapping = true
+		return true, false
+	case f.flapping && len(f.changes) == 0:
+		f.flapping = false
+		return false, true
+	}
+	return false, false
+}
+
+// flapDetection returns the monitor's "flap_threshold", the status changes
+// within "flap_window" seconds that mark it flapping. A threshold of 0 turns
+// detection off.
+func flapDetection(monitor *Monitor) (threshold int, window time.Duration) {
+	switch v := monitor.Config["flap_threshold"].(type) {
+	case float64:
+		threshold = int(v)
+	case int:
+		threshold = v
+	}
+	window = defaultFlapWindow
+	switch v := monitor.Config["flap_window"].(type) {
+	case float64:
+		window = time.Duration(v) * time.Second
+	case int:
+		window = time.Duration(v) * time.Second
+	}
+	return threshold, window
+}
+
+// ValidateFlapDetection checks the optional flap_threshold config, a whole
+// number of at least 2, and flap_window, whole seconds up to a day
+func ValidateFlapDetection(monitor *Monitor) error {
+	threshold, set, err := wholeConfigNumber(monitor.Config, "flap_threshold")
+	if err != nil {
+		return err
+	}
+	if set && threshold < 2 {
+		return fmt.Errorf("flap_threshold must be a whole number of at least 2")
+	}
+
+	window, windowSet, err := wholeConfigNumber(monitor.Config, "flap_window")
+	if err != nil {
+		return err
+	}
+	if windowSet && !set {
+		return fmt.Errorf("flap_window requires flap_threshold")
+	}
+	if windowSet && (window < 1 || window > maxFlapWindow) {
+		return fmt.Errorf("flap_window must be between 1 and %d seconds", maxFlapWindow)
+	}
+	return nil
+}
+
+// wholeConfigNumber reads a whole number config value, reporting whether it
+// is set
+func wholeConfigNumber(config map[string]interface{}, key string) (int, bool, error) {
+	var n float64
+	switch v := config[key].(type) {
+	case nil:
+		return 0, false, nil
+	case float64:
+		n = v
+	case int:
+		n = float64(v)
+	default:
+		return 0, false, fmt.Errorf("%s must be a number", key)
+	}
+	if n != float64(int(n)) {
+		return 0, false, fmt.Errorf("%s must be a whole number", key)
+	}
+	return int(n), true, nil
+}
+
+// trackFlapping feeds a checked status to the job's flap detection, if the
+// monitor has it, and reports whether the monitor just started flapping.
+// Callers hold stateMu.
+func (job *monitorJob) trackFlapping(status int, at time.Time) bool {
+	threshold, window := flapDetection(job.monitor)
+	if threshold < 2 {
+		job.flap = flapState{}
+		return false
+	}
+
+	started, stopped := job.flap.observe(status, at, threshold, window)
+	if stopped {
+		log.Printf("Monitor %s (ID: %d) stopped flapping", job.monitor.Name, job.monitor.ID)
+	}
+	return started
+}
+
+// notifyFlapping sends the one alert of a monitor that started flapping
+func (e *Executor) notifyFlapping(job *monitorJob, changes int, window time.Duration) {
+	monitor := job.monitor
+	log.Printf("Monitor %s (ID: %d) is flapping: %d status changes in %s",
+		monitor.Name, monitor.ID, changes, notification.FormatDuration(window))
+
+	if e.dispatcher == nil {
+		return
+	}
+	message := fmt.Sprintf("Changed between up and down %d times in the last %s.", changes, notification.FormatDuration(window))
+	if err := e.dispatcher.NotifyMonitorFlapping(context.Background(), monitor.ID, monitor.Name, "", message); err != nil {
+		log.Printf("Failed to send flapping notification for monitor %d: %v", monitor.ID, err)
+	}
+}
+
+// Flapping reports whether the monitor is currently flapping. Monitors
+// without a running job or flap detection never are.
+func (e *Executor) Flapping(monitorID int) bool {
+	e.mu.RLock()
+	job, ok := e.monitors[monitorID]
+	e.mu.RUnlock()
+	if !ok {
+		return false
+	}
+
+	job.stateMu.Lock()
+	defer job.stateMu.Unlock()
+	return job.flap.flapping
+}
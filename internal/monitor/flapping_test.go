@@ -0,0 +1,103 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFlapStateAlternatingStatusesFlap(t *testing.T) {
+	var f flapState
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	statuses := []int{StatusUp, StatusDown, StatusUp, StatusDown, StatusUp, StatusDown}
+
+	startedAt := -1
+	for i, status := range statuses {
+		started, stopped := f.observe(status, start.Add(time.Duration(i)*time.Minute), 4, 10*time.Minute)
+		if stopped {
+			t.Fatalf("check %d: stopped flapping while still alternating", i)
+		}
+		if started {
+			if startedAt >= 0 {
+				t.Fatalf("check %d: started flapping again, first at check %d", i, startedAt)
+			}
+			startedAt = i
+		}
+	}
+
+	// The fourth change is the fifth check
+	if startedAt != 4 {
+		t.Errorf("started flapping at check %d, want 4", startedAt)
+	}
+	if !f.flapping {
+		t.Error("not flapping after five changes in six minutes")
+	}
+}
+
+func TestFlapStateStopsOnceStable(t *testing.T) {
+	var f flapState
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	for i, status := range []int{StatusUp, StatusDown, StatusUp, StatusDown} {
+		f.observe(status, at(i), 3, 10*time.Minute)
+	}
+	if !f.flapping {
+		t.Fatal("not flapping after three changes")
+	}
+
+	// Down since minute 3; still within the window of that change
+	if _, stopped := f.observe(StatusDown, at(12), 3, 10*time.Minute); stopped || !f.flapping {
+		t.Fatal("stopped flapping before a whole window passed without a change")
+	}
+	if _, stopped := f.observe(StatusDown, at(14), 3, 10*time.Minute); !stopped || f.flapping {
+		t.Fatal("still flapping after a whole window without a change")
+	}
+}
+
+func TestFlapStateSlowChangesDoNotFlap(t *testing.T) {
+	var f flapState
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// One change every 20 minutes never puts three within 10 minutes
+	for i, status := range []int{StatusUp, StatusDown, StatusUp, StatusDown, StatusUp} {
+		if started, _ := f.observe(status, start.Add(time.Duration(i)*20*time.Minute), 3, 10*time.Minute); started {
+			t.Fatalf("check %d: started flapping on slow changes", i)
+		}
+	}
+}
+
+func TestFlapStateIgnoresMaintenance(t *testing.T) {
+	var f flapState
+	start := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	for i, status := range []int{StatusUp, StatusMaintenance, StatusUp, StatusMaintenance, StatusUp} {
+		f.observe(status, start.Add(time.Duration(i)*time.Minute), 2, 10*time.Minute)
+	}
+	if len(f.changes) != 0 || f.flapping {
+		t.Errorf("maintenance counted as %d changes (flapping %v), want none", len(f.changes), f.flapping)
+	}
+}
+
+func TestValidateFlapDetection(t *testing.T) {
+	tests := []struct {
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{config: map[string]interface{}{}},
+		{config: map[string]interface{}{"flap_threshold": float64(5)}},
+		{config: map[string]interface{}{"flap_threshold": float64(5), "flap_window": float64(900)}},
+		{config: map[string]interface{}{"flap_threshold": float64(1)}, wantErr: true},
+		{config: map[string]interface{}{"flap_threshold": 2.5}, wantErr: true},
+		{config: map[string]interface{}{"flap_threshold": "5"}, wantErr: true},
+		{config: map[string]interface{}{"flap_window": float64(900)}, wantErr: true},
+		{config: map[string]interface{}{"flap_threshold": float64(5), "flap_window": float64(0)}, wantErr: true},
+		{config: map[string]interface{}{"flap_threshold": float64(5), "flap_window": float64(maxFlapWindow + 1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := ValidateFlapDetection(&Monitor{Config: tt.config})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateFlapDetection(%v) = %v, want error %v", tt.config, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,244 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"strings"
+	"time"
+)
+
+// maxFlowSteps caps the number of requests in a flow
+const maxFlowSteps = 10
+
+// maxFlowStepBody caps how much of a step's response is searched for its
+// keyword
+const maxFlowStepBody = 1 << 20
+
+// flowStep is one request of a flow monitor:
+//
+//	{"name": "login", "method": "POST", "url": "https://example.com/login",
+//	 "headers": {"Content-Type": "application/x-www-form-urlencoded"},
+//	 "body": "user=probe&password=...", "expected_status": 302,
+//	 "keyword": "Welcome"}
+//
+// expected_status defaults to 200 and keyword, when set, must appear in the
+// response body.
+type flowStep struct {
+	name           string
+	method         string
+	url            string
+	headers        map[string]string
+	body           string
+	expectedStatus int
+	keyword        string
+}
+
+// label names a step in check messages by its number and name
+func (s *flowStep) label(index int) string {
+	if s.name == "" {
+		return fmt.Sprintf("Step %d", index+1)
+	}
+	return fmt.Sprintf("Step %d (%s)", index+1, s.name)
+}
+
+// FlowMonitor runs a user flow as an ordered list of HTTP requests sharing
+// a cookie jar, e.g. load the home page, log in and open the dashboard. The
+// monitor is up when every step passes; otherwise the message names the
+// first step that failed. Redirects are not followed, so a step can expect
+// the 302 of a login form.
+type FlowMonitor struct{}
+
+func init() {
+	RegisterMonitorType(&FlowMonitor{})
+}
+
+func (f *FlowMonitor) Name() string {
+	return "flow"
+}
+
+func (f *FlowMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, error) {
+	heartbeat := &Heartbeat{
+		MonitorID: monitor.ID,
+		Time:      time.Now(),
+		Status:    StatusDown,
+	}
+
+	steps, err := parseFlowSteps(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	// The timeout bounds the whole flow, not each step
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	jar, err := cookiejar.New(nil)
+	if err != nil {
+		heartbeat.Message = fmt.Sprintf("Failed to create cookie jar: %v", err)
+		return heartbeat, nil
+	}
+	transport := newHTTPTransport(monitor, &tls.Config{}, nil, true)
+	defer transport.CloseIdleConnections()
+	client := &http.Client{
+		Transport: transport,
+		Jar:       jar,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	start := time.Now()
+	for i := range steps {
+		step := &steps[i]
+		stepStart := time.Now()
+		err := step.run(ctx, client)
+		latency := time.Since(stepStart).Milliseconds()
+		heartbeat.Ping = int(time.Since(start).Milliseconds())
+		if err != nil {
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				err = fmt.Errorf("flow timed out after %ds", monitor.Timeout)
+			}
+			heartbeat.Message = fmt.Sprintf("%s failed after %dms: %v", step.label(i), latency, err)
+			return heartbeat, nil
+		}
+	}
+
+	heartbeat.Status = StatusUp
+	heartbeat.Message = fmt.Sprintf("%d steps passed - %dms", len(steps), heartbeat.Ping)
+	return heartbeat, nil
+}
+
+// run makes a step's request and checks its response
+func (s *flowStep) run(ctx context.Context, client *http.Client) error {
+	if err := revalidateURL(s.url); err != nil {
+		return err
+	}
+
+	var reqBody io.Reader
+	if s.body != "" {
+		reqBody = strings.NewReader(s.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, s.method, s.url, reqBody)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	for key, value := range s.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != s.expectedStatus {
+		return fmt.Errorf("HTTP %d, expected %d", resp.StatusCode, s.expectedStatus)
+	}
+	if s.keyword == "" {
+		return nil
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFlowStepBody))
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %v", err)
+	}
+	if !strings.Contains(string(body), s.keyword) {
+		return fmt.Errorf("keyword %q not found", s.keyword)
+	}
+	return nil
+}
+
+// parseFlowSteps reads the "steps" config
+func parseFlowSteps(config map[string]interface{}) ([]flowStep, error) {
+	raw, ok := config["steps"].([]interface{})
+	if !ok || len(raw) == 0 {
+		return nil, fmt.Errorf("steps must be a non-empty list")
+	}
+	if len(raw) > maxFlowSteps {
+		return nil, fmt.Errorf("a flow has at most %d steps", maxFlowSteps)
+	}
+
+	steps := make([]flowStep, 0, len(raw))
+	for i, item := range raw {
+		cfg, ok := item.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("steps[%d] must be an object", i)
+		}
+		step := flowStep{method: http.MethodGet, headers: make(map[string]string), expectedStatus: http.StatusOK}
+
+		if name, ok := cfg["name"]; ok && name != nil {
+			if step.name, ok = name.(string); !ok {
+				return nil, fmt.Errorf("steps[%d].name must be a string", i)
+			}
+		}
+		step.url, _ = cfg["url"].(string)
+		if !strings.HasPrefix(step.url, "http://") && !strings.HasPrefix(step.url, "https://") {
+			return nil, fmt.Errorf("steps[%d].url must start with http:// or https://", i)
+		}
+		if method, _ := cfg["method"].(string); method != "" {
+			step.method = strings.ToUpper(method)
+		}
+		switch headers := cfg["headers"].(type) {
+		case nil:
+		case map[string]interface{}:
+			for k, v := range headers {
+				value, ok := v.(string)
+				if !ok {
+					return nil, fmt.Errorf("steps[%d].headers.%s must be a string", i, k)
+				}
+				step.headers[k] = value
+			}
+		default:
+			return nil, fmt.Errorf("steps[%d].headers must be an object", i)
+		}
+		if body, ok := cfg["body"]; ok && body != nil {
+			if step.body, ok = body.(string); !ok {
+				return nil, fmt.Errorf("steps[%d].body must be a string", i)
+			}
+		}
+		if status, ok := cfg["expected_status"]; ok && status != nil {
+			code, ok := status.(float64)
+			if !ok || code != float64(int(code)) || code < 100 || code > 599 {
+				return nil, fmt.Errorf("steps[%d].expected_status must be an HTTP status code", i)
+			}
+			step.expectedStatus = int(code)
+		}
+		if keyword, ok := cfg["keyword"]; ok && keyword != nil {
+			if step.keyword, ok = keyword.(string); !ok {
+				return nil, fmt.Errorf("steps[%d].keyword must be a string", i)
+			}
+		}
+		steps = append(steps, step)
+	}
+	return steps, nil
+}
+
+func (f *FlowMonitor) Validate(monitor *Monitor) error {
+	steps, err := parseFlowSteps(monitor.Config)
+	if err != nil {
+		return err
+	}
+
+	ssrfProtection := GetConfig().SSRFProtection()
+	for i, step := range steps {
+		if err := ssrfProtection.ValidateURL(step.url); err != nil {
+			return fmt.Errorf("steps[%d].url validation failed: %w", i, err)
+		}
+	}
+
+	if err := validateSourceIPConfig(monitor); err != nil {
+		return err
+	}
+
+	return nil
+}
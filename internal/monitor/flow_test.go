@@ -0,0 +1,135 @@
+package monitor
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newFlowServer serves a home page, a login form setting a session cookie
+// and redirecting, and a dashboard that requires the cookie
+func newFlowServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "Home")
+	})
+	mux.HandleFunc("/login", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if r.Method != http.MethodPost || string(body) != "user=probe" {
+			http.Error(w, "bad login", http.StatusUnauthorized)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "s3cret", Path: "/"})
+		http.Redirect(w, r, "/dashboard", http.StatusFound)
+	})
+	mux.HandleFunc("/dashboard", func(w http.ResponseWriter, r *http.Request) {
+		if cookie, err := r.Cookie("session"); err != nil || cookie.Value != "s3cret" {
+			http.Error(w, "not logged in", http.StatusForbidden)
+			return
+		}
+		io.WriteString(w, "Welcome back")
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func flowMonitor(steps ...map[string]interface{}) *Monitor {
+	raw := make([]interface{}, len(steps))
+	for i, step := range steps {
+		raw[i] = step
+	}
+	return &Monitor{ID: 1, Type: "flow", Timeout: 5, Config: map[string]interface{}{"steps": raw}}
+}
+
+func loginFlow(serverURL, loginBody string) *Monitor {
+	return flowMonitor(
+		map[string]interface{}{"name": "home", "url": serverURL + "/", "keyword": "Home"},
+		map[string]interface{}{"name": "login", "method": "post", "url": serverURL + "/login", "body": loginBody, "expected_status": float64(302)},
+		map[string]interface{}{"name": "dashboard", "url": serverURL + "/dashboard", "keyword": "Welcome"},
+	)
+}
+
+func TestFlowMonitorSharesCookies(t *testing.T) {
+	SetConfig(&MonitorConfig{AllowPrivateIPs: true})
+	defer SetConfig(nil)
+	server := newFlowServer(t)
+
+	hb, err := (&FlowMonitor{}).Check(context.Background(), loginFlow(server.URL, "user=probe"))
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if hb.Status != StatusUp {
+		t.Fatalf("status = %d (%s), want up", hb.Status, hb.Message)
+	}
+	if !strings.HasPrefix(hb.Message, "3 steps passed") {
+		t.Errorf("message = %q", hb.Message)
+	}
+}
+
+func TestFlowMonitorReportsFailedStep(t *testing.T) {
+	SetConfig(&MonitorConfig{AllowPrivateIPs: true})
+	defer SetConfig(nil)
+	server := newFlowServer(t)
+
+	hb, err := (&FlowMonitor{}).Check(context.Background(), loginFlow(server.URL, "user=intruder"))
+	if err != nil {
+		t.Fatalf("Check returned error: %v", err)
+	}
+	if hb.Status != StatusDown {
+		t.Fatalf("status = %d, want down", hb.Status)
+	}
+	if !strings.HasPrefix(hb.Message, "Step 2 (login) failed after ") || !strings.HasSuffix(hb.Message, "ms: HTTP 401, expected 302") {
+		t.Errorf("message = %q, want step 2 failure with its latency", hb.Message)
+	}
+}
+
+func TestFlowMonitorTimeoutCoversWholeFlow(t *testing.T) {
+	SetConfig(&MonitorConfig{AllowPrivateIPs: true})
+	defer SetConfig(nil)
+	block := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-block
+	}))
+	// Release the handler before Close waits for it
+	defer server.Close()
+	defer close(block)
+
+	m := flowMonitor(map[string]interface{}{"url": server.URL})
+	m.Timeout = 1
+	hb, _ := (&FlowMonitor{}).Check(context.Background(), m)
+	if hb.Status != StatusDown || !strings.Contains(hb.Message, "Step 1 failed after") || !strings.Contains(hb.Message, "flow timed out after 1s") {
+		t.Errorf("got status %d message %q, want a timeout of step 1", hb.Status, hb.Message)
+	}
+}
+
+func TestFlowMonitorValidate(t *testing.T) {
+	SetConfig(&MonitorConfig{})
+	defer SetConfig(nil)
+
+	tests := []struct {
+		name    string
+		monitor *Monitor
+		wantErr string
+	}{
+		{"no steps", &Monitor{Config: map[string]interface{}{}}, "steps must be a non-empty list"},
+		{"bad scheme", flowMonitor(map[string]interface{}{"url": "ftp://example.com"}), "steps[0].url must start with"},
+		{"bad status", flowMonitor(map[string]interface{}{"url": "https://example.com", "expected_status": float64(42)}), "steps[0].expected_status"},
+		{"private step", flowMonitor(
+			map[string]interface{}{"url": "https://93.184.216.34/"},
+			map[string]interface{}{"url": "http://127.0.0.1/admin"},
+		), "steps[1].url validation failed"},
+	}
+	for _, tt := range tests {
+		err := (&FlowMonitor{}).Validate(tt.monitor)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: Validate error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
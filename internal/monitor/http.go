@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"errors"
 	"fmt"
 	"io"
 	"net"
 	"net/http"
+	"net/http/httptrace"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
@@ -17,6 +20,7 @@ import (
 // certLoader may be nil; if nil, mTLS is unavailable.
 type HTTPMonitor struct {
 	certLoader CertLoader
+	stability  stabilityTracker // rolling windows of the stability check
 }
 
 // NewHTTPMonitor creates an HTTPMonitor with the given CertLoader.
@@ -41,11 +45,113 @@ func (h *HTTPMonitor) Validate(monitor *Monitor) error {
 
 	// SSRF Protection - validate URL to prevent access to private IPs and metadata endpoints
 	cfg := GetConfig()
-	ssrfProtection := NewSSRFProtection(cfg.AllowPrivateIPs, cfg.AllowMetadataEndpoints)
+	ssrfProtection := cfg.SSRFProtection()
 	if err := ssrfProtection.ValidateURL(monitor.URL); err != nil {
 		return fmt.Errorf("URL validation failed: %w", err)
 	}
 
+	if err := validateProxyConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateSourceIPConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateHTTP3Config(monitor); err != nil {
+		return err
+	}
+
+	if err := validateMinTLSVersionConfig(monitor); err != nil {
+		return err
+	}
+
+	if _, err := parseExpectedALPN(monitor); err != nil {
+		return err
+	}
+
+	if err := validateCACertConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateTLSServerNameConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateAuthConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateHTTPPreflight(monitor, h.getConfigMap(monitor, "headers")); err != nil {
+		return err
+	}
+
+	if raw, ok := monitor.Config["condition"]; ok && raw != nil {
+		condition, ok := raw.(string)
+		if !ok {
+			return fmt.Errorf("condition must be a string")
+		}
+		if condition != "" {
+			if _, err := CompileCondition(condition, httpConditionEnv{}); err != nil {
+				return fmt.Errorf("invalid condition: %w", err)
+			}
+		}
+	}
+
+	keywords, err := parseKeywordMatch(monitor.Config)
+	if err != nil {
+		return err
+	}
+	// A condition replaces the keyword checks, so they would be ignored
+	if (keywords != nil || h.getConfigString(monitor, "keyword", "") != "") && h.getConfigString(monitor, "condition", "") != "" {
+		return fmt.Errorf("keyword and keywords cannot be combined with condition, match the body in the condition instead")
+	}
+
+	redirect, err := parseRedirectAssertion(monitor.Config)
+	if err != nil {
+		return err
+	}
+	// Followed redirects never reach the assertion
+	if redirect != nil && h.getConfigBool(monitor, "follow_redirects", true) {
+		return fmt.Errorf("expected_redirect requires follow_redirects to be false")
+	}
+	if redirect != nil && h.getConfigString(monitor, "condition", "") != "" {
+		return fmt.Errorf("expected_redirect cannot be combined with condition")
+	}
+
+	upHeader, err := parseHealthHeader(monitor.Config)
+	if err != nil {
+		return err
+	}
+	if upHeader != nil && h.getConfigString(monitor, "condition", "") != "" {
+		return fmt.Errorf("up_header cannot be combined with condition")
+	}
+
+	stability, err := parseHTTPStability(monitor.Config)
+	if err != nil {
+		return err
+	}
+	if stability != nil && h.getConfigString(monitor, "condition", "") != "" {
+		return fmt.Errorf("stability cannot be combined with condition")
+	}
+
+	schema, err := parseJSONSchema(monitor.Config)
+	if err != nil {
+		return err
+	}
+	if schema != nil && h.getConfigString(monitor, "condition", "") != "" {
+		return fmt.Errorf("json_schema cannot be combined with condition")
+	}
+
+	valueRange, err := parseJSONRange(monitor.Config)
+	if err != nil {
+		return err
+	}
+	// A condition replaces the standard checks, so the range would be ignored
+	if valueRange != nil && h.getConfigString(monitor, "condition", "") != "" {
+		return fmt.Errorf("json_path cannot be combined with condition")
+	}
+
 	if monitor.Timeout <= 0 {
 		monitor.Timeout = 30
 	}
@@ -74,6 +180,48 @@ func (h *HTTPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat,
 	invertKeyword := h.getConfigBool(monitor, "invert_keyword", false)
 	ignoreTLS := h.getConfigBool(monitor, "ignore_tls", false)
 	followRedirects := h.getConfigBool(monitor, "follow_redirects", true)
+	decodeBody := h.getConfigBool(monitor, "decode_body", true)
+	useHTTP3 := h.getConfigBool(monitor, "http3", false)
+	minTLSVersion, err := parseTLSVersion(h.getConfigString(monitor, "min_tls_version", ""))
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	keywords, err := parseKeywordMatch(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	valueRange, err := parseJSONRange(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	stability, err := parseHTTPStability(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	redirect, err := parseRedirectAssertion(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	schema, err := parseJSONSchema(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	upHeader, err := parseHealthHeader(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	expectedALPN, err := parseExpectedALPN(monitor)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
 
 	// Load client certificate if configured
 	var tlsCerts []tls.Certificate
@@ -116,24 +264,61 @@ func (h *HTTPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat,
 		}
 	}
 
+	// Trust a private CA on top of the system roots
+	if rootCAs, err = withCACertConfig(monitor, rootCAs); err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	proxyURL, err := getProxyURL(monitor)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: ignoreTLS,
+		Certificates:       tlsCerts,
+		RootCAs:            rootCAs,
+		MinVersion:         minTLSVersion,
+		// Sent as SNI and verified against the certificate instead of the
+		// URL host, which stays in the Host header
+		ServerName: tlsServerNameConfig(monitor),
+	}
+
+	// Record the address of the connection that served the final response.
+	// Through a proxy the connection ends at the proxy, so leave it unset.
+	var remoteAddr *string
+	gotConn := func(addr net.Addr) {
+		remoteAddr = remoteIP(addr)
+	}
+
 	// Create HTTP client with IP version support
+	var roundTripper http.RoundTripper
+	if useHTTP3 {
+		if proxyURL != nil {
+			heartbeat.Message = "http3 cannot be used with proxy_url"
+			return heartbeat, nil
+		}
+		transport, closeTransport := newHTTP3Transport(monitor, tlsConfig, !decodeBody, gotConn)
+		defer closeTransport()
+		roundTripper = transport
+	} else {
+		transport := newHTTPTransport(monitor, tlsConfig, proxyURL, decodeBody)
+		// A custom TLS config turns HTTP/2 off unless forced; offer it so
+		// the server gets to pick
+		if expectedALPN != "" {
+			transport.ForceAttemptHTTP2 = true
+		}
+		roundTripper = transport
+	}
+	if creds := ntlmCredentialsFor(monitor); creds != nil {
+		roundTripper = &ntlmTransport{base: roundTripper, creds: creds}
+	}
+
 	client := &http.Client{
-		Timeout: time.Duration(monitor.Timeout) * time.Second,
-		Transport: &http.Transport{
-			DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
-				// Determine network based on IP version preference
-				network = GetNetworkForIPVersion(network, monitor.IPVersion)
-				dialer := &net.Dialer{
-					Timeout: time.Duration(monitor.Timeout) * time.Second,
-				}
-				return dialer.DialContext(ctx, network, addr)
-			},
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: ignoreTLS,
-				Certificates:       tlsCerts,
-				RootCAs:            rootCAs,
-			},
-		},
+		Timeout:   time.Duration(monitor.Timeout) * time.Second,
+		Transport: roundTripper,
 	}
 
 	// Disable redirects if needed
@@ -143,13 +328,36 @@ func (h *HTTPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat,
 		}
 	}
 
+	// Fetch the token the main request needs, if any
+	requestURL := monitor.URL
+	preflight, err := parseHTTPPreflight(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+	if preflight != nil {
+		token, err := preflight.fetchToken(ctx, client, decodeBody)
+		if err != nil {
+			heartbeat.Message = "Preflight request failed: " + err.Error()
+			return heartbeat, nil
+		}
+		requestURL, headers = withPreflightToken(requestURL, headers, token)
+
+		// Tell main request failures apart from preflight ones
+		defer func() {
+			if heartbeat.Status != StatusUp {
+				heartbeat.Message = "Main request: " + heartbeat.Message
+			}
+		}()
+	}
+
 	// Create request
 	var reqBody io.Reader
 	if body != "" {
 		reqBody = strings.NewReader(body)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, method, monitor.URL, reqBody)
+	req, err := http.NewRequestWithContext(ctx, method, requestURL, reqBody)
 	if err != nil {
 		heartbeat.Message = fmt.Sprintf("Failed to create request: %v", err)
 		return heartbeat, nil
@@ -160,18 +368,59 @@ func (h *HTTPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat,
 		req.Header.Set(key, value)
 	}
 
+	// The HTTP/3 dialer reports its connections itself
+	if proxyURL == nil && !useHTTP3 {
+		req = req.WithContext(httptrace.WithClientTrace(req.Context(), &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				gotConn(info.Conn.RemoteAddr())
+			},
+		}))
+	}
+
 	// Perform request and measure time
 	start := time.Now()
 	resp, err := client.Do(req)
 	ping := time.Since(start).Milliseconds()
 	heartbeat.Ping = int(ping)
+	heartbeat.RemoteAddr = remoteAddr
 
 	if err != nil {
+		heartbeat.HardError = isHardError(err)
+		if useHTTP3 {
+			heartbeat.Message = http3ErrorMessage(err)
+			return heartbeat, nil
+		}
+		if minTLSVersion != 0 && isTLSVersionError(err) {
+			heartbeat.Message = fmt.Sprintf("TLS handshake failed: server does not support %s or higher", tls.VersionName(minTLSVersion))
+			return heartbeat, nil
+		}
 		heartbeat.Message = fmt.Sprintf("Request failed: %v", err)
 		return heartbeat, nil
 	}
 	defer resp.Body.Close()
 
+	// The negotiated protocol is checked whatever else decides the status
+	if expectedALPN != "" {
+		if err := checkALPN(resp, expectedALPN); err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+	}
+
+	// A condition expression replaces the status code and keyword checks
+	if condition := h.getConfigString(monitor, "condition", ""); condition != "" {
+		h.checkCondition(heartbeat, resp, condition, decodeBody)
+		return heartbeat, nil
+	}
+
+	// The health header decides first; the status code still has to pass
+	if upHeader != nil {
+		if err := upHeader.check(resp); err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+	}
+
 	// Check status code
 	statusOK := false
 	for _, code := range acceptedStatusCodes {
@@ -186,14 +435,36 @@ func (h *HTTPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat,
 		return heartbeat, nil
 	}
 
-	// Check keyword if specified
-	if keyword != "" {
-		bodyBytes, err := io.ReadAll(resp.Body)
+	// Check where an accepted, unfollowed redirect points
+	if redirect != nil && !followRedirects {
+		if err := redirect.check(resp); err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+	}
+
+	// The keyword, JSON range and JSON schema checks read the body
+	var bodyBytes []byte
+	readBody := stability != nil && stability.source == "json_path"
+	if keyword != "" || keywords != nil || valueRange != nil || schema != nil || readBody {
+		body, err := responseBody(resp, decodeBody)
+		if err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+		// One byte past the limit is enough for the schema check to reject it
+		if schema != nil {
+			body = io.LimitReader(body, maxJSONSchemaBodySize+1)
+		}
+		bodyBytes, err = io.ReadAll(body)
 		if err != nil {
 			heartbeat.Message = fmt.Sprintf("Failed to read response body: %v", err)
 			return heartbeat, nil
 		}
+	}
 
+	// Check keyword if specified
+	if keyword != "" {
 		bodyText := string(bodyBytes)
 		containsKeyword := strings.Contains(bodyText, keyword)
 
@@ -212,13 +483,238 @@ func (h *HTTPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat,
 		}
 	}
 
+	// Check the keyword list, all or any of it
+	if keywords != nil {
+		if err := keywords.check(string(bodyBytes)); err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+	}
+
+	// Check the value at json_path against json_min/json_max or expected_value
+	var rangeValue string
+	if valueRange != nil {
+		if rangeValue, err = valueRange.check(bodyBytes); err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+	}
+
+	// Check the body conforms to the JSON schema
+	if schema != nil {
+		if err := schema.check(bodyBytes); err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+	}
+
+	// Check the ETag or value at the stability path hasn't been flapping
+	if stability != nil {
+		if err := h.stability.check(monitor.ID, stability, resp, bodyBytes, heartbeat.Time); err != nil {
+			heartbeat.Message = err.Error()
+			return heartbeat, nil
+		}
+	}
+
 	// All checks passed
 	heartbeat.Status = StatusUp
 	heartbeat.Message = fmt.Sprintf("HTTP %d - %dms", resp.StatusCode, ping)
+	if minTLSVersion != 0 && resp.TLS != nil {
+		heartbeat.Message += " - " + tls.VersionName(resp.TLS.Version)
+	}
+	if useHTTP3 {
+		heartbeat.Message += " - HTTP/3"
+	} else if expectedALPN != "" {
+		heartbeat.Message += " - " + expectedALPN
+	}
+	if upHeader != nil {
+		heartbeat.Message += fmt.Sprintf(" - %s: %s", upHeader.name, upHeader.value)
+	}
+	if valueRange != nil {
+		heartbeat.Message += fmt.Sprintf(" - %s = %s", valueRange.path, rangeValue)
+	}
 
 	return heartbeat, nil
 }
 
+// newHTTPTransport returns the HTTP/1.1 and HTTP/2 transport of a check,
+// dialing with the monitor's IP version and source IP
+func newHTTPTransport(monitor *Monitor, tlsConfig *tls.Config, proxyURL *url.URL, decodeBody bool) *http.Transport {
+	transport := &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			// Network follows the IP version preference and source IP
+			dialer, network, err := newCheckDialer(monitor, network)
+			if err != nil {
+				return nil, err
+			}
+			return dialer.DialContext(ctx, network, addr)
+		},
+		TLSClientConfig: tlsConfig,
+	}
+	if proxyURL != nil {
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	if !decodeBody {
+		// Don't let Go request and transparently decompress gzip either
+		transport.DisableCompression = true
+	}
+	return transport
+}
+
+// withCACertConfig adds the optional ca_cert config's certificates to roots,
+// or to a copy of the system roots when roots is nil. Without ca_cert roots
+// is returned as is.
+func withCACertConfig(monitor *Monitor, roots *x509.CertPool) (*x509.CertPool, error) {
+	caCert := getConfigString(monitor, "ca_cert", "")
+	if strings.TrimSpace(caCert) == "" {
+		return roots, nil
+	}
+	if roots == nil {
+		var err error
+		if roots, err = x509.SystemCertPool(); err != nil {
+			roots = x509.NewCertPool()
+		}
+	}
+	if !roots.AppendCertsFromPEM([]byte(caCert)) {
+		return nil, fmt.Errorf("Failed to parse ca_cert")
+	}
+	return roots, nil
+}
+
+// validateCACertConfig checks that the optional ca_cert config holds PEM
+// encoded certificates
+func validateCACertConfig(monitor *Monitor) error {
+	raw, ok := monitor.Config["ca_cert"]
+	if !ok || raw == nil {
+		return nil
+	}
+	caCert, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("ca_cert must be a string")
+	}
+	if strings.TrimSpace(caCert) != "" && !x509.NewCertPool().AppendCertsFromPEM([]byte(caCert)) {
+		return fmt.Errorf("ca_cert must contain at least one PEM encoded certificate")
+	}
+	return nil
+}
+
+// validateTLSServerNameConfig checks that the optional tls_server_name config
+// is a DNS host name; SNI can't carry an IP address
+func validateTLSServerNameConfig(monitor *Monitor) error {
+	raw, ok := monitor.Config["tls_server_name"]
+	if !ok || raw == nil {
+		return nil
+	}
+	serverName, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("tls_server_name must be a string")
+	}
+	if serverName == "" {
+		return nil
+	}
+	name := strings.ToLower(strings.TrimSuffix(serverName, "."))
+	if net.ParseIP(name) != nil {
+		return fmt.Errorf("tls_server_name must be a host name, not an IP address")
+	}
+	if name == "" || len(name) > 253 {
+		return fmt.Errorf("tls_server_name %q is not a valid host name", serverName)
+	}
+	for _, label := range strings.Split(name, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("tls_server_name %q is not a valid host name", serverName)
+		}
+		for _, c := range label {
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return fmt.Errorf("tls_server_name %q is not a valid host name", serverName)
+			}
+		}
+	}
+	return nil
+}
+
+// tlsServerNameConfig returns the optional tls_server_name config, the name
+// sent as SNI and verified against the certificate, normalized
+func tlsServerNameConfig(monitor *Monitor) string {
+	return strings.ToLower(strings.TrimSuffix(getConfigString(monitor, "tls_server_name", ""), "."))
+}
+
+// validateMinTLSVersionConfig checks the optional min_tls_version config
+func validateMinTLSVersionConfig(monitor *Monitor) error {
+	raw, ok := monitor.Config["min_tls_version"]
+	if !ok || raw == nil {
+		return nil
+	}
+	version, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("min_tls_version must be a string")
+	}
+	_, err := parseTLSVersion(version)
+	return err
+}
+
+// parseTLSVersion maps a min_tls_version config value ("1.0" to "1.3") to
+// its crypto/tls constant. Empty means the Go default.
+func parseTLSVersion(version string) (uint16, error) {
+	switch strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(version)), "TLS") {
+	case "":
+		return 0, nil
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("min_tls_version must be one of 1.0, 1.1, 1.2, 1.3")
+	}
+}
+
+// isTLSVersionError reports whether a request failed because client and
+// server share no acceptable TLS version
+func isTLSVersionError(err error) bool {
+	var alert tls.AlertError
+	if errors.As(err, &alert) && alert == 70 { // protocol_version
+		return true
+	}
+	return strings.Contains(err.Error(), "protocol version")
+}
+
+// checkCondition evaluates a condition expression against the response
+func (h *HTTPMonitor) checkCondition(heartbeat *Heartbeat, resp *http.Response, source string, decodeBody bool) {
+	condition, err := CompileCondition(source, httpConditionEnv{})
+	if err != nil {
+		heartbeat.Message = fmt.Sprintf("Invalid condition: %v", err)
+		return
+	}
+
+	body, err := responseBody(resp, decodeBody)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return
+	}
+
+	bodyBytes, err := io.ReadAll(io.LimitReader(body, maxConditionBodySize))
+	if err != nil {
+		heartbeat.Message = fmt.Sprintf("Failed to read response body: %v", err)
+		return
+	}
+
+	ok, err := condition.Eval(newHTTPConditionEnv(resp.StatusCode, heartbeat.Ping, string(bodyBytes)))
+	if err != nil {
+		heartbeat.Message = fmt.Sprintf("Condition evaluation failed: %v", err)
+		return
+	}
+	if !ok {
+		heartbeat.Message = fmt.Sprintf("Condition not met: HTTP %d - %dms", resp.StatusCode, heartbeat.Ping)
+		return
+	}
+
+	heartbeat.Status = StatusUp
+	heartbeat.Message = fmt.Sprintf("HTTP %d - %dms", resp.StatusCode, heartbeat.Ping)
+}
+
 // Helper methods to get config values
 func (h *HTTPMonitor) getConfigString(monitor *Monitor, key, defaultValue string) string {
 	if monitor.Config == nil {
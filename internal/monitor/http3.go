@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+
+	"github.com/quic-go/quic-go"
+	"github.com/quic-go/quic-go/http3"
+)
+
+// validateHTTP3Config checks the http3 option of an HTTP monitor. HTTP/3
+// runs over QUIC, so it needs an https:// URL and can't go through the
+// http(s)/socks5 proxies the TCP transport supports.
+func validateHTTP3Config(monitor *Monitor) error {
+	raw, ok := monitor.Config["http3"]
+	if !ok || raw == nil {
+		return nil
+	}
+	enabled, ok := raw.(bool)
+	if !ok {
+		return fmt.Errorf("http3 must be a boolean")
+	}
+	if !enabled {
+		return nil
+	}
+
+	if !strings.HasPrefix(monitor.URL, "https://") {
+		return fmt.Errorf("http3 requires an https:// URL")
+	}
+	if proxy, _ := monitor.Config["proxy_url"].(string); strings.TrimSpace(proxy) != "" {
+		return fmt.Errorf("http3 cannot be used with proxy_url")
+	}
+	return nil
+}
+
+// http3Dialer opens the QUIC connections of one check. Each connection gets
+// its own UDP socket so the monitor's IP version and source IP apply, and all
+// of them are released by Close.
+type http3Dialer struct {
+	monitor *Monitor
+	// gotConn is called with the server address of each new connection
+	gotConn func(net.Addr)
+
+	mu         sync.Mutex
+	transports []*quic.Transport
+}
+
+// Dial implements http3.Transport.Dial
+func (d *http3Dialer) Dial(ctx context.Context, addr string, tlsCfg *tls.Config, cfg *quic.Config) (*quic.Conn, error) {
+	dialer, network, err := newCheckDialer(d.monitor, "udp")
+	if err != nil {
+		return nil, err
+	}
+	udpAddr, err := net.ResolveUDPAddr(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	localAddr, _ := dialer.LocalAddr.(*net.UDPAddr)
+	udpConn, err := net.ListenUDP(network, localAddr)
+	if err != nil {
+		return nil, err
+	}
+
+	tr := &quic.Transport{Conn: udpConn}
+	d.mu.Lock()
+	d.transports = append(d.transports, tr)
+	d.mu.Unlock()
+
+	conn, err := tr.DialEarly(ctx, udpAddr, tlsCfg, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if d.gotConn != nil {
+		d.gotConn(conn.RemoteAddr())
+	}
+	return conn, nil
+}
+
+// Close shuts down every QUIC transport and UDP socket opened by Dial
+func (d *http3Dialer) Close() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, tr := range d.transports {
+		tr.Close()
+		tr.Conn.Close()
+	}
+	d.transports = nil
+}
+
+// newHTTP3Transport returns a round tripper that speaks HTTP/3 only, and a
+// function that releases its connections once the check is done
+func newHTTP3Transport(monitor *Monitor, tlsConfig *tls.Config, disableCompression bool, gotConn func(net.Addr)) (*http3.Transport, func()) {
+	dialer := &http3Dialer{monitor: monitor, gotConn: gotConn}
+	transport := &http3.Transport{
+		TLSClientConfig:    tlsConfig,
+		DisableCompression: disableCompression,
+		Dial:               dialer.Dial,
+	}
+	return transport, func() {
+		transport.Close()
+		dialer.Close()
+	}
+}
+
+// http3ErrorMessage explains a failed HTTP/3 request. A server without HTTP/3
+// support either ignores QUIC packets, which ends in a handshake or idle
+// timeout, or rejects the h3 ALPN during the handshake.
+func http3ErrorMessage(err error) string {
+	var idleErr *quic.IdleTimeoutError
+	var handshakeErr *quic.HandshakeTimeoutError
+	var transportErr *quic.TransportError
+	switch {
+	case errors.As(err, &idleErr), errors.As(err, &handshakeErr):
+		return fmt.Sprintf("HTTP/3 request failed: no QUIC response, the server may not support HTTP/3 (%v)", err)
+	case errors.As(err, &transportErr) && transportErr.ErrorCode.IsCryptoError():
+		return fmt.Sprintf("HTTP/3 request failed: QUIC handshake rejected, the server may not support HTTP/3 (%v)", err)
+	}
+	return fmt.Sprintf("HTTP/3 request failed: %v", err)
+}
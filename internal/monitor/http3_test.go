@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/quic-go/quic-go/http3"
+)
+
+// newHTTP3Server starts an HTTP/3-only server on a local UDP port and returns
+// its https:// URL
+func newHTTP3Server(t *testing.T, handler http.Handler) string {
+	t.Helper()
+
+	// Borrow httptest's self-signed certificate
+	tlsServer := httptest.NewTLSServer(http.NotFoundHandler())
+	certs := tlsServer.TLS.Certificates
+	tlsServer.Close()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP unavailable: %v", err)
+	}
+	server := &http3.Server{
+		Handler:   handler,
+		TLSConfig: http3.ConfigureTLSConfig(&tls.Config{Certificates: certs}),
+	}
+	go server.Serve(conn)
+	t.Cleanup(func() {
+		server.Close()
+		conn.Close()
+	})
+
+	return fmt.Sprintf("https://%s/health", conn.LocalAddr())
+}
+
+func TestHTTPMonitorHTTP3(t *testing.T) {
+	url := newHTTP3Server(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.ProtoMajor != 3 {
+			t.Errorf("request protocol = %s, want HTTP/3", r.Proto)
+		}
+		w.Write([]byte("ok"))
+	}))
+
+	m := &Monitor{
+		URL:     url,
+		Timeout: 5,
+		Config: map[string]interface{}{
+			"http3":      true,
+			"ignore_tls": true,
+			"keyword":    "ok",
+		},
+	}
+	hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusUp {
+		t.Fatalf("status = %d (%s), want up", hb.Status, hb.Message)
+	}
+	if !strings.HasSuffix(hb.Message, "HTTP/3") {
+		t.Errorf("message = %q, want the protocol reported", hb.Message)
+	}
+	if hb.RemoteAddr == nil || *hb.RemoteAddr != "127.0.0.1" {
+		t.Errorf("remote addr = %v, want 127.0.0.1", hb.RemoteAddr)
+	}
+}
+
+func TestHTTPMonitorHTTP3Unsupported(t *testing.T) {
+	// A TCP-only server: nothing answers QUIC on its port
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	m := &Monitor{
+		URL:     server.URL,
+		Timeout: 2,
+		Config: map[string]interface{}{
+			"http3":      true,
+			"ignore_tls": true,
+		},
+	}
+	hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusDown {
+		t.Fatalf("status = %d (%s), want down", hb.Status, hb.Message)
+	}
+	if !strings.HasPrefix(hb.Message, "HTTP/3 request failed") {
+		t.Errorf("message = %q, want an HTTP/3 failure", hb.Message)
+	}
+}
+
+func TestValidateHTTP3Config(t *testing.T) {
+	valid := []*Monitor{
+		{URL: "http://example.com"},
+		{URL: "http://example.com", Config: map[string]interface{}{"http3": false}},
+		{URL: "https://example.com", Config: map[string]interface{}{"http3": true}},
+	}
+	for _, m := range valid {
+		if err := validateHTTP3Config(m); err != nil {
+			t.Errorf("validateHTTP3Config(%s, %v): %v", m.URL, m.Config, err)
+		}
+	}
+
+	invalid := []*Monitor{
+		{URL: "https://example.com", Config: map[string]interface{}{"http3": "yes"}},
+		{URL: "http://example.com", Config: map[string]interface{}{"http3": true}},
+		{URL: "https://example.com", Config: map[string]interface{}{"http3": true, "proxy_url": "http://proxy:3128"}},
+	}
+	for _, m := range invalid {
+		if err := validateHTTP3Config(m); err == nil {
+			t.Errorf("validateHTTP3Config(%s, %v) succeeded, want error", m.URL, m.Config)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// parseExpectedALPN reads the expected_alpn config, the application protocol
+// the server must negotiate over TLS: "h2" or "http/1.1", or "h3" for
+// http3 monitors. It returns "" when no protocol is expected.
+func parseExpectedALPN(monitor *Monitor) (string, error) {
+	protocol, err := optionalConfigString(monitor.Config, "expected_alpn")
+	if err != nil || protocol == "" {
+		return "", err
+	}
+	protocol = strings.ToLower(protocol)
+
+	useHTTP3, _ := monitor.Config["http3"].(bool)
+	switch protocol {
+	case "h2", "http/1.1":
+		if useHTTP3 {
+			return "", fmt.Errorf("expected_alpn must be h3 when http3 is enabled")
+		}
+	case "h3":
+		if !useHTTP3 {
+			return "", fmt.Errorf("expected_alpn h3 requires http3")
+		}
+	default:
+		return "", fmt.Errorf("expected_alpn must be one of h2, http/1.1, h3")
+	}
+
+	// Plain HTTP negotiates nothing
+	if !strings.HasPrefix(monitor.URL, "https://") {
+		return "", fmt.Errorf("expected_alpn requires an https:// URL")
+	}
+	return protocol, nil
+}
+
+// checkALPN compares the protocol negotiated for the response with the
+// expected one
+func checkALPN(resp *http.Response, expected string) error {
+	got := ""
+	if resp.TLS != nil {
+		got = resp.TLS.NegotiatedProtocol
+	}
+	if got == "" {
+		return fmt.Errorf("No ALPN protocol negotiated, expected %s", expected)
+	}
+	if got != expected {
+		return fmt.Errorf("Negotiated ALPN %s, expected %s", got, expected)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMonitorExpectedALPN(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	// httptest only offers http/1.1 unless HTTP/2 is enabled
+	http1 := httptest.NewTLSServer(handler)
+	defer http1.Close()
+	h2 := httptest.NewUnstartedServer(handler)
+	h2.EnableHTTP2 = true
+	h2.StartTLS()
+	defer h2.Close()
+
+	tests := []struct {
+		name        string
+		url         string
+		config      map[string]interface{}
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "h2 server", url: h2.URL, config: map[string]interface{}{"expected_alpn": "h2"}, wantStatus: StatusUp, wantMessage: " - h2"},
+		{name: "http/1.1 only server", url: http1.URL, config: map[string]interface{}{"expected_alpn": "h2"}, wantStatus: StatusDown, wantMessage: "Negotiated ALPN http/1.1, expected h2"},
+		{name: "http/1.1 expected", url: http1.URL, config: map[string]interface{}{"expected_alpn": "http/1.1"}, wantStatus: StatusUp},
+		{name: "h2 server, http/1.1 expected", url: h2.URL, config: map[string]interface{}{"expected_alpn": "http/1.1"}, wantStatus: StatusDown, wantMessage: "Negotiated ALPN h2, expected http/1.1"},
+		{name: "with min TLS version", url: h2.URL, config: map[string]interface{}{"expected_alpn": "H2", "min_tls_version": "1.2"}, wantStatus: StatusUp, wantMessage: "TLS 1.3 - h2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["ignore_tls"] = true
+			m := &Monitor{URL: tt.url, Timeout: 5, Config: tt.config}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseExpectedALPN(t *testing.T) {
+	tests := []struct {
+		url     string
+		config  map[string]interface{}
+		want    string
+		wantErr string
+	}{
+		{url: "https://example.com", config: map[string]interface{}{}},
+		{url: "https://example.com", config: map[string]interface{}{"expected_alpn": " H2 "}, want: "h2"},
+		{url: "https://example.com", config: map[string]interface{}{"expected_alpn": "h3", "http3": true}, want: "h3"},
+		{url: "https://example.com", config: map[string]interface{}{"expected_alpn": "spdy/3"}, wantErr: "must be one of"},
+		{url: "https://example.com", config: map[string]interface{}{"expected_alpn": 2.0}, wantErr: "must be a string"},
+		{url: "https://example.com", config: map[string]interface{}{"expected_alpn": "h3"}, wantErr: "requires http3"},
+		{url: "https://example.com", config: map[string]interface{}{"expected_alpn": "h2", "http3": true}, wantErr: "must be h3"},
+		{url: "http://example.com", config: map[string]interface{}{"expected_alpn": "h2"}, wantErr: "requires an https:// URL"},
+	}
+
+	for _, tt := range tests {
+		got, err := parseExpectedALPN(&Monitor{URL: tt.url, Config: tt.config})
+		if tt.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("parseExpectedALPN(%v) error = %v, want %q", tt.config, err, tt.wantErr)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseExpectedALPN(%v) = %q, %v, want %q", tt.config, got, err, tt.want)
+		}
+	}
+}
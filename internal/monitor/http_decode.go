@@ -0,0 +1,75 @@
+package monitor
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// maxDecodedBodySize caps how much a compressed body may expand to while it
+// is decoded, so a small compressed response can't exhaust memory
+const maxDecodedBodySize = 32 << 20
+
+// responseBody returns the body to match keywords and conditions against.
+// Go only decompresses gzip itself when it added Accept-Encoding; when the
+// monitor sets its own Accept-Encoding header the body arrives encoded, so it
+// is decoded here unless decode_body is false.
+func responseBody(resp *http.Response, decode bool) (io.Reader, error) {
+	if !decode || resp.Uncompressed {
+		return resp.Body, nil
+	}
+	return decodeContentEncoding(resp.Body, resp.Header.Get("Content-Encoding"))
+}
+
+// decodeContentEncoding undoes the codings listed in a Content-Encoding
+// header, last applied first
+func decodeContentEncoding(body io.Reader, contentEncoding string) (io.Reader, error) {
+	codings := strings.Split(contentEncoding, ",")
+	decoded := false
+	for i := len(codings) - 1; i >= 0; i-- {
+		var err error
+		switch coding := strings.ToLower(strings.TrimSpace(codings[i])); coding {
+		case "", "identity":
+			continue
+		case "gzip", "x-gzip":
+			body, err = gzip.NewReader(body)
+		case "deflate":
+			body, err = newDeflateReader(body)
+		case "br":
+			body = brotli.NewReader(body)
+		default:
+			return nil, fmt.Errorf("unsupported Content-Encoding %q", coding)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode %s body: %w", strings.TrimSpace(codings[i]), err)
+		}
+		decoded = true
+	}
+
+	if decoded {
+		body = io.LimitReader(body, maxDecodedBodySize)
+	}
+	return body, nil
+}
+
+// newDeflateReader reads "deflate" bodies, which should be zlib-wrapped
+// (RFC 9110) but are sent as raw DEFLATE by some servers
+func newDeflateReader(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+	// A zlib header is CMF/FLG with compression method 8 and a checksum
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
@@ -0,0 +1,118 @@
+package monitor
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+// newEncodedServer serves body compressed with the given Content-Encoding
+func newEncodedServer(t *testing.T, encoding string, body string) *httptest.Server {
+	t.Helper()
+
+	var buf bytes.Buffer
+	var w io.WriteCloser
+	switch encoding {
+	case "gzip":
+		w = gzip.NewWriter(&buf)
+	case "deflate":
+		w = zlib.NewWriter(&buf)
+	case "br":
+		w = brotli.NewWriter(&buf)
+	default:
+		t.Fatalf("unknown encoding %q", encoding)
+	}
+	w.Write([]byte(body))
+	w.Close()
+	encoded := buf.Bytes()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", encoding)
+		w.Write(encoded)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPMonitorKeywordInEncodedBody(t *testing.T) {
+	for _, encoding := range []string{"gzip", "deflate", "br"} {
+		t.Run(encoding, func(t *testing.T) {
+			server := newEncodedServer(t, encoding, "<html>status: all systems operational</html>")
+
+			m := &Monitor{
+				URL:     server.URL,
+				Timeout: 5,
+				Config: map[string]interface{}{
+					"keyword": "all systems operational",
+					// A manual Accept-Encoding stops Go from decoding the body itself
+					"headers": map[string]interface{}{"Accept-Encoding": encoding},
+				},
+			}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != StatusUp {
+				t.Fatalf("status = %d (%s), want up", hb.Status, hb.Message)
+			}
+		})
+	}
+}
+
+func TestHTTPMonitorDecodeBodyDisabled(t *testing.T) {
+	// Long and repetitive so gzip compresses it rather than storing it verbatim
+	server := newEncodedServer(t, "gzip", strings.Repeat("all systems operational\n", 50))
+
+	m := &Monitor{
+		URL:     server.URL,
+		Timeout: 5,
+		Config: map[string]interface{}{
+			"keyword":     "all systems operational",
+			"headers":     map[string]interface{}{"Accept-Encoding": "gzip"},
+			"decode_body": false,
+		},
+	}
+	hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusDown || !strings.Contains(hb.Message, "not found") {
+		t.Fatalf("status = %d (%s), want the keyword missing from the raw body", hb.Status, hb.Message)
+	}
+}
+
+func TestDecodeContentEncoding(t *testing.T) {
+	var raw bytes.Buffer
+	w, _ := flate.NewWriter(&raw, flate.DefaultCompression)
+	w.Write([]byte("raw deflate"))
+	w.Close()
+
+	body, err := decodeContentEncoding(&raw, "deflate")
+	if err != nil {
+		t.Fatalf("raw deflate: %v", err)
+	}
+	if got, _ := io.ReadAll(body); string(got) != "raw deflate" {
+		t.Errorf("raw deflate decoded to %q", got)
+	}
+
+	if _, err := decodeContentEncoding(strings.NewReader("x"), "compress"); err == nil {
+		t.Error("unsupported encoding accepted")
+	}
+
+	body, err = decodeContentEncoding(strings.NewReader("plain"), "identity")
+	if err != nil {
+		t.Fatalf("identity: %v", err)
+	}
+	if got, _ := io.ReadAll(body); string(got) != "plain" {
+		t.Errorf("identity decoded to %q", got)
+	}
+}
@@ -0,0 +1,68 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// healthHeader decides a check by a response header, for services that
+// report their health in a header like "X-Health: ok" while always
+// answering 200
+type healthHeader struct {
+	name  string
+	value string
+}
+
+// parseHealthHeader reads the up_header and up_header_value config, which
+// are set together. It returns nil when no header is configured.
+func parseHealthHeader(config map[string]interface{}) (*healthHeader, error) {
+	name, err := optionalConfigString(config, "up_header")
+	if err != nil {
+		return nil, err
+	}
+	value, err := optionalConfigString(config, "up_header_value")
+	if err != nil {
+		return nil, err
+	}
+
+	if name == "" && value == "" {
+		return nil, nil
+	}
+	if name == "" {
+		return nil, fmt.Errorf("up_header_value requires up_header")
+	}
+	if value == "" {
+		return nil, fmt.Errorf("up_header requires up_header_value")
+	}
+	if strings.ContainsAny(name, " \t:") {
+		return nil, fmt.Errorf("up_header %q is not a valid header name", name)
+	}
+	return &healthHeader{name: http.CanonicalHeaderKey(name), value: value}, nil
+}
+
+// optionalConfigString returns the trimmed string at key, or "" when unset
+func optionalConfigString(config map[string]interface{}, key string) (string, error) {
+	switch v := config[key].(type) {
+	case nil:
+		return "", nil
+	case string:
+		return strings.TrimSpace(v), nil
+	default:
+		return "", fmt.Errorf("%s must be a string", key)
+	}
+}
+
+// check compares the header with the expected value, ignoring case and
+// surrounding whitespace
+func (h *healthHeader) check(resp *http.Response) error {
+	values := resp.Header.Values(h.name)
+	if len(values) == 0 {
+		return fmt.Errorf("Header %s missing, expected '%s'", h.name, h.value)
+	}
+	got := strings.TrimSpace(values[0])
+	if !strings.EqualFold(got, h.value) {
+		return fmt.Errorf("Header %s is '%s', expected '%s'", h.name, got, h.value)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMonitorUpHeader(t *testing.T) {
+	tests := []struct {
+		name        string
+		status      int
+		header      string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "healthy", status: 200, header: "ok", wantStatus: StatusUp, wantMessage: "X-Health: ok"},
+		{name: "value ignores case", status: 200, header: " OK ", wantStatus: StatusUp},
+		{name: "200 with wrong value", status: 200, header: "degraded", wantStatus: StatusDown, wantMessage: "Header X-Health is 'degraded', expected 'ok'"},
+		{name: "missing header", status: 200, wantStatus: StatusDown, wantMessage: "Header X-Health missing"},
+		{name: "status code still gates", status: 503, header: "ok", wantStatus: StatusDown, wantMessage: "Unexpected status code: 503"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set("X-Health", tt.header)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			m := &Monitor{URL: server.URL, Timeout: 5, Config: map[string]interface{}{
+				"up_header":       "x-health",
+				"up_header_value": "ok",
+			}}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseHealthHeaderValidation(t *testing.T) {
+	tests := []struct {
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{config: map[string]interface{}{"up_header": "X-Health"}, wantErr: "up_header requires up_header_value"},
+		{config: map[string]interface{}{"up_header_value": "ok"}, wantErr: "up_header_value requires up_header"},
+		{config: map[string]interface{}{"up_header": "X Health", "up_header_value": "ok"}, wantErr: "not a valid header name"},
+		{config: map[string]interface{}{"up_header": 1.0, "up_header_value": "ok"}, wantErr: "up_header must be a string"},
+		{config: map[string]interface{}{"up_header": "X-Health", "up_header_value": "ok"}},
+		{config: map[string]interface{}{}},
+	}
+
+	for _, tt := range tests {
+		_, err := parseHealthHeader(tt.config)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("parseHealthHeader(%v) = %v, want nil", tt.config, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("parseHealthHeader(%v) = %v, want error containing %q", tt.config, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+const (
+	// maxJSONSchemaBodySize caps the response body validated against json_schema
+	maxJSONSchemaBodySize = 1 << 20
+
+	// maxJSONSchemaErrors bounds how many validation errors a heartbeat lists
+	maxJSONSchemaErrors = 5
+)
+
+// jsonSchemaURL names the schema resource while it compiles
+const jsonSchemaURL = "json_schema.json"
+
+// jsonSchemaCheck validates the response body against a JSON Schema
+type jsonSchemaCheck struct {
+	schema *jsonschema.Schema
+}
+
+// parseJSONSchema reads and compiles the json_schema config, given as a
+// JSON object or as a string holding one. It returns nil when no schema is
+// set. $ref may only point inside the schema: nothing is loaded from files
+// or the network.
+func parseJSONSchema(config map[string]interface{}) (*jsonSchemaCheck, error) {
+	var source string
+	switch v := config["json_schema"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		source = strings.TrimSpace(v)
+	case map[string]interface{}:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("json_schema: %v", err)
+		}
+		source = string(data)
+	default:
+		return nil, fmt.Errorf("json_schema must be a JSON object")
+	}
+	if source == "" {
+		return nil, nil
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.LoadURL = func(url string) (io.ReadCloser, error) {
+		return nil, fmt.Errorf("$ref to %s is not allowed, only references within the schema", url)
+	}
+	if err := compiler.AddResource(jsonSchemaURL, strings.NewReader(source)); err != nil {
+		return nil, fmt.Errorf("invalid json_schema: %v", err)
+	}
+	schema, err := compiler.Compile(jsonSchemaURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json_schema: %s", schemaCompileError(err))
+	}
+	return &jsonSchemaCheck{schema: schema}, nil
+}
+
+// schemaCompileError drops the library's prefix naming the resource
+func schemaCompileError(err error) string {
+	if se, ok := err.(*jsonschema.SchemaError); ok && se.Err != nil {
+		return strings.TrimPrefix(se.Err.Error(), "jsonschema: ")
+	}
+	return err.Error()
+}
+
+// check validates body, returning an error listing where and why it does
+// not conform
+func (c *jsonSchemaCheck) check(body []byte) error {
+	if len(body) > maxJSONSchemaBodySize {
+		return fmt.Errorf("response body exceeds %d bytes, too large to validate against json_schema", maxJSONSchemaBodySize)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return fmt.Errorf("response is not valid JSON: %v", err)
+	}
+
+	err := c.schema.Validate(doc)
+	if err == nil {
+		return nil
+	}
+	validationErr, ok := err.(*jsonschema.ValidationError)
+	if !ok {
+		return fmt.Errorf("JSON schema validation failed: %v", err)
+	}
+
+	problems := schemaViolations(validationErr)
+	more := ""
+	if len(problems) > maxJSONSchemaErrors {
+		more = fmt.Sprintf(" (and %d more)", len(problems)-maxJSONSchemaErrors)
+		problems = problems[:maxJSONSchemaErrors]
+	}
+	return fmt.Errorf("Response does not match json_schema: %s%s", strings.Join(problems, "; "), more)
+}
+
+// schemaViolations lists the innermost errors of a validation failure as
+// "location: message", sorted and without duplicates
+func schemaViolations(err *jsonschema.ValidationError) []string {
+	seen := make(map[string]bool)
+	var problems []string
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) > 0 {
+			for _, cause := range e.Causes {
+				walk(cause)
+			}
+			return
+		}
+		location := e.InstanceLocation
+		if location == "" {
+			location = "/"
+		}
+		problem := location + ": " + e.Message
+		if !seen[problem] {
+			seen[problem] = true
+			problems = append(problems, problem)
+		}
+	}
+	walk(err)
+	sort.Strings(problems)
+	return problems
+}
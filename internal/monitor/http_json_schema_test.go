@@ -0,0 +1,86 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+const userSchema = `{
+	"type": "object",
+	"required": ["id", "email"],
+	"properties": {
+		"id": {"type": "integer"},
+		"email": {"type": "string"},
+		"roles": {"type": "array", "items": {"type": "string"}}
+	}
+}`
+
+func TestHTTPMonitorJSONSchema(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		wantStatus  int
+		wantMessage []string
+	}{
+		{
+			name:       "conforming",
+			body:       `{"id": 7, "email": "ada@example.com", "roles": ["admin"]}`,
+			wantStatus: StatusUp,
+		},
+		{
+			name:        "non-conforming",
+			body:        `{"id": "7", "roles": ["admin", 3]}`,
+			wantStatus:  StatusDown,
+			wantMessage: []string{"Response does not match json_schema", "/: missing properties: 'email'", "/id: expected integer, but got string", "/roles/1: expected string, but got number"},
+		},
+		{
+			name:        "not JSON",
+			body:        `<html>maintenance</html>`,
+			wantStatus:  StatusDown,
+			wantMessage: []string{"response is not valid JSON"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newJSONServer(t, tt.body)
+			m := &Monitor{URL: server.URL, Timeout: 5, Config: map[string]interface{}{"json_schema": userSchema}}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus {
+				t.Errorf("status = %d %q, want %d", hb.Status, hb.Message, tt.wantStatus)
+			}
+			for _, want := range tt.wantMessage {
+				if !strings.Contains(hb.Message, want) {
+					t.Errorf("message %q does not contain %q", hb.Message, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseJSONSchema(t *testing.T) {
+	// Given as an object, as a client sending the config as JSON does
+	schema, err := parseJSONSchema(map[string]interface{}{"json_schema": map[string]interface{}{"type": "array"}})
+	if err != nil || schema == nil {
+		t.Fatalf("object schema = %v, %v", schema, err)
+	}
+	if err := schema.check([]byte(`{}`)); err == nil {
+		t.Error("object accepted by an array schema")
+	}
+
+	invalid := []interface{}{
+		`{"type": "no-such-type"}`,
+		`{"type": `,
+		`{"$ref": "file:///etc/passwd"}`,
+		42.0,
+	}
+	for _, raw := range invalid {
+		if _, err := parseJSONSchema(map[string]interface{}{"json_schema": raw}); err == nil {
+			t.Errorf("json_schema %v accepted", raw)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package monitor
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keywordMatch checks the response body for a list of keywords, requiring
+// all of them or any one of them
+type keywordMatch struct {
+	keywords []string
+	matchAll bool
+}
+
+// parseKeywordMatch reads the keywords and keyword_match config. The mode is
+// "all" (the default) or "any". It returns nil when no keywords are set.
+func parseKeywordMatch(config map[string]interface{}) (*keywordMatch, error) {
+	m := &keywordMatch{matchAll: true}
+	switch mode := config["keyword_match"].(type) {
+	case nil:
+	case string:
+		switch mode {
+		case "", "all":
+		case "any":
+			m.matchAll = false
+		default:
+			return nil, fmt.Errorf("keyword_match must be \"any\" or \"all\"")
+		}
+	default:
+		return nil, fmt.Errorf("keyword_match must be \"any\" or \"all\"")
+	}
+
+	var raw []interface{}
+	switch v := config["keywords"].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		for _, keyword := range v {
+			raw = append(raw, keyword)
+		}
+	case []interface{}:
+		raw = v
+	default:
+		return nil, fmt.Errorf("keywords must be a list of strings")
+	}
+	for _, item := range raw {
+		keyword, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("keywords must be a list of strings")
+		}
+		if keyword != "" {
+			m.keywords = append(m.keywords, keyword)
+		}
+	}
+	if len(m.keywords) == 0 {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// check returns an error naming the missing keyword in "all" mode, or every
+// keyword in "any" mode when none was found
+func (m *keywordMatch) check(body string) error {
+	if m.matchAll {
+		for _, keyword := range m.keywords {
+			if !strings.Contains(body, keyword) {
+				return fmt.Errorf("Keyword '%s' not found", keyword)
+			}
+		}
+		return nil
+	}
+
+	for _, keyword := range m.keywords {
+		if strings.Contains(body, keyword) {
+			return nil
+		}
+	}
+	return fmt.Errorf("None of the keywords found: '%s'", strings.Join(m.keywords, "', '"))
+}
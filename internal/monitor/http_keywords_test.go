@@ -0,0 +1,83 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMonitorKeywords(t *testing.T) {
+	server := newJSONServer(t, `{"database": "ok", "cache": "ok", "queue": "degraded"}`)
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:       "all present",
+			config:     map[string]interface{}{"keywords": []interface{}{"database", "cache"}, "keyword_match": "all"},
+			wantStatus: StatusUp,
+		},
+		{
+			name:        "all with one missing",
+			config:      map[string]interface{}{"keywords": []interface{}{"database", "search", "cache"}},
+			wantStatus:  StatusDown,
+			wantMessage: "Keyword 'search' not found",
+		},
+		{
+			name:       "any with one present",
+			config:     map[string]interface{}{"keywords": []interface{}{"maintenance", "degraded"}, "keyword_match": "any"},
+			wantStatus: StatusUp,
+		},
+		{
+			name:        "any with none present",
+			config:      map[string]interface{}{"keywords": []interface{}{"maintenance", "offline"}, "keyword_match": "any"},
+			wantStatus:  StatusDown,
+			wantMessage: "None of the keywords found: 'maintenance', 'offline'",
+		},
+		{
+			name:        "single keyword still checked",
+			config:      map[string]interface{}{"keyword": "search", "keywords": []interface{}{"database"}},
+			wantStatus:  StatusDown,
+			wantMessage: "Keyword 'search' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{URL: server.URL, Timeout: 5, Config: tt.config}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseKeywordMatch(t *testing.T) {
+	m, err := parseKeywordMatch(map[string]interface{}{"keywords": []interface{}{"a", "", "b"}})
+	if err != nil || m == nil || !m.matchAll || len(m.keywords) != 2 {
+		t.Errorf("parseKeywordMatch = %+v, %v; want keywords [a b] matching all", m, err)
+	}
+	if m, err := parseKeywordMatch(map[string]interface{}{"keyword_match": "any"}); m != nil || err != nil {
+		t.Errorf("without keywords = %+v, %v; want nil", m, err)
+	}
+
+	invalid := []map[string]interface{}{
+		{"keywords": []interface{}{"a"}, "keyword_match": "some"},
+		{"keyword_match": "ALL"},
+		{"keywords": []interface{}{"a"}, "keyword_match": 1},
+		{"keywords": "a,b"},
+		{"keywords": []interface{}{"a", 2}},
+	}
+	for _, config := range invalid {
+		if _, err := parseKeywordMatch(config); err == nil {
+			t.Errorf("parseKeywordMatch(%v) succeeded, want error", config)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-ntlmssp"
+)
+
+// authTypeNTLM is the auth_type of HTTP monitors authenticating with NTLM,
+// offered by the server as either NTLM or Negotiate
+const authTypeNTLM = "ntlm"
+
+// ntlmCredentials are the account an HTTP monitor authenticates as
+type ntlmCredentials struct {
+	domain   string
+	username string
+	password string
+}
+
+// validateAuthConfig checks the optional auth_type of an HTTP monitor and,
+// for NTLM, its username and password. NTLM authenticates a connection, so
+// it can't run over the multiplexed HTTP/3 transport.
+func validateAuthConfig(monitor *Monitor) error {
+	raw, ok := monitor.Config["auth_type"]
+	if !ok || raw == nil {
+		return nil
+	}
+	authType, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("auth_type must be a string")
+	}
+	switch authType {
+	case "":
+		return nil
+	case authTypeNTLM:
+	default:
+		return fmt.Errorf("auth_type must be empty or %q", authTypeNTLM)
+	}
+
+	for _, field := range []string{"auth_domain", "auth_username", "auth_password"} {
+		if v, ok := monitor.Config[field]; ok && v != nil {
+			if _, ok := v.(string); !ok {
+				return fmt.Errorf("%s must be a string", field)
+			}
+		}
+	}
+	creds := ntlmCredentialsFor(monitor)
+	if strings.TrimSpace(creds.username) == "" {
+		return fmt.Errorf("auth_username is required for NTLM authentication")
+	}
+	if creds.password == "" {
+		return fmt.Errorf("auth_password is required for NTLM authentication")
+	}
+	if useHTTP3, _ := monitor.Config["http3"].(bool); useHTTP3 {
+		return fmt.Errorf("http3 cannot be used with NTLM authentication")
+	}
+	return nil
+}
+
+// ntlmCredentialsFor returns the NTLM account of a monitor, or nil when it
+// doesn't use NTLM
+func ntlmCredentialsFor(monitor *Monitor) *ntlmCredentials {
+	if authType, _ := monitor.Config["auth_type"].(string); authType != authTypeNTLM {
+		return nil
+	}
+	domain, _ := monitor.Config["auth_domain"].(string)
+	username, _ := monitor.Config["auth_username"].(string)
+	password, _ := monitor.Config["auth_password"].(string)
+	return &ntlmCredentials{domain: strings.TrimSpace(domain), username: strings.TrimSpace(username), password: password}
+}
+
+// ntlmTransport answers a server's NTLM or Negotiate challenge. The
+// handshake authenticates the connection it runs on, so base must keep
+// connections alive and serve one request at a time on each.
+type ntlmTransport struct {
+	base  http.RoundTripper
+	creds *ntlmCredentials
+}
+
+// RoundTrip sends req and, when the server asks for NTLM, runs the
+// handshake as the monitor's account. The negotiator takes the account from
+// the request's basic auth and never sends it as Basic.
+func (t *ntlmTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	username := t.creds.username
+	if t.creds.domain != "" {
+		username = t.creds.domain + `\` + username
+	}
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(username, t.creds.password)
+	return ntlmssp.Negotiator{RoundTripper: t.base}.RoundTrip(req)
+}
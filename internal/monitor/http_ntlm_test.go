@@ -0,0 +1,198 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+// ntlmUnicode encodes s as UTF-16LE
+func ntlmUnicode(s string) []byte {
+	units := utf16.Encode([]rune(s))
+	out := make([]byte, 2*len(units))
+	for i, u := range units {
+		binary.LittleEndian.PutUint16(out[2*i:], u)
+	}
+	return out
+}
+
+func ntlmHMAC(key []byte, data ...[]byte) []byte {
+	mac := hmac.New(md5.New, key)
+	for _, d := range data {
+		mac.Write(d)
+	}
+	return mac.Sum(nil)
+}
+
+// ntlmV2Hash is NTOWFv2: the HMAC-MD5, keyed with the MD4 of the password,
+// of the upper-cased username and the domain
+func ntlmV2Hash(domain, user, password string) []byte {
+	h := md4.New()
+	h.Write(ntlmUnicode(password))
+	return ntlmHMAC(h.Sum(nil), ntlmUnicode(strings.ToUpper(user)+domain))
+}
+
+// ntlmToken returns the decoded NTLM message a request sent with scheme
+func ntlmToken(r *http.Request, scheme string) ([]byte, bool) {
+	name, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(name, scheme) {
+		return nil, false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(token)
+	return decoded, err == nil && len(decoded) >= 12
+}
+
+// newNTLMServer starts a server that challenges every request for NTLM
+// under scheme and accepts user's NTLMv2 response for password. Like IIS,
+// it expects the handshake to stay on one connection.
+func newNTLMServer(t *testing.T, scheme, domain, user, password string) *httptest.Server {
+	t.Helper()
+	serverChallenge := []byte{1, 2, 3, 4, 5, 6, 7, 8}
+	targetInfo := append([]byte{2, 0, byte(len(ntlmUnicode(domain))), 0}, ntlmUnicode(domain)...)
+	targetInfo = append(targetInfo, 0, 0, 0, 0)
+
+	var mu sync.Mutex
+	challenged := make(map[string]bool)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := ntlmToken(r, scheme)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Basic realm=\"intranet\"")
+			w.Header().Add("WWW-Authenticate", scheme)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch binary.LittleEndian.Uint32(token[8:]) {
+		case 1:
+			challenge := make([]byte, 48)
+			copy(challenge, "NTLMSSP\x00")
+			binary.LittleEndian.PutUint32(challenge[8:], 2)
+			// NEGOTIATE_UNICODE, NEGOTIATE_NTLM and NEGOTIATE_TARGET_INFO
+			binary.LittleEndian.PutUint32(challenge[20:], 0x00000001|0x00000200|0x00800000)
+			copy(challenge[24:], serverChallenge)
+			binary.LittleEndian.PutUint16(challenge[40:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint16(challenge[42:], uint16(len(targetInfo)))
+			binary.LittleEndian.PutUint32(challenge[44:], 48)
+			challenge = append(challenge, targetInfo...)
+
+			mu.Lock()
+			challenged[r.RemoteAddr] = true
+			mu.Unlock()
+			w.Header().Set("WWW-Authenticate", scheme+" "+base64.StdEncoding.EncodeToString(challenge))
+			w.WriteHeader(http.StatusUnauthorized)
+		case 3:
+			mu.Lock()
+			sameConn := challenged[r.RemoteAddr]
+			mu.Unlock()
+
+			field := func(offset int) []byte {
+				length := int(binary.LittleEndian.Uint16(token[offset:]))
+				start := int(binary.LittleEndian.Uint32(token[offset+4:]))
+				return token[start : start+length]
+			}
+			ntResponse := field(20)
+			key := ntlmV2Hash(domain, user, password)
+			valid := bytes.Equal(field(28), ntlmUnicode(domain)) &&
+				bytes.Equal(field(36), ntlmUnicode(user)) &&
+				bytes.Equal(ntResponse[:16], ntlmHMAC(key, serverChallenge, ntResponse[16:]))
+			if !sameConn || !valid {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			body, _ := io.ReadAll(r.Body)
+			w.Write([]byte("welcome " + r.Method + " " + string(body)))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPMonitorNTLM(t *testing.T) {
+	tests := []struct {
+		name        string
+		scheme      string
+		password    string
+		wantStatus  int
+		wantMessage string
+	}{
+		{name: "ntlm", scheme: "NTLM", password: "Summer2026!", wantStatus: StatusUp, wantMessage: "HTTP 200"},
+		{name: "negotiate", scheme: "Negotiate", password: "Summer2026!", wantStatus: StatusUp, wantMessage: "HTTP 200"},
+		{name: "wrong password", scheme: "NTLM", password: "Winter2025!", wantStatus: StatusDown, wantMessage: "Unexpected status code: 401"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newNTLMServer(t, tt.scheme, "CORP", "monitor", "Summer2026!")
+			m := &Monitor{URL: server.URL, Timeout: 5, Config: map[string]interface{}{
+				"method":        "POST",
+				"body":          "ping",
+				"keyword":       "welcome POST ping",
+				"auth_type":     "ntlm",
+				"auth_domain":   "CORP",
+				"auth_username": "monitor",
+				"auth_password": tt.password,
+			}}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHTTPMonitorWithoutNTLMIsChallenged(t *testing.T) {
+	server := newNTLMServer(t, "NTLM", "CORP", "monitor", "Summer2026!")
+	hb, err := NewHTTPMonitor(nil).Check(context.Background(), &Monitor{URL: server.URL, Timeout: 5})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusDown || hb.Message != "Unexpected status code: 401" {
+		t.Errorf("got status %d %q, want down with a 401", hb.Status, hb.Message)
+	}
+}
+
+func TestValidateAuthConfig(t *testing.T) {
+	valid := []map[string]interface{}{
+		{},
+		{"auth_type": ""},
+		{"auth_type": "ntlm", "auth_username": "monitor", "auth_password": "secret"},
+		{"auth_type": "ntlm", "auth_domain": "CORP", "auth_username": "monitor", "auth_password": "secret"},
+	}
+	for _, config := range valid {
+		if err := validateAuthConfig(&Monitor{Config: config}); err != nil {
+			t.Errorf("validateAuthConfig(%v) = %v", config, err)
+		}
+	}
+
+	invalid := []map[string]interface{}{
+		{"auth_type": "kerberos"},
+		{"auth_type": 1},
+		{"auth_type": "ntlm", "auth_password": "secret"},
+		{"auth_type": "ntlm", "auth_username": "monitor"},
+		{"auth_type": "ntlm", "auth_username": "monitor", "auth_password": "secret", "auth_domain": 5},
+		{"auth_type": "ntlm", "auth_username": "monitor", "auth_password": "secret", "http3": true},
+	}
+	for _, config := range invalid {
+		if err := validateAuthConfig(&Monitor{Config: config}); err == nil {
+			t.Errorf("validateAuthConfig(%v) succeeded, want error", config)
+		}
+	}
+}
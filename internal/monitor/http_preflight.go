@@ -0,0 +1,209 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// preflightTokenPlaceholder is replaced with the preflight token in the
+// monitor URL and header values
+const preflightTokenPlaceholder = "{{preflight_token}}"
+
+// maxPreflightBody caps how much of the preflight response is read
+const maxPreflightBody = 1 << 20
+
+// httpPreflight is the optional request an HTTP monitor makes before each
+// check to fetch a token for the main request, e.g. logging in:
+//
+//	{"url": "https://auth.example.com/login", "method": "POST",
+//	 "headers": {"Content-Type": "application/json"},
+//	 "body": "{\"user\": \"probe\", \"password\": \"...\"}",
+//	 "token_path": "$.access_token"}
+//
+// The string or number at token_path replaces {{preflight_token}} in the
+// monitor URL (query escaped) and header values.
+type httpPreflight struct {
+	url       string
+	method    string
+	headers   map[string]string
+	body      string
+	tokenPath string
+	steps     []jsonPathStep
+}
+
+// parseHTTPPreflight reads the "preflight" config. It returns nil when no
+// preflight request is configured.
+func parseHTTPPreflight(config map[string]interface{}) (*httpPreflight, error) {
+	raw, ok := config["preflight"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	cfg, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("preflight must be an object")
+	}
+
+	p := &httpPreflight{method: http.MethodGet, headers: make(map[string]string)}
+	p.url, _ = cfg["url"].(string)
+	if !strings.HasPrefix(p.url, "http://") && !strings.HasPrefix(p.url, "https://") {
+		return nil, fmt.Errorf("preflight.url must start with http:// or https://")
+	}
+	if method, _ := cfg["method"].(string); method != "" {
+		p.method = strings.ToUpper(method)
+	}
+	switch headers := cfg["headers"].(type) {
+	case nil:
+	case map[string]interface{}:
+		for k, v := range headers {
+			value, ok := v.(string)
+			if !ok {
+				return nil, fmt.Errorf("preflight.headers.%s must be a string", k)
+			}
+			p.headers[k] = value
+		}
+	default:
+		return nil, fmt.Errorf("preflight.headers must be an object")
+	}
+	if body, ok := cfg["body"]; ok && body != nil {
+		if p.body, ok = body.(string); !ok {
+			return nil, fmt.Errorf("preflight.body must be a string")
+		}
+	}
+
+	p.tokenPath, _ = cfg["token_path"].(string)
+	p.tokenPath = strings.TrimSpace(p.tokenPath)
+	if p.tokenPath == "" {
+		return nil, fmt.Errorf("preflight.token_path is required")
+	}
+	var err error
+	if p.steps, err = parseJSONPath(p.tokenPath); err != nil {
+		return nil, fmt.Errorf("invalid preflight.token_path %q: %w", p.tokenPath, err)
+	}
+	return p, nil
+}
+
+// validateHTTPPreflight checks the preflight config and that the main
+// request uses its token. The token may only go in the URL's path or query,
+// so a response can't pick the host the check connects to.
+func validateHTTPPreflight(monitor *Monitor, headers map[string]string) error {
+	p, err := parseHTTPPreflight(monitor.Config)
+	if err != nil || p == nil {
+		return err
+	}
+	if err := GetConfig().SSRFProtection().ValidateURL(p.url); err != nil {
+		return fmt.Errorf("preflight.url validation failed: %w", err)
+	}
+
+	used := false
+	if i := strings.Index(monitor.URL, preflightTokenPlaceholder); i >= 0 {
+		if i < urlPathStart(monitor.URL) {
+			return fmt.Errorf("%s may only be used in the URL path or query", preflightTokenPlaceholder)
+		}
+		used = true
+	}
+	for _, value := range headers {
+		if strings.Contains(value, preflightTokenPlaceholder) {
+			used = true
+		}
+	}
+	if !used {
+		return fmt.Errorf("preflight needs %s in the URL or a header", preflightTokenPlaceholder)
+	}
+	return nil
+}
+
+// urlPathStart returns the index where an absolute URL's authority ends
+func urlPathStart(rawURL string) int {
+	scheme := strings.Index(rawURL, "://")
+	if scheme < 0 {
+		return 0
+	}
+	authority := scheme + len("://")
+	if end := strings.IndexAny(rawURL[authority:], "/?#"); end >= 0 {
+		return authority + end
+	}
+	return len(rawURL)
+}
+
+// fetchToken makes the preflight request with the check's client and returns
+// the token found in its response
+func (p *httpPreflight) fetchToken(ctx context.Context, client *http.Client, decodeBody bool) (string, error) {
+	if err := revalidateURL(p.url); err != nil {
+		return "", err
+	}
+
+	var reqBody io.Reader
+	if p.body != "" {
+		reqBody = strings.NewReader(p.body)
+	}
+	req, err := http.NewRequestWithContext(ctx, p.method, p.url, reqBody)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %v", err)
+	}
+	for key, value := range p.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	body, err := responseBody(resp, decodeBody)
+	if err != nil {
+		return "", err
+	}
+	data, err := io.ReadAll(io.LimitReader(body, maxPreflightBody))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response body: %v", err)
+	}
+	return p.extractToken(data)
+}
+
+// extractToken returns the string or number at token_path in a JSON body
+func (p *httpPreflight) extractToken(body []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("response is not valid JSON: %v", err)
+	}
+
+	raw, ok := lookupJSONPath(doc, p.steps)
+	if !ok {
+		return "", fmt.Errorf("%s not found in response", p.tokenPath)
+	}
+	var token string
+	switch v := raw.(type) {
+	case string:
+		token = v
+	case json.Number:
+		token = v.String()
+	default:
+		return "", fmt.Errorf("%s is not a string or number", p.tokenPath)
+	}
+	if token == "" {
+		return "", fmt.Errorf("%s is empty", p.tokenPath)
+	}
+	return token, nil
+}
+
+// withPreflightToken returns the main request's URL and headers with the
+// token in place of the placeholder
+func withPreflightToken(rawURL string, headers map[string]string, token string) (string, map[string]string) {
+	injected := make(map[string]string, len(headers))
+	for key, value := range headers {
+		injected[key] = strings.ReplaceAll(value, preflightTokenPlaceholder, token)
+	}
+	return strings.ReplaceAll(rawURL, preflightTokenPlaceholder, url.QueryEscape(token)), injected
+}
@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// redirectAssertion checks the Location of a redirect the monitor does not
+// follow, e.g. that an old URL points at its canonical one
+type redirectAssertion struct {
+	expected string
+	contains bool
+}
+
+// parseRedirectAssertion reads the expected_redirect and
+// expected_redirect_match config. The mode is "equals" (the default) or
+// "contains". It returns nil when no redirect is expected.
+func parseRedirectAssertion(config map[string]interface{}) (*redirectAssertion, error) {
+	var expected string
+	switch v := config["expected_redirect"].(type) {
+	case nil:
+	case string:
+		expected = strings.TrimSpace(v)
+	default:
+		return nil, fmt.Errorf("expected_redirect must be a string")
+	}
+
+	a := &redirectAssertion{expected: expected}
+	switch mode := config["expected_redirect_match"].(type) {
+	case nil:
+	case string:
+		switch mode {
+		case "", "equals":
+		case "contains":
+			a.contains = true
+		default:
+			return nil, fmt.Errorf("expected_redirect_match must be \"equals\" or \"contains\"")
+		}
+	default:
+		return nil, fmt.Errorf("expected_redirect_match must be \"equals\" or \"contains\"")
+	}
+
+	if expected == "" {
+		return nil, nil
+	}
+	return a, nil
+}
+
+// check compares the Location of a 3xx response with the expected value.
+// In "equals" mode both are resolved against the request URL first, so a
+// relative Location matches its absolute form. Other responses pass.
+func (a *redirectAssertion) check(resp *http.Response) error {
+	if resp.StatusCode < 300 || resp.StatusCode > 399 {
+		return nil
+	}
+
+	location := resp.Header.Get("Location")
+	if location == "" {
+		return fmt.Errorf("Redirect %d has no Location header, expected %s", resp.StatusCode, a.expected)
+	}
+
+	if a.contains {
+		if !strings.Contains(location, a.expected) {
+			return fmt.Errorf("Redirect Location '%s' does not contain '%s'", location, a.expected)
+		}
+		return nil
+	}
+
+	if resolveAgainst(resp.Request, location) != resolveAgainst(resp.Request, a.expected) {
+		return fmt.Errorf("Redirect Location '%s' does not match expected '%s'", location, a.expected)
+	}
+	return nil
+}
+
+// resolveAgainst resolves ref against the URL of req, returning ref as is
+// when either can't be used
+func resolveAgainst(req *http.Request, ref string) string {
+	if req == nil || req.URL == nil {
+		return ref
+	}
+	parsed, err := url.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return req.URL.ResolveReference(parsed).String()
+}
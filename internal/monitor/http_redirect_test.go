@@ -0,0 +1,80 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHTTPMonitorExpectedRedirect(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/en/home", http.StatusMovedPermanently)
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:       "relative location equals absolute",
+			config:     map[string]interface{}{"expected_redirect": server.URL + "/en/home"},
+			wantStatus: StatusUp,
+		},
+		{
+			name:        "wrong location",
+			config:      map[string]interface{}{"expected_redirect": "/de/home"},
+			wantStatus:  StatusDown,
+			wantMessage: "Redirect Location '/en/home' does not match expected '/de/home'",
+		},
+		{
+			name:       "contains",
+			config:     map[string]interface{}{"expected_redirect": "/home", "expected_redirect_match": "contains"},
+			wantStatus: StatusUp,
+		},
+		{
+			name:        "contains with wrong location",
+			config:      map[string]interface{}{"expected_redirect": "/fr/", "expected_redirect_match": "contains"},
+			wantStatus:  StatusDown,
+			wantMessage: "Redirect Location '/en/home' does not contain '/fr/'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"follow_redirects": false, "accepted_status_codes": []interface{}{float64(301)}}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+			m := &Monitor{URL: server.URL, Timeout: 5, Config: config}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseRedirectAssertionValidation(t *testing.T) {
+	invalid := []map[string]interface{}{
+		{"expected_redirect": 301.0},
+		{"expected_redirect": "/home", "expected_redirect_match": "prefix"},
+		{"expected_redirect": "/home", "expected_redirect_match": true},
+	}
+	for _, config := range invalid {
+		if _, err := parseRedirectAssertion(config); err == nil {
+			t.Errorf("parseRedirectAssertion(%v) accepted", config)
+		}
+	}
+
+	if a, err := parseRedirectAssertion(map[string]interface{}{"expected_redirect": "  "}); a != nil || err != nil {
+		t.Errorf("blank expected_redirect = %+v, %v, want nil", a, err)
+	}
+}
@@ -0,0 +1,189 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultStabilityMaxChanges is how many changes a window tolerates
+	defaultStabilityMaxChanges = 3
+	// defaultStabilityWindow is the window changes are counted over, in seconds
+	defaultStabilityWindow = 3600
+)
+
+// httpStability flags a response value that keeps changing between checks,
+// e.g. a caching layer flapping between origins or stale copies:
+//
+//	{"source": "json_path", "json_path": "$.build.id", "max_changes": 3, "window": 3600}
+//
+// source is "etag" (the default) or "json_path". Unlike page change
+// detection, a single change is fine; the check fails once the value has
+// changed more than max_changes times within the last window seconds.
+type httpStability struct {
+	source     string
+	path       string
+	steps      []jsonPathStep
+	maxChanges int
+	window     time.Duration
+}
+
+// parseHTTPStability reads the "stability" config. It returns nil when the
+// stability check is not configured.
+func parseHTTPStability(config map[string]interface{}) (*httpStability, error) {
+	raw, ok := config["stability"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	cfg, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("stability must be an object")
+	}
+
+	s := &httpStability{source: "etag", maxChanges: defaultStabilityMaxChanges, window: defaultStabilityWindow * time.Second}
+	if source, _ := cfg["source"].(string); source != "" {
+		s.source = strings.ToLower(source)
+	}
+	switch s.source {
+	case "etag":
+	case "json_path":
+		s.path, _ = cfg["json_path"].(string)
+		s.path = strings.TrimSpace(s.path)
+		if s.path == "" {
+			return nil, fmt.Errorf("stability.json_path is required for the json_path source")
+		}
+		var err error
+		if s.steps, err = parseJSONPath(s.path); err != nil {
+			return nil, fmt.Errorf("invalid stability.json_path %q: %w", s.path, err)
+		}
+	default:
+		return nil, fmt.Errorf("stability.source must be etag or json_path")
+	}
+
+	switch v := cfg["max_changes"].(type) {
+	case nil:
+	case float64:
+		if v != float64(int(v)) || v < 1 {
+			return nil, fmt.Errorf("stability.max_changes must be a whole number of at least 1")
+		}
+		s.maxChanges = int(v)
+	default:
+		return nil, fmt.Errorf("stability.max_changes must be a number")
+	}
+
+	switch v := cfg["window"].(type) {
+	case nil:
+	case float64:
+		if v != float64(int(v)) || v < 1 {
+			return nil, fmt.Errorf("stability.window must be a whole number of seconds")
+		}
+		s.window = time.Duration(v) * time.Second
+	default:
+		return nil, fmt.Errorf("stability.window must be a number")
+	}
+
+	return s, nil
+}
+
+// value returns the value compared across checks, from the ETag header or
+// the JSON body
+func (s *httpStability) value(resp *http.Response, body []byte) (string, error) {
+	if s.source == "etag" {
+		etag := resp.Header.Get("ETag")
+		if etag == "" {
+			return "", fmt.Errorf("response has no ETag header")
+		}
+		return etag, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("response is not valid JSON: %v", err)
+	}
+	raw, ok := lookupJSONPath(doc, s.steps)
+	if !ok {
+		return "", fmt.Errorf("%s not found in response", s.path)
+	}
+	// Objects and arrays compare by their encoding, which sorts keys
+	encoded, err := json.Marshal(raw)
+	if err != nil {
+		return "", fmt.Errorf("%s cannot be compared: %v", s.path, err)
+	}
+	return string(encoded), nil
+}
+
+// stabilityWindow is the last value seen for a monitor and when it changed
+// within the window
+type stabilityWindow struct {
+	last    string
+	changes []time.Time
+}
+
+// stabilityTracker holds the rolling windows of every monitor using the
+// stability check. Windows live in memory and start empty after a restart.
+type stabilityTracker struct {
+	mu      sync.Mutex
+	windows map[int]*stabilityWindow
+}
+
+// record notes a monitor's value at now and returns how many times it has
+// changed within the window. At most maxChanges+1 changes are kept.
+func (t *stabilityTracker) record(monitorID int, value string, s *httpStability, now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.windows == nil {
+		t.windows = make(map[int]*stabilityWindow)
+	}
+
+	w, ok := t.windows[monitorID]
+	if !ok {
+		t.windows[monitorID] = &stabilityWindow{last: value}
+		return 0
+	}
+
+	cutoff := now.Add(-s.window)
+	kept := w.changes[:0]
+	for _, at := range w.changes {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	w.changes = kept
+
+	if value != w.last {
+		w.last = value
+		w.changes = append(w.changes, now)
+		if len(w.changes) > s.maxChanges+1 {
+			w.changes = w.changes[len(w.changes)-s.maxChanges-1:]
+		}
+	}
+	return len(w.changes)
+}
+
+// check records the response's value and returns an error once it has
+// changed more than max_changes times within the window
+func (t *stabilityTracker) check(monitorID int, s *httpStability, resp *http.Response, body []byte, now time.Time) error {
+	value, err := s.value(resp, body)
+	if err != nil {
+		return err
+	}
+	if changes := t.record(monitorID, value, s, now); changes > s.maxChanges {
+		return fmt.Errorf("Unstable response: %s changed more than %d times in %s", s.label(), s.maxChanges, s.window)
+	}
+	return nil
+}
+
+// label names the compared value in heartbeat messages
+func (s *httpStability) label() string {
+	if s.source == "etag" {
+		return "ETag"
+	}
+	return s.path
+}
@@ -0,0 +1,110 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPMonitorStabilityFlagsFlappingValue(t *testing.T) {
+	SetConfig(&MonitorConfig{AllowPrivateIPs: true})
+	defer SetConfig(nil)
+
+	// Alternates between two origins' builds, as a misrouted cache would
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		build := requests.Add(1) % 2
+		w.Header().Set("ETag", fmt.Sprintf(`"build-%d"`, build))
+		fmt.Fprintf(w, `{"build": {"id": "b%d"}, "served_at": %d}`, build, time.Now().UnixNano())
+	}))
+	defer server.Close()
+
+	for _, source := range []map[string]interface{}{
+		{"source": "etag", "max_changes": 2.0, "window": 600.0},
+		{"source": "json_path", "json_path": "$.build.id", "max_changes": 2.0, "window": 600.0},
+	} {
+		t.Run(source["source"].(string), func(t *testing.T) {
+			h := NewHTTPMonitor(nil)
+			m := &Monitor{ID: 1, Timeout: 5, URL: server.URL, Config: map[string]interface{}{"stability": source}}
+
+			// The first check sets the baseline, the next two changes are tolerated
+			for i := 0; i < 3; i++ {
+				hb, err := h.Check(context.Background(), m)
+				if err != nil {
+					t.Fatalf("Check() error = %v", err)
+				}
+				if hb.Status != StatusUp {
+					t.Fatalf("check %d: status = %d (%s), want up", i+1, hb.Status, hb.Message)
+				}
+			}
+
+			hb, err := h.Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if hb.Status != StatusDown || !strings.HasPrefix(hb.Message, "Unstable response: ") {
+				t.Errorf("third change: got %d %q, want down as unstable", hb.Status, hb.Message)
+			}
+		})
+	}
+}
+
+func TestStabilityTrackerWindow(t *testing.T) {
+	s := &httpStability{source: "etag", maxChanges: 1, window: time.Minute}
+	tracker := &stabilityTracker{}
+	start := time.Now()
+
+	if got := tracker.record(1, "a", s, start); got != 0 {
+		t.Errorf("baseline: %d changes, want 0", got)
+	}
+	if got := tracker.record(1, "a", s, start.Add(10*time.Second)); got != 0 {
+		t.Errorf("same value: %d changes, want 0", got)
+	}
+	tracker.record(1, "b", s, start.Add(20*time.Second))
+	if got := tracker.record(1, "a", s, start.Add(30*time.Second)); got != 2 {
+		t.Errorf("flapping: %d changes, want 2", got)
+	}
+	// Both changes age out of the window
+	if got := tracker.record(1, "a", s, start.Add(2*time.Minute)); got != 0 {
+		t.Errorf("after the window: %d changes, want 0", got)
+	}
+	// Monitors have their own windows
+	if got := tracker.record(2, "b", s, start.Add(2*time.Minute)); got != 0 {
+		t.Errorf("other monitor: %d changes, want 0", got)
+	}
+}
+
+func TestParseHTTPStability(t *testing.T) {
+	none, err := parseHTTPStability(map[string]interface{}{})
+	if none != nil || err != nil {
+		t.Errorf("unconfigured = %v, %v; want nil", none, err)
+	}
+
+	defaults, err := parseHTTPStability(map[string]interface{}{"stability": map[string]interface{}{}})
+	if err != nil {
+		t.Fatalf("parseHTTPStability: %v", err)
+	}
+	if defaults.source != "etag" || defaults.maxChanges != defaultStabilityMaxChanges || defaults.window != defaultStabilityWindow*time.Second {
+		t.Errorf("defaults = %+v", defaults)
+	}
+
+	for _, cfg := range []interface{}{
+		"etag",
+		map[string]interface{}{"source": "last-modified"},
+		map[string]interface{}{"source": "json_path"},
+		map[string]interface{}{"source": "json_path", "json_path": "build.id"},
+		map[string]interface{}{"source": "json_path", "json_path": "$.items[*].id"},
+		map[string]interface{}{"max_changes": 0.0},
+		map[string]interface{}{"max_changes": 1.5},
+		map[string]interface{}{"window": "1h"},
+	} {
+		if _, err := parseHTTPStability(map[string]interface{}{"stability": cfg}); err == nil {
+			t.Errorf("parseHTTPStability(%v) succeeded, want error", cfg)
+		}
+	}
+}
@@ -0,0 +1,280 @@
+package monitor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTLS11Server starts an HTTPS server that only speaks TLS 1.0/1.1
+func newTLS11Server(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		MinVersion: tls.VersionTLS10,
+		MaxVersion: tls.VersionTLS11,
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPMonitorMinTLSVersion(t *testing.T) {
+	server := newTLS11Server(t)
+
+	tests := []struct {
+		name        string
+		minVersion  string
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "below minimum fails",
+			minVersion:  "1.2",
+			wantStatus:  StatusDown,
+			wantMessage: "server does not support TLS 1.2 or higher",
+		},
+		{
+			name:        "meets minimum",
+			minVersion:  "1.1",
+			wantStatus:  StatusUp,
+			wantMessage: "TLS 1.1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{
+				ID:      1,
+				URL:     server.URL,
+				Timeout: 5,
+				Config: map[string]interface{}{
+					"ignore_tls":      true,
+					"min_tls_version": tt.minVersion,
+				},
+			}
+
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if hb.Status != tt.wantStatus {
+				t.Fatalf("status = %d (%s), want %d", hb.Status, hb.Message, tt.wantStatus)
+			}
+			if !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Fatalf("message = %q, want it to contain %q", hb.Message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestParseTLSVersion(t *testing.T) {
+	valid := map[string]uint16{
+		"":       0,
+		"1.0":    tls.VersionTLS10,
+		"1.2":    tls.VersionTLS12,
+		"TLS1.3": tls.VersionTLS13,
+	}
+	for input, want := range valid {
+		got, err := parseTLSVersion(input)
+		if err != nil || got != want {
+			t.Errorf("parseTLSVersion(%q) = %d, %v; want %d", input, got, err, want)
+		}
+	}
+
+	for _, input := range []string{"1.4", "ssl3", "12"} {
+		if _, err := parseTLSVersion(input); err == nil {
+			t.Errorf("parseTLSVersion(%q) succeeded, want error", input)
+		}
+	}
+}
+
+// newPrivateCAServer starts an HTTPS server whose certificate is issued by a
+// freshly generated CA, and returns the CA certificate as PEM
+func newPrivateCAServer(t *testing.T) (*httptest.Server, string) {
+	t.Helper()
+
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Private CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		KeyUsage:              x509.KeyUsageCertSign,
+		BasicConstraintsValid: true,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leafKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafTemplate := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "internal.test"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+	}
+	leafDER, err := x509.CreateCertificate(rand.Reader, leafTemplate, caCert, &leafKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{leafDER}, PrivateKey: leafKey}},
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	return server, string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+}
+
+func TestHTTPMonitorCACert(t *testing.T) {
+	server, caPEM := newPrivateCAServer(t)
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "untrusted without ca_cert",
+			config:      map[string]interface{}{},
+			wantStatus:  StatusDown,
+			wantMessage: "certificate signed by unknown authority",
+		},
+		{
+			name:        "verified with ca_cert",
+			config:      map[string]interface{}{"ca_cert": caPEM},
+			wantStatus:  StatusUp,
+			wantMessage: "HTTP 200",
+		},
+		{
+			name:        "unparsable ca_cert",
+			config:      map[string]interface{}{"ca_cert": "not a certificate"},
+			wantStatus:  StatusDown,
+			wantMessage: "Failed to parse ca_cert",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{ID: 1, URL: server.URL, Timeout: 5, Config: tt.config}
+
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Fatalf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestValidateCACertConfig(t *testing.T) {
+	_, caPEM := newPrivateCAServer(t)
+
+	for _, config := range []map[string]interface{}{{}, {"ca_cert": ""}, {"ca_cert": caPEM}} {
+		if err := validateCACertConfig(&Monitor{Config: config}); err != nil {
+			t.Errorf("validateCACertConfig(%v): %v", config, err)
+		}
+	}
+	for _, config := range []map[string]interface{}{{"ca_cert": 42}, {"ca_cert": "-----BEGIN CERTIFICATE-----\nnope\n-----END CERTIFICATE-----"}} {
+		if err := validateCACertConfig(&Monitor{Config: config}); err == nil {
+			t.Errorf("validateCACertConfig(%v) succeeded, want error", config)
+		}
+	}
+}
+
+func TestHTTPMonitorTLSServerName(t *testing.T) {
+	var gotSNI, gotHost string
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHost = r.Host
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			gotSNI = hello.ServerName
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	t.Cleanup(server.Close)
+
+	tests := []struct {
+		serverName string
+		wantSNI    string
+	}{
+		{"", ""}, // an IP address URL sends no SNI
+		{"tenant-a.example.com", "tenant-a.example.com"},
+		{"Tenant-B.Example.com.", "tenant-b.example.com"},
+	}
+	for _, tt := range tests {
+		gotSNI, gotHost = "unset", ""
+		m := &Monitor{ID: 1, URL: server.URL, Timeout: 5, Config: map[string]interface{}{
+			"ignore_tls":      true,
+			"tls_server_name": tt.serverName,
+		}}
+
+		hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+		if err != nil {
+			t.Fatalf("Check() error = %v", err)
+		}
+		if hb.Status != StatusUp {
+			t.Fatalf("tls_server_name %q: status %d (%s), want up", tt.serverName, hb.Status, hb.Message)
+		}
+		if gotSNI != tt.wantSNI {
+			t.Errorf("tls_server_name %q: server saw SNI %q, want %q", tt.serverName, gotSNI, tt.wantSNI)
+		}
+		if gotHost != strings.TrimPrefix(server.URL, "https://") {
+			t.Errorf("tls_server_name %q: Host header %q, want the URL host", tt.serverName, gotHost)
+		}
+	}
+}
+
+func TestValidateTLSServerNameConfig(t *testing.T) {
+	for _, name := range []interface{}{nil, "", "vhost.example.com", "Internal_API.corp.", "localhost"} {
+		if err := validateTLSServerNameConfig(&Monitor{Config: map[string]interface{}{"tls_server_name": name}}); err != nil {
+			t.Errorf("validateTLSServerNameConfig(%v): %v", name, err)
+		}
+	}
+	for _, name := range []interface{}{42, "10.0.0.1", "::1", "bad..host", "-lead.example.com", "host:443", "https://vhost.example.com", "*.example.com"} {
+		if err := validateTLSServerNameConfig(&Monitor{Config: map[string]interface{}{"tls_server_name": name}}); err == nil {
+			t.Errorf("validateTLSServerNameConfig(%v) succeeded, want error", name)
+		}
+	}
+}
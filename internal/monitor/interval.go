@@ -0,0 +1,18 @@
+package monitor
+
+import "fmt"
+
+// ValidateInterval checks a monitor's check interval against the configured
+// MinInterval, so a monitor can't hammer its target. Admins may go below the
+// floor, and so may a monitor whose interval is unchanged (previous), which
+// keeps monitors created before the floor editable.
+func ValidateInterval(monitor *Monitor, admin bool, previous int) error {
+	if monitor.Interval < 1 {
+		return fmt.Errorf("interval must be at least 1 second")
+	}
+	floor := GetConfig().MinInterval
+	if floor <= 0 || admin || monitor.Interval >= floor || monitor.Interval == previous {
+		return nil
+	}
+	return fmt.Errorf("interval must be at least %d seconds (got %d); ask an admin for a shorter interval", floor, monitor.Interval)
+}
@@ -0,0 +1,46 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateIntervalFloor(t *testing.T) {
+	SetConfig(&MonitorConfig{MinInterval: 20})
+	defer SetConfig(nil)
+
+	for _, interval := range []int{1, 5, 19} {
+		err := ValidateInterval(&Monitor{Interval: interval}, false, 0)
+		if err == nil || !strings.Contains(err.Error(), "at least 20 seconds") {
+			t.Errorf("interval %d: err = %v, want it rejected below the 20s floor", interval, err)
+		}
+	}
+	for _, interval := range []int{20, 60} {
+		if err := ValidateInterval(&Monitor{Interval: interval}, false, 0); err != nil {
+			t.Errorf("interval %d: %v", interval, err)
+		}
+	}
+
+	// Admins may go below the floor, and existing monitors keep their interval
+	if err := ValidateInterval(&Monitor{Interval: 5}, true, 0); err != nil {
+		t.Errorf("admin: %v", err)
+	}
+	if err := ValidateInterval(&Monitor{Interval: 5}, false, 5); err != nil {
+		t.Errorf("unchanged interval: %v", err)
+	}
+	if err := ValidateInterval(&Monitor{Interval: 4}, false, 5); err == nil {
+		t.Error("lowering an interval already below the floor was accepted")
+	}
+	if err := ValidateInterval(&Monitor{Interval: 0}, true, 0); err == nil {
+		t.Error("interval 0 was accepted")
+	}
+}
+
+func TestValidateIntervalWithoutFloor(t *testing.T) {
+	SetConfig(&MonitorConfig{})
+	defer SetConfig(nil)
+
+	if err := ValidateInterval(&Monitor{Interval: 1}, false, 0); err != nil {
+		t.Errorf("no floor: %v", err)
+	}
+}
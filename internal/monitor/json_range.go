@@ -0,0 +1,270 @@
+package monitor
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is one segment of a parsed JSONPath: an object key or an
+// array index
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// parseJSONPath parses the JSONPath subset used by json_path: "$" followed by
+// .key, ['key'], ["key"] and [n] segments, e.g. $.sla.targets[0].latency_ms.
+// Wildcards, slices and filters select several values and aren't supported.
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("must start with $")
+	}
+
+	var steps []jsonPathStep
+	rest := path[1:]
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			key := rest[:end]
+			if key == "" {
+				return nil, fmt.Errorf("empty key")
+			}
+			if key == "*" {
+				return nil, fmt.Errorf("wildcards are not supported")
+			}
+			steps = append(steps, jsonPathStep{key: key})
+			rest = rest[end:]
+
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("unclosed [")
+			}
+			inner := strings.TrimSpace(rest[1:end])
+			if len(inner) >= 2 && (inner[0] == '\'' || inner[0] == '"') && inner[len(inner)-1] == inner[0] {
+				steps = append(steps, jsonPathStep{key: inner[1 : len(inner)-1]})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil || index < 0 {
+					return nil, fmt.Errorf("[%s] must be a quoted key or a non-negative index", inner)
+				}
+				steps = append(steps, jsonPathStep{index: index, isIndex: true})
+			}
+			rest = rest[end+1:]
+
+		default:
+			return nil, fmt.Errorf("unexpected %q", rest[0])
+		}
+	}
+
+	if len(steps) == 0 {
+		return nil, fmt.Errorf("must select a value below $")
+	}
+	return steps, nil
+}
+
+// lookupJSONPath returns the value at steps in a decoded JSON document
+func lookupJSONPath(doc interface{}, steps []jsonPathStep) (interface{}, bool) {
+	value := doc
+	for _, step := range steps {
+		if step.isIndex {
+			list, ok := value.([]interface{})
+			if !ok || step.index >= len(list) {
+				return nil, false
+			}
+			value = list[step.index]
+			continue
+		}
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if value, ok = object[step.key]; !ok {
+			return nil, false
+		}
+	}
+	return value, true
+}
+
+// jsonRange checks that the number at a JSONPath of the response body lies
+// within [min, max], either bound may be omitted, or that the value there
+// equals expected.
+type jsonRange struct {
+	path     string
+	steps    []jsonPathStep
+	min      *float64
+	max      *float64
+	expected *string
+}
+
+// parseJSONRange reads the json_path, json_min, json_max and expected_value
+// config. It returns nil when json_path is not set.
+func parseJSONRange(config map[string]interface{}) (*jsonRange, error) {
+	raw, ok := config["json_path"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	path, ok := raw.(string)
+	if !ok {
+		return nil, fmt.Errorf("json_path must be a string")
+	}
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return nil, nil
+	}
+
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("invalid json_path %q: %w", path, err)
+	}
+	r := &jsonRange{path: path, steps: steps}
+
+	if r.min, err = jsonRangeBound(config, "json_min"); err != nil {
+		return nil, err
+	}
+	if r.max, err = jsonRangeBound(config, "json_max"); err != nil {
+		return nil, err
+	}
+	if r.expected, err = jsonExpectedValue(config); err != nil {
+		return nil, err
+	}
+	if r.expected != nil {
+		if r.min != nil || r.max != nil {
+			return nil, fmt.Errorf("expected_value cannot be combined with json_min or json_max")
+		}
+		return r, nil
+	}
+	if r.min == nil && r.max == nil {
+		return nil, fmt.Errorf("json_path needs expected_value, json_min, json_max or both bounds")
+	}
+	if r.min != nil && r.max != nil && *r.min > *r.max {
+		return nil, fmt.Errorf("json_min must not be greater than json_max")
+	}
+	return r, nil
+}
+
+// jsonRangeBound reads an optional numeric bound
+func jsonRangeBound(config map[string]interface{}, key string) (*float64, error) {
+	var bound float64
+	switch v := config[key].(type) {
+	case nil:
+		return nil, nil
+	case float64:
+		bound = v
+	case int:
+		bound = float64(v)
+	default:
+		return nil, fmt.Errorf("%s must be a number", key)
+	}
+	return &bound, nil
+}
+
+// jsonExpectedValue reads expected_value, kept as the text it is compared as:
+// a string, or a number or boolean as written in JSON
+func jsonExpectedValue(config map[string]interface{}) (*string, error) {
+	var expected string
+	switch v := config["expected_value"].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		expected = v
+	case float64:
+		expected = formatJSONNumber(v)
+	case int:
+		expected = strconv.Itoa(v)
+	case bool:
+		expected = strconv.FormatBool(v)
+	default:
+		return nil, fmt.Errorf("expected_value must be a string, number or boolean")
+	}
+	return &expected, nil
+}
+
+// jsonScalarText returns a scalar JSON value as text: strings as they are,
+// numbers and booleans as written, and null as "null"
+func jsonScalarText(value interface{}) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "null", true
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
+
+// matches reports whether a scalar JSON value, written as text, equals the
+// expected value. Numbers are compared by value, so 1.0 matches an expected 1.
+func (r *jsonRange) matches(value interface{}, text string) bool {
+	if number, isNumber := value.(json.Number); isNumber {
+		actual, err := number.Float64()
+		expected, expectedErr := strconv.ParseFloat(*r.expected, 64)
+		if err == nil && expectedErr == nil {
+			return actual == expected
+		}
+	}
+	return text == *r.expected
+}
+
+// check reads the value at the path from body and compares it to the bounds
+// or the expected value. It returns the value as text for the heartbeat
+// message, and an error describing why the check failed.
+func (r *jsonRange) check(body []byte) (string, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var doc interface{}
+	if err := decoder.Decode(&doc); err != nil {
+		return "", fmt.Errorf("response is not valid JSON: %v", err)
+	}
+
+	raw, ok := lookupJSONPath(doc, r.steps)
+	if !ok {
+		return "", fmt.Errorf("%s not found in response", r.path)
+	}
+
+	if r.expected != nil {
+		text, ok := jsonScalarText(raw)
+		if !ok {
+			return "", fmt.Errorf("%s is an object or array, expected %q", r.path, *r.expected)
+		}
+		if !r.matches(raw, text) {
+			return text, fmt.Errorf("%s = %q, expected %q", r.path, text, *r.expected)
+		}
+		return text, nil
+	}
+
+	number, ok := raw.(json.Number)
+	if !ok {
+		return "", fmt.Errorf("%s is not a number", r.path)
+	}
+	value, err := number.Float64()
+	if err != nil {
+		return "", fmt.Errorf("%s is not a valid number: %v", r.path, err)
+	}
+
+	text := formatJSONNumber(value)
+	if r.min != nil && value < *r.min {
+		return text, fmt.Errorf("%s = %s, below minimum %s", r.path, text, formatJSONNumber(*r.min))
+	}
+	if r.max != nil && value > *r.max {
+		return text, fmt.Errorf("%s = %s, above maximum %s", r.path, text, formatJSONNumber(*r.max))
+	}
+	return text, nil
+}
+
+// formatJSONNumber formats a value for heartbeat messages
+func formatJSONNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
@@ -0,0 +1,196 @@
+package monitor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// newJSONServer serves body as application/json
+func newJSONServer(t *testing.T, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHTTPMonitorJSONRange(t *testing.T) {
+	server := newJSONServer(t, `{"sla": {"uptime": 99.95, "targets": [{"latency_ms": 420}]}, "status": "ok"}`)
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "in range",
+			config:      map[string]interface{}{"json_path": "$.sla.uptime", "json_min": 99.9, "json_max": 100.0},
+			wantStatus:  StatusUp,
+			wantMessage: "$.sla.uptime = 99.95",
+		},
+		{
+			name:        "bounds are inclusive",
+			config:      map[string]interface{}{"json_path": "$.sla.targets[0]['latency_ms']", "json_max": 420.0},
+			wantStatus:  StatusUp,
+			wantMessage: "= 420",
+		},
+		{
+			name:        "below minimum",
+			config:      map[string]interface{}{"json_path": "$.sla.uptime", "json_min": 99.99},
+			wantStatus:  StatusDown,
+			wantMessage: "$.sla.uptime = 99.95, below minimum 99.99",
+		},
+		{
+			name:        "above maximum",
+			config:      map[string]interface{}{"json_path": "$.sla.targets[0].latency_ms", "json_max": 250.0},
+			wantStatus:  StatusDown,
+			wantMessage: "above maximum 250",
+		},
+		{
+			name:        "missing value",
+			config:      map[string]interface{}{"json_path": "$.sla.targets[3].latency_ms", "json_max": 250.0},
+			wantStatus:  StatusDown,
+			wantMessage: "not found in response",
+		},
+		{
+			name:        "not a number",
+			config:      map[string]interface{}{"json_path": "$.status", "json_min": 1.0},
+			wantStatus:  StatusDown,
+			wantMessage: "$.status is not a number",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{URL: server.URL, Timeout: 5, Config: tt.config}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHTTPMonitorJSONExpectedValue(t *testing.T) {
+	server := newJSONServer(t, `{"status": "ok", "checks": {"db": "degraded", "replicas": 3.0, "healthy": true, "error": null}, "items": []}`)
+
+	tests := []struct {
+		name        string
+		config      map[string]interface{}
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "string matches",
+			config:      map[string]interface{}{"json_path": "$.status", "expected_value": "ok"},
+			wantStatus:  StatusUp,
+			wantMessage: "$.status = ok",
+		},
+		{
+			name:        "string differs",
+			config:      map[string]interface{}{"json_path": "$.checks.db", "expected_value": "ok"},
+			wantStatus:  StatusDown,
+			wantMessage: `$.checks.db = "degraded", expected "ok"`,
+		},
+		{
+			name:        "numbers compare by value",
+			config:      map[string]interface{}{"json_path": "$.checks.replicas", "expected_value": "3"},
+			wantStatus:  StatusUp,
+			wantMessage: "$.checks.replicas = 3.0",
+		},
+		{
+			name:       "boolean",
+			config:     map[string]interface{}{"json_path": "$.checks.healthy", "expected_value": true},
+			wantStatus: StatusUp,
+		},
+		{
+			name:       "null",
+			config:     map[string]interface{}{"json_path": "$.checks.error", "expected_value": "null"},
+			wantStatus: StatusUp,
+		},
+		{
+			name:        "not a scalar",
+			config:      map[string]interface{}{"json_path": "$.items", "expected_value": "ok"},
+			wantStatus:  StatusDown,
+			wantMessage: "$.items is an object or array",
+		},
+		{
+			name:        "alongside a keyword",
+			config:      map[string]interface{}{"json_path": "$.status", "expected_value": "ok", "keyword": "maintenance"},
+			wantStatus:  StatusDown,
+			wantMessage: "Keyword 'maintenance' not found",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := &Monitor{URL: server.URL, Timeout: 5, Config: tt.config}
+			hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.Contains(hb.Message, tt.wantMessage) {
+				t.Errorf("got status %d %q, want %d containing %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestHTTPMonitorJSONRangeInvalidBody(t *testing.T) {
+	server := newJSONServer(t, "<html>maintenance</html>")
+
+	m := &Monitor{URL: server.URL, Timeout: 5, Config: map[string]interface{}{"json_path": "$.value", "json_min": 0.0}}
+	hb, err := NewHTTPMonitor(nil).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusDown || !strings.HasPrefix(hb.Message, "response is not valid JSON") {
+		t.Errorf("got status %d %q, want down for a non-JSON body", hb.Status, hb.Message)
+	}
+}
+
+func TestParseJSONRange(t *testing.T) {
+	valid := []map[string]interface{}{
+		{},
+		{"json_path": ""},
+		{"json_path": "$.a", "json_min": 1.0},
+		{"json_path": "$['a b'][2].c", "json_min": 1, "json_max": 1},
+		{"json_path": "$.status", "expected_value": "ok"},
+		{"json_path": "$.count", "expected_value": 3.0},
+		{"json_path": "$.healthy", "expected_value": true},
+	}
+	for _, config := range valid {
+		if _, err := parseJSONRange(config); err != nil {
+			t.Errorf("parseJSONRange(%v): %v", config, err)
+		}
+	}
+
+	invalid := []map[string]interface{}{
+		{"json_path": 5, "json_min": 1.0},
+		{"json_path": "a.b", "json_min": 1.0},
+		{"json_path": "$", "json_min": 1.0},
+		{"json_path": "$.a[", "json_min": 1.0},
+		{"json_path": "$.a[-1]", "json_min": 1.0},
+		{"json_path": "$.a.*", "json_min": 1.0},
+		{"json_path": "$..a", "json_min": 1.0},
+		{"json_path": "$.a"},
+		{"json_path": "$.a", "json_min": "1"},
+		{"json_path": "$.a", "json_min": 10.0, "json_max": 1.0},
+		{"json_path": "$.a", "expected_value": []interface{}{"ok"}},
+		{"json_path": "$.a", "expected_value": "ok", "json_max": 1.0},
+	}
+	for _, config := range invalid {
+		if _, err := parseJSONRange(config); err == nil {
+			t.Errorf("parseJSONRange(%v) succeeded, want error", config)
+		}
+	}
+}
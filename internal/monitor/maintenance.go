@@ -0,0 +1,73 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+// loadMaintenanceWindows loads the maintenance windows that are under way or
+// ahead, so checks can be put into maintenance without a query each
+func (e *Executor) loadMaintenanceWindows() error {
+	var windows []models.MaintenanceWindow
+	if err := e.db.Where("weekly = ? OR ends_at > ?", true, time.Now()).Find(&windows).Error; err != nil {
+		return err
+	}
+
+	byMonitor := make(map[int][]models.MaintenanceWindow)
+	for _, window := range windows {
+		byMonitor[window.MonitorID] = append(byMonitor[window.MonitorID], window)
+	}
+
+	e.mu.Lock()
+	e.maintenance = byMonitor
+	e.mu.Unlock()
+	return nil
+}
+
+// SetMaintenanceWindows replaces a monitor's maintenance windows, after they
+// were created, changed or deleted
+func (e *Executor) SetMaintenanceWindows(monitorID int, windows []models.MaintenanceWindow) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(windows) == 0 {
+		delete(e.maintenance, monitorID)
+		return
+	}
+	if e.maintenance == nil {
+		e.maintenance = make(map[int][]models.MaintenanceWindow)
+	}
+	e.maintenance[monitorID] = windows
+}
+
+// activeMaintenance returns the maintenance window the monitor is in at t,
+// or nil
+func (e *Executor) activeMaintenance(monitorID int, t time.Time) *models.MaintenanceWindow {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	for _, window := range e.maintenance[monitorID] {
+		if _, _, ok := window.Occurrence(t); ok {
+			return &window
+		}
+	}
+	return nil
+}
+
+// applyMaintenance records a heartbeat within a maintenance window as
+// maintenance, whatever the check found, so planned work sends no alerts
+// and the monitor enters and leaves maintenance once
+func (job *monitorJob) applyMaintenance(heartbeat *Heartbeat) {
+	if heartbeat.Status == StatusMaintenance {
+		return
+	}
+	window := job.executor.activeMaintenance(job.monitor.ID, heartbeat.Time)
+	if window == nil {
+		return
+	}
+	heartbeat.Status = StatusMaintenance
+	heartbeat.Warning = false
+	heartbeat.Message = fmt.Sprintf("Maintenance (%s): %s", window.Title, heartbeat.Message)
+}
@@ -0,0 +1,67 @@
+package monitor
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fuomag9/uptime-kabomba/internal/models"
+)
+
+func TestRecordWithinMaintenanceWindow(t *testing.T) {
+	var mu sync.Mutex
+	var saved []*Heartbeat
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error {
+		mu.Lock()
+		defer mu.Unlock()
+		saved = append(saved, heartbeat)
+		return nil
+	}
+
+	now := time.Now()
+	e.SetMaintenanceWindows(5, []models.MaintenanceWindow{
+		{MonitorID: 5, Title: "DB upgrade", StartsAt: now.Add(-time.Hour), EndsAt: now.Add(time.Hour)},
+	})
+	job := &monitorJob{monitor: &Monitor{ID: 5, Name: "db"}, executor: e, lastStatus: StatusUp}
+
+	job.record(&Heartbeat{MonitorID: 5, Status: StatusDown, Message: "connection refused", Time: now})
+	decision := job.evaluate(StatusDown, now.Add(2*time.Hour))
+
+	mu.Lock()
+	defer mu.Unlock()
+	hb := saved[0]
+	if hb.Status != StatusMaintenance || !strings.HasPrefix(hb.Message, "Maintenance (DB upgrade): ") {
+		t.Errorf("got status %d %q, want maintenance", hb.Status, hb.Message)
+	}
+	if job.consecutiveFailures != 1 || !decision.notifyDown {
+		t.Errorf("the first failure after the window = %d failures, notify %v; want it to alert", job.consecutiveFailures, decision.notifyDown)
+	}
+}
+
+func TestRecordOutsideMaintenanceWindow(t *testing.T) {
+	e := NewExecutor(nil, nil, nil, 0)
+	var saved *Heartbeat
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error {
+		saved = heartbeat
+		return nil
+	}
+
+	now := time.Now()
+	e.SetMaintenanceWindows(5, []models.MaintenanceWindow{
+		{MonitorID: 5, Title: "tomorrow", StartsAt: now.Add(24 * time.Hour), EndsAt: now.Add(25 * time.Hour)},
+	})
+	job := &monitorJob{monitor: &Monitor{ID: 5, Name: "db"}, executor: e, lastStatus: StatusUp}
+
+	job.record(&Heartbeat{MonitorID: 5, Status: StatusDown, Message: "connection refused", Time: now})
+	if saved.Status != StatusDown {
+		t.Errorf("status = %d before the window, want down", saved.Status)
+	}
+
+	// Removing the windows leaves nothing behind
+	e.SetMaintenanceWindows(5, nil)
+	if _, ok := e.maintenance[5]; ok {
+		t.Error("monitor still has maintenance windows after they were removed")
+	}
+}
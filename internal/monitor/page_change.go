@@ -68,7 +68,7 @@ func (p *PageChangeMonitor) Validate(monitor *Monitor) error {
 
 	// SSRF protection
 	cfg := GetConfig()
-	ssrfProtection := NewSSRFProtection(cfg.AllowPrivateIPs, cfg.AllowMetadataEndpoints)
+	ssrfProtection := cfg.SSRFProtection()
 	if err := ssrfProtection.ValidateURL(monitor.URL); err != nil {
 		return fmt.Errorf("URL validation failed: %w", err)
 	}
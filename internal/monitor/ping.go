@@ -49,6 +49,7 @@ func (p *PingMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat,
 	if err != nil {
 		heartbeat.Status = StatusDown
 		heartbeat.Message = fmt.Sprintf("Failed to create pinger: %v", err)
+		heartbeat.HardError = isHardError(err)
 		return heartbeat, nil
 	}
 
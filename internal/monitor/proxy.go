@@ -0,0 +1,156 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"golang.org/x/net/proxy"
+)
+
+// ParseProxyURL parses and validates a proxy_url monitor setting.
+// Supported schemes are http, https, socks5 and socks5h.
+func ParseProxyURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy_url: %w", err)
+	}
+
+	switch u.Scheme {
+	case "http", "https", "socks5", "socks5h":
+	default:
+		return nil, fmt.Errorf("proxy_url scheme must be http, https, socks5 or socks5h")
+	}
+
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("proxy_url must have a hostname")
+	}
+
+	return u, nil
+}
+
+// RedactProxyURL hides the password in a proxy URL so it can be returned to clients
+func RedactProxyURL(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.User == nil {
+		return raw
+	}
+	return u.Redacted()
+}
+
+// getProxyURL returns the monitor's configured proxy, or nil if none is set
+func getProxyURL(monitor *Monitor) (*url.URL, error) {
+	raw, _ := monitor.Config["proxy_url"].(string)
+	if raw == "" {
+		return nil, nil
+	}
+	return ParseProxyURL(raw)
+}
+
+// validateProxyConfig checks the proxy_url setting, if present
+func validateProxyConfig(monitor *Monitor) error {
+	raw, ok := monitor.Config["proxy_url"]
+	if !ok {
+		return nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("proxy_url must be a string")
+	}
+	if s == "" {
+		return nil
+	}
+	_, err := ParseProxyURL(s)
+	return err
+}
+
+// dialThroughProxy opens a TCP connection to addr tunnelled through proxyURL.
+// dialer is used for the connection to the proxy itself.
+func dialThroughProxy(ctx context.Context, dialer *net.Dialer, network string, proxyURL *url.URL, addr string) (net.Conn, error) {
+	switch proxyURL.Scheme {
+	case "socks5", "socks5h":
+		var auth *proxy.Auth
+		if proxyURL.User != nil {
+			password, _ := proxyURL.User.Password()
+			auth = &proxy.Auth{User: proxyURL.User.Username(), Password: password}
+		}
+		socks, err := proxy.SOCKS5(network, proxyURL.Host, auth, dialer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create SOCKS5 dialer: %w", err)
+		}
+		return socks.(proxy.ContextDialer).DialContext(ctx, "tcp", addr)
+	default:
+		return dialHTTPConnect(ctx, dialer, network, proxyURL, addr)
+	}
+}
+
+// dialHTTPConnect tunnels a connection through an HTTP(S) proxy using CONNECT
+func dialHTTPConnect(ctx context.Context, dialer *net.Dialer, network string, proxyURL *url.URL, addr string) (net.Conn, error) {
+	proxyAddr := proxyURL.Host
+	if proxyURL.Port() == "" {
+		if proxyURL.Scheme == "https" {
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "443")
+		} else {
+			proxyAddr = net.JoinHostPort(proxyURL.Hostname(), "80")
+		}
+	}
+
+	conn, err := dialer.DialContext(ctx, network, proxyAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to proxy: %w", err)
+	}
+
+	if proxyURL.Scheme == "https" {
+		tlsConn := tls.Client(conn, &tls.Config{ServerName: proxyURL.Hostname()})
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("proxy TLS handshake failed: %w", err)
+		}
+		conn = tlsConn
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	req := &http.Request{
+		Method: http.MethodConnect,
+		URL:    &url.URL{Opaque: addr},
+		Host:   addr,
+		Header: make(http.Header),
+	}
+	if proxyURL.User != nil {
+		password, _ := proxyURL.User.Password()
+		credentials := proxyURL.User.Username() + ":" + password
+		req.Header.Set("Proxy-Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(credentials)))
+	}
+
+	if err := req.Write(conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to send CONNECT to proxy: %w", err)
+	}
+
+	resp, err := http.ReadResponse(bufio.NewReader(conn), req)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to read proxy response: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		conn.Close()
+		return nil, fmt.Errorf("proxy CONNECT failed: %s", strings.TrimSpace(resp.Status))
+	}
+
+	// Clear the handshake deadline; callers manage their own timeouts
+	conn.SetDeadline(time.Time{})
+
+	return conn, nil
+}
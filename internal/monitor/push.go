@@ -0,0 +1,173 @@
+package monitor
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"time"
+)
+
+const (
+	// pushMonitorType is the type of monitors that receive heartbeats
+	pushMonitorType = "push"
+
+	// defaultPushGrace is how long past its interval a push may be late
+	// unless grace_period is set
+	defaultPushGrace = 30 * time.Second
+
+	// maxPushGrace caps grace_period at a day
+	maxPushGrace = 24 * 60 * 60
+)
+
+// pushTokenPattern matches the tokens GeneratePushToken makes
+var pushTokenPattern = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// PushMonitor is a monitor the target reports to, e.g. a cron job calling
+// its push URL after each run. It is down when no push arrived within its
+// interval and grace period.
+type PushMonitor struct{}
+
+func init() {
+	RegisterMonitorType(&PushMonitor{})
+}
+
+func (p *PushMonitor) Name() string {
+	return pushMonitorType
+}
+
+// Check is never called: the executor records pushes as they arrive and its
+// scheduled checks only look for a missed one
+func (p *PushMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, error) {
+	return nil, fmt.Errorf("push monitors are not checked actively")
+}
+
+func (p *PushMonitor) Validate(monitor *Monitor) error {
+	token, _ := monitor.Config["push_token"].(string)
+	if !pushTokenPattern.MatchString(token) {
+		return fmt.Errorf("push_token must be a generated push token")
+	}
+
+	grace, set, err := wholeConfigNumber(monitor.Config, "grace_period")
+	if err != nil {
+		return err
+	}
+	if set && (grace < 0 || grace > maxPushGrace) {
+		return fmt.Errorf("grace_period must be between 0 and %d seconds", maxPushGrace)
+	}
+	return nil
+}
+
+// GeneratePushToken returns a new random token for a push monitor's URL
+func GeneratePushToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// pushGrace returns the monitor's "grace_period": how long past its interval
+// a push may arrive before the monitor is down
+func pushGrace(monitor *Monitor) time.Duration {
+	switch v := monitor.Config["grace_period"].(type) {
+	case float64:
+		return time.Duration(v) * time.Second
+	case int:
+		return time.Duration(v) * time.Second
+	}
+	return defaultPushGrace
+}
+
+// checkPush returns a down heartbeat when the monitor's last push is older
+// than its interval and grace period, or nil while pushes arrive on time.
+// A job that has seen no push yet counts from when it started, so a restart
+// gives every push monitor a full interval.
+func (job *monitorJob) checkPush(now time.Time) *Heartbeat {
+	job.stateMu.Lock()
+	lastPush := job.lastPush
+	job.stateMu.Unlock()
+
+	deadline := time.Duration(job.monitor.Interval)*time.Second + pushGrace(job.monitor)
+	since := now.Sub(lastPush)
+	if since <= deadline {
+		return nil
+	}
+	return &Heartbeat{
+		MonitorID: job.monitor.ID,
+		Status:    StatusDown,
+		Message:   fmt.Sprintf("No push received for %s", since.Round(time.Second)),
+		Time:      now,
+	}
+}
+
+// pushTokenKey is the key of a push token in Executor.pushJobs. The index
+// is keyed by the token's hash so a lookup reveals nothing about how close
+// a guessed token came.
+func pushTokenKey(token string) [sha256.Size]byte {
+	return sha256.Sum256([]byte(token))
+}
+
+// indexPushJob adds a starting push monitor's job to the token index. e.mu
+// must be held.
+func (e *Executor) indexPushJob(job *monitorJob) {
+	if job.monitor.Type != pushMonitorType {
+		return
+	}
+	token, _ := job.monitor.Config["push_token"].(string)
+	if token == "" {
+		return
+	}
+	if e.pushJobs == nil {
+		e.pushJobs = make(map[[sha256.Size]byte]*monitorJob)
+	}
+	e.pushJobs[pushTokenKey(token)] = job
+}
+
+// unindexPushJob removes a stopped job from the token index unless another
+// job took its token. e.mu must be held.
+func (e *Executor) unindexPushJob(job *monitorJob) {
+	token, _ := job.monitor.Config["push_token"].(string)
+	key := pushTokenKey(token)
+	if e.pushJobs[key] == job {
+		delete(e.pushJobs, key)
+	}
+}
+
+// RecordPush records a heartbeat pushed to the active push monitor with the
+// given token. Its status is up unless the pusher reported down, and ping is
+// the pusher's reported duration in milliseconds, if any. It returns false
+// when no running push monitor has the token.
+func (e *Executor) RecordPush(token string, status, ping int, message string, at time.Time) bool {
+	e.mu.RLock()
+	job := e.pushJobs[pushTokenKey(token)]
+	e.mu.RUnlock()
+	if job == nil {
+		return false
+	}
+	if stored, _ := job.monitor.Config["push_token"].(string); subtle.ConstantTimeCompare([]byte(stored), []byte(token)) != 1 {
+		return false
+	}
+
+	job.stateMu.Lock()
+	job.lastPush = at
+	job.stateMu.Unlock()
+
+	if message == "" {
+		message = "Push received"
+		if status == StatusDown {
+			message = "Push reported down"
+		}
+	}
+	job.record(&Heartbeat{
+		MonitorID: job.monitor.ID,
+		Status:    status,
+		Ping:      ping,
+		Message:   message,
+		Time:      at,
+	})
+	return true
+}
@@ -0,0 +1,173 @@
+package monitor
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCheckPushDeadline(t *testing.T) {
+	start := time.Date(2026, 7, 1, 3, 0, 0, 0, time.UTC)
+	job := &monitorJob{
+		monitor:  &Monitor{ID: 4, Interval: 300, Config: map[string]interface{}{"grace_period": float64(60)}},
+		lastPush: start,
+	}
+
+	if hb := job.checkPush(start.Add(6 * time.Minute)); hb != nil {
+		t.Errorf("down %q within interval and grace period", hb.Message)
+	}
+	hb := job.checkPush(start.Add(6*time.Minute + time.Second))
+	if hb == nil || hb.Status != StatusDown || hb.Message != "No push received for 6m1s" {
+		t.Errorf("got %+v past the grace period, want down without a push for 6m1s", hb)
+	}
+}
+
+func TestCheckPushDefaultGrace(t *testing.T) {
+	start := time.Date(2026, 7, 1, 3, 0, 0, 0, time.UTC)
+	job := &monitorJob{monitor: &Monitor{Interval: 60}, lastPush: start}
+
+	if hb := job.checkPush(start.Add(90 * time.Second)); hb != nil {
+		t.Errorf("down %q within the default grace period", hb.Message)
+	}
+	if hb := job.checkPush(start.Add(91 * time.Second)); hb == nil {
+		t.Error("still up past the default grace period")
+	}
+}
+
+func TestRecordPush(t *testing.T) {
+	var mu sync.Mutex
+	var saved []*Heartbeat
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error {
+		mu.Lock()
+		defer mu.Unlock()
+		saved = append(saved, heartbeat)
+		return nil
+	}
+	e.jobHistory = func(monitorID int) jobStart { return jobStart{lastStatus: StatusUp} }
+
+	token := strings.Repeat("ab", 16)
+	e.StartMonitor(&Monitor{ID: 9, Name: "backup", Type: "push", Interval: 3600, Config: map[string]interface{}{"push_token": token}})
+	defer e.Stop()
+
+	at := time.Now()
+	if e.RecordPush(strings.Repeat("cd", 16), StatusUp, 0, "", at) {
+		t.Error("recorded a push for an unknown token")
+	}
+	if !e.RecordPush(token, StatusUp, 1500, "", at) {
+		t.Fatal("push to an active monitor was not recorded")
+	}
+	if !e.RecordPush(token, StatusDown, 0, "backup failed", at.Add(time.Minute)) {
+		t.Fatal("down push was not recorded")
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(saved) != 2 {
+		t.Fatalf("saved %d heartbeats, want 2", len(saved))
+	}
+	if hb := saved[0]; hb.MonitorID != 9 || hb.Status != StatusUp || hb.Ping != 1500 || hb.Message != "Push received" {
+		t.Errorf("first heartbeat = %+v, want an up push with ping 1500", hb)
+	}
+	if hb := saved[1]; hb.Status != StatusDown || hb.Message != "backup failed" || !hb.Important {
+		t.Errorf("second heartbeat = %+v, want an important down push", hb)
+	}
+}
+
+func TestRecordPushFollowsRunningJobs(t *testing.T) {
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error { return nil }
+	e.jobHistory = func(monitorID int) jobStart { return jobStart{lastStatus: StatusUp} }
+	defer e.Stop()
+
+	oldToken, newToken := strings.Repeat("12", 16), strings.Repeat("34", 16)
+	e.StartMonitor(&Monitor{ID: 12, Name: "backup", Type: "push", Interval: 3600, Config: map[string]interface{}{"push_token": oldToken}})
+	e.StartMonitor(&Monitor{ID: 12, Name: "backup", Type: "push", Interval: 3600, Config: map[string]interface{}{"push_token": newToken}})
+	if e.RecordPush(oldToken, StatusUp, 0, "", time.Now()) {
+		t.Error("push accepted for the token the monitor was edited away from")
+	}
+	if !e.RecordPush(newToken, StatusUp, 0, "", time.Now()) {
+		t.Error("push to the edited monitor was not recorded")
+	}
+
+	e.StopMonitor(12)
+	if e.RecordPush(newToken, StatusUp, 0, "", time.Now()) {
+		t.Error("push accepted for a stopped monitor")
+	}
+}
+
+func TestRecordPushConcurrent(t *testing.T) {
+	var mu sync.Mutex
+	saved := 0
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error {
+		mu.Lock()
+		defer mu.Unlock()
+		saved++
+		return nil
+	}
+	e.jobHistory = func(monitorID int) jobStart { return jobStart{lastStatus: StatusUp} }
+
+	token := strings.Repeat("ef", 16)
+	e.StartMonitor(&Monitor{ID: 11, Name: "cron", Type: "push", Interval: 3600, Config: map[string]interface{}{"push_token": token}})
+	defer e.Stop()
+
+	// Pushes come in on their own request goroutines, so outage state
+	// updated by record must not race
+	var wg sync.WaitGroup
+	at := time.Now()
+	for i := 0; i < 8; i++ {
+		status := StatusUp
+		if i%2 == 1 {
+			status = StatusDown
+		}
+		wg.Add(1)
+		go func(i, status int) {
+			defer wg.Done()
+			if !e.RecordPush(token, status, i, "", at.Add(time.Duration(i)*time.Second)) {
+				t.Errorf("push %d was not recorded", i)
+			}
+		}(i, status)
+	}
+	wg.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if saved != 8 {
+		t.Errorf("saved %d heartbeats, want 8", saved)
+	}
+}
+
+func TestPushMonitorValidate(t *testing.T) {
+	token := strings.Repeat("0f", 16)
+	tests := []struct {
+		config  map[string]interface{}
+		wantErr bool
+	}{
+		{config: map[string]interface{}{"push_token": token}},
+		{config: map[string]interface{}{"push_token": token, "grace_period": float64(120)}},
+		{config: map[string]interface{}{}, wantErr: true},
+		{config: map[string]interface{}{"push_token": "my-token"}, wantErr: true},
+		{config: map[string]interface{}{"push_token": token, "grace_period": float64(-1)}, wantErr: true},
+		{config: map[string]interface{}{"push_token": token, "grace_period": float64(maxPushGrace + 1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		err := (&PushMonitor{}).Validate(&Monitor{Interval: 60, Config: tt.config})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Validate(%v) = %v, want error %v", tt.config, err, tt.wantErr)
+		}
+	}
+}
+
+func TestGeneratePushToken(t *testing.T) {
+	a, err := GeneratePushToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GeneratePushToken()
+	if !pushTokenPattern.MatchString(a) || a == b {
+		t.Errorf("tokens %q and %q, want two different 32 character hex tokens", a, b)
+	}
+}
@@ -0,0 +1,159 @@
+package monitor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// localSource names the executor's own checks among a monitor's sources
+const localSource = "local"
+
+// sourceResult is the latest check result reported by one source
+type sourceResult struct {
+	status  int
+	message string
+	at      time.Time
+}
+
+// agentResults holds the latest result of every remote agent checking a
+// monitor. Agents report from API handlers while checks read them, so it is
+// locked.
+type agentResults struct {
+	mu      sync.Mutex
+	results map[string]sourceResult
+}
+
+// set records an agent's latest result
+func (a *agentResults) set(agent string, result sourceResult) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.results == nil {
+		a.results = make(map[string]sourceResult)
+	}
+	a.results[agent] = result
+}
+
+// fresh returns the results reported at or after since
+func (a *agentResults) fresh(since time.Time) map[string]sourceResult {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	fresh := make(map[string]sourceResult, len(a.results))
+	for agent, result := range a.results {
+		if !result.at.Before(since) {
+			fresh[agent] = result
+		}
+	}
+	return fresh
+}
+
+// monitorQuorum returns how many sources must report a monitor down before
+// it is down. The "quorum" config defaults to 1, any single source.
+func monitorQuorum(monitor *Monitor) int {
+	quorum := 1
+	switch v := monitor.Config["quorum"].(type) {
+	case float64:
+		quorum = int(v)
+	case int:
+		quorum = v
+	}
+	if quorum < 1 {
+		quorum = 1
+	}
+	return quorum
+}
+
+// ValidateQuorum checks the optional quorum config, a whole number of at
+// least 1
+func ValidateQuorum(monitor *Monitor) error {
+	var quorum float64
+	switch v := monitor.Config["quorum"].(type) {
+	case nil:
+		return nil
+	case float64:
+		quorum = v
+	case int:
+		quorum = float64(v)
+	default:
+		return fmt.Errorf("quorum must be a number")
+	}
+	if quorum < 1 || quorum != float64(int(quorum)) {
+		return fmt.Errorf("quorum must be a whole number of at least 1")
+	}
+	return nil
+}
+
+// agentResultMaxAge is how long an agent's result counts toward the quorum:
+// two of the monitor's intervals, so one late report doesn't drop it
+func agentResultMaxAge(monitor *Monitor) time.Duration {
+	return 2 * time.Duration(monitor.Interval) * time.Second
+}
+
+// quorumStatus combines the local check with fresh agent results. The
+// monitor is down when at least quorum sources report it down. A local
+// failure short of the quorum is pending, unconfirmed; otherwise it is up.
+// Pending and maintenance local results are returned as they are, as is the
+// local result when no agent reported.
+func quorumStatus(local sourceResult, agents map[string]sourceResult, quorum int) (int, string) {
+	if len(agents) == 0 || (local.status != StatusUp && local.status != StatusDown) {
+		return local.status, local.message
+	}
+
+	names := make([]string, 0, len(agents))
+	for name := range agents {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var down []string
+	message := ""
+	if local.status == StatusDown {
+		down = append(down, localSource)
+		message = local.message
+	}
+	for _, name := range names {
+		if agents[name].status == StatusDown {
+			down = append(down, name)
+			if message == "" {
+				message = agents[name].message
+			}
+		}
+	}
+	if len(down) == 0 {
+		return StatusUp, local.message
+	}
+
+	summary := fmt.Sprintf("Down from %d/%d sources (%s)", len(down), len(agents)+1, strings.Join(down, ", "))
+	if len(down) >= quorum {
+		return StatusDown, summary + ": " + message
+	}
+	if local.status == StatusDown {
+		return StatusPending, fmt.Sprintf("%s, quorum %d: %s", summary, quorum, message)
+	}
+	return StatusUp, fmt.Sprintf("%s, quorum %d - %s", summary, quorum, local.message)
+}
+
+// RecordAgentResult stores a check result reported by a remote agent for
+// an active monitor. It counts toward the monitor's quorum from its next
+// check. It returns false when the monitor isn't running.
+func (e *Executor) RecordAgentResult(monitorID int, agent string, status int, message string, at time.Time) bool {
+	e.mu.RLock()
+	job, ok := e.monitors[monitorID]
+	e.mu.RUnlock()
+	if !ok {
+		return false
+	}
+	job.agents.set(agent, sourceResult{status: status, message: message, at: at})
+	return true
+}
+
+// applyQuorum rewrites a local heartbeat's status and message from the
+// monitor's quorum of sources
+func (job *monitorJob) applyQuorum(heartbeat *Heartbeat) {
+	agents := job.agents.fresh(heartbeat.Time.Add(-agentResultMaxAge(job.monitor)))
+	heartbeat.Status, heartbeat.Message = quorumStatus(
+		sourceResult{status: heartbeat.Status, message: heartbeat.Message, at: heartbeat.Time},
+		agents, monitorQuorum(job.monitor))
+}
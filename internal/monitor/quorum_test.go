@@ -0,0 +1,138 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuorumStatus(t *testing.T) {
+	up := sourceResult{status: StatusUp, message: "HTTP 200 - 40ms"}
+	down := sourceResult{status: StatusDown, message: "Request failed: timeout"}
+
+	tests := []struct {
+		name        string
+		local       sourceResult
+		agents      map[string]sourceResult
+		quorum      int
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			name:        "no agents keeps the local result",
+			local:       down,
+			quorum:      2,
+			wantStatus:  StatusDown,
+			wantMessage: "Request failed: timeout",
+		},
+		{
+			name:        "all sources agree down",
+			local:       down,
+			agents:      map[string]sourceResult{"agent:eu": down, "agent:us": down},
+			quorum:      2,
+			wantStatus:  StatusDown,
+			wantMessage: "Down from 3/3 sources (local, agent:eu, agent:us): Request failed: timeout",
+		},
+		{
+			name:        "local failure short of quorum is pending",
+			local:       down,
+			agents:      map[string]sourceResult{"agent:eu": up, "agent:us": up},
+			quorum:      2,
+			wantStatus:  StatusPending,
+			wantMessage: "Down from 1/3 sources (local), quorum 2: Request failed: timeout",
+		},
+		{
+			name:        "agents reach quorum while local is up",
+			local:       up,
+			agents:      map[string]sourceResult{"agent:eu": {status: StatusDown, message: "connection refused"}, "agent:us": down},
+			quorum:      2,
+			wantStatus:  StatusDown,
+			wantMessage: "Down from 2/3 sources (agent:eu, agent:us): connection refused",
+		},
+		{
+			name:        "one agent down below quorum stays up",
+			local:       up,
+			agents:      map[string]sourceResult{"agent:eu": down, "agent:us": up},
+			quorum:      2,
+			wantStatus:  StatusUp,
+			wantMessage: "Down from 1/3 sources (agent:eu), quorum 2 - HTTP 200 - 40ms",
+		},
+		{
+			name:        "all sources agree up",
+			local:       up,
+			agents:      map[string]sourceResult{"agent:eu": up},
+			quorum:      1,
+			wantStatus:  StatusUp,
+			wantMessage: "HTTP 200 - 40ms",
+		},
+		{
+			name:        "quorum of one counts any source",
+			local:       up,
+			agents:      map[string]sourceResult{"agent:eu": down},
+			quorum:      1,
+			wantStatus:  StatusDown,
+			wantMessage: "Down from 1/2 sources (agent:eu): Request failed: timeout",
+		},
+		{
+			name:        "maintenance passes through",
+			local:       sourceResult{status: StatusMaintenance, message: "maintenance"},
+			agents:      map[string]sourceResult{"agent:eu": down, "agent:us": down},
+			quorum:      1,
+			wantStatus:  StatusMaintenance,
+			wantMessage: "maintenance",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, message := quorumStatus(tt.local, tt.agents, tt.quorum)
+			if status != tt.wantStatus || message != tt.wantMessage {
+				t.Errorf("got %d %q, want %d %q", status, message, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestApplyQuorumIgnoresStaleAgents(t *testing.T) {
+	now := time.Now()
+	job := &monitorJob{monitor: &Monitor{Interval: 60, Config: map[string]interface{}{"quorum": 2.0}}}
+	job.agents.set("agent:eu", sourceResult{status: StatusDown, message: "timeout", at: now.Add(-time.Minute)})
+	job.agents.set("agent:us", sourceResult{status: StatusDown, message: "timeout", at: now.Add(-3 * time.Minute)})
+
+	heartbeat := &Heartbeat{Status: StatusUp, Message: "HTTP 200", Time: now}
+	job.applyQuorum(heartbeat)
+	if heartbeat.Status != StatusUp {
+		t.Errorf("status = %d, want up: only agent:eu is fresh, short of quorum 2", heartbeat.Status)
+	}
+
+	heartbeat = &Heartbeat{Status: StatusDown, Message: "HTTP 503", Time: now}
+	job.applyQuorum(heartbeat)
+	if heartbeat.Status != StatusDown {
+		t.Errorf("status = %d %q, want down from local and agent:eu", heartbeat.Status, heartbeat.Message)
+	}
+}
+
+func TestRecordAgentResult(t *testing.T) {
+	e := &Executor{monitors: map[int]*monitorJob{7: {monitor: &Monitor{ID: 7, Interval: 60}}}}
+	if !e.RecordAgentResult(7, "agent:eu", StatusDown, "timeout", time.Now()) {
+		t.Fatal("RecordAgentResult for a running monitor returned false")
+	}
+	if e.RecordAgentResult(8, "agent:eu", StatusDown, "timeout", time.Now()) {
+		t.Error("RecordAgentResult for a stopped monitor returned true")
+	}
+	if got := e.monitors[7].agents.fresh(time.Now().Add(-time.Minute)); got["agent:eu"].status != StatusDown {
+		t.Errorf("recorded results = %v, want agent:eu down", got)
+	}
+}
+
+func TestValidateQuorum(t *testing.T) {
+	for _, quorum := range []interface{}{nil, 1.0, 3.0, 2} {
+		if err := ValidateQuorum(&Monitor{Config: map[string]interface{}{"quorum": quorum}}); err != nil {
+			t.Errorf("ValidateQuorum(%v): %v", quorum, err)
+		}
+	}
+	for _, quorum := range []interface{}{0.0, -1.0, 1.5, "2"} {
+		if err := ValidateQuorum(&Monitor{Config: map[string]interface{}{"quorum": quorum}}); err == nil {
+			t.Errorf("ValidateQuorum(%v) succeeded, want error", quorum)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+package monitor
+
+import "net"
+
+// remoteIP returns the IP address of the remote end of a connection, or nil
+// when addr carries no IP
+func remoteIP(addr net.Addr) *string {
+	var ip net.IP
+	switch a := addr.(type) {
+	case *net.TCPAddr:
+		ip = a.IP
+	case *net.UDPAddr:
+		ip = a.IP
+	}
+	if ip == nil {
+		return nil
+	}
+	s := ip.String()
+	return &s
+}
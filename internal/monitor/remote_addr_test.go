@@ -0,0 +1,84 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHTTPMonitorRecordsRemoteAddr(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	hb, err := NewHTTPMonitor(nil).Check(context.Background(), &Monitor{URL: server.URL, Timeout: 5})
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusUp {
+		t.Fatalf("status = %d (%s), want up", hb.Status, hb.Message)
+	}
+	if hb.RemoteAddr == nil || *hb.RemoteAddr != "127.0.0.1" {
+		t.Errorf("RemoteAddr = %v, want 127.0.0.1", hb.RemoteAddr)
+	}
+}
+
+func TestTCPMonitorRecordsRemoteAddr(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	m := &Monitor{
+		URL:     "127.0.0.1",
+		Timeout: 5,
+		Config:  map[string]interface{}{"port": float64(listener.Addr().(*net.TCPAddr).Port)},
+	}
+	hb, err := (&TCPMonitor{}).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusUp {
+		t.Fatalf("status = %d (%s), want up", hb.Status, hb.Message)
+	}
+	if hb.RemoteAddr == nil || *hb.RemoteAddr != "127.0.0.1" {
+		t.Errorf("RemoteAddr = %v, want 127.0.0.1", hb.RemoteAddr)
+	}
+}
+
+func TestTCPMonitorFailedConnectionHasNoRemoteAddr(t *testing.T) {
+	// Grab a free port and close it so the connection is refused
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	m := &Monitor{
+		URL:     "127.0.0.1",
+		Timeout: 5,
+		Config:  map[string]interface{}{"port": float64(port)},
+	}
+	hb, err := (&TCPMonitor{}).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusDown {
+		t.Fatalf("status = %d, want down", hb.Status)
+	}
+	if hb.RemoteAddr != nil {
+		t.Errorf("RemoteAddr = %q, want nil", *hb.RemoteAddr)
+	}
+}
@@ -0,0 +1,63 @@
+package monitor
+
+import (
+	"fmt"
+	"time"
+)
+
+// maxResendIntervalSeconds caps resend_interval_seconds at a week
+const maxResendIntervalSeconds = 7 * 24 * 60 * 60
+
+// resendPeriod returns the monitor's "resend_interval_seconds" config: how
+// much longer an outage must last for each repeated down alert. When set it
+// replaces resend_interval; 0 leaves resend_interval counting failures.
+func resendPeriod(monitor *Monitor) time.Duration {
+	var seconds float64
+	switch v := monitor.Config["resend_interval_seconds"].(type) {
+	case float64:
+		seconds = v
+	case int:
+		seconds = float64(v)
+	}
+	if seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// shouldResendByTime applies resend_interval_seconds to an ongoing outage.
+// The first alert goes out with the first failure; after that one goes out
+// each time the downtime, measured from the outage start, passes another
+// period. sent is the last period alerted for, which the caller keeps. A
+// check that skips past several periods alerts once.
+func shouldResendByTime(consecutiveFailures int, downtime, period time.Duration, sent *int) bool {
+	reached := int(downtime / period)
+	if consecutiveFailures == 1 {
+		*sent = reached
+		return true
+	}
+	if reached > *sent {
+		*sent = reached
+		return true
+	}
+	return false
+}
+
+// ValidateResendInterval checks the optional resend_interval_seconds config:
+// whole seconds, at least the check interval and at most a week
+func ValidateResendInterval(monitor *Monitor) error {
+	seconds, set, err := wholeConfigNumber(monitor.Config, "resend_interval_seconds")
+	if err != nil {
+		return err
+	}
+	if !set || seconds == 0 {
+		return nil
+	}
+	if seconds < monitor.Interval {
+		return fmt.Errorf("resend_interval_seconds must be at least the check interval (%ds)", monitor.Interval)
+	}
+	if seconds > maxResendIntervalSeconds {
+		return fmt.Errorf("resend_interval_seconds must be at most %d seconds", maxResendIntervalSeconds)
+	}
+	return nil
+}
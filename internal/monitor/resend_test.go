@@ -0,0 +1,105 @@
+package monitor
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEvaluateResendsByDowntime(t *testing.T) {
+	job := newTestJob(StatusUp, 0)
+	job.monitor.Config = map[string]interface{}{"resend_interval_seconds": float64(300)}
+	start := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	// Checks every minute for 16 minutes of downtime
+	var alertedAt []int
+	for minute := 0; minute <= 16; minute++ {
+		if job.evaluate(StatusDown, start.Add(time.Duration(minute)*time.Minute)).notifyDown {
+			alertedAt = append(alertedAt, minute)
+		}
+	}
+
+	want := []int{0, 5, 10, 15}
+	if len(alertedAt) != len(want) {
+		t.Fatalf("alerted at minutes %v, want %v", alertedAt, want)
+	}
+	for i := range want {
+		if alertedAt[i] != want[i] {
+			t.Errorf("alerted at minutes %v, want %v", alertedAt, want)
+			break
+		}
+	}
+}
+
+func TestEvaluateResendByDowntimeIndependentOfCheckInterval(t *testing.T) {
+	job := newTestJob(StatusUp, 0)
+	job.monitor.Config = map[string]interface{}{"resend_interval_seconds": float64(600)}
+	start := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+
+	// Checks at irregular times, one skipping past two periods
+	steps := []struct {
+		minute int
+		want   bool
+	}{
+		{0, true},   // outage starts
+		{7, false},  // within the first period
+		{10, true},  // 10 minutes down
+		{19, false}, // still within the second period
+		{41, true},  // skipped past 20 and 30: alerted once
+		{45, false},
+		{50, true},
+	}
+	for _, step := range steps {
+		d := job.evaluate(StatusDown, start.Add(time.Duration(step.minute)*time.Minute))
+		if d.notifyDown != step.want {
+			t.Errorf("minute %d: notifyDown = %v, want %v", step.minute, d.notifyDown, step.want)
+		}
+	}
+}
+
+func TestEvaluateResendByDowntimeRestartsWithNewOutage(t *testing.T) {
+	job := newTestJob(StatusUp, 0)
+	job.monitor.Config = map[string]interface{}{"resend_interval_seconds": float64(300)}
+	start := time.Date(2026, 4, 1, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	job.evaluate(StatusDown, at(0))
+	job.evaluate(StatusDown, at(12)) // alerted for the 10 minute mark
+	job.evaluate(StatusUp, at(13))
+
+	// The new outage counts from its own start
+	if !job.evaluate(StatusDown, at(20)).notifyDown {
+		t.Error("first failure of a new outage did not alert")
+	}
+	if job.evaluate(StatusDown, at(24)).notifyDown {
+		t.Error("resent 4 minutes into a new outage")
+	}
+	if !job.evaluate(StatusDown, at(25)).notifyDown {
+		t.Error("no resend 5 minutes into a new outage")
+	}
+}
+
+func TestValidateResendInterval(t *testing.T) {
+	tests := []struct {
+		config         map[string]interface{}
+		resendInterval int
+		wantErr        bool
+	}{
+		{config: map[string]interface{}{}},
+		{config: map[string]interface{}{}, resendInterval: 3},
+		{config: map[string]interface{}{"resend_interval_seconds": float64(0)}, resendInterval: 3},
+		{config: map[string]interface{}{"resend_interval_seconds": float64(900)}},
+		{config: map[string]interface{}{"resend_interval_seconds": float64(900)}, resendInterval: 3},
+		{config: map[string]interface{}{"resend_interval_seconds": float64(30)}, wantErr: true},
+		{config: map[string]interface{}{"resend_interval_seconds": 90.5}, wantErr: true},
+		{config: map[string]interface{}{"resend_interval_seconds": "900"}, wantErr: true},
+		{config: map[string]interface{}{"resend_interval_seconds": float64(maxResendIntervalSeconds + 1)}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		m := &Monitor{Interval: 60, ResendInterval: tt.resendInterval, Config: tt.config}
+		err := ValidateResendInterval(m)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateResendInterval(%v, resend_interval %d) = %v, want error %v", tt.config, tt.resendInterval, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// resultWebhookTimeout bounds each POST to a result webhook
+const resultWebhookTimeout = 5 * time.Second
+
+// resultWebhookPayload is the JSON body posted to a result webhook
+type resultWebhookPayload struct {
+	MonitorID   int       `json:"monitor_id"`
+	MonitorName string    `json:"monitor_name"`
+	MonitorType string    `json:"monitor_type"`
+	URL         string    `json:"url"`
+	Status      int       `json:"status"` // 0=down, 1=up, 2=pending, 3=maintenance
+	Ping        int       `json:"ping"`   // milliseconds
+	Important   bool      `json:"important"`
+	Message     string    `json:"message"`
+	Time        time.Time `json:"time"`
+	RemoteAddr  *string   `json:"remote_addr"`
+}
+
+// newResultWebhookClient returns the client for result webhooks. Redirects
+// are not followed, so a webhook can't bounce the request past the SSRF check.
+func newResultWebhookClient() *http.Client {
+	return &http.Client{
+		Timeout: resultWebhookTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// resultWebhookURL returns the monitor's result_webhook_url, or "" when it
+// has none
+func resultWebhookURL(monitor *Monitor) string {
+	url, _ := monitor.Config["result_webhook_url"].(string)
+	return strings.TrimSpace(url)
+}
+
+// ValidateResultWebhook checks the optional result_webhook_url config, which
+// every monitor type accepts: an http(s) URL that passes the SSRF check
+func ValidateResultWebhook(monitor *Monitor) error {
+	raw, ok := monitor.Config["result_webhook_url"]
+	if !ok || raw == nil {
+		return nil
+	}
+	if _, ok := raw.(string); !ok {
+		return fmt.Errorf("result_webhook_url must be a string")
+	}
+	url := resultWebhookURL(monitor)
+	if url == "" {
+		return nil
+	}
+
+	cfg := GetConfig()
+	if err := cfg.SSRFProtection().ValidateURL(url); err != nil {
+		return fmt.Errorf("result_webhook_url: %w", err)
+	}
+	return nil
+}
+
+// postResult posts a check result to the monitor's result webhook, if it has
+// one. It runs in its own goroutine so a slow endpoint never delays checks.
+func (e *Executor) postResult(monitor *Monitor, heartbeat *Heartbeat) {
+	url := resultWebhookURL(monitor)
+	if url == "" {
+		return
+	}
+
+	if err := revalidateURL(url); err != nil {
+		log.Printf("Skipping result webhook for monitor %d: %v", monitor.ID, err)
+		return
+	}
+
+	body, err := json.Marshal(resultWebhookPayload{
+		MonitorID:   monitor.ID,
+		MonitorName: monitor.Name,
+		MonitorType: monitor.Type,
+		URL:         monitor.URL,
+		Status:      heartbeat.Status,
+		Ping:        heartbeat.Ping,
+		Important:   heartbeat.Important,
+		Message:     heartbeat.Message,
+		Time:        heartbeat.Time,
+		RemoteAddr:  heartbeat.RemoteAddr,
+	})
+	if err != nil {
+		log.Printf("Failed to encode result webhook for monitor %d: %v", monitor.ID, err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), resultWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		log.Printf("Failed to create result webhook request for monitor %d: %v", monitor.ID, err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Uptime-Kuma-Go/1.0")
+
+	resp, err := e.resultClient.Do(req)
+	if err != nil {
+		log.Printf("Result webhook for monitor %d failed: %v", monitor.ID, err)
+		return
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		log.Printf("Result webhook for monitor %d returned status %d", monitor.ID, resp.StatusCode)
+	}
+}
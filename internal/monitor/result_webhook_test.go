@@ -0,0 +1,122 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// scriptedMonitorType returns the next status of a script on every check
+type scriptedMonitorType struct {
+	statuses chan int
+}
+
+func (s *scriptedMonitorType) Name() string { return "result-webhook-test" }
+
+func (s *scriptedMonitorType) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, error) {
+	return &Heartbeat{MonitorID: monitor.ID, Status: <-s.statuses, Ping: 12, Message: "scripted", Time: time.Now()}, nil
+}
+
+func (s *scriptedMonitorType) Validate(monitor *Monitor) error { return nil }
+
+func TestResultWebhookPostedForEveryCheck(t *testing.T) {
+	SetConfig(&MonitorConfig{AllowPrivateIPs: true})
+	defer SetConfig(nil)
+
+	received := make(chan resultWebhookPayload, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload resultWebhookPayload
+		if r.Method != http.MethodPost || json.NewDecoder(r.Body).Decode(&payload) != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received <- payload
+	}))
+	defer server.Close()
+
+	scripted := &scriptedMonitorType{statuses: make(chan int, 3)}
+	RegisterMonitorType(scripted)
+	statuses := []int{StatusUp, StatusUp, StatusDown}
+	for _, status := range statuses {
+		scripted.statuses <- status
+	}
+
+	e := NewExecutor(nil, nil, nil, 0)
+	e.saveHeartbeat = func(heartbeat *Heartbeat) error { return nil }
+	job := &monitorJob{
+		monitor: &Monitor{
+			ID: 9, Name: "pipeline", Type: scripted.Name(), Interval: 60, Timeout: 5,
+			Config: map[string]interface{}{"result_webhook_url": server.URL + "/ingest"},
+		},
+		executor:   e,
+		lastStatus: StatusPending,
+	}
+
+	for range statuses {
+		job.runCheck()
+	}
+
+	// Posts are asynchronous, so they may arrive in any order
+	counts := make(map[int]int)
+	for i := range statuses {
+		select {
+		case payload := <-received:
+			if payload.MonitorID != 9 || payload.MonitorName != "pipeline" || payload.Ping != 12 || payload.Time.IsZero() {
+				t.Errorf("payload = %+v, want the monitor's check result", payload)
+			}
+			counts[payload.Status]++
+		case <-time.After(5 * time.Second):
+			t.Fatalf("got %d of %d result webhooks", i, len(statuses))
+		}
+	}
+	if counts[StatusUp] != 2 || counts[StatusDown] != 1 {
+		t.Errorf("posted statuses = %v, want 2 up and 1 down", counts)
+	}
+}
+
+// roundTripFunc adapts a function to http.RoundTripper
+type roundTripFunc func(*http.Request) (*http.Response, error)
+
+func (f roundTripFunc) RoundTrip(r *http.Request) (*http.Response, error) { return f(r) }
+
+func TestResultWebhookSkippedWithoutURL(t *testing.T) {
+	e := NewExecutor(nil, nil, nil, 0)
+	e.resultClient = &http.Client{Transport: roundTripFunc(func(*http.Request) (*http.Response, error) {
+		t.Error("result webhook posted for a monitor without result_webhook_url")
+		return nil, http.ErrHandlerTimeout
+	})}
+
+	e.postResult(&Monitor{ID: 1, Config: map[string]interface{}{}}, &Heartbeat{Status: StatusUp})
+	e.postResult(&Monitor{ID: 2, Config: map[string]interface{}{"result_webhook_url": "  "}}, &Heartbeat{Status: StatusUp})
+}
+
+func TestValidateResultWebhook(t *testing.T) {
+	SetConfig(&MonitorConfig{})
+	defer SetConfig(nil)
+
+	valid := []map[string]interface{}{
+		{},
+		{"result_webhook_url": ""},
+		{"result_webhook_url": "https://8.8.8.8/ingest"},
+	}
+	for _, config := range valid {
+		if err := ValidateResultWebhook(&Monitor{Config: config}); err != nil {
+			t.Errorf("ValidateResultWebhook(%v): %v", config, err)
+		}
+	}
+
+	invalid := []map[string]interface{}{
+		{"result_webhook_url": 5},
+		{"result_webhook_url": "ftp://8.8.8.8/ingest"},
+		{"result_webhook_url": "http://127.0.0.1:9000/ingest"},
+		{"result_webhook_url": "http://169.254.169.254/latest"},
+	}
+	for _, config := range invalid {
+		if err := ValidateResultWebhook(&Monitor{Config: config}); err == nil {
+			t.Errorf("ValidateResultWebhook(%v) succeeded, want error", config)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package monitor
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+const (
+	// maxRetries caps how often a failed check is repeated
+	maxRetries = 10
+
+	// defaultRetryInterval is the wait between retries unless
+	// retry_interval is set
+	defaultRetryInterval = 5 * time.Second
+)
+
+// retryInterval returns how long to wait before re-checking a failed check
+func retryInterval(monitor *Monitor) time.Duration {
+	if monitor.RetryInterval > 0 {
+		return time.Duration(monitor.RetryInterval) * time.Second
+	}
+	return defaultRetryInterval
+}
+
+// ValidateRetries checks retries and retry_interval. The retries must fit
+// within the check interval, so a retrying check is done before the next one
+// is scheduled.
+func ValidateRetries(monitor *Monitor) error {
+	if monitor.Retries < 0 || monitor.Retries > maxRetries {
+		return fmt.Errorf("retries must be between 0 and %d", maxRetries)
+	}
+	if monitor.RetryInterval < 0 {
+		return fmt.Errorf("retry_interval must not be negative")
+	}
+	if monitor.Retries == 0 {
+		return nil
+	}
+	if wait := time.Duration(monitor.Retries) * retryInterval(monitor); wait >= time.Duration(monitor.Interval)*time.Second {
+		return fmt.Errorf("retries × retry_interval (%s) must be shorter than the check interval (%ds)", wait, monitor.Interval)
+	}
+	return nil
+}
+
+// checkWithRetries runs a check, repeating a down result up to the monitor's
+// retries before it counts. Each retry waits retry_interval and broadcasts a
+// pending heartbeat so dashboards show the monitor is being re-checked. Only
+// the final result is returned: intermediate failures are never stored.
+// Outages already under way aren't retried, as another failure is expected,
+// nor are checks within a maintenance window.
+func (job *monitorJob) checkWithRetries(monitorType MonitorType) (*Heartbeat, error) {
+	monitor := job.monitor
+
+	retries := monitor.Retries
+	job.stateMu.Lock()
+	if job.lastStatus == StatusDown {
+		retries = 0
+	}
+	job.stateMu.Unlock()
+	// Failures within maintenance are recorded as maintenance anyway
+	if retries > 0 && job.executor.activeMaintenance(monitor.ID, time.Now()) != nil {
+		retries = 0
+	}
+
+	for attempt := 0; ; attempt++ {
+		heartbeat, err := job.check(monitorType)
+		if err != nil || heartbeat.Status != StatusDown {
+			return heartbeat, err
+		}
+		if attempt == retries {
+			if retries > 0 {
+				heartbeat.Message = fmt.Sprintf("%s (failed after %d attempts)", heartbeat.Message, retries+1)
+			}
+			return heartbeat, nil
+		}
+
+		wait := retryInterval(monitor)
+		if job.executor.hub != nil {
+			job.executor.hub.Broadcast("heartbeat", &Heartbeat{
+				MonitorID: monitor.ID,
+				Status:    StatusPending,
+				Ping:      heartbeat.Ping,
+				Message:   fmt.Sprintf("Retry %d/%d in %s: %s", attempt+1, retries, wait, heartbeat.Message),
+				Time:      heartbeat.Time,
+			})
+		}
+		job.executor.retryWait(wait)
+	}
+}
+
+// check runs one check of the monitor within its timeout
+func (job *monitorJob) check(monitorType MonitorType) (*Heartbeat, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(job.monitor.Timeout+5)*time.Second)
+	defer cancel()
+	return monitorType.Check(ctx, job.monitor)
+}
@@ -0,0 +1,88 @@
+package monitor
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// newRetryJob returns a job with the given retries whose checks follow
+// statuses, recording the waits between them
+func newRetryJob(lastStatus, retries int, statuses ...int) (*monitorJob, *scriptedMonitorType, *[]time.Duration) {
+	scripted := &scriptedMonitorType{statuses: make(chan int, len(statuses))}
+	for _, status := range statuses {
+		scripted.statuses <- status
+	}
+
+	var waits []time.Duration
+	e := NewExecutor(nil, nil, nil, 0)
+	e.retryWait = func(d time.Duration) { waits = append(waits, d) }
+	job := &monitorJob{
+		monitor:    &Monitor{ID: 3, Name: "api", Interval: 60, Timeout: 5, Retries: retries, RetryInterval: 10},
+		executor:   e,
+		lastStatus: lastStatus,
+	}
+	return job, scripted, &waits
+}
+
+func TestCheckWithRetriesRecovers(t *testing.T) {
+	job, scripted, waits := newRetryJob(StatusUp, 3, StatusDown, StatusDown, StatusUp)
+
+	hb, err := job.checkWithRetries(scripted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hb.Status != StatusUp {
+		t.Errorf("status = %d, want up after the blip", hb.Status)
+	}
+	if len(*waits) != 2 || (*waits)[0] != 10*time.Second {
+		t.Errorf("waits = %v, want two of 10s", *waits)
+	}
+}
+
+func TestCheckWithRetriesExhausted(t *testing.T) {
+	job, scripted, waits := newRetryJob(StatusUp, 3, StatusDown, StatusDown, StatusDown, StatusDown)
+
+	hb, err := job.checkWithRetries(scripted)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hb.Status != StatusDown || !strings.HasSuffix(hb.Message, "(failed after 4 attempts)") {
+		t.Errorf("got status %d %q, want down after 4 attempts", hb.Status, hb.Message)
+	}
+	if len(*waits) != 3 {
+		t.Errorf("waited %d times, want 3", len(*waits))
+	}
+}
+
+func TestCheckWithRetriesSkipsOngoingOutage(t *testing.T) {
+	job, scripted, waits := newRetryJob(StatusDown, 3, StatusDown)
+
+	hb, _ := job.checkWithRetries(scripted)
+	if hb.Status != StatusDown || hb.Message != "scripted" || len(*waits) != 0 {
+		t.Errorf("got %q after %d waits, want the failure as is during an outage", hb.Message, len(*waits))
+	}
+}
+
+func TestValidateRetries(t *testing.T) {
+	tests := []struct {
+		retries, retryInterval int
+		wantErr                bool
+	}{
+		{0, 0, false},
+		{3, 0, false},
+		{3, 15, false},
+		{11, 0, true},
+		{-1, 0, true},
+		{2, -5, true},
+		{3, 20, true}, // a minute of retries doesn't fit a 60s interval
+		{0, 120, false},
+	}
+
+	for _, tt := range tests {
+		err := ValidateRetries(&Monitor{Interval: 60, Retries: tt.retries, RetryInterval: tt.retryInterval})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateRetries(retries %d, retry_interval %d) = %v, want error %v", tt.retries, tt.retryInterval, err, tt.wantErr)
+		}
+	}
+}
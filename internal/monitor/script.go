@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// maxScriptOutput caps how much of a script's output is kept for the message
+const maxScriptOutput = 1024
+
+// scriptWaitDelay is how long a timed out script's output pipes may stay
+// open, e.g. held by a child process, before they are closed
+const scriptWaitDelay = 2 * time.Second
+
+// ScriptMonitor runs a command and is up when it exits with status 0. The
+// command can do anything the server's user can, so it is only registered
+// when SCRIPT_MONITOR_ENABLED is set, and only admins may create one.
+type ScriptMonitor struct{}
+
+// NewScriptMonitor creates a script monitor
+func NewScriptMonitor() *ScriptMonitor {
+	return &ScriptMonitor{}
+}
+
+func (s *ScriptMonitor) Name() string {
+	return "script"
+}
+
+func (s *ScriptMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, error) {
+	heartbeat := &Heartbeat{
+		MonitorID: monitor.ID,
+		Time:      time.Now(),
+		Status:    StatusDown,
+	}
+
+	path, err := exec.LookPath(monitor.URL)
+	if err != nil {
+		heartbeat.Message = fmt.Sprintf("Command not found: %s", monitor.URL)
+		return heartbeat, nil
+	}
+	args, err := scriptArgs(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	timeout := time.Duration(monitor.Timeout) * time.Second
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// Arguments are passed as they are, never through a shell, and the
+	// script doesn't inherit the server's environment and its secrets
+	cmd := exec.CommandContext(ctx, path, args...)
+	cmd.Env = []string{"PATH=" + os.Getenv("PATH")}
+	cmd.WaitDelay = scriptWaitDelay
+	output := &cappedBuffer{limit: maxScriptOutput}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	start := time.Now()
+	err = cmd.Run()
+	heartbeat.Ping = int(time.Since(start).Milliseconds())
+
+	var exitErr *exec.ExitError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		heartbeat.Message = withOutput(fmt.Sprintf("Timed out after %s", timeout), output)
+	case err == nil:
+		heartbeat.Status = StatusUp
+		heartbeat.Message = withOutput("Exit code 0", output)
+	case errors.As(err, &exitErr):
+		heartbeat.Message = withOutput(fmt.Sprintf("Exit code %d", exitErr.ExitCode()), output)
+	default:
+		heartbeat.Message = fmt.Sprintf("Failed to run command: %v", err)
+	}
+
+	return heartbeat, nil
+}
+
+func (s *ScriptMonitor) Validate(monitor *Monitor) error {
+	if strings.TrimSpace(monitor.URL) == "" {
+		return fmt.Errorf("command is required")
+	}
+	if _, err := exec.LookPath(monitor.URL); err != nil {
+		return fmt.Errorf("command not found: %s", monitor.URL)
+	}
+	if _, err := scriptArgs(monitor.Config); err != nil {
+		return err
+	}
+	return nil
+}
+
+// scriptArgs returns the optional args config, a list of strings
+func scriptArgs(config map[string]interface{}) ([]string, error) {
+	switch raw := config["args"].(type) {
+	case nil:
+		return nil, nil
+	case []string:
+		return raw, nil
+	case []interface{}:
+		args := make([]string, len(raw))
+		for i, arg := range raw {
+			s, ok := arg.(string)
+			if !ok {
+				return nil, fmt.Errorf("args must be a list of strings")
+			}
+			args[i] = s
+		}
+		return args, nil
+	default:
+		return nil, fmt.Errorf("args must be a list of strings")
+	}
+}
+
+// withOutput appends a script's output to a status message
+func withOutput(message string, output *cappedBuffer) string {
+	if text := output.String(); text != "" {
+		return message + ": " + text
+	}
+	return message
+}
+
+// cappedBuffer keeps the first limit bytes written to it and drops the rest.
+// exec.Cmd serializes writes when Stdout and Stderr are the same buffer.
+type cappedBuffer struct {
+	limit     int
+	data      []byte
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - len(b.data); room < len(p) {
+		b.data = append(b.data, p[:max(room, 0)]...)
+		b.truncated = true
+	} else {
+		b.data = append(b.data, p...)
+	}
+	return len(p), nil
+}
+
+// String returns the kept output, trimmed and with invalid UTF-8 replaced
+func (b *cappedBuffer) String() string {
+	text := strings.TrimSpace(strings.ToValidUTF8(string(b.data), "�"))
+	if b.truncated {
+		text += "… (truncated)"
+	}
+	return text
+}
@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func runScript(t *testing.T, timeout int, command string, args ...interface{}) *Heartbeat {
+	t.Helper()
+	monitor := &Monitor{ID: 1, Type: "script", URL: command, Timeout: timeout, Config: map[string]interface{}{"args": args}}
+	heartbeat, err := NewScriptMonitor().Check(context.Background(), monitor)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	return heartbeat
+}
+
+func TestScriptMonitorExitCodes(t *testing.T) {
+	up := runScript(t, 5, "sh", "-c", "echo all good")
+	if up.Status != StatusUp || up.Message != "Exit code 0: all good" {
+		t.Errorf("exit 0: status %d, message %q; want up with the output", up.Status, up.Message)
+	}
+
+	down := runScript(t, 5, "sh", "-c", "echo disk full >&2; exit 3")
+	if down.Status != StatusDown || down.Message != "Exit code 3: disk full" {
+		t.Errorf("exit 3: status %d, message %q; want down with stderr", down.Status, down.Message)
+	}
+
+	quiet := runScript(t, 5, "false")
+	if quiet.Status != StatusDown || quiet.Message != "Exit code 1" {
+		t.Errorf("false: status %d, message %q", quiet.Status, quiet.Message)
+	}
+}
+
+func TestScriptMonitorArgsAreNotInterpolated(t *testing.T) {
+	heartbeat := runScript(t, 5, "echo", "$HOME", "; exit 1", "`id`")
+	if heartbeat.Status != StatusUp || heartbeat.Message != "Exit code 0: $HOME ; exit 1 `id`" {
+		t.Errorf("status %d, message %q; want the arguments echoed as they are", heartbeat.Status, heartbeat.Message)
+	}
+}
+
+func TestScriptMonitorDoesNotInheritEnvironment(t *testing.T) {
+	t.Setenv("JWT_SECRET", "server-secret")
+
+	heartbeat := runScript(t, 5, "sh", "-c", "echo ${JWT_SECRET:-unset}")
+	if heartbeat.Message != "Exit code 0: unset" {
+		t.Errorf("message = %q, want the server environment hidden", heartbeat.Message)
+	}
+}
+
+func TestScriptMonitorTimeout(t *testing.T) {
+	heartbeat := runScript(t, 1, "sleep", "10")
+	if heartbeat.Status != StatusDown || !strings.HasPrefix(heartbeat.Message, "Timed out after 1s") {
+		t.Errorf("status %d, message %q; want a timeout", heartbeat.Status, heartbeat.Message)
+	}
+}
+
+func TestScriptMonitorTruncatesOutput(t *testing.T) {
+	heartbeat := runScript(t, 5, "sh", "-c", "head -c 5000 /dev/zero | tr '\\0' x")
+	if !strings.HasSuffix(heartbeat.Message, "… (truncated)") {
+		t.Fatalf("message not truncated: %d bytes", len(heartbeat.Message))
+	}
+	output := strings.TrimPrefix(heartbeat.Message, "Exit code 0: ")
+	if got := strings.Count(output, "x"); got != maxScriptOutput {
+		t.Errorf("kept %d bytes of output, want %d", got, maxScriptOutput)
+	}
+}
+
+func TestScriptMonitorValidate(t *testing.T) {
+	s := NewScriptMonitor()
+
+	valid := []*Monitor{
+		{URL: "true"},
+		{URL: "sh", Config: map[string]interface{}{"args": []interface{}{"-c", "exit 0"}}},
+	}
+	for _, m := range valid {
+		if err := s.Validate(m); err != nil {
+			t.Errorf("Validate(%q, %v): %v", m.URL, m.Config, err)
+		}
+	}
+
+	invalid := []*Monitor{
+		{URL: ""},
+		{URL: "/nonexistent/check-backup"},
+		{URL: "no-such-command-uptime-kabomba"},
+		{URL: "true", Config: map[string]interface{}{"args": "-c exit 0"}},
+		{URL: "true", Config: map[string]interface{}{"args": []interface{}{"-n", 5}}},
+	}
+	for _, m := range invalid {
+		if err := s.Validate(m); err == nil {
+			t.Errorf("Validate(%q, %v) succeeded, want error", m.URL, m.Config)
+		}
+	}
+}
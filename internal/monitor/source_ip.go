@@ -0,0 +1,95 @@
+package monitor
+
+import (
+	"fmt"
+	"net"
+	"time"
+)
+
+// ParseSourceIP parses a source_ip setting and checks that the address is
+// assigned to one of this host's interfaces
+func ParseSourceIP(raw string) (net.IP, error) {
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return nil, fmt.Errorf("source_ip %q is not a valid IP address", raw)
+	}
+
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list interface addresses: %w", err)
+	}
+	for _, addr := range addrs {
+		if ipNet, ok := addr.(*net.IPNet); ok && ipNet.IP.Equal(ip) {
+			return ip, nil
+		}
+	}
+
+	return nil, fmt.Errorf("source_ip %s is not assigned to any interface on this host", raw)
+}
+
+// getSourceIP returns the address checks should egress from: the monitor's
+// source_ip, else the global SOURCE_IP, else nil for default routing.
+// Assignment is checked on validation, not on every check.
+func getSourceIP(monitor *Monitor) (net.IP, error) {
+	raw, _ := monitor.Config["source_ip"].(string)
+	if raw == "" {
+		raw = GetConfig().SourceIP
+	}
+	if raw == "" {
+		return nil, nil
+	}
+
+	ip := net.ParseIP(raw)
+	if ip == nil {
+		return nil, fmt.Errorf("source_ip %q is not a valid IP address", raw)
+	}
+	return ip, nil
+}
+
+// validateSourceIPConfig checks the source_ip setting, if present
+func validateSourceIPConfig(monitor *Monitor) error {
+	raw, ok := monitor.Config["source_ip"]
+	if !ok {
+		return nil
+	}
+	s, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("source_ip must be a string")
+	}
+	if s == "" {
+		return nil
+	}
+	_, err := ParseSourceIP(s)
+	return err
+}
+
+// newCheckDialer builds the dialer for a monitor's connections, bound to its
+// source IP when one is configured. network must be the base network ("tcp"
+// or "udp"); the returned network is pinned to the source IP's family.
+func newCheckDialer(monitor *Monitor, network string) (*net.Dialer, string, error) {
+	dialer := &net.Dialer{
+		Timeout: time.Duration(monitor.Timeout) * time.Second,
+	}
+	network = GetNetworkForIPVersion(network, monitor.IPVersion)
+
+	sourceIP, err := getSourceIP(monitor)
+	if err != nil || sourceIP == nil {
+		return dialer, network, err
+	}
+
+	base := network[:3]
+	switch base {
+	case "udp":
+		dialer.LocalAddr = &net.UDPAddr{IP: sourceIP}
+	default:
+		dialer.LocalAddr = &net.TCPAddr{IP: sourceIP}
+	}
+
+	if sourceIP.To4() != nil {
+		network = base + "4"
+	} else {
+		network = base + "6"
+	}
+
+	return dialer, network, nil
+}
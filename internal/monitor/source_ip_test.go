@@ -0,0 +1,107 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"testing"
+)
+
+func TestNewCheckDialerUsesSourceIP(t *testing.T) {
+	m := &Monitor{Timeout: 5, Config: map[string]interface{}{"source_ip": "127.0.0.1"}}
+
+	dialer, network, err := newCheckDialer(m, "tcp")
+	if err != nil {
+		t.Fatalf("newCheckDialer: %v", err)
+	}
+	local, ok := dialer.LocalAddr.(*net.TCPAddr)
+	if !ok || !local.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Fatalf("LocalAddr = %v, want 127.0.0.1", dialer.LocalAddr)
+	}
+	if network != "tcp4" {
+		t.Errorf("network = %s, want tcp4 to match the source IP", network)
+	}
+
+	dialer, network, err = newCheckDialer(m, "udp")
+	if err != nil {
+		t.Fatalf("newCheckDialer: %v", err)
+	}
+	if _, ok := dialer.LocalAddr.(*net.UDPAddr); !ok || network != "udp4" {
+		t.Errorf("udp dialer = (%v, %s), want UDP local address on udp4", dialer.LocalAddr, network)
+	}
+}
+
+func TestNewCheckDialerDefaultRouting(t *testing.T) {
+	dialer, network, err := newCheckDialer(&Monitor{Timeout: 5, Config: map[string]interface{}{}}, "tcp")
+	if err != nil {
+		t.Fatalf("newCheckDialer: %v", err)
+	}
+	if dialer.LocalAddr != nil {
+		t.Errorf("LocalAddr = %v, want nil without source_ip", dialer.LocalAddr)
+	}
+	if network != "tcp" {
+		t.Errorf("network = %s, want tcp", network)
+	}
+}
+
+func TestNewCheckDialerGlobalSourceIP(t *testing.T) {
+	SetConfig(&MonitorConfig{SourceIP: "127.0.0.1"})
+	defer SetConfig(nil)
+
+	dialer, _, err := newCheckDialer(&Monitor{Timeout: 5, Config: map[string]interface{}{}}, "tcp")
+	if err != nil {
+		t.Fatalf("newCheckDialer: %v", err)
+	}
+	if dialer.LocalAddr == nil {
+		t.Fatal("LocalAddr = nil, want the global SOURCE_IP")
+	}
+}
+
+func TestTCPMonitorConnectsFromSourceIP(t *testing.T) {
+	listener, err := net.Listen("tcp4", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer listener.Close()
+
+	remote := make(chan net.Addr, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		remote <- conn.RemoteAddr()
+		conn.Close()
+	}()
+
+	m := &Monitor{
+		URL:     "127.0.0.1",
+		Timeout: 5,
+		Config: map[string]interface{}{
+			"port":      float64(listener.Addr().(*net.TCPAddr).Port),
+			"source_ip": "127.0.0.1",
+		},
+	}
+	hb, err := (&TCPMonitor{}).Check(context.Background(), m)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if hb.Status != StatusUp {
+		t.Fatalf("status = %d (%s), want up", hb.Status, hb.Message)
+	}
+	if addr := (<-remote).(*net.TCPAddr); !addr.IP.Equal(net.ParseIP("127.0.0.1")) {
+		t.Errorf("connection came from %s, want 127.0.0.1", addr.IP)
+	}
+}
+
+func TestParseSourceIP(t *testing.T) {
+	if _, err := ParseSourceIP("127.0.0.1"); err != nil {
+		t.Errorf("ParseSourceIP(127.0.0.1): %v", err)
+	}
+	if _, err := ParseSourceIP("not-an-ip"); err == nil {
+		t.Error("ParseSourceIP accepted an invalid address")
+	}
+	// TEST-NET-3, never assigned to a local interface
+	if _, err := ParseSourceIP("203.0.113.77"); err == nil {
+		t.Error("ParseSourceIP accepted an address not assigned to this host")
+	}
+}
@@ -3,6 +3,7 @@ package monitor
 import (
 	"fmt"
 	"net"
+	"net/netip"
 	"net/url"
 	"strings"
 )
@@ -11,6 +12,16 @@ import (
 type SSRFProtection struct {
 	allowPrivateIPs        bool
 	allowMetadataEndpoints bool
+	allowedCIDRs           []netip.Prefix  // private targets allowed even when private IPs are blocked
+	allowedHosts           map[string]bool // lowercase host names, likewise
+}
+
+// metadataIPs are the cloud metadata addresses, which the allowlist never
+// opens up; only allowMetadataEndpoints does
+var metadataIPs = []netip.Addr{
+	netip.MustParseAddr("169.254.169.254"),
+	netip.MustParseAddr("169.254.170.2"),
+	netip.MustParseAddr("fd00:ec2::254"),
 }
 
 // NewSSRFProtection creates a new SSRF protection validator
@@ -21,6 +32,22 @@ func NewSSRFProtection(allowPrivateIPs bool, allowMetadataEndpoints bool) *SSRFP
 	}
 }
 
+// WithAllowlist lets the given CIDRs and host names through even when private
+// IPs are blocked. Everything else private stays blocked.
+func (s *SSRFProtection) WithAllowlist(cidrs []netip.Prefix, hosts []string) *SSRFProtection {
+	s.allowedCIDRs = cidrs
+	s.allowedHosts = make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		s.allowedHosts[normalizeHostname(host)] = true
+	}
+	return s
+}
+
+// normalizeHostname lowercases a host name and drops a trailing dot
+func normalizeHostname(hostname string) string {
+	return strings.TrimSuffix(strings.ToLower(hostname), ".")
+}
+
 // ValidateURL validates a URL against SSRF attacks
 func (s *SSRFProtection) ValidateURL(rawURL string) error {
 	// Parse URL
@@ -40,6 +67,18 @@ func (s *SSRFProtection) ValidateURL(rawURL string) error {
 		return fmt.Errorf("URL must have a hostname")
 	}
 
+	return s.ValidateHost(hostname)
+}
+
+// revalidateURL checks a URL that passed validation when its monitor was
+// saved again right before it is requested, as its host may resolve to a
+// blocked address by now
+func revalidateURL(rawURL string) error {
+	return GetConfig().SSRFProtection().ValidateURL(rawURL)
+}
+
+// ValidateHost validates a bare hostname or IP against SSRF attacks
+func (s *SSRFProtection) ValidateHost(hostname string) error {
 	// Check for blocked hostnames
 	if s.isBlockedHostname(hostname) {
 		return fmt.Errorf("access to this hostname is not allowed")
@@ -55,8 +94,13 @@ func (s *SSRFProtection) ValidateURL(rawURL string) error {
 		return fmt.Errorf("hostname does not resolve to any IP address")
 	}
 
-	// Check each resolved IP
+	// Check each resolved IP. An allowlisted host name may resolve to
+	// private addresses, but not to a metadata endpoint.
+	hostAllowed := s.allowedHosts[normalizeHostname(hostname)]
 	for _, ip := range ips {
+		if hostAllowed && !s.isBlockedMetadataIP(ip) {
+			continue
+		}
 		if err := s.validateIP(ip); err != nil {
 			return fmt.Errorf("IP address %s is not allowed: %w", ip.String(), err)
 		}
@@ -81,7 +125,7 @@ func (s *SSRFProtection) isBlockedHostname(hostname string) bool {
 
 	for _, blocked := range localhostVariations {
 		if hostname == blocked {
-			return !s.allowPrivateIPs
+			return !s.allowPrivateIPs && !s.isAllowedHostname(hostname)
 		}
 	}
 
@@ -111,6 +155,11 @@ func (s *SSRFProtection) validateIP(ip net.IP) error {
 		return nil
 	}
 
+	// Allowlisted addresses pass, except metadata endpoints
+	if s.isAllowedIP(ip) && !s.isBlockedMetadataIP(ip) {
+		return nil
+	}
+
 	// Check for private IP ranges
 	if s.isPrivateIP(ip) {
 		return fmt.Errorf("access to private IP addresses is not allowed")
@@ -139,6 +188,52 @@ func (s *SSRFProtection) validateIP(ip net.IP) error {
 	return nil
 }
 
+// isAllowedHostname checks if a host name, or the IP literal it is, is
+// allowlisted
+func (s *SSRFProtection) isAllowedHostname(hostname string) bool {
+	if s.allowedHosts[normalizeHostname(hostname)] {
+		return true
+	}
+	if ip := net.ParseIP(strings.Trim(hostname, "[]")); ip != nil {
+		return s.isAllowedIP(ip)
+	}
+	return false
+}
+
+// isAllowedIP checks if an IP is in an allowlisted CIDR
+func (s *SSRFProtection) isAllowedIP(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range s.allowedCIDRs {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlockedMetadataIP checks if an IP is a cloud metadata endpoint that
+// isn't allowed
+func (s *SSRFProtection) isBlockedMetadataIP(ip net.IP) bool {
+	if s.allowMetadataEndpoints {
+		return false
+	}
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, metadata := range metadataIPs {
+		if addr == metadata {
+			return true
+		}
+	}
+	return false
+}
+
 // isPrivateIP checks if an IP is in a private range
 func (s *SSRFProtection) isPrivateIP(ip net.IP) bool {
 	// Private IPv4 ranges
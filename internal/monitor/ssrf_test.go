@@ -0,0 +1,49 @@
+package monitor
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestSSRFAllowlist(t *testing.T) {
+	s := NewSSRFProtection(false, false).WithAllowlist(
+		[]netip.Prefix{
+			netip.MustParsePrefix("10.0.5.20/32"),
+			netip.MustParsePrefix("192.168.10.0/24"),
+			netip.MustParsePrefix("169.254.0.0/16"),
+		},
+		[]string{"LocalHost."},
+	)
+
+	for _, host := range []string{"10.0.5.20", "192.168.10.7", "localhost"} {
+		if err := s.ValidateHost(host); err != nil {
+			t.Errorf("ValidateHost(%q) = %v, want allowed", host, err)
+		}
+	}
+	if err := s.ValidateURL("http://10.0.5.20:8080/health"); err != nil {
+		t.Errorf("ValidateURL of an allowlisted IP = %v, want allowed", err)
+	}
+
+	for _, host := range []string{"10.0.5.21", "192.168.1.1", "172.16.0.1", "127.0.0.1", "169.254.169.254"} {
+		if err := s.ValidateHost(host); err == nil {
+			t.Errorf("ValidateHost(%q) succeeded, want blocked", host)
+		}
+	}
+}
+
+func TestSSRFAllowlistedLoopbackLiteral(t *testing.T) {
+	s := NewSSRFProtection(false, false).WithAllowlist([]netip.Prefix{netip.MustParsePrefix("127.0.0.1/32")}, nil)
+	if err := s.ValidateHost("127.0.0.1"); err != nil {
+		t.Errorf("ValidateHost(127.0.0.1) = %v, want allowed", err)
+	}
+	if err := s.ValidateHost("localhost.localdomain"); err == nil {
+		t.Error("ValidateHost(localhost.localdomain) succeeded, want blocked")
+	}
+}
+
+func TestSSRFAllowlistMetadataEndpoints(t *testing.T) {
+	s := NewSSRFProtection(false, true).WithAllowlist([]netip.Prefix{netip.MustParsePrefix("169.254.169.254/32")}, nil)
+	if err := s.ValidateHost("169.254.169.254"); err != nil {
+		t.Errorf("ValidateHost(169.254.169.254) = %v, want allowed with metadata endpoints enabled", err)
+	}
+}
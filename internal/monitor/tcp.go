@@ -41,27 +41,61 @@ func (t *TCPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, e
 	// Construct address
 	address := fmt.Sprintf("%s:%d", host, port)
 
-	// Create dialer with timeout
-	dialer := &net.Dialer{
-		Timeout: time.Duration(monitor.Timeout) * time.Second,
+	// Create dialer with timeout, bound to the source IP if configured
+	dialer, network, err := newCheckDialer(monitor, "tcp")
+	if err != nil {
+		heartbeat.Status = StatusDown
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
 	}
 
-	// Determine network based on IP version preference
-	network := GetNetworkForIPVersion("tcp", monitor.IPVersion)
+	proxyURL, err := getProxyURL(monitor)
+	if err != nil {
+		heartbeat.Status = StatusDown
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
 
 	// Measure connection time
 	start := time.Now()
-	conn, err := dialer.DialContext(ctx, network, address)
+	var conn net.Conn
+	if proxyURL != nil {
+		conn, err = dialThroughProxy(ctx, dialer, network, proxyURL, address)
+	} else {
+		conn, err = dialer.DialContext(ctx, network, address)
+	}
 	ping := time.Since(start).Milliseconds()
 
 	if err != nil {
 		heartbeat.Status = StatusDown
 		heartbeat.Ping = int(ping)
 		heartbeat.Message = fmt.Sprintf("Connection failed: %v", err)
+		heartbeat.HardError = isHardError(err)
 		return heartbeat, nil
 	}
 	defer conn.Close()
 
+	// Through a proxy the connection ends at the proxy, not the target
+	if proxyURL == nil {
+		heartbeat.RemoteAddr = remoteIP(conn.RemoteAddr())
+	}
+
+	// Upgrade to TLS the way the protocol does, and time the whole exchange
+	if protocol, _ := monitor.Config["starttls"].(string); protocol != "" {
+		summary, err := checkStartTLS(ctx, conn, monitor, protocol, host)
+		ping = time.Since(start).Milliseconds()
+		heartbeat.Ping = int(ping)
+		if err != nil {
+			heartbeat.Status = StatusDown
+			heartbeat.Message = fmt.Sprintf("STARTTLS (%s) failed: %v", protocol, err)
+			heartbeat.HardError = isHardError(err)
+			return heartbeat, nil
+		}
+		heartbeat.Status = StatusUp
+		heartbeat.Message = fmt.Sprintf("STARTTLS (%s) OK - %s - %dms", protocol, summary, ping)
+		return heartbeat, nil
+	}
+
 	heartbeat.Status = StatusUp
 	heartbeat.Ping = int(ping)
 	heartbeat.Message = fmt.Sprintf("Port %d is open - %dms", port, ping)
@@ -74,6 +108,28 @@ func (t *TCPMonitor) Validate(monitor *Monitor) error {
 		return fmt.Errorf("host is required")
 	}
 
+	if err := validateProxyConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateSourceIPConfig(monitor); err != nil {
+		return err
+	}
+
+	if err := validateStartTLSConfig(monitor); err != nil {
+		return err
+	}
+
+	// The proxy resolves and connects to the target, so apply the same SSRF
+	// rules HTTP monitors use before handing it off
+	if proxyURL, _ := monitor.Config["proxy_url"].(string); proxyURL != "" {
+		cfg := GetConfig()
+		ssrfProtection := cfg.SSRFProtection()
+		if err := ssrfProtection.ValidateHost(monitor.URL); err != nil {
+			return fmt.Errorf("host validation failed: %w", err)
+		}
+	}
+
 	// Validate port if provided
 	if port, ok := monitor.Config["port"]; ok {
 		if p, ok := port.(float64); ok {
@@ -0,0 +1,225 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// starttlsProtocols upgrade a plaintext connection to TLS the way each
+// protocol does, leaving the connection ready for the TLS handshake
+var starttlsProtocols = map[string]func(conn net.Conn, r *bufio.Reader) error{
+	"smtp":     smtpStartTLS,
+	"imap":     imapStartTLS,
+	"postgres": postgresStartTLS,
+}
+
+// validateStartTLSConfig checks the optional starttls protocol and the TLS
+// settings the handshake uses
+func validateStartTLSConfig(monitor *Monitor) error {
+	raw, ok := monitor.Config["starttls"]
+	if !ok || raw == nil {
+		return nil
+	}
+	protocol, ok := raw.(string)
+	if !ok {
+		return fmt.Errorf("starttls must be a string")
+	}
+	if protocol == "" {
+		return nil
+	}
+	if _, ok := starttlsProtocols[protocol]; !ok {
+		return fmt.Errorf("starttls must be smtp, imap or postgres")
+	}
+	if err := validateCACertConfig(monitor); err != nil {
+		return err
+	}
+	return validateTLSServerNameConfig(monitor)
+}
+
+// checkStartTLS upgrades conn with the protocol's STARTTLS command and
+// completes the TLS handshake, verifying the certificate against host
+// unless ignore_tls is set. It describes the negotiated session.
+func checkStartTLS(ctx context.Context, conn net.Conn, monitor *Monitor, protocol, host string) (string, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	r := bufio.NewReader(conn)
+	if err := starttlsProtocols[protocol](conn, r); err != nil {
+		return "", err
+	}
+	// Anything buffered past the upgrade reply was sent in plaintext after
+	// it, which a server must not do
+	if r.Buffered() > 0 {
+		return "", fmt.Errorf("server sent data before the TLS handshake")
+	}
+
+	tlsConfig := &tls.Config{ServerName: host}
+	if serverName, _ := monitor.Config["tls_server_name"].(string); serverName != "" {
+		tlsConfig.ServerName = strings.ToLower(strings.TrimSuffix(serverName, "."))
+	}
+	if ignoreTLS, _ := monitor.Config["ignore_tls"].(bool); ignoreTLS {
+		tlsConfig.InsecureSkipVerify = true
+	}
+	if caCert, _ := monitor.Config["ca_cert"].(string); strings.TrimSpace(caCert) != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM([]byte(caCert)) {
+			return "", fmt.Errorf("failed to parse ca_cert")
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	tlsConn := tls.Client(conn, tlsConfig)
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return "", fmt.Errorf("TLS handshake failed: %w", err)
+	}
+
+	state := tlsConn.ConnectionState()
+	summary := tls.VersionName(state.Version)
+	if len(state.PeerCertificates) > 0 {
+		summary += " - " + certificateExpiry(state.PeerCertificates[0], time.Now())
+	}
+	return summary, nil
+}
+
+// certificateExpiry describes when a certificate expires, in whole days
+func certificateExpiry(cert *x509.Certificate, now time.Time) string {
+	left := cert.NotAfter.Sub(now)
+	if left < 0 {
+		return fmt.Sprintf("certificate expired %s", cert.NotAfter.UTC().Format("2006-01-02"))
+	}
+	days := int(left / (24 * time.Hour))
+	if days == 1 {
+		return "certificate expires in 1 day"
+	}
+	return fmt.Sprintf("certificate expires in %d days", days)
+}
+
+// smtpStartTLS greets the server with EHLO and sends STARTTLS once the
+// server offers it (RFC 3207)
+func smtpStartTLS(conn net.Conn, r *bufio.Reader) error {
+	if _, _, err := readSMTPReply(r, 220); err != nil {
+		return fmt.Errorf("greeting: %w", err)
+	}
+	if _, err := io.WriteString(conn, "EHLO uptime-kabomba\r\n"); err != nil {
+		return err
+	}
+	_, extensions, err := readSMTPReply(r, 250)
+	if err != nil {
+		return fmt.Errorf("EHLO: %w", err)
+	}
+	offered := false
+	for _, extension := range extensions[1:] {
+		if name, _, _ := strings.Cut(extension, " "); strings.EqualFold(name, "STARTTLS") {
+			offered = true
+		}
+	}
+	if !offered {
+		return fmt.Errorf("server does not offer STARTTLS")
+	}
+	if _, err := io.WriteString(conn, "STARTTLS\r\n"); err != nil {
+		return err
+	}
+	if _, _, err := readSMTPReply(r, 220); err != nil {
+		return fmt.Errorf("STARTTLS: %w", err)
+	}
+	return nil
+}
+
+// readSMTPReply reads a possibly multi-line SMTP reply and checks its code.
+// It returns the code and the text of each line.
+func readSMTPReply(r *bufio.Reader, want int) (int, []string, error) {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return 0, nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if len(line) < 3 {
+			return 0, nil, fmt.Errorf("malformed reply %q", line)
+		}
+		var code int
+		if _, err := fmt.Sscanf(line[:3], "%d", &code); err != nil {
+			return 0, nil, fmt.Errorf("malformed reply %q", line)
+		}
+		text := ""
+		if len(line) > 4 {
+			text = line[4:]
+		}
+		lines = append(lines, text)
+		if len(line) == 3 || line[3] == ' ' {
+			if code != want {
+				return code, lines, fmt.Errorf("unexpected reply %q", line)
+			}
+			return code, lines, nil
+		}
+	}
+}
+
+// imapStartTLS waits for the untagged OK greeting and sends a tagged
+// STARTTLS command (RFC 3501)
+func imapStartTLS(conn net.Conn, r *bufio.Reader) error {
+	greeting, err := r.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("greeting: %w", err)
+	}
+	if !strings.HasPrefix(strings.ToUpper(greeting), "* OK") {
+		return fmt.Errorf("unexpected greeting %q", strings.TrimSpace(greeting))
+	}
+	if _, err := io.WriteString(conn, "a1 STARTTLS\r\n"); err != nil {
+		return err
+	}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("STARTTLS: %w", err)
+		}
+		// Untagged responses may come first
+		if strings.HasPrefix(line, "* ") {
+			continue
+		}
+		if !strings.HasPrefix(strings.ToUpper(line), "A1 OK") {
+			return fmt.Errorf("STARTTLS: unexpected reply %q", strings.TrimSpace(line))
+		}
+		return nil
+	}
+}
+
+// postgresSSLRequestCode is the protocol version PostgreSQL's SSLRequest
+// message carries in place of a real one
+const postgresSSLRequestCode = 80877103
+
+// postgresStartTLS sends an SSLRequest, which the server answers with a
+// single S when it will negotiate TLS
+func postgresStartTLS(conn net.Conn, r *bufio.Reader) error {
+	request := make([]byte, 8)
+	binary.BigEndian.PutUint32(request[0:4], 8)
+	binary.BigEndian.PutUint32(request[4:8], postgresSSLRequestCode)
+	if _, err := conn.Write(request); err != nil {
+		return err
+	}
+	answer, err := r.ReadByte()
+	if err != nil {
+		return fmt.Errorf("SSLRequest: %w", err)
+	}
+	switch answer {
+	case 'S':
+		return nil
+	case 'N':
+		return fmt.Errorf("server does not support SSL")
+	default:
+		return fmt.Errorf("SSLRequest: unexpected answer %q", answer)
+	}
+}
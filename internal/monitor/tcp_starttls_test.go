@@ -0,0 +1,141 @@
+package monitor
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// newSMTPServer serves SMTP on a local port, offering STARTTLS with cert
+// when it is set. It returns the port.
+func newSMTPServer(t *testing.T, cert *tls.Certificate) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go serveSMTP(conn, cert)
+		}
+	}()
+	return listener.Addr().(*net.TCPAddr).Port
+}
+
+func serveSMTP(conn net.Conn, cert *tls.Certificate) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	io.WriteString(conn, "220 mail.test ESMTP ready\r\n")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		switch command := strings.ToUpper(strings.TrimSpace(line)); {
+		case strings.HasPrefix(command, "EHLO"):
+			if cert != nil {
+				io.WriteString(conn, "250-mail.test\r\n250-SIZE 10240000\r\n250 STARTTLS\r\n")
+			} else {
+				io.WriteString(conn, "250-mail.test\r\n250 SIZE 10240000\r\n")
+			}
+		case command == "STARTTLS" && cert != nil:
+			io.WriteString(conn, "220 Ready to start TLS\r\n")
+			tlsConn := tls.Server(conn, &tls.Config{Certificates: []tls.Certificate{*cert}})
+			if tlsConn.Handshake() == nil {
+				io.Copy(io.Discard, tlsConn)
+			}
+			return
+		default:
+			io.WriteString(conn, "502 Command not recognized\r\n")
+		}
+	}
+}
+
+func TestTCPMonitorSMTPStartTLS(t *testing.T) {
+	server, caPEM := newPrivateCAServer(t)
+	cert := server.TLS.Certificates[0]
+
+	tests := []struct {
+		name        string
+		offerTLS    bool
+		config      map[string]interface{}
+		wantStatus  int
+		wantMessage string
+		wantHard    bool
+	}{
+		{"trusted certificate", true, map[string]interface{}{"ca_cert": caPEM}, StatusUp, "STARTTLS (smtp) OK - TLS 1.3 - certificate expires in 0 days", false},
+		{"untrusted certificate", true, map[string]interface{}{}, StatusDown, "STARTTLS (smtp) failed: TLS handshake failed: ", true},
+		{"verification skipped", true, map[string]interface{}{"ignore_tls": true}, StatusUp, "STARTTLS (smtp) OK - TLS 1.3", false},
+		{"not offered", false, map[string]interface{}{"ca_cert": caPEM}, StatusDown, "STARTTLS (smtp) failed: server does not offer STARTTLS", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var offered *tls.Certificate
+			if tt.offerTLS {
+				offered = &cert
+			}
+			port := newSMTPServer(t, offered)
+
+			config := map[string]interface{}{"port": float64(port), "starttls": "smtp"}
+			for k, v := range tt.config {
+				config[k] = v
+			}
+			m := &Monitor{ID: 1, Type: "tcp", URL: "127.0.0.1", Timeout: 5, Config: config}
+
+			hb, err := (&TCPMonitor{}).Check(context.Background(), m)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if hb.Status != tt.wantStatus || !strings.HasPrefix(hb.Message, tt.wantMessage) {
+				t.Errorf("got %d %q, want %d starting with %q", hb.Status, hb.Message, tt.wantStatus, tt.wantMessage)
+			}
+			if hb.HardError != tt.wantHard {
+				t.Errorf("HardError = %v, want %v", hb.HardError, tt.wantHard)
+			}
+		})
+	}
+}
+
+func TestPostgresStartTLSRefused(t *testing.T) {
+	client, server := net.Pipe()
+	defer client.Close()
+	go func() {
+		defer server.Close()
+		request := make([]byte, 8)
+		if _, err := io.ReadFull(server, request); err == nil {
+			server.Write([]byte("N"))
+		}
+	}()
+
+	err := postgresStartTLS(client, bufio.NewReader(client))
+	if err == nil || err.Error() != "server does not support SSL" {
+		t.Errorf("postgresStartTLS = %v, want SSL refused", err)
+	}
+}
+
+func TestValidateStartTLSConfig(t *testing.T) {
+	for _, protocol := range []interface{}{nil, "", "smtp", "imap", "postgres"} {
+		if err := validateStartTLSConfig(&Monitor{Config: map[string]interface{}{"starttls": protocol}}); err != nil {
+			t.Errorf("starttls %v: %v", protocol, err)
+		}
+	}
+	for _, protocol := range []interface{}{"pop3", "SMTP", 25.0} {
+		if err := validateStartTLSConfig(&Monitor{Config: map[string]interface{}{"starttls": protocol}}); err == nil {
+			t.Errorf("starttls %v accepted", protocol)
+		}
+	}
+	bad := map[string]interface{}{"starttls": "smtp", "tls_server_name": "10.0.0.1"}
+	if err := validateStartTLSConfig(&Monitor{Config: bad}); err == nil {
+		t.Error("IP tls_server_name accepted")
+	}
+}
@@ -3,6 +3,7 @@ package monitor
 import (
 	"context"
 	"encoding/json"
+	"net/netip"
 	"time"
 
 	"gorm.io/gorm"
@@ -30,9 +31,13 @@ type Monitor struct {
 	Interval                int                    `json:"interval" gorm:"default:60"`        // seconds
 	Timeout                 int                    `json:"timeout" gorm:"default:30"`         // seconds
 	ResendInterval          int                    `json:"resend_interval" gorm:"default:0"`  // 0=once per downtime period, N=resend every N failures
+	Retries                 int                    `json:"retries" gorm:"default:0"`          // re-checks of a failed check before it counts as down
+	RetryInterval           int                    `json:"retry_interval" gorm:"default:0"`   // seconds between retries, 0=5
 	IPVersion               string                 `json:"ip_version" gorm:"default:'auto'"`  // auto, ipv4, ipv6
+	Priority                int                    `json:"priority" gorm:"default:0"`         // higher runs first when checks are queued
 	Active                  bool                   `json:"active" gorm:"default:true;index"`
 	NotificationsConfigured bool                   `json:"-" gorm:"default:false"`            // true if notifications have been explicitly set
+	EscalationPolicyID      *int                   `json:"escalation_policy_id"`              // nil: no escalation
 	Config                  map[string]interface{} `json:"config" gorm:"-"`                   // Type-specific config (not from DB)
 	ConfigRaw               string                 `json:"-" gorm:"column:config;type:text"`  // JSON storage
 	CreatedAt               time.Time              `json:"created_at"`
@@ -73,6 +78,22 @@ type Heartbeat struct {
 	Important bool      `json:"important" gorm:"default:false;index"`
 	Message   string    `json:"message" gorm:"type:text"`
 	Time      time.Time `json:"time" gorm:"not null;index"`
+
+	// RemoteAddr is the IP that served the check; nil for checks made through
+	// a proxy and for types that don't connect to the target directly
+	RemoteAddr *string `json:"remote_addr"`
+
+	// Checks is how many checks the heartbeat stands for: more than one when
+	// coalesce_interval folded a run of identical statuses into it
+	Checks int `json:"checks" gorm:"default:1"`
+
+	// HardError marks a failure retrying won't fix, such as NXDOMAIN or an
+	// untrusted certificate; it counts toward auto_pause_after
+	HardError bool `json:"-" gorm:"-"`
+
+	// Warning marks a result that needs attention before it fails, such as
+	// a certificate close to expiry. The first of a run sends a warning.
+	Warning bool `json:"-" gorm:"-"`
 }
 
 // TableName specifies the table name for Heartbeat
@@ -98,6 +119,16 @@ var globalConfig *MonitorConfig
 type MonitorConfig struct {
 	AllowPrivateIPs        bool
 	AllowMetadataEndpoints bool
+	SourceIP               string // default local address for checks; empty uses routing
+	AllowedCIDRs           []netip.Prefix // private targets allowed when private IPs are blocked
+	AllowedHosts           []string
+	MinInterval            int // seconds between checks non-admins may set; 0 for no floor
+}
+
+// SSRFProtection returns the SSRF protection for this configuration
+func (c *MonitorConfig) SSRFProtection() *SSRFProtection {
+	return NewSSRFProtection(c.AllowPrivateIPs, c.AllowMetadataEndpoints).
+		WithAllowlist(c.AllowedCIDRs, c.AllowedHosts)
 }
 
 // SetConfig sets the global monitor configuration
@@ -0,0 +1,184 @@
+package monitor
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+)
+
+// maxUDPResponseSize is the largest datagram the UDP monitor reads
+const maxUDPResponseSize = 65535
+
+// UDPMonitor sends a datagram and expects a response. UDP has no
+// connection, so the service is only considered up when it answers.
+type UDPMonitor struct{}
+
+func init() {
+	RegisterMonitorType(&UDPMonitor{})
+}
+
+func (u *UDPMonitor) Name() string {
+	return "udp"
+}
+
+func (u *UDPMonitor) Check(ctx context.Context, monitor *Monitor) (*Heartbeat, error) {
+	heartbeat := &Heartbeat{
+		MonitorID: monitor.ID,
+		Time:      time.Now(),
+		Status:    StatusDown,
+	}
+
+	if monitor.URL == "" {
+		heartbeat.Message = "No host specified"
+		return heartbeat, nil
+	}
+
+	port, _ := monitor.Config["port"].(float64)
+	payload, expected, err := udpPayloads(monitor.Config)
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	address := net.JoinHostPort(monitor.URL, strconv.Itoa(int(port)))
+	timeout := time.Duration(monitor.Timeout) * time.Second
+
+	dialer, network, err := newCheckDialer(monitor, "udp")
+	if err != nil {
+		heartbeat.Message = err.Error()
+		return heartbeat, nil
+	}
+
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		heartbeat.Message = fmt.Sprintf("Failed to open socket: %v", err)
+		return heartbeat, nil
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	conn.SetDeadline(deadline)
+
+	start := time.Now()
+	if _, err := conn.Write(payload); err != nil {
+		heartbeat.Message = fmt.Sprintf("Failed to send payload: %v", err)
+		return heartbeat, nil
+	}
+
+	buf := make([]byte, maxUDPResponseSize)
+	n, err := conn.Read(buf)
+	ping := time.Since(start).Milliseconds()
+	heartbeat.Ping = int(ping)
+
+	if err != nil {
+		var netErr net.Error
+		if errors.As(err, &netErr) && netErr.Timeout() {
+			heartbeat.Message = fmt.Sprintf("No response within %ds", monitor.Timeout)
+		} else {
+			heartbeat.Message = fmt.Sprintf("Read failed: %v", err)
+		}
+		return heartbeat, nil
+	}
+
+	if len(expected) > 0 && !bytes.Contains(buf[:n], expected) {
+		heartbeat.Message = fmt.Sprintf("Unexpected response (%d bytes)", n)
+		return heartbeat, nil
+	}
+
+	heartbeat.Status = StatusUp
+	heartbeat.Message = fmt.Sprintf("Received %d bytes - %dms", n, ping)
+
+	return heartbeat, nil
+}
+
+func (u *UDPMonitor) Validate(monitor *Monitor) error {
+	if monitor.URL == "" {
+		return fmt.Errorf("host is required")
+	}
+
+	cfg := GetConfig()
+	ssrfProtection := cfg.SSRFProtection()
+	if err := ssrfProtection.ValidateHost(monitor.URL); err != nil {
+		return fmt.Errorf("host validation failed: %w", err)
+	}
+
+	port, ok := monitor.Config["port"].(float64)
+	if !ok {
+		return fmt.Errorf("port is required and must be a number")
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535")
+	}
+
+	if _, _, err := udpPayloads(monitor.Config); err != nil {
+		return err
+	}
+
+	if err := validateSourceIPConfig(monitor); err != nil {
+		return err
+	}
+
+	if monitor.Timeout <= 0 {
+		monitor.Timeout = 10
+	}
+
+	return nil
+}
+
+// udpPayloads decodes the request payload and the optional expected
+// response. payload_encoding is "text" (default) or "hex" and applies to both.
+func udpPayloads(config map[string]interface{}) (payload, expected []byte, err error) {
+	encoding, _ := config["payload_encoding"].(string)
+	if encoding == "" {
+		encoding = "text"
+	}
+
+	decode := func(key string) ([]byte, error) {
+		raw, ok := config[key]
+		if !ok || raw == nil {
+			return nil, nil
+		}
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a string", key)
+		}
+		switch encoding {
+		case "text":
+			return []byte(s), nil
+		case "hex":
+			b, err := hex.DecodeString(s)
+			if err != nil {
+				return nil, fmt.Errorf("%s is not valid hex: %w", key, err)
+			}
+			return b, nil
+		default:
+			return nil, fmt.Errorf("payload_encoding must be text or hex")
+		}
+	}
+
+	payload, err = decode("payload")
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(payload) == 0 {
+		return nil, nil, fmt.Errorf("payload is required for UDP monitors")
+	}
+	if len(payload) > maxUDPResponseSize {
+		return nil, nil, fmt.Errorf("payload is too large")
+	}
+
+	expected, err = decode("expected_response")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return payload, expected, nil
+}
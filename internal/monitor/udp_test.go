@@ -0,0 +1,121 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// startUDPEchoServer echoes every datagram back, upper-cased
+func startUDPEchoServer(t *testing.T) int {
+	t.Helper()
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, 2048)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			conn.WriteTo([]byte(strings.ToUpper(string(buf[:n]))), addr)
+		}
+	}()
+
+	return conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func TestUDPMonitorCheck(t *testing.T) {
+	port := startUDPEchoServer(t)
+
+	tests := []struct {
+		name       string
+		config     map[string]interface{}
+		wantStatus int
+	}{
+		{
+			name:       "any response is up",
+			config:     map[string]interface{}{"payload": "ping"},
+			wantStatus: StatusUp,
+		},
+		{
+			name:       "expected response matches",
+			config:     map[string]interface{}{"payload": "ping", "expected_response": "PING"},
+			wantStatus: StatusUp,
+		},
+		{
+			name:       "expected response missing",
+			config:     map[string]interface{}{"payload": "ping", "expected_response": "pong"},
+			wantStatus: StatusDown,
+		},
+		{
+			name:       "hex payload",
+			config:     map[string]interface{}{"payload": "6869", "expected_response": "4849", "payload_encoding": "hex"},
+			wantStatus: StatusUp,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config["port"] = float64(port)
+			monitor := &Monitor{URL: "127.0.0.1", Timeout: 2, Config: tt.config}
+
+			heartbeat, err := (&UDPMonitor{}).Check(context.Background(), monitor)
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if heartbeat.Status != tt.wantStatus {
+				t.Fatalf("status = %d (%s), want %d", heartbeat.Status, heartbeat.Message, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestUDPMonitorNoResponse(t *testing.T) {
+	// Bound but silent socket: datagrams are accepted and never answered
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer conn.Close()
+
+	monitor := &Monitor{
+		URL:     "127.0.0.1",
+		Timeout: 1,
+		Config: map[string]interface{}{
+			"port":    float64(conn.LocalAddr().(*net.UDPAddr).Port),
+			"payload": "ping",
+		},
+	}
+
+	heartbeat, err := (&UDPMonitor{}).Check(context.Background(), monitor)
+	if err != nil {
+		t.Fatalf("Check: %v", err)
+	}
+	if heartbeat.Status != StatusDown {
+		t.Fatalf("status = %d (%s), want down", heartbeat.Status, heartbeat.Message)
+	}
+}
+
+func TestUDPPayloadsValidation(t *testing.T) {
+	invalid := []map[string]interface{}{
+		{},
+		{"payload": ""},
+		{"payload": 42},
+		{"payload": "zz", "payload_encoding": "hex"},
+		{"payload": "ping", "payload_encoding": "base64"},
+		{"payload": "ping", "expected_response": false},
+	}
+
+	for _, config := range invalid {
+		if _, _, err := udpPayloads(config); err == nil {
+			t.Errorf("udpPayloads(%v) succeeded, want error", config)
+		}
+	}
+}
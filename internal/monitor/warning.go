@@ -0,0 +1,50 @@
+package monitor
+
+import (
+	"context"
+	"log"
+)
+
+// trackWarning reports whether a heartbeat starts a run of warnings, which
+// gets one notification, or ends one. The run lasts until an up check, so a
+// warning that is interrupted by a failure does not alert again. Callers
+// hold stateMu.
+func (job *monitorJob) trackWarning(heartbeat *Heartbeat) (started, cleared bool) {
+	if heartbeat.Warning {
+		started = !job.warned
+		job.warned = true
+		return started, false
+	}
+	if heartbeat.Status == StatusUp {
+		cleared = job.warned
+		job.warned = false
+	}
+	return false, cleared
+}
+
+// notifyWarning sends the one alert of a run of warnings
+func (e *Executor) notifyWarning(job *monitorJob, message string) {
+	monitor := job.monitor
+	log.Printf("Monitor %s (ID: %d) warning: %s", monitor.Name, monitor.ID, message)
+
+	if e.dispatcher == nil {
+		return
+	}
+	if err := e.dispatcher.NotifyMonitorWarning(context.Background(), monitor.ID, monitor.Name, "", message); err != nil {
+		log.Printf("Failed to send warning notification for monitor %d: %v", monitor.ID, err)
+	}
+}
+
+// notifyWarningCleared lets channels that keep an alert open for a warning
+// close it once the monitor checks up without one
+func (e *Executor) notifyWarningCleared(job *monitorJob, message string) {
+	monitor := job.monitor
+	log.Printf("Monitor %s (ID: %d) warning cleared", monitor.Name, monitor.ID)
+
+	if e.dispatcher == nil {
+		return
+	}
+	if err := e.dispatcher.NotifyMonitorWarningCleared(context.Background(), monitor.ID, monitor.Name, "", message); err != nil {
+		log.Printf("Failed to send warning cleared notification for monitor %d: %v", monitor.ID, err)
+	}
+}
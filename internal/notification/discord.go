@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 )
 
 // DiscordProvider sends Discord webhook notifications
@@ -43,6 +42,8 @@ func (d *DiscordProvider) Send(ctx context.Context, notification *Notification,
 		color = 0xFF0000 // Red
 	case "maintenance":
 		color = 0x0000FF // Blue
+	case "warning":
+		color = 0xFFA500 // Orange
 	default:
 		color = 0x808080 // Gray
 	}
@@ -55,12 +56,12 @@ func (d *DiscordProvider) Send(ctx context.Context, notification *Notification,
 		"timestamp":   message.Time,
 		"fields": []map[string]interface{}{
 			{
-				"name":   "Monitor",
+				"name":   translate(message.Locale, labelMonitor),
 				"value":  message.MonitorName,
 				"inline": true,
 			},
 			{
-				"name":   "Status",
+				"name":   translate(message.Locale, labelStatus),
 				"value":  message.Status,
 				"inline": true,
 			},
@@ -70,16 +71,25 @@ func (d *DiscordProvider) Send(ctx context.Context, notification *Notification,
 	// Add ping if available
 	if message.Ping > 0 {
 		embed["fields"] = append(embed["fields"].([]map[string]interface{}), map[string]interface{}{
-			"name":   "Response Time",
+			"name":   translate(message.Locale, labelResponseTime),
 			"value":  fmt.Sprintf("%dms", message.Ping),
 			"inline": true,
 		})
 	}
 
+	// Add downtime on recovery
+	if message.DowntimeDuration > 0 {
+		embed["fields"] = append(embed["fields"].([]map[string]interface{}), map[string]interface{}{
+			"name":   translate(message.Locale, labelDowntime),
+			"value":  FormatDuration(message.DowntimeDuration),
+			"inline": true,
+		})
+	}
+
 	// Add URL if available
 	if message.MonitorURL != "" {
 		embed["fields"] = append(embed["fields"].([]map[string]interface{}), map[string]interface{}{
-			"name":   "URL",
+			"name":   translate(message.Locale, labelURL),
 			"value":  message.MonitorURL,
 			"inline": false,
 		})
@@ -105,7 +115,7 @@ func (d *DiscordProvider) Send(ctx context.Context, notification *Notification,
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send Discord webhook: %w", err)
@@ -127,3 +137,8 @@ func (d *DiscordProvider) Validate(config map[string]interface{}) error {
 
 	return nil
 }
+
+func (d *DiscordProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	webhookURL, _ := config["webhook_url"].(string)
+	return probeHTTP(ctx, webhookURL)
+}
@@ -5,62 +5,443 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// DefaultSendConcurrency is how many notifications of one event are sent at
+// once unless configured otherwise
+const DefaultSendConcurrency = 8
+
 // Dispatcher handles sending notifications
 type Dispatcher struct {
 	db *gorm.DB
+
+	// notificationsFor resolves the channels a monitor notifies
+	notificationsFor func(monitorID int) ([]*Notification, error)
+	// notificationsByID loads the active channels among ids
+	notificationsByID func(ids []int) ([]*Notification, error)
+	// receiveAllFor loads the channels of the monitor's owner that receive
+	// every event (nil when there are none to look up)
+	receiveAllFor func(monitorID int) ([]*Notification, error)
+
+	// timezoneFor loads the time zone a user's notifications show times in
+	// (nil shows them in UTC)
+	timezoneFor func(userID int) *time.Location
+
+	// sendConcurrency bounds the fan-out of one event's notifications
+	sendConcurrency int
+
+	// Mass outage coalescing (nil when disabled)
+	coalescer       *outageCoalescer
+	outageThreshold int
+
+	// Flap settling of up and down notifications (nil when disabled)
+	settler *statusSettler
 }
 
 // NewDispatcher creates a new notification dispatcher
 func NewDispatcher(db *gorm.DB) *Dispatcher {
-	return &Dispatcher{db: db}
+	d := &Dispatcher{db: db, sendConcurrency: DefaultSendConcurrency}
+	d.notificationsFor = d.resolveMonitorNotifications
+	d.notificationsByID = d.getNotificationsByID
+	d.receiveAllFor = d.getReceiveAllNotifications
+	d.timezoneFor = d.getUserTimezone
+	return d
 }
 
-// NotifyMonitorDown sends notifications when a monitor goes down
+// SetSendConcurrency sets how many notifications of one event are sent at
+// once. Values below 1 restore DefaultSendConcurrency.
+func (d *Dispatcher) SetSendConcurrency(n int) {
+	if n < 1 {
+		n = DefaultSendConcurrency
+	}
+	d.sendConcurrency = n
+}
+
+// NotifyMonitorDown sends notifications when a monitor goes down.
+// With settling or outage coalescing enabled the notification is held for
+// the window and delivery errors are logged instead of returned.
 func (d *Dispatcher) NotifyMonitorDown(ctx context.Context, monitorID int, monitorName, monitorURL string, ping int, message string) error {
-	return d.sendMonitorNotifications(ctx, monitorID, &Message{
+	msg := &Message{
 		Title:       "Monitor is DOWN",
+		titleKey:    msgMonitorDown,
 		Body:        message,
+		MonitorID:   monitorID,
 		MonitorName: monitorName,
 		MonitorURL:  monitorURL,
 		Status:      "down",
 		Ping:        ping,
 		Time:        time.Now().Format(time.RFC3339),
 		Important:   true,
+	}
+
+	if d.settler != nil {
+		d.settler.add(monitorID, msg)
+		return nil
+	}
+
+	return d.sendDown(ctx, monitorID, msg)
+}
+
+// sendDown sends a down notification, or holds it for the outage batch
+func (d *Dispatcher) sendDown(ctx context.Context, monitorID int, msg *Message) error {
+	if d.coalescer != nil {
+		d.coalescer.add(monitorID, msg)
+		return nil
+	}
+
+	return d.sendMonitorNotifications(ctx, monitorID, msg)
+}
+
+// NotifyMonitorUp sends notifications when a monitor comes back up. downtime
+// is how long the monitor was down, from the first down check of the outage.
+func (d *Dispatcher) NotifyMonitorUp(ctx context.Context, monitorID int, monitorName, monitorURL string, ping int, message string, downtime time.Duration) error {
+	msg := &Message{
+		Title:       "Monitor is UP",
+		titleKey:    msgMonitorUp,
+		Body:        message,
+		MonitorID:   monitorID,
+		MonitorName: monitorName,
+		MonitorURL:  monitorURL,
+		Status:      "up",
+		Ping:        ping,
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   false,
+
+		DowntimeDuration: downtime,
+	}
+
+	if d.settler != nil {
+		d.settler.add(monitorID, msg)
+		return nil
+	}
+
+	return d.sendUp(ctx, monitorID, msg)
+}
+
+// sendUp sends an up notification unless the down alert it follows is
+// still held for the outage batch
+func (d *Dispatcher) sendUp(ctx context.Context, monitorID int, msg *Message) error {
+	// Recovered before its held down alert went out: report neither
+	if d.coalescer != nil && d.coalescer.cancel(monitorID) {
+		return nil
+	}
+
+	return d.sendMonitorNotifications(ctx, monitorID, msg)
+}
+
+// sendSettled sends the status a monitor settled on, logging failures
+func (d *Dispatcher) sendSettled(monitorID int, msg *Message) {
+	send := d.sendUp
+	if msg.Status == "down" {
+		send = d.sendDown
+	}
+	if err := send(context.Background(), monitorID, msg); err != nil {
+		log.Printf("Failed to send %s notification for monitor %d: %v", msg.Status, monitorID, err)
+	}
+}
+
+// NotifyMonitorStalled sends notifications when a monitor has stopped reporting heartbeats.
+// It is delivered as a down alert so every provider treats it as an incident.
+func (d *Dispatcher) NotifyMonitorStalled(ctx context.Context, monitorID int, monitorName, monitorURL string, message string) error {
+	return d.sendMonitorNotifications(ctx, monitorID, &Message{
+		Title:       "Monitor has STALLED",
+		titleKey:    msgMonitorStalled,
+		Body:        message,
+		MonitorID:   monitorID,
+		MonitorName: monitorName,
+		MonitorURL:  monitorURL,
+		Status:      "down",
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
+	})
+}
+
+// NotifyMonitorAutoPaused sends notifications when a monitor has paused
+// itself after repeated hard errors. It is delivered as a down alert, once.
+func (d *Dispatcher) NotifyMonitorAutoPaused(ctx context.Context, monitorID int, monitorName, monitorURL string, message string) error {
+	return d.sendMonitorNotifications(ctx, monitorID, &Message{
+		Title:       "Monitor was AUTO-PAUSED",
+		titleKey:    msgMonitorAutoPaused,
+		Body:        message,
+		MonitorID:   monitorID,
+		MonitorName: monitorName,
+		MonitorURL:  monitorURL,
+		Status:      "down",
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
 	})
 }
 
-// NotifyMonitorUp sends notifications when a monitor comes back up
-func (d *Dispatcher) NotifyMonitorUp(ctx context.Context, monitorID int, monitorName, monitorURL string, ping int, message string) error {
+// NotifyMonitorFlapping sends notifications when a monitor keeps changing
+// between up and down. It is delivered as a down alert, once per flapping
+// period.
+func (d *Dispatcher) NotifyMonitorFlapping(ctx context.Context, monitorID int, monitorName, monitorURL string, message string) error {
 	return d.sendMonitorNotifications(ctx, monitorID, &Message{
+		Title:       "Monitor is FLAPPING",
+		titleKey:    msgMonitorFlapping,
+		Body:        message,
+		MonitorID:   monitorID,
+		MonitorName: monitorName,
+		MonitorURL:  monitorURL,
+		Status:      "down",
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
+	})
+}
+
+// NotifyMonitorWarning sends the alert of a check that still passes but needs
+// attention, such as a certificate close to expiry
+func (d *Dispatcher) NotifyMonitorWarning(ctx context.Context, monitorID int, monitorName, monitorURL string, message string) error {
+	return d.sendMonitorNotifications(ctx, monitorID, &Message{
+		Title:       "Monitor needs ATTENTION",
+		titleKey:    msgMonitorWarning,
+		Body:        message,
+		MonitorID:   monitorID,
+		MonitorName: monitorName,
+		MonitorURL:  monitorURL,
+		Status:      "warning",
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
+	})
+}
+
+// NotifyMonitorWarningCleared closes the warning alert of a monitor that
+// checks up without a warning again. Only channels that keep an alert open,
+// like PagerDuty and Opsgenie, get it; elsewhere the warning was a one-off.
+func (d *Dispatcher) NotifyMonitorWarningCleared(ctx context.Context, monitorID int, monitorName, monitorURL string, message string) error {
+	notifications, err := d.monitorNotifications(monitorID)
+	if err != nil {
+		return err
+	}
+	var trackers []*Notification
+	for _, n := range notifications {
+		if tracksAlerts(n.Type) {
+			trackers = append(trackers, n)
+		}
+	}
+	return d.sendToNotifications(ctx, trackers, &Message{
+		Title:       "Monitor warning CLEARED",
+		titleKey:    msgWarningCleared,
+		Body:        message,
+		MonitorID:   monitorID,
+		MonitorName: monitorName,
+		MonitorURL:  monitorURL,
+		Status:      "warning_cleared",
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   false,
+	})
+}
+
+// NotifyAPIKeyExpiring warns a user through their default notifications
+// that one of their API keys is about to expire
+func (d *Dispatcher) NotifyAPIKeyExpiring(ctx context.Context, userID int, keyName string, message string) error {
+	notifications, err := d.getUserDefaultNotifications(userID)
+	if err != nil {
+		return fmt.Errorf("failed to get default notifications: %w", err)
+	}
+	return d.sendToNotifications(ctx, notifications, &Message{
+		Title:       "API key is about to EXPIRE",
+		titleKey:    msgAPIKeyExpiring,
+		Body:        message,
+		MonitorName: "API key " + keyName,
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
+	})
+}
+
+// NotifyDatabaseSize warns the admins, through their default notifications,
+// that the database grew past a configured size or growth rate
+func (d *Dispatcher) NotifyDatabaseSize(ctx context.Context, message string) error {
+	notifications, err := d.getAdminDefaultNotifications()
+	if err != nil {
+		return fmt.Errorf("failed to get admin notifications: %w", err)
+	}
+	return d.sendToNotifications(ctx, notifications, &Message{
+		Title:       "Database size needs ATTENTION",
+		titleKey:    msgDatabaseSize,
+		Body:        message,
+		MonitorName: "Database",
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
+	})
+}
+
+// NotifyMonitorEscalated sends a still-down alert to the channels of an
+// escalation step, the step'th of the monitor's policy counting from 1.
+// Escalations bypass outage coalescing: they follow an alert already sent.
+func (d *Dispatcher) NotifyMonitorEscalated(ctx context.Context, notificationIDs []int, step int, monitorID int, monitorName, monitorURL string, ping int, message string, downtime time.Duration) error {
+	notifications, err := d.notificationsByID(notificationIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get escalation notifications: %w", err)
+	}
+	return d.sendToNotifications(ctx, notifications, &Message{
+		Title:       fmt.Sprintf("Monitor is still DOWN (escalation step %d)", step),
+		titleKey:    msgMonitorEscalated,
+		titleArgs:   []interface{}{step},
+		Body:        message,
+		MonitorID:   monitorID,
+		MonitorName: monitorName,
+		MonitorURL:  monitorURL,
+		Status:      "down",
+		Ping:        ping,
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
+
+		DowntimeDuration: downtime,
+	})
+}
+
+// NotifyEscalationRecovered tells the channels an outage escalated to that
+// the monitor is back up. Channels the monitor notifies anyway are skipped:
+// NotifyMonitorUp already reached them.
+func (d *Dispatcher) NotifyEscalationRecovered(ctx context.Context, notificationIDs []int, monitorID int, monitorName, monitorURL string, ping int, message string, downtime time.Duration) error {
+	escalated, err := d.notificationsByID(notificationIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get escalation notifications: %w", err)
+	}
+	regular, err := d.monitorNotifications(monitorID)
+	if err != nil {
+		return err
+	}
+	notified := make(map[int]bool, len(regular))
+	for _, n := range regular {
+		notified[n.ID] = true
+	}
+	notifications := make([]*Notification, 0, len(escalated))
+	for _, n := range escalated {
+		if !notified[n.ID] {
+			notifications = append(notifications, n)
+		}
+	}
+
+	return d.sendToNotifications(ctx, notifications, &Message{
 		Title:       "Monitor is UP",
+		titleKey:    msgMonitorUp,
 		Body:        message,
+		MonitorID:   monitorID,
 		MonitorName: monitorName,
 		MonitorURL:  monitorURL,
 		Status:      "up",
 		Ping:        ping,
 		Time:        time.Now().Format(time.RFC3339),
 		Important:   false,
+
+		DowntimeDuration: downtime,
 	})
 }
 
 // sendMonitorNotifications sends notifications to all configured providers for a monitor
 func (d *Dispatcher) sendMonitorNotifications(ctx context.Context, monitorID int, msg *Message) error {
+	notifications, err := d.monitorNotifications(monitorID)
+	if err != nil {
+		return err
+	}
+	return d.sendToNotifications(ctx, notifications, msg)
+}
+
+// monitorNotifications returns every channel an event of the monitor
+// reaches: its own or default channels plus the receive-all ones, each once
+func (d *Dispatcher) monitorNotifications(monitorID int) ([]*Notification, error) {
+	notifications, err := d.notificationsFor(monitorID)
+	if err != nil {
+		return nil, err
+	}
+	if d.receiveAllFor == nil {
+		return notifications, nil
+	}
+
+	receiveAll, err := d.receiveAllFor(monitorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get receive-all notifications: %w", err)
+	}
+	seen := make(map[int]bool, len(notifications))
+	for _, n := range notifications {
+		seen[n.ID] = true
+	}
+	for _, n := range receiveAll {
+		if !seen[n.ID] {
+			seen[n.ID] = true
+			notifications = append(notifications, n)
+		}
+	}
+	return notifications, nil
+}
+
+// sendToNotifications sends msg to every channel outside its quiet hours
+func (d *Dispatcher) sendToNotifications(ctx context.Context, notifications []*Notification, msg *Message) error {
+	notifications = withoutQuietHours(notifications, msg, time.Now())
+
+	concurrency := d.sendConcurrency
+	if concurrency < 1 {
+		concurrency = DefaultSendConcurrency
+	}
+
+	// Send to all notifications, at most concurrency at a time
+	errs := make([]error, len(notifications))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, notif := range notifications {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, n *Notification) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if err := d.sendNotification(ctx, n, msg); err != nil {
+				log.Printf("Failed to send notification via %s (%s): %v", n.Type, n.Name, err)
+				errs[i] = err
+			}
+		}(i, notif)
+	}
+	wg.Wait()
+
+	return sendError(notifications, errs)
+}
+
+// sendError summarizes the failed sends of a fan-out, naming each failing
+// provider type once, e.g. "failed to send 3/10 notifications: slack, webhook
+// failed". It returns nil when every send succeeded.
+func sendError(notifications []*Notification, errs []error) error {
+	failed := 0
+	seen := make(map[string]bool)
+	var providers []string
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		failed++
+		if t := notifications[i].Type; !seen[t] {
+			seen[t] = true
+			providers = append(providers, t)
+		}
+	}
+	if failed == 0 {
+		return nil
+	}
+
+	sort.Strings(providers)
+	return fmt.Errorf("failed to send %d/%d notifications: %s failed", failed, len(notifications), strings.Join(providers, ", "))
+}
+
+// resolveMonitorNotifications returns the channels a monitor notifies,
+// falling back to the defaults when it was never explicitly configured
+func (d *Dispatcher) resolveMonitorNotifications(monitorID int) ([]*Notification, error) {
 	// Get all notifications linked to this monitor
 	notifications, err := d.getMonitorNotifications(monitorID)
 	if err != nil {
-		return fmt.Errorf("failed to get monitor notifications: %w", err)
+		return nil, fmt.Errorf("failed to get monitor notifications: %w", err)
 	}
 
 	// If no active notifications, check if monitor has explicit notification config
 	if len(notifications) == 0 {
 		configured, err := d.monitorHasExplicitNotificationConfig(monitorID)
 		if err != nil {
-			return fmt.Errorf("failed to check monitor notification config: %w", err)
+			return nil, fmt.Errorf("failed to check monitor notification config: %w", err)
 		}
 
 		// If monitor has never been explicitly configured, use defaults
@@ -68,37 +449,12 @@ func (d *Dispatcher) sendMonitorNotifications(ctx context.Context, monitorID int
 		if !configured {
 			notifications, err = d.getDefaultNotifications()
 			if err != nil {
-				return fmt.Errorf("failed to get default notifications: %w", err)
-			}
-		}
-	}
-
-	// Send to all notifications concurrently
-	errCh := make(chan error, len(notifications))
-	for _, notif := range notifications {
-		go func(n *Notification) {
-			if err := d.sendNotification(ctx, n, msg); err != nil {
-				log.Printf("Failed to send notification via %s (%s): %v", n.Type, n.Name, err)
-				errCh <- err
-			} else {
-				errCh <- nil
+				return nil, fmt.Errorf("failed to get default notifications: %w", err)
 			}
-		}(notif)
-	}
-
-	// Collect results
-	var errors []error
-	for i := 0; i < len(notifications); i++ {
-		if err := <-errCh; err != nil {
-			errors = append(errors, err)
 		}
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("failed to send %d/%d notifications", len(errors), len(notifications))
-	}
-
-	return nil
+	return notifications, nil
 }
 
 // sendNotification sends a notification using the appropriate provider
@@ -112,7 +468,55 @@ func (d *Dispatcher) sendNotification(ctx context.Context, notif *Notification,
 		return fmt.Errorf("unknown notification provider: %s", notif.Type)
 	}
 
-	return provider.Send(ctx, notif, msg)
+	// Resolve ${NOTIFY_...} references on a copy, keeping secrets out of the
+	// stored config
+	expanded := *notif
+	expanded.Config = ExpandEnv(notif.Config)
+
+	localized := localize(msg, notificationLocale(expanded.Config))
+	localized.Time = d.ownerTime(localized.Time, notif.UserID)
+
+	start := time.Now()
+	err := provider.Send(ctx, &expanded, localized)
+	recordSend(notif.Type, time.Since(start), err)
+	return err
+}
+
+// ownerTime rewrites an RFC 3339 message time in the time zone of the
+// channel's owner, UTC unless their settings name another
+func (d *Dispatcher) ownerTime(value string, userID int) string {
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	loc := time.UTC
+	if d.timezoneFor != nil {
+		if userLoc := d.timezoneFor(userID); userLoc != nil {
+			loc = userLoc
+		}
+	}
+	return at.In(loc).Format(time.RFC3339)
+}
+
+// getUserTimezone loads the time zone from a user's settings, nil when they
+// have none or it is unknown
+func (d *Dispatcher) getUserTimezone(userID int) *time.Location {
+	if d.db == nil {
+		return nil
+	}
+	var tz string
+	err := d.db.Table("user_settings").
+		Select("timezone").
+		Where("user_id = ?", userID).
+		Scan(&tz).Error
+	if err != nil || tz == "" {
+		return nil
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return nil
+	}
+	return loc
 }
 
 // monitorHasExplicitNotificationConfig checks if notifications have been explicitly configured
@@ -131,86 +535,85 @@ func (d *Dispatcher) monitorHasExplicitNotificationConfig(monitorID int) (bool,
 
 // getMonitorNotifications gets all notifications linked to a monitor
 func (d *Dispatcher) getMonitorNotifications(monitorID int) ([]*Notification, error) {
-	query := `
-		SELECT n.id, n.user_id, n.name, n.type, n.config, n.is_default, n.active, n.created_at, n.updated_at
+	return d.queryNotifications(`
+		SELECT n.id, n.user_id, n.name, n.type, n.config, n.is_default, n.receive_all_events, n.active, n.created_at, n.updated_at
 		FROM notifications n
 		INNER JOIN monitor_notifications mn ON n.id = mn.notification_id
 		WHERE mn.monitor_id = ? AND n.active = true
-	`
-
-	// Use a temporary struct to avoid GORM's issues with map fields
-	type NotificationRow struct {
-		ID        int    `gorm:"column:id"`
-		UserID    int    `gorm:"column:user_id"`
-		Name      string `gorm:"column:name"`
-		Type      string `gorm:"column:type"`
-		ConfigRaw string `gorm:"column:config"`
-		IsDefault bool   `gorm:"column:is_default"`
-		Active    bool   `gorm:"column:active"`
-		CreatedAt string `gorm:"column:created_at"`
-		UpdatedAt string `gorm:"column:updated_at"`
-	}
-
-	var rows []NotificationRow
-	err := d.db.Raw(query, monitorID).Scan(&rows).Error
-	if err != nil {
-		return nil, err
-	}
+	`, monitorID)
+}
 
-	// Convert rows to Notification structs
-	notifications := make([]*Notification, 0, len(rows))
-	for _, row := range rows {
-		notif := &Notification{
-			ID:        row.ID,
-			UserID:    row.UserID,
-			Name:      row.Name,
-			Type:      row.Type,
-			ConfigRaw: row.ConfigRaw,
-			IsDefault: row.IsDefault,
-			Active:    row.Active,
-			CreatedAt: row.CreatedAt,
-			UpdatedAt: row.UpdatedAt,
-		}
+// getDefaultNotifications gets all default notifications for a user
+func (d *Dispatcher) getDefaultNotifications() ([]*Notification, error) {
+	return d.queryNotifications(`
+		SELECT id, user_id, name, type, config, is_default, receive_all_events, active, created_at, updated_at
+		FROM notifications
+		WHERE is_default = true AND active = true
+	`)
+}
 
-		// Parse config JSON
-		if notif.ConfigRaw != "" {
-			var config map[string]interface{}
-			if err := json.Unmarshal([]byte(notif.ConfigRaw), &config); err != nil {
-				log.Printf("Failed to parse notification config for %s: %v", notif.Name, err)
-				continue
-			}
-			notif.Config = config
-		}
+// getUserDefaultNotifications gets the active default notifications of a user
+func (d *Dispatcher) getUserDefaultNotifications(userID int) ([]*Notification, error) {
+	return d.queryNotifications(`
+		SELECT id, user_id, name, type, config, is_default, receive_all_events, active, created_at, updated_at
+		FROM notifications
+		WHERE is_default = true AND active = true AND user_id = ?
+	`, userID)
+}
 
-		notifications = append(notifications, notif)
-	}
+// getAdminDefaultNotifications gets the active default notifications of
+// admin users
+func (d *Dispatcher) getAdminDefaultNotifications() ([]*Notification, error) {
+	return d.queryNotifications(`
+		SELECT id, user_id, name, type, config, is_default, receive_all_events, active, created_at, updated_at
+		FROM notifications
+		WHERE is_default = true AND active = true
+			AND user_id IN (SELECT id FROM users WHERE is_admin = true)
+	`)
+}
 
-	return notifications, nil
+// getReceiveAllNotifications gets the active notifications of the monitor's
+// owner that receive every event
+func (d *Dispatcher) getReceiveAllNotifications(monitorID int) ([]*Notification, error) {
+	return d.queryNotifications(`
+		SELECT id, user_id, name, type, config, is_default, receive_all_events, active, created_at, updated_at
+		FROM notifications
+		WHERE receive_all_events = true AND active = true
+			AND user_id = (SELECT user_id FROM monitors WHERE id = ?)
+	`, monitorID)
 }
 
-// getDefaultNotifications gets all default notifications for a user
-func (d *Dispatcher) getDefaultNotifications() ([]*Notification, error) {
-	query := `
-		SELECT id, user_id, name, type, config, is_default, active, created_at, updated_at
+// getNotificationsByID gets the active notifications among ids
+func (d *Dispatcher) getNotificationsByID(ids []int) ([]*Notification, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return d.queryNotifications(`
+		SELECT id, user_id, name, type, config, is_default, receive_all_events, active, created_at, updated_at
 		FROM notifications
-		WHERE is_default = true AND active = true
-	`
+		WHERE id IN ? AND active = true
+	`, ids)
+}
 
+// queryNotifications runs a query selecting notification rows and parses
+// their config, skipping rows with invalid config
+func (d *Dispatcher) queryNotifications(query string, args ...interface{}) ([]*Notification, error) {
 	// Use a temporary struct to avoid GORM's issues with map fields
 	type NotificationRow struct {
-		ID        int    `gorm:"column:id"`
-		UserID    int    `gorm:"column:user_id"`
-		Name      string `gorm:"column:name"`
-		Type      string `gorm:"column:type"`
-		ConfigRaw string `gorm:"column:config"`
-		IsDefault bool   `gorm:"column:is_default"`
-		Active    bool   `gorm:"column:active"`
-		CreatedAt string `gorm:"column:created_at"`
-		UpdatedAt string `gorm:"column:updated_at"`
+		ID         int    `gorm:"column:id"`
+		UserID     int    `gorm:"column:user_id"`
+		Name       string `gorm:"column:name"`
+		Type       string `gorm:"column:type"`
+		ConfigRaw  string `gorm:"column:config"`
+		IsDefault  bool   `gorm:"column:is_default"`
+		ReceiveAll bool   `gorm:"column:receive_all_events"`
+		Active     bool   `gorm:"column:active"`
+		CreatedAt  string `gorm:"column:created_at"`
+		UpdatedAt  string `gorm:"column:updated_at"`
 	}
 
 	var rows []NotificationRow
-	err := d.db.Raw(query).Scan(&rows).Error
+	err := d.db.Raw(query, args...).Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
@@ -219,15 +622,16 @@ func (d *Dispatcher) getDefaultNotifications() ([]*Notification, error) {
 	notifications := make([]*Notification, 0, len(rows))
 	for _, row := range rows {
 		notif := &Notification{
-			ID:        row.ID,
-			UserID:    row.UserID,
-			Name:      row.Name,
-			Type:      row.Type,
-			ConfigRaw: row.ConfigRaw,
-			IsDefault: row.IsDefault,
-			Active:    row.Active,
-			CreatedAt: row.CreatedAt,
-			UpdatedAt: row.UpdatedAt,
+			ID:               row.ID,
+			UserID:           row.UserID,
+			Name:             row.Name,
+			Type:             row.Type,
+			ConfigRaw:        row.ConfigRaw,
+			IsDefault:        row.IsDefault,
+			ReceiveAllEvents: row.ReceiveAll,
+			Active:           row.Active,
+			CreatedAt:        row.CreatedAt,
+			UpdatedAt:        row.UpdatedAt,
 		}
 
 		// Parse config JSON
@@ -251,6 +655,8 @@ func (d *Dispatcher) TestNotification(ctx context.Context, notif *Notification)
 	msg := &Message{
 		Title:       "Test Notification",
 		Body:        "This is a test notification from Uptime Kabomba.",
+		titleKey:    msgTestTitle,
+		bodyKey:     msgTestBody,
 		MonitorName: "Test Monitor",
 		Status:      "up",
 		Time:        time.Now().Format(time.RFC3339),
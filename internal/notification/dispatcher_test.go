@@ -0,0 +1,176 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// sendGauge tracks how many sends run at once across providers
+type sendGauge struct {
+	running, peak atomic.Int32
+}
+
+// fanoutProvider counts its sends and fails when asked to
+type fanoutProvider struct {
+	name  string
+	fail  bool
+	gauge *sendGauge
+	sent  atomic.Int32
+}
+
+func (p *fanoutProvider) Name() string { return p.name }
+
+func (p *fanoutProvider) Send(ctx context.Context, notification *Notification, message *Message) error {
+	n := p.gauge.running.Add(1)
+	defer p.gauge.running.Add(-1)
+	for {
+		peak := p.gauge.peak.Load()
+		if n <= peak || p.gauge.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	p.sent.Add(1)
+	if p.fail {
+		return errors.New("unreachable")
+	}
+	return nil
+}
+
+func (p *fanoutProvider) Validate(config map[string]interface{}) error { return nil }
+
+func TestSendMonitorNotificationsBoundsFanout(t *testing.T) {
+	gauge := &sendGauge{}
+	ok := &fanoutProvider{name: "fanout-ok", gauge: gauge}
+	slack := &fanoutProvider{name: "fanout-slack", fail: true, gauge: gauge}
+	webhook := &fanoutProvider{name: "fanout-webhook", fail: true, gauge: gauge}
+	for _, p := range []*fanoutProvider{ok, slack, webhook} {
+		RegisterProvider(p)
+	}
+
+	var channels []*Notification
+	for i := 0; i < 300; i++ {
+		channel := &Notification{ID: i + 1, Type: "fanout-ok", Active: true}
+		switch {
+		case i%50 == 0:
+			channel.Type = "fanout-webhook"
+		case i%30 == 0:
+			channel.Type = "fanout-slack"
+		}
+		channels = append(channels, channel)
+	}
+
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) { return channels, nil },
+	}
+	d.SetSendConcurrency(5)
+
+	err := d.sendMonitorNotifications(context.Background(), 1, &Message{Title: "test", Status: "down"})
+	want := "failed to send 14/300 notifications: fanout-slack, fanout-webhook failed"
+	if err == nil || err.Error() != want {
+		t.Errorf("err = %v, want %q", err, want)
+	}
+
+	if total := ok.sent.Load() + slack.sent.Load() + webhook.sent.Load(); total != 300 {
+		t.Errorf("sent %d notifications, want 300", total)
+	}
+	if peak := gauge.peak.Load(); peak > 5 {
+		t.Errorf("%d notifications were sent at once, want at most 5", peak)
+	}
+}
+
+func TestSendErrorNilWhenAllSucceed(t *testing.T) {
+	if err := sendError([]*Notification{{Type: "slack"}}, []error{nil}); err != nil {
+		t.Errorf("sendError = %v, want nil", err)
+	}
+}
+
+func TestReceiveAllNotificationGetsEveryMonitorsEvents(t *testing.T) {
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	ops := &Notification{ID: 1, Name: "ops", Type: provider.Name(), Active: true}
+	firehose := &Notification{ID: 2, Name: "firehose", Type: provider.Name(), Active: true, ReceiveAllEvents: true}
+	d := &Dispatcher{
+		// Monitor 1 is linked to ops and the firehose, monitor 2 to nothing
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			if monitorID == 1 {
+				return []*Notification{ops, firehose}, nil
+			}
+			return nil, nil
+		},
+		receiveAllFor: func(monitorID int) ([]*Notification, error) {
+			return []*Notification{firehose}, nil
+		},
+	}
+
+	if err := d.NotifyMonitorDown(context.Background(), 1, "api", "", 0, "timeout"); err != nil {
+		t.Fatalf("NotifyMonitorDown: %v", err)
+	}
+	if err := d.NotifyMonitorDown(context.Background(), 2, "db", "", 0, "connection refused"); err != nil {
+		t.Fatalf("NotifyMonitorDown: %v", err)
+	}
+
+	if got := provider.messages(1); len(got) != 1 || got[0].MonitorName != "api" {
+		t.Errorf("ops got %+v, want only the api event", got)
+	}
+	got := provider.messages(2)
+	if len(got) != 2 {
+		t.Fatalf("firehose got %d messages, want one per monitor", len(got))
+	}
+	names := map[string]bool{got[0].MonitorName: true, got[1].MonitorName: true}
+	if !names["api"] || !names["db"] {
+		t.Errorf("firehose got events for %v, want api and db", names)
+	}
+}
+
+func TestNotificationTimeInOwnerTimezone(t *testing.T) {
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	channels := []*Notification{
+		{ID: 1, UserID: 10, Name: "tokyo", Type: provider.Name(), Active: true},
+		{ID: 2, UserID: 20, Name: "no settings", Type: provider.Name(), Active: true},
+	}
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return channels, nil
+		},
+		timezoneFor: func(userID int) *time.Location {
+			if userID == 10 {
+				return tokyo
+			}
+			return nil
+		},
+	}
+
+	if err := d.NotifyMonitorDown(context.Background(), 1, "api", "", 0, "timeout"); err != nil {
+		t.Fatalf("NotifyMonitorDown: %v", err)
+	}
+
+	local, utc := provider.messages(1), provider.messages(2)
+	if len(local) != 1 || len(utc) != 1 {
+		t.Fatalf("got %d and %d messages, want one each", len(local), len(utc))
+	}
+	if !strings.HasSuffix(local[0].Time, "+09:00") {
+		t.Errorf("Tokyo owner's time = %q, want it in +09:00", local[0].Time)
+	}
+	if !strings.HasSuffix(utc[0].Time, "Z") {
+		t.Errorf("default time = %q, want it in UTC", utc[0].Time)
+	}
+
+	localAt, _ := time.Parse(time.RFC3339, local[0].Time)
+	utcAt, _ := time.Parse(time.RFC3339, utc[0].Time)
+	if !localAt.Equal(utcAt) {
+		t.Errorf("times %q and %q are different instants", local[0].Time, utc[0].Time)
+	}
+}
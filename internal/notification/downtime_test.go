@@ -0,0 +1,94 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNotifyMonitorUpIncludesDowntime(t *testing.T) {
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	channels := []*Notification{
+		{ID: 1, Name: "ops", Type: provider.Name(), Active: true},
+		{ID: 2, Name: "oncall-de", Type: provider.Name(), Active: true, Config: map[string]interface{}{"locale": "de"}},
+	}
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return channels, nil
+		},
+	}
+
+	if err := d.NotifyMonitorUp(context.Background(), 1, "api", "", 40, "HTTP 200", 12*time.Minute+10*time.Second); err != nil {
+		t.Fatalf("NotifyMonitorUp: %v", err)
+	}
+
+	sent := provider.messages(1)
+	if len(sent) != 1 || sent[0].DowntimeDuration != 12*time.Minute+10*time.Second {
+		t.Fatalf("sent %+v, want the recovery with its downtime", sent)
+	}
+	if text := FormatMessage(sent[0]); !strings.Contains(text, "Downtime: 12m 10s") {
+		t.Errorf("formatted message has no downtime:\n%s", text)
+	}
+	if text := FormatMessage(provider.messages(2)[0]); !strings.Contains(text, "Ausfallzeit: 12m 10s") {
+		t.Errorf("downtime label is not translated:\n%s", text)
+	}
+}
+
+func TestDowntimeInProviderPayloads(t *testing.T) {
+	msg := &Message{Title: "Monitor is UP", MonitorName: "api", Status: "up", Ping: 40, DowntimeDuration: 90 * time.Minute}
+
+	webhook, err := webhookPayload(&Notification{}, msg)
+	if err != nil {
+		t.Fatalf("webhookPayload: %v", err)
+	}
+	payloads := map[string]interface{}{
+		"slack":   slackPayload(&Notification{}, msg),
+		"blocks":  slackBlocks(msg, ""),
+		"webhook": webhook,
+	}
+
+	for name, payload := range payloads {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			t.Fatalf("%s: marshal: %v", name, err)
+		}
+		want := "1h 30m"
+		if name == "webhook" {
+			want = `"downtime":5400`
+		}
+		if !strings.Contains(string(data), want) {
+			t.Errorf("%s payload has no downtime %s: %s", name, want, data)
+		}
+	}
+
+	// Other messages don't show a downtime
+	msg.DowntimeDuration = 0
+	if text := FormatMessage(msg); strings.Contains(text, "Downtime") {
+		t.Errorf("message without downtime shows one:\n%s", text)
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{400 * time.Millisecond, "0s"},
+		{45 * time.Second, "45s"},
+		{12 * time.Minute, "12m"},
+		{12*time.Minute + 30*time.Second, "12m 30s"},
+		{time.Hour + 5*time.Minute + 59*time.Second, "1h 5m"},
+		{time.Hour + 20*time.Second, "1h"},
+		{51 * time.Hour, "2d 3h"},
+	}
+	for _, tt := range tests {
+		if got := FormatDuration(tt.d); got != tt.want {
+			t.Errorf("FormatDuration(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
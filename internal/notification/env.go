@@ -0,0 +1,102 @@
+package notification
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// EnvPrefix is the prefix of the environment variables a notification config
+// may reference. Other variables stay out of reach, so a user can't send
+// server secrets such as JWT_SECRET to an endpoint they control.
+const EnvPrefix = "NOTIFY_"
+
+// envReference matches a ${NAME} reference in a config string
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// ExpandEnv returns config with the ${NOTIFY_...} references in its string
+// values, nested ones included, replaced from the process environment.
+// References to unset variables are left as written. config itself is not
+// modified, so the references, not the secrets, are what gets stored.
+func ExpandEnv(config map[string]interface{}) map[string]interface{} {
+	expanded, _ := expandEnvValue(config, os.LookupEnv).(map[string]interface{})
+	return expanded
+}
+
+// CheckEnvReferences validates the environment variable references of a
+// config before it is saved. It fails on references to variables without
+// EnvPrefix and returns the referenced variables that are currently unset.
+func CheckEnvReferences(config map[string]interface{}) (unset []string, err error) {
+	seen := make(map[string]bool)
+	var check func(v interface{}) error
+	check = func(v interface{}) error {
+		switch v := v.(type) {
+		case string:
+			for _, match := range envReference.FindAllStringSubmatch(v, -1) {
+				name := match[1]
+				if !strings.HasPrefix(name, EnvPrefix) {
+					return fmt.Errorf("config references ${%s}: only environment variables starting with %s can be used", name, EnvPrefix)
+				}
+				if _, ok := os.LookupEnv(name); !ok && !seen[name] {
+					seen[name] = true
+					unset = append(unset, name)
+				}
+			}
+		case map[string]interface{}:
+			for _, item := range v {
+				if err := check(item); err != nil {
+					return err
+				}
+			}
+		case []interface{}:
+			for _, item := range v {
+				if err := check(item); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := check(config); err != nil {
+		return nil, err
+	}
+	return unset, nil
+}
+
+// expandEnvValue expands the references in v, copying maps and slices
+// instead of modifying them
+func expandEnvValue(v interface{}, lookup func(string) (string, bool)) interface{} {
+	switch v := v.(type) {
+	case string:
+		return envReference.ReplaceAllStringFunc(v, func(ref string) string {
+			name := ref[2 : len(ref)-1]
+			if !strings.HasPrefix(name, EnvPrefix) {
+				return ref
+			}
+			value, ok := lookup(name)
+			if !ok {
+				log.Printf("Notification config references unset environment variable %s", name)
+				return ref
+			}
+			return value
+		})
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		expanded := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			expanded[key] = expandEnvValue(item, lookup)
+		}
+		return expanded
+	case []interface{}:
+		expanded := make([]interface{}, len(v))
+		for i, item := range v {
+			expanded[i] = expandEnvValue(item, lookup)
+		}
+		return expanded
+	}
+	return v
+}
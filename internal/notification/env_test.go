@@ -0,0 +1,75 @@
+package notification
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// configProvider records the config of the last notification it sent
+type configProvider struct {
+	config map[string]interface{}
+}
+
+func (p *configProvider) Name() string { return "env-test" }
+
+func (p *configProvider) Send(ctx context.Context, notification *Notification, message *Message) error {
+	p.config = notification.Config
+	return nil
+}
+
+func (p *configProvider) Validate(config map[string]interface{}) error { return nil }
+
+func TestSendResolvesEnvReferences(t *testing.T) {
+	t.Setenv("NOTIFY_TEST_BOT_TOKEN", "123456:secret")
+	t.Setenv("JWT_SECRET", "server-only")
+	provider := &configProvider{}
+	RegisterProvider(provider)
+
+	notif := &Notification{ID: 1, Name: "telegram", Type: provider.Name(), Active: true, Config: map[string]interface{}{
+		"bot_token": "${NOTIFY_TEST_BOT_TOKEN}",
+		"chat_id":   "42",
+		"headers":   map[string]interface{}{"Authorization": "Bearer ${NOTIFY_TEST_BOT_TOKEN}"},
+		"tags":      []interface{}{"${NOTIFY_TEST_BOT_TOKEN}", 7.0},
+		"leak":      "${JWT_SECRET}",
+		"missing":   "${NOTIFY_TEST_UNSET}",
+	}}
+	if err := (&Dispatcher{}).sendNotification(context.Background(), notif, &Message{Title: "test"}); err != nil {
+		t.Fatalf("sendNotification: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"bot_token": "123456:secret",
+		"chat_id":   "42",
+		"headers":   map[string]interface{}{"Authorization": "Bearer 123456:secret"},
+		"tags":      []interface{}{"123456:secret", 7.0},
+		"leak":      "${JWT_SECRET}",
+		"missing":   "${NOTIFY_TEST_UNSET}",
+	}
+	if !reflect.DeepEqual(provider.config, want) {
+		t.Errorf("sent config %v, want %v", provider.config, want)
+	}
+	if notif.Config["bot_token"] != "${NOTIFY_TEST_BOT_TOKEN}" {
+		t.Errorf("stored config was modified: %v", notif.Config)
+	}
+}
+
+func TestCheckEnvReferences(t *testing.T) {
+	t.Setenv("NOTIFY_TEST_WEBHOOK", "https://hooks.example.com/x")
+
+	unset, err := CheckEnvReferences(map[string]interface{}{
+		"url":     "${NOTIFY_TEST_WEBHOOK}",
+		"headers": map[string]interface{}{"X-Token": "${NOTIFY_TEST_TOKEN}", "X-Other": "${NOTIFY_TEST_TOKEN}"},
+		"literal": "costs $5 {not a reference}",
+	})
+	if err != nil {
+		t.Fatalf("CheckEnvReferences: %v", err)
+	}
+	if !reflect.DeepEqual(unset, []string{"NOTIFY_TEST_TOKEN"}) {
+		t.Errorf("unset = %v, want [NOTIFY_TEST_TOKEN]", unset)
+	}
+
+	if _, err := CheckEnvReferences(map[string]interface{}{"url": "https://x/${DATABASE_DSN}"}); err == nil {
+		t.Error("reference without the NOTIFY_ prefix was accepted")
+	}
+}
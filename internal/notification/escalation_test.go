@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newEscalationTestDispatcher() (*Dispatcher, *recordingProvider) {
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	channels := map[int]*Notification{
+		1: {ID: 1, Name: "slack", Type: provider.Name(), Active: true},
+		2: {ID: 2, Name: "oncall", Type: provider.Name(), Active: true, Config: map[string]interface{}{"locale": "de"}},
+		3: {ID: 3, Name: "manager", Type: provider.Name(), Active: true},
+	}
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return []*Notification{channels[1]}, nil
+		},
+		notificationsByID: func(ids []int) ([]*Notification, error) {
+			var notifications []*Notification
+			for _, id := range ids {
+				notifications = append(notifications, channels[id])
+			}
+			return notifications, nil
+		},
+	}
+	return d, provider
+}
+
+func TestNotifyMonitorEscalated(t *testing.T) {
+	d, provider := newEscalationTestDispatcher()
+
+	if err := d.NotifyMonitorEscalated(context.Background(), []int{2, 3}, 2, 9, "api", "", 0, "timeout", 10*time.Minute); err != nil {
+		t.Fatalf("NotifyMonitorEscalated: %v", err)
+	}
+
+	if got := provider.messages(1); len(got) != 0 {
+		t.Errorf("channel outside the step got %d messages", len(got))
+	}
+	manager := provider.messages(3)
+	if len(manager) != 1 || manager[0].Title != "Monitor is still DOWN (escalation step 2)" || manager[0].Status != "down" {
+		t.Fatalf("manager got %+v, want the step 2 escalation", manager)
+	}
+	if manager[0].DowntimeDuration != 10*time.Minute {
+		t.Errorf("downtime = %s, want 10m", manager[0].DowntimeDuration)
+	}
+	if oncall := provider.messages(2); len(oncall) != 1 || oncall[0].Title != "Monitor ist weiterhin DOWN (Eskalationsstufe 2)" {
+		t.Errorf("german channel got %+v, want the translated title", oncall)
+	}
+}
+
+func TestNotifyEscalationRecoveredSkipsMonitorChannels(t *testing.T) {
+	d, provider := newEscalationTestDispatcher()
+
+	if err := d.NotifyEscalationRecovered(context.Background(), []int{1, 2, 3}, 9, "api", "", 40, "OK", time.Hour); err != nil {
+		t.Fatalf("NotifyEscalationRecovered: %v", err)
+	}
+
+	// Channel 1 is linked to the monitor, so NotifyMonitorUp covers it
+	if got := provider.messages(1); len(got) != 0 {
+		t.Errorf("monitor channel got %d recoveries, want none", len(got))
+	}
+	for _, id := range []int{2, 3} {
+		if got := provider.messages(id); len(got) != 1 || got[0].Status != "up" {
+			t.Errorf("channel %d got %+v, want one recovery", id, got)
+		}
+	}
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 )
 
 // GotifyProvider sends Gotify notifications (self-hosted)
@@ -74,7 +73,7 @@ func (g *GotifyProvider) Send(ctx context.Context, notification *Notification, m
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send Gotify notification: %w", err)
@@ -101,3 +100,8 @@ func (g *GotifyProvider) Validate(config map[string]interface{}) error {
 
 	return nil
 }
+
+func (g *GotifyProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	serverURL, _ := config["server_url"].(string)
+	return probeHTTP(ctx, serverURL)
+}
@@ -0,0 +1,60 @@
+package notification
+
+import (
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultSendTimeout bounds each provider request unless SetSendTimeout
+// configures another budget
+const DefaultSendTimeout = 10 * time.Second
+
+// maxResponseSize caps how much of a provider's response is read
+const maxResponseSize = 1 << 20
+
+// sharedClient is the HTTP client every provider sends with
+var sharedClient atomic.Pointer[http.Client]
+
+func init() {
+	SetSendTimeout(DefaultSendTimeout)
+}
+
+// SetSendTimeout sets how long a provider request may take, including reading
+// the response. Values of zero or less restore DefaultSendTimeout.
+func SetSendTimeout(timeout time.Duration) {
+	if timeout <= 0 {
+		timeout = DefaultSendTimeout
+	}
+	sharedClient.Store(&http.Client{
+		Timeout:   timeout,
+		Transport: limitedTransport{base: http.DefaultTransport},
+	})
+}
+
+// httpClient returns the shared client providers send requests with
+func httpClient() *http.Client {
+	return sharedClient.Load()
+}
+
+// limitedTransport caps response bodies at maxResponseSize, so a misbehaving
+// endpoint can't make a provider read without end
+type limitedTransport struct {
+	base http.RoundTripper
+}
+
+func (t limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = limitedBody{Reader: io.LimitReader(resp.Body, maxResponseSize), Closer: resp.Body}
+	return resp, nil
+}
+
+// limitedBody reads through a limit and closes the original body
+type limitedBody struct {
+	io.Reader
+	io.Closer
+}
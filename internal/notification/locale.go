@@ -0,0 +1,237 @@
+package notification
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// DefaultLocale is used for notifications without a locale, and for strings
+// a locale does not translate
+const DefaultLocale = "en"
+
+// Message catalog keys. Titles may contain fmt verbs filled from the
+// message's title arguments.
+const (
+	msgMonitorDown       = "monitor_down"
+	msgMonitorUp         = "monitor_up"
+	msgMonitorStalled    = "monitor_stalled"
+	msgMonitorAutoPaused = "monitor_auto_paused"
+	msgMonitorFlapping   = "monitor_flapping"
+	msgMonitorWarning    = "monitor_warning"
+	msgWarningCleared    = "warning_cleared"
+	msgMonitorEscalated  = "monitor_escalated" // %d: escalation step
+	msgMassOutage        = "mass_outage"       // %d: number of monitors down
+	msgAPIKeyExpiring    = "api_key_expiring"
+	msgDatabaseSize      = "database_size"
+	msgTestTitle         = "test_title"
+	msgTestBody          = "test_body"
+	labelMonitor         = "label_monitor"
+	labelStatus          = "label_status"
+	labelURL             = "label_url"
+	labelResponseTime    = "label_response_time"
+	labelTime            = "label_time"
+	labelDowntime        = "label_downtime"
+)
+
+var (
+	catalogMu sync.RWMutex
+	catalogs  = map[string]map[string]string{
+		"en": {
+			msgMonitorDown:       "Monitor is DOWN",
+			msgMonitorUp:         "Monitor is UP",
+			msgMonitorStalled:    "Monitor has STALLED",
+			msgMonitorAutoPaused: "Monitor was AUTO-PAUSED",
+			msgMonitorFlapping:   "Monitor is FLAPPING",
+			msgMonitorWarning:    "Monitor needs ATTENTION",
+			msgWarningCleared:    "Monitor warning CLEARED",
+			msgMonitorEscalated:  "Monitor is still DOWN (escalation step %d)",
+			msgMassOutage:        "Mass outage: %d monitors are DOWN",
+			msgAPIKeyExpiring:    "API key is about to EXPIRE",
+			msgDatabaseSize:      "Database size needs ATTENTION",
+			msgTestTitle:         "Test Notification",
+			msgTestBody:          "This is a test notification from Uptime Kabomba.",
+			labelMonitor:         "Monitor",
+			labelStatus:          "Status",
+			labelURL:             "URL",
+			labelResponseTime:    "Response Time",
+			labelTime:            "Time",
+			labelDowntime:        "Downtime",
+		},
+		"it": {
+			msgMonitorDown:       "Il monitor è DOWN",
+			msgMonitorUp:         "Il monitor è UP",
+			msgMonitorStalled:    "Il monitor è BLOCCATO",
+			msgMonitorAutoPaused: "Il monitor è stato messo in PAUSA automaticamente",
+			msgMonitorFlapping:   "Il monitor è INSTABILE",
+			msgMonitorWarning:    "Il monitor richiede ATTENZIONE",
+			msgWarningCleared:    "Avviso del monitor RISOLTO",
+			msgMonitorEscalated:  "Il monitor è ancora DOWN (escalation livello %d)",
+			msgMassOutage:        "Disservizio esteso: %d monitor sono DOWN",
+			msgAPIKeyExpiring:    "La chiave API sta per SCADERE",
+			msgDatabaseSize:      "La dimensione del database richiede ATTENZIONE",
+			msgTestTitle:         "Notifica di prova",
+			msgTestBody:          "Questa è una notifica di prova da Uptime Kabomba.",
+			labelMonitor:         "Monitor",
+			labelStatus:          "Stato",
+			labelURL:             "URL",
+			labelResponseTime:    "Tempo di risposta",
+			labelTime:            "Ora",
+			labelDowntime:        "Durata del disservizio",
+		},
+		"de": {
+			msgMonitorDown:       "Monitor ist DOWN",
+			msgMonitorUp:         "Monitor ist UP",
+			msgMonitorStalled:    "Monitor ist BLOCKIERT",
+			msgMonitorAutoPaused: "Monitor wurde automatisch PAUSIERT",
+			msgMonitorFlapping:   "Monitor ist INSTABIL",
+			msgMonitorWarning:    "Monitor erfordert AUFMERKSAMKEIT",
+			msgWarningCleared:    "Warnung des Monitors BEHOBEN",
+			msgMonitorEscalated:  "Monitor ist weiterhin DOWN (Eskalationsstufe %d)",
+			msgMassOutage:        "Großstörung: %d Monitore sind DOWN",
+			msgAPIKeyExpiring:    "API-Schlüssel läuft bald AB",
+			msgDatabaseSize:      "Datenbankgröße erfordert AUFMERKSAMKEIT",
+			msgTestTitle:         "Testbenachrichtigung",
+			msgTestBody:          "Dies ist eine Testbenachrichtigung von Uptime Kabomba.",
+			labelMonitor:         "Monitor",
+			labelStatus:          "Status",
+			labelURL:             "URL",
+			labelResponseTime:    "Antwortzeit",
+			labelTime:            "Zeit",
+			labelDowntime:        "Ausfallzeit",
+		},
+		"fr": {
+			msgMonitorDown:       "Le moniteur est DOWN",
+			msgMonitorUp:         "Le moniteur est UP",
+			msgMonitorStalled:    "Le moniteur est BLOQUÉ",
+			msgMonitorAutoPaused: "Le moniteur a été mis en PAUSE automatiquement",
+			msgMonitorFlapping:   "Le moniteur est INSTABLE",
+			msgMonitorWarning:    "Le moniteur requiert votre ATTENTION",
+			msgWarningCleared:    "Avertissement du moniteur RÉSOLU",
+			msgMonitorEscalated:  "Le moniteur est toujours DOWN (escalade niveau %d)",
+			msgMassOutage:        "Panne majeure : %d moniteurs sont DOWN",
+			msgAPIKeyExpiring:    "La clé API va bientôt EXPIRER",
+			msgDatabaseSize:      "La taille de la base de données demande de l'ATTENTION",
+			msgTestTitle:         "Notification de test",
+			msgTestBody:          "Ceci est une notification de test d'Uptime Kabomba.",
+			labelMonitor:         "Moniteur",
+			labelStatus:          "Statut",
+			labelURL:             "URL",
+			labelResponseTime:    "Temps de réponse",
+			labelTime:            "Heure",
+			labelDowntime:        "Durée de l'interruption",
+		},
+		"es": {
+			msgMonitorDown:       "El monitor está DOWN",
+			msgMonitorUp:         "El monitor está UP",
+			msgMonitorStalled:    "El monitor está BLOQUEADO",
+			msgMonitorAutoPaused: "El monitor se ha PAUSADO automáticamente",
+			msgMonitorFlapping:   "El monitor está INESTABLE",
+			msgMonitorWarning:    "El monitor requiere ATENCIÓN",
+			msgWarningCleared:    "Advertencia del monitor RESUELTA",
+			msgMonitorEscalated:  "El monitor sigue DOWN (escalado nivel %d)",
+			msgMassOutage:        "Caída masiva: %d monitores están DOWN",
+			msgAPIKeyExpiring:    "La clave API está a punto de CADUCAR",
+			msgDatabaseSize:      "El tamaño de la base de datos requiere ATENCIÓN",
+			msgTestTitle:         "Notificación de prueba",
+			msgTestBody:          "Esta es una notificación de prueba de Uptime Kabomba.",
+			labelMonitor:         "Monitor",
+			labelStatus:          "Estado",
+			labelURL:             "URL",
+			labelResponseTime:    "Tiempo de respuesta",
+			labelTime:            "Hora",
+			labelDowntime:        "Tiempo de inactividad",
+		},
+	}
+)
+
+// RegisterLocale adds a locale to the message catalog, or overrides strings
+// of an existing one. Keys are the catalog keys used by the "en" locale;
+// keys a locale leaves out fall back to English.
+func RegisterLocale(locale string, messages map[string]string) {
+	locale = normalizeLocale(locale)
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+	catalog, ok := catalogs[locale]
+	if !ok {
+		catalog = make(map[string]string, len(messages))
+		catalogs[locale] = catalog
+	}
+	for key, text := range messages {
+		catalog[key] = text
+	}
+}
+
+// Locales returns the locales in the message catalog, sorted
+func Locales() []string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+	locales := make([]string, 0, len(catalogs))
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	sort.Strings(locales)
+	return locales
+}
+
+// normalizeLocale lowercases a locale tag and uses "-" as separator
+func normalizeLocale(locale string) string {
+	return strings.ReplaceAll(strings.ToLower(strings.TrimSpace(locale)), "_", "-")
+}
+
+// translate returns the catalog string for key. A regional locale such as
+// "it-CH" falls back to its language, and unknown locales to English.
+func translate(locale, key string) string {
+	catalogMu.RLock()
+	defer catalogMu.RUnlock()
+
+	locale = normalizeLocale(locale)
+	candidates := []string{locale}
+	if i := strings.Index(locale, "-"); i > 0 {
+		candidates = append(candidates, locale[:i])
+	}
+	candidates = append(candidates, DefaultLocale)
+
+	for _, candidate := range candidates {
+		if text, ok := catalogs[candidate][key]; ok {
+			return text
+		}
+	}
+	return key
+}
+
+// notificationLocale returns the optional "locale" config of a notification
+func notificationLocale(config map[string]interface{}) string {
+	if locale, ok := config["locale"].(string); ok && strings.TrimSpace(locale) != "" {
+		return locale
+	}
+	return DefaultLocale
+}
+
+// ValidateLocale checks the optional "locale" config of a notification. It
+// must be a string; an empty value means English.
+func ValidateLocale(config map[string]interface{}) error {
+	raw, ok := config["locale"]
+	if !ok || raw == nil {
+		return nil
+	}
+	if _, ok := raw.(string); !ok {
+		return fmt.Errorf("locale must be a string")
+	}
+	return nil
+}
+
+// localize returns a copy of msg with its standard texts in locale. The
+// original is shared by every channel of the alert, so it is not modified.
+func localize(msg *Message, locale string) *Message {
+	localized := *msg
+	localized.Locale = locale
+	if msg.titleKey != "" {
+		localized.Title = fmt.Sprintf(translate(locale, msg.titleKey), msg.titleArgs...)
+	}
+	if msg.bodyKey != "" {
+		localized.Body = translate(locale, msg.bodyKey)
+	}
+	return &localized
+}
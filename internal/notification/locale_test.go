@@ -0,0 +1,90 @@
+package notification
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNotifyMonitorDownLocalized(t *testing.T) {
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	channels := []*Notification{
+		{ID: 1, Name: "oncall-it", Type: provider.Name(), Active: true, Config: map[string]interface{}{"locale": "it"}},
+		{ID: 2, Name: "ops", Type: provider.Name(), Active: true},
+	}
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return channels, nil
+		},
+	}
+
+	if err := d.NotifyMonitorDown(context.Background(), 1, "api", "https://api.example.com", 150, "timeout"); err != nil {
+		t.Fatalf("NotifyMonitorDown: %v", err)
+	}
+
+	italian := provider.messages(1)
+	if len(italian) != 1 || italian[0].Title != "Il monitor è DOWN" {
+		t.Fatalf("italian channel got %+v, want the translated title", italian)
+	}
+	text := FormatMessage(italian[0])
+	if !strings.Contains(text, "Tempo di risposta: 150ms") || !strings.Contains(text, "Ora: ") {
+		t.Errorf("formatted message is not translated:\n%s", text)
+	}
+
+	english := provider.messages(2)
+	if len(english) != 1 || english[0].Title != "Monitor is DOWN" {
+		t.Fatalf("default channel got %+v, want the English title", english)
+	}
+	if text := FormatMessage(english[0]); !strings.Contains(text, "Response Time: 150ms") {
+		t.Errorf("default channel message is not English:\n%s", text)
+	}
+}
+
+func TestTranslateFallback(t *testing.T) {
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"de", "Monitor ist UP"},
+		{"it_CH", "Il monitor è UP"}, // regional tag falls back to its language
+		{"FR", "Le moniteur est UP"},
+		{"xx", "Monitor is UP"}, // unknown locale falls back to English
+		{"", "Monitor is UP"},
+	}
+	for _, tt := range tests {
+		if got := translate(tt.locale, msgMonitorUp); got != tt.want {
+			t.Errorf("translate(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+
+	summary := localize(buildOutageSummary([]*Message{{MonitorName: "a"}, {MonitorName: "b"}}), "es")
+	if summary.Title != "Caída masiva: 2 monitores están DOWN" {
+		t.Errorf("outage summary title = %q", summary.Title)
+	}
+}
+
+func TestRegisterLocale(t *testing.T) {
+	RegisterLocale("nl", map[string]string{msgMonitorDown: "Monitor is OFFLINE"})
+
+	msg := localize(&Message{Title: "Monitor is DOWN", titleKey: msgMonitorDown, Ping: 10}, "nl")
+	if msg.Title != "Monitor is OFFLINE" {
+		t.Errorf("title = %q, want the registered translation", msg.Title)
+	}
+	// Keys the locale leaves out fall back to English
+	if got := translate("nl", labelResponseTime); got != "Response Time" {
+		t.Errorf("untranslated label = %q, want English", got)
+	}
+}
+
+func TestValidateLocale(t *testing.T) {
+	for _, config := range []map[string]interface{}{{}, {"locale": "it"}, {"locale": "pt-BR"}} {
+		if err := ValidateLocale(config); err != nil {
+			t.Errorf("ValidateLocale(%v): %v", config, err)
+		}
+	}
+	if err := ValidateLocale(map[string]interface{}{"locale": 3}); err == nil {
+		t.Error("non-string locale accepted")
+	}
+}
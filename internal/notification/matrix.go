@@ -0,0 +1,168 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// matrixTxnCounter keeps transaction IDs unique within a second
+var matrixTxnCounter atomic.Uint64
+
+// MatrixProvider sends messages to a Matrix room
+type MatrixProvider struct{}
+
+func init() {
+	RegisterProvider(&MatrixProvider{})
+}
+
+func (m *MatrixProvider) Name() string {
+	return "matrix"
+}
+
+func (m *MatrixProvider) Send(ctx context.Context, notification *Notification, message *Message) error {
+	// Get Matrix configuration
+	homeserverURL, _ := notification.Config["homeserver_url"].(string)
+	accessToken, _ := notification.Config["access_token"].(string)
+	roomID, _ := notification.Config["room_id"].(string)
+
+	if homeserverURL == "" {
+		return fmt.Errorf("homeserver_url is required")
+	}
+
+	if accessToken == "" {
+		return fmt.Errorf("access_token is required")
+	}
+
+	if roomID == "" {
+		return fmt.Errorf("room_id is required")
+	}
+
+	// Clients without HTML support show the plain body
+	payload := map[string]interface{}{
+		"msgtype":        "m.text",
+		"body":           FormatMessage(message),
+		"format":         "org.matrix.custom.html",
+		"formatted_body": matrixHTML(message),
+	}
+
+	// Marshal payload
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	// Matrix deduplicates sends by transaction ID, so each message needs its own
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, matrixSendURL(homeserverURL, roomID, matrixTxnID()), bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+
+	client := httpClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Matrix message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		// Matrix errors carry an errcode and a readable error
+		var result struct {
+			ErrCode string `json:"errcode"`
+			Error   string `json:"error"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&result) == nil && result.ErrCode != "" {
+			return fmt.Errorf("Matrix API returned status %d: %s: %s", resp.StatusCode, result.ErrCode, result.Error)
+		}
+		return fmt.Errorf("Matrix API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// matrixSendURL returns the endpoint that sends a message event to a room
+func matrixSendURL(homeserverURL, roomID, txnID string) string {
+	return fmt.Sprintf("%s/_matrix/client/v3/rooms/%s/send/m.room.message/%s",
+		strings.TrimRight(homeserverURL, "/"), url.PathEscape(roomID), url.PathEscape(txnID))
+}
+
+// matrixTxnID returns a transaction ID unique to this process
+func matrixTxnID() string {
+	return fmt.Sprintf("kabomba-%d-%d", time.Now().UnixNano(), matrixTxnCounter.Add(1))
+}
+
+// matrixHTML formats a message as the HTML body of a Matrix event
+func matrixHTML(message *Message) string {
+	var statusEmoji, color string
+	switch message.Status {
+	case "up":
+		statusEmoji, color = "✅", "#2eb886"
+	case "down":
+		statusEmoji, color = "❌", "#e01e5a"
+	case "maintenance":
+		statusEmoji, color = "🔧", "#0000ff"
+	case "warning":
+		statusEmoji, color = "⚠️", "#ecb22e"
+	default:
+		statusEmoji, color = "ℹ️", "#808080"
+	}
+
+	text := fmt.Sprintf(`<h4>%s <font color="%s">%s</font></h4>`, statusEmoji, color, html.EscapeString(message.Title))
+	text += fmt.Sprintf("<p>%s</p>", strings.ReplaceAll(html.EscapeString(message.Body), "\n", "<br>"))
+	text += fmt.Sprintf("<b>%s:</b> %s<br>", translate(message.Locale, labelMonitor), html.EscapeString(message.MonitorName))
+
+	if message.MonitorURL != "" {
+		text += fmt.Sprintf("<b>%s:</b> %s<br>", translate(message.Locale, labelURL), html.EscapeString(message.MonitorURL))
+	}
+
+	if message.Ping > 0 {
+		text += fmt.Sprintf("<b>%s:</b> %dms<br>", translate(message.Locale, labelResponseTime), message.Ping)
+	}
+
+	if message.DowntimeDuration > 0 {
+		text += fmt.Sprintf("<b>%s:</b> %s<br>", translate(message.Locale, labelDowntime), FormatDuration(message.DowntimeDuration))
+	}
+
+	text += fmt.Sprintf("<b>%s:</b> %s", translate(message.Locale, labelTime), html.EscapeString(message.Time))
+	return text
+}
+
+func (m *MatrixProvider) Validate(config map[string]interface{}) error {
+	homeserverURL, ok := config["homeserver_url"].(string)
+	if !ok || homeserverURL == "" {
+		return fmt.Errorf("homeserver_url is required")
+	}
+	if !isHTTPURL(homeserverURL) {
+		return fmt.Errorf("homeserver_url must be an http or https URL")
+	}
+
+	accessToken, ok := config["access_token"].(string)
+	if !ok || accessToken == "" {
+		return fmt.Errorf("access_token is required")
+	}
+
+	roomID, ok := config["room_id"].(string)
+	if !ok || roomID == "" {
+		return fmt.Errorf("room_id is required")
+	}
+	if !strings.HasPrefix(roomID, "!") || !strings.Contains(roomID, ":") {
+		return fmt.Errorf("room_id must be a room ID like !abc123:example.org, not an alias")
+	}
+
+	return nil
+}
+
+func (m *MatrixProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	homeserverURL, _ := config["homeserver_url"].(string)
+	return probeHTTP(ctx, homeserverURL)
+}
@@ -0,0 +1,107 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMatrixSend(t *testing.T) {
+	var gotMethod, gotPath, gotAuth string
+	var payload map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod, gotPath, gotAuth = r.Method, r.URL.EscapedPath(), r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&payload)
+		w.Write([]byte(`{"event_id": "$abc"}`))
+	}))
+	defer server.Close()
+
+	notification := &Notification{Config: map[string]interface{}{
+		"homeserver_url": server.URL + "/",
+		"access_token":   "syt_secret",
+		"room_id":        "!ops:example.org",
+	}}
+	msg := &Message{
+		Title:       "API is DOWN",
+		Body:        "HTTP 500 <Internal Server Error>",
+		MonitorName: "API",
+		Status:      "down",
+		Ping:        120,
+		Time:        "2026-01-01 10:00:00",
+	}
+	if err := (&MatrixProvider{}).Send(context.Background(), notification, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if gotMethod != http.MethodPut {
+		t.Errorf("method = %s, want PUT", gotMethod)
+	}
+	if !strings.HasPrefix(gotPath, "/_matrix/client/v3/rooms/%21ops:example.org/send/m.room.message/") {
+		t.Errorf("path = %s", gotPath)
+	}
+	if gotAuth != "Bearer syt_secret" {
+		t.Errorf("Authorization = %q", gotAuth)
+	}
+	if payload["msgtype"] != "m.text" || payload["format"] != "org.matrix.custom.html" {
+		t.Errorf("payload = %v", payload)
+	}
+	if !strings.Contains(payload["body"], "API is DOWN") {
+		t.Errorf("body = %q", payload["body"])
+	}
+	html := payload["formatted_body"]
+	if !strings.Contains(html, "❌") || !strings.Contains(html, "&lt;Internal Server Error&gt;") || !strings.Contains(html, "<b>Response Time:</b> 120ms") {
+		t.Errorf("formatted_body = %q", html)
+	}
+}
+
+func TestMatrixSendReportsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"errcode": "M_FORBIDDEN", "error": "User not in room"}`))
+	}))
+	defer server.Close()
+
+	err := (&MatrixProvider{}).Send(context.Background(), &Notification{Config: map[string]interface{}{
+		"homeserver_url": server.URL,
+		"access_token":   "syt_secret",
+		"room_id":        "!ops:example.org",
+	}}, &Message{Title: "API is UP", Status: "up"})
+	if err == nil || !strings.Contains(err.Error(), "M_FORBIDDEN: User not in room") {
+		t.Errorf("Send error = %v, want the Matrix error", err)
+	}
+}
+
+func TestMatrixValidate(t *testing.T) {
+	valid := map[string]interface{}{
+		"homeserver_url": "https://matrix.example.org",
+		"access_token":   "syt_secret",
+		"room_id":        "!ops:example.org",
+	}
+	if err := (&MatrixProvider{}).Validate(valid); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+
+	tests := []struct {
+		key, value, wantErr string
+	}{
+		{"homeserver_url", "", "homeserver_url is required"},
+		{"homeserver_url", "matrix.example.org", "homeserver_url must be an http or https URL"},
+		{"access_token", "", "access_token is required"},
+		{"room_id", "", "room_id is required"},
+		{"room_id", "#ops:example.org", "room_id must be a room ID"},
+	}
+	for _, tt := range tests {
+		config := make(map[string]interface{})
+		for k, v := range valid {
+			config[k] = v
+		}
+		config[tt.key] = tt.value
+		err := (&MatrixProvider{}).Validate(config)
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s=%q: error = %v, want %q", tt.key, tt.value, err, tt.wantErr)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package notification
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// SendDurationBuckets are the upper bounds, in seconds, of the send duration
+// histogram
+var SendDurationBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// ProviderSendMetrics are the send statistics of one provider type since
+// startup
+type ProviderSendMetrics struct {
+	Provider string
+	Buckets  []uint64 // cumulative counts for SendDurationBuckets
+	Count    uint64
+	Sum      float64 // seconds
+	Failures uint64
+}
+
+// sendMetrics records every send by provider type
+var sendMetrics = struct {
+	sync.Mutex
+	byProvider map[string]*ProviderSendMetrics
+}{byProvider: make(map[string]*ProviderSendMetrics)}
+
+// recordSend adds a send that took d and failed unless err is nil
+func recordSend(provider string, d time.Duration, err error) {
+	sendMetrics.Lock()
+	defer sendMetrics.Unlock()
+
+	m, ok := sendMetrics.byProvider[provider]
+	if !ok {
+		m = &ProviderSendMetrics{Provider: provider, Buckets: make([]uint64, len(SendDurationBuckets))}
+		sendMetrics.byProvider[provider] = m
+	}
+
+	seconds := d.Seconds()
+	for i, bound := range SendDurationBuckets {
+		if seconds <= bound {
+			m.Buckets[i]++
+		}
+	}
+	m.Count++
+	m.Sum += seconds
+	if err != nil {
+		m.Failures++
+	}
+}
+
+// SendMetrics returns a copy of the send statistics of every provider type
+// that has sent, ordered by provider
+func SendMetrics() []ProviderSendMetrics {
+	sendMetrics.Lock()
+	defer sendMetrics.Unlock()
+
+	metrics := make([]ProviderSendMetrics, 0, len(sendMetrics.byProvider))
+	for _, m := range sendMetrics.byProvider {
+		c := *m
+		c.Buckets = append([]uint64(nil), m.Buckets...)
+		metrics = append(metrics, c)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Provider < metrics[j].Provider
+	})
+	return metrics
+}
@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// providerMetrics returns the send metrics of provider, or zero values
+func providerMetrics(provider string) ProviderSendMetrics {
+	for _, m := range SendMetrics() {
+		if m.Provider == provider {
+			return m
+		}
+	}
+	return ProviderSendMetrics{Provider: provider, Buckets: make([]uint64, len(SendDurationBuckets))}
+}
+
+func TestSendMetricsIncrementOnSend(t *testing.T) {
+	ok := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer ok.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return []*Notification{
+				{ID: 1, Name: "ok", Type: "webhook", Active: true, Config: map[string]interface{}{"webhook_url": ok.URL}},
+				{ID: 2, Name: "flaky", Type: "webhook", Active: true, Config: map[string]interface{}{"webhook_url": failing.URL}},
+			}, nil
+		},
+	}
+
+	before := providerMetrics("webhook")
+	if err := d.NotifyMonitorDown(context.Background(), 1, "api", "", 0, "timeout"); err == nil {
+		t.Fatal("NotifyMonitorDown succeeded, want the failing channel reported")
+	}
+	after := providerMetrics("webhook")
+
+	if got := after.Count - before.Count; got != 2 {
+		t.Errorf("send count grew by %d, want 2", got)
+	}
+	if got := after.Failures - before.Failures; got != 1 {
+		t.Errorf("failures grew by %d, want 1", got)
+	}
+	if after.Sum <= before.Sum {
+		t.Errorf("duration sum didn't grow: %v -> %v", before.Sum, after.Sum)
+	}
+	last := len(SendDurationBuckets) - 1
+	if got := after.Buckets[last] - before.Buckets[last]; got != 2 {
+		t.Errorf("largest bucket grew by %d, want 2", got)
+	}
+}
+
+func TestRecordSendBuckets(t *testing.T) {
+	recordSend("bucket-test", 300*time.Millisecond, nil)
+	recordSend("bucket-test", 45*time.Second, context.DeadlineExceeded)
+
+	m := providerMetrics("bucket-test")
+	want := []uint64{0, 0, 1, 1, 1, 1, 1, 1} // 0.3s falls in le=0.5 and up; 45s in none
+	for i, bound := range SendDurationBuckets {
+		if m.Buckets[i] != want[i] {
+			t.Errorf("bucket le=%v = %d, want %d", bound, m.Buckets[i], want[i])
+		}
+	}
+	if m.Count != 2 || m.Failures != 1 || math.Abs(m.Sum-45.3) > 1e-9 {
+		t.Errorf("metrics = %+v, want 2 sends, 1 failure and 45.3s", m)
+	}
+}
+
+func TestSendTimeoutIsConfigurable(t *testing.T) {
+	defer SetSendTimeout(DefaultSendTimeout)
+
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	SetSendTimeout(200 * time.Millisecond)
+	start := time.Now()
+	err := (&WebhookProvider{}).Send(context.Background(),
+		&Notification{Config: map[string]interface{}{"webhook_url": server.URL}},
+		&Message{Title: "test", Status: "down"})
+	if err == nil {
+		t.Fatal("Send succeeded against a hanging endpoint")
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Send took %s, want it cut off after 200ms", elapsed)
+	}
+
+	SetSendTimeout(0)
+	if got := httpClient().Timeout; got != DefaultSendTimeout {
+		t.Errorf("timeout after SetSendTimeout(0) = %s, want %s", got, DefaultSendTimeout)
+	}
+}
+
+func TestSharedClientCapsResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", maxResponseSize+4096)))
+	}))
+	defer server.Close()
+
+	resp, err := httpClient().Get(server.URL)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	defer resp.Body.Close()
+
+	var read int
+	buf := make([]byte, 32*1024)
+	for {
+		n, err := resp.Body.Read(buf)
+		read += n
+		if err != nil {
+			break
+		}
+	}
+	if read != maxResponseSize {
+		t.Errorf("read %d bytes, want %d", read, maxResponseSize)
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"net/http"
 	"strings"
-	"time"
 )
 
 // NtfyProvider sends Ntfy notifications (self-hosted or ntfy.sh)
@@ -71,7 +70,7 @@ func (n *NtfyProvider) Send(ctx context.Context, notification *Notification, mes
 		req.Header.Set("Actions", fmt.Sprintf("view, View Monitor, %s", message.MonitorURL))
 	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send Ntfy notification: %w", err)
@@ -94,6 +93,14 @@ func (n *NtfyProvider) Validate(config map[string]interface{}) error {
 	return nil
 }
 
+func (n *NtfyProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	serverURL, _ := config["server_url"].(string)
+	if serverURL == "" {
+		serverURL = "https://ntfy.sh"
+	}
+	return probeHTTP(ctx, serverURL)
+}
+
 // getTagsForStatus returns emoji tags based on status
 func getTagsForStatus(status string) string {
 	switch status {
@@ -0,0 +1,246 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// opsgenieBaseURLs are the Opsgenie API endpoints by account region
+var opsgenieBaseURLs = map[string]string{
+	"us": "https://api.opsgenie.com",
+	"eu": "https://api.eu.opsgenie.com",
+}
+
+// Opsgenie caps alert messages and descriptions at these lengths
+const (
+	maxOpsgenieMessage     = 130
+	maxOpsgenieDescription = 15000
+)
+
+// opsgenieResponderTypes are the responder types an alert can be routed to
+var opsgenieResponderTypes = map[string]bool{"team": true, "user": true, "escalation": true, "schedule": true}
+
+// opsgenieEventCounter keeps aliases of monitor-less events unique
+var opsgenieEventCounter atomic.Uint64
+
+// OpsgenieProvider sends Opsgenie Alert API notifications. A down event
+// opens an alert under an alias derived from the monitor, so repeats
+// deduplicate, and the recovery closes it. Warnings get an alias of their
+// own that the warning clearing closes.
+type OpsgenieProvider struct{}
+
+func init() {
+	RegisterProvider(&OpsgenieProvider{})
+}
+
+func (o *OpsgenieProvider) Name() string {
+	return "opsgenie"
+}
+
+func (o *OpsgenieProvider) Send(ctx context.Context, notification *Notification, message *Message) error {
+	// Get Opsgenie configuration
+	apiKey, _ := notification.Config["api_key"].(string)
+	region, _ := notification.Config["region"].(string)
+
+	if apiKey == "" {
+		return fmt.Errorf("api_key is required")
+	}
+
+	baseURL, ok := opsgenieBaseURL(region)
+	if !ok {
+		return fmt.Errorf("region must be us or eu")
+	}
+
+	alias := opsgenieAlias(message)
+
+	// Recoveries close the alert the outage or warning opened. Events without
+	// a monitor, like a test notification, have nothing to close.
+	var endpoint string
+	var payload map[string]interface{}
+	if (message.Status == "up" || message.Status == "warning_cleared") && message.MonitorID > 0 {
+		endpoint = fmt.Sprintf("%s/v2/alerts/%s/close?identifierType=alias", baseURL, url.PathEscape(alias))
+		payload = map[string]interface{}{
+			"source": "Uptime Kabomba",
+			"note":   message.Title,
+		}
+	} else {
+		endpoint = baseURL + "/v2/alerts"
+		payload = opsgenieAlert(notification, message, alias)
+	}
+
+	// Marshal payload
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "GenieKey "+apiKey)
+
+	client := httpClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Opsgenie alert: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var result struct {
+			Message string `json:"message"`
+		}
+		if json.NewDecoder(resp.Body).Decode(&result) == nil && result.Message != "" {
+			return fmt.Errorf("Opsgenie API returned status %d: %s", resp.StatusCode, result.Message)
+		}
+		return fmt.Errorf("Opsgenie API returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// opsgenieBaseURL returns the API endpoint of a region, us by default
+func opsgenieBaseURL(region string) (string, bool) {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if region == "" {
+		region = "us"
+	}
+	baseURL, ok := opsgenieBaseURLs[region]
+	return baseURL, ok
+}
+
+// opsgenieAlias identifies a monitor's alert by its ID, so renames and
+// duplicate names don't break deduplication or closing. Events without a
+// monitor never recover, so each gets an alert of its own.
+func opsgenieAlias(message *Message) string {
+	if message.MonitorID <= 0 {
+		return fmt.Sprintf("uptime-kabomba-event-%d-%d", time.Now().UnixNano(), opsgenieEventCounter.Add(1))
+	}
+	alias := strings.ReplaceAll(defaultDedupKeyTemplate, "{monitor_id}", strconv.Itoa(message.MonitorID))
+	if message.Status == "warning" || message.Status == "warning_cleared" {
+		alias += "-warning"
+	}
+	return alias
+}
+
+// opsgenieAlert builds the body of the create alert request
+func opsgenieAlert(notification *Notification, message *Message, alias string) map[string]interface{} {
+	priority, _ := notification.Config["priority"].(string)
+
+	// Default priority based on status
+	if priority == "" {
+		if message.Status == "down" {
+			priority = "P1"
+		} else {
+			priority = "P3"
+		}
+	}
+
+	// Build details
+	details := map[string]string{
+		"monitor":       message.MonitorName,
+		"status":        message.Status,
+		"response_time": fmt.Sprintf("%dms", message.Ping),
+		"time":          message.Time,
+	}
+
+	if message.MonitorURL != "" {
+		details["url"] = message.MonitorURL
+	}
+
+	if message.DowntimeDuration > 0 {
+		details["downtime"] = FormatDuration(message.DowntimeDuration)
+	}
+
+	alert := map[string]interface{}{
+		"message":     truncateRunes(message.Title, maxOpsgenieMessage),
+		"alias":       alias,
+		"description": truncateRunes(FormatMessage(message), maxOpsgenieDescription),
+		"priority":    strings.ToUpper(priority),
+		"source":      "Uptime Kabomba",
+		"details":     details,
+	}
+
+	if responders, ok := notification.Config["responders"].([]interface{}); ok && len(responders) > 0 {
+		alert["responders"] = responders
+	}
+
+	return alert
+}
+
+// truncateRunes shortens s to at most max characters
+func truncateRunes(s string, max int) string {
+	if runes := []rune(s); len(runes) > max {
+		return string(runes[:max])
+	}
+	return s
+}
+
+func (o *OpsgenieProvider) tracksAlerts() {}
+
+func (o *OpsgenieProvider) Validate(config map[string]interface{}) error {
+	apiKey, ok := config["api_key"].(string)
+	if !ok || apiKey == "" {
+		return fmt.Errorf("api_key is required")
+	}
+
+	if v, ok := config["region"]; ok && v != nil {
+		region, _ := v.(string)
+		if _, ok := opsgenieBaseURL(region); !ok {
+			return fmt.Errorf("region must be us or eu")
+		}
+	}
+
+	if v, ok := config["priority"]; ok && v != nil && v != "" {
+		priority, _ := v.(string)
+		switch strings.ToUpper(priority) {
+		case "P1", "P2", "P3", "P4", "P5":
+		default:
+			return fmt.Errorf("priority must be one of P1 to P5")
+		}
+	}
+
+	if v, ok := config["responders"]; ok && v != nil {
+		responders, ok := v.([]interface{})
+		if !ok {
+			return fmt.Errorf("responders must be a list")
+		}
+		for i, raw := range responders {
+			responder, ok := raw.(map[string]interface{})
+			if !ok {
+				return fmt.Errorf("responders[%d] must be an object", i)
+			}
+			if kind, _ := responder["type"].(string); !opsgenieResponderTypes[kind] {
+				return fmt.Errorf("responders[%d].type must be team, user, escalation or schedule", i)
+			}
+			id, _ := responder["id"].(string)
+			name, _ := responder["name"].(string)
+			username, _ := responder["username"].(string)
+			if id == "" && name == "" && username == "" {
+				return fmt.Errorf("responders[%d] needs an id, name or username", i)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (o *OpsgenieProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	region, _ := config["region"].(string)
+	baseURL, ok := opsgenieBaseURL(region)
+	if !ok {
+		return fmt.Errorf("region must be us or eu")
+	}
+	return probeHTTP(ctx, baseURL)
+}
@@ -0,0 +1,206 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// opsgenieRequest is a request the fake Opsgenie API received
+type opsgenieRequest struct {
+	path string
+	auth string
+	body map[string]interface{}
+}
+
+// newOpsgenieServer points both regions at a fake API recording requests
+func newOpsgenieServer(t *testing.T) *[]opsgenieRequest {
+	t.Helper()
+	var requests []opsgenieRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := opsgenieRequest{path: r.URL.RequestURI(), auth: r.Header.Get("Authorization")}
+		json.NewDecoder(r.Body).Decode(&req.body)
+		requests = append(requests, req)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"result": "Request will be processed", "requestId": "abc"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	saved := opsgenieBaseURLs
+	opsgenieBaseURLs = map[string]string{"us": server.URL, "eu": server.URL + "/eu"}
+	t.Cleanup(func() { opsgenieBaseURLs = saved })
+	return &requests
+}
+
+func TestOpsgenieOpensAndClosesAlert(t *testing.T) {
+	requests := newOpsgenieServer(t)
+	notification := &Notification{Config: map[string]interface{}{
+		"api_key":    "genie-secret",
+		"responders": []interface{}{map[string]interface{}{"type": "team", "name": "ops"}},
+	}}
+	provider := &OpsgenieProvider{}
+
+	down := &Message{Title: "API is DOWN", Body: "timeout", MonitorID: 42, MonitorName: "API", Status: "down"}
+	if err := provider.Send(context.Background(), notification, down); err != nil {
+		t.Fatalf("Send down: %v", err)
+	}
+	up := &Message{Title: "API is UP", MonitorID: 42, MonitorName: "API", Status: "up"}
+	if err := provider.Send(context.Background(), notification, up); err != nil {
+		t.Fatalf("Send up: %v", err)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(*requests))
+	}
+	create, closeReq := (*requests)[0], (*requests)[1]
+
+	if create.path != "/v2/alerts" || create.auth != "GenieKey genie-secret" {
+		t.Errorf("create: path %s, auth %q", create.path, create.auth)
+	}
+	if create.body["alias"] != "uptime-kabomba-42" || create.body["priority"] != "P1" || create.body["message"] != "API is DOWN" {
+		t.Errorf("create body = %v", create.body)
+	}
+	if responders, _ := create.body["responders"].([]interface{}); len(responders) != 1 {
+		t.Errorf("responders = %v", create.body["responders"])
+	}
+
+	if closeReq.path != "/v2/alerts/uptime-kabomba-42/close?identifierType=alias" || closeReq.auth != "GenieKey genie-secret" {
+		t.Errorf("close: path %s, auth %q", closeReq.path, closeReq.auth)
+	}
+}
+
+func TestOpsgenieMonitorlessEventsOpenOwnAlerts(t *testing.T) {
+	requests := newOpsgenieServer(t)
+	notification := &Notification{Config: map[string]interface{}{"api_key": "k"}}
+
+	// API key expiry, database size and an up status without a monitor
+	for _, msg := range []*Message{
+		{Title: "API key is about to EXPIRE", MonitorName: "API key ci"},
+		{Title: "Database size needs ATTENTION", MonitorName: "Database"},
+		{Title: "Something is UP", Status: "up"},
+	} {
+		if err := (&OpsgenieProvider{}).Send(context.Background(), notification, msg); err != nil {
+			t.Fatalf("Send %q: %v", msg.Title, err)
+		}
+	}
+
+	aliases := make(map[interface{}]bool)
+	for _, req := range *requests {
+		if req.path != "/v2/alerts" {
+			t.Errorf("path = %s, want an alert to be created", req.path)
+		}
+		if req.body["alias"] == "uptime-kabomba-0" {
+			t.Errorf("alias = %v, want one not shared with other events", req.body["alias"])
+		}
+		aliases[req.body["alias"]] = true
+	}
+	if len(aliases) != 3 {
+		t.Errorf("got %d distinct aliases for 3 events", len(aliases))
+	}
+}
+
+func TestOpsgenieClosesWarningWhenCleared(t *testing.T) {
+	requests := newOpsgenieServer(t)
+	recorder := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(recorder)
+	channels := []*Notification{
+		{ID: 1, Name: "ops", Type: recorder.Name(), Active: true},
+		{ID: 2, Name: "genie", Type: "opsgenie", Active: true, Config: map[string]interface{}{"api_key": "k"}},
+	}
+	d := &Dispatcher{notificationsFor: func(monitorID int) ([]*Notification, error) { return channels, nil }}
+
+	if err := d.NotifyMonitorWarning(context.Background(), 42, "API", "", "certificate expires in 5 days"); err != nil {
+		t.Fatalf("NotifyMonitorWarning: %v", err)
+	}
+	if err := d.NotifyMonitorWarningCleared(context.Background(), 42, "API", "", "OK"); err != nil {
+		t.Fatalf("NotifyMonitorWarningCleared: %v", err)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("got %d requests, want 2", len(*requests))
+	}
+	create, closeReq := (*requests)[0], (*requests)[1]
+	if create.path != "/v2/alerts" || create.body["alias"] != "uptime-kabomba-42-warning" || create.body["priority"] != "P3" {
+		t.Errorf("create: path %s, body %v", create.path, create.body)
+	}
+	if closeReq.path != "/v2/alerts/uptime-kabomba-42-warning/close?identifierType=alias" {
+		t.Errorf("close path = %s", closeReq.path)
+	}
+
+	// Channels without open alerts only see the warning
+	if sent := recorder.messages(1); len(sent) != 1 || sent[0].Status != "warning" {
+		t.Errorf("other channel received %d messages, want only the warning", len(sent))
+	}
+}
+
+func TestOpsgenieTestNotificationOpensAlert(t *testing.T) {
+	requests := newOpsgenieServer(t)
+	notification := &Notification{Name: "genie", Type: "opsgenie", Active: true, Config: map[string]interface{}{"api_key": "k"}}
+
+	if err := (&Dispatcher{}).TestNotification(context.Background(), notification); err != nil {
+		t.Fatalf("TestNotification: %v", err)
+	}
+
+	if len(*requests) != 1 {
+		t.Fatalf("got %d requests, want 1", len(*requests))
+	}
+	if req := (*requests)[0]; req.path != "/v2/alerts" || req.body["message"] != "Test Notification" {
+		t.Errorf("test notification: path %s, body %v, want a created alert", req.path, req.body)
+	}
+}
+
+func TestOpsgenieRegionAndPriority(t *testing.T) {
+	requests := newOpsgenieServer(t)
+	notification := &Notification{Config: map[string]interface{}{"api_key": "k", "region": "EU", "priority": "p2"}}
+
+	msg := &Message{Title: strings.Repeat("x", 200), MonitorID: 1, Status: "down"}
+	if err := (&OpsgenieProvider{}).Send(context.Background(), notification, msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	req := (*requests)[0]
+	if req.path != "/eu/v2/alerts" {
+		t.Errorf("path = %s, want the EU endpoint", req.path)
+	}
+	if req.body["priority"] != "P2" {
+		t.Errorf("priority = %v, want P2", req.body["priority"])
+	}
+	if title, _ := req.body["message"].(string); len(title) != maxOpsgenieMessage {
+		t.Errorf("message length = %d, want %d", len(title), maxOpsgenieMessage)
+	}
+}
+
+func TestOpsgenieValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  map[string]interface{}
+		wantErr string
+	}{
+		{"minimal", map[string]interface{}{"api_key": "k"}, ""},
+		{"full", map[string]interface{}{"api_key": "k", "region": "eu", "priority": "P4",
+			"responders": []interface{}{map[string]interface{}{"type": "user", "username": "jo@example.com"}}}, ""},
+		{"no key", map[string]interface{}{}, "api_key is required"},
+		{"bad region", map[string]interface{}{"api_key": "k", "region": "apac"}, "region must be us or eu"},
+		{"bad priority", map[string]interface{}{"api_key": "k", "priority": "P9"}, "priority must be"},
+		{"bad responder type", map[string]interface{}{"api_key": "k",
+			"responders": []interface{}{map[string]interface{}{"type": "group", "name": "ops"}}}, "responders[0].type"},
+		{"anonymous responder", map[string]interface{}{"api_key": "k",
+			"responders": []interface{}{map[string]interface{}{"type": "team"}}}, "needs an id, name or username"},
+	}
+
+	for _, tt := range tests {
+		err := (&OpsgenieProvider{}).Validate(tt.config)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("%s: unexpected error %v", tt.name, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
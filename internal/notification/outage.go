@@ -0,0 +1,179 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// pendingDown is a down notification held back while a batch window is open
+type pendingDown struct {
+	monitorID int
+	message   *Message
+}
+
+// outageCoalescer batches down notifications over a short window so a
+// correlated outage can be reported as one summary instead of a flood
+type outageCoalescer struct {
+	window time.Duration
+	flush  func(batch []pendingDown)
+
+	mu      sync.Mutex
+	pending []pendingDown
+	timer   *time.Timer
+}
+
+// newOutageCoalescer creates a coalescer that calls flush with every down
+// notification received within window of the first one
+func newOutageCoalescer(window time.Duration, flush func(batch []pendingDown)) *outageCoalescer {
+	return &outageCoalescer{window: window, flush: flush}
+}
+
+// add queues a down notification, opening a batch window if none is open
+func (c *outageCoalescer) add(monitorID int, message *Message) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.pending = append(c.pending, pendingDown{monitorID: monitorID, message: message})
+	if c.timer == nil {
+		c.timer = time.AfterFunc(c.window, c.release)
+	}
+}
+
+// cancel drops a held down notification for the monitor, reporting whether
+// one was pending
+func (c *outageCoalescer) cancel(monitorID int) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for i, p := range c.pending {
+		if p.monitorID == monitorID {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// release hands the current batch to flush and closes the window
+func (c *outageCoalescer) release() {
+	c.mu.Lock()
+	batch := c.pending
+	c.pending = nil
+	c.timer = nil
+	c.mu.Unlock()
+
+	if len(batch) > 0 {
+		c.flush(batch)
+	}
+}
+
+// alertTracker is implemented by providers that open an alert per monitor and
+// close it when that monitor recovers, like PagerDuty and Opsgenie. A summary
+// has no monitor for a recovery to close it by, so these channels still get
+// an alert per monitor in a mass outage.
+type alertTracker interface {
+	tracksAlerts()
+}
+
+// tracksAlerts reports whether a notification type keeps an alert per monitor
+func tracksAlerts(notificationType string) bool {
+	provider, ok := GetProvider(notificationType)
+	if !ok {
+		return false
+	}
+	_, ok = provider.(alertTracker)
+	return ok
+}
+
+// EnableOutageCoalescing holds down notifications for window and, when at
+// least threshold monitors went down in it, sends each notification channel
+// one summary instead of individual alerts. A threshold of 0 disables it.
+func (d *Dispatcher) EnableOutageCoalescing(threshold int, window time.Duration) {
+	if threshold <= 0 || window <= 0 {
+		d.coalescer = nil
+		return
+	}
+	d.outageThreshold = threshold
+	d.coalescer = newOutageCoalescer(window, d.flushDownBatch)
+}
+
+// flushDownBatch sends a batch of held down notifications, either
+// individually or as one summary per notification channel
+func (d *Dispatcher) flushDownBatch(batch []pendingDown) {
+	ctx := context.Background()
+
+	if len(batch) < d.outageThreshold {
+		for _, p := range batch {
+			if err := d.sendMonitorNotifications(ctx, p.monitorID, p.message); err != nil {
+				log.Printf("Failed to send down notification for monitor %d: %v", p.monitorID, err)
+			}
+		}
+		return
+	}
+
+	log.Printf("Mass outage detected: %d monitors down, sending summary notifications", len(batch))
+
+	// Group the affected monitors by the channels that would have alerted
+	type channelBatch struct {
+		notification *Notification
+		messages     []*Message
+	}
+	var order []int
+	channels := make(map[int]*channelBatch)
+	for _, p := range batch {
+		notifications, err := d.monitorNotifications(p.monitorID)
+		if err != nil {
+			log.Printf("Failed to get notifications for monitor %d: %v", p.monitorID, err)
+			continue
+		}
+		for _, n := range notifications {
+			cb, ok := channels[n.ID]
+			if !ok {
+				cb = &channelBatch{notification: n}
+				channels[n.ID] = cb
+				order = append(order, n.ID)
+			}
+			cb.messages = append(cb.messages, p.message)
+		}
+	}
+
+	for _, id := range order {
+		cb := channels[id]
+		if tracksAlerts(cb.notification.Type) {
+			for _, msg := range cb.messages {
+				if err := d.sendNotification(ctx, cb.notification, msg); err != nil {
+					log.Printf("Failed to send down notification for monitor %d via %s (%s): %v", msg.MonitorID, cb.notification.Type, cb.notification.Name, err)
+				}
+			}
+			continue
+		}
+		if err := d.sendNotification(ctx, cb.notification, buildOutageSummary(cb.messages)); err != nil {
+			log.Printf("Failed to send outage summary via %s (%s): %v", cb.notification.Type, cb.notification.Name, err)
+		}
+	}
+}
+
+// buildOutageSummary combines down messages into a single notification
+func buildOutageSummary(messages []*Message) *Message {
+	var body strings.Builder
+	names := make([]string, 0, len(messages))
+	for _, m := range messages {
+		names = append(names, m.MonitorName)
+		fmt.Fprintf(&body, "- %s: %s\n", m.MonitorName, m.Body)
+	}
+
+	return &Message{
+		Title:       fmt.Sprintf("Mass outage: %d monitors are DOWN", len(messages)),
+		titleKey:    msgMassOutage,
+		titleArgs:   []interface{}{len(messages)},
+		Body:        strings.TrimSuffix(body.String(), "\n"),
+		MonitorName: strings.Join(names, ", "),
+		Status:      "down",
+		Time:        time.Now().Format(time.RFC3339),
+		Important:   true,
+	}
+}
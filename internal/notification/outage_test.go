@@ -0,0 +1,165 @@
+package notification
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingProvider captures every message it is asked to send
+type recordingProvider struct {
+	mu   sync.Mutex
+	sent map[int][]*Message // by notification ID
+}
+
+func (p *recordingProvider) Name() string { return "outage-test" }
+
+func (p *recordingProvider) Send(ctx context.Context, notification *Notification, message *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.sent[notification.ID] = append(p.sent[notification.ID], message)
+	return nil
+}
+
+func (p *recordingProvider) Validate(config map[string]interface{}) error { return nil }
+
+func (p *recordingProvider) messages(notificationID int) []*Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.sent[notificationID]
+}
+
+// newOutageTestDispatcher wires every monitor to two channels backed by a
+// recording provider
+func newOutageTestDispatcher(threshold int, window time.Duration) (*Dispatcher, *recordingProvider) {
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	channels := []*Notification{
+		{ID: 1, Name: "ops", Type: provider.Name(), Active: true},
+		{ID: 2, Name: "oncall", Type: provider.Name(), Active: true},
+	}
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return channels, nil
+		},
+	}
+	d.EnableOutageCoalescing(threshold, window)
+	return d, provider
+}
+
+func TestOutageCoalescingSendsOneSummary(t *testing.T) {
+	d, provider := newOutageTestDispatcher(3, 50*time.Millisecond)
+
+	names := []string{"api", "web", "db", "cache", "queue"}
+	for i, name := range names {
+		if err := d.NotifyMonitorDown(context.Background(), i+1, name, "", 0, "connection refused"); err != nil {
+			t.Fatalf("NotifyMonitorDown: %v", err)
+		}
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	for _, id := range []int{1, 2} {
+		sent := provider.messages(id)
+		if len(sent) != 1 {
+			t.Fatalf("channel %d received %d messages, want 1 summary", id, len(sent))
+		}
+		summary := sent[0]
+		if summary.Title != "Mass outage: 5 monitors are DOWN" {
+			t.Errorf("title = %q", summary.Title)
+		}
+		if summary.Status != "down" || !summary.Important {
+			t.Errorf("summary status = %q important = %v, want down and important", summary.Status, summary.Important)
+		}
+		for _, name := range names {
+			if !strings.Contains(summary.Body, "- "+name+": connection refused") {
+				t.Errorf("summary body missing %q:\n%s", name, summary.Body)
+			}
+		}
+	}
+}
+
+func TestOutageCoalescingBelowThresholdSendsIndividually(t *testing.T) {
+	d, provider := newOutageTestDispatcher(3, 50*time.Millisecond)
+
+	d.NotifyMonitorDown(context.Background(), 1, "api", "", 0, "timeout")
+	d.NotifyMonitorDown(context.Background(), 2, "web", "", 0, "timeout")
+
+	time.Sleep(200 * time.Millisecond)
+
+	sent := provider.messages(1)
+	if len(sent) != 2 {
+		t.Fatalf("received %d messages, want 2 individual alerts", len(sent))
+	}
+	for _, msg := range sent {
+		if msg.Title != "Monitor is DOWN" {
+			t.Errorf("title = %q, want individual down alert", msg.Title)
+		}
+	}
+}
+
+func TestOutageCoalescingDropsRecoveredMonitor(t *testing.T) {
+	d, provider := newOutageTestDispatcher(3, 50*time.Millisecond)
+
+	d.NotifyMonitorDown(context.Background(), 1, "api", "", 0, "timeout")
+	d.NotifyMonitorUp(context.Background(), 1, "api", "", 10, "OK", 0)
+
+	time.Sleep(200 * time.Millisecond)
+
+	if sent := provider.messages(1); len(sent) != 0 {
+		t.Fatalf("received %d messages for a monitor that recovered within the window, want 0", len(sent))
+	}
+}
+
+func TestOutageCoalescingKeepsPagerDutyAlertsPerMonitor(t *testing.T) {
+	var mu sync.Mutex
+	var events []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event struct {
+			EventAction string `json:"event_action"`
+			DedupKey    string `json:"dedup_key"`
+		}
+		json.NewDecoder(r.Body).Decode(&event)
+		mu.Lock()
+		events = append(events, event.EventAction+" "+event.DedupKey)
+		mu.Unlock()
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(`{"status": "success"}`))
+	}))
+	defer server.Close()
+	saved := pagerDutyEventsURL
+	pagerDutyEventsURL = server.URL
+	defer func() { pagerDutyEventsURL = saved }()
+
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+	channels := []*Notification{
+		{ID: 1, Name: "ops", Type: provider.Name(), Active: true},
+		{ID: 2, Name: "pager", Type: "pagerduty", Active: true, Config: map[string]interface{}{"integration_key": "k"}},
+	}
+	d := &Dispatcher{notificationsFor: func(monitorID int) ([]*Notification, error) { return channels, nil }}
+	d.EnableOutageCoalescing(2, 50*time.Millisecond)
+
+	d.NotifyMonitorDown(context.Background(), 1, "api", "", 0, "timeout")
+	d.NotifyMonitorDown(context.Background(), 2, "web", "", 0, "timeout")
+	time.Sleep(200 * time.Millisecond)
+	d.NotifyMonitorUp(context.Background(), 1, "api", "", 10, "OK", time.Minute)
+	d.NotifyMonitorUp(context.Background(), 2, "web", "", 10, "OK", time.Minute)
+
+	if sent := provider.messages(1); len(sent) != 3 || sent[0].Title != "Mass outage: 2 monitors are DOWN" {
+		t.Errorf("summary channel received %d messages, want the summary and two recoveries", len(sent))
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := []string{"trigger uptime-kabomba-1", "trigger uptime-kabomba-2", "resolve uptime-kabomba-1", "resolve uptime-kabomba-2"}
+	if strings.Join(events, ", ") != strings.Join(want, ", ") {
+		t.Errorf("PagerDuty events = %v, want %v", events, want)
+	}
+}
@@ -6,9 +6,17 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
+	"strconv"
+	"strings"
 )
 
+// defaultDedupKeyTemplate groups events by monitor ID so renames and
+// duplicate names don't break alert grouping or auto-resolve
+const defaultDedupKeyTemplate = "uptime-kabomba-{monitor_id}"
+
+// pagerDutyEventsURL is the PagerDuty Events API v2 endpoint
+var pagerDutyEventsURL = "https://events.pagerduty.com/v2/enqueue"
+
 // PagerDutyProvider sends PagerDuty Events API notifications
 type PagerDutyProvider struct{}
 
@@ -40,7 +48,7 @@ func (p *PagerDutyProvider) Send(ctx context.Context, notification *Notification
 
 	// Determine event action based on status
 	eventAction := "trigger"
-	if message.Status == "up" {
+	if message.Status == "up" || message.Status == "warning_cleared" {
 		eventAction = "resolve"
 	}
 
@@ -57,11 +65,15 @@ func (p *PagerDutyProvider) Send(ctx context.Context, notification *Notification
 		customDetails["url"] = message.MonitorURL
 	}
 
+	if message.DowntimeDuration > 0 {
+		customDetails["downtime"] = FormatDuration(message.DowntimeDuration)
+	}
+
 	// Build PagerDuty Events API v2 payload
 	payload := map[string]interface{}{
 		"routing_key":  integrationKey,
 		"event_action": eventAction,
-		"dedup_key":    fmt.Sprintf("uptime-kuma-%s", message.MonitorName),
+		"dedup_key":    pagerDutyDedupKey(notification, message),
 		"payload": map[string]interface{}{
 			"summary":        message.Title,
 			"source":         "Uptime Kabomba",
@@ -78,16 +90,14 @@ func (p *PagerDutyProvider) Send(ctx context.Context, notification *Notification
 	}
 
 	// Send to PagerDuty Events API
-	url := "https://events.pagerduty.com/v2/enqueue"
-
-	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, "POST", pagerDutyEventsURL, bytes.NewBuffer(payloadBytes))
 	if err != nil {
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send PagerDuty event: %w", err)
@@ -112,11 +122,39 @@ func (p *PagerDutyProvider) Send(ctx context.Context, notification *Notification
 	return nil
 }
 
+func (p *PagerDutyProvider) tracksAlerts() {}
+
 func (p *PagerDutyProvider) Validate(config map[string]interface{}) error {
 	integrationKey, ok := config["integration_key"].(string)
 	if !ok || integrationKey == "" {
 		return fmt.Errorf("integration_key is required")
 	}
 
+	if template, ok := config["dedup_key_template"]; ok {
+		if _, ok := template.(string); !ok {
+			return fmt.Errorf("dedup_key_template must be a string")
+		}
+	}
+
 	return nil
 }
+
+// pagerDutyDedupKey builds the dedup key from the optional dedup_key_template
+// config, substituting {monitor_id} and {monitor_name}. Warnings get their
+// own incident, so they neither absorb nor resolve the monitor's outage.
+func pagerDutyDedupKey(notification *Notification, message *Message) string {
+	template, _ := notification.Config["dedup_key_template"].(string)
+	if template == "" {
+		template = defaultDedupKeyTemplate
+	}
+
+	replacer := strings.NewReplacer(
+		"{monitor_id}", strconv.Itoa(message.MonitorID),
+		"{monitor_name}", message.MonitorName,
+	)
+	key := replacer.Replace(template)
+	if message.Status == "warning" || message.Status == "warning_cleared" {
+		key += "-warning"
+	}
+	return key
+}
@@ -0,0 +1,52 @@
+package notification
+
+import "testing"
+
+func TestPagerDutyDedupKey(t *testing.T) {
+	tests := []struct {
+		name   string
+		config map[string]interface{}
+		msg    *Message
+		want   string
+	}{
+		{
+			name:   "default uses monitor id",
+			config: map[string]interface{}{},
+			msg:    &Message{MonitorID: 42, MonitorName: "API"},
+			want:   "uptime-kabomba-42",
+		},
+		{
+			name:   "monitors sharing a name get distinct keys",
+			config: map[string]interface{}{},
+			msg:    &Message{MonitorID: 7, MonitorName: "API"},
+			want:   "uptime-kabomba-7",
+		},
+		{
+			name:   "custom template",
+			config: map[string]interface{}{"dedup_key_template": "prod-{monitor_id}-{monitor_name}"},
+			msg:    &Message{MonitorID: 3, MonitorName: "web"},
+			want:   "prod-3-web",
+		},
+		{
+			name:   "warnings get their own incident",
+			config: map[string]interface{}{},
+			msg:    &Message{MonitorID: 42, Status: "warning_cleared"},
+			want:   "uptime-kabomba-42-warning",
+		},
+		{
+			name:   "empty template falls back to default",
+			config: map[string]interface{}{"dedup_key_template": ""},
+			msg:    &Message{MonitorID: 9},
+			want:   "uptime-kabomba-9",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := pagerDutyDedupKey(&Notification{Config: tt.config}, tt.msg)
+			if got != tt.want {
+				t.Fatalf("pagerDutyDedupKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
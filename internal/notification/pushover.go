@@ -6,7 +6,6 @@ import (
 	"net/http"
 	"net/url"
 	"strings"
-	"time"
 )
 
 // PushoverProvider sends Pushover notifications
@@ -79,7 +78,7 @@ func (p *PushoverProvider) Send(ctx context.Context, notification *Notification,
 
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send Pushover notification: %w", err)
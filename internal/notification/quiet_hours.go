@@ -0,0 +1,145 @@
+package notification
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+// quietHours is a recurring window during which non-important alerts
+// (recoveries, info) are suppressed for a notification. Important alerts such
+// as a monitor going down are always sent.
+type quietHours struct {
+	start    int // minutes after midnight
+	end      int // minutes after midnight; before start when the window spans midnight
+	location *time.Location
+	days     map[time.Weekday]bool // days the window starts on
+}
+
+var quietHoursDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseQuietHours reads the optional "quiet_hours" config:
+//
+//	{"start": "22:00", "end": "07:00", "timezone": "Europe/Rome", "days": ["mon", "tue"]}
+//
+// timezone defaults to UTC and days to every day. It returns nil when quiet
+// hours are not configured.
+func parseQuietHours(config map[string]interface{}) (*quietHours, error) {
+	raw, ok := config["quiet_hours"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	cfg, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("quiet_hours must be an object")
+	}
+
+	q := &quietHours{location: time.UTC, days: make(map[time.Weekday]bool)}
+
+	var err error
+	startRaw, _ := cfg["start"].(string)
+	if q.start, err = parseClock(startRaw); err != nil {
+		return nil, fmt.Errorf("quiet_hours.start: %w", err)
+	}
+	endRaw, _ := cfg["end"].(string)
+	if q.end, err = parseClock(endRaw); err != nil {
+		return nil, fmt.Errorf("quiet_hours.end: %w", err)
+	}
+	if q.start == q.end {
+		return nil, fmt.Errorf("quiet_hours start and end must differ")
+	}
+
+	if tz, _ := cfg["timezone"].(string); tz != "" {
+		if q.location, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("quiet_hours.timezone: unknown time zone %q", tz)
+		}
+	}
+
+	switch days := cfg["days"].(type) {
+	case nil:
+		for _, d := range quietHoursDays {
+			q.days[d] = true
+		}
+	case []interface{}:
+		if len(days) == 0 {
+			return nil, fmt.Errorf("quiet_hours.days must not be empty")
+		}
+		for _, v := range days {
+			name, _ := v.(string)
+			d, ok := quietHoursDays[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("quiet_hours.days: unknown day %v (use sun, mon, ... sat)", v)
+			}
+			q.days[d] = true
+		}
+	default:
+		return nil, fmt.Errorf("quiet_hours.days must be a list")
+	}
+
+	return q, nil
+}
+
+// parseClock parses "HH:MM" into minutes after midnight
+func parseClock(s string) (int, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// active reports whether t falls within quiet hours, in the configured time zone
+func (q *quietHours) active(t time.Time) bool {
+	local := t.In(q.location)
+	minute := local.Hour()*60 + local.Minute()
+
+	if q.start < q.end {
+		return q.days[local.Weekday()] && minute >= q.start && minute < q.end
+	}
+
+	// Spans midnight: the early morning part belongs to the previous day's window
+	if minute >= q.start {
+		return q.days[local.Weekday()]
+	}
+	if minute < q.end {
+		return q.days[local.AddDate(0, 0, -1).Weekday()]
+	}
+	return false
+}
+
+// ValidateQuietHours checks the optional quiet_hours setting of a notification config
+func ValidateQuietHours(config map[string]interface{}) error {
+	_, err := parseQuietHours(config)
+	return err
+}
+
+// withoutQuietHours drops the notifications currently in quiet hours unless
+// the message is important
+func withoutQuietHours(notifications []*Notification, msg *Message, now time.Time) []*Notification {
+	if msg.Important {
+		return notifications
+	}
+
+	kept := make([]*Notification, 0, len(notifications))
+	for _, n := range notifications {
+		q, err := parseQuietHours(n.Config)
+		if err != nil {
+			log.Printf("Ignoring invalid quiet hours for %s (%s): %v", n.Type, n.Name, err)
+		}
+		if q != nil && q.active(now) {
+			log.Printf("Suppressed %q via %s (%s) during quiet hours", msg.Title, n.Type, n.Name)
+			continue
+		}
+		kept = append(kept, n)
+	}
+	return kept
+}
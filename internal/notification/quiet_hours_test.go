@@ -0,0 +1,117 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestQuietHoursHoldsRecoveryButNotDown(t *testing.T) {
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	// A window around the current time, so the test does not depend on the clock
+	now := time.Now().UTC()
+	channel := &Notification{
+		ID:     1,
+		Name:   "oncall",
+		Type:   provider.Name(),
+		Active: true,
+		Config: map[string]interface{}{
+			"quiet_hours": map[string]interface{}{
+				"start": now.Add(-time.Hour).Format("15:04"),
+				"end":   now.Add(time.Hour).Format("15:04"),
+			},
+		},
+	}
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return []*Notification{channel}, nil
+		},
+	}
+
+	if err := d.NotifyMonitorUp(context.Background(), 1, "api", "", 20, "OK", 0); err != nil {
+		t.Fatalf("NotifyMonitorUp: %v", err)
+	}
+	if sent := provider.messages(1); len(sent) != 0 {
+		t.Fatalf("recovery alert sent during quiet hours: %q", sent[0].Title)
+	}
+
+	if err := d.NotifyMonitorDown(context.Background(), 1, "api", "", 0, "timeout"); err != nil {
+		t.Fatalf("NotifyMonitorDown: %v", err)
+	}
+	sent := provider.messages(1)
+	if len(sent) != 1 || sent[0].Title != "Monitor is DOWN" {
+		t.Fatalf("sent = %d messages, want the down alert", len(sent))
+	}
+}
+
+func TestQuietHoursActive(t *testing.T) {
+	rome, err := time.LoadLocation("Europe/Rome")
+	if err != nil {
+		t.Skipf("time zone data unavailable: %v", err)
+	}
+
+	overnight, err := parseQuietHours(map[string]interface{}{
+		"quiet_hours": map[string]interface{}{
+			"start":    "22:00",
+			"end":      "07:00",
+			"timezone": "Europe/Rome",
+			"days":     []interface{}{"fri", "sat"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("parseQuietHours: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		at   time.Time
+		want bool
+	}{
+		// 2026-06-05 is a Friday
+		{"friday late evening", time.Date(2026, 6, 5, 23, 30, 0, 0, rome), true},
+		{"saturday early morning belongs to friday night", time.Date(2026, 6, 6, 3, 0, 0, 0, rome), true},
+		{"saturday midday", time.Date(2026, 6, 6, 12, 0, 0, 0, rome), false},
+		{"end is exclusive", time.Date(2026, 6, 6, 7, 0, 0, 0, rome), false},
+		{"thursday night is not quiet", time.Date(2026, 6, 4, 23, 0, 0, 0, rome), false},
+		{"monday early morning follows sunday, not quiet", time.Date(2026, 6, 8, 3, 0, 0, 0, rome), false},
+		// 21:30 UTC is 23:30 in Rome during summer time
+		{"evaluated in the configured zone", time.Date(2026, 6, 5, 21, 30, 0, 0, time.UTC), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := overnight.active(tt.at); got != tt.want {
+				t.Errorf("active(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateQuietHours(t *testing.T) {
+	valid := []map[string]interface{}{
+		{},
+		{"quiet_hours": map[string]interface{}{"start": "09:00", "end": "17:00"}},
+	}
+	for _, config := range valid {
+		if err := ValidateQuietHours(config); err != nil {
+			t.Errorf("ValidateQuietHours(%v): %v", config, err)
+		}
+	}
+
+	invalid := []map[string]interface{}{
+		{"quiet_hours": "22:00-07:00"},
+		{"quiet_hours": map[string]interface{}{"start": "25:00", "end": "07:00"}},
+		{"quiet_hours": map[string]interface{}{"start": "22:00"}},
+		{"quiet_hours": map[string]interface{}{"start": "22:00", "end": "22:00"}},
+		{"quiet_hours": map[string]interface{}{"start": "22:00", "end": "07:00", "timezone": "Mars/Base"}},
+		{"quiet_hours": map[string]interface{}{"start": "22:00", "end": "07:00", "days": []interface{}{"funday"}}},
+		{"quiet_hours": map[string]interface{}{"start": "22:00", "end": "07:00", "days": []interface{}{}}},
+	}
+	for _, config := range invalid {
+		if err := ValidateQuietHours(config); err == nil {
+			t.Errorf("ValidateQuietHours(%v) succeeded, want error", config)
+		}
+	}
+}
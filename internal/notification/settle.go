@@ -0,0 +1,89 @@
+package notification
+
+import (
+	"sync"
+	"time"
+)
+
+// pendingChange is a monitor's status change held back until it settles
+type pendingChange struct {
+	base    string // status before the change, what a flap back returns to
+	message *Message
+	timer   *time.Timer
+	gen     int // bumped on every reset so a stale timer can tell
+}
+
+// statusSettler holds each monitor's up and down notifications for a short
+// window. A further change restarts the window with the newest status; one
+// back to the status before the first change drops the notification, since
+// nothing changed. Only the settled status is sent.
+type statusSettler struct {
+	window time.Duration
+	send   func(monitorID int, message *Message)
+
+	mu      sync.Mutex
+	pending map[int]*pendingChange
+}
+
+// newStatusSettler creates a settler that calls send with each monitor's
+// status once it has held for window
+func newStatusSettler(window time.Duration, send func(monitorID int, message *Message)) *statusSettler {
+	return &statusSettler{window: window, send: send, pending: make(map[int]*pendingChange)}
+}
+
+// add holds a status change of the monitor, restarting its window
+func (s *statusSettler) add(monitorID int, message *Message) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	p, ok := s.pending[monitorID]
+	if ok {
+		p.timer.Stop()
+		if message.Status == p.base {
+			delete(s.pending, monitorID)
+			return
+		}
+		p.message = message
+	} else {
+		p = &pendingChange{base: previousStatus(message.Status), message: message}
+		s.pending[monitorID] = p
+	}
+
+	p.gen++
+	gen := p.gen
+	p.timer = time.AfterFunc(s.window, func() { s.release(monitorID, p, gen) })
+}
+
+// release sends the monitor's settled status unless the change was dropped
+// or its window restarted since the timer was set
+func (s *statusSettler) release(monitorID int, p *pendingChange, gen int) {
+	s.mu.Lock()
+	if s.pending[monitorID] != p || p.gen != gen {
+		s.mu.Unlock()
+		return
+	}
+	delete(s.pending, monitorID)
+	s.mu.Unlock()
+
+	s.send(monitorID, p.message)
+}
+
+// previousStatus is the status a monitor had before changing to status
+func previousStatus(status string) string {
+	if status == "down" {
+		return "up"
+	}
+	return "down"
+}
+
+// EnableSettling holds up and down notifications for window after a status
+// change and sends only the status the monitor settled on, so a flapping
+// monitor sends one notification instead of one per flip. A window of 0
+// disables it.
+func (d *Dispatcher) EnableSettling(window time.Duration) {
+	if window <= 0 {
+		d.settler = nil
+		return
+	}
+	d.settler = newStatusSettler(window, d.sendSettled)
+}
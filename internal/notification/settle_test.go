@@ -0,0 +1,83 @@
+package notification
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// newSettleTestDispatcher wires every monitor to one channel backed by a
+// recording provider, with flap settling enabled
+func newSettleTestDispatcher(window time.Duration) (*Dispatcher, *recordingProvider) {
+	provider := &recordingProvider{sent: make(map[int][]*Message)}
+	RegisterProvider(provider)
+
+	channels := []*Notification{{ID: 1, Name: "ops", Type: provider.Name(), Active: true}}
+	d := &Dispatcher{
+		notificationsFor: func(monitorID int) ([]*Notification, error) {
+			return channels, nil
+		},
+	}
+	d.EnableSettling(window)
+	return d, provider
+}
+
+func TestSettlingSendsOnlyFinalStatus(t *testing.T) {
+	d, provider := newSettleTestDispatcher(50 * time.Millisecond)
+	ctx := context.Background()
+
+	d.NotifyMonitorDown(ctx, 1, "api", "", 0, "timeout")
+	d.NotifyMonitorUp(ctx, 1, "api", "", 10, "OK", time.Second)
+	d.NotifyMonitorDown(ctx, 1, "api", "", 0, "connection refused")
+
+	if sent := provider.messages(1); len(sent) != 0 {
+		t.Fatalf("sent %d messages before the window closed, want 0", len(sent))
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	sent := provider.messages(1)
+	if len(sent) != 1 {
+		t.Fatalf("flap sent %d messages, want 1 settled notification", len(sent))
+	}
+	if sent[0].Status != "down" || sent[0].Body != "connection refused" {
+		t.Errorf("settled on %q (%q), want the final down", sent[0].Status, sent[0].Body)
+	}
+}
+
+func TestSettlingDropsFlapBackToPreviousStatus(t *testing.T) {
+	d, provider := newSettleTestDispatcher(50 * time.Millisecond)
+	ctx := context.Background()
+
+	d.NotifyMonitorDown(ctx, 1, "api", "", 0, "timeout")
+	d.NotifyMonitorUp(ctx, 1, "api", "", 10, "OK", time.Second)
+
+	time.Sleep(200 * time.Millisecond)
+
+	if sent := provider.messages(1); len(sent) != 0 {
+		t.Fatalf("sent %d messages for a monitor that flapped back up, want 0", len(sent))
+	}
+}
+
+func TestSettlingWaitsAFullWindowAfterLastChange(t *testing.T) {
+	d, provider := newSettleTestDispatcher(80 * time.Millisecond)
+	ctx := context.Background()
+
+	d.NotifyMonitorUp(ctx, 1, "api", "", 10, "OK", time.Minute)
+	time.Sleep(50 * time.Millisecond)
+	d.NotifyMonitorDown(ctx, 1, "api", "", 0, "timeout")
+	time.Sleep(50 * time.Millisecond)
+	d.NotifyMonitorUp(ctx, 1, "api", "", 12, "OK again", time.Minute)
+
+	// 100ms after the first change, but the last one has just happened
+	if sent := provider.messages(1); len(sent) != 0 {
+		t.Fatalf("sent %d messages before the status settled, want 0", len(sent))
+	}
+
+	time.Sleep(200 * time.Millisecond)
+
+	sent := provider.messages(1)
+	if len(sent) != 1 || sent[0].Status != "up" || sent[0].Body != "OK again" {
+		t.Fatalf("got %d messages, want one settled up notification with the latest body", len(sent))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"time"
 )
 
@@ -23,14 +24,48 @@ func (s *SlackProvider) Name() string {
 func (s *SlackProvider) Send(ctx context.Context, notification *Notification, message *Message) error {
 	// Get Slack webhook URL
 	webhookURL, _ := notification.Config["webhook_url"].(string)
-	channel, _ := notification.Config["channel"].(string)
-	username, _ := notification.Config["username"].(string)
-	iconEmoji, _ := notification.Config["icon_emoji"].(string)
 
 	if webhookURL == "" {
 		return fmt.Errorf("webhook_url is required")
 	}
 
+	// Marshal payload
+	payloadBytes, err := json.Marshal(slackPayload(notification, message))
+	if err != nil {
+		return fmt.Errorf("failed to marshal payload: %w", err)
+	}
+
+	// Send webhook
+	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(payloadBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	client := httpClient()
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send Slack webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// slackPayload builds the webhook body: Block Kit when use_blocks is set,
+// legacy attachments otherwise
+func slackPayload(notification *Notification, message *Message) map[string]interface{} {
+	channel, _ := notification.Config["channel"].(string)
+	username, _ := notification.Config["username"].(string)
+	iconEmoji, _ := notification.Config["icon_emoji"].(string)
+	useBlocks, _ := notification.Config["use_blocks"].(bool)
+	statusPageURL, _ := notification.Config["status_page_url"].(string)
+
 	// Default username
 	if username == "" {
 		username = "Uptime Kabomba"
@@ -45,11 +80,26 @@ func (s *SlackProvider) Send(ctx context.Context, notification *Notification, me
 			iconEmoji = ":x:"
 		case "maintenance":
 			iconEmoji = ":wrench:"
+		case "warning":
+			iconEmoji = ":warning:"
 		default:
 			iconEmoji = ":information_source:"
 		}
 	}
 
+	if useBlocks {
+		payload := map[string]interface{}{
+			"username":   username,
+			"icon_emoji": iconEmoji,
+			"text":       message.Title, // shown in notifications and by clients without Block Kit
+			"blocks":     slackBlocks(message, statusPageURL),
+		}
+		if channel != "" {
+			payload["channel"] = channel
+		}
+		return payload
+	}
+
 	// Determine color based on status
 	var color string
 	switch message.Status {
@@ -59,6 +109,8 @@ func (s *SlackProvider) Send(ctx context.Context, notification *Notification, me
 		color = "danger" // Red
 	case "maintenance":
 		color = "#0000FF" // Blue
+	case "warning":
+		color = "warning" // Orange
 	default:
 		color = "#808080" // Gray
 	}
@@ -77,28 +129,36 @@ func (s *SlackProvider) Send(ctx context.Context, notification *Notification, me
 	// Add fields
 	fields := attachment["fields"].([]map[string]interface{})
 	fields = append(fields, map[string]interface{}{
-		"title": "Monitor",
+		"title": translate(message.Locale, labelMonitor),
 		"value": message.MonitorName,
 		"short": true,
 	})
 
 	fields = append(fields, map[string]interface{}{
-		"title": "Status",
+		"title": translate(message.Locale, labelStatus),
 		"value": message.Status,
 		"short": true,
 	})
 
 	if message.Ping > 0 {
 		fields = append(fields, map[string]interface{}{
-			"title": "Response Time",
+			"title": translate(message.Locale, labelResponseTime),
 			"value": fmt.Sprintf("%dms", message.Ping),
 			"short": true,
 		})
 	}
 
+	if message.DowntimeDuration > 0 {
+		fields = append(fields, map[string]interface{}{
+			"title": translate(message.Locale, labelDowntime),
+			"value": FormatDuration(message.DowntimeDuration),
+			"short": true,
+		})
+	}
+
 	if message.MonitorURL != "" {
 		fields = append(fields, map[string]interface{}{
-			"title": "URL",
+			"title": translate(message.Locale, labelURL),
 			"value": message.MonitorURL,
 			"short": false,
 		})
@@ -118,32 +178,83 @@ func (s *SlackProvider) Send(ctx context.Context, notification *Notification, me
 		payload["channel"] = channel
 	}
 
-	// Marshal payload
-	payloadBytes, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal payload: %w", err)
+	return payload
+}
+
+// slackHeaderMaxLen is Slack's limit for header block text
+const slackHeaderMaxLen = 150
+
+// slackBlocks builds a Block Kit message: a header, the message body, a
+// fields section and buttons to the monitored URL and status page
+func slackBlocks(message *Message, statusPageURL string) []map[string]interface{} {
+	title := message.Title
+	if runes := []rune(title); len(runes) > slackHeaderMaxLen {
+		title = string(runes[:slackHeaderMaxLen-1]) + "…"
 	}
 
-	// Send webhook
-	req, err := http.NewRequestWithContext(ctx, "POST", webhookURL, bytes.NewBuffer(payloadBytes))
-	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+	blocks := []map[string]interface{}{
+		{
+			"type": "header",
+			"text": map[string]interface{}{"type": "plain_text", "text": title, "emoji": true},
+		},
 	}
 
-	req.Header.Set("Content-Type", "application/json")
+	if message.Body != "" {
+		blocks = append(blocks, map[string]interface{}{
+			"type": "section",
+			"text": map[string]interface{}{"type": "mrkdwn", "text": message.Body},
+		})
+	}
 
-	client := &http.Client{Timeout: 10 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		return fmt.Errorf("failed to send Slack webhook: %w", err)
+	fields := []map[string]interface{}{
+		{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", translate(message.Locale, labelMonitor), message.MonitorName)},
+		{"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", translate(message.Locale, labelStatus), message.Status)},
 	}
-	defer resp.Body.Close()
+	if message.Ping > 0 {
+		fields = append(fields, map[string]interface{}{
+			"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%dms", translate(message.Locale, labelResponseTime), message.Ping),
+		})
+	}
+	if message.DowntimeDuration > 0 {
+		fields = append(fields, map[string]interface{}{
+			"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", translate(message.Locale, labelDowntime), FormatDuration(message.DowntimeDuration)),
+		})
+	}
+	if message.Time != "" {
+		fields = append(fields, map[string]interface{}{
+			"type": "mrkdwn", "text": fmt.Sprintf("*%s*\n%s", translate(message.Locale, labelTime), message.Time),
+		})
+	}
+	blocks = append(blocks, map[string]interface{}{"type": "section", "fields": fields})
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return fmt.Errorf("Slack webhook returned status %d", resp.StatusCode)
+	// Slack rejects buttons whose URL is not http(s)
+	var buttons []map[string]interface{}
+	if isHTTPURL(message.MonitorURL) {
+		buttons = append(buttons, slackButton("Open Monitor", message.MonitorURL, "open_monitor"))
+	}
+	if isHTTPURL(statusPageURL) {
+		buttons = append(buttons, slackButton("Status Page", statusPageURL, "open_status_page"))
+	}
+	if len(buttons) > 0 {
+		blocks = append(blocks, map[string]interface{}{"type": "actions", "elements": buttons})
 	}
 
-	return nil
+	return blocks
+}
+
+func slackButton(text, link, actionID string) map[string]interface{} {
+	return map[string]interface{}{
+		"type":      "button",
+		"text":      map[string]interface{}{"type": "plain_text", "text": text},
+		"url":       link,
+		"action_id": actionID,
+	}
+}
+
+// isHTTPURL reports whether raw is an absolute http or https URL
+func isHTTPURL(raw string) bool {
+	u, err := url.Parse(raw)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
 }
 
 func (s *SlackProvider) Validate(config map[string]interface{}) error {
@@ -152,5 +263,23 @@ func (s *SlackProvider) Validate(config map[string]interface{}) error {
 		return fmt.Errorf("webhook_url is required")
 	}
 
+	if v, ok := config["use_blocks"]; ok && v != nil {
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("use_blocks must be a boolean")
+		}
+	}
+
+	if v, ok := config["status_page_url"]; ok && v != nil && v != "" {
+		statusPageURL, _ := v.(string)
+		if !isHTTPURL(statusPageURL) {
+			return fmt.Errorf("status_page_url must be an http or https URL")
+		}
+	}
+
 	return nil
 }
+
+func (s *SlackProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	webhookURL, _ := config["webhook_url"].(string)
+	return probeHTTP(ctx, webhookURL)
+}
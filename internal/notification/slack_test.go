@@ -0,0 +1,129 @@
+package notification
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// slackPayloadJSON round-trips the payload through JSON, as Slack receives it
+func slackPayloadJSON(t *testing.T, config map[string]interface{}, msg *Message) map[string]interface{} {
+	t.Helper()
+	data, err := json.Marshal(slackPayload(&Notification{Config: config}, msg))
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	var payload map[string]interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	return payload
+}
+
+func TestSlackBlocksPayload(t *testing.T) {
+	msg := &Message{
+		Title:       "Monitor is DOWN",
+		Body:        "connection refused",
+		MonitorName: "API",
+		MonitorURL:  "https://api.example.com/health",
+		Status:      "down",
+		Ping:        120,
+	}
+	payload := slackPayloadJSON(t, map[string]interface{}{
+		"webhook_url":     "https://hooks.slack.com/services/x",
+		"use_blocks":      true,
+		"status_page_url": "https://status.example.com/status/acme",
+		"channel":         "#alerts",
+	}, msg)
+
+	if _, ok := payload["attachments"]; ok {
+		t.Error("blocks payload also contains legacy attachments")
+	}
+	if payload["text"] != "Monitor is DOWN" || payload["channel"] != "#alerts" {
+		t.Errorf("text = %v, channel = %v", payload["text"], payload["channel"])
+	}
+
+	blocks, _ := payload["blocks"].([]interface{})
+	var types []string
+	for _, b := range blocks {
+		types = append(types, b.(map[string]interface{})["type"].(string))
+	}
+	want := []string{"header", "section", "section", "actions"}
+	if len(types) != len(want) {
+		t.Fatalf("block types = %v, want %v", types, want)
+	}
+	for i := range want {
+		if types[i] != want[i] {
+			t.Fatalf("block types = %v, want %v", types, want)
+		}
+	}
+
+	header := blocks[0].(map[string]interface{})["text"].(map[string]interface{})
+	if header["type"] != "plain_text" || header["text"] != "Monitor is DOWN" {
+		t.Errorf("header text = %v", header)
+	}
+
+	fields := blocks[2].(map[string]interface{})["fields"].([]interface{})
+	if len(fields) != 3 {
+		t.Fatalf("fields = %d, want monitor, status and response time", len(fields))
+	}
+	if got := fields[0].(map[string]interface{})["text"]; got != "*Monitor*\nAPI" {
+		t.Errorf("first field = %q", got)
+	}
+
+	buttons := blocks[3].(map[string]interface{})["elements"].([]interface{})
+	if len(buttons) != 2 {
+		t.Fatalf("buttons = %d, want 2", len(buttons))
+	}
+	for i, wantURL := range []string{msg.MonitorURL, "https://status.example.com/status/acme"} {
+		button := buttons[i].(map[string]interface{})
+		if button["type"] != "button" || button["url"] != wantURL {
+			t.Errorf("button %d = %v, want a button to %s", i, button, wantURL)
+		}
+	}
+}
+
+func TestSlackBlocksSkipsButtonsWithoutHTTPURL(t *testing.T) {
+	payload := slackPayloadJSON(t, map[string]interface{}{"use_blocks": true}, &Message{
+		Title:      "Monitor is UP",
+		MonitorURL: "tcp://db:5432",
+		Status:     "up",
+	})
+
+	for _, b := range payload["blocks"].([]interface{}) {
+		if b.(map[string]interface{})["type"] == "actions" {
+			t.Error("actions block added without an http(s) link")
+		}
+	}
+}
+
+func TestSlackDefaultsToAttachments(t *testing.T) {
+	payload := slackPayloadJSON(t, map[string]interface{}{}, &Message{Title: "Monitor is UP", Status: "up"})
+
+	if _, ok := payload["blocks"]; ok {
+		t.Error("default payload uses blocks")
+	}
+	if attachments, _ := payload["attachments"].([]interface{}); len(attachments) != 1 {
+		t.Errorf("attachments = %v, want one", payload["attachments"])
+	}
+}
+
+func TestSlackValidateBlocksConfig(t *testing.T) {
+	s := &SlackProvider{}
+	base := func(extra map[string]interface{}) map[string]interface{} {
+		config := map[string]interface{}{"webhook_url": "https://hooks.slack.com/services/x"}
+		for k, v := range extra {
+			config[k] = v
+		}
+		return config
+	}
+
+	if err := s.Validate(base(map[string]interface{}{"use_blocks": true, "status_page_url": "https://status.example.com"})); err != nil {
+		t.Errorf("valid config rejected: %v", err)
+	}
+	if err := s.Validate(base(map[string]interface{}{"use_blocks": "yes"})); err == nil {
+		t.Error("non-boolean use_blocks accepted")
+	}
+	if err := s.Validate(base(map[string]interface{}{"status_page_url": "javascript:alert(1)"})); err == nil {
+		t.Error("non-http status_page_url accepted")
+	}
+}
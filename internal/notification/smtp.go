@@ -3,7 +3,9 @@ package notification
 import (
 	"context"
 	"fmt"
+	"net"
 	"net/smtp"
+	"strconv"
 	"strings"
 )
 
@@ -102,3 +104,19 @@ func (s *SMTPProvider) Validate(config map[string]interface{}) error {
 
 	return nil
 }
+
+func (s *SMTPProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	host, _ := config["smtp_host"].(string)
+	port, _ := config["smtp_port"].(float64)
+	useTLS, _ := config["use_tls"].(bool)
+
+	if port == 0 {
+		if useTLS {
+			port = 587
+		} else {
+			port = 25
+		}
+	}
+
+	return probeTCP(ctx, net.JoinHostPort(host, strconv.Itoa(int(port))))
+}
@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 )
 
 // TeamsProvider sends Microsoft Teams webhook notifications
@@ -37,6 +36,8 @@ func (t *TeamsProvider) Send(ctx context.Context, notification *Notification, me
 		themeColor = "FF0000" // Red
 	case "maintenance":
 		themeColor = "0000FF" // Blue
+	case "warning":
+		themeColor = "FFA500" // Orange
 	default:
 		themeColor = "808080" // Gray
 	}
@@ -44,31 +45,38 @@ func (t *TeamsProvider) Send(ctx context.Context, notification *Notification, me
 	// Build facts
 	facts := []map[string]string{
 		{
-			"name":  "Monitor",
+			"name":  translate(message.Locale, labelMonitor),
 			"value": message.MonitorName,
 		},
 		{
-			"name":  "Status",
+			"name":  translate(message.Locale, labelStatus),
 			"value": message.Status,
 		},
 	}
 
 	if message.Ping > 0 {
 		facts = append(facts, map[string]string{
-			"name":  "Response Time",
+			"name":  translate(message.Locale, labelResponseTime),
 			"value": fmt.Sprintf("%dms", message.Ping),
 		})
 	}
 
+	if message.DowntimeDuration > 0 {
+		facts = append(facts, map[string]string{
+			"name":  translate(message.Locale, labelDowntime),
+			"value": FormatDuration(message.DowntimeDuration),
+		})
+	}
+
 	if message.MonitorURL != "" {
 		facts = append(facts, map[string]string{
-			"name":  "URL",
+			"name":  translate(message.Locale, labelURL),
 			"value": message.MonitorURL,
 		})
 	}
 
 	facts = append(facts, map[string]string{
-		"name":  "Time",
+		"name":  translate(message.Locale, labelTime),
 		"value": message.Time,
 	})
 
@@ -101,7 +109,7 @@ func (t *TeamsProvider) Send(ctx context.Context, notification *Notification, me
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send Teams webhook: %w", err)
@@ -123,3 +131,8 @@ func (t *TeamsProvider) Validate(config map[string]interface{}) error {
 
 	return nil
 }
+
+func (t *TeamsProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	webhookURL, _ := config["webhook_url"].(string)
+	return probeHTTP(ctx, webhookURL)
+}
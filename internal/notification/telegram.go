@@ -6,7 +6,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
-	"time"
 )
 
 // TelegramProvider sends Telegram bot notifications
@@ -43,23 +42,29 @@ func (t *TelegramProvider) Send(ctx context.Context, notification *Notification,
 		statusEmoji = "❌"
 	case "maintenance":
 		statusEmoji = "🔧"
+	case "warning":
+		statusEmoji = "⚠️"
 	default:
 		statusEmoji = "ℹ️"
 	}
 
 	text := fmt.Sprintf("<b>%s %s</b>\n\n", statusEmoji, message.Title)
 	text += fmt.Sprintf("%s\n\n", message.Body)
-	text += fmt.Sprintf("<b>Monitor:</b> %s\n", message.MonitorName)
+	text += fmt.Sprintf("<b>%s:</b> %s\n", translate(message.Locale, labelMonitor), message.MonitorName)
 
 	if message.MonitorURL != "" {
-		text += fmt.Sprintf("<b>URL:</b> %s\n", message.MonitorURL)
+		text += fmt.Sprintf("<b>%s:</b> %s\n", translate(message.Locale, labelURL), message.MonitorURL)
 	}
 
 	if message.Ping > 0 {
-		text += fmt.Sprintf("<b>Response Time:</b> %dms\n", message.Ping)
+		text += fmt.Sprintf("<b>%s:</b> %dms\n", translate(message.Locale, labelResponseTime), message.Ping)
 	}
 
-	text += fmt.Sprintf("<b>Time:</b> %s", message.Time)
+	if message.DowntimeDuration > 0 {
+		text += fmt.Sprintf("<b>%s:</b> %s\n", translate(message.Locale, labelDowntime), FormatDuration(message.DowntimeDuration))
+	}
+
+	text += fmt.Sprintf("<b>%s:</b> %s", translate(message.Locale, labelTime), message.Time)
 
 	// Build payload
 	payload := map[string]interface{}{
@@ -85,7 +90,7 @@ func (t *TelegramProvider) Send(ctx context.Context, notification *Notification,
 
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{Timeout: 10 * time.Second}
+	client := httpClient()
 	resp, err := client.Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send Telegram message: %w", err)
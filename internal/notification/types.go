@@ -3,7 +3,9 @@ package notification
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
+	"time"
 )
 
 // Provider defines the interface for all notification providers
@@ -20,28 +22,40 @@ type Provider interface {
 
 // Notification represents a notification configuration
 type Notification struct {
-	ID        int                    `json:"id" db:"id"`
-	UserID    int                    `json:"user_id" db:"user_id"`
-	Name      string                 `json:"name" db:"name"`
-	Type      string                 `json:"type" db:"type"` // smtp, webhook, discord, etc.
-	Config    map[string]interface{} `json:"config" gorm:"-"`
-	ConfigRaw string                 `json:"-" gorm:"column:config"` // JSON storage
-	IsDefault bool                   `json:"is_default" gorm:"column:is_default"`
-	Active    bool                   `json:"active" gorm:"column:active"`
-	CreatedAt string                 `json:"created_at" db:"created_at"`
-	UpdatedAt string                 `json:"updated_at" db:"updated_at"`
+	ID               int                    `json:"id" db:"id"`
+	UserID           int                    `json:"user_id" db:"user_id"`
+	Name             string                 `json:"name" db:"name"`
+	Type             string                 `json:"type" db:"type"` // smtp, webhook, discord, etc.
+	Config           map[string]interface{} `json:"config" gorm:"-"`
+	ConfigRaw        string                 `json:"-" gorm:"column:config"` // JSON storage
+	IsDefault        bool                   `json:"is_default" gorm:"column:is_default"`
+	ReceiveAllEvents bool                   `json:"receive_all_events" gorm:"column:receive_all_events"`
+	Active           bool                   `json:"active" gorm:"column:active"`
+	CreatedAt        string                 `json:"created_at" db:"created_at"`
+	UpdatedAt        string                 `json:"updated_at" db:"updated_at"`
 }
 
 // Message represents a notification message to be sent
 type Message struct {
 	Title       string
 	Body        string
+	MonitorID   int
 	MonitorName string
 	MonitorURL  string
-	Status      string // "up", "down", "maintenance"
+	Status      string // "up", "down", "maintenance", "warning", "warning_cleared"
 	Ping        int    // milliseconds
 	Time        string
 	Important   bool
+	Locale      string // catalog locale the message was localized for
+
+	// DowntimeDuration is how long the outage a recovery ends lasted, from
+	// its first down check; 0 for other messages
+	DowntimeDuration time.Duration
+
+	// Catalog keys of the standard title and body; see localize
+	titleKey  string
+	titleArgs []interface{}
+	bodyKey   string
 }
 
 // Registry holds all registered notification providers
@@ -86,23 +100,56 @@ func FormatMessage(msg *Message) string {
 		statusEmoji = "❌"
 	case "maintenance":
 		statusEmoji = "🔧"
+	case "warning":
+		statusEmoji = "⚠️"
 	default:
 		statusEmoji = "ℹ️"
 	}
 
 	body := fmt.Sprintf("%s %s\n\n", statusEmoji, msg.Title)
 	body += msg.Body + "\n\n"
-	body += fmt.Sprintf("Monitor: %s\n", msg.MonitorName)
+	body += fmt.Sprintf("%s: %s\n", translate(msg.Locale, labelMonitor), msg.MonitorName)
 
 	if msg.MonitorURL != "" {
-		body += fmt.Sprintf("URL: %s\n", msg.MonitorURL)
+		body += fmt.Sprintf("%s: %s\n", translate(msg.Locale, labelURL), msg.MonitorURL)
 	}
 
 	if msg.Ping > 0 {
-		body += fmt.Sprintf("Response Time: %dms\n", msg.Ping)
+		body += fmt.Sprintf("%s: %dms\n", translate(msg.Locale, labelResponseTime), msg.Ping)
 	}
 
-	body += fmt.Sprintf("Time: %s\n", msg.Time)
+	if msg.DowntimeDuration > 0 {
+		body += fmt.Sprintf("%s: %s\n", translate(msg.Locale, labelDowntime), FormatDuration(msg.DowntimeDuration))
+	}
+
+	body += fmt.Sprintf("%s: %s\n", translate(msg.Locale, labelTime), msg.Time)
 
 	return body
 }
+
+// FormatDuration formats a downtime compactly with its two largest units,
+// e.g. "45s", "12m", "1h 5m" or "2d 3h"
+func FormatDuration(d time.Duration) string {
+	seconds := int64(d.Round(time.Second) / time.Second)
+	if seconds < 60 {
+		return fmt.Sprintf("%ds", max(seconds, 0))
+	}
+
+	units := []struct {
+		suffix  string
+		seconds int64
+	}{{"d", 86400}, {"h", 3600}, {"m", 60}, {"s", 1}}
+	var parts []string
+	for _, unit := range units {
+		if seconds >= unit.seconds {
+			parts = append(parts, fmt.Sprintf("%d%s", seconds/unit.seconds, unit.suffix))
+			seconds %= unit.seconds
+		} else if len(parts) > 0 {
+			break
+		}
+		if len(parts) == 2 {
+			break
+		}
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,66 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// verifyTimeout bounds a single reachability probe
+const verifyTimeout = 5 * time.Second
+
+// Verifier is implemented by providers that can check their endpoint is
+// reachable without sending a notification
+type Verifier interface {
+	// Verify probes the configured endpoint and returns an error when it
+	// cannot be reached
+	Verify(ctx context.Context, config map[string]interface{}) error
+}
+
+// Verify probes the endpoint of a provider configuration. supported is false
+// when the provider has no lightweight probe.
+func Verify(ctx context.Context, provider Provider, config map[string]interface{}) (supported bool, err error) {
+	verifier, ok := provider.(Verifier)
+	if !ok {
+		return false, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	return true, verifier.Verify(ctx, config)
+}
+
+// probeHTTP sends a HEAD request to url. Any HTTP response counts as
+// reachable: webhook endpoints commonly reject HEAD, which still proves the
+// host resolves and answers.
+func probeHTTP(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, url, nil)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+	req.Header.Set("User-Agent", "Uptime-Kuma-Go/1.0")
+
+	client := &http.Client{Timeout: verifyTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	resp.Body.Close()
+
+	return nil
+}
+
+// probeTCP opens and immediately closes a TCP connection to addr
+func probeTCP(ctx context.Context, addr string) error {
+	dialer := &net.Dialer{Timeout: verifyTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("endpoint unreachable: %w", err)
+	}
+	conn.Close()
+
+	return nil
+}
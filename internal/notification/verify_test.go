@@ -0,0 +1,79 @@
+package notification
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+func TestVerifyReachable(t *testing.T) {
+	var method string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method = r.Method
+		// Webhook endpoints often only accept POST; that still counts as reachable
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}))
+	defer server.Close()
+
+	supported, err := Verify(context.Background(), &WebhookProvider{}, map[string]interface{}{
+		"webhook_url": server.URL,
+	})
+	if !supported {
+		t.Fatal("webhook provider should support verification")
+	}
+	if err != nil {
+		t.Fatalf("Verify: %v", err)
+	}
+	if method != http.MethodHead {
+		t.Errorf("probe used %s, want HEAD so no alert is sent", method)
+	}
+}
+
+func TestVerifyUnreachable(t *testing.T) {
+	// Grab a free port and release it so nothing is listening there
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	tests := []struct {
+		name     string
+		provider Provider
+		config   map[string]interface{}
+	}{
+		{
+			name:     "webhook",
+			provider: &WebhookProvider{},
+			config:   map[string]interface{}{"webhook_url": "http://127.0.0.1:" + strconv.Itoa(port) + "/hook"},
+		},
+		{
+			name:     "smtp",
+			provider: &SMTPProvider{},
+			config:   map[string]interface{}{"smtp_host": "127.0.0.1", "smtp_port": float64(port)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			supported, err := Verify(context.Background(), tt.provider, tt.config)
+			if !supported {
+				t.Fatal("provider should support verification")
+			}
+			if err == nil {
+				t.Fatal("Verify succeeded against a closed port, want error")
+			}
+		})
+	}
+}
+
+func TestVerifyUnsupportedProvider(t *testing.T) {
+	supported, err := Verify(context.Background(), &TelegramProvider{}, map[string]interface{}{})
+	if supported || err != nil {
+		t.Fatalf("Verify = (%v, %v), want (false, nil)", supported, err)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 )
 
@@ -42,15 +44,9 @@ func (w *WebhookProvider) Send(ctx context.Context, notification *Notification,
 	}
 
 	// Build payload
-	payload := map[string]interface{}{
-		"title":        message.Title,
-		"body":         message.Body,
-		"monitor_name": message.MonitorName,
-		"monitor_url":  message.MonitorURL,
-		"status":       message.Status,
-		"ping":         message.Ping,
-		"time":         message.Time,
-		"important":    message.Important,
+	payload, err := webhookPayload(notification, message)
+	if err != nil {
+		return err
 	}
 
 	// Marshal payload
@@ -79,11 +75,7 @@ func (w *WebhookProvider) Send(ctx context.Context, notification *Notification,
 	}
 
 	// Send request
-	client := &http.Client{
-		Timeout: 10 * time.Second,
-	}
-
-	resp, err := client.Do(req)
+	resp, err := httpClient().Do(req)
 	if err != nil {
 		return fmt.Errorf("failed to send webhook: %w", err)
 	}
@@ -103,5 +95,83 @@ func (w *WebhookProvider) Validate(config map[string]interface{}) error {
 		return fmt.Errorf("webhook_url is required")
 	}
 
+	if _, err := webhookFields(config); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+func (w *WebhookProvider) Verify(ctx context.Context, config map[string]interface{}) error {
+	url, _ := config["webhook_url"].(string)
+	return probeHTTP(ctx, url)
+}
+
+// webhookPayloadFields lists every field a webhook payload can carry
+var webhookPayloadFields = []string{
+	"title", "body", "monitor_name", "monitor_url", "status", "ping", "time", "important", "downtime",
+}
+
+// webhookPayload builds the outgoing payload, limited to the fields allowed
+// by the "fields" config
+func webhookPayload(notification *Notification, message *Message) (map[string]interface{}, error) {
+	fields, err := webhookFields(notification.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	all := map[string]interface{}{
+		"title":        message.Title,
+		"body":         message.Body,
+		"monitor_name": message.MonitorName,
+		"monitor_url":  message.MonitorURL,
+		"status":       message.Status,
+		"ping":         message.Ping,
+		"time":         message.Time,
+		"important":    message.Important,
+		"downtime":     int(message.DowntimeDuration / time.Second),
+	}
+
+	payload := make(map[string]interface{}, len(fields))
+	for _, field := range fields {
+		payload[field] = all[field]
+	}
+	return payload, nil
+}
+
+// webhookFields returns the payload allowlist from config. "fields" may be a
+// list or a comma separated string; when unset every field is sent.
+func webhookFields(config map[string]interface{}) ([]string, error) {
+	var fields []string
+	switch v := config["fields"].(type) {
+	case nil:
+		return webhookPayloadFields, nil
+	case string:
+		for _, f := range strings.Split(v, ",") {
+			if f = strings.TrimSpace(f); f != "" {
+				fields = append(fields, f)
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			f, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("fields must be a list of strings")
+			}
+			fields = append(fields, strings.TrimSpace(f))
+		}
+	default:
+		return nil, fmt.Errorf("fields must be a list of strings")
+	}
+
+	if len(fields) == 0 {
+		return webhookPayloadFields, nil
+	}
+
+	for _, f := range fields {
+		if !slices.Contains(webhookPayloadFields, f) {
+			return nil, fmt.Errorf("unknown webhook field %q (allowed: %s)", f, strings.Join(webhookPayloadFields, ", "))
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,110 @@
+package notification
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhookPayloadFields(t *testing.T) {
+	message := &Message{
+		Title:       "Monitor is DOWN",
+		Body:        "Request failed",
+		MonitorName: "internal-api",
+		MonitorURL:  "http://10.0.0.5/health",
+		Status:      "down",
+		Ping:        120,
+		Time:        "2026-01-01T00:00:00Z",
+		Important:   true,
+	}
+
+	tests := []struct {
+		name     string
+		fields   interface{}
+		wantKeys []string
+	}{
+		{
+			name:     "default sends every field",
+			fields:   nil,
+			wantKeys: webhookPayloadFields,
+		},
+		{
+			name:     "list allowlist",
+			fields:   []interface{}{"title", "status"},
+			wantKeys: []string{"title", "status"},
+		},
+		{
+			name:     "comma separated allowlist",
+			fields:   "monitor_name, status, time",
+			wantKeys: []string{"monitor_name", "status", "time"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := map[string]interface{}{"webhook_url": "https://example.com/hook"}
+			if tt.fields != nil {
+				config["fields"] = tt.fields
+			}
+
+			payload, err := webhookPayload(&Notification{Config: config}, message)
+			if err != nil {
+				t.Fatalf("webhookPayload: %v", err)
+			}
+			if len(payload) != len(tt.wantKeys) {
+				t.Fatalf("payload = %v, want keys %v", payload, tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := payload[key]; !ok {
+					t.Errorf("payload missing %q", key)
+				}
+			}
+		})
+	}
+}
+
+func TestWebhookSendOmitsExcludedFields(t *testing.T) {
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	notification := &Notification{Config: map[string]interface{}{
+		"webhook_url": server.URL,
+		"fields":      []interface{}{"title", "status"},
+	}}
+	message := &Message{Title: "Monitor is DOWN", Status: "down", MonitorURL: "http://10.0.0.5/health"}
+
+	if err := (&WebhookProvider{}).Send(t.Context(), notification, message); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if _, ok := received["monitor_url"]; ok {
+		t.Errorf("monitor_url should be excluded, got payload %v", received)
+	}
+	if received["title"] != "Monitor is DOWN" || received["status"] != "down" {
+		t.Errorf("unexpected payload %v", received)
+	}
+}
+
+func TestWebhookValidateFields(t *testing.T) {
+	provider := &WebhookProvider{}
+	base := func(fields interface{}) map[string]interface{} {
+		return map[string]interface{}{"webhook_url": "https://example.com", "fields": fields}
+	}
+
+	if err := provider.Validate(base([]interface{}{"title", "ping"})); err != nil {
+		t.Errorf("valid fields rejected: %v", err)
+	}
+	if err := provider.Validate(base([]interface{}{"password"})); err == nil {
+		t.Error("unknown field accepted")
+	}
+	if err := provider.Validate(base(42)); err == nil {
+		t.Error("non-list fields accepted")
+	}
+}
@@ -0,0 +1,163 @@
+package uptime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// BusinessHours is the weekly window an SLA is measured over. Checks outside
+// it count neither for nor against uptime.
+type BusinessHours struct {
+	start    int // minutes after midnight
+	end      int // minutes after midnight, after start
+	location *time.Location
+	days     map[time.Weekday]bool
+}
+
+var businessDays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// ParseBusinessHours reads the optional "business_hours" monitor config:
+//
+//	{"start": "09:00", "end": "17:00", "timezone": "Europe/Rome", "days": ["mon", "tue"]}
+//
+// timezone defaults to UTC and days to Monday to Friday. It returns nil when
+// business hours are not configured.
+func ParseBusinessHours(config map[string]interface{}) (*BusinessHours, error) {
+	raw, ok := config["business_hours"]
+	if !ok || raw == nil {
+		return nil, nil
+	}
+	cfg, ok := raw.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("business_hours must be an object")
+	}
+
+	b := &BusinessHours{location: time.UTC, days: make(map[time.Weekday]bool)}
+
+	var err error
+	startRaw, _ := cfg["start"].(string)
+	if b.start, err = parseClock(startRaw); err != nil {
+		return nil, fmt.Errorf("business_hours.start: %w", err)
+	}
+	endRaw, _ := cfg["end"].(string)
+	if b.end, err = parseClock(endRaw); err != nil {
+		return nil, fmt.Errorf("business_hours.end: %w", err)
+	}
+	if b.end <= b.start {
+		return nil, fmt.Errorf("business_hours.end must be after start")
+	}
+
+	if tz, _ := cfg["timezone"].(string); tz != "" {
+		if b.location, err = time.LoadLocation(tz); err != nil {
+			return nil, fmt.Errorf("business_hours.timezone: unknown time zone %q", tz)
+		}
+	}
+
+	switch days := cfg["days"].(type) {
+	case nil:
+		for d := time.Monday; d <= time.Friday; d++ {
+			b.days[d] = true
+		}
+	case []interface{}:
+		if len(days) == 0 {
+			return nil, fmt.Errorf("business_hours.days must not be empty")
+		}
+		for _, v := range days {
+			name, _ := v.(string)
+			d, ok := businessDays[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("business_hours.days: unknown day %v (use sun, mon, ... sat)", v)
+			}
+			b.days[d] = true
+		}
+	default:
+		return nil, fmt.Errorf("business_hours.days must be a list")
+	}
+
+	return b, nil
+}
+
+// ValidateBusinessHours checks the optional business_hours setting of a monitor config
+func ValidateBusinessHours(config map[string]interface{}) error {
+	_, err := ParseBusinessHours(config)
+	return err
+}
+
+// parseClock parses "HH:MM" into minutes after midnight. "24:00" is accepted
+// as the end of the day.
+func parseClock(s string) (int, error) {
+	if s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a HH:MM time", s)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// Contains reports whether t falls within business hours, in the configured time zone
+func (b *BusinessHours) Contains(t time.Time) bool {
+	local := t.In(b.location)
+	minute := local.Hour()*60 + local.Minute()
+	return b.days[local.Weekday()] && minute >= b.start && minute < b.end
+}
+
+// businessHoursTally sums heartbeats that fall within business hours
+type businessHoursTally struct {
+	hours      *BusinessHours
+	total      int
+	up         int
+	down       int
+	pingSum    float64
+	pingedRows int
+}
+
+// add counts a heartbeat standing for checks checks, unless it is out of
+// hours. Like the SQL average, up heartbeats without a ping are left out of
+// the average ping.
+func (t *businessHoursTally) add(at time.Time, status, checks int, ping *int) {
+	if !t.hours.Contains(at) {
+		return
+	}
+	t.total += checks
+	switch status {
+	case 1:
+		t.up += checks
+		if ping != nil {
+			t.pingSum += float64(*ping)
+			t.pingedRows++
+		}
+	case 0:
+		t.down += checks
+	}
+}
+
+// stats returns the tally as uptime statistics
+func (t *businessHoursTally) stats(monitorID int, startTime, endTime time.Time) *UptimeStats {
+	stats := &UptimeStats{
+		MonitorID:     monitorID,
+		TotalChecks:   t.total,
+		UpChecks:      t.up,
+		DownChecks:    t.down,
+		StartTime:     startTime.Format(time.RFC3339),
+		EndTime:       endTime.Format(time.RFC3339),
+		BusinessHours: true,
+	}
+	if t.total > 0 {
+		stats.UptimePercentage = (float64(t.up) / float64(t.total)) * 100
+	}
+	if t.pingedRows > 0 {
+		stats.AveragePing = t.pingSum / float64(t.pingedRows)
+	}
+	return stats
+}
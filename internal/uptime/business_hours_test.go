@@ -0,0 +1,106 @@
+package uptime
+
+import (
+	"testing"
+	"time"
+)
+
+func romeBusinessHours(t *testing.T) *BusinessHours {
+	t.Helper()
+	hours, err := ParseBusinessHours(map[string]interface{}{
+		"business_hours": map[string]interface{}{"start": "09:00", "end": "17:00", "timezone": "Europe/Rome"},
+	})
+	if err != nil {
+		t.Fatalf("ParseBusinessHours: %v", err)
+	}
+	return hours
+}
+
+func TestBusinessHoursContains(t *testing.T) {
+	hours := romeBusinessHours(t)
+	rome, _ := time.LoadLocation("Europe/Rome")
+
+	tests := []struct {
+		at   time.Time
+		want bool
+	}{
+		{time.Date(2026, 10, 14, 9, 0, 0, 0, rome), true},      // Wednesday opening
+		{time.Date(2026, 10, 14, 16, 59, 0, 0, rome), true},    // last minute
+		{time.Date(2026, 10, 14, 17, 0, 0, 0, rome), false},    // closing
+		{time.Date(2026, 10, 14, 8, 59, 0, 0, rome), false},    // before opening
+		{time.Date(2026, 10, 17, 12, 0, 0, 0, rome), false},    // Saturday
+		{time.Date(2026, 10, 14, 7, 30, 0, 0, time.UTC), true}, // 09:30 in Rome
+	}
+	for _, tt := range tests {
+		if got := hours.Contains(tt.at); got != tt.want {
+			t.Errorf("Contains(%s) = %v, want %v", tt.at, got, tt.want)
+		}
+	}
+}
+
+func TestBusinessHoursTallyExcludesOutOfHours(t *testing.T) {
+	tally := &businessHoursTally{hours: romeBusinessHours(t)}
+	rome, _ := time.LoadLocation("Europe/Rome")
+	ping := func(ms int) *int { return &ms }
+
+	// A working day up, except one failed check at 10:00
+	for hour := 9; hour < 17; hour++ {
+		status := 1
+		if hour == 10 {
+			status = 0
+		}
+		tally.add(time.Date(2026, 10, 14, hour, 0, 0, 0, rome), status, 1, ping(100))
+	}
+	// An overnight and a weekend outage, folded into coalesced heartbeats
+	tally.add(time.Date(2026, 10, 14, 23, 0, 0, 0, rome), 0, 60, nil)
+	tally.add(time.Date(2026, 10, 15, 6, 0, 0, 0, rome), 0, 60, nil)
+	tally.add(time.Date(2026, 10, 17, 12, 0, 0, 0, rome), 0, 120, nil)
+	tally.add(time.Date(2026, 10, 17, 12, 0, 0, 0, rome), 1, 1, ping(900))
+	// A coalesced in-hours heartbeat and one without a ping
+	tally.add(time.Date(2026, 10, 15, 11, 0, 0, 0, rome), 1, 10, ping(100))
+	tally.add(time.Date(2026, 10, 15, 11, 30, 0, 0, rome), 1, 1, nil)
+
+	stats := tally.stats(7, time.Time{}, time.Time{})
+	if stats.TotalChecks != 19 || stats.UpChecks != 18 || stats.DownChecks != 1 {
+		t.Fatalf("got %d total, %d up, %d down; want 19, 18, 1", stats.TotalChecks, stats.UpChecks, stats.DownChecks)
+	}
+	if want := float64(18) / float64(19) * 100; stats.UptimePercentage != want {
+		t.Errorf("uptime = %v, want %v", stats.UptimePercentage, want)
+	}
+	if stats.AveragePing != 100 {
+		t.Errorf("average ping = %v, want 100 from in-hours pings only", stats.AveragePing)
+	}
+	if !stats.BusinessHours || stats.MonitorID != 7 {
+		t.Errorf("stats = %+v, want business hours for monitor 7", stats)
+	}
+}
+
+func TestParseBusinessHours(t *testing.T) {
+	none, err := ParseBusinessHours(map[string]interface{}{})
+	if none != nil || err != nil {
+		t.Errorf("unconfigured = %v, %v; want nil", none, err)
+	}
+
+	weekend, err := ParseBusinessHours(map[string]interface{}{
+		"business_hours": map[string]interface{}{"start": "00:00", "end": "24:00", "days": []interface{}{"Sat", "sun"}},
+	})
+	if err != nil {
+		t.Fatalf("ParseBusinessHours: %v", err)
+	}
+	if !weekend.Contains(time.Date(2026, 10, 18, 23, 59, 0, 0, time.UTC)) || weekend.Contains(time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)) {
+		t.Error("all-day weekend window should cover Sunday 23:59 but not Monday 00:00")
+	}
+
+	for _, config := range []map[string]interface{}{
+		{"business_hours": "09:00-17:00"},
+		{"business_hours": map[string]interface{}{"start": "09:00"}},
+		{"business_hours": map[string]interface{}{"start": "17:00", "end": "09:00"}},
+		{"business_hours": map[string]interface{}{"start": "09:00", "end": "17:00", "timezone": "Mars/Base"}},
+		{"business_hours": map[string]interface{}{"start": "09:00", "end": "17:00", "days": []interface{}{}}},
+		{"business_hours": map[string]interface{}{"start": "09:00", "end": "17:00", "days": []interface{}{"monday"}}},
+	} {
+		if err := ValidateBusinessHours(config); err == nil {
+			t.Errorf("ValidateBusinessHours(%v) succeeded, want error", config)
+		}
+	}
+}
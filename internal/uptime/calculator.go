@@ -28,6 +28,7 @@ type UptimeStats struct {
 	AveragePing       float64 `json:"average_ping"`
 	StartTime         string  `json:"start_time"`
 	EndTime           string  `json:"end_time"`
+	BusinessHours     bool    `json:"business_hours,omitempty"` // only checks within business hours counted
 }
 
 // Calculate24HourUptime calculates uptime for the last 24 hours
@@ -57,9 +58,9 @@ func (c *Calculator) CalculateUptimeForPeriod(monitorID int, duration time.Durat
 
 	query := `
 		SELECT
-			COUNT(*) as total_checks,
-			SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END) as up_checks,
-			SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END) as down_checks,
+			COALESCE(SUM(checks), 0) as total_checks,
+			SUM(CASE WHEN status = 1 THEN checks ELSE 0 END) as up_checks,
+			SUM(CASE WHEN status = 0 THEN checks ELSE 0 END) as down_checks,
 			AVG(CASE WHEN status = 1 THEN ping ELSE NULL END) as average_ping
 		FROM heartbeats
 		WHERE monitor_id = ? AND time >= ? AND time <= ?
@@ -99,9 +100,9 @@ func (c *Calculator) CalculateUptimeForPeriod(monitorID int, duration time.Durat
 func (c *Calculator) CalculateUptimeForTimeRange(monitorID int, startTime, endTime time.Time) (*UptimeStats, error) {
 	query := `
 		SELECT
-			COUNT(*) as total_checks,
-			SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END) as up_checks,
-			SUM(CASE WHEN status = 0 THEN 1 ELSE 0 END) as down_checks,
+			COALESCE(SUM(checks), 0) as total_checks,
+			SUM(CASE WHEN status = 1 THEN checks ELSE 0 END) as up_checks,
+			SUM(CASE WHEN status = 0 THEN checks ELSE 0 END) as down_checks,
 			AVG(CASE WHEN status = 1 THEN ping ELSE NULL END) as average_ping
 		FROM heartbeats
 		WHERE monitor_id = ? AND time >= ? AND time <= ?
@@ -136,6 +137,40 @@ func (c *Calculator) CalculateUptimeForTimeRange(monitorID int, startTime, endTi
 	}, nil
 }
 
+// CalculateBusinessHoursUptime calculates uptime for a time period counting
+// only heartbeats within business hours; the rest are left out of both the
+// up checks and the total
+func (c *Calculator) CalculateBusinessHoursUptime(monitorID int, duration time.Duration, hours *BusinessHours) (*UptimeStats, error) {
+	endTime := time.Now()
+	startTime := endTime.Add(-duration)
+
+	rows, err := c.db.Raw(`
+		SELECT time, status, checks, ping
+		FROM heartbeats
+		WHERE monitor_id = ? AND time >= ? AND time <= ?
+	`, monitorID, startTime, endTime).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tally := &businessHoursTally{hours: hours}
+	for rows.Next() {
+		var at time.Time
+		var status, checks int
+		var ping *int
+		if err := rows.Scan(&at, &status, &checks, &ping); err != nil {
+			return nil, err
+		}
+		tally.add(at, status, checks, ping)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return tally.stats(monitorID, startTime, endTime), nil
+}
+
 // GetUptimeForAllMonitors calculates uptime for all active monitors
 func (c *Calculator) GetUptimeForAllMonitors(duration time.Duration) (map[int]*UptimeStats, error) {
 	// Get all active monitor IDs
@@ -173,51 +208,57 @@ type DailyUptimePoint struct {
 	UpChecks         int     `json:"up_checks"`
 }
 
-// GetDailyUptimeHistory returns uptime stats for each day in the given period
-func (c *Calculator) GetDailyUptimeHistory(monitorID int, days int) ([]DailyUptimePoint, error) {
+// GetDailyUptimeHistory returns uptime stats for each day in the given
+// period, with days starting at midnight in loc
+func (c *Calculator) GetDailyUptimeHistory(monitorID int, days int, loc *time.Location) ([]DailyUptimePoint, error) {
 	endTime := time.Now()
 	startTime := endTime.AddDate(0, 0, -days)
 
-	query := `
-		SELECT
-			DATE(time) as date,
-			COUNT(*) as total_checks,
-			SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END) as up_checks
-		FROM heartbeats
-		WHERE monitor_id = ? AND time >= ? AND time <= ?
-		GROUP BY DATE(time)
-		ORDER BY date ASC
-	`
-
-	rows, err := c.db.Raw(query, monitorID, startTime, endTime).Rows()
+	buckets, err := c.loadCheckBuckets(monitorID, startTime, endTime)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
 	var results []DailyUptimePoint
-	for rows.Next() {
-		var point DailyUptimePoint
-		var totalChecks, upChecks int
-		var date string
-
-		if err := rows.Scan(&date, &totalChecks, &upChecks); err != nil {
-			continue
-		}
+	for _, g := range groupBuckets(buckets, dayLabel(loc)) {
+		results = append(results, DailyUptimePoint{
+			Date:             g.label,
+			UptimePercentage: g.uptimePercentage(),
+			TotalChecks:      g.total,
+			UpChecks:         g.upChecks,
+		})
+	}
+	return results, nil
+}
 
-		uptimePercentage := 0.0
-		if totalChecks > 0 {
-			uptimePercentage = (float64(upChecks) / float64(totalChecks)) * 100
-		}
+// WeeklyUptimePoint represents uptime for a single week, starting Monday
+type WeeklyUptimePoint struct {
+	WeekStart        string  `json:"week_start"`
+	UptimePercentage float64 `json:"uptime_percentage"`
+	TotalChecks      int     `json:"total_checks"`
+	UpChecks         int     `json:"up_checks"`
+}
 
-		point.Date = date
-		point.UptimePercentage = uptimePercentage
-		point.TotalChecks = totalChecks
-		point.UpChecks = upChecks
+// GetWeeklyUptimeHistory returns uptime stats for each week in the given
+// number of weeks, with weeks starting Monday at midnight in loc
+func (c *Calculator) GetWeeklyUptimeHistory(monitorID int, weeks int, loc *time.Location) ([]WeeklyUptimePoint, error) {
+	endTime := time.Now()
+	startTime := endTime.AddDate(0, 0, -7*weeks)
 
-		results = append(results, point)
+	buckets, err := c.loadCheckBuckets(monitorID, startTime, endTime)
+	if err != nil {
+		return nil, err
 	}
 
+	var results []WeeklyUptimePoint
+	for _, g := range groupBuckets(buckets, weekLabel(loc)) {
+		results = append(results, WeeklyUptimePoint{
+			WeekStart:        g.label,
+			UptimePercentage: g.uptimePercentage(),
+			TotalChecks:      g.total,
+			UpChecks:         g.upChecks,
+		})
+	}
 	return results, nil
 }
 
@@ -229,50 +270,25 @@ type HourlyUptimePoint struct {
 	UpChecks         int     `json:"up_checks"`
 }
 
-// GetHourlyUptimeHistory returns uptime stats for each hour in the last 24 hours
-func (c *Calculator) GetHourlyUptimeHistory(monitorID int) ([]HourlyUptimePoint, error) {
+// GetHourlyUptimeHistory returns uptime stats for each hour in the last 24
+// hours, labeled with the hour in loc
+func (c *Calculator) GetHourlyUptimeHistory(monitorID int, loc *time.Location) ([]HourlyUptimePoint, error) {
 	endTime := time.Now()
 	startTime := endTime.Add(-24 * time.Hour)
 
-	query := `
-		SELECT
-			strftime('%Y-%m-%d %H:00:00', time) as hour,
-			COUNT(*) as total_checks,
-			SUM(CASE WHEN status = 1 THEN 1 ELSE 0 END) as up_checks
-		FROM heartbeats
-		WHERE monitor_id = ? AND time >= ? AND time <= ?
-		GROUP BY hour
-		ORDER BY hour ASC
-	`
-
-	rows, err := c.db.Raw(query, monitorID, startTime, endTime).Rows()
+	buckets, err := c.loadCheckBuckets(monitorID, startTime, endTime)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
 
 	var results []HourlyUptimePoint
-	for rows.Next() {
-		var point HourlyUptimePoint
-		var totalChecks, upChecks int
-		var hour string
-
-		if err := rows.Scan(&hour, &totalChecks, &upChecks); err != nil {
-			continue
-		}
-
-		uptimePercentage := 0.0
-		if totalChecks > 0 {
-			uptimePercentage = (float64(upChecks) / float64(totalChecks)) * 100
-		}
-
-		point.Hour = hour
-		point.UptimePercentage = uptimePercentage
-		point.TotalChecks = totalChecks
-		point.UpChecks = upChecks
-
-		results = append(results, point)
+	for _, g := range groupBuckets(buckets, hourLabel(loc)) {
+		results = append(results, HourlyUptimePoint{
+			Hour:             g.label,
+			UptimePercentage: g.uptimePercentage(),
+			TotalChecks:      g.total,
+			UpChecks:         g.upChecks,
+		})
 	}
-
 	return results, nil
 }
@@ -0,0 +1,99 @@
+package uptime
+
+import (
+	"time"
+)
+
+// checkBucket sums the checks of a monitor in one 15 minute slot
+type checkBucket struct {
+	start    time.Time
+	total    int
+	upChecks int
+}
+
+// historyGroup sums the buckets falling in one day, hour or week
+type historyGroup struct {
+	label    string
+	total    int
+	upChecks int
+}
+
+// uptimePercentage returns the share of up checks in the group
+func (g historyGroup) uptimePercentage() float64 {
+	if g.total == 0 {
+		return 0
+	}
+	return (float64(g.upChecks) / float64(g.total)) * 100
+}
+
+// loadCheckBuckets sums a monitor's checks between start and end in 15
+// minute slots. Every time zone offset is a whole number of quarter hours,
+// so a slot never straddles a local day or hour and can be grouped in any
+// user's time zone.
+func (c *Calculator) loadCheckBuckets(monitorID int, startTime, endTime time.Time) ([]checkBucket, error) {
+	query := `
+		SELECT
+			date_trunc('hour', time) + FLOOR(EXTRACT(MINUTE FROM time) / 15) * INTERVAL '15 minutes' as slot,
+			COALESCE(SUM(checks), 0) as total_checks,
+			SUM(CASE WHEN status = 1 THEN checks ELSE 0 END) as up_checks
+		FROM heartbeats
+		WHERE monitor_id = ? AND time >= ? AND time <= ?
+		GROUP BY slot
+		ORDER BY slot ASC
+	`
+
+	rows, err := c.db.Raw(query, monitorID, startTime, endTime).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var buckets []checkBucket
+	for rows.Next() {
+		var b checkBucket
+		if err := rows.Scan(&b.start, &b.total, &b.upChecks); err != nil {
+			return nil, err
+		}
+		buckets = append(buckets, b)
+	}
+	return buckets, rows.Err()
+}
+
+// groupBuckets sums buckets sharing a label, in order of first appearance.
+// Buckets must be sorted by start.
+func groupBuckets(buckets []checkBucket, label func(time.Time) string) []historyGroup {
+	var groups []historyGroup
+	for _, b := range buckets {
+		l := label(b.start)
+		if len(groups) == 0 || groups[len(groups)-1].label != l {
+			groups = append(groups, historyGroup{label: l})
+		}
+		g := &groups[len(groups)-1]
+		g.total += b.total
+		g.upChecks += b.upChecks
+	}
+	return groups
+}
+
+// dayLabel returns the date t falls on in loc
+func dayLabel(loc *time.Location) func(time.Time) string {
+	return func(t time.Time) string {
+		return t.In(loc).Format("2006-01-02")
+	}
+}
+
+// hourLabel returns the hour t falls in, in loc
+func hourLabel(loc *time.Location) func(time.Time) string {
+	return func(t time.Time) string {
+		return t.In(loc).Format("2006-01-02 15:00:00")
+	}
+}
+
+// weekLabel returns the date of the Monday starting the week t falls in, in loc
+func weekLabel(loc *time.Location) func(time.Time) string {
+	return func(t time.Time) string {
+		local := t.In(loc)
+		offset := (int(local.Weekday()) + 6) % 7 // days since Monday
+		return local.AddDate(0, 0, -offset).Format("2006-01-02")
+	}
+}
@@ -0,0 +1,54 @@
+package uptime
+
+import (
+	"testing"
+	"time"
+)
+
+func TestGroupBucketsShiftsDayBoundaries(t *testing.T) {
+	// Late evening in UTC is already the next day in Tokyo (UTC+9)
+	buckets := []checkBucket{
+		{start: time.Date(2026, 10, 14, 10, 0, 0, 0, time.UTC), total: 4, upChecks: 4},
+		{start: time.Date(2026, 10, 14, 16, 0, 0, 0, time.UTC), total: 4, upChecks: 0},
+		{start: time.Date(2026, 10, 14, 23, 45, 0, 0, time.UTC), total: 4, upChecks: 2},
+	}
+
+	utc := groupBuckets(buckets, dayLabel(time.UTC))
+	if len(utc) != 1 || utc[0].label != "2026-10-14" || utc[0].total != 12 {
+		t.Fatalf("UTC days = %+v, want one day of 12 checks", utc)
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+	days := groupBuckets(buckets, dayLabel(tokyo))
+	if len(days) != 2 {
+		t.Fatalf("Tokyo days = %+v, want 2", days)
+	}
+	if days[0].label != "2026-10-14" || days[0].total != 4 || days[0].uptimePercentage() != 100 {
+		t.Errorf("first Tokyo day = %+v, want 2026-10-14 fully up", days[0])
+	}
+	if days[1].label != "2026-10-15" || days[1].total != 8 || days[1].upChecks != 2 {
+		t.Errorf("second Tokyo day = %+v, want 2026-10-15 with 2 of 8 up", days[1])
+	}
+}
+
+func TestHourAndWeekLabels(t *testing.T) {
+	kolkata, err := time.LoadLocation("Asia/Kolkata")
+	if err != nil {
+		t.Skip("time zone database unavailable")
+	}
+
+	// UTC+5:30 moves a quarter hour slot into a different local hour
+	at := time.Date(2026, 10, 18, 18, 45, 0, 0, time.UTC) // Sunday 18:45 UTC, Monday 00:15 in Kolkata
+	if got := hourLabel(kolkata)(at); got != "2026-10-19 00:00:00" {
+		t.Errorf("hourLabel = %q, want 2026-10-19 00:00:00", got)
+	}
+	if got := weekLabel(time.UTC)(at); got != "2026-10-12" {
+		t.Errorf("UTC weekLabel = %q, want the Monday before, 2026-10-12", got)
+	}
+	if got := weekLabel(kolkata)(at); got != "2026-10-19" {
+		t.Errorf("Kolkata weekLabel = %q, want 2026-10-19", got)
+	}
+}
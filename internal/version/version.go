@@ -0,0 +1,5 @@
+package version
+
+// Version is the application version, overridden at build time with
+// -ldflags "-X github.com/fuomag9/uptime-kabomba/internal/version.Version=v1.2.3"
+var Version = "dev"
@@ -3,19 +3,20 @@ package websocket
 import (
 	"context"
 	"encoding/json"
-	"fmt"
 	"log"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 	"nhooyr.io/websocket"
 
+	"github.com/fuomag9/uptime-kabomba/internal/jwtkeys"
 	"github.com/fuomag9/uptime-kabomba/internal/models"
 )
 
@@ -33,6 +34,9 @@ type Client struct {
 	Send chan []byte
 }
 
+// DefaultBroadcastBuffer is the broadcast queue size used when none is configured
+const DefaultBroadcastBuffer = 256
+
 // Hub maintains active clients and broadcasts messages
 type Hub struct {
 	clients       map[*Client]bool
@@ -40,19 +44,24 @@ type Hub struct {
 	register      chan *Client
 	unregister    chan *Client
 	mu            sync.RWMutex
-	jwtSecret     string
+	jwtKeys       *jwtkeys.KeySet
 	allowedOrigins []string
 	db            *gorm.DB
+	dropped       atomic.Uint64
 }
 
-// NewHub creates a new Hub
-func NewHub(jwtSecret string, allowedOrigins []string, db *gorm.DB) *Hub {
+// NewHub creates a new Hub. bufferSize is the number of broadcasts queued
+// for delivery; values below 1 use DefaultBroadcastBuffer.
+func NewHub(jwtKeys *jwtkeys.KeySet, allowedOrigins []string, db *gorm.DB, bufferSize int) *Hub {
+	if bufferSize < 1 {
+		bufferSize = DefaultBroadcastBuffer
+	}
 	return &Hub{
 		clients:       make(map[*Client]bool),
-		broadcast:     make(chan []byte, 256),
+		broadcast:     make(chan []byte, bufferSize),
 		register:      make(chan *Client),
 		unregister:    make(chan *Client),
-		jwtSecret:     jwtSecret,
+		jwtKeys:       jwtKeys,
 		allowedOrigins: allowedOrigins,
 		db:            db,
 	}
@@ -78,7 +87,8 @@ func (h *Hub) Run() {
 			h.mu.Unlock()
 
 		case message := <-h.broadcast:
-			h.mu.RLock()
+			// Slow clients are removed, so this needs the write lock
+			h.mu.Lock()
 			for client := range h.clients {
 				select {
 				case client.Send <- message:
@@ -87,12 +97,14 @@ func (h *Hub) Run() {
 					delete(h.clients, client)
 				}
 			}
-			h.mu.RUnlock()
+			h.mu.Unlock()
 		}
 	}
 }
 
-// Broadcast sends a message to all connected clients
+// Broadcast sends a message to all connected clients. It never blocks: when
+// the queue is full the oldest queued message is dropped to make room, so a
+// stalled hub cannot hold up monitor checks.
 func (h *Hub) Broadcast(msgType string, payload interface{}) error {
 	payloadJSON, err := json.Marshal(payload)
 	if err != nil {
@@ -109,10 +121,35 @@ func (h *Hub) Broadcast(msgType string, payload interface{}) error {
 		return err
 	}
 
-	h.broadcast <- msgJSON
+	h.enqueue(msgJSON)
 	return nil
 }
 
+// enqueue adds msg to the broadcast queue, dropping the oldest queued
+// messages while it is full
+func (h *Hub) enqueue(msg []byte) {
+	for {
+		select {
+		case h.broadcast <- msg:
+			return
+		default:
+		}
+
+		select {
+		case <-h.broadcast:
+			h.dropped.Add(1)
+		default:
+			// Drained by Run in the meantime; retry the send
+		}
+	}
+}
+
+// DroppedBroadcasts returns how many messages were dropped because the
+// broadcast queue was full
+func (h *Hub) DroppedBroadcasts() uint64 {
+	return h.dropped.Load()
+}
+
 // HandleWebSocket handles WebSocket connections
 func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Authenticate WebSocket connection (Authorization header or subprotocol)
@@ -142,17 +179,7 @@ func (h *Hub) HandleWebSocket(w http.ResponseWriter, r *http.Request) {
 	// Validate JWT token with algorithm check
 	userID := ""
 	if token != "" {
-		parsedToken, err := jwt.Parse(token, func(token *jwt.Token) (interface{}, error) {
-			// Validate the algorithm is HMAC
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-			}
-			// Only accept HS256
-			if token.Method.Alg() != "HS256" {
-				return nil, fmt.Errorf("unexpected signing algorithm: %v", token.Method.Alg())
-			}
-			return []byte(h.jwtSecret), nil
-		})
+		parsedToken, err := jwt.Parse(token, h.jwtKeys.Keyfunc)
 
 		if err == nil && parsedToken.Valid {
 			claims := parsedToken.Claims.(jwt.MapClaims)
@@ -0,0 +1,50 @@
+package websocket
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestBroadcastFullBufferDoesNotBlock(t *testing.T) {
+	// Run is not started, so nothing drains the queue
+	h := NewHub(nil, nil, nil, 2)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 5; i++ {
+			if err := h.Broadcast("heartbeat", i); err != nil {
+				t.Errorf("Broadcast: %v", err)
+			}
+		}
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Broadcast blocked on a full buffer")
+	}
+
+	if got := h.DroppedBroadcasts(); got != 3 {
+		t.Errorf("DroppedBroadcasts() = %d, want 3", got)
+	}
+
+	// The newest messages are kept, oldest first
+	for _, want := range []string{"3", "4"} {
+		var msg Message
+		if err := json.Unmarshal(<-h.broadcast, &msg); err != nil {
+			t.Fatalf("unmarshal: %v", err)
+		}
+		if string(msg.Payload) != want {
+			t.Errorf("queued payload = %s, want %s", msg.Payload, want)
+		}
+	}
+}
+
+func TestNewHubDefaultBuffer(t *testing.T) {
+	h := NewHub(nil, nil, nil, 0)
+	if cap(h.broadcast) != DefaultBroadcastBuffer {
+		t.Errorf("buffer = %d, want %d", cap(h.broadcast), DefaultBroadcastBuffer)
+	}
+}
@@ -0,0 +1,2 @@
+-- Remove priority column from monitors table
+ALTER TABLE monitors DROP COLUMN priority;
@@ -0,0 +1,4 @@
+-- Add priority column to monitors table
+-- When MAX_CONCURRENT_CHECKS bounds the check worker pool, queued checks
+-- with a higher priority run first
+ALTER TABLE monitors ADD COLUMN priority INTEGER NOT NULL DEFAULT 0;
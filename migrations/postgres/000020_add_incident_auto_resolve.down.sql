@@ -0,0 +1,4 @@
+-- Remove incident auto-resolution
+DROP INDEX IF EXISTS idx_incidents_auto_resolve;
+ALTER TABLE incidents DROP COLUMN resolved_at;
+ALTER TABLE incidents DROP COLUMN auto_resolve_after;
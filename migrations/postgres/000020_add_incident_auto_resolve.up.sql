@@ -0,0 +1,8 @@
+-- Add optional auto-resolution to incidents
+-- auto_resolve_after: seconds after creation when the incident is resolved automatically (NULL = never)
+-- resolved_at: when the incident was resolved (NULL = still open)
+ALTER TABLE incidents ADD COLUMN auto_resolve_after INTEGER;
+ALTER TABLE incidents ADD COLUMN resolved_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_incidents_auto_resolve ON incidents(created_at)
+    WHERE auto_resolve_after IS NOT NULL AND resolved_at IS NULL;
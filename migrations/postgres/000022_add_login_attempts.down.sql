@@ -0,0 +1,2 @@
+-- Remove login lockout tracking
+DROP TABLE IF EXISTS login_attempts;
@@ -0,0 +1,12 @@
+-- Track failed logins per account to lock out brute-force attempts
+-- key is "user:<id>" for existing accounts and "login:<identifier>" otherwise,
+-- so unknown usernames lock out exactly like real ones
+CREATE TABLE IF NOT EXISTS login_attempts (
+    key TEXT PRIMARY KEY,
+    failed_count INTEGER NOT NULL DEFAULT 0,
+    window_start TIMESTAMP NOT NULL,
+    locked_until TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_login_attempts_updated_at ON login_attempts(updated_at);
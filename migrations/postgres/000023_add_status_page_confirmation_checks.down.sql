@@ -0,0 +1,2 @@
+-- Remove status page outage confirmation
+ALTER TABLE status_pages DROP COLUMN confirmation_checks;
@@ -0,0 +1,3 @@
+-- Number of consecutive down checks before a status page shows a monitor as down
+-- 1 keeps the previous behaviour of showing the latest heartbeat as is
+ALTER TABLE status_pages ADD COLUMN confirmation_checks INTEGER NOT NULL DEFAULT 1;
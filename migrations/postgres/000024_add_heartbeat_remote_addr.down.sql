@@ -0,0 +1,2 @@
+-- Remove heartbeat remote address
+ALTER TABLE heartbeats DROP COLUMN remote_addr;
@@ -0,0 +1,3 @@
+-- IP address that served an HTTP or TCP check (anycast/CDN debugging)
+-- NULL for other monitor types and for checks made through a proxy
+ALTER TABLE heartbeats ADD COLUMN remote_addr TEXT;
@@ -0,0 +1,2 @@
+-- Remove auto-attaching default notifications to new monitors
+ALTER TABLE user_settings DROP COLUMN auto_attach_default_notifications;
@@ -0,0 +1,3 @@
+-- Link a user's default notifications to each new monitor they create
+-- Enabled by default so new monitors alert out of the box
+ALTER TABLE user_settings ADD COLUMN auto_attach_default_notifications BOOLEAN NOT NULL DEFAULT true;
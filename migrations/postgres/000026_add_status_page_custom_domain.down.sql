@@ -0,0 +1,3 @@
+-- Remove status page custom domains
+DROP INDEX IF EXISTS idx_status_pages_custom_domain;
+ALTER TABLE status_pages DROP COLUMN custom_domain;
@@ -0,0 +1,4 @@
+-- Host name that serves a status page directly, e.g. status.example.com
+-- Stored lowercase without a port; NULL when the page is only reachable by slug
+ALTER TABLE status_pages ADD COLUMN custom_domain TEXT;
+CREATE UNIQUE INDEX idx_status_pages_custom_domain ON status_pages (custom_domain) WHERE custom_domain IS NOT NULL;
@@ -0,0 +1,2 @@
+-- Remove the status page history cache
+DROP TABLE IF EXISTS monitor_status_cache;
@@ -0,0 +1,16 @@
+-- Recent status history per monitor for public status pages, updated with each
+-- saved heartbeat so pages don't aggregate heartbeats on every request.
+-- buckets is a JSON array of {"i": bucket index, "w": most severe status,
+-- "l": latest status} covering the last hour; carry_status is the status of the
+-- latest heartbeat older than the buckets.
+-- Monitors without a row (until their next heartbeat) are read from heartbeats.
+CREATE TABLE IF NOT EXISTS monitor_status_cache (
+    monitor_id INTEGER PRIMARY KEY,
+    bucket_seconds INTEGER NOT NULL,
+    last_status INTEGER NOT NULL,
+    last_time TIMESTAMP NOT NULL,
+    carry_status INTEGER,
+    buckets TEXT NOT NULL DEFAULT '[]',
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (monitor_id) REFERENCES monitors(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+-- Remove the monitor public flag
+ALTER TABLE monitors DROP COLUMN public;
@@ -0,0 +1,4 @@
+-- Only monitors marked public are shown on public status pages.
+-- Existing monitors keep showing; new monitors have to opt in.
+ALTER TABLE monitors ADD COLUMN public BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE monitors SET public = TRUE;
@@ -0,0 +1,2 @@
+-- Remove the user admin flag
+ALTER TABLE users DROP COLUMN is_admin;
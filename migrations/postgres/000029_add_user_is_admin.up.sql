@@ -0,0 +1,4 @@
+-- Admins may use features that run code on the server, such as script monitors.
+-- The account created during setup, the earliest user, becomes the admin.
+ALTER TABLE users ADD COLUMN is_admin BOOLEAN NOT NULL DEFAULT FALSE;
+UPDATE users SET is_admin = TRUE WHERE id = (SELECT MIN(id) FROM users);
@@ -0,0 +1,2 @@
+-- Remove the status page uptime percentage toggle
+ALTER TABLE status_pages DROP COLUMN show_uptime_percentage;
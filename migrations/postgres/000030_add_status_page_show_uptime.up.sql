@@ -0,0 +1,3 @@
+-- Whether public status pages publish each monitor's uptime percentage
+-- TRUE keeps showing them on existing pages
+ALTER TABLE status_pages ADD COLUMN show_uptime_percentage BOOLEAN NOT NULL DEFAULT TRUE;
@@ -0,0 +1,2 @@
+ALTER TABLE monitors DROP COLUMN IF EXISTS escalation_policy_id;
+DROP TABLE IF EXISTS escalation_policies;
@@ -0,0 +1,14 @@
+-- Escalation policies notify further channels the longer an outage lasts.
+-- steps holds a JSON array of {"delay_minutes": N, "notification_ids": [...]}.
+CREATE TABLE escalation_policies (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    steps      TEXT NOT NULL DEFAULT '[]',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_escalation_policies_user_id ON escalation_policies(user_id);
+
+ALTER TABLE monitors ADD COLUMN escalation_policy_id INTEGER REFERENCES escalation_policies(id) ON DELETE SET NULL;
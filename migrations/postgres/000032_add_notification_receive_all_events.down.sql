@@ -0,0 +1,2 @@
+-- Remove the receive-all notification flag
+ALTER TABLE notifications DROP COLUMN receive_all_events;
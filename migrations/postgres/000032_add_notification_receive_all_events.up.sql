@@ -0,0 +1,3 @@
+-- Notifications that receive the events of all of the user's monitors,
+-- in addition to each monitor's own or default channels
+ALTER TABLE notifications ADD COLUMN receive_all_events BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Remove the heartbeat check count
+ALTER TABLE heartbeats DROP COLUMN checks;
@@ -0,0 +1,4 @@
+-- How many checks a heartbeat stands for. Monitors with coalesce_interval
+-- fold runs of identical statuses into one row, so uptime sums this column
+-- instead of counting rows. Existing heartbeats each stand for one check.
+ALTER TABLE heartbeats ADD COLUMN checks INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+-- Remove the status page banner
+ALTER TABLE status_pages DROP COLUMN banner_style;
+ALTER TABLE status_pages DROP COLUMN banner;
@@ -0,0 +1,4 @@
+-- An optional announcement banner shown at the top of a status page,
+-- separate from incidents
+ALTER TABLE status_pages ADD COLUMN banner TEXT NOT NULL DEFAULT '';
+ALTER TABLE status_pages ADD COLUMN banner_style VARCHAR(20) NOT NULL DEFAULT 'info';
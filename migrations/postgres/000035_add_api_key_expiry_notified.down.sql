@@ -0,0 +1,2 @@
+-- Remove the API key expiry warning marker
+ALTER TABLE api_keys DROP COLUMN expiry_notified_at;
@@ -0,0 +1,3 @@
+-- When the owner was last warned that an API key is about to expire, so the
+-- expiry job warns once per key
+ALTER TABLE api_keys ADD COLUMN expiry_notified_at TIMESTAMP;
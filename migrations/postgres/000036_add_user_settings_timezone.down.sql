@@ -0,0 +1,2 @@
+-- Remove the user time zone setting
+ALTER TABLE user_settings DROP COLUMN timezone;
@@ -0,0 +1,2 @@
+-- IANA time zone the user's uptime history and notification times are shown in
+ALTER TABLE user_settings ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
@@ -0,0 +1,16 @@
+-- Tags group monitors for fleet management. Names are unique per user.
+CREATE TABLE tags (
+    id         SERIAL PRIMARY KEY,
+    user_id    INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name       TEXT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE monitor_tags (
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    tag_id     INTEGER NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+    PRIMARY KEY (monitor_id, tag_id)
+);
+
+CREATE INDEX idx_monitor_tags_tag_id ON monitor_tags(tag_id);
@@ -0,0 +1,2 @@
+-- Remove the per-monitor heartbeat retention
+ALTER TABLE monitors DROP COLUMN retention_days;
@@ -0,0 +1,3 @@
+-- Days to keep a monitor's non-important heartbeats, overriding its owner's
+-- heartbeat_retention_days. 0 uses the owner's setting.
+ALTER TABLE monitors ADD COLUMN retention_days INTEGER NOT NULL DEFAULT 0;
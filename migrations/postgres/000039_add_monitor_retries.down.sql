@@ -0,0 +1,3 @@
+-- Remove the monitor retries
+ALTER TABLE monitors DROP COLUMN retry_interval;
+ALTER TABLE monitors DROP COLUMN retries;
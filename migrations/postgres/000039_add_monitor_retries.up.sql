@@ -0,0 +1,5 @@
+-- Re-checks of a failed check before it is stored as down, and the seconds
+-- between them. 0 retries records failures right away; a retry_interval of 0
+-- waits 5 seconds.
+ALTER TABLE monitors ADD COLUMN retries INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE monitors ADD COLUMN retry_interval INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Remove maintenance windows
+DROP TABLE IF EXISTS maintenance_windows;
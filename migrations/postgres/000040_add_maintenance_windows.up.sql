@@ -0,0 +1,15 @@
+-- Planned maintenance of a monitor: checks within a window are recorded as
+-- maintenance and send no alerts. Weekly windows repeat every seven days
+-- from starts_at.
+CREATE TABLE maintenance_windows (
+    id         SERIAL PRIMARY KEY,
+    monitor_id INTEGER NOT NULL REFERENCES monitors(id) ON DELETE CASCADE,
+    title      TEXT NOT NULL,
+    starts_at  TIMESTAMPTZ NOT NULL,
+    ends_at    TIMESTAMPTZ NOT NULL,
+    weekly     BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CHECK (ends_at > starts_at)
+);
+
+CREATE INDEX idx_maintenance_windows_monitor_id ON maintenance_windows(monitor_id);
@@ -0,0 +1,3 @@
+-- Time zone weekly maintenance windows repeat in, so they keep their wall
+-- clock time across daylight saving time changes
+ALTER TABLE maintenance_windows ADD COLUMN timezone VARCHAR(64) NOT NULL DEFAULT 'UTC';
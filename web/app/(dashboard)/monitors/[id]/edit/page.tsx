@@ -105,10 +105,12 @@ export default function EditMonitorPage() {
               timeout: monitor.timeout,
               resend_interval: monitor.resend_interval,
               ip_version: monitor.ip_version,
+              escalation_policy_id: monitor.escalation_policy_id,
               config: monitor.config,
             }}
             monitorId={monitorId}
             notificationsConfigured={monitor.notifications_configured}
+            pushToken={monitor.push_token}
             onSubmit={(data) => updateMutation.mutate({
               ...data.monitor,
               active: monitor.active,
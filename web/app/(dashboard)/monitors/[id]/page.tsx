@@ -13,6 +13,7 @@ import { Card, CardContent } from '@/components/ui/card';
 import { Badge } from '@/components/ui/badge';
 import { Button } from '@/components/ui/button';
 import { Skeleton } from '@/components/ui/skeleton';
+import { Alert, AlertDescription } from '@/components/ui/alert';
 import {
   Table,
   TableHeader,
@@ -125,6 +126,7 @@ export default function MonitorDetailPage() {
       interval: monitor!.interval,
       timeout: monitor!.timeout,
       active: !monitor!.active,
+      public: monitor!.public,
       config: monitor!.config,
     }),
     onSuccess: () => {
@@ -277,6 +279,15 @@ export default function MonitorDetailPage() {
         </div>
       </div>
 
+      {monitor.unsupported_type && (
+        <Alert variant="destructive" className="mt-6">
+          <AlertDescription>
+            The &quot;{monitor.type}&quot; monitor type isn&apos;t available on this server, so this monitor is not being checked.
+            Enable the feature it needs (for example Chrome for page change monitors) or change its type.
+          </AlertDescription>
+        </Alert>
+      )}
+
       {/* Stats */}
       <div className="mt-8 grid grid-cols-1 gap-5 sm:grid-cols-3">
         <Card className="transition-all hover:shadow-xl hover:scale-105">
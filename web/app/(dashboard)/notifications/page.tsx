@@ -30,6 +30,7 @@ export default function NotificationsPage() {
   const [showForm, setShowForm] = useState(false);
   const [editingNotification, setEditingNotification] = useState<Notification | null>(null);
   const [deleteId, setDeleteId] = useState<number | null>(null);
+  const [testingAll, setTestingAll] = useState(false);
 
   useEffect(() => {
     loadData();
@@ -87,6 +88,29 @@ export default function NotificationsPage() {
     }
   }
 
+  async function handleTestAll() {
+    try {
+      setTestingAll(true);
+      const report = await apiClient.testAllNotifications();
+      if (report.failed === 0) {
+        toast.success(`Test sent through all ${report.succeeded} active notifications`);
+      } else {
+        const failures = report.results
+          .filter((r) => !r.success)
+          .map((r) => `${r.name}: ${r.error}`)
+          .join('\n');
+        toast.error(`${report.failed} of ${report.results.length} test notifications failed`, {
+          description: failures,
+        });
+      }
+    } catch (err: any) {
+      console.error('Failed to test notifications:', err);
+      toast.error('Failed to test notifications: ' + (err.message || 'Unknown error'));
+    } finally {
+      setTestingAll(false);
+    }
+  }
+
   function handleEdit(notification: Notification) {
     setEditingNotification(notification);
     setShowForm(true);
@@ -144,9 +168,16 @@ export default function NotificationsPage() {
             Manage notification channels for monitor alerts
           </p>
         </div>
-        <Button onClick={handleCreate}>
-          Add Notification
-        </Button>
+        <div className="flex gap-2">
+          {notifications && notifications.some((n) => n.active) && (
+            <Button variant="outline" onClick={handleTestAll} disabled={testingAll}>
+              {testingAll ? 'Testing...' : 'Test All'}
+            </Button>
+          )}
+          <Button onClick={handleCreate}>
+            Add Notification
+          </Button>
+        </div>
       </div>
 
       {showForm && (
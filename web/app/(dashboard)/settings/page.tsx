@@ -11,6 +11,7 @@ import {
 } from '@/components/ui/card';
 import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
+import { Switch } from '@/components/ui/switch';
 import { Button } from '@/components/ui/button';
 import { Badge } from '@/components/ui/badge';
 import { Separator } from '@/components/ui/separator';
@@ -24,6 +25,9 @@ export default function SettingsPage() {
   const [loading, setLoading] = useState(true);
   const [retentionSettings, setRetentionSettings] = useState<UserSettings | null>(null);
   const [savingRetention, setSavingRetention] = useState(false);
+  const [savingAutoAttach, setSavingAutoAttach] = useState(false);
+  const [timezone, setTimezone] = useState('UTC');
+  const [savingTimezone, setSavingTimezone] = useState(false);
   const [currentPassword, setCurrentPassword] = useState('');
   const [newPassword, setNewPassword] = useState('');
   const [confirmPassword, setConfirmPassword] = useState('');
@@ -40,6 +44,7 @@ export default function SettingsPage() {
         setUser(currentUser);
         setOauthConfig(config);
         setRetentionSettings(settings);
+        setTimezone(settings.timezone || 'UTC');
       } catch (err) {
         console.error('Failed to load settings data:', err);
       } finally {
@@ -72,6 +77,52 @@ export default function SettingsPage() {
     }
   };
 
+  const handleToggleAutoAttach = async (checked: boolean) => {
+    if (!retentionSettings) return;
+
+    setSavingAutoAttach(true);
+
+    try {
+      const updated = await apiClient.updateUserSettings({
+        heartbeat_retention_days: retentionSettings.heartbeat_retention_days,
+        hourly_stat_retention_days: retentionSettings.hourly_stat_retention_days,
+        daily_stat_retention_days: retentionSettings.daily_stat_retention_days,
+        auto_attach_default_notifications: checked,
+      });
+      setRetentionSettings(updated);
+      toast.success('Notification settings saved successfully!');
+    } catch (err: unknown) {
+      const message = err instanceof Error ? err.message : 'Failed to save settings';
+      toast.error(message);
+    } finally {
+      setSavingAutoAttach(false);
+    }
+  };
+
+  const handleSaveTimezone = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (!retentionSettings) return;
+
+    setSavingTimezone(true);
+
+    try {
+      const updated = await apiClient.updateUserSettings({
+        heartbeat_retention_days: retentionSettings.heartbeat_retention_days,
+        hourly_stat_retention_days: retentionSettings.hourly_stat_retention_days,
+        daily_stat_retention_days: retentionSettings.daily_stat_retention_days,
+        timezone: timezone.trim(),
+      });
+      setRetentionSettings(updated);
+      setTimezone(updated.timezone);
+      toast.success('Time zone saved successfully!');
+    } catch (err: unknown) {
+      const message = err instanceof Error ? err.message : 'Failed to save settings';
+      toast.error(message);
+    } finally {
+      setSavingTimezone(false);
+    }
+  };
+
   const handleChangePassword = async (e: React.FormEvent) => {
     e.preventDefault();
     if (newPassword !== confirmPassword) {
@@ -246,6 +297,64 @@ export default function SettingsPage() {
 
         <Separator />
 
+        <Card>
+          <CardHeader>
+            <CardTitle>Notification Defaults</CardTitle>
+            <CardDescription>
+              Choose which notifications new monitors start with.
+            </CardDescription>
+          </CardHeader>
+          <CardContent>
+            {retentionSettings && (
+              <div className="flex items-center gap-2">
+                <Switch
+                  id="auto-attach-defaults"
+                  checked={retentionSettings.auto_attach_default_notifications}
+                  onCheckedChange={handleToggleAutoAttach}
+                  disabled={savingAutoAttach}
+                />
+                <Label htmlFor="auto-attach-defaults" className="cursor-pointer">
+                  Attach default notifications to new monitors
+                </Label>
+              </div>
+            )}
+          </CardContent>
+        </Card>
+
+        <Card>
+          <CardHeader>
+            <CardTitle>Time Zone</CardTitle>
+            <CardDescription>
+              Daily, hourly and weekly uptime history and notification times use this time zone.
+            </CardDescription>
+          </CardHeader>
+          <CardContent>
+            {retentionSettings && (
+              <form onSubmit={handleSaveTimezone} className="space-y-4 max-w-sm">
+                <div className="space-y-2">
+                  <Label htmlFor="timezone">IANA Time Zone</Label>
+                  <Input
+                    id="timezone"
+                    value={timezone}
+                    onChange={(e) => setTimezone(e.target.value)}
+                    placeholder="Europe/Rome"
+                  />
+                </div>
+                <Button type="submit" disabled={savingTimezone}>
+                  {savingTimezone ? (
+                    <>
+                      <Loader2 className="mr-2 h-4 w-4 animate-spin" />
+                      Saving...
+                    </>
+                  ) : (
+                    'Save Time Zone'
+                  )}
+                </Button>
+              </form>
+            )}
+          </CardContent>
+        </Card>
+
         <Card>
           <CardHeader>
             <CardTitle>Data Retention Settings</CardTitle>
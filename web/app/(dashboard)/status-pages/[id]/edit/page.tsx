@@ -20,10 +20,15 @@ export default function EditStatusPagePage() {
 
   const [monitors, setMonitors] = useState<Monitor[]>([]);
   const [slug, setSlug] = useState('');
+  const [customDomain, setCustomDomain] = useState('');
   const [title, setTitle] = useState('');
   const [description, setDescription] = useState('');
+  const [banner, setBanner] = useState('');
+  const [bannerStyle, setBannerStyle] = useState('info');
   const [published, setPublished] = useState(false);
   const [showPoweredBy, setShowPoweredBy] = useState(true);
+  const [showUptimePercentage, setShowUptimePercentage] = useState(true);
+  const [confirmationChecks, setConfirmationChecks] = useState(1);
   const [theme, setTheme] = useState('light');
   const [customCss, setCustomCss] = useState('');
   const [password, setPassword] = useState('');
@@ -45,10 +50,15 @@ export default function EditStatusPagePage() {
       ]);
 
       setSlug(statusPageData.slug);
+      setCustomDomain(statusPageData.custom_domain || '');
       setTitle(statusPageData.title);
       setDescription(statusPageData.description || '');
       setPublished(statusPageData.published);
       setShowPoweredBy(statusPageData.show_powered_by);
+      setShowUptimePercentage(statusPageData.show_uptime_percentage);
+      setConfirmationChecks(statusPageData.confirmation_checks || 1);
+      setBanner(statusPageData.banner || '');
+      setBannerStyle(statusPageData.banner_style || 'info');
       setTheme(statusPageData.theme || 'light');
       setCustomCss(statusPageData.custom_css || '');
       setSelectedMonitorIds(statusPageData.monitors?.map(m => m.id) || []);
@@ -81,10 +91,15 @@ export default function EditStatusPagePage() {
     try {
       const data = {
         slug,
+        custom_domain: customDomain,
         title,
         description,
         published,
         show_powered_by: showPoweredBy,
+        show_uptime_percentage: showUptimePercentage,
+        confirmation_checks: confirmationChecks,
+        banner,
+        banner_style: bannerStyle,
         theme,
         custom_css: customCss,
         password: password || undefined,
@@ -161,6 +176,21 @@ export default function EditStatusPagePage() {
               </p>
             </div>
 
+            <div className="space-y-2">
+              <Label htmlFor="custom-domain">Custom Domain (optional)</Label>
+              <Input
+                id="custom-domain"
+                type="text"
+                value={customDomain}
+                onChange={(e) => setCustomDomain(e.target.value)}
+                className="font-mono"
+                placeholder="status.example.com"
+              />
+              <p className="text-xs text-muted-foreground">
+                Point the domain at this server to serve the page there. Clear it to remove the domain
+              </p>
+            </div>
+
             <div className="space-y-2">
               <Label htmlFor="description">Description</Label>
               <Textarea
@@ -171,6 +201,32 @@ export default function EditStatusPagePage() {
               />
             </div>
 
+            <div className="space-y-2">
+              <Label htmlFor="banner">Announcement Banner (optional)</Label>
+              <Textarea
+                id="banner"
+                value={banner}
+                onChange={(e) => setBanner(e.target.value)}
+                rows={2}
+                maxLength={500}
+                placeholder="Migration in progress this week"
+              />
+              <select
+                aria-label="Banner style"
+                value={bannerStyle}
+                onChange={(e) => setBannerStyle(e.target.value)}
+                className="flex h-8 w-full rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 md:text-sm dark:bg-input/30"
+              >
+                <option value="info">Info</option>
+                <option value="warning">Warning</option>
+                <option value="danger">Danger</option>
+                <option value="success">Success</option>
+              </select>
+              <p className="text-xs text-muted-foreground">
+                Shown at the top of the page until cleared, separate from incidents
+              </p>
+            </div>
+
             <Separator />
 
             <div className="space-y-2">
@@ -188,6 +244,9 @@ export default function EditStatusPagePage() {
                       <Label className="cursor-pointer font-normal">
                         {monitor.name}
                         <span className="ml-2 text-xs text-muted-foreground">({monitor.type})</span>
+                        {!monitor.public && (
+                          <span className="ml-2 text-xs text-muted-foreground">· private, not shown</span>
+                        )}
                       </Label>
                     </div>
                   ))
@@ -210,6 +269,21 @@ export default function EditStatusPagePage() {
               </select>
             </div>
 
+            <div className="space-y-2">
+              <Label htmlFor="confirmation-checks">Confirmation Checks</Label>
+              <Input
+                id="confirmation-checks"
+                type="number"
+                min={1}
+                max={20}
+                value={confirmationChecks}
+                onChange={(e) => setConfirmationChecks(parseInt(e.target.value) || 1)}
+              />
+              <p className="text-xs text-muted-foreground">
+                Consecutive down checks before the page shows a monitor as down
+              </p>
+            </div>
+
             <div className="space-y-2">
               <Label htmlFor="password">Password Protection (optional)</Label>
               <Input
@@ -254,6 +328,14 @@ export default function EditStatusPagePage() {
                 />
                 <Label className="cursor-pointer font-normal">Show &quot;Powered by Uptime Kabomba&quot; footer</Label>
               </div>
+
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  checked={showUptimePercentage}
+                  onCheckedChange={(checked) => setShowUptimePercentage(checked as boolean)}
+                />
+                <Label className="cursor-pointer font-normal">Show uptime percentages</Label>
+              </div>
             </div>
 
             <Separator />
@@ -18,8 +18,11 @@ export default function NewStatusPagePage() {
   const [slug, setSlug] = useState('');
   const [title, setTitle] = useState('');
   const [description, setDescription] = useState('');
+  const [banner, setBanner] = useState('');
+  const [bannerStyle, setBannerStyle] = useState('info');
   const [published, setPublished] = useState(false);
   const [showPoweredBy, setShowPoweredBy] = useState(true);
+  const [showUptimePercentage, setShowUptimePercentage] = useState(true);
   const [theme, setTheme] = useState('light');
   const [customCss, setCustomCss] = useState('');
   const [password, setPassword] = useState('');
@@ -74,6 +77,9 @@ export default function NewStatusPagePage() {
         description,
         published,
         show_powered_by: showPoweredBy,
+        show_uptime_percentage: showUptimePercentage,
+        banner,
+        banner_style: bannerStyle,
         theme,
         custom_css: customCss,
         password: password || undefined,
@@ -148,6 +154,32 @@ export default function NewStatusPagePage() {
               />
             </div>
 
+            <div className="space-y-2">
+              <Label htmlFor="banner">Announcement Banner (optional)</Label>
+              <Textarea
+                id="banner"
+                value={banner}
+                onChange={(e) => setBanner(e.target.value)}
+                rows={2}
+                maxLength={500}
+                placeholder="Migration in progress this week"
+              />
+              <select
+                aria-label="Banner style"
+                value={bannerStyle}
+                onChange={(e) => setBannerStyle(e.target.value)}
+                className="flex h-8 w-full rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 md:text-sm dark:bg-input/30"
+              >
+                <option value="info">Info</option>
+                <option value="warning">Warning</option>
+                <option value="danger">Danger</option>
+                <option value="success">Success</option>
+              </select>
+              <p className="text-xs text-muted-foreground">
+                Shown at the top of the page until cleared, separate from incidents
+              </p>
+            </div>
+
             <Separator />
 
             <div className="space-y-2">
@@ -165,6 +197,9 @@ export default function NewStatusPagePage() {
                       <Label className="cursor-pointer font-normal">
                         {monitor.name}
                         <span className="ml-2 text-xs text-muted-foreground">({monitor.type})</span>
+                        {!monitor.public && (
+                          <span className="ml-2 text-xs text-muted-foreground">· private, not shown</span>
+                        )}
                       </Label>
                     </div>
                   ))
@@ -231,6 +266,14 @@ export default function NewStatusPagePage() {
                 />
                 <Label className="cursor-pointer font-normal">Show &quot;Powered by Uptime Kabomba&quot; footer</Label>
               </div>
+
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  checked={showUptimePercentage}
+                  onCheckedChange={(checked) => setShowUptimePercentage(checked as boolean)}
+                />
+                <Label className="cursor-pointer font-normal">Show uptime percentages</Label>
+              </div>
             </div>
 
             <Separator />
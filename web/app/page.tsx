@@ -13,6 +13,13 @@ export default function Home() {
   useEffect(() => {
     const checkSetupStatus = async () => {
       try {
+        // On a status page's custom domain, show that page instead of the app
+        const statusPageSlug = await apiClient.resolveStatusPageDomain();
+        if (statusPageSlug) {
+          router.replace(`/status/${statusPageSlug}`);
+          return;
+        }
+
         const status = await apiClient.getSetupStatus();
 
         if (!status.setupComplete) {
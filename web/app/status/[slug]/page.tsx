@@ -2,7 +2,7 @@
 
 import { useState, useEffect } from 'react';
 import { useParams } from 'next/navigation';
-import { apiClient, MonitorWithStatus, PublicStatusPage, Heartbeat } from '@/lib/api';
+import { apiClient, PublicMonitorStatus, PublicStatusPage, Heartbeat, PublicIncident } from '@/lib/api';
 import { ThemeToggle } from '@/components/ThemeToggle';
 import HeartbeatChart from '@/components/monitors/HeartbeatChart';
 import { Card, CardContent } from '@/components/ui/card';
@@ -17,6 +17,9 @@ import {
   DialogTitle,
 } from '@/components/ui/dialog';
 
+// Incidents fetched per page of the incident history
+const INCIDENT_HISTORY_PAGE_SIZE = 20;
+
 export default function PublicStatusPageComponent() {
   const params = useParams();
   const slug = params.slug as string;
@@ -25,25 +28,34 @@ export default function PublicStatusPageComponent() {
   const [loading, setLoading] = useState(true);
   const [error, setError] = useState<string | null>(null);
   const [password, setPassword] = useState('');
+  const [accessToken, setAccessToken] = useState<string | undefined>();
   const [showPasswordPrompt, setShowPasswordPrompt] = useState(false);
-  const [selectedMonitor, setSelectedMonitor] = useState<MonitorWithStatus | null>(null);
+  const [selectedMonitor, setSelectedMonitor] = useState<PublicMonitorStatus | null>(null);
   const [chartHeartbeats, setChartHeartbeats] = useState<Record<number, Heartbeat[]>>({});
   const [loadingChart, setLoadingChart] = useState(false);
+  const [history, setHistory] = useState<PublicIncident[] | null>(null);
+  const [historyHasMore, setHistoryHasMore] = useState(false);
+  const [loadingHistory, setLoadingHistory] = useState(false);
+
+  const accessTokenKey = `status-page-token:${slug}`;
 
   useEffect(() => {
-    loadStatusPage();
+    loadStatusPage(sessionStorage.getItem(accessTokenKey) || undefined);
   }, [slug]);
 
-  async function loadStatusPage(pwd?: string) {
+  async function loadStatusPage(token?: string) {
     try {
       setLoading(true);
       setError(null);
-      const statusPage = await apiClient.getPublicStatusPage(slug, pwd);
+      const statusPage = await apiClient.getPublicStatusPage(slug, token);
       setData(statusPage);
+      setAccessToken(token);
       setShowPasswordPrompt(false);
     } catch (err: any) {
       console.error('Failed to load status page:', err);
       if (err.status === 401) {
+        // The stored token expired or the password changed
+        sessionStorage.removeItem(accessTokenKey);
         setShowPasswordPrompt(true);
         setError('This status page is password protected');
       } else {
@@ -54,9 +66,45 @@ export default function PublicStatusPageComponent() {
     }
   }
 
-  function handlePasswordSubmit(e: React.FormEvent) {
+  async function handlePasswordSubmit(e: React.FormEvent) {
     e.preventDefault();
-    loadStatusPage(password);
+    try {
+      const access = await apiClient.authenticateStatusPage(slug, password);
+      if (access?.token) {
+        sessionStorage.setItem(accessTokenKey, access.token);
+      }
+      setPassword('');
+      loadStatusPage(access?.token);
+    } catch (err: any) {
+      setError(err.status === 401 ? 'Incorrect password' : err.message || 'Failed to check password');
+    }
+  }
+
+  async function loadIncidentHistory() {
+    const offset = history?.length ?? 0;
+    try {
+      setLoadingHistory(true);
+      const page = await apiClient.getPublicIncidentHistory(
+        slug,
+        { limit: INCIDENT_HISTORY_PAGE_SIZE, offset },
+        accessToken
+      );
+      setHistory([...(history ?? []), ...page]);
+      setHistoryHasMore(page.length === INCIDENT_HISTORY_PAGE_SIZE);
+    } catch (err) {
+      console.error('Failed to load incident history:', err);
+    } finally {
+      setLoadingHistory(false);
+    }
+  }
+
+  function formatResolution(seconds: number) {
+    if (seconds < 60) return `${seconds}s`;
+    const minutes = Math.round(seconds / 60);
+    if (minutes < 60) return `${minutes}m`;
+    const hours = Math.floor(minutes / 60);
+    if (hours < 24) return `${hours}h ${minutes % 60}m`;
+    return `${Math.floor(hours / 24)}d ${hours % 24}h`;
   }
 
   function getStatusColor(status: number) {
@@ -80,11 +128,8 @@ export default function PublicStatusPageComponent() {
   function getOverallStatus() {
     if (!data?.monitors.length) return { text: 'No monitors', color: 'text-gray-600' };
 
-    const hasDown = data.monitors.some(m => m.last_heartbeat?.status === 0);
-    const hasMaintenance = data.monitors.some(m => m.last_heartbeat?.status === 3);
-
-    if (hasDown) return { text: 'Partial Outage', color: 'text-red-600' };
-    if (hasMaintenance) return { text: 'Under Maintenance', color: 'text-yellow-600' };
+    if (data.status === 0) return { text: 'Partial Outage', color: 'text-red-600' };
+    if (data.status === 3) return { text: 'Under Maintenance', color: 'text-yellow-600' };
     return { text: 'All Systems Operational', color: 'text-green-600' };
   }
 
@@ -114,11 +159,11 @@ export default function PublicStatusPageComponent() {
     return `${startDate.toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' })} - ${endDate.toLocaleTimeString([], { hour: '2-digit', minute: '2-digit' })}`;
   }
 
-  function handleMonitorClick(monitor: MonitorWithStatus) {
+  function handleMonitorClick(monitor: PublicMonitorStatus) {
     setSelectedMonitor(monitor);
     if (!chartHeartbeats[monitor.id]) {
       setLoadingChart(true);
-      apiClient.getPublicStatusPageHeartbeats(slug, monitor.id, { period: '1h' })
+      apiClient.getPublicStatusPageHeartbeats(slug, monitor.id, { period: '1h', token: accessToken })
         .then((heartbeats) => {
           setChartHeartbeats((prev) => ({ ...prev, [monitor.id]: heartbeats }));
         })
@@ -219,12 +264,22 @@ export default function PublicStatusPageComponent() {
           )}
         </div>
 
+        {data?.page.banner && (
+          <div
+            role="status"
+            className={`mb-8 border rounded-lg p-4 text-sm whitespace-pre-wrap ${getIncidentStyle(data.page.banner_style)}`}
+          >
+            {data.page.banner}
+          </div>
+        )}
+
         {data?.incidents && data.incidents.length > 0 && (
           <div className="mb-8 space-y-4">
             <h2 className="text-xl font-semibold mb-4">Incidents</h2>
             {data.incidents.map((incident) => (
               <div
                 key={incident.id}
+                id={`incident-${incident.id}`}
                 className={`border rounded-lg p-4 ${getIncidentStyle(incident.style)}`}
               >
                 <div className="flex items-start justify-between">
@@ -302,8 +357,19 @@ export default function PublicStatusPageComponent() {
                           {monitor.last_heartbeat.ping}ms
                         </div>
                       )}
+                      {monitor.uptime_percentage !== undefined && (
+                        <div className={`text-sm ${mutedTextClass}`}>
+                          {monitor.uptime_percentage.toFixed(2)}% uptime (24h)
+                        </div>
+                      )}
                     </div>
                   </div>
+                  {monitor.maintenance && (
+                    <div role="status" className={`mt-3 border rounded-md p-2 text-sm ${getIncidentStyle('info')}`}>
+                      Scheduled maintenance: {monitor.maintenance.title}, until{' '}
+                      {new Date(monitor.maintenance.ends_at).toLocaleString()}
+                    </div>
+                  )}
                   {monitor.last_heartbeat?.message && status === 0 && (
                     <div className="mt-2 text-sm text-red-600">
                       {monitor.last_heartbeat.message}
@@ -345,6 +411,44 @@ export default function PublicStatusPageComponent() {
           )}
         </div>
 
+        {history === null ? (
+          <div className="mt-8">
+            <Button variant="outline" size="sm" onClick={loadIncidentHistory} disabled={loadingHistory}>
+              {loadingHistory ? 'Loading...' : 'Show incident history'}
+            </Button>
+          </div>
+        ) : (
+          <div className="mt-8 space-y-3">
+            <h2 className="text-xl font-semibold mb-4">Incident History</h2>
+            {history.length === 0 && (
+              <p className={`text-sm ${mutedTextClass}`}>No incidents</p>
+            )}
+            {history.map((incident) => (
+              <div
+                key={incident.id}
+                className={`border rounded-lg p-4 ${getIncidentStyle(incident.style)}`}
+              >
+                <h3 className="font-semibold">{incident.title}</h3>
+                {incident.content && (
+                  <p className="mt-2 text-sm whitespace-pre-wrap">{incident.content}</p>
+                )}
+                <p className="mt-2 text-xs opacity-75">
+                  {new Date(incident.created_at).toLocaleString()}
+                  {' · '}
+                  {incident.resolution_seconds !== null
+                    ? `Resolved in ${formatResolution(incident.resolution_seconds)}`
+                    : 'Ongoing'}
+                </p>
+              </div>
+            ))}
+            {historyHasMore && (
+              <Button variant="outline" size="sm" onClick={loadIncidentHistory} disabled={loadingHistory}>
+                {loadingHistory ? 'Loading...' : 'Load older incidents'}
+              </Button>
+            )}
+          </div>
+        )}
+
         {data?.page.show_powered_by && (
           <div className={`mt-12 text-center text-sm ${mutedTextClass}`}>
             Powered by <a href="https://github.com/fuomag9/uptime-kabomba" className="underline hover:no-underline">Uptime Kabomba</a>
@@ -354,6 +458,15 @@ export default function PublicStatusPageComponent() {
         {data && (
           <div className={`mt-4 text-center text-xs ${mutedTextClass}`}>
             Last updated: {new Date().toLocaleString()}
+            {/* Feed readers can't send the password of a protected page */}
+            {!accessToken && (
+              <>
+                {' · '}
+                <a href={`/api/status/${slug}/incidents.rss`} className="underline hover:no-underline">
+                  Incidents RSS
+                </a>
+              </>
+            )}
           </div>
         )}
       </div>
@@ -71,6 +71,25 @@ export default function MonitorCard({ monitor, latestPing, uptime }: MonitorCard
                     Paused
                   </span>
                 )}
+                {monitor.unsupported_type && (
+                  <Badge variant="destructive">Unsupported type</Badge>
+                )}
+                {monitor.active && monitor.is_stale && !heartbeat && (
+                  <Badge
+                    variant="outline"
+                    title={`Last checked ${monitor.seconds_since_last_check}s ago`}
+                  >
+                    Stale
+                  </Badge>
+                )}
+                {monitor.flapping && (
+                  <Badge
+                    variant="outline"
+                    title="Changing between up and down more often than its flap threshold"
+                  >
+                    Flapping
+                  </Badge>
+                )}
               </div>
               <p className="mt-1 text-sm text-gray-500 dark:text-gray-400 truncate">
                 {monitor.type.toUpperCase()} &bull; {monitor.url}
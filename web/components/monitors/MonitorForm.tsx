@@ -1,7 +1,7 @@
 "use client";
 
 import { useState, useEffect } from 'react';
-import { CreateMonitorRequest, Notification, Certificate, apiClient } from '@/lib/api';
+import { CreateMonitorRequest, Notification, Certificate, EscalationPolicy, apiClient } from '@/lib/api';
 import { Input } from '@/components/ui/input';
 import { Label } from '@/components/ui/label';
 import { Textarea } from '@/components/ui/textarea';
@@ -20,6 +20,7 @@ interface MonitorFormProps {
   initialData?: Partial<CreateMonitorRequest>;
   monitorId?: number;
   notificationsConfigured?: boolean; // true if monitor has explicit config, false if using defaults
+  pushToken?: string; // push monitor's URL token, once the server generated it
   onSubmit: (data: MonitorFormData) => void;
   onCancel?: () => void;
   isSubmitting?: boolean;
@@ -27,11 +28,15 @@ interface MonitorFormProps {
 
 const MONITOR_TYPES = [
   { value: 'http', label: 'HTTP(s)', urlLabel: 'URL', urlPlaceholder: 'https://example.com' },
+  { value: 'flow', label: 'Flow (multi-step HTTP)', urlLabel: 'URL', urlPlaceholder: '' },
   { value: 'tcp', label: 'TCP Port', urlLabel: 'Host', urlPlaceholder: 'example.com or 192.168.1.1' },
   { value: 'ping', label: 'Ping (ICMP)', urlLabel: 'Host', urlPlaceholder: 'example.com or 192.168.1.1' },
   { value: 'dns', label: 'DNS', urlLabel: 'Hostname', urlPlaceholder: 'example.com' },
+  { value: 'push', label: 'Push', urlLabel: 'URL', urlPlaceholder: '' },
+  { value: 'cert', label: 'TLS Certificate', urlLabel: 'Host', urlPlaceholder: 'example.com or example.com:8443' },
   { value: 'docker', label: 'Docker Container', urlLabel: 'Container Name/ID', urlPlaceholder: 'my-container' },
   { value: 'page_change', label: 'Page Change', urlLabel: 'URL', urlPlaceholder: 'https://example.com' },
+  { value: 'script', label: 'Script (admin only)', urlLabel: 'Command', urlPlaceholder: '/usr/local/bin/check-backup' },
 ];
 
 const HTTP_METHODS = ['GET', 'POST', 'PUT', 'PATCH', 'DELETE', 'HEAD', 'OPTIONS'];
@@ -54,7 +59,7 @@ function normalizeAcceptedStatusCodes(value: unknown): string {
   return '200-299';
 }
 
-export default function MonitorForm({ initialData, monitorId, notificationsConfigured, onSubmit, onCancel, isSubmitting }: MonitorFormProps) {
+export default function MonitorForm({ initialData, monitorId, notificationsConfigured, pushToken, onSubmit, onCancel, isSubmitting }: MonitorFormProps) {
   const [formData, setFormData] = useState<CreateMonitorRequest>({
     name: initialData?.name || '',
     type: initialData?.type || 'http',
@@ -62,14 +67,25 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
     interval: initialData?.interval || 60,
     timeout: initialData?.timeout || 30,
     resend_interval: initialData?.resend_interval || 1,
+    retries: initialData?.retries || 0,
+    retry_interval: initialData?.retry_interval || 0,
     ip_version: initialData?.ip_version || 'auto',
+    public: initialData?.public ?? false,
+    escalation_policy_id: initialData?.escalation_policy_id ?? null,
     config: initialData?.config || {},
   });
 
+  const [notifyRecovery, setNotifyRecovery] = useState<boolean>(initialData?.config?.notify_recovery !== false);
+  const [resultWebhookUrl, setResultWebhookUrl] = useState<string>((initialData?.config?.result_webhook_url as string) || '');
+  const [quorum, setQuorum] = useState<number>((initialData?.config?.quorum as number) || 1);
+  const [resendSeconds, setResendSeconds] = useState<number>((initialData?.config?.resend_interval_seconds as number) || 0);
+  const [coalesceInterval, setCoalesceInterval] = useState<number>((initialData?.config?.coalesce_interval as number) || 0);
+  const [autoPauseAfter, setAutoPauseAfter] = useState<number>((initialData?.config?.auto_pause_after as number) || 0);
   const [notifications, setNotifications] = useState<Notification[]>([]);
   const [selectedNotificationIds, setSelectedNotificationIds] = useState<number[]>([]);
   const [loadingNotifications, setLoadingNotifications] = useState(true);
   const [certificates, setCertificates] = useState<Certificate[]>([]);
+  const [escalationPolicies, setEscalationPolicies] = useState<EscalationPolicy[]>([]);
   // Default to using defaults for new monitors, for existing monitors check the flag
   const [useDefaultNotifications, setUseDefaultNotifications] = useState<boolean>(
     monitorId ? notificationsConfigured === false : true
@@ -82,14 +98,49 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
     acceptedStatusCodes: normalizeAcceptedStatusCodes(initialData?.config?.accepted_status_codes),
     keyword: (initialData?.config?.keyword as string) || '',
     invertKeyword: (initialData?.config?.invert_keyword as boolean) || false,
+    keywords: Array.isArray(initialData?.config?.keywords) ? (initialData.config.keywords as string[]).join('\n') : '',
+    keywordMatch: (initialData?.config?.keyword_match as string) || 'all',
     ignoreTLS: (initialData?.config?.ignore_tls as boolean) || false,
+    http3: (initialData?.config?.http3 as boolean) || false,
+    caCert: (initialData?.config?.ca_cert as string) || '',
+    tlsServerName: (initialData?.config?.tls_server_name as string) || '',
+    expectedAlpn: (initialData?.config?.expected_alpn as string) || '',
+    authType: (initialData?.config?.auth_type as string) || '',
+    authDomain: (initialData?.config?.auth_domain as string) || '',
+    authUsername: (initialData?.config?.auth_username as string) || '',
+    authPassword: (initialData?.config?.auth_password as string) || '',
     maxRedirects: (initialData?.config?.max_redirects as number) || 10,
+    expectedRedirect: (initialData?.config?.expected_redirect as string) || '',
+    expectedRedirectMatch: (initialData?.config?.expected_redirect_match as string) || 'equals',
+    upHeader: (initialData?.config?.up_header as string) || '',
+    upHeaderValue: (initialData?.config?.up_header_value as string) || '',
     certificateId: (initialData?.config?.certificate_id as number) || 0,
   });
 
   const [headerKey, setHeaderKey] = useState('');
   const [headerValue, setHeaderValue] = useState('');
 
+  // Preflight request fetching a token for the HTTP check
+  const initialPreflight = initialData?.config?.preflight as Record<string, unknown> | undefined;
+  const [preflight, setPreflight] = useState({
+    enabled: !!initialPreflight,
+    url: (initialPreflight?.url as string) || '',
+    method: (initialPreflight?.method as string) || 'POST',
+    headers: (initialPreflight?.headers as Record<string, string>) || { 'Content-Type': 'application/json' },
+    body: (initialPreflight?.body as string) || '',
+    tokenPath: (initialPreflight?.token_path as string) || '',
+  });
+
+  // Stability check flagging a response value that keeps changing
+  const initialStability = initialData?.config?.stability as Record<string, unknown> | undefined;
+  const [stability, setStability] = useState({
+    enabled: !!initialStability,
+    source: (initialStability?.source as string) || 'etag',
+    jsonPath: (initialStability?.json_path as string) || '',
+    maxChanges: (initialStability?.max_changes as number) || 3,
+    window: (initialStability?.window as number) || 3600,
+  });
+
   // TCP config
   const [tcpConfig, setTcpConfig] = useState({
     port: (initialData?.config?.port as number) || 80,
@@ -107,13 +158,39 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
     query_type: (initialData?.config?.query_type as string) || 'A',
     dns_server: (initialData?.config?.dns_server as string) || '',
     expected_result: (initialData?.config?.expected_result as string) || '',
+    query_timeout: (initialData?.config?.query_timeout as number) || 0,
+    expect_nxdomain: initialData?.config?.expect_nxdomain === true,
+  });
+
+  // TLS certificate config
+  const [certConfig, setCertConfig] = useState({
+    days_warning: (initialData?.config?.days_warning as number) ?? 14,
+    verify_chain: initialData?.config?.verify_chain !== false,
+    ca_cert: (initialData?.config?.ca_cert as string) || '',
+    tls_server_name: (initialData?.config?.tls_server_name as string) || '',
+    min_tls_version: (initialData?.config?.min_tls_version as string) || '',
   });
 
+  // Push config; the token is generated by the server
+  const pushUrlToken = (initialData?.type === 'push' && pushToken) || '';
+  const [pushGracePeriod, setPushGracePeriod] = useState<number>((initialData?.config?.grace_period as number) ?? 30);
+
+  // Flow config: the steps are edited as JSON
+  const [flowSteps, setFlowSteps] = useState<string>(
+    Array.isArray(initialData?.config?.steps) ? JSON.stringify(initialData.config.steps, null, 2) : ''
+  );
+  const [flowStepsError, setFlowStepsError] = useState('');
+
   // Docker config
   const [dockerConfig, setDockerConfig] = useState({
     docker_host: (initialData?.config?.docker_host as string) || '',
   });
 
+  // Script config: one argument per line
+  const [scriptArgs, setScriptArgs] = useState<string>(
+    Array.isArray(initialData?.config?.args) ? (initialData.config.args as string[]).join('\n') : ''
+  );
+
   // Page Change config
   const [pageChangeConfig, setPageChangeConfig] = useState({
     change_threshold: ((initialData?.config?.change_threshold as number) ?? 0.1) * 100,
@@ -137,12 +214,14 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
   useEffect(() => {
     async function loadData() {
       try {
-        const [notifs, certs] = await Promise.all([
+        const [notifs, certs, policies] = await Promise.all([
           apiClient.getNotifications(),
           apiClient.getCertificates(),
+          apiClient.getEscalationPolicies(),
         ]);
         setNotifications(notifs);
         setCertificates(certs);
+        setEscalationPolicies(policies);
 
         if (monitorId) {
           // Editing existing monitor - load its linked notifications
@@ -167,6 +246,16 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
 
     const config: Record<string, any> = {};
 
+    if (formData.type === 'flow') {
+      try {
+        config.steps = JSON.parse(flowSteps);
+      } catch {
+        setFlowStepsError('Steps must be valid JSON');
+        return;
+      }
+      setFlowStepsError('');
+    }
+
     if (formData.type === 'http') {
       config.method = httpConfig.method;
       if (Object.keys(httpConfig.headers).length > 0) {
@@ -183,15 +272,65 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
         config.keyword = httpConfig.keyword;
         config.invert_keyword = httpConfig.invertKeyword;
       }
+      const keywords = httpConfig.keywords.split('\n').filter((keyword) => keyword !== '');
+      if (keywords.length > 0) {
+        config.keywords = keywords;
+        config.keyword_match = httpConfig.keywordMatch;
+      }
       if (httpConfig.ignoreTLS) {
         config.ignore_tls = true;
       }
+      if (httpConfig.http3) {
+        config.http3 = true;
+      }
+      if (httpConfig.caCert.trim()) {
+        config.ca_cert = httpConfig.caCert;
+      }
+      if (httpConfig.tlsServerName.trim()) {
+        config.tls_server_name = httpConfig.tlsServerName.trim();
+      }
+      if (httpConfig.expectedAlpn) {
+        config.expected_alpn = httpConfig.expectedAlpn;
+      }
+      if (httpConfig.authType === 'ntlm') {
+        config.auth_type = 'ntlm';
+        config.auth_domain = httpConfig.authDomain;
+        config.auth_username = httpConfig.authUsername;
+        config.auth_password = httpConfig.authPassword;
+      }
       if (httpConfig.maxRedirects !== 10) {
         config.max_redirects = httpConfig.maxRedirects;
       }
+      if (httpConfig.expectedRedirect.trim()) {
+        // The Location is asserted on the redirect itself, so don't follow it
+        config.follow_redirects = false;
+        config.expected_redirect = httpConfig.expectedRedirect.trim();
+        config.expected_redirect_match = httpConfig.expectedRedirectMatch;
+      }
+      if (httpConfig.upHeader.trim()) {
+        config.up_header = httpConfig.upHeader.trim();
+        config.up_header_value = httpConfig.upHeaderValue.trim();
+      }
       if (httpConfig.certificateId) {
         config.certificate_id = httpConfig.certificateId;
       }
+      if (preflight.enabled) {
+        config.preflight = {
+          url: preflight.url,
+          method: preflight.method,
+          headers: preflight.headers,
+          body: preflight.body,
+          token_path: preflight.tokenPath,
+        };
+      }
+      if (stability.enabled) {
+        config.stability = {
+          source: stability.source,
+          ...(stability.source === 'json_path' ? { json_path: stability.jsonPath } : {}),
+          max_changes: stability.maxChanges,
+          window: stability.window,
+        };
+      }
     } else if (formData.type === 'tcp') {
       config.port = tcpConfig.port;
     } else if (formData.type === 'ping') {
@@ -209,13 +348,37 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
       if (dnsConfig.dns_server) {
         config.dns_server = dnsConfig.dns_server;
       }
-      if (dnsConfig.expected_result) {
+      if (dnsConfig.expect_nxdomain) {
+        config.expect_nxdomain = true;
+      } else if (dnsConfig.expected_result) {
         config.expected_result = dnsConfig.expected_result;
       }
+      if (dnsConfig.query_timeout > 0) {
+        config.query_timeout = dnsConfig.query_timeout;
+      }
+    } else if (formData.type === 'cert') {
+      config.days_warning = certConfig.days_warning;
+      config.verify_chain = certConfig.verify_chain;
+      if (certConfig.ca_cert.trim()) {
+        config.ca_cert = certConfig.ca_cert;
+      }
+      if (certConfig.tls_server_name.trim()) {
+        config.tls_server_name = certConfig.tls_server_name.trim();
+      }
+      if (certConfig.min_tls_version) {
+        config.min_tls_version = certConfig.min_tls_version;
+      }
+    } else if (formData.type === 'push') {
+      config.grace_period = pushGracePeriod;
     } else if (formData.type === 'docker') {
       if (dockerConfig.docker_host) {
         config.docker_host = dockerConfig.docker_host;
       }
+    } else if (formData.type === 'script') {
+      const args = scriptArgs.split('\n').filter((arg) => arg !== '');
+      if (args.length > 0) {
+        config.args = args;
+      }
     } else if (formData.type === 'page_change') {
       config.change_threshold = pageChangeConfig.change_threshold / 100;
       config.wait_time = pageChangeConfig.wait_time;
@@ -238,6 +401,25 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
       }
     }
 
+    if (!notifyRecovery) {
+      config.notify_recovery = false;
+    }
+    if (resultWebhookUrl.trim()) {
+      config.result_webhook_url = resultWebhookUrl.trim();
+    }
+    if (quorum > 1) {
+      config.quorum = quorum;
+    }
+    if (resendSeconds > 0) {
+      config.resend_interval_seconds = resendSeconds;
+    }
+    if (coalesceInterval > 0) {
+      config.coalesce_interval = coalesceInterval;
+    }
+    if (autoPauseAfter > 0) {
+      config.auto_pause_after = autoPauseAfter;
+    }
+
     onSubmit({
       monitor: {
         ...formData,
@@ -303,19 +485,21 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
           </select>
         </div>
 
-        <div className="space-y-2">
-          <Label htmlFor="url">
-            {MONITOR_TYPES.find(t => t.value === formData.type)?.urlLabel || 'URL'}
-          </Label>
-          <Input
-            type="text"
-            id="url"
-            value={formData.url}
-            onChange={(e) => setFormData({ ...formData, url: e.target.value })}
-            required
-            placeholder={MONITOR_TYPES.find(t => t.value === formData.type)?.urlPlaceholder || 'Enter URL'}
-          />
-        </div>
+        {formData.type !== 'push' && formData.type !== 'flow' && (
+          <div className="space-y-2">
+            <Label htmlFor="url">
+              {MONITOR_TYPES.find(t => t.value === formData.type)?.urlLabel || 'URL'}
+            </Label>
+            <Input
+              type="text"
+              id="url"
+              value={formData.url}
+              onChange={(e) => setFormData({ ...formData, url: e.target.value })}
+              required
+              placeholder={MONITOR_TYPES.find(t => t.value === formData.type)?.urlPlaceholder || 'Enter URL'}
+            />
+          </div>
+        )}
 
         <div className="grid grid-cols-2 gap-4">
           <div className="space-y-2">
@@ -347,6 +531,43 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
           </div>
         </div>
 
+        {formData.type !== 'push' && (
+          <div className="grid grid-cols-2 gap-4">
+            <div className="space-y-2">
+              <Label htmlFor="retries">
+                Retries
+              </Label>
+              <Input
+                type="number"
+                id="retries"
+                value={formData.retries}
+                onChange={(e) => setFormData({ ...formData, retries: parseInt(e.target.value) || 0 })}
+                min={0}
+                max={10}
+              />
+            </div>
+
+            <div className="space-y-2">
+              <Label htmlFor="retry_interval">
+                Retry Interval (seconds)
+              </Label>
+              <Input
+                type="number"
+                id="retry_interval"
+                value={formData.retry_interval}
+                onChange={(e) => setFormData({ ...formData, retry_interval: parseInt(e.target.value) || 0 })}
+                min={0}
+                placeholder="5"
+              />
+            </div>
+
+            <p className="col-span-2 text-sm text-gray-500 dark:text-gray-400">
+              Re-check a failed check this many times before it is recorded as down, so network blips don&apos;t alert.
+              The monitor shows as pending while retrying. All retries must fit within the check interval.
+            </p>
+          </div>
+        )}
+
         <div className="space-y-2">
           <Label htmlFor="resend_interval">
             Resend Notification After X Consecutive Failures
@@ -366,6 +587,138 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
           </p>
         </div>
 
+        <div className="space-y-2">
+          <Label htmlFor="resend_interval_seconds">
+            Resend Every X Seconds of Downtime
+          </Label>
+          <Input
+            type="number"
+            id="resend_interval_seconds"
+            value={resendSeconds}
+            onChange={(e) => setResendSeconds(parseInt(e.target.value) || 0)}
+            min={0}
+          />
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            Optional: alert on the first failure, then again each time the outage lasts this much longer, whatever the check interval.
+            Replaces the failure count above when set; 0 to count failures.
+          </p>
+        </div>
+
+        <div className="space-y-2">
+          <Label htmlFor="escalation_policy_id">
+            Escalation Policy
+          </Label>
+          <select
+            id="escalation_policy_id"
+            value={formData.escalation_policy_id ?? ''}
+            onChange={(e) => setFormData({ ...formData, escalation_policy_id: e.target.value ? Number(e.target.value) : null })}
+            className="flex h-8 w-full min-w-0 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none placeholder:text-muted-foreground focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 disabled:pointer-events-none disabled:cursor-not-allowed disabled:bg-input/50 disabled:opacity-50 md:text-sm dark:bg-input/30"
+          >
+            <option value="">None</option>
+            {escalationPolicies.map((p) => (
+              <option key={p.id} value={p.id}>{p.name}</option>
+            ))}
+          </select>
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            Optional: notify further channels the longer an outage lasts
+          </p>
+        </div>
+
+        <div className="space-y-2">
+          <div className="flex items-center gap-2">
+            <Checkbox
+              id="public"
+              checked={formData.public === true}
+              onCheckedChange={(checked) => setFormData({ ...formData, public: checked === true })}
+            />
+            <Label htmlFor="public" className="font-normal">
+              Show on public status pages
+            </Label>
+          </div>
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            Status pages only show public monitors, so internal monitors aren&apos;t exposed by accident.
+          </p>
+        </div>
+
+        <div className="flex items-center gap-2">
+          <Checkbox
+            id="notify_recovery"
+            checked={notifyRecovery}
+            onCheckedChange={(checked) => setNotifyRecovery(checked === true)}
+          />
+          <Label htmlFor="notify_recovery" className="font-normal">
+            Notify when the monitor recovers (includes how long it was down)
+          </Label>
+        </div>
+
+        <div className="space-y-2">
+          <Label htmlFor="result_webhook_url">
+            Result Webhook URL (optional)
+          </Label>
+          <Input
+            type="url"
+            id="result_webhook_url"
+            value={resultWebhookUrl}
+            onChange={(e) => setResultWebhookUrl(e.target.value)}
+            placeholder="https://pipeline.example.com/ingest"
+          />
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            Every check result is POSTed here as JSON, separately from notifications.
+          </p>
+        </div>
+
+        <div className="space-y-2">
+          <Label htmlFor="coalesce_interval">
+            Heartbeat Coalescing (seconds, optional)
+          </Label>
+          <Input
+            type="number"
+            id="coalesce_interval"
+            min={0}
+            max={86400}
+            value={coalesceInterval || ''}
+            onChange={(e) => setCoalesceInterval(parseInt(e.target.value) || 0)}
+            placeholder="Store every check"
+          />
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            Fold runs of the same status into one heartbeat per this many seconds. Status changes are always stored and uptime still counts every check.
+          </p>
+        </div>
+
+        <div className="space-y-2">
+          <Label htmlFor="auto_pause_after">
+            Auto-Pause After Hard Errors (optional)
+          </Label>
+          <Input
+            type="number"
+            id="auto_pause_after"
+            min={0}
+            value={autoPauseAfter || ''}
+            onChange={(e) => setAutoPauseAfter(parseInt(e.target.value) || 0)}
+            placeholder="Never pause"
+          />
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            Pause the monitor after this many consecutive checks fail with NXDOMAIN or an untrusted or expired certificate. It sends one notification and stays paused until resumed.
+          </p>
+        </div>
+
+        <div className="space-y-2">
+          <Label htmlFor="quorum">
+            Down Quorum
+          </Label>
+          <Input
+            type="number"
+            id="quorum"
+            value={quorum}
+            onChange={(e) => setQuorum(parseInt(e.target.value) || 1)}
+            min={1}
+          />
+          <p className="text-sm text-gray-500 dark:text-gray-400">
+            How many sources (this server plus remote agents) must report the monitor down before it is down.
+            A local failure short of the quorum is recorded as pending.
+          </p>
+        </div>
+
         <div className="space-y-2">
           <Label htmlFor="ip_version">
             IP Version
@@ -630,6 +983,126 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
               </div>
             )}
 
+            {/* Preflight Request */}
+            <div className="space-y-2">
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="preflightEnabled"
+                  checked={preflight.enabled}
+                  onCheckedChange={(checked) => setPreflight({ ...preflight, enabled: checked === true })}
+                />
+                <Label htmlFor="preflightEnabled" className="font-normal">
+                  Fetch a token with a preflight request first
+                </Label>
+              </div>
+              {preflight.enabled && (
+                <div className="space-y-2 pl-6">
+                  <div className="flex gap-2">
+                    <select
+                      aria-label="Preflight method"
+                      value={preflight.method}
+                      onChange={(e) => setPreflight({ ...preflight, method: e.target.value })}
+                      className="flex h-8 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base outline-none md:text-sm dark:bg-input/30"
+                    >
+                      <option value="GET">GET</option>
+                      <option value="POST">POST</option>
+                    </select>
+                    <Input
+                      type="url"
+                      value={preflight.url}
+                      onChange={(e) => setPreflight({ ...preflight, url: e.target.value })}
+                      placeholder="https://auth.example.com/login"
+                      className="flex-1"
+                      required
+                    />
+                  </div>
+                  {preflight.method === 'POST' && (
+                    <Textarea
+                      aria-label="Preflight body"
+                      value={preflight.body}
+                      onChange={(e) => setPreflight({ ...preflight, body: e.target.value })}
+                      rows={3}
+                      className="font-mono text-xs"
+                      placeholder='{"username": "probe", "password": "..."}'
+                    />
+                  )}
+                  <Input
+                    aria-label="Token JSONPath"
+                    value={preflight.tokenPath}
+                    onChange={(e) => setPreflight({ ...preflight, tokenPath: e.target.value })}
+                    placeholder="$.access_token"
+                    required
+                  />
+                  <p className="text-sm text-gray-500 dark:text-gray-400">
+                    The value at this JSONPath replaces {'{{preflight_token}}'} in the URL path or query and in header values
+                  </p>
+                </div>
+              )}
+            </div>
+
+            {/* Response Stability */}
+            <div className="space-y-2">
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="stabilityEnabled"
+                  checked={stability.enabled}
+                  onCheckedChange={(checked) => setStability({ ...stability, enabled: checked === true })}
+                />
+                <Label htmlFor="stabilityEnabled" className="font-normal">
+                  Flag a response that keeps changing between checks
+                </Label>
+              </div>
+              {stability.enabled && (
+                <div className="space-y-2 pl-6">
+                  <div className="flex gap-2">
+                    <select
+                      aria-label="Stability source"
+                      value={stability.source}
+                      onChange={(e) => setStability({ ...stability, source: e.target.value })}
+                      className="flex h-8 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base outline-none md:text-sm dark:bg-input/30"
+                    >
+                      <option value="etag">ETag</option>
+                      <option value="json_path">JSONPath</option>
+                    </select>
+                    {stability.source === 'json_path' && (
+                      <Input
+                        aria-label="Stability JSONPath"
+                        value={stability.jsonPath}
+                        onChange={(e) => setStability({ ...stability, jsonPath: e.target.value })}
+                        placeholder="$.build.id"
+                        className="flex-1"
+                        required
+                      />
+                    )}
+                  </div>
+                  <div className="flex items-center gap-2 text-sm">
+                    <span>Down after more than</span>
+                    <Input
+                      type="number"
+                      aria-label="Maximum changes"
+                      min={1}
+                      value={stability.maxChanges}
+                      onChange={(e) => setStability({ ...stability, maxChanges: parseInt(e.target.value) || 1 })}
+                      className="w-20"
+                    />
+                    <span>changes in</span>
+                    <Input
+                      type="number"
+                      aria-label="Window in seconds"
+                      min={1}
+                      value={stability.window}
+                      onChange={(e) => setStability({ ...stability, window: parseInt(e.target.value) || 1 })}
+                      className="w-24"
+                    />
+                    <span>seconds</span>
+                  </div>
+                  <p className="text-sm text-gray-500 dark:text-gray-400">
+                    A single change is fine; repeated changes suggest a cache serving mixed versions
+                  </p>
+                </div>
+              )}
+            </div>
+
             {/* Keyword Search */}
             <div className="space-y-2">
               <Label htmlFor="keyword">
@@ -654,6 +1127,33 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
               </div>
             </div>
 
+            {/* Keyword List */}
+            <div className="space-y-2">
+              <Label htmlFor="keywords">
+                Keywords (optional)
+              </Label>
+              <Textarea
+                id="keywords"
+                value={httpConfig.keywords}
+                onChange={(e) => setHttpConfig({ ...httpConfig, keywords: e.target.value })}
+                placeholder={'"database":"ok"\n"cache":"ok"'}
+                rows={3}
+                className="font-mono text-sm"
+              />
+              <select
+                id="keywordMatch"
+                value={httpConfig.keywordMatch}
+                onChange={(e) => setHttpConfig({ ...httpConfig, keywordMatch: e.target.value })}
+                className="flex h-8 w-full min-w-0 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none placeholder:text-muted-foreground focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 disabled:pointer-events-none disabled:cursor-not-allowed disabled:bg-input/50 disabled:opacity-50 md:text-sm dark:bg-input/30"
+              >
+                <option value="all">Response must contain all keywords</option>
+                <option value="any">Response must contain any keyword</option>
+              </select>
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                One keyword per line, checked in addition to the keyword above.
+              </p>
+            </div>
+
             {/* Advanced Options */}
             <div className="space-y-2">
               <div className="flex items-center gap-2">
@@ -667,6 +1167,110 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
                 </Label>
               </div>
 
+              <div className="flex items-center gap-2">
+                <Checkbox
+                  id="http3"
+                  checked={httpConfig.http3}
+                  onCheckedChange={(checked) => setHttpConfig({ ...httpConfig, http3: checked === true })}
+                />
+                <Label htmlFor="http3" className="font-normal">
+                  Use HTTP/3 (QUIC, https:// only)
+                </Label>
+              </div>
+
+              {!httpConfig.ignoreTLS && (
+                <div className="space-y-2">
+                  <Label htmlFor="caCert">
+                    Trusted CA Certificate (optional)
+                  </Label>
+                  <Textarea
+                    id="caCert"
+                    value={httpConfig.caCert}
+                    onChange={(e) => setHttpConfig({ ...httpConfig, caCert: e.target.value })}
+                    rows={4}
+                    className="font-mono text-xs"
+                    placeholder="-----BEGIN CERTIFICATE-----"
+                  />
+                </div>
+              )}
+
+              <div className="space-y-2">
+                <Label htmlFor="tlsServerName">
+                  TLS Server Name (SNI, optional)
+                </Label>
+                <Input
+                  id="tlsServerName"
+                  value={httpConfig.tlsServerName}
+                  onChange={(e) => setHttpConfig({ ...httpConfig, tlsServerName: e.target.value })}
+                  placeholder="tenant.example.com"
+                />
+                <p className="text-sm text-gray-500 dark:text-gray-400">
+                  Sent in the TLS handshake and checked against the certificate instead of the URL host
+                </p>
+              </div>
+
+              <div className="space-y-2">
+                <Label htmlFor="expectedAlpn">
+                  Expected Protocol (ALPN)
+                </Label>
+                <select
+                  id="expectedAlpn"
+                  value={httpConfig.expectedAlpn}
+                  onChange={(e) => setHttpConfig({ ...httpConfig, expectedAlpn: e.target.value })}
+                  className="flex h-8 w-full min-w-0 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none placeholder:text-muted-foreground focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 disabled:pointer-events-none disabled:cursor-not-allowed disabled:bg-input/50 disabled:opacity-50 md:text-sm dark:bg-input/30"
+                >
+                  <option value="">Any</option>
+                  <option value="h2">h2 (HTTP/2)</option>
+                  <option value="http/1.1">http/1.1</option>
+                  <option value="h3">h3 (with HTTP/3)</option>
+                </select>
+                <p className="text-sm text-gray-500 dark:text-gray-400">
+                  Fail the check when the server negotiates a different protocol (https:// only)
+                </p>
+              </div>
+
+              <div className="space-y-2">
+                <Label htmlFor="authType">
+                  Authentication
+                </Label>
+                <select
+                  id="authType"
+                  value={httpConfig.authType}
+                  onChange={(e) => setHttpConfig({ ...httpConfig, authType: e.target.value })}
+                  className="flex h-8 w-full min-w-0 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none placeholder:text-muted-foreground focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 disabled:pointer-events-none disabled:cursor-not-allowed disabled:bg-input/50 disabled:opacity-50 md:text-sm dark:bg-input/30"
+                >
+                  <option value="">None</option>
+                  <option value="ntlm">NTLM / Negotiate</option>
+                </select>
+              </div>
+
+              {httpConfig.authType === 'ntlm' && (
+                <div className="grid grid-cols-3 gap-2">
+                  <Input
+                    id="authDomain"
+                    value={httpConfig.authDomain}
+                    onChange={(e) => setHttpConfig({ ...httpConfig, authDomain: e.target.value })}
+                    placeholder="Domain"
+                  />
+                  <Input
+                    id="authUsername"
+                    value={httpConfig.authUsername}
+                    onChange={(e) => setHttpConfig({ ...httpConfig, authUsername: e.target.value })}
+                    placeholder="Username"
+                    required
+                  />
+                  <Input
+                    type="password"
+                    id="authPassword"
+                    value={httpConfig.authPassword}
+                    onChange={(e) => setHttpConfig({ ...httpConfig, authPassword: e.target.value })}
+                    placeholder="Password"
+                    autoComplete="new-password"
+                    required
+                  />
+                </div>
+              )}
+
               <div className="space-y-2">
                 <Label htmlFor="maxRedirects">
                   Maximum Redirects
@@ -680,6 +1284,60 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
                   max={20}
                 />
               </div>
+
+              <div className="space-y-2">
+                <Label htmlFor="expectedRedirect">
+                  Expected Redirect (optional)
+                </Label>
+                <Input
+                  type="text"
+                  id="expectedRedirect"
+                  value={httpConfig.expectedRedirect}
+                  onChange={(e) => setHttpConfig({ ...httpConfig, expectedRedirect: e.target.value })}
+                  placeholder="https://www.example.com/"
+                />
+                <select
+                  id="expectedRedirectMatch"
+                  value={httpConfig.expectedRedirectMatch}
+                  onChange={(e) => setHttpConfig({ ...httpConfig, expectedRedirectMatch: e.target.value })}
+                  className="flex h-8 w-full min-w-0 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none placeholder:text-muted-foreground focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 disabled:pointer-events-none disabled:cursor-not-allowed disabled:bg-input/50 disabled:opacity-50 md:text-sm dark:bg-input/30"
+                >
+                  <option value="equals">Location must equal this URL</option>
+                  <option value="contains">Location must contain this text</option>
+                </select>
+                <p className="text-sm text-gray-500 dark:text-gray-400">
+                  Redirects are not followed when set. Add the redirect status (e.g. 301) to the accepted status codes.
+                </p>
+              </div>
+            </div>
+
+            <div className="grid grid-cols-2 gap-4">
+              <div className="space-y-2">
+                <Label htmlFor="upHeader">
+                  Health Header (optional)
+                </Label>
+                <Input
+                  type="text"
+                  id="upHeader"
+                  value={httpConfig.upHeader}
+                  onChange={(e) => setHttpConfig({ ...httpConfig, upHeader: e.target.value })}
+                  placeholder="X-Health"
+                />
+              </div>
+
+              <div className="space-y-2">
+                <Label htmlFor="upHeaderValue">
+                  Healthy Value
+                </Label>
+                <Input
+                  type="text"
+                  id="upHeaderValue"
+                  value={httpConfig.upHeaderValue}
+                  onChange={(e) => setHttpConfig({ ...httpConfig, upHeaderValue: e.target.value })}
+                  placeholder="ok"
+                  required={httpConfig.upHeader.trim() !== ''}
+                />
+              </div>
             </div>
 
             <div className="space-y-2">
@@ -734,6 +1392,94 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
         </>
       )}
 
+      {/* TLS certificate configuration */}
+      {formData.type === 'cert' && (
+        <>
+          <Separator />
+          <div className="space-y-4">
+            <h3 className="text-lg font-medium text-gray-900 dark:text-white">Certificate Configuration</h3>
+
+            <div className="space-y-2">
+              <Label htmlFor="daysWarning">
+                Warning Threshold (days)
+              </Label>
+              <Input
+                type="number"
+                id="daysWarning"
+                value={certConfig.days_warning}
+                onChange={(e) => setCertConfig({ ...certConfig, days_warning: parseInt(e.target.value) || 0 })}
+                min={0}
+                max={365}
+              />
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                Warn once when the certificate expires within this many days (0 to turn off)
+              </p>
+            </div>
+
+            <div className="flex items-center gap-2">
+              <Checkbox
+                id="verifyChain"
+                checked={certConfig.verify_chain}
+                onCheckedChange={(checked) => setCertConfig({ ...certConfig, verify_chain: checked === true })}
+              />
+              <Label htmlFor="verifyChain" className="font-normal">
+                Verify the certificate chain and host name
+              </Label>
+            </div>
+
+            {certConfig.verify_chain && (
+              <div className="space-y-2">
+                <Label htmlFor="certCaCert">
+                  Trusted CA Certificate (optional)
+                </Label>
+                <Textarea
+                  id="certCaCert"
+                  value={certConfig.ca_cert}
+                  onChange={(e) => setCertConfig({ ...certConfig, ca_cert: e.target.value })}
+                  rows={4}
+                  className="font-mono text-xs"
+                  placeholder="-----BEGIN CERTIFICATE-----"
+                />
+              </div>
+            )}
+
+            <div className="space-y-2">
+              <Label htmlFor="certTlsServerName">
+                TLS Server Name (SNI, optional)
+              </Label>
+              <Input
+                id="certTlsServerName"
+                value={certConfig.tls_server_name}
+                onChange={(e) => setCertConfig({ ...certConfig, tls_server_name: e.target.value })}
+                placeholder="tenant.example.com"
+              />
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                Sent in the TLS handshake and checked against the certificate instead of the host
+              </p>
+            </div>
+
+            <div className="space-y-2">
+              <Label htmlFor="certMinTlsVersion">
+                Minimum TLS Version
+              </Label>
+              <select
+                id="certMinTlsVersion"
+                value={certConfig.min_tls_version}
+                onChange={(e) => setCertConfig({ ...certConfig, min_tls_version: e.target.value })}
+                className="flex h-8 w-full min-w-0 rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none placeholder:text-muted-foreground focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 disabled:pointer-events-none disabled:cursor-not-allowed disabled:bg-input/50 disabled:opacity-50 md:text-sm dark:bg-input/30"
+              >
+                <option value="">Any</option>
+                {['1.0', '1.1', '1.2', '1.3'].map((version) => (
+                  <option key={version} value={version}>
+                    TLS {version}
+                  </option>
+                ))}
+              </select>
+            </div>
+          </div>
+        </>
+      )}
+
       {/* Ping-specific configuration */}
       {formData.type === 'ping' && (
         <>
@@ -833,6 +1579,24 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
               </p>
             </div>
 
+            <div className="space-y-2">
+              <Label htmlFor="queryTimeout">
+                Query Timeout (seconds, optional)
+              </Label>
+              <Input
+                type="number"
+                id="queryTimeout"
+                value={dnsConfig.query_timeout || ''}
+                onChange={(e) => setDnsConfig({ ...dnsConfig, query_timeout: parseFloat(e.target.value) || 0 })}
+                min={0.1}
+                max={formData.timeout}
+                step={0.1}
+              />
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                How long the lookup may take; connecting to the server still gets the full timeout
+              </p>
+            </div>
+
             <div className="space-y-2">
               <Label htmlFor="expectedResult">
                 Expected Result (optional)
@@ -843,11 +1607,23 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
                 value={dnsConfig.expected_result}
                 onChange={(e) => setDnsConfig({ ...dnsConfig, expected_result: e.target.value })}
                 placeholder="Expected value in DNS response"
+                disabled={dnsConfig.expect_nxdomain}
               />
               <p className="text-sm text-gray-500 dark:text-gray-400">
                 Alert if this value is not found in the response
               </p>
             </div>
+
+            <div className="flex items-center gap-2">
+              <Checkbox
+                id="expectNxdomain"
+                checked={dnsConfig.expect_nxdomain}
+                onCheckedChange={(checked) => setDnsConfig({ ...dnsConfig, expect_nxdomain: checked === true })}
+              />
+              <Label htmlFor="expectNxdomain" className="font-normal">
+                Expect NXDOMAIN (up while the name does not resolve, down when a record is found)
+              </Label>
+            </div>
           </div>
         </>
       )}
@@ -878,6 +1654,113 @@ export default function MonitorForm({ initialData, monitorId, notificationsConfi
         </>
       )}
 
+      {/* Flow-specific configuration */}
+      {formData.type === 'flow' && (
+        <>
+          <Separator />
+          <div className="space-y-4">
+            <h3 className="text-lg font-medium text-gray-900 dark:text-white">Flow Configuration</h3>
+
+            <div className="space-y-2">
+              <Label htmlFor="flowSteps">
+                Steps
+              </Label>
+              <Textarea
+                id="flowSteps"
+                value={flowSteps}
+                onChange={(e) => setFlowSteps(e.target.value)}
+                placeholder={'[\n  {"name": "home", "url": "https://example.com/"},\n  {"name": "login", "method": "POST", "url": "https://example.com/login",\n   "body": "user=probe", "expected_status": 302},\n  {"name": "dashboard", "url": "https://example.com/dashboard", "keyword": "Welcome"}\n]'}
+                rows={8}
+                className="font-mono text-sm"
+                required
+              />
+              {flowStepsError && (
+                <p className="text-sm text-red-600 dark:text-red-400">{flowStepsError}</p>
+              )}
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                Up to 10 requests run in order, sharing cookies. Each step takes url, name, method, headers, body,
+                expected_status (default 200) and keyword. Redirects are not followed, and the timeout covers the whole flow.
+              </p>
+            </div>
+          </div>
+        </>
+      )}
+
+      {/* Push-specific configuration */}
+      {formData.type === 'push' && (
+        <>
+          <Separator />
+          <div className="space-y-4">
+            <h3 className="text-lg font-medium text-gray-900 dark:text-white">Push Configuration</h3>
+
+            <div className="space-y-2">
+              <Label htmlFor="pushUrl">
+                Push URL
+              </Label>
+              {pushUrlToken ? (
+                <Input
+                  id="pushUrl"
+                  readOnly
+                  value={`${typeof window !== 'undefined' ? window.location.origin : ''}/api/push/${pushUrlToken}`}
+                  className="font-mono text-sm"
+                  onFocus={(e) => e.target.select()}
+                />
+              ) : (
+                <p className="text-sm text-gray-500 dark:text-gray-400">
+                  The push URL is generated when the monitor is saved.
+                </p>
+              )}
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                Call it with GET or POST after each run, optionally with ?status=down, ?ping= (ms) and ?msg=.
+                The monitor is down when no push arrives within the check interval plus the grace period.
+              </p>
+            </div>
+
+            <div className="space-y-2">
+              <Label htmlFor="pushGracePeriod">
+                Grace Period (seconds)
+              </Label>
+              <Input
+                type="number"
+                id="pushGracePeriod"
+                value={pushGracePeriod}
+                onChange={(e) => setPushGracePeriod(parseInt(e.target.value) || 0)}
+                min={0}
+                max={86400}
+              />
+            </div>
+          </div>
+        </>
+      )}
+
+      {/* Script-specific configuration */}
+      {formData.type === 'script' && (
+        <>
+          <Separator />
+          <div className="space-y-4">
+            <h3 className="text-lg font-medium text-gray-900 dark:text-white">Script Configuration</h3>
+
+            <div className="space-y-2">
+              <Label htmlFor="scriptArgs">
+                Arguments (optional)
+              </Label>
+              <Textarea
+                id="scriptArgs"
+                value={scriptArgs}
+                onChange={(e) => setScriptArgs(e.target.value)}
+                placeholder={'--target\ndb.internal'}
+                rows={3}
+                className="font-mono text-sm"
+              />
+              <p className="text-sm text-gray-500 dark:text-gray-400">
+                One argument per line, passed as-is without a shell. Exit code 0 is up, anything else is down;
+                the output is shown in the check message. Requires SCRIPT_MONITOR_ENABLED on the server.
+              </p>
+            </div>
+          </div>
+        </>
+      )}
+
       {/* Page Change-specific configuration */}
       {formData.type === 'page_change' && (
         <>
@@ -1116,6 +1999,8 @@ function getProviderLabel(type: string): string {
     pushover: 'Pushover',
     gotify: 'Gotify',
     ntfy: 'Ntfy',
+    matrix: 'Matrix',
+    opsgenie: 'Opsgenie',
   };
   return labels[type] || type.toUpperCase();
 }
@@ -11,6 +11,16 @@ import { Label } from '@/components/ui/label';
 import { Switch } from '@/components/ui/switch';
 import { Button } from '@/components/ui/button';
 import { Alert, AlertDescription } from '@/components/ui/alert';
+import { toast } from 'sonner';
+
+// Locales of the server's built-in notification message catalog
+const notificationLocales = [
+  { value: 'en', label: 'English' },
+  { value: 'it', label: 'Italiano' },
+  { value: 'de', label: 'Deutsch' },
+  { value: 'fr', label: 'Français' },
+  { value: 'es', label: 'Español' },
+];
 
 interface NotificationFormProps {
   notification: Notification | null;
@@ -28,6 +38,7 @@ export default function NotificationForm({
   const [name, setName] = useState('');
   const [type, setType] = useState('smtp');
   const [isDefault, setIsDefault] = useState(false);
+  const [receiveAllEvents, setReceiveAllEvents] = useState(false);
   const [active, setActive] = useState(true);
   const [config, setConfig] = useState<Record<string, any>>({});
   const [loading, setLoading] = useState(false);
@@ -38,6 +49,7 @@ export default function NotificationForm({
       setName(notification.name);
       setType(notification.type);
       setIsDefault(notification.is_default);
+      setReceiveAllEvents(notification.receive_all_events);
       setActive(notification.active);
       try {
         const parsedConfig = JSON.parse(notification.config);
@@ -60,13 +72,16 @@ export default function NotificationForm({
         type,
         config,
         is_default: isDefault,
+        receive_all_events: receiveAllEvents,
         active,
       };
 
-      if (notification) {
-        await apiClient.updateNotification(notification.id, data);
-      } else {
-        await apiClient.createNotification(data);
+      const saved = notification
+        ? await apiClient.updateNotification(notification.id, data)
+        : await apiClient.createNotification(data);
+      // Saved, but e.g. a referenced ${NOTIFY_...} variable isn't set yet
+      for (const warning of saved.warnings ?? []) {
+        toast.warning(warning);
       }
 
       onSuccess();
@@ -131,6 +146,22 @@ export default function NotificationForm({
 
           {renderProviderConfig(type, config, updateConfig)}
 
+          <div className="space-y-2">
+            <Label htmlFor="notification-locale">Language (alert titles and labels)</Label>
+            <select
+              id="notification-locale"
+              value={config.locale || 'en'}
+              onChange={(e) => updateConfig('locale', e.target.value)}
+              className="flex h-8 w-full rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 md:text-sm dark:bg-input/30"
+            >
+              {notificationLocales.map((locale) => (
+                <option key={locale.value} value={locale.value}>
+                  {locale.label}
+                </option>
+              ))}
+            </select>
+          </div>
+
           <div className="flex items-center gap-6">
             <div className="flex items-center gap-2">
               <Switch
@@ -140,6 +171,14 @@ export default function NotificationForm({
               <Label className="cursor-pointer">Default notification</Label>
             </div>
 
+            <div className="flex items-center gap-2">
+              <Switch
+                checked={receiveAllEvents}
+                onCheckedChange={setReceiveAllEvents}
+              />
+              <Label className="cursor-pointer">Receive all monitor events</Label>
+            </div>
+
             <div className="flex items-center gap-2">
               <Switch
                 checked={active}
@@ -331,6 +370,26 @@ function renderProviderConfig(
               placeholder="#alerts"
             />
           </div>
+          <div className="flex items-center gap-2">
+            <Switch
+              id="slack-blocks"
+              checked={config.use_blocks === true}
+              onCheckedChange={(checked) => updateConfig('use_blocks', checked)}
+            />
+            <Label htmlFor="slack-blocks" className="cursor-pointer">Use Block Kit formatting</Label>
+          </div>
+          {config.use_blocks === true && (
+            <div className="space-y-2">
+              <Label htmlFor="slack-status-page">Status Page URL (optional, adds a button)</Label>
+              <Input
+                id="slack-status-page"
+                type="url"
+                value={config.status_page_url || ''}
+                onChange={(e) => updateConfig('status_page_url', e.target.value)}
+                placeholder="https://status.example.com/status/my-page"
+              />
+            </div>
+          )}
         </>
       );
 
@@ -392,6 +451,51 @@ function renderProviderConfig(
         </div>
       );
 
+    case 'opsgenie':
+      return (
+        <>
+          <div className="space-y-2">
+            <Label htmlFor="opsgenie-key">API Key</Label>
+            <Input
+              id="opsgenie-key"
+              type="password"
+              value={config.api_key || ''}
+              onChange={(e) => updateConfig('api_key', e.target.value)}
+              placeholder="API integration key from Opsgenie"
+              required
+            />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="opsgenie-region">Region</Label>
+            <select
+              id="opsgenie-region"
+              value={config.region || 'us'}
+              onChange={(e) => updateConfig('region', e.target.value)}
+              className="flex h-8 w-full rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 md:text-sm dark:bg-input/30"
+            >
+              <option value="us">US</option>
+              <option value="eu">EU</option>
+            </select>
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="opsgenie-priority">Priority</Label>
+            <select
+              id="opsgenie-priority"
+              value={config.priority || ''}
+              onChange={(e) => updateConfig('priority', e.target.value)}
+              className="flex h-8 w-full rounded-lg border border-input bg-transparent px-2.5 py-1 text-base transition-colors outline-none focus-visible:border-ring focus-visible:ring-3 focus-visible:ring-ring/50 md:text-sm dark:bg-input/30"
+            >
+              <option value="">Default (P1 when down, P3 otherwise)</option>
+              <option value="P1">P1</option>
+              <option value="P2">P2</option>
+              <option value="P3">P3</option>
+              <option value="P4">P4</option>
+              <option value="P5">P5</option>
+            </select>
+          </div>
+        </>
+      );
+
     case 'pushover':
       return (
         <>
@@ -448,6 +552,45 @@ function renderProviderConfig(
         </>
       );
 
+    case 'matrix':
+      return (
+        <>
+          <div className="space-y-2">
+            <Label htmlFor="matrix-homeserver">Homeserver URL</Label>
+            <Input
+              id="matrix-homeserver"
+              type="url"
+              value={config.homeserver_url || ''}
+              onChange={(e) => updateConfig('homeserver_url', e.target.value)}
+              placeholder="https://matrix.example.org"
+              required
+            />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="matrix-token">Access Token</Label>
+            <Input
+              id="matrix-token"
+              type="password"
+              value={config.access_token || ''}
+              onChange={(e) => updateConfig('access_token', e.target.value)}
+              placeholder="Access token of a user in the room"
+              required
+            />
+          </div>
+          <div className="space-y-2">
+            <Label htmlFor="matrix-room">Room ID</Label>
+            <Input
+              id="matrix-room"
+              type="text"
+              value={config.room_id || ''}
+              onChange={(e) => updateConfig('room_id', e.target.value)}
+              placeholder="!AbCdEf123:example.org"
+              required
+            />
+          </div>
+        </>
+      );
+
     case 'ntfy':
       return (
         <>
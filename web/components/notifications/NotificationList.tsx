@@ -35,6 +35,11 @@ export default function NotificationList({
                       Default
                     </Badge>
                   )}
+                  {notification.receive_all_events && (
+                    <Badge className="bg-blue-100 text-blue-800 dark:bg-blue-900/30 dark:text-blue-300">
+                      All events
+                    </Badge>
+                  )}
                   {!notification.active && (
                     <Badge variant="outline">
                       Inactive
@@ -89,6 +94,8 @@ function getProviderLabel(type: string): string {
     pushover: 'Pushover',
     gotify: 'Gotify',
     ntfy: 'Ntfy',
+    matrix: 'Matrix',
+    opsgenie: 'Opsgenie',
   };
   return labels[type] || type;
 }
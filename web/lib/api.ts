@@ -21,6 +21,7 @@ export interface User {
   email?: string;
   provider?: string;
   active: boolean;
+  is_admin: boolean; // may create script monitors
   created_at: string;
 }
 
@@ -95,8 +96,9 @@ class ApiClient {
     });
 
     if (!response.ok) {
-      // Clear token on authentication errors
-      if (response.status === 401) {
+      // Clear token on authentication errors; a 401 from a public status
+      // page is about its password, not the session
+      if (response.status === 401 && !endpoint.startsWith('/api/status/')) {
         this.setToken(null);
       }
 
@@ -194,6 +196,13 @@ class ApiClient {
     });
   }
 
+  async compareMonitorsUptime(monitorIds: number[], period: UptimePeriod = '24h'): Promise<UptimeComparison> {
+    return this.request<UptimeComparison>('/api/monitors/uptime/compare', {
+      method: 'POST',
+      body: JSON.stringify({ monitor_ids: monitorIds, period }),
+    });
+  }
+
   async getMonitorNotifications(monitorId: number): Promise<Notification[]> {
     const result = await this.request<Notification[] | null>(
       `/api/monitors/${monitorId}/notifications`
@@ -249,6 +258,15 @@ class ApiClient {
     return result || [];
   }
 
+  async getMonitorEvents(
+    monitorId: number,
+    period?: '1h' | '3h' | '6h' | '24h' | '7d' | '30d' | '90d'
+  ): Promise<MonitorEvent[]> {
+    const query = period ? `?period=${period}` : '';
+    const result = await this.request<MonitorEvent[] | null>(`/api/monitors/${monitorId}/events${query}`);
+    return result || [];
+  }
+
   async getUserSettings(): Promise<UserSettings> {
     return this.request<UserSettings>('/api/settings');
   }
@@ -257,6 +275,8 @@ class ApiClient {
     heartbeat_retention_days: number;
     hourly_stat_retention_days: number;
     daily_stat_retention_days: number;
+    auto_attach_default_notifications?: boolean;
+    timezone?: string;
   }): Promise<UserSettings> {
     return this.request<UserSettings>('/api/settings', {
       method: 'PUT',
@@ -281,15 +301,15 @@ class ApiClient {
     return this.request<Notification>(`/api/notifications/${id}`);
   }
 
-  async createNotification(data: CreateNotificationRequest): Promise<Notification> {
-    return this.request<Notification>('/api/notifications', {
+  async createNotification(data: CreateNotificationRequest): Promise<Notification & { verification?: NotificationVerification; warnings?: string[] }> {
+    return this.request<Notification & { verification?: NotificationVerification; warnings?: string[] }>('/api/notifications', {
       method: 'POST',
       body: JSON.stringify(data),
     });
   }
 
-  async updateNotification(id: number, data: UpdateNotificationRequest): Promise<Notification> {
-    return this.request<Notification>(`/api/notifications/${id}`, {
+  async updateNotification(id: number, data: UpdateNotificationRequest): Promise<Notification & { verification?: NotificationVerification; warnings?: string[] }> {
+    return this.request<Notification & { verification?: NotificationVerification; warnings?: string[] }>(`/api/notifications/${id}`, {
       method: 'PUT',
       body: JSON.stringify(data),
     });
@@ -307,6 +327,12 @@ class ApiClient {
     });
   }
 
+  async testAllNotifications(): Promise<NotificationTestReport> {
+    return this.request<NotificationTestReport>('/api/notifications/test-all', {
+      method: 'POST',
+    });
+  }
+
   async getNotificationProviders(): Promise<NotificationProvider[]> {
     const result = await this.request<NotificationProvider[] | null>('/api/notifications/providers');
     return result || [];
@@ -342,18 +368,41 @@ class ApiClient {
     });
   }
 
-  async getPublicStatusPage(slug: string, password?: string): Promise<PublicStatusPage> {
-    const headers: Record<string, string> = {};
-    if (password) {
-      headers['X-Status-Page-Password'] = password;
+  // Checks a status page password, returning an access token for the
+  // public status page requests when it matches; rejects with status 401
+  // otherwise
+  async authenticateStatusPage(slug: string, password: string): Promise<StatusPageAccess> {
+    return this.request<StatusPageAccess>(`/api/status/${slug}/auth`, {
+      method: 'POST',
+      body: JSON.stringify({ password }),
+    });
+  }
+
+  private statusPageHeaders(token?: string): Record<string, string> {
+    return token ? { 'X-Status-Page-Token': token } : {};
+  }
+
+  async getPublicStatusPage(slug: string, token?: string): Promise<PublicStatusPage> {
+    return this.request<PublicStatusPage>(`/api/status/${slug}`, {
+      headers: this.statusPageHeaders(token),
+    });
+  }
+
+  // Returns the slug of the published status page whose custom domain is
+  // the current host, or null when the host has none
+  async resolveStatusPageDomain(): Promise<string | null> {
+    try {
+      const response = await fetch(`${this.baseUrl}/api/status-domain`);
+      return response.headers.get('X-Status-Page-Slug');
+    } catch {
+      return null;
     }
-    return this.request<PublicStatusPage>(`/api/status/${slug}`, { headers });
   }
 
   async getPublicStatusPageHeartbeats(
     slug: string,
     monitorId: number,
-    options?: { limit?: number; period?: '1h' }
+    options?: { limit?: number; period?: '1h'; token?: string }
   ): Promise<Heartbeat[]> {
     const params = new URLSearchParams();
     if (options?.limit) {
@@ -364,12 +413,35 @@ class ApiClient {
     }
     const queryString = params.toString();
     const url = `/api/status/${slug}/monitors/${monitorId}/heartbeats${queryString ? `?${queryString}` : ''}`;
-    const result = await this.request<Heartbeat[] | null>(url);
+    const result = await this.request<Heartbeat[] | null>(url, {
+      headers: this.statusPageHeaders(options?.token),
+    });
     return result || [];
   }
 
-  async getIncidents(statusPageId: number): Promise<Incident[]> {
-    const result = await this.request<Incident[] | null>(`/api/status-pages/${statusPageId}/incidents`);
+  // One page of a public status page's incident history, newest first
+  async getPublicIncidentHistory(slug: string, filter: IncidentFilter = {}, token?: string): Promise<PublicIncident[]> {
+    const params = new URLSearchParams();
+    Object.entries(filter).forEach(([key, value]) => {
+      if (value !== undefined) params.set(key, String(value));
+    });
+    const queryString = params.toString();
+    const result = await this.request<PublicIncident[] | null>(
+      `/api/status/${slug}/incidents${queryString ? `?${queryString}` : ''}`,
+      { headers: this.statusPageHeaders(token) }
+    );
+    return result || [];
+  }
+
+  async getIncidents(statusPageId: number, filter: IncidentFilter = {}): Promise<Incident[]> {
+    const params = new URLSearchParams();
+    Object.entries(filter).forEach(([key, value]) => {
+      if (value !== undefined) params.set(key, String(value));
+    });
+    const queryString = params.toString();
+    const result = await this.request<Incident[] | null>(
+      `/api/status-pages/${statusPageId}/incidents${queryString ? `?${queryString}` : ''}`
+    );
     return result || [];
   }
 
@@ -416,6 +488,36 @@ class ApiClient {
     });
   }
 
+  // Escalation policy endpoints
+  async getEscalationPolicies(): Promise<EscalationPolicy[]> {
+    const result = await this.request<EscalationPolicy[] | null>('/api/escalation-policies');
+    return result || [];
+  }
+
+  async getEscalationPolicy(id: number): Promise<EscalationPolicy> {
+    return this.request<EscalationPolicy>(`/api/escalation-policies/${id}`);
+  }
+
+  async createEscalationPolicy(data: EscalationPolicyRequest): Promise<EscalationPolicy> {
+    return this.request<EscalationPolicy>('/api/escalation-policies', {
+      method: 'POST',
+      body: JSON.stringify(data),
+    });
+  }
+
+  async updateEscalationPolicy(id: number, data: EscalationPolicyRequest): Promise<EscalationPolicy> {
+    return this.request<EscalationPolicy>(`/api/escalation-policies/${id}`, {
+      method: 'PUT',
+      body: JSON.stringify(data),
+    });
+  }
+
+  async deleteEscalationPolicy(id: number): Promise<void> {
+    return this.request<void>(`/api/escalation-policies/${id}`, {
+      method: 'DELETE',
+    });
+  }
+
   // Page Change Snapshots
   async getMonitorSnapshots(monitorId: number, limit?: number): Promise<PageChangeSnapshot[]> {
     const params = limit ? `?limit=${limit}` : '';
@@ -437,12 +539,20 @@ export interface Monitor {
   interval: number;
   timeout: number;
   resend_interval: number;
+  retries: number; // re-checks of a failed check before it counts as down
+  retry_interval: number; // seconds between retries, 0 = 5
   ip_version: string;
+  priority: number;
+  retention_days: number; // 0 uses the heartbeat retention from settings
   active: boolean;
+  public: boolean; // shown on public status pages
   notifications_configured: boolean; // true if using explicit config, false if using defaults
+  escalation_policy_id: number | null;
   config: Record<string, any>;
   created_at: string;
   updated_at: string;
+  unsupported_type?: boolean; // type isn't available on this server, so it is never checked
+  push_token?: string; // push monitors' URL token, only on the single monitor
 }
 
 export interface CreateMonitorRequest {
@@ -452,7 +562,13 @@ export interface CreateMonitorRequest {
   interval?: number;
   timeout?: number;
   resend_interval?: number;
+  retries?: number;
+  retry_interval?: number;
   ip_version?: string;
+  priority?: number;
+  retention_days?: number;
+  public?: boolean;
+  escalation_policy_id?: number | null;
   config?: Record<string, any>;
 }
 
@@ -471,6 +587,25 @@ export interface Certificate {
   // key_pem is intentionally absent — never returned by the API
 }
 
+export interface EscalationStep {
+  delay_minutes: number; // outage length before the step notifies
+  notification_ids: number[];
+}
+
+export interface EscalationPolicy {
+  id: number;
+  user_id: number;
+  name: string;
+  steps: EscalationStep[];
+  created_at: string;
+  updated_at: string;
+}
+
+export interface EscalationPolicyRequest {
+  name: string;
+  steps: EscalationStep[];
+}
+
 export interface CreateCertificateRequest {
   name: string;
   cert_pem: string;
@@ -514,6 +649,37 @@ export interface Heartbeat {
   important: boolean;
   message: string;
   time: string;
+  remote_addr?: string | null; // IP that served an HTTP/TCP check
+  checks: number; // checks this heartbeat stands for, more than 1 when coalesced
+}
+
+export type UptimePeriod = '24h' | '7d' | '30d' | '90d';
+
+export interface UptimeStats {
+  monitor_id: number;
+  uptime_percentage: number;
+  total_checks: number;
+  up_checks: number;
+  down_checks: number;
+  average_ping: number;
+  start_time: string;
+  end_time: string;
+  business_hours?: boolean; // only checks within the monitor's business hours counted
+}
+
+export interface UptimeComparison {
+  period: UptimePeriod;
+  monitors: { monitor_id: number; name: string; stats: UptimeStats }[]; // in requested order
+}
+
+export interface MonitorEvent {
+  heartbeat_id: number;
+  time: string;
+  status: number;
+  previous_status: number | null;
+  ping: number;
+  message: string;
+  previous_duration: number | null; // seconds
 }
 
 export interface UserSettings {
@@ -522,6 +688,8 @@ export interface UserSettings {
   heartbeat_retention_days: number;
   hourly_stat_retention_days: number;
   daily_stat_retention_days: number;
+  auto_attach_default_notifications: boolean;
+  timezone: string; // IANA name, e.g. "Europe/Rome"
   created_at: string;
   updated_at: string;
 }
@@ -534,6 +702,7 @@ export interface Notification {
   type: string;
   config: string; // JSON string from backend
   is_default: boolean;
+  receive_all_events: boolean;
   active: boolean;
   created_at: string;
   updated_at: string;
@@ -544,11 +713,33 @@ export interface CreateNotificationRequest {
   type: string;
   config: Record<string, any>;
   is_default?: boolean;
+  receive_all_events?: boolean; // send every monitor's events here too
   active?: boolean;
+  verify?: boolean; // probe the endpoint's reachability on save
+}
+
+export interface NotificationVerification {
+  supported: boolean;
+  reachable: boolean;
+  error?: string;
 }
 
 export interface UpdateNotificationRequest extends CreateNotificationRequest {}
 
+export interface NotificationTestResult {
+  id: number;
+  name: string;
+  type: string;
+  success: boolean;
+  error?: string;
+}
+
+export interface NotificationTestReport {
+  results: NotificationTestResult[];
+  succeeded: number;
+  failed: number;
+}
+
 export interface NotificationProvider {
   name: string;
   label: string;
@@ -565,6 +756,11 @@ export interface StatusPage {
   show_powered_by: boolean;
   theme: string;
   custom_css: string;
+  confirmation_checks: number;
+  custom_domain: string | null;
+  show_uptime_percentage: boolean;
+  banner: string; // announcement shown above the monitors; empty for none
+  banner_style: 'info' | 'warning' | 'danger' | 'success';
   created_at: string;
   updated_at: string;
 }
@@ -579,6 +775,11 @@ export interface StatusPageWithMonitors {
   show_powered_by: boolean;
   theme: string;
   custom_css: string;
+  confirmation_checks: number;
+  custom_domain: string | null;
+  show_uptime_percentage: boolean;
+  banner: string; // announcement shown above the monitors; empty for none
+  banner_style: 'info' | 'warning' | 'danger' | 'success';
   created_at: string;
   updated_at: string;
   monitors: Monitor[];
@@ -594,10 +795,23 @@ export interface CreateStatusPageRequest {
   custom_css?: string;
   password?: string;
   monitor_ids?: number[];
+  confirmation_checks?: number; // consecutive down checks before showing a monitor as down
+  custom_domain?: string; // host name serving the page, e.g. status.example.com
+  show_uptime_percentage?: boolean; // default true
+  banner?: string; // at most 500 characters, shown as plain text
+  banner_style?: string; // info (default), warning, danger or success
 }
 
 export interface UpdateStatusPageRequest extends CreateStatusPageRequest {}
 
+export interface IncidentFilter {
+  limit?: number; // 1-200, default 50
+  offset?: number;
+  status?: 'open' | 'resolved';
+  from?: string; // RFC 3339, created at or after
+  to?: string; // RFC 3339, created before
+}
+
 export interface Incident {
   id: number;
   status_page_id: number;
@@ -607,6 +821,18 @@ export interface Incident {
   pin: boolean;
   created_at: string;
   updated_at: string;
+  auto_resolve_after?: number; // seconds
+  resolved_at: string | null;
+}
+
+export interface PublicIncident {
+  id: number;
+  title: string;
+  content: string;
+  style: string;
+  created_at: string;
+  resolved_at: string | null;
+  resolution_seconds: number | null; // null while open
 }
 
 export interface CreateIncidentRequest {
@@ -614,10 +840,14 @@ export interface CreateIncidentRequest {
   content: string;
   style?: string;
   pin?: boolean;
+  auto_resolve_after?: string; // duration, e.g. "30m" or "4h"
 }
 
 export interface MonitorWithStatus extends Monitor {
   last_heartbeat?: Heartbeat;
+  is_stale?: boolean; // last heartbeat older than two check intervals
+  seconds_since_last_check?: number | null; // null without a heartbeat
+  flapping?: boolean; // changing between up and down more than flap_threshold allows
   history?: StatusHistoryBucket[];
 }
 
@@ -626,9 +856,27 @@ export interface StatusHistoryBucket {
   status: number;
 }
 
+export interface StatusPageAccess {
+  token?: string; // absent when the page has no password
+  expires_at?: string;
+}
+
+// A monitor as shown on a public status page; its config stays private
+export interface PublicMonitorStatus {
+  id: number;
+  name: string;
+  type: string;
+  interval: number;
+  last_heartbeat?: Heartbeat;
+  history?: StatusHistoryBucket[];
+  uptime_percentage?: number; // last 24 hours; absent when the page hides uptime
+  maintenance?: { title: string; ends_at: string }; // planned maintenance under way
+}
+
 export interface PublicStatusPage {
   page: StatusPage;
-  monitors: MonitorWithStatus[];
+  status: number; // overall: 0 down, 1 up, 3 maintenance; down only once confirmed
+  monitors: PublicMonitorStatus[];
   incidents: Incident[];
 }
 